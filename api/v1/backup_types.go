@@ -37,6 +37,10 @@ const (
 	// BackupPhasePending means that the backup is still waiting to be started
 	BackupPhasePending = "pending"
 
+	// BackupPhaseQueued means that the backup is waiting to be started
+	// because the maximum number of concurrent backups has been reached
+	BackupPhaseQueued = "queued"
+
 	// BackupPhaseStarted means that the backup is now running
 	BackupPhaseStarted = "started"
 
@@ -115,6 +119,15 @@ type BackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// Whether to request an immediate checkpoint when starting a backup with
+	// the `barmanObjectStore` method. An immediate checkpoint reduces the WAL
+	// to be replayed when restoring the backup, at the cost of an I/O spike
+	// on the instance being backed up.
+	// Overrides the default setting specified in the cluster field
+	// '.spec.backup.barmanObjectStore.data.immediateCheckpoint'
+	// +optional
+	ImmediateCheckpoint *bool `json:"immediateCheckpoint,omitempty"`
 }
 
 // BackupPluginConfiguration contains the backup configuration used by
@@ -165,6 +178,11 @@ type BackupStatus struct {
 	// +optional
 	EndpointURL string `json:"endpointURL,omitempty"`
 
+	// ForcePathStyle is true when the buckets are addressed with
+	// path-style URLs
+	// +optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+
 	// The path where to store the backup (i.e. s3://bucket/path/to/folder)
 	// this path, with different destination folders, will be used for WALs
 	// and for data. This may not be populated in case of errors.
@@ -216,6 +234,16 @@ type BackupStatus struct {
 	// +optional
 	EndLSN string `json:"endLSN,omitempty"`
 
+	// The major version of the PostgreSQL server where the backup
+	// has been taken, as reported in the PG_VERSION file
+	// +optional
+	MajorVersion int `json:"majorVersion,omitempty"`
+
+	// The size of the backup, in bytes, as reported by Barman Cloud or,
+	// for the volume snapshots, as the sum of their restore sizes
+	// +optional
+	Size int64 `json:"size,omitempty"`
+
 	// The detected error
 	// +optional
 	Error string `json:"error,omitempty"`
@@ -250,6 +278,58 @@ type BackupStatus struct {
 
 	// Whether the backup was online/hot (`true`) or offline/cold (`false`)
 	Online *bool `json:"online,omitempty"`
+
+	// The outcome of the last verification of this backup, when
+	// the backup verification is enabled in the cluster
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+
+	// The export of this backup to the archive tier, when the
+	// latest backup there was outdated
+	// +optional
+	ArchiveTierExport *BackupArchiveTierExportStatus `json:"archiveTierExport,omitempty"`
+}
+
+// BackupArchiveTierExportPhase is the phase of the export
+// of a backup to the archive tier
+type BackupArchiveTierExportPhase string
+
+const (
+	// BackupArchiveTierExportPhaseRunning means that the backup
+	// is being copied to the archive tier
+	BackupArchiveTierExportPhaseRunning BackupArchiveTierExportPhase = "running"
+
+	// BackupArchiveTierExportPhaseCompleted means that the backup, together
+	// with the WAL files needed to restore it, is in the archive tier
+	BackupArchiveTierExportPhaseCompleted BackupArchiveTierExportPhase = "completed"
+
+	// BackupArchiveTierExportPhaseFailed means that the backup
+	// couldn't be copied to the archive tier
+	BackupArchiveTierExportPhaseFailed BackupArchiveTierExportPhase = "failed"
+)
+
+// BackupArchiveTierExportStatus contains the status of the
+// export of a backup to the archive tier
+type BackupArchiveTierExportStatus struct {
+	// The name of the destination acting as archive tier
+	// +optional
+	DestinationName string `json:"destinationName,omitempty"`
+
+	// The phase of the export
+	// +optional
+	Phase BackupArchiveTierExportPhase `json:"phase,omitempty"`
+
+	// When the export was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the export was terminated
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The detected error, if any
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // InstanceID contains the information to identify an instance
@@ -379,6 +459,13 @@ func (backupStatus *BackupStatus) IsInProgress() bool {
 		backupStatus.Phase == BackupPhaseRunning
 }
 
+// IsArchiveTierExportRunning check if the backup is being
+// exported to the archive tier
+func (backupStatus *BackupStatus) IsArchiveTierExportRunning() bool {
+	return backupStatus.ArchiveTierExport != nil &&
+		backupStatus.ArchiveTierExport.Phase == BackupArchiveTierExportPhaseRunning
+}
+
 // GetPendingBackupNames returns the pending backup list
 func (list BackupList) GetPendingBackupNames() []string {
 	// Retry the backup if another backup is running
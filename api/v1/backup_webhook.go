@@ -108,6 +108,15 @@ func (r *Backup) validate() field.ErrorList {
 		))
 	}
 
+	if (r.Spec.Method == BackupMethodVolumeSnapshot || r.Spec.Method == BackupMethodPlugin) &&
+		r.Spec.ImmediateCheckpoint != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "immediateCheckpoint"),
+			r.Spec.ImmediateCheckpoint,
+			"ImmediateCheckpoint parameter can be specified only if the backup method is barmanObjectStore",
+		))
+	}
+
 	if r.Spec.Method == BackupMethodPlugin && r.Spec.PluginConfiguration.IsEmpty() {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "pluginConfiguration"),
@@ -72,4 +72,27 @@ var _ = Describe("Backup webhook validate", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("complains if immediateCheckpoint is set on a volume snapshot backup", func() {
+		utils.SetVolumeSnapshot(true)
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:              BackupMethodVolumeSnapshot,
+				ImmediateCheckpoint: ptr.To(true),
+			},
+		}
+		result := backup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.immediateCheckpoint"))
+	})
+
+	It("accepts immediateCheckpoint on a barman backup", func() {
+		backup := &Backup{
+			Spec: BackupSpec{
+				Method:              BackupMethodBarmanObjectStore,
+				ImmediateCheckpoint: ptr.To(true),
+			},
+		}
+		Expect(backup.validate()).To(BeEmpty())
+	})
 })
@@ -21,6 +21,43 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// GetSyncReplicasUnavailablePolicy gets the behavior of the synchronous
+// replication when fewer than `minSyncReplicas` standbys are ready,
+// defaulting to blocking the write transactions
+func (cluster *Cluster) GetSyncReplicasUnavailablePolicy() SyncReplicasUnavailablePolicy {
+	if cluster.Spec.SyncReplicasUnavailablePolicy == "" {
+		return SyncReplicasUnavailablePolicyBlock
+	}
+
+	return cluster.Spec.SyncReplicasUnavailablePolicy
+}
+
+// IsSyncReplicationRelaxed checks if the number of synchronous replicas
+// has been lowered below `minSyncReplicas` because of the standbys
+// not being ready, as allowed by the `relax` policy
+func (cluster *Cluster) IsSyncReplicationRelaxed() bool {
+	if cluster.Spec.MinSyncReplicas == 0 ||
+		cluster.GetSyncReplicasUnavailablePolicy() != SyncReplicasUnavailablePolicyRelax {
+		return false
+	}
+
+	syncReplicas, _ := cluster.GetSyncReplicasData()
+	return syncReplicas < cluster.Spec.MinSyncReplicas
+}
+
+// GetFailoverLSNTolerance gets the maximum amount of WAL, in bytes, by
+// which a replica can be behind the most advanced one to be preferred for
+// its failover priority. There is no tolerance when the synchronous
+// replication is configured, as a replica behind the most advanced one may
+// be missing transactions whose commit has been acknowledged to the clients
+func (cluster *Cluster) GetFailoverLSNTolerance() int64 {
+	if cluster.Spec.MinSyncReplicas > 0 || cluster.Spec.MaxSyncReplicas > 0 {
+		return 0
+	}
+
+	return cluster.Spec.FailoverPriority.GetLSNTolerance()
+}
+
 // GetSyncReplicasData computes the actual number of required synchronous replicas and the names of
 // the electable sync replicas given the requested min, max, the number of ready replicas in the cluster and the sync
 // replicas constraints (if any)
@@ -29,10 +66,11 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	// and verify it is greater than 0 and between minSyncReplicas and maxSyncReplicas.
 	// Formula: 1 <= minSyncReplicas <= SyncReplicas <= maxSyncReplicas < readyReplicas
 	readyReplicas := len(cluster.Status.InstancesStatus[utils.PodHealthy]) - 1
+	relax := cluster.GetSyncReplicasUnavailablePolicy() == SyncReplicasUnavailablePolicyRelax
 
 	// If the number of ready replicas is negative,
 	// there are no healthy Pods so no sync replica can be configured
-	if readyReplicas < 0 {
+	if relax && readyReplicas < 0 {
 		return 0, nil
 	}
 
@@ -46,8 +84,9 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 
 	// Lower to ready replicas if min sync replicas is too high
 	// (this is a self-healing procedure that prevents from a
-	// temporarily unresponsive system)
-	if readyReplicas < cluster.Spec.MinSyncReplicas {
+	// temporarily unresponsive system), when the user chose
+	// availability over durability
+	if relax && readyReplicas < cluster.Spec.MinSyncReplicas {
 		syncReplicas = readyReplicas
 		log.Warning("Ignore minSyncReplicas to enforce self-healing",
 			"syncReplicas", readyReplicas,
@@ -55,7 +94,7 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 			"maxSyncReplicas", cluster.Spec.MaxSyncReplicas)
 	}
 
-	electableSyncReplicas = cluster.getElectableSyncReplicas()
+	electableSyncReplicas = cluster.getElectableSyncReplicas(!relax)
 	numberOfElectableSyncReplicas := len(electableSyncReplicas)
 	if numberOfElectableSyncReplicas < syncReplicas {
 		log.Warning("lowering sync replicas due to not enough electable instances for sync replication "+
@@ -69,11 +108,20 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
-// getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
-func (cluster *Cluster) getElectableSyncReplicas() []string {
+// getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas,
+// including the ones that are not ready if requested, so that they are waited for
+func (cluster *Cluster) getElectableSyncReplicas(includeNotReady bool) []string {
+	instances := cluster.Status.InstancesStatus[utils.PodHealthy]
+	if includeNotReady {
+		instances = cluster.Status.InstanceNames
+	}
+
 	var nonPrimaryInstances []string
-	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
-		if cluster.Status.CurrentPrimary != instance {
+	for _, instance := range instances {
+		// The delayed replicas are not electable, as they would
+		// hold the commits with synchronous_commit set to remote_apply
+		if cluster.Status.CurrentPrimary != instance &&
+			!cluster.Spec.PostgresConfiguration.DelayedReplicas.IsDelayedReplica(instance) {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
 	}
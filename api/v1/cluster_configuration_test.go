@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,6 +33,16 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(Equal([]string{"example-2", "example-3"}))
 	})
 
+	It("should not elect the delayed replicas", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.DelayedReplicas = &DelayedReplicasConfiguration{
+			Instances:     []string{"example-3"},
+			MinApplyDelay: "1h",
+		}
+		_, names := cluster.GetSyncReplicasData()
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
+
 	It("should return only the pod in the different AZ", func() {
 		const (
 			primaryPod     = "exampleAntiAffinity-1"
@@ -66,6 +78,7 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 
 	It("should lower the synchronous replica number to enforce self-healing", func() {
 		cluster := createFakeCluster("exampleOnePod")
+		cluster.Spec.SyncReplicasUnavailablePolicy = SyncReplicasUnavailablePolicyRelax
 		cluster.Status = ClusterStatus{
 			CurrentPrimary: "exampleOnePod-1",
 			InstancesStatus: map[utils.PodStatus][]string{
@@ -78,10 +91,35 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(number).To(BeZero())
 		Expect(names).To(BeEmpty())
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
+		Expect(cluster.IsSyncReplicationRelaxed()).To(BeTrue())
+	})
+
+	It("should keep requiring the minimum number of synchronous replicas by default", func() {
+		cluster := createFakeCluster("exampleOnePod")
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"exampleOnePod-1"},
+			utils.PodFailed:  {"exampleOnePod-2", "exampleOnePod-3"},
+		}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"exampleOnePod-2", "exampleOnePod-3"}))
+		Expect(cluster.IsSyncReplicationRelaxed()).To(BeFalse())
+	})
+
+	It("should restore the synchronous replicas when the standbys are back", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.SyncReplicasUnavailablePolicy = SyncReplicasUnavailablePolicyRelax
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-2", "example-3"}))
+		Expect(cluster.IsSyncReplicationRelaxed()).To(BeFalse())
 	})
 
 	It("should behave correctly if there is no ready host", func() {
 		cluster := createFakeCluster("exampleNoPods")
+		cluster.Spec.SyncReplicasUnavailablePolicy = SyncReplicasUnavailablePolicyRelax
 		cluster.Status = ClusterStatus{
 			CurrentPrimary: "example-1",
 			InstancesStatus: map[utils.PodStatus][]string{
@@ -94,3 +132,43 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(BeEmpty())
 	})
 })
+
+var _ = Describe("GetSyncReplicasUnavailablePolicy", func() {
+	It("defaults to blocking the write transactions", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetSyncReplicasUnavailablePolicy()).To(Equal(SyncReplicasUnavailablePolicyBlock))
+	})
+
+	It("returns the configured policy", func() {
+		cluster := &Cluster{Spec: ClusterSpec{SyncReplicasUnavailablePolicy: SyncReplicasUnavailablePolicyRelax}}
+		Expect(cluster.GetSyncReplicasUnavailablePolicy()).To(Equal(SyncReplicasUnavailablePolicyRelax))
+	})
+})
+
+var _ = Describe("GetFailoverLSNTolerance", func() {
+	It("defaults to the size of a WAL segment", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetFailoverLSNTolerance()).To(Equal(DefaultFailoverLSNTolerance.Value()))
+	})
+
+	It("returns the configured tolerance", func() {
+		tolerance := resource.MustParse("64Mi")
+		cluster := &Cluster{Spec: ClusterSpec{
+			FailoverPriority: &FailoverPriorityConfiguration{LSNTolerance: &tolerance},
+		}}
+		Expect(cluster.GetFailoverLSNTolerance()).To(Equal(tolerance.Value()))
+	})
+
+	It("has no tolerance with the synchronous replication", func() {
+		tolerance := resource.MustParse("64Mi")
+		cluster := &Cluster{Spec: ClusterSpec{
+			MinSyncReplicas:  1,
+			MaxSyncReplicas:  1,
+			FailoverPriority: &FailoverPriorityConfiguration{LSNTolerance: &tolerance},
+		}}
+		Expect(cluster.GetFailoverLSNTolerance()).To(BeZero())
+
+		cluster.Spec.MinSyncReplicas = 0
+		Expect(cluster.GetFailoverLSNTolerance()).To(BeZero())
+	})
+})
@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+)
+
+const (
+	// NamespaceDefaultsImageNameKey is the key of the namespace defaults
+	// ConfigMap containing the PostgreSQL image of the new clusters
+	NamespaceDefaultsImageNameKey = "imageName"
+
+	// NamespaceDefaultsStorageClassKey is the key of the namespace defaults
+	// ConfigMap containing the storage class of the new clusters
+	NamespaceDefaultsStorageClassKey = "storageClass"
+
+	// NamespaceDefaultsBackupKey is the key of the namespace defaults
+	// ConfigMap containing the backup configuration of the new clusters,
+	// in YAML format
+	NamespaceDefaultsBackupKey = "backup"
+)
+
+// clusterDefaulter is the defaulting webhook of the Cluster resource.
+// Other than the operator defaults, it applies the defaults of the
+// namespace to the clusters being created
+type clusterDefaulter struct {
+	reader client.Reader
+}
+
+var _ admission.CustomDefaulter = &clusterDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *clusterDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return fmt.Errorf("expected a Cluster but got a %T", obj)
+	}
+
+	// The namespace defaults are applied only at creation time, so that
+	// removing a setting from an existing cluster is always possible
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Create {
+		defaults, err := getNamespaceDefaults(ctx, d.reader, req.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := cluster.applyNamespaceDefaults(defaults); err != nil {
+			return err
+		}
+	}
+
+	cluster.Default()
+	return nil
+}
+
+// getNamespaceDefaults gets the content of the namespace defaults ConfigMap
+// of the passed namespace, returning nil if it doesn't exist or if the
+// namespace defaults are disabled
+func getNamespaceDefaults(
+	ctx context.Context,
+	reader client.Reader,
+	namespace string,
+) (map[string]string, error) {
+	name := configuration.Current.NamespaceDefaultsConfigMapName
+	if name == "" || namespace == "" {
+		return nil, nil
+	}
+
+	var configMap corev1.ConfigMap
+	err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &configMap)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while reading the namespace defaults from ConfigMap %s: %w", name, err)
+	}
+
+	return configMap.Data, nil
+}
+
+// applyNamespaceDefaults sets the passed namespace defaults in the settings
+// that are not specified in the cluster, before the operator defaults are
+// applied. This way, the settings of the cluster take precedence over the
+// namespace defaults, which take precedence over the operator defaults.
+func (r *Cluster) applyNamespaceDefaults(defaults map[string]string) error {
+	if imageName := defaults[NamespaceDefaultsImageNameKey]; imageName != "" &&
+		r.Spec.ImageName == "" && r.Spec.ImageCatalogRef == nil {
+		r.Spec.ImageName = imageName
+	}
+
+	if storageClass := defaults[NamespaceDefaultsStorageClassKey]; storageClass != "" {
+		r.Spec.StorageConfiguration.defaultStorageClass(storageClass)
+		if r.Spec.WalStorage != nil {
+			r.Spec.WalStorage.defaultStorageClass(storageClass)
+		}
+	}
+
+	if backup := defaults[NamespaceDefaultsBackupKey]; backup != "" && r.Spec.Backup == nil {
+		var backupConfiguration BackupConfiguration
+		if err := yaml.UnmarshalStrict([]byte(backup), &backupConfiguration); err != nil {
+			return fmt.Errorf("while decoding the %q key of the namespace defaults: %w",
+				NamespaceDefaultsBackupKey, err)
+		}
+		r.Spec.Backup = &backupConfiguration
+	}
+
+	return nil
+}
+
+// defaultStorageClass sets the passed storage class, unless one
+// has already been chosen in the storage configuration
+func (s *StorageConfiguration) defaultStorageClass(storageClass string) {
+	if s.StorageClass != nil {
+		return
+	}
+	if s.PersistentVolumeClaimTemplate != nil && s.PersistentVolumeClaimTemplate.StorageClassName != nil {
+		return
+	}
+
+	s.StorageClass = &storageClass
+}
@@ -0,0 +1,128 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("namespace defaults", func() {
+	defaults := map[string]string{
+		NamespaceDefaultsImageNameKey:    "ghcr.io/cloudnative-pg/postgresql:16.3",
+		NamespaceDefaultsStorageClassKey: "team-storage",
+		NamespaceDefaultsBackupKey: `
+barmanObjectStore:
+  destinationPath: s3://team-backups/
+  s3Credentials:
+    inheritFromIAMRole: true
+retentionPolicy: 30d
+`,
+	}
+
+	It("applies the namespace defaults to the unset settings", func() {
+		cluster := &Cluster{Spec: ClusterSpec{WalStorage: &StorageConfiguration{}}}
+		Expect(cluster.applyNamespaceDefaults(defaults)).To(Succeed())
+		Expect(cluster.Spec.ImageName).To(Equal("ghcr.io/cloudnative-pg/postgresql:16.3"))
+		Expect(cluster.Spec.StorageConfiguration.StorageClass).To(Equal(ptr.To("team-storage")))
+		Expect(cluster.Spec.WalStorage.StorageClass).To(Equal(ptr.To("team-storage")))
+		Expect(cluster.Spec.Backup.BarmanObjectStore.DestinationPath).To(Equal("s3://team-backups/"))
+		Expect(cluster.Spec.Backup.RetentionPolicy).To(Equal("30d"))
+	})
+
+	It("gives precedence to the settings of the cluster", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageCatalogRef: &ImageCatalogRef{Major: 16},
+				StorageConfiguration: StorageConfiguration{
+					PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+						StorageClassName: ptr.To("fast"),
+					},
+				},
+				Backup: &BackupConfiguration{},
+			},
+		}
+		Expect(cluster.applyNamespaceDefaults(defaults)).To(Succeed())
+		Expect(cluster.Spec.ImageName).To(BeEmpty())
+		Expect(cluster.Spec.StorageConfiguration.StorageClass).To(BeNil())
+		Expect(cluster.Spec.Backup.BarmanObjectStore).To(BeNil())
+	})
+
+	It("rejects an invalid backup configuration", func() {
+		cluster := &Cluster{}
+		Expect(cluster.applyNamespaceDefaults(map[string]string{
+			NamespaceDefaultsBackupKey: "barmanObjectStore:\n  destination: s3://team-backups/\n",
+		})).ToNot(Succeed())
+	})
+
+	Context("defaulting webhook", func() {
+		var defaulter *clusterDefaulter
+
+		BeforeEach(func() {
+			previousName := configuration.Current.NamespaceDefaultsConfigMapName
+			configuration.Current.NamespaceDefaultsConfigMapName = "cnpg-defaults"
+			DeferCleanup(func() {
+				configuration.Current.NamespaceDefaultsConfigMapName = previousName
+			})
+
+			defaulter = &clusterDefaulter{
+				reader: fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "cnpg-defaults", Namespace: "team-a"},
+					Data: map[string]string{
+						NamespaceDefaultsImageNameKey: "ghcr.io/cloudnative-pg/postgresql:16.3",
+					},
+				}).Build(),
+			}
+		})
+
+		withOperation := func(ctx SpecContext, namespace string, operation admissionv1.Operation) context.Context {
+			return admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Namespace: namespace, Operation: operation},
+			})
+		}
+
+		It("applies the namespace defaults before the operator ones on creation", func(ctx SpecContext) {
+			cluster := &Cluster{}
+			Expect(defaulter.Default(withOperation(ctx, "team-a", admissionv1.Create), cluster)).To(Succeed())
+			Expect(cluster.Spec.ImageName).To(Equal("ghcr.io/cloudnative-pg/postgresql:16.3"))
+		})
+
+		It("applies only the operator defaults without a namespace defaults ConfigMap", func(ctx SpecContext) {
+			cluster := &Cluster{}
+			Expect(defaulter.Default(withOperation(ctx, "team-b", admissionv1.Create), cluster)).To(Succeed())
+			Expect(cluster.Spec.ImageName).To(Equal(configuration.Current.PostgresImageName))
+		})
+
+		It("doesn't apply the namespace defaults on update", func(ctx SpecContext) {
+			cluster := &Cluster{Spec: ClusterSpec{ImageName: "postgres:16"}}
+			Expect(defaulter.Default(withOperation(ctx, "team-a", admissionv1.Update), cluster)).To(Succeed())
+			Expect(cluster.Spec.ImageName).To(Equal("postgres:16"))
+			Expect(cluster.Spec.StorageConfiguration.StorageClass).To(BeNil())
+		})
+	})
+})
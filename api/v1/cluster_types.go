@@ -26,10 +26,12 @@ import (
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/robfig/cron"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -126,6 +128,71 @@ const (
 	// MissingWALDiskSpaceExitCode is the exit code the instance manager
 	// will use to signal that there's no more WAL disk space
 	MissingWALDiskSpaceExitCode = 4
+
+	// BackupVerificationJobSuffix is the suffix appended to the cluster name to
+	// get the name of the job verifying the latest base backup
+	BackupVerificationJobSuffix = "-backup-verification"
+
+	// ImportSourceClusterSuffix is the suffix appended to the cluster name to
+	// get the name of the temporary cluster used to import a database
+	// from a base backup
+	ImportSourceClusterSuffix = "-import-source"
+
+	// DefaultBackupVerificationSchedule is the schedule used to verify
+	// the base backups when the user hasn't specified one
+	DefaultBackupVerificationSchedule = "0 0 0 * * 0"
+
+	// DefaultScheduledMaintenanceSchedule is the schedule of the maintenance
+	// of the databases when the user hasn't specified one
+	DefaultScheduledMaintenanceSchedule = "0 0 2 * * 0"
+
+	// DefaultQuarantineRestartWindow is the number of seconds in which the
+	// restarts of an instance are counted when the user hasn't specified it
+	DefaultQuarantineRestartWindow = 3600
+
+	// DefaultWalArchiveCheckInterval is the number of seconds between two
+	// checks of the WAL archive when the user hasn't specified it
+	DefaultWalArchiveCheckInterval = 3600
+
+	// DefaultTableStatisticsInterval is the number of seconds between two
+	// samples of the table statistics when the user hasn't specified it
+	DefaultTableStatisticsInterval = 900
+
+	// DefaultTableStatisticsMaxTables is the number of tables reported
+	// for each database when the user hasn't specified it
+	DefaultTableStatisticsMaxTables = 10
+
+	// DefaultTransactionIDWarningAge is the age of the oldest unfrozen
+	// transaction ID above which a warning is reported, when the user
+	// hasn't specified it
+	DefaultTransactionIDWarningAge = 1000000000
+
+	// DefaultTransactionIDCriticalAge is the age of the oldest unfrozen
+	// transaction ID above which the risk of wraparound is reported as
+	// critical, when the user hasn't specified it
+	DefaultTransactionIDCriticalAge = 1500000000
+
+	// DefaultDiskUsageProjectionWindow is the number of seconds of disk
+	// usage samples used to compute the growth rate of the volumes, when
+	// the user hasn't specified it
+	DefaultDiskUsageProjectionWindow = 3600
+
+	// DefaultDiskTimeToFullThreshold is the number of seconds below which
+	// the projected time needed to fill a volume is reported, when the
+	// user hasn't specified it
+	DefaultDiskTimeToFullThreshold = 86400
+
+	// DefaultBackupHookTimeout is the number of seconds after which a
+	// backup hook is terminated when the user hasn't specified it
+	DefaultBackupHookTimeout = 300
+
+	// DefaultAdaptiveArchiveTimeoutMin is the lowest value of archive_timeout,
+	// in seconds, when the user hasn't specified it
+	DefaultAdaptiveArchiveTimeoutMin = 60
+
+	// DefaultAdaptiveArchiveTimeoutMax is the highest value of archive_timeout,
+	// in seconds, when the user hasn't specified it
+	DefaultAdaptiveArchiveTimeoutMax = 1800
 )
 
 // SnapshotOwnerReference defines the reference type for the owner of the snapshot.
@@ -293,6 +360,14 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=1
 	Instances int `json:"instances"`
 
+	// The automatic scaling of the number of instances, depending on the
+	// active client connections of the replicas. When enabled, the operator
+	// chooses the number of instances within the configured bounds and
+	// reports it in the status, while the `instances` field is only used
+	// as the initial number
+	// +optional
+	ReplicaAutoscaling *ReplicaAutoscalingConfiguration `json:"replicaAutoscaling,omitempty"`
+
 	// Minimum number of instances required in synchronous replication with the
 	// primary. Undefined or 0 allow writes to complete when no standby is
 	// available.
@@ -309,6 +384,17 @@ type ClusterSpec struct {
 	// +optional
 	MaxSyncReplicas int `json:"maxSyncReplicas,omitempty"`
 
+	// The behavior when fewer than `minSyncReplicas` standbys are ready.
+	// With `block`, the default, the primary keeps requiring
+	// `minSyncReplicas` synchronous standbys, and the write transactions
+	// wait for them to come back. With `relax`, the number of synchronous
+	// standbys is lowered to the ready ones, down to disabling synchronous
+	// replication when no standby is ready, and restored when the
+	// standbys come back, trading durability for availability
+	// +kubebuilder:validation:Enum=block;relax
+	// +optional
+	SyncReplicasUnavailablePolicy SyncReplicasUnavailablePolicy `json:"syncReplicasUnavailablePolicy,omitempty"`
+
 	// Configuration of the PostgreSQL server
 	// +optional
 	PostgresConfiguration PostgresConfiguration `json:"postgresql,omitempty"`
@@ -341,6 +427,11 @@ type ClusterSpec struct {
 	// +optional
 	EnableSuperuserAccess *bool `json:"enableSuperuserAccess,omitempty"`
 
+	// The scheduled rotation of the passwords of the superuser and of the
+	// application user, applied to the secrets generated by the operator
+	// +optional
+	PasswordRotation *PasswordRotationConfiguration `json:"passwordRotation,omitempty"`
+
 	// The configuration for the CA and related certificates
 	// +optional
 	Certificates *CertificatesConfiguration `json:"certificates,omitempty"`
@@ -361,6 +452,11 @@ type ClusterSpec struct {
 	// +optional
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// The configuration of a WAL restore cache shared by the instances, to
+	// download each WAL file from the object store only once
+	// +optional
+	WalRestoreCache *WalRestoreCacheConfiguration `json:"walRestoreCache,omitempty"`
+
 	// EphemeralVolumeSource allows the user to configure the source of ephemeral volumes.
 	// +optional
 	EphemeralVolumeSource *corev1.EphemeralVolumeSource `json:"ephemeralVolumeSource,omitempty"`
@@ -393,6 +489,16 @@ type ClusterSpec struct {
 	// +optional
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// The maximum time in seconds to wait, during a switchover, for the
+	// transactions running on the former primary to complete before shutting
+	// it down. While this happens, the former primary is removed from the
+	// `-rw` service, so that it doesn't receive new connections. The
+	// transactions still running when the timeout expires are terminated.
+	// Default value is 0 (disabled).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SwitchoverDrainTimeout int32 `json:"switchoverDrainTimeout,omitempty"`
+
 	// The amount of time (in seconds) to wait before triggering a failover
 	// after the primary PostgreSQL instance in the cluster was detected
 	// to be unhealthy
@@ -400,6 +506,51 @@ type ClusterSpec struct {
 	// +optional
 	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 
+	// Configuration of the cooldown period following the promotion of a
+	// new primary, during which the operator won't initiate another failover
+	// +optional
+	FailoverCooldown *FailoverCooldownConfiguration `json:"failoverCooldown,omitempty"`
+
+	// The priority of the replicas to be promoted during a failover,
+	// among the ones which are close enough to the most advanced replica
+	// +optional
+	FailoverPriority *FailoverPriorityConfiguration `json:"failoverPriority,omitempty"`
+
+	// Configuration of the quarantine of the instances that keep crashing.
+	// A quarantined instance is fenced, so that it is not restarted anymore
+	// and it can be inspected
+	// +optional
+	Quarantine *QuarantineConfiguration `json:"quarantine,omitempty"`
+
+	// The action taken when a former primary can't rejoin the cluster as a
+	// replica because `pg_rewind` can't be used, for example because the WAL
+	// files needed to find the point of divergence are not available anymore.
+	// With `wait` (default), `pg_rewind` is retried until the issue is solved
+	// manually. With `reclone`, the storage of the former primary is deleted,
+	// and the instance is recreated as a new replica of the current primary,
+	// discarding the changes that were not replicated before the failover.
+	// +kubebuilder:validation:Enum=wait;reclone
+	// +kubebuilder:default:=wait
+	// +optional
+	RewindFailurePolicy RewindFailurePolicy `json:"rewindFailurePolicy,omitempty"`
+
+	// Configuration of the readiness of the replicas based on their
+	// replication lag. When set, a replica lagging behind the primary
+	// more than the threshold is reported as not ready, and it's removed
+	// from the endpoints of the services until it catches up
+	// +optional
+	ReplicaReadiness *ReplicaReadinessConfiguration `json:"replicaReadiness,omitempty"`
+
+	// Configuration of the scheduled maintenance of the databases, like
+	// the periodic rebuild of the indexes. It is run by the primary instance
+	// +optional
+	ScheduledMaintenance *ScheduledMaintenanceConfiguration `json:"scheduledMaintenance,omitempty"`
+
+	// The policy applied when the cluster is deleted. By default, the
+	// PVCs and the backups of the cluster are retained
+	// +optional
+	DeletionPolicy *DeletionPolicyConfiguration `json:"deletionPolicy,omitempty"`
+
 	// LivenessProbeTimeout is the time (in seconds) that is allowed for a PostgreSQL instance
 	// to successfully respond to the liveness probe (default 30).
 	// The Liveness probe failure threshold is derived from this value using the formula:
@@ -407,6 +558,11 @@ type ClusterSpec struct {
 	// +optional
 	LivenessProbeTimeout *int32 `json:"livenessProbeTimeout,omitempty"`
 
+	// The configuration of the probes of the PostgreSQL container,
+	// overriding the values derived from `startDelay`
+	// +optional
+	Probes *ProbesConfiguration `json:"probes,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -417,6 +573,11 @@ type ClusterSpec struct {
 	// +optional
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
+	// The placement rules of the pods of the primary and of the replicas,
+	// overriding the ones defined for every pod of the cluster
+	// +optional
+	RolePlacement *RolePlacementConfiguration `json:"rolePlacement,omitempty"`
+
 	// Resources requirements of every generated Pod. Please refer to
 	// https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// for more information.
@@ -427,6 +588,14 @@ type ClusterSpec struct {
 	// volumes
 	EphemeralVolumesSizeLimit *EphemeralVolumesSizeLimitConfiguration `json:"ephemeralVolumesSizeLimit,omitempty"`
 
+	// Additional containers to be run in every instance pod, next to
+	// PostgreSQL. They can mount the `pgdata`, `pg-wal`, `scratch-data`
+	// and `shm` volumes of the instance, and they are restarted by the
+	// kubelet without restarting PostgreSQL. Changing them triggers a
+	// rolling update of the cluster
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
 	// Name of the priority class which will be used in every generated Pod, if the PriorityClass
 	// specified does not exist, the pod will not be able to schedule.  Please refer to
 	// https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/#priorityclass
@@ -450,6 +619,12 @@ type ClusterSpec struct {
 	// +optional
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// When the instances are restarted to apply the changes to the
+	// PostgreSQL parameters requiring a restart: immediately (default),
+	// during a maintenance window, or after the approval of the user
+	// +optional
+	ParameterChangeRestart *ParameterChangeRestartConfiguration `json:"parameterChangeRestart,omitempty"`
+
 	// The configuration to be used for backups
 	// +optional
 	Backup *BackupConfiguration `json:"backup,omitempty"`
@@ -514,6 +689,13 @@ type ClusterSpec struct {
 	// +optional
 	EnablePDB *bool `json:"enablePDB,omitempty"`
 
+	// The policies used to generate the `PodDisruptionBudget` resources
+	// of the primary and of the replicas, when `enablePDB` is `true`.
+	// If not specified, the primary can't be evicted while the replicas
+	// can be evicted one at a time.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetConfiguration `json:"podDisruptionBudget,omitempty"`
+
 	// The plugins configuration, containing
 	// any plugin to be loaded with the corresponding configuration
 	Plugins PluginConfigurationList `json:"plugins,omitempty"`
@@ -576,8 +758,46 @@ const (
 
 	// PhaseCannotCreateClusterObjects is set by the operator when is unable to create cluster resources
 	PhaseCannotCreateClusterObjects = "Unable to create required cluster objects"
+
+	// PhaseImportSourceRecovery is set by the operator while the base backup
+	// containing the database to be imported is being recovered
+	PhaseImportSourceRecovery = "Recovering the backup to import the database from"
+
+	// PhaseMajorUpgrade is set by the operator while the data directory
+	// is being upgraded to a new PostgreSQL major version
+	PhaseMajorUpgrade = "Upgrading Postgres major version"
+
+	// PhaseMajorUpgradeFailed is set by the operator when the upgrade of
+	// the data directory to a new PostgreSQL major version failed
+	PhaseMajorUpgradeFailed = "Postgres major version upgrade failed, needs manual intervention"
 )
 
+// ImageInfo contains the information about a PostgreSQL image
+type ImageInfo struct {
+	// Image is the image name
+	Image string `json:"image"`
+
+	// MajorVersion is the major version of PostgreSQL
+	MajorVersion int `json:"majorVersion"`
+}
+
+// WalRestoreCacheConfiguration is the configuration of the WAL restore
+// cache shared by the instances of a cluster. The first instance needing
+// a WAL file downloads it, together with the prefetched ones, into the
+// cache, where the other instances read it from
+type WalRestoreCacheConfiguration struct {
+	// The name of the PersistentVolumeClaim hosting the cache, in the
+	// namespace of the cluster. It must be mountable by every instance
+	// at the same time, i.e. having the `ReadWriteMany` access mode
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// How long the WAL files are kept in the cache, expressed as a
+	// Go duration like `30m` or `2h`. Defaults to `1h`
+	// +optional
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
+}
+
 // EphemeralVolumesSizeLimitConfiguration contains the configuration of the ephemeral
 // storage
 type EphemeralVolumesSizeLimitConfiguration struct {
@@ -764,6 +984,15 @@ type ClusterStatus struct {
 	// +optional
 	ReadyInstances int `json:"readyInstances,omitempty"`
 
+	// The node where the instance being replaced was running, which the
+	// instance created in its place is not scheduled on
+	// +optional
+	ReplacedInstanceNode string `json:"replacedInstanceNode,omitempty"`
+
+	// The label selector of the instance pods, used by the scale subresource
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
 	// InstancesStatus indicates in which status the instances are
 	// +optional
 	InstancesStatus map[utils.PodStatus][]string `json:"instancesStatus,omitempty"`
@@ -780,6 +1009,22 @@ type ClusterStatus struct {
 	// +optional
 	TablespacesStatus []TablespaceState `json:"tablespacesStatus,omitempty"`
 
+	// LogicalReplicationSlotsStatus reports the state of the managed
+	// logical replication slots in the primary instance
+	// +optional
+	LogicalReplicationSlotsStatus []LogicalReplicationSlotStatus `json:"logicalReplicationSlotsStatus,omitempty"`
+
+	// TimeoutExemptRoles are the roles which have the default timeouts
+	// disabled by the operator, and whose settings are managed by it
+	// +optional
+	TimeoutExemptRoles []string `json:"timeoutExemptRoles,omitempty"`
+
+	// WalRestoreCacheClaim is the PersistentVolumeClaim hosting the shared
+	// WAL restore cache, set once the operator verified it can be mounted
+	// by the instances
+	// +optional
+	WalRestoreCacheClaim string `json:"walRestoreCacheClaim,omitempty"`
+
 	// The timeline of the Postgres cluster
 	// +optional
 	TimelineID int `json:"timelineID,omitempty"`
@@ -868,6 +1113,11 @@ type ClusterStatus struct {
 	// +optional
 	Certificates CertificatesStatus `json:"certificates,omitempty"`
 
+	// The time of the last scheduled rotation of the passwords
+	// of the superuser and of the application user
+	// +optional
+	LastPasswordRotationTime *metav1.Time `json:"lastPasswordRotationTime,omitempty"`
+
 	// The first recoverability point, stored as a date in RFC3339 format.
 	// This field is calculated from the content of FirstRecoverabilityPointByMethod
 	// +optional
@@ -877,6 +1127,17 @@ type ClusterStatus struct {
 	// +optional
 	FirstRecoverabilityPointByMethod map[BackupMethod]metav1.Time `json:"firstRecoverabilityPointByMethod,omitempty"`
 
+	// The first WAL file needed to recover from the oldest successful
+	// base backup in the object store
+	// +optional
+	FirstRequiredWAL string `json:"firstRequiredWAL,omitempty"`
+
+	// The time when the oldest successful base backup in the object store
+	// started, that is when FirstRequiredWAL was being written, stored as
+	// a date in RFC3339 format
+	// +optional
+	FirstRequiredWALTime string `json:"firstRequiredWALTime,omitempty"`
+
 	// Last successful backup, stored as a date in RFC3339 format
 	// This field is calculated from the content of LastSuccessfulBackupByMethod
 	// +optional
@@ -903,6 +1164,11 @@ type ClusterStatus struct {
 	// +optional
 	CurrentPrimaryFailingSinceTimestamp string `json:"currentPrimaryFailingSinceTimestamp,omitempty"`
 
+	// The timestamp when the failover in progress, if any, has been
+	// started, that is when the primary was detected to be unhealthy
+	// +optional
+	FailoverStartedTimestamp string `json:"failoverStartedTimestamp,omitempty"`
+
 	// The timestamp when the last request for a new primary has occurred
 	// +optional
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
@@ -939,6 +1205,12 @@ type ClusterStatus struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// PGDataImageInfo contains the details of the latest image that
+	// has run on the data directory of the primary instance, and the
+	// PostgreSQL major version of the data directory itself
+	// +optional
+	PGDataImageInfo *ImageInfo `json:"pgDataImageInfo,omitempty"`
+
 	// PluginStatus is the status of the loaded plugins
 	PluginStatus []PluginStatus `json:"pluginStatus,omitempty"`
 
@@ -952,202 +1224,1431 @@ type ClusterStatus struct {
 	// WAL file, and Time of latest checkpoint
 	// +optional
 	DemotionToken string `json:"demotionToken,omitempty"`
-}
 
-// SwitchReplicaClusterStatus contains all the statuses regarding the switch of a cluster to a replica cluster
-type SwitchReplicaClusterStatus struct {
-	// InProgress indicates if there is an ongoing procedure of switching a cluster to a replica cluster.
+	// LastBackupVerification is the outcome of the last backup verification
 	// +optional
-	InProgress bool `json:"inProgress,omitempty"`
-}
+	LastBackupVerification *BackupVerificationStatus `json:"lastBackupVerification,omitempty"`
 
-// InstanceReportedState describes the last reported state of an instance during a reconciliation loop
-type InstanceReportedState struct {
-	// indicates if an instance is the primary one
-	IsPrimary bool `json:"isPrimary"`
-	// indicates on which TimelineId the instance is
+	// WalArchiveCheck is the outcome of the last check of the
+	// continuity of the WAL archive
 	// +optional
-	TimeLineID int `json:"timeLineID,omitempty"`
-}
+	WalArchiveCheck *WalArchiveCheckStatus `json:"walArchiveCheck,omitempty"`
 
-// ClusterConditionType defines types of cluster conditions
-type ClusterConditionType string
+	// QuarantinedInstances is the list of the instances that
+	// have been fenced because they kept crashing
+	// +optional
+	QuarantinedInstances []string `json:"quarantinedInstances,omitempty"`
 
-// These are valid conditions of a Cluster, some of the conditions could be owned by
-// Instance Manager and some of them could be owned by reconciler.
-const (
-	// ConditionContinuousArchiving represents whether WAL archiving is working
-	ConditionContinuousArchiving ClusterConditionType = "ContinuousArchiving"
-	// ConditionBackup represents the last backup's status
-	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
-	// ConditionClusterReady represents whether a cluster is Ready
-	ConditionClusterReady ClusterConditionType = "Ready"
-)
+	// ScheduledMaintenance is the status of the last scheduled
+	// maintenance of the databases
+	// +optional
+	ScheduledMaintenance *ScheduledMaintenanceStatus `json:"scheduledMaintenance,omitempty"`
 
-// A Condition that can be used to communicate the Backup progress
-var (
-	// BackupSucceededCondition is added to a backup
-	// when it was completed correctly
-	BackupSucceededCondition = &metav1.Condition{
-		Type:    string(ConditionBackup),
-		Status:  metav1.ConditionTrue,
-		Reason:  string(ConditionReasonLastBackupSucceeded),
-		Message: "Backup was successful",
-	}
+	// StatStatementsReset is the status of the last scheduled
+	// reset of the `pg_stat_statements` statistics
+	// +optional
+	StatStatementsReset *StatStatementsResetStatus `json:"statStatementsReset,omitempty"`
 
-	// BackupStartingCondition is added to a backup
-	// when it started
-	BackupStartingCondition = &metav1.Condition{
-		Type:    string(ConditionBackup),
-		Status:  metav1.ConditionFalse,
-		Reason:  string(ConditionBackupStarted),
-		Message: "New Backup starting up",
-	}
+	// PendingRestart is the restart required to apply the changes to the
+	// PostgreSQL parameters, when deferred by the restart strategy
+	// +optional
+	PendingRestart *PendingRestartStatus `json:"pendingRestart,omitempty"`
 
-	// BuildClusterBackupFailedCondition builds
-	// ConditionReasonLastBackupFailed condition
-	BuildClusterBackupFailedCondition = func(err error) *metav1.Condition {
-		return &metav1.Condition{
-			Type:    string(ConditionBackup),
-			Status:  metav1.ConditionFalse,
-			Reason:  string(ConditionReasonLastBackupFailed),
-			Message: err.Error(),
-		}
-	}
-)
+	// RecoveredAlterSystemSettings contains the parameters that were set
+	// with `ALTER SYSTEM` in the origin cluster, as found in the recovered
+	// backup
+	// +optional
+	RecoveredAlterSystemSettings *RecoveredAlterSystemSettingsStatus `json:"recoveredAlterSystemSettings,omitempty"`
 
-// ConditionStatus defines conditions of resources
-type ConditionStatus string
+	// RewindStatus is the outcome of the latest `pg_rewind` executed
+	// by the former primaries to rejoin the cluster as replicas
+	// +optional
+	RewindStatus map[PodName]InstanceRewindStatus `json:"rewindStatus,omitempty"`
 
-// These are valid condition statuses. "ConditionTrue" means a resource is in the condition;
-// "ConditionFalse" means a resource is not in the condition; "ConditionUnknown" means kubernetes
-// can't decide if a resource is in the condition or not. In the future, we could add other
-// intermediate conditions, e.g. ConditionDegraded
-const (
-	ConditionTrue    ConditionStatus = "True"
-	ConditionFalse   ConditionStatus = "False"
-	ConditionUnknown ConditionStatus = "Unknown"
-)
+	// LogicalImport is the progress of the logical import of the
+	// databases, when bootstrapping the cluster with `initdb.import`
+	// +optional
+	LogicalImport *LogicalImportStatus `json:"logicalImport,omitempty"`
 
-// ConditionReason defines the reason why a certain
-// condition changed
-type ConditionReason string
+	// ReplicaAutoscaling is the status of the automatic scaling of the
+	// number of instances
+	// +optional
+	ReplicaAutoscaling *ReplicaAutoscalingStatus `json:"replicaAutoscaling,omitempty"`
 
-const (
-	// ConditionBackupStarted means that the condition changed because the debug
-	// started
-	ConditionBackupStarted ConditionReason = "BackupStarted"
+	// Switchover is the progress of the last switchover requested
+	// with the `cnpg.io/switchoverTo` annotation
+	// +optional
+	Switchover *SwitchoverStatus `json:"switchover,omitempty"`
+}
 
-	// ConditionReasonLastBackupSucceeded means that the condition changed because the last backup
-	// has been taken successfully
-	ConditionReasonLastBackupSucceeded ConditionReason = "LastBackupSucceeded"
+// ReplicaAutoscalingStatus is the status of the automatic scaling of the
+// number of instances
+type ReplicaAutoscalingStatus struct {
+	// The number of instances chosen by the operator, which takes the
+	// place of the `instances` field of the specification
+	// +optional
+	DesiredInstances int `json:"desiredInstances,omitempty"`
 
-	// ConditionReasonLastBackupFailed means that the condition changed because the last backup
-	// failed
-	ConditionReasonLastBackupFailed ConditionReason = "LastBackupFailed"
+	// When the number of instances was last changed by the operator
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
 
-	// ConditionReasonContinuousArchivingSuccess means that the condition changed because the
-	// WAL archiving was working correctly
-	ConditionReasonContinuousArchivingSuccess ConditionReason = "ContinuousArchivingSuccess"
+	// Since when the active connections require less instances than the
+	// current ones
+	// +optional
+	ScaleDownPendingSince *metav1.Time `json:"scaleDownPendingSince,omitempty"`
 
-	// ConditionReasonContinuousArchivingFailing means that the condition has changed because
-	// the WAL archiving is not working correctly
-	ConditionReasonContinuousArchivingFailing ConditionReason = "ContinuousArchivingFailing"
+	// The replica whose connections are being drained before removing it
+	// +optional
+	DrainingInstance string `json:"drainingInstance,omitempty"`
 
-	// ClusterReady means that the condition changed because the cluster is ready and working properly
-	ClusterReady ConditionReason = "ClusterIsReady"
+	// When the drain of the connections of the replica was started
+	// +optional
+	DrainStartedAt *metav1.Time `json:"drainStartedAt,omitempty"`
+}
 
-	// ClusterIsNotReady means that the condition changed because the cluster is not ready
-	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+// SwitchoverPhase is the phase of a requested switchover
+type SwitchoverPhase string
 
-	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
-	DetachedVolume ConditionReason = "DetachedVolume"
+const (
+	// SwitchoverPhasePreparing means that the target instance has been
+	// checked and the switchover can still be cancelled by removing the
+	// request
+	SwitchoverPhasePreparing SwitchoverPhase = "preparing"
+
+	// SwitchoverPhaseDemotingPrimary means that the current primary has
+	// been asked to shut down, and the switchover can't be cancelled anymore
+	SwitchoverPhaseDemotingPrimary SwitchoverPhase = "demotingPrimary"
+
+	// SwitchoverPhaseWaitingForSync means that the former primary is down
+	// and the target instance is replaying the WAL it received from it
+	SwitchoverPhaseWaitingForSync SwitchoverPhase = "waitingForSync"
+
+	// SwitchoverPhasePromotingTarget means that the target instance has
+	// been promoted and is taking over the primary role
+	SwitchoverPhasePromotingTarget SwitchoverPhase = "promotingTarget"
+
+	// SwitchoverPhaseReconfiguringReplicas means that the target instance
+	// is the new primary, and the operator is waiting for the other
+	// instances to follow it
+	SwitchoverPhaseReconfiguringReplicas SwitchoverPhase = "reconfiguringReplicas"
+
+	// SwitchoverPhaseCompleted means that the target instance is the new
+	// primary and every replica is following it
+	SwitchoverPhaseCompleted SwitchoverPhase = "completed"
+
+	// SwitchoverPhaseCancelled means that the request has been removed
+	// before the current primary was asked to shut down
+	SwitchoverPhaseCancelled SwitchoverPhase = "cancelled"
+
+	// SwitchoverPhaseAborted means that the switchover has been rejected
+	// or interrupted, and the target instance is not the primary
+	SwitchoverPhaseAborted SwitchoverPhase = "aborted"
 )
 
-// EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
-type EmbeddedObjectMetadata struct {
+// SwitchoverStatus is the progress of a requested switchover
+type SwitchoverStatus struct {
+	// The instance which was the primary when the switchover was requested
 	// +optional
-	Labels map[string]string `json:"labels,omitempty"`
+	SourcePrimary string `json:"sourcePrimary,omitempty"`
 
+	// The instance which is going to be the new primary
 	// +optional
-	Annotations map[string]string `json:"annotations,omitempty"`
-}
+	TargetPrimary string `json:"targetPrimary,omitempty"`
 
-// PoolerIntegrations encapsulates the needed integration for the poolers referencing the cluster
-type PoolerIntegrations struct {
+	// The phase of the switchover
 	// +optional
-	PgBouncerIntegration PgBouncerIntegrationStatus `json:"pgBouncerIntegration,omitempty"`
-}
+	Phase SwitchoverPhase `json:"phase,omitempty"`
 
-// PgBouncerIntegrationStatus encapsulates the needed integration for the pgbouncer poolers referencing the cluster
-type PgBouncerIntegrationStatus struct {
+	// When the phase of the switchover was entered
 	// +optional
-	Secrets []string `json:"secrets,omitempty"`
+	PhaseStartedAt *metav1.Time `json:"phaseStartedAt,omitempty"`
+
+	// When the switchover was requested
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the switchover was terminated
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// A human-readable description of the phase, or the reason why the
+	// switchover has been aborted
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
-// ReplicaClusterConfiguration encapsulates the configuration of a replica
-// cluster
-type ReplicaClusterConfiguration struct {
-	// Self defines the name of this cluster. It is used to determine if this is a primary
-	// or a replica cluster, comparing it with `primary`
-	Self string `json:"self,omitempty"`
+// LogicalImportPhase is the phase of the logical import of the databases
+type LogicalImportPhase string
 
-	// Primary defines which Cluster is defined to be the primary in the distributed PostgreSQL cluster, based on the
-	// topology specified in externalClusters
-	Primary string `json:"primary,omitempty"`
+const (
+	// LogicalImportPhaseRunning means that the import is running
+	LogicalImportPhaseRunning LogicalImportPhase = "running"
 
-	// The name of the external cluster which is the replication origin
-	// +kubebuilder:validation:MinLength=1
-	Source string `json:"source"`
+	// LogicalImportPhaseCompleted means that every database has been imported
+	LogicalImportPhaseCompleted LogicalImportPhase = "completed"
 
-	// If replica mode is enabled, this cluster will be a replica of an
-	// existing cluster. Replica cluster can be created from a recovery
-	// object store or via streaming through pg_basebackup.
-	// Refer to the Replica clusters page of the documentation for more information.
-	Enabled *bool `json:"enabled,omitempty"`
+	// LogicalImportPhaseFailed means that the import failed, and will
+	// be retried from scratch by the import job
+	LogicalImportPhaseFailed LogicalImportPhase = "failed"
+)
 
-	// A demotion token generated by an external cluster used to
-	// check if the promotion requirements are met.
-	PromotionToken string `json:"promotionToken,omitempty"`
-}
+// LogicalImportStatus is the progress of the logical import of the databases
+type LogicalImportStatus struct {
+	// The phase of the import
+	// +optional
+	Phase LogicalImportPhase `json:"phase,omitempty"`
 
-// DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
-const DefaultReplicationSlotsUpdateInterval = 30
+	// The databases to be imported
+	// +optional
+	Databases []string `json:"databases,omitempty"`
 
-// DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
-const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
+	// The databases that have already been imported
+	// +optional
+	ImportedDatabases []string `json:"importedDatabases,omitempty"`
 
-// SynchronizeReplicasConfiguration contains the configuration for the synchronization of user defined
-// physical replication slots
-type SynchronizeReplicasConfiguration struct {
-	// When set to true, every replication slot that is on the primary is synchronized on each standby
-	// +kubebuilder:default:=true
-	Enabled *bool `json:"enabled"`
+	// The step being executed, such as the import of a section
+	// of a database
+	// +optional
+	Step string `json:"step,omitempty"`
 
-	// List of regular expression patterns to match the names of replication slots to be excluded (by default empty)
+	// When the step being executed was started
 	// +optional
-	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+	StepStartedAt *metav1.Time `json:"stepStartedAt,omitempty"`
 
-	synchronizeReplicasCache `json:"-"`
+	// When the import was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the import was terminated
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The detected error, if any
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
-// synchronizeReplicasCache contains the result of the regex compilation
-// +kubebuilder:object:generate:=false
-type synchronizeReplicasCache struct {
-	compiledPatterns []regexp.Regexp `json:"-"`
+// InstanceRewindStatus is the outcome of the latest `pg_rewind` executed
+// by an instance
+type InstanceRewindStatus struct {
+	// Whether `pg_rewind` succeeded
+	Succeeded bool `json:"succeeded"`
 
-	compiled bool `json:"-"`
+	// The time when `pg_rewind` completed
+	// +optional
+	Time string `json:"time,omitempty"`
 
-	compileErrors []error `json:"-"`
+	// The error reported by `pg_rewind`, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Whether `pg_rewind` failed because a WAL file it needs is not
+	// available anymore, neither in `pg_wal` nor in the WAL archive
+	// +optional
+	WALUnavailable bool `json:"walUnavailable,omitempty"`
 }
 
-// DeepCopyInto needs to be manually added for the controller-gen compiler to work correctly, given that it cannot
-// generate the DeepCopyInto for the regexp type.
-// The method is empty because we don't want to transfer the cache when invoking DeepCopyInto
-func (receiver synchronizeReplicasCache) DeepCopyInto(*synchronizeReplicasCache) {}
+// RecoveredAlterSystemSettingsStatus contains the parameters found in the
+// `postgresql.auto.conf` file of a recovered backup
+type RecoveredAlterSystemSettingsStatus struct {
+	// Whether the parameters have been kept in the recovered cluster,
+	// or removed from it
+	// +optional
+	Reapplied bool `json:"reapplied,omitempty"`
 
-func (r *SynchronizeReplicasConfiguration) compileRegex() []error {
-	if r == nil {
+	// The parameters, together with their values
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The parameters whose value differs from the one declared
+	// in `.spec.postgresql.parameters`, including the ones
+	// which are not declared there
+	// +optional
+	DivergingParameters []string `json:"divergingParameters,omitempty"`
+}
+
+// ScheduledMaintenancePhase is the phase of a scheduled maintenance
+type ScheduledMaintenancePhase string
+
+const (
+	// ScheduledMaintenancePhaseRunning means that the maintenance is running
+	ScheduledMaintenancePhaseRunning ScheduledMaintenancePhase = "running"
+
+	// ScheduledMaintenancePhaseSucceeded means that every maintenance
+	// statement has been executed
+	ScheduledMaintenancePhaseSucceeded ScheduledMaintenancePhase = "succeeded"
+
+	// ScheduledMaintenancePhaseFailed means that a maintenance statement failed
+	ScheduledMaintenancePhaseFailed ScheduledMaintenancePhase = "failed"
+)
+
+// ScheduledMaintenanceStatus is the status of a scheduled maintenance
+type ScheduledMaintenanceStatus struct {
+	// The phase of the maintenance
+	// +optional
+	Phase ScheduledMaintenancePhase `json:"phase,omitempty"`
+
+	// The instance where the maintenance has been run
+	// +optional
+	Instance string `json:"instance,omitempty"`
+
+	// When the maintenance was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the maintenance was terminated
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The detected error, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// StatStatementsResetPhase is the phase of a scheduled reset
+// of the `pg_stat_statements` statistics
+type StatStatementsResetPhase string
+
+const (
+	// StatStatementsResetPhaseSucceeded means that the statistics
+	// have been reset
+	StatStatementsResetPhaseSucceeded StatStatementsResetPhase = "succeeded"
+
+	// StatStatementsResetPhaseFailed means that the statistics
+	// couldn't be reset
+	StatStatementsResetPhaseFailed StatStatementsResetPhase = "failed"
+)
+
+// StatStatementsResetStatus is the status of a scheduled reset
+// of the `pg_stat_statements` statistics
+type StatStatementsResetStatus struct {
+	// The phase of the reset
+	// +optional
+	Phase StatStatementsResetPhase `json:"phase,omitempty"`
+
+	// The instance where the statistics have been reset
+	// +optional
+	Instance string `json:"instance,omitempty"`
+
+	// When the reset was executed
+	// +optional
+	ResetAt *metav1.Time `json:"resetAt,omitempty"`
+
+	// The number of statements that were tracked when the
+	// statistics have been reset
+	// +optional
+	Statements int64 `json:"statements,omitempty"`
+
+	// The detected error, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BackupVerificationPhase is the phase of a backup verification
+type BackupVerificationPhase string
+
+const (
+	// BackupVerificationPhaseRunning means that the verification job is running
+	BackupVerificationPhaseRunning BackupVerificationPhase = "running"
+
+	// BackupVerificationPhaseSucceeded means that the backup has been restored
+	// and PostgreSQL started correctly after replaying the WALs
+	BackupVerificationPhaseSucceeded BackupVerificationPhase = "succeeded"
+
+	// BackupVerificationPhaseFailed means that the backup couldn't be restored
+	BackupVerificationPhaseFailed BackupVerificationPhase = "failed"
+)
+
+// BackupVerificationStatus contains the outcome of a backup verification
+type BackupVerificationStatus struct {
+	// The ID of the verified backup
+	// +optional
+	BackupID string `json:"backupId,omitempty"`
+
+	// The phase of the verification
+	// +optional
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// When the verification was started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the verification was terminated
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The detected error, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WalArchiveCheckStatus is the outcome of the last check of the
+// continuity of the WAL archive
+type WalArchiveCheckStatus struct {
+	// The last WAL file that has been found in the archive, with
+	// no gaps between it and the beginning of the oldest base backup.
+	// The next check will start from the following one.
+	// +optional
+	LastCheckedWAL string `json:"lastCheckedWAL,omitempty"`
+
+	// The first WAL file that is missing from the archive, if any
+	// +optional
+	MissingWAL string `json:"missingWAL,omitempty"`
+
+	// When the last check was terminated
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// SwitchReplicaClusterStatus contains all the statuses regarding the switch of a cluster to a replica cluster
+type SwitchReplicaClusterStatus struct {
+	// InProgress indicates if there is an ongoing procedure of switching a cluster to a replica cluster.
+	// +optional
+	InProgress bool `json:"inProgress,omitempty"`
+}
+
+// InstanceReportedState describes the last reported state of an instance during a reconciliation loop
+type InstanceReportedState struct {
+	// indicates if an instance is the primary one
+	IsPrimary bool `json:"isPrimary"`
+	// indicates on which TimelineId the instance is
+	// +optional
+	TimeLineID int `json:"timeLineID,omitempty"`
+}
+
+// RewindFailurePolicy is the action taken when a former primary can't
+// be resynchronized with the new primary through `pg_rewind`
+type RewindFailurePolicy string
+
+const (
+	// RewindFailurePolicyWait keeps retrying `pg_rewind`, waiting for
+	// a manual intervention
+	RewindFailurePolicyWait RewindFailurePolicy = "wait"
+
+	// RewindFailurePolicyReclone recreates the former primary as a new
+	// replica of the current primary
+	RewindFailurePolicyReclone RewindFailurePolicy = "reclone"
+)
+
+// FailoverCooldownConfiguration contains the configuration of the cooldown
+// period following the promotion of a new primary
+type FailoverCooldownConfiguration struct {
+	// The time in seconds, starting from the last promotion of a primary,
+	// during which the operator won't initiate a failover
+	// +kubebuilder:validation:Minimum=1
+	Period int32 `json:"period"`
+
+	// Whether to initiate a failover during the cooldown period anyway,
+	// when the primary hard-fails, that is when its Pod is not running or
+	// its instance manager is not reachable. It is `true` by default:
+	// when `false`, the operator waits for the cooldown period to expire
+	// even if the primary is gone.
+	// +kubebuilder:default:=true
+	// +optional
+	AllowOnHardFailure *bool `json:"allowOnHardFailure,omitempty"`
+}
+
+// DefaultFailoverLSNTolerance is the maximum amount of WAL by which a
+// replica can be behind the most advanced one to be preferred for its
+// failover priority, if not specified
+var DefaultFailoverLSNTolerance = resource.MustParse("16Mi")
+
+// FailoverPriorityConfiguration contains the failover priority of the
+// replicas. During a failover the operator promotes the replica with the
+// highest priority among the ones whose received WAL is within the LSN
+// tolerance from the most advanced replica
+type FailoverPriorityConfiguration struct {
+	// The failover priority of the instances. The instances which are
+	// not listed have priority 0
+	// +kubebuilder:validation:MinItems=1
+	Instances []InstanceFailoverPriority `json:"instances"`
+
+	// The maximum amount of WAL, like `64Mi`, by which a replica can be
+	// behind the most advanced one to be preferred for its priority.
+	// It cannot be greater than 1Gi, and it defaults to 16Mi, the size
+	// of a WAL segment. It is ignored when the synchronous replication
+	// is configured, as only the most advanced replicas are promoted
+	// +optional
+	LSNTolerance *resource.Quantity `json:"lsnTolerance,omitempty"`
+}
+
+// InstanceFailoverPriority is the failover priority of an instance
+type InstanceFailoverPriority struct {
+	// The name of the instance, like `cluster-example-2`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The priority of the instance: higher values are preferred
+	Priority int32 `json:"priority"`
+}
+
+// ReplicaAutoscalingConfiguration contains the configuration of the
+// automatic scaling of the number of instances. The operator adds a
+// replica when the average active client connections of the replicas
+// exceed the target, and removes one, after draining its connections,
+// when they stay below the target for the scale down delay
+type ReplicaAutoscalingConfiguration struct {
+	// The minimum number of instances, including the primary
+	// +kubebuilder:validation:Minimum=1
+	MinInstances int `json:"minInstances"`
+
+	// The maximum number of instances, including the primary
+	// +kubebuilder:validation:Minimum=1
+	MaxInstances int `json:"maxInstances"`
+
+	// The target average number of active client connections per replica
+	// +kubebuilder:validation:Minimum=1
+	TargetActiveConnections int32 `json:"targetActiveConnections"`
+
+	// The time in seconds during which the active connections must require
+	// less instances before a replica is removed. Default is 300
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ScaleDownDelay *int32 `json:"scaleDownDelay,omitempty"`
+
+	// The maximum time in seconds to wait for the active connections of a
+	// replica to complete, after it has been removed from the `-ro`
+	// service, before removing it. Default is 60
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DrainTimeout *int32 `json:"drainTimeout,omitempty"`
+}
+
+// QuarantineConfiguration contains the configuration of the quarantine
+// of the instances that keep crashing
+type QuarantineConfiguration struct {
+	// The number of restarts of the PostgreSQL container, within the
+	// restart window, after which an instance that is still crashing
+	// is quarantined
+	// +kubebuilder:validation:Minimum=1
+	MaxRestarts int32 `json:"maxRestarts"`
+
+	// The time window, in seconds, in which the restarts of the
+	// PostgreSQL container are counted. Default: 3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestartWindow int32 `json:"restartWindow,omitempty"`
+}
+
+// GetRestartWindow gets the time window in which the
+// restarts of the PostgreSQL container are counted
+func (configuration *QuarantineConfiguration) GetRestartWindow() time.Duration {
+	if configuration.RestartWindow > 0 {
+		return time.Duration(configuration.RestartWindow) * time.Second
+	}
+
+	return DefaultQuarantineRestartWindow * time.Second
+}
+
+// ClusterFinalizerName is the name of the finalizer used to apply
+// the deletion policy of the cluster
+const ClusterFinalizerName = utils.MetadataNamespace + "/deletionPolicy"
+
+// ClusterReclaimPolicy describes what happens to the resources
+// of a cluster when it is deleted
+type ClusterReclaimPolicy string
+
+const (
+	// ClusterReclaimRetain means the resources are kept after the
+	// deletion of the cluster, for a manual recovery or removal
+	ClusterReclaimRetain ClusterReclaimPolicy = "retain"
+
+	// ClusterReclaimDelete means the resources are deleted
+	// together with the cluster
+	ClusterReclaimDelete ClusterReclaimPolicy = "delete"
+)
+
+// DeletionPolicyConfiguration contains the policy applied when
+// the cluster is deleted
+type DeletionPolicyConfiguration struct {
+	// What happens to the PVCs of the instances when the cluster is
+	// deleted. With `retain` (default), they are kept, together with the
+	// data they contain. With `delete`, they are deleted with the cluster
+	// +kubebuilder:validation:Enum=retain;delete
+	// +kubebuilder:default:=retain
+	// +optional
+	PVCs ClusterReclaimPolicy `json:"pvcs,omitempty"`
+
+	// What happens to the Backup objects of the cluster, and to their
+	// volume snapshots, when the cluster is deleted. With `retain`
+	// (default), they are kept. With `delete`, they are deleted with the
+	// cluster. The backups stored in the object store are never deleted
+	// +kubebuilder:validation:Enum=retain;delete
+	// +kubebuilder:default:=retain
+	// +optional
+	Backups ClusterReclaimPolicy `json:"backups,omitempty"`
+
+	// When true, the deletion of the cluster is rejected unless the
+	// `cnpg.io/deletionConfirmation` annotation is set to the name
+	// of the cluster
+	// +optional
+	RequireConfirmation bool `json:"requireConfirmation,omitempty"`
+}
+
+// GetPVCsReclaimPolicy gets what happens to the PVCs when the
+// cluster is deleted, defaulting to ClusterReclaimRetain
+func (configuration *DeletionPolicyConfiguration) GetPVCsReclaimPolicy() ClusterReclaimPolicy {
+	if configuration == nil || configuration.PVCs == "" {
+		return ClusterReclaimRetain
+	}
+
+	return configuration.PVCs
+}
+
+// GetBackupsReclaimPolicy gets what happens to the backups when the
+// cluster is deleted, defaulting to ClusterReclaimRetain
+func (configuration *DeletionPolicyConfiguration) GetBackupsReclaimPolicy() ClusterReclaimPolicy {
+	if configuration == nil || configuration.Backups == "" {
+		return ClusterReclaimRetain
+	}
+
+	return configuration.Backups
+}
+
+// IsConfirmationRequired returns true if the deletion of the cluster
+// must be confirmed through the deletionConfirmation annotation
+func (configuration *DeletionPolicyConfiguration) IsConfirmationRequired() bool {
+	return configuration != nil && configuration.RequireConfirmation
+}
+
+// IsDeletionConfirmed returns true if the deletion of the cluster doesn't
+// need to be confirmed, or if it has been confirmed by setting the
+// deletionConfirmation annotation to the name of the cluster
+func (cluster *Cluster) IsDeletionConfirmed() bool {
+	if !cluster.Spec.DeletionPolicy.IsConfirmationRequired() {
+		return true
+	}
+
+	return cluster.Annotations[utils.DeletionConfirmationAnnotationName] == cluster.Name
+}
+
+// ScheduledMaintenanceConfiguration contains the configuration of the
+// scheduled maintenance of the databases
+type ScheduledMaintenanceConfiguration struct {
+	// Enabled tells the primary instance to periodically run the
+	// maintenance statements in the databases
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The schedule does not follow the same format used in Kubernetes CronJobs
+	// as it includes an additional seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format.
+	// Defaults to once a week, on Sunday at 2 AM
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// The databases where the maintenance is run. Defaults to every
+	// database accepting connections, except the template ones
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// The SQL statements executed, in order, in every database, outside
+	// a transaction block. Defaults to `REINDEX DATABASE CONCURRENTLY`,
+	// together with the name of the database
+	// +optional
+	SQL []string `json:"sql,omitempty"`
+}
+
+// IsEnabled returns true if the scheduled maintenance has been requested
+func (configuration *ScheduledMaintenanceConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.Enabled
+}
+
+// GetSchedule gets the schedule of the maintenance, defaulting
+// to DefaultScheduledMaintenanceSchedule
+func (configuration *ScheduledMaintenanceConfiguration) GetSchedule() string {
+	if configuration == nil || configuration.Schedule == "" {
+		return DefaultScheduledMaintenanceSchedule
+	}
+
+	return configuration.Schedule
+}
+
+// ProbesConfiguration contains the configuration of the probes
+// of the PostgreSQL container
+type ProbesConfiguration struct {
+	// The configuration of the startup probe
+	// +optional
+	Startup *ProbeConfiguration `json:"startup,omitempty"`
+}
+
+// ProbeConfiguration contains the parameters of a probe
+type ProbeConfiguration struct {
+	// How often, in seconds, the probe is performed (default 10)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// The number of consecutive failures after which the probe is considered
+	// failed. Defaults to the value derived from `startDelay`, that is
+	// ceiling(startDelay / periodSeconds)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// The number of seconds after which the probe times out (default 5)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// GetStartup gets the configuration of the startup probe, if any
+func (configuration *ProbesConfiguration) GetStartup() *ProbeConfiguration {
+	if configuration == nil {
+		return nil
+	}
+
+	return configuration.Startup
+}
+
+// ReplicaReadinessConfiguration contains the configuration of the
+// readiness of the replicas based on their replication lag
+type ReplicaReadinessConfiguration struct {
+	// The maximum replication lag, in seconds, of a ready replica. The lag
+	// is the time elapsed since the last transaction that has been replayed,
+	// and it's zero when the replica has replayed all the WAL it received
+	// +kubebuilder:validation:Minimum=1
+	MaximumLag int32 `json:"maximumLag"`
+}
+
+// GetAllowOnHardFailure tells whether a failover can be initiated during
+// the cooldown period when the primary hard-fails
+func (configuration *FailoverCooldownConfiguration) GetAllowOnHardFailure() bool {
+	if configuration == nil || configuration.AllowOnHardFailure == nil {
+		return true
+	}
+
+	return *configuration.AllowOnHardFailure
+}
+
+// GetPriority gets the failover priority of the instance with the
+// passed name
+func (configuration *FailoverPriorityConfiguration) GetPriority(instanceName string) int32 {
+	if configuration == nil {
+		return 0
+	}
+
+	for _, instance := range configuration.Instances {
+		if instance.Name == instanceName {
+			return instance.Priority
+		}
+	}
+
+	return 0
+}
+
+// GetLSNTolerance gets the maximum amount of WAL, in bytes, by which a
+// replica can be behind the most advanced one to be preferred for its
+// failover priority
+func (configuration *FailoverPriorityConfiguration) GetLSNTolerance() int64 {
+	if configuration == nil || configuration.LSNTolerance == nil {
+		return DefaultFailoverLSNTolerance.Value()
+	}
+
+	return configuration.LSNTolerance.Value()
+}
+
+const (
+	// DefaultReplicaAutoscalingScaleDownDelay is the time during which the
+	// active connections must require less instances before a replica is
+	// removed, if not specified
+	DefaultReplicaAutoscalingScaleDownDelay = 300 * time.Second
+
+	// DefaultReplicaAutoscalingDrainTimeout is the maximum time to wait for
+	// the active connections of a replica to complete before removing it,
+	// if not specified
+	DefaultReplicaAutoscalingDrainTimeout = 60 * time.Second
+)
+
+// GetScaleDownDelay gets the time during which the active connections
+// must require less instances before a replica is removed
+func (configuration *ReplicaAutoscalingConfiguration) GetScaleDownDelay() time.Duration {
+	if configuration == nil || configuration.ScaleDownDelay == nil {
+		return DefaultReplicaAutoscalingScaleDownDelay
+	}
+
+	return time.Duration(*configuration.ScaleDownDelay) * time.Second
+}
+
+// GetDrainTimeout gets the maximum time to wait for the active
+// connections of a replica to complete before removing it
+func (configuration *ReplicaAutoscalingConfiguration) GetDrainTimeout() time.Duration {
+	if configuration == nil || configuration.DrainTimeout == nil {
+		return DefaultReplicaAutoscalingDrainTimeout
+	}
+
+	return time.Duration(*configuration.DrainTimeout) * time.Second
+}
+
+// GetDesiredInstances gets the number of instances chosen by
+// the operator, or zero if it didn't choose any yet
+func (status *ReplicaAutoscalingStatus) GetDesiredInstances() int {
+	if status == nil {
+		return 0
+	}
+
+	return status.DesiredInstances
+}
+
+// GetDrainingInstance gets the name of the replica whose connections
+// are being drained before removing it, if any
+func (status *ReplicaAutoscalingStatus) GetDrainingInstance() string {
+	if status == nil {
+		return ""
+	}
+
+	return status.DrainingInstance
+}
+
+// IsInProgress checks whether the switchover has been requested
+// and is not terminated yet
+func (status *SwitchoverStatus) IsInProgress() bool {
+	if status == nil {
+		return false
+	}
+
+	switch status.Phase {
+	case SwitchoverPhaseCompleted, SwitchoverPhaseCancelled, SwitchoverPhaseAborted, "":
+		return false
+	default:
+		return true
+	}
+}
+
+// ClusterConditionType defines types of cluster conditions
+type ClusterConditionType string
+
+// These are valid conditions of a Cluster, some of the conditions could be owned by
+// Instance Manager and some of them could be owned by reconciler.
+const (
+	// ConditionContinuousArchiving represents whether WAL archiving is working
+	ConditionContinuousArchiving ClusterConditionType = "ContinuousArchiving"
+	// ConditionBackup represents the last backup's status
+	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
+	// ConditionClusterReady represents whether a cluster is Ready
+	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionBackupVerification represents the last backup verification's status
+	ConditionBackupVerification ClusterConditionType = "LastBackupVerificationSucceeded"
+	// ConditionSwitchover represents the last requested switchover's status
+	ConditionSwitchover ClusterConditionType = "LastSwitchoverSucceeded"
+	// ConditionRollingUpdate represents the progress of the rolling update
+	// of the instances
+	ConditionRollingUpdate ClusterConditionType = "RollingUpdateCompleted"
+	// ConditionWALArchiveContiguous represents whether the WAL archive
+	// contains every WAL file needed to recover from the oldest base backup
+	ConditionWALArchiveContiguous ClusterConditionType = "WALArchiveContiguous"
+	// ConditionTargetRPO represents whether the archive lag of the
+	// primary is within the target recovery point objective
+	ConditionTargetRPO ClusterConditionType = "TargetRPOMet"
+	// ConditionWALArchiveBacklog represents whether the number of WAL
+	// files waiting to be archived by the primary is within the threshold
+	ConditionWALArchiveBacklog ClusterConditionType = "WALArchiveBacklogWithinThreshold"
+	// ConditionTransactionIDAge represents whether the age of the oldest
+	// unfrozen transaction or multixact ID of the databases is within the
+	// thresholds protecting from the wraparound
+	ConditionTransactionIDAge ClusterConditionType = "TransactionIDAgeWithinThreshold"
+	// ConditionSlowQueryLogging represents whether every instance is
+	// running with the slow query logging settings of the cluster
+	ConditionSlowQueryLogging ClusterConditionType = "SlowQueryLoggingApplied"
+	// ConditionSyncReplication represents whether the primary is requiring
+	// `minSyncReplicas` synchronous standbys, or if the synchronous
+	// replication has been relaxed because of the standbys not being ready
+	ConditionSyncReplication ClusterConditionType = "SynchronousReplicationEnforced"
+	// ConditionInstanceReplacement represents the progress of the last
+	// requested replacement of an instance
+	ConditionInstanceReplacement ClusterConditionType = "LastInstanceReplacementSucceeded"
+	// ConditionDiskTimeToFull represents whether the volumes of every
+	// instance are projected to be full later than the threshold, given
+	// their growth rate
+	ConditionDiskTimeToFull ClusterConditionType = "DiskTimeToFullAboveThreshold"
+)
+
+// A Condition that can be used to communicate the Backup progress
+var (
+	// BackupSucceededCondition is added to a backup
+	// when it was completed correctly
+	BackupSucceededCondition = &metav1.Condition{
+		Type:    string(ConditionBackup),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonLastBackupSucceeded),
+		Message: "Backup was successful",
+	}
+
+	// BackupStartingCondition is added to a backup
+	// when it started
+	BackupStartingCondition = &metav1.Condition{
+		Type:    string(ConditionBackup),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(ConditionBackupStarted),
+		Message: "New Backup starting up",
+	}
+
+	// BuildClusterBackupFailedCondition builds
+	// ConditionReasonLastBackupFailed condition
+	BuildClusterBackupFailedCondition = func(err error) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionBackup),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonLastBackupFailed),
+			Message: err.Error(),
+		}
+	}
+
+	// BackupVerificationSucceededCondition is added to a cluster
+	// when the latest base backup has been verified correctly
+	BackupVerificationSucceededCondition = &metav1.Condition{
+		Type:    string(ConditionBackupVerification),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonBackupVerificationSucceeded),
+		Message: "Backup verification was successful",
+	}
+
+	// BuildBackupVerificationFailedCondition builds
+	// ConditionReasonBackupVerificationFailed condition
+	BuildBackupVerificationFailedCondition = func(err error) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionBackupVerification),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonBackupVerificationFailed),
+			Message: err.Error(),
+		}
+	}
+
+	// BuildSwitchoverStartedCondition builds
+	// ConditionReasonSwitchoverStarted condition
+	BuildSwitchoverStartedCondition = func(targetPrimary string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionSwitchover),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonSwitchoverStarted),
+			Message: fmt.Sprintf("Switching over to %s", targetPrimary),
+		}
+	}
+
+	// BuildSwitchoverSucceededCondition builds
+	// ConditionReasonSwitchoverSucceeded condition
+	BuildSwitchoverSucceededCondition = func(currentPrimary string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionSwitchover),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonSwitchoverSucceeded),
+			Message: fmt.Sprintf("Switched over to %s", currentPrimary),
+		}
+	}
+
+	// BuildSwitchoverAbortedCondition builds
+	// ConditionReasonSwitchoverAborted condition
+	BuildSwitchoverAbortedCondition = func(err error) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionSwitchover),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonSwitchoverAborted),
+			Message: err.Error(),
+		}
+	}
+
+	// BuildInstanceReplacementInProgressCondition builds
+	// ConditionReasonInstanceReplacementInProgress condition
+	// reporting the current step of the replacement
+	BuildInstanceReplacementInProgressCondition = func(message string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionInstanceReplacement),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonInstanceReplacementInProgress),
+			Message: message,
+		}
+	}
+
+	// BuildInstanceReplacementSucceededCondition builds
+	// ConditionReasonInstanceReplacementSucceeded condition
+	BuildInstanceReplacementSucceededCondition = func(instanceName string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionInstanceReplacement),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonInstanceReplacementSucceeded),
+			Message: fmt.Sprintf("Instance %s has been replaced", instanceName),
+		}
+	}
+
+	// BuildInstanceReplacementAbortedCondition builds
+	// ConditionReasonInstanceReplacementAborted condition
+	BuildInstanceReplacementAbortedCondition = func(err error) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionInstanceReplacement),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonInstanceReplacementAborted),
+			Message: err.Error(),
+		}
+	}
+
+	// BuildRollingUpdateInProgressCondition builds the ConditionRollingUpdate
+	// condition reporting the current step of the rolling update
+	BuildRollingUpdateInProgressCondition = func(reason ConditionReason, message string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionRollingUpdate),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(reason),
+			Message: message,
+		}
+	}
+
+	// RollingUpdateCompletedCondition is added to a cluster
+	// when every instance has been updated
+	RollingUpdateCompletedCondition = &metav1.Condition{
+		Type:    string(ConditionRollingUpdate),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonRollingUpdateCompleted),
+		Message: "Every instance has been updated",
+	}
+
+	// WALArchiveContiguousCondition is added to a cluster
+	// when no gaps have been found in the WAL archive
+	WALArchiveContiguousCondition = &metav1.Condition{
+		Type:    string(ConditionWALArchiveContiguous),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(ConditionReasonWALArchiveContiguous),
+		Message: "No gaps found in the WAL archive",
+	}
+
+	// BuildWALArchiveGapDetectedCondition builds
+	// ConditionReasonWALArchiveGapDetected condition
+	BuildWALArchiveGapDetectedCondition = func(missingWAL string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionWALArchiveContiguous),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonWALArchiveGapDetected),
+			Message: fmt.Sprintf("WAL file %s is missing from the WAL archive", missingWAL),
+		}
+	}
+
+	// BuildTargetRPOMetCondition builds the ConditionTargetRPO condition
+	// for an archive lag within the target recovery point objective
+	BuildTargetRPOMetCondition = func(targetRPO time.Duration) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionTargetRPO),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonTargetRPOMet),
+			Message: fmt.Sprintf("The archive lag is within the target RPO of %v", targetRPO),
+		}
+	}
+
+	// BuildTargetRPOExceededCondition builds the ConditionTargetRPO condition
+	// for an archive lag exceeding the target recovery point objective
+	BuildTargetRPOExceededCondition = func(targetRPO time.Duration) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionTargetRPO),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonTargetRPOExceeded),
+			Message: fmt.Sprintf("The archive lag exceeds the target RPO of %v", targetRPO),
+		}
+	}
+
+	// BuildWALArchiveBacklogWithinThresholdCondition builds the
+	// ConditionWALArchiveBacklog condition for a backlog within the threshold
+	BuildWALArchiveBacklogWithinThresholdCondition = func(readyWALFiles int, threshold int32) *metav1.Condition {
+		return &metav1.Condition{
+			Type:   string(ConditionWALArchiveBacklog),
+			Status: metav1.ConditionTrue,
+			Reason: string(ConditionReasonWALArchiveBacklogWithinThreshold),
+			Message: fmt.Sprintf("%d WAL files are waiting to be archived, the threshold is %d",
+				readyWALFiles, threshold),
+		}
+	}
+
+	// BuildWALArchiveBacklogExceededCondition builds the
+	// ConditionWALArchiveBacklog condition for a backlog exceeding the threshold
+	BuildWALArchiveBacklogExceededCondition = func(
+		readyWALFiles int,
+		threshold int32,
+		action WalArchiveBacklogAction,
+	) *metav1.Condition {
+		message := fmt.Sprintf("%d WAL files are waiting to be archived, exceeding the threshold of %d",
+			readyWALFiles, threshold)
+		if action == WalArchiveBacklogActionThrottle {
+			message += ", the write transactions on the primary are being throttled"
+		}
+		return &metav1.Condition{
+			Type:    string(ConditionWALArchiveBacklog),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(ConditionReasonWALArchiveBacklogExceeded),
+			Message: message,
+		}
+	}
+
+	// BuildTransactionIDAgeWithinThresholdCondition builds the
+	// ConditionTransactionIDAge condition for an age within the thresholds
+	BuildTransactionIDAgeWithinThresholdCondition = func(age int64, warningAge int64) *metav1.Condition {
+		return &metav1.Condition{
+			Type:   string(ConditionTransactionIDAge),
+			Status: metav1.ConditionTrue,
+			Reason: string(ConditionReasonTransactionIDAgeWithinThreshold),
+			Message: fmt.Sprintf("The oldest unfrozen transaction or multixact ID has age %d, "+
+				"the warning threshold is %d",
+				age, warningAge),
+		}
+	}
+
+	// BuildTransactionIDAgeExceededCondition builds the
+	// ConditionTransactionIDAge condition for an age exceeding one
+	// of the thresholds
+	BuildTransactionIDAgeExceededCondition = func(
+		reason ConditionReason,
+		database string,
+		age int64,
+		threshold int64,
+	) *metav1.Condition {
+		return &metav1.Condition{
+			Type:   string(ConditionTransactionIDAge),
+			Status: metav1.ConditionFalse,
+			Reason: string(reason),
+			Message: fmt.Sprintf("The oldest unfrozen transaction or multixact ID of database %q has age %d, "+
+				"exceeding the threshold of %d: run VACUUM FREEZE on it to prevent the wraparound",
+				database, age, threshold),
+		}
+	}
+
+	// BuildSlowQueryLoggingAppliedCondition builds the
+	// ConditionSlowQueryLogging condition for a cluster where every
+	// instance is running with the slow query logging settings
+	BuildSlowQueryLoggingAppliedCondition = func() *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionSlowQueryLogging),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonSlowQueryLoggingApplied),
+			Message: "The slow query logging settings are in effect on every instance",
+		}
+	}
+
+	// BuildSlowQueryLoggingPendingCondition builds the
+	// ConditionSlowQueryLogging condition for a cluster where some
+	// instances are not running with the slow query logging settings yet
+	BuildSlowQueryLoggingPendingCondition = func(instances []string) *metav1.Condition {
+		return &metav1.Condition{
+			Type:   string(ConditionSlowQueryLogging),
+			Status: metav1.ConditionFalse,
+			Reason: string(ConditionReasonSlowQueryLoggingPending),
+			Message: fmt.Sprintf("The slow query logging settings are not in effect yet on: %s",
+				strings.Join(instances, ", ")),
+		}
+	}
+
+	// BuildDiskTimeToFullAboveThresholdCondition builds the
+	// ConditionDiskTimeToFull condition for volumes that are not
+	// projected to be full within the threshold
+	BuildDiskTimeToFullAboveThresholdCondition = func(threshold time.Duration) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionDiskTimeToFull),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonDiskTimeToFullAboveThreshold),
+			Message: fmt.Sprintf("No volume is projected to be full within %v", threshold),
+		}
+	}
+
+	// BuildDiskTimeToFullBelowThresholdCondition builds the
+	// ConditionDiskTimeToFull condition for a volume projected
+	// to be full within the threshold
+	BuildDiskTimeToFullBelowThresholdCondition = func(
+		instance string,
+		volume string,
+		timeToFull time.Duration,
+		threshold time.Duration,
+	) *metav1.Condition {
+		return &metav1.Condition{
+			Type:   string(ConditionDiskTimeToFull),
+			Status: metav1.ConditionFalse,
+			Reason: string(ConditionReasonDiskTimeToFullBelowThreshold),
+			Message: fmt.Sprintf("The %s volume of instance %s is projected to be full in %v, "+
+				"below the threshold of %v: consider enlarging it",
+				volume, instance, timeToFull, threshold),
+		}
+	}
+
+	// BuildSyncReplicationEnforcedCondition builds the
+	// ConditionSyncReplication condition for a primary requiring
+	// `minSyncReplicas` synchronous standbys
+	BuildSyncReplicationEnforcedCondition = func(syncReplicas int) *metav1.Condition {
+		return &metav1.Condition{
+			Type:    string(ConditionSyncReplication),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(ConditionReasonSyncReplicationEnforced),
+			Message: fmt.Sprintf("The primary is requiring %d synchronous standbys", syncReplicas),
+		}
+	}
+
+	// BuildSyncReplicationRelaxedCondition builds the
+	// ConditionSyncReplication condition for a primary whose
+	// synchronous replication has been relaxed
+	BuildSyncReplicationRelaxedCondition = func(syncReplicas int, minSyncReplicas int) *metav1.Condition {
+		return &metav1.Condition{
+			Type:   string(ConditionSyncReplication),
+			Status: metav1.ConditionFalse,
+			Reason: string(ConditionReasonSyncReplicationRelaxed),
+			Message: fmt.Sprintf("The primary is requiring %d synchronous standbys instead of %d, "+
+				"as not enough standbys are ready: the committed transactions may be lost on failover",
+				syncReplicas, minSyncReplicas),
+		}
+	}
+)
+
+// ConditionStatus defines conditions of resources
+type ConditionStatus string
+
+// These are valid condition statuses. "ConditionTrue" means a resource is in the condition;
+// "ConditionFalse" means a resource is not in the condition; "ConditionUnknown" means kubernetes
+// can't decide if a resource is in the condition or not. In the future, we could add other
+// intermediate conditions, e.g. ConditionDegraded
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// ConditionReason defines the reason why a certain
+// condition changed
+type ConditionReason string
+
+const (
+	// ConditionBackupStarted means that the condition changed because the debug
+	// started
+	ConditionBackupStarted ConditionReason = "BackupStarted"
+
+	// ConditionReasonLastBackupSucceeded means that the condition changed because the last backup
+	// has been taken successfully
+	ConditionReasonLastBackupSucceeded ConditionReason = "LastBackupSucceeded"
+
+	// ConditionReasonLastBackupFailed means that the condition changed because the last backup
+	// failed
+	ConditionReasonLastBackupFailed ConditionReason = "LastBackupFailed"
+
+	// ConditionReasonContinuousArchivingSuccess means that the condition changed because the
+	// WAL archiving was working correctly
+	ConditionReasonContinuousArchivingSuccess ConditionReason = "ContinuousArchivingSuccess"
+
+	// ConditionReasonContinuousArchivingFailing means that the condition has changed because
+	// the WAL archiving is not working correctly
+	ConditionReasonContinuousArchivingFailing ConditionReason = "ContinuousArchivingFailing"
+
+	// ConditionReasonContinuousArchivingDegraded means that the condition has changed because
+	// the WAL archiving is working, but is failing on some of the WAL destinations
+	ConditionReasonContinuousArchivingDegraded ConditionReason = "ContinuousArchivingDegraded"
+
+	// ClusterReady means that the condition changed because the cluster is ready and working properly
+	ClusterReady ConditionReason = "ClusterIsReady"
+
+	// ClusterIsNotReady means that the condition changed because the cluster is not ready
+	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+
+	// DetachedVolume is the reason that is set when we do a rolling upgrade to add a PVC volume to a cluster
+	DetachedVolume ConditionReason = "DetachedVolume"
+
+	// ConditionReasonBackupVerificationSucceeded means that the latest base backup
+	// has been restored and PostgreSQL started correctly
+	ConditionReasonBackupVerificationSucceeded ConditionReason = "BackupVerificationSucceeded"
+
+	// ConditionReasonBackupVerificationFailed means that the latest base backup
+	// couldn't be restored
+	ConditionReasonBackupVerificationFailed ConditionReason = "BackupVerificationFailed"
+
+	// ConditionReasonSwitchoverStarted means that the requested switchover
+	// has been started
+	ConditionReasonSwitchoverStarted ConditionReason = "SwitchoverStarted"
+
+	// ConditionReasonSwitchoverSucceeded means that the requested switchover
+	// has been completed and the target instance is the new primary
+	ConditionReasonSwitchoverSucceeded ConditionReason = "SwitchoverSucceeded"
+
+	// ConditionReasonSwitchoverAborted means that the requested switchover
+	// has been rejected or aborted, and the primary didn't change
+	ConditionReasonSwitchoverAborted ConditionReason = "SwitchoverAborted"
+
+	// ConditionReasonInstanceReplacementInProgress means that the requested
+	// replacement of an instance has been started and is not completed yet
+	ConditionReasonInstanceReplacementInProgress ConditionReason = "InstanceReplacementInProgress"
+
+	// ConditionReasonInstanceReplacementSucceeded means that the replaced
+	// instance has been deleted and the new one is ready
+	ConditionReasonInstanceReplacementSucceeded ConditionReason = "InstanceReplacementSucceeded"
+
+	// ConditionReasonInstanceReplacementAborted means that the requested
+	// replacement of an instance has been rejected or withdrawn
+	ConditionReasonInstanceReplacementAborted ConditionReason = "InstanceReplacementAborted"
+
+	// ConditionReasonRollingUpdateReplicas means that the rolling update
+	// is restarting or recreating the replicas
+	ConditionReasonRollingUpdateReplicas ConditionReason = "UpdatingReplicas"
+
+	// ConditionReasonRollingUpdateSwitchover means that the replicas have
+	// been updated and the primary is being switched over to one of them
+	ConditionReasonRollingUpdateSwitchover ConditionReason = "SwitchingOverPrimary"
+
+	// ConditionReasonRollingUpdatePrimary means that the primary instance
+	// is being restarted or recreated without a switchover
+	ConditionReasonRollingUpdatePrimary ConditionReason = "UpdatingPrimary"
+
+	// ConditionReasonRollingUpdateWaitingForUser means that the replicas
+	// have been updated and the user must issue a switchover to update the
+	// primary, as the primary update strategy is supervised
+	ConditionReasonRollingUpdateWaitingForUser ConditionReason = "WaitingForUser"
+
+	// ConditionReasonRollingUpdateCompleted means that every instance
+	// has been updated
+	ConditionReasonRollingUpdateCompleted ConditionReason = "RollingUpdateCompleted"
+
+	// ConditionReasonWALArchiveContiguous means that the WAL archive
+	// contains every WAL file since the oldest base backup
+	ConditionReasonWALArchiveContiguous ConditionReason = "WALArchiveContiguous"
+
+	// ConditionReasonWALArchiveGapDetected means that at least a WAL file
+	// is missing from the WAL archive, and point-in-time recovery
+	// won't work beyond it
+	ConditionReasonWALArchiveGapDetected ConditionReason = "WALArchiveGapDetected"
+
+	// ConditionReasonTargetRPOMet means that the archive lag of the
+	// primary is within the target recovery point objective
+	ConditionReasonTargetRPOMet ConditionReason = "TargetRPOMet"
+
+	// ConditionReasonTargetRPOExceeded means that the primary has WAL
+	// files waiting to be archived for longer than the target recovery
+	// point objective
+	ConditionReasonTargetRPOExceeded ConditionReason = "TargetRPOExceeded"
+
+	// ConditionReasonWALArchiveBacklogWithinThreshold means that the number
+	// of WAL files waiting to be archived by the primary is within the threshold
+	ConditionReasonWALArchiveBacklogWithinThreshold ConditionReason = "WALArchiveBacklogWithinThreshold"
+
+	// ConditionReasonWALArchiveBacklogExceeded means that the primary has
+	// more WAL files waiting to be archived than the threshold
+	ConditionReasonWALArchiveBacklogExceeded ConditionReason = "WALArchiveBacklogExceeded"
+
+	// ConditionReasonTransactionIDAgeWithinThreshold means that the age of
+	// the oldest unfrozen transaction or multixact ID is below the warning
+	// threshold
+	ConditionReasonTransactionIDAgeWithinThreshold ConditionReason = "TransactionIDAgeWithinThreshold"
+
+	// ConditionReasonTransactionIDAgeWarning means that the age of the
+	// oldest unfrozen transaction or multixact ID exceeds the warning
+	// threshold
+	ConditionReasonTransactionIDAgeWarning ConditionReason = "TransactionIDAgeWarning"
+
+	// ConditionReasonTransactionIDAgeCritical means that the age of the
+	// oldest unfrozen transaction or multixact ID exceeds the critical
+	// threshold, and the databases are approaching the wraparound
+	ConditionReasonTransactionIDAgeCritical ConditionReason = "TransactionIDAgeCritical"
+
+	// ConditionReasonSlowQueryLoggingApplied means that every instance
+	// is running with the slow query logging settings of the cluster
+	ConditionReasonSlowQueryLoggingApplied ConditionReason = "SlowQueryLoggingApplied"
+
+	// ConditionReasonSlowQueryLoggingPending means that some instances
+	// didn't reload their configuration with the slow query logging
+	// settings yet
+	ConditionReasonSlowQueryLoggingPending ConditionReason = "SlowQueryLoggingPending"
+
+	// ConditionReasonSyncReplicationEnforced means that the primary is
+	// requiring `minSyncReplicas` synchronous standbys
+	ConditionReasonSyncReplicationEnforced ConditionReason = "SynchronousReplicationEnforced"
+
+	// ConditionReasonSyncReplicationRelaxed means that the number of
+	// synchronous standbys has been lowered below `minSyncReplicas`
+	// because of the standbys not being ready
+	ConditionReasonSyncReplicationRelaxed ConditionReason = "SynchronousReplicationRelaxed"
+
+	// ConditionReasonDiskTimeToFullAboveThreshold means that no volume
+	// is projected to be full within the threshold
+	ConditionReasonDiskTimeToFullAboveThreshold ConditionReason = "DiskTimeToFullAboveThreshold"
+
+	// ConditionReasonDiskTimeToFullBelowThreshold means that at least one
+	// volume is projected to be full within the threshold, given its
+	// growth rate
+	ConditionReasonDiskTimeToFullBelowThreshold ConditionReason = "DiskTimeToFullBelowThreshold"
+)
+
+// EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
+type EmbeddedObjectMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PoolerIntegrations encapsulates the needed integration for the poolers referencing the cluster
+type PoolerIntegrations struct {
+	// +optional
+	PgBouncerIntegration PgBouncerIntegrationStatus `json:"pgBouncerIntegration,omitempty"`
+}
+
+// PgBouncerIntegrationStatus encapsulates the needed integration for the pgbouncer poolers referencing the cluster
+type PgBouncerIntegrationStatus struct {
+	// +optional
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+// ReplicaClusterConfiguration encapsulates the configuration of a replica
+// cluster
+type ReplicaClusterConfiguration struct {
+	// Self defines the name of this cluster. It is used to determine if this is a primary
+	// or a replica cluster, comparing it with `primary`
+	Self string `json:"self,omitempty"`
+
+	// Primary defines which Cluster is defined to be the primary in the distributed PostgreSQL cluster, based on the
+	// topology specified in externalClusters
+	Primary string `json:"primary,omitempty"`
+
+	// The name of the external cluster which is the replication origin
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// If replica mode is enabled, this cluster will be a replica of an
+	// existing cluster. Replica cluster can be created from a recovery
+	// object store or via streaming through pg_basebackup.
+	// Refer to the Replica clusters page of the documentation for more information.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// A demotion token generated by an external cluster used to
+	// check if the promotion requirements are met.
+	PromotionToken string `json:"promotionToken,omitempty"`
+}
+
+// DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
+const DefaultReplicationSlotsUpdateInterval = 30
+
+// DefaultReplicationSlotsHASlotPrefix is the default prefix for names of replication slots used for HA.
+const DefaultReplicationSlotsHASlotPrefix = "_cnpg_"
+
+// SynchronizeReplicasConfiguration contains the configuration for the synchronization of user defined
+// physical replication slots
+type SynchronizeReplicasConfiguration struct {
+	// When set to true, every replication slot that is on the primary is synchronized on each standby
+	// +kubebuilder:default:=true
+	Enabled *bool `json:"enabled"`
+
+	// List of regular expression patterns to match the names of replication slots to be excluded (by default empty)
+	// +optional
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+
+	synchronizeReplicasCache `json:"-"`
+}
+
+// synchronizeReplicasCache contains the result of the regex compilation
+// +kubebuilder:object:generate:=false
+type synchronizeReplicasCache struct {
+	compiledPatterns []regexp.Regexp `json:"-"`
+
+	compiled bool `json:"-"`
+
+	compileErrors []error `json:"-"`
+}
+
+// DeepCopyInto needs to be manually added for the controller-gen compiler to work correctly, given that it cannot
+// generate the DeepCopyInto for the regexp type.
+// The method is empty because we don't want to transfer the cache when invoking DeepCopyInto
+func (receiver synchronizeReplicasCache) DeepCopyInto(*synchronizeReplicasCache) {}
+
+func (r *SynchronizeReplicasConfiguration) compileRegex() []error {
+	if r == nil {
 		return nil
 	}
 	if r.compiled {
@@ -1215,6 +2716,93 @@ type ReplicationSlotsConfiguration struct {
 	// Configures the synchronization of the user defined physical replication slots
 	// +optional
 	SynchronizeReplicas *SynchronizeReplicasConfiguration `json:"synchronizeReplicas,omitempty"`
+
+	// The logical replication slots managed by the operator, to be used by
+	// the subscribers outside the cluster. From PostgreSQL 17, they are
+	// synchronized on the standbys to survive failovers and switchovers
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Logical []LogicalReplicationSlotConfiguration `json:"logical,omitempty"`
+}
+
+// LogicalReplicationSlotConfiguration is the configuration of a logical
+// replication slot managed by the operator
+type LogicalReplicationSlotConfiguration struct {
+	// The name of the replication slot. It may only contain lower case
+	// letters, numbers, and the underscore character
+	// +kubebuilder:validation:Pattern=^[0-9a-z_]+$
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The database the replication slot is created in
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+
+	// The output plugin used to decode the changes. Defaults to `pgoutput`,
+	// the plugin used by the PostgreSQL logical replication
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+
+	// Ensure the replication slot is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// DefaultLogicalReplicationSlotPlugin is the output plugin of the managed
+// logical replication slots, if not specified
+const DefaultLogicalReplicationSlotPlugin = "pgoutput"
+
+// GetPlugin gets the output plugin of the logical replication slot
+func (slot LogicalReplicationSlotConfiguration) GetPlugin() string {
+	if slot.Plugin == "" {
+		return DefaultLogicalReplicationSlotPlugin
+	}
+	return slot.Plugin
+}
+
+// GetEnsure gets whether the logical replication slot should be present or absent
+func (slot LogicalReplicationSlotConfiguration) GetEnsure() EnsureOption {
+	if slot.Ensure == "" {
+		return EnsurePresent
+	}
+	return slot.Ensure
+}
+
+// GetLogicalSlots gets the configuration of the managed logical replication slots
+func (r *ReplicationSlotsConfiguration) GetLogicalSlots() []LogicalReplicationSlotConfiguration {
+	if r == nil {
+		return nil
+	}
+	return r.Logical
+}
+
+// LogicalReplicationSlotStatus is the status of a managed logical
+// replication slot, as reported by the primary instance
+type LogicalReplicationSlotStatus struct {
+	// The name of the replication slot
+	Name string `json:"name"`
+
+	// The database the replication slot is defined in
+	Database string `json:"database,omitempty"`
+
+	// Whether a subscriber is connected to the replication slot
+	Active bool `json:"active"`
+
+	// Whether the replication slot is synchronized on the standbys, to
+	// be used after a failover
+	Failover bool `json:"failover"`
+
+	// The LSN up to which the subscriber has confirmed receiving data
+	// +optional
+	ConfirmedFlushLSN string `json:"confirmedFlushLSN,omitempty"`
+
+	// The amount of WAL, in bytes, generated after the last position
+	// confirmed by the subscriber
+	// +optional
+	LagBytes int64 `json:"lagBytes,omitempty"`
 }
 
 // GetEnabled returns false if replication slots are disabled, default is true
@@ -1350,9 +2938,30 @@ const (
 	// replica when it needs to upgrade the primary instance
 	PrimaryUpdateMethodSwitchover PrimaryUpdateMethod = "switchover"
 
-	// PrimaryUpdateMethodRestart means that the operator will restart the primary instance in-place
-	// when it needs to upgrade it
-	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
+	// PrimaryUpdateMethodRestart means that the operator will restart the primary instance in-place
+	// when it needs to upgrade it
+	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
+)
+
+// ParameterChangeRestartStrategy tells when the instances are restarted
+// to apply the changes to the PostgreSQL parameters
+type ParameterChangeRestartStrategy string
+
+const (
+	// ParameterChangeRestartStrategyImmediate means that the instances are
+	// restarted as soon as the changes are applied (`immediate`, default)
+	ParameterChangeRestartStrategyImmediate ParameterChangeRestartStrategy = "immediate"
+
+	// ParameterChangeRestartStrategyMaintenanceWindow means that the instances
+	// are restarted during the next maintenance window (`maintenanceWindow`)
+	ParameterChangeRestartStrategyMaintenanceWindow ParameterChangeRestartStrategy = "maintenanceWindow"
+
+	// ParameterChangeRestartStrategyManual means that the instances are
+	// restarted only after the user approved it (`manual`)
+	ParameterChangeRestartStrategyManual ParameterChangeRestartStrategy = "manual"
+)
+
+const (
 
 	// DefaultPgCtlTimeoutForPromotion is the default for the pg_ctl timeout when a promotion is performed.
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
@@ -1374,25 +2983,67 @@ type PostgresConfiguration struct {
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// The names of the parameters to be accepted even if they are not in
+	// the catalog of the parameters supported by the PostgreSQL major version,
+	// for example when using an image built with additional patches
+	// +optional
+	AllowedUnknownParameters []string `json:"allowedUnknownParameters,omitempty"`
+
 	// PostgreSQL Host Based Authentication rules (lines to be appended
 	// to the pg_hba.conf file)
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// PostgreSQL Host Based Authentication rules to be placed at the
+	// beginning of the pg_hba.conf file, before the fixed rules defined by
+	// the operator. Use them with care, as they can prevent the instances
+	// from replicating or the operator from managing them
+	// +optional
+	PgHBAPrepend []string `json:"pg_hba_prepend,omitempty"`
+
 	// PostgreSQL User Name Maps rules (lines to be appended
 	// to the pg_ident.conf file)
 	// +optional
 	PgIdent []string `json:"pg_ident,omitempty"`
 
+	// The authentication of the clients through their TLS certificates,
+	// mapping the common names of the certificates to PostgreSQL roles
+	// +optional
+	CertificateAuthentication *CertificateAuthenticationConfiguration `json:"certificateAuthentication,omitempty"`
+
 	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
 	// set up.
 	// +optional
 	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
 
+	// The names of the instances where `hot_standby_feedback` will be
+	// enabled, i.e. the replicas dedicated to long-running queries, like
+	// reporting ones. The other instances will use the default setting.
+	// Cannot be used together with the `hot_standby_feedback` parameter
+	// +optional
+	HotStandbyFeedbackInstances []string `json:"hotStandbyFeedbackInstances,omitempty"`
+
+	// The replicas applying the changes with a delay, to be used as a
+	// safety net against human errors. They are never promoted
+	// automatically and are excluded from the read-only service
+	// and from the synchronous replicas
+	// +optional
+	DelayedReplicas *DelayedReplicasConfiguration `json:"delayedReplicas,omitempty"`
+
 	// Lists of shared preload libraries to add to the default ones
 	// +optional
 	AdditionalLibraries []string `json:"shared_preload_libraries,omitempty"`
 
+	// The extensions requiring superuser privileges that are fully
+	// managed by the operator, which loads the needed shared preload
+	// libraries, applies their default configuration and creates them
+	// in every database. The parameters set in `parameters` take
+	// precedence over the default configuration of the extensions
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Extensions []ManagedExtensionConfiguration `json:"extensions,omitempty"`
+
 	// Options to specify LDAP configuration
 	// +optional
 	LDAP *LDAPConfig `json:"ldap,omitempty"`
@@ -1409,8 +3060,427 @@ type PostgresConfiguration struct {
 	// Defaults to false.
 	// +optional
 	EnableAlterSystem bool `json:"enableAlterSystem,omitempty"`
+
+	// The format of the log written by PostgreSQL, which is parsed by the
+	// instance manager and written to its standard output in JSON format.
+	// Available options are `csvlog`, which is the default, and `jsonlog`,
+	// which requires PostgreSQL 15 or newer
+	// +kubebuilder:validation:Enum=csvlog;jsonlog
+	// +optional
+	LogDestination LogDestination `json:"logDestination,omitempty"`
+
+	// Configuration of the automatic tuning of `archive_timeout`, that is
+	// increased on idle clusters and decreased on busy ones depending
+	// on the observed WAL generation rate
+	// +optional
+	AdaptiveArchiveTimeout *AdaptiveArchiveTimeoutConfiguration `json:"adaptiveArchiveTimeout,omitempty"`
+
+	// The default timeouts enforced on the sessions of every role
+	// of the cluster, except for the exempted ones
+	// +optional
+	Timeouts *TimeoutsConfiguration `json:"timeouts,omitempty"`
+
+	// The logging of the slow statements and of the related events,
+	// applied by reloading the configuration, without restarting
+	// the instances
+	// +optional
+	SlowQueryLogging *SlowQueryLoggingConfiguration `json:"slowQueryLogging,omitempty"`
+
+	// The management of the `pg_stat_statements` extension, which is
+	// enabled in every database when this section is set, including the
+	// number of tracked statements and the periodic reset of the statistics
+	// +optional
+	StatStatements *StatStatementsConfiguration `json:"statStatements,omitempty"`
+
+	// The autovacuum tuning profile, which sets the autovacuum parameters
+	// suited for the workload of the cluster. The parameters set in
+	// `parameters` take precedence over the ones of the profile
+	// +optional
+	Autovacuum *AutovacuumConfiguration `json:"autovacuum,omitempty"`
+
+	// The checkpoint tuning profile, which sets the size of the WAL and
+	// the frequency of the checkpoints suited for the write workload of
+	// the cluster. The parameters set in `parameters` take precedence
+	// over the ones of the profile
+	// +optional
+	Checkpoint *CheckpointConfiguration `json:"checkpoint,omitempty"`
+
+	// The memory settings of PostgreSQL expressed as a percentage of the
+	// memory limit of the container, which the instances compute into
+	// absolute values, following any change to the resources of the cluster
+	// +optional
+	Memory *MemoryConfiguration `json:"memory,omitempty"`
+
+	// The TLS protocol versions and ciphers accepted by PostgreSQL
+	// +optional
+	TLS *PostgresTLSConfiguration `json:"tls,omitempty"`
+}
+
+// ClientCertificateIdentMap is the name of the pg_ident.conf user
+// map containing the mappings of the client certificates
+const ClientCertificateIdentMap = "cnpg_client_certificate"
+
+// CertificateAuthenticationConfiguration contains the mappings
+// between the common names of the client certificates and the PostgreSQL
+// roles. Each mapped role is authenticated with the `cert` method of
+// pg_hba.conf, using the corresponding user map of pg_ident.conf
+type CertificateAuthenticationConfiguration struct {
+	// The mappings between the common names of the client
+	// certificates and the PostgreSQL roles
+	// +kubebuilder:validation:MinItems=1
+	Mappings []CertificateMapping `json:"mappings"`
+
+	// Additional secrets, each one containing a `ca.crt` entry, with the
+	// CA certificates trusted to sign the client certificates together
+	// with the client CA of the cluster. Use them while rotating the CA
+	// issuing the client certificates, to trust both the old and the new
+	// one until every client has a new certificate
+	// +optional
+	AdditionalClientCASecrets []LocalObjectReference `json:"additionalClientCASecrets,omitempty"`
+}
+
+// CertificateMapping maps the common name of a client
+// certificate to a PostgreSQL role
+type CertificateMapping struct {
+	// The common name of the client certificate. When it starts with
+	// a slash (`/`), the rest is a regular expression
+	CommonName string `json:"commonName"`
+
+	// The PostgreSQL role the clients are authenticated as. It must be
+	// a managed role or the owner of the application database
+	Role string `json:"role"`
+}
+
+// GetIdentMappings gets the pg_ident.conf lines mapping the common
+// names of the client certificates to the PostgreSQL roles
+func (configuration *CertificateAuthenticationConfiguration) GetIdentMappings() []string {
+	if configuration == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(configuration.Mappings))
+	for _, mapping := range configuration.Mappings {
+		commonName := mapping.CommonName
+		if !strings.HasPrefix(commonName, "/") {
+			commonName = quoteConfigurationToken(commonName)
+		}
+		result = append(result, fmt.Sprintf("%s %s %s",
+			ClientCertificateIdentMap, commonName, quoteConfigurationToken(mapping.Role)))
+	}
+
+	return result
+}
+
+// GetHBARules gets the pg_hba.conf rules authenticating the mapped
+// roles with their client certificates
+func (configuration *CertificateAuthenticationConfiguration) GetHBARules() []string {
+	if configuration == nil {
+		return nil
+	}
+
+	var result []string
+	for _, mapping := range configuration.Mappings {
+		rule := fmt.Sprintf("hostssl all %s all cert map=%s",
+			quoteConfigurationToken(mapping.Role), ClientCertificateIdentMap)
+		if !slices.Contains(result, rule) {
+			result = append(result, rule)
+		}
+	}
+
+	return result
+}
+
+// GetAdditionalClientCASecretNames gets the names of the secrets containing
+// the additional CA certificates trusted to sign the client certificates
+func (configuration *CertificateAuthenticationConfiguration) GetAdditionalClientCASecretNames() []string {
+	if configuration == nil {
+		return nil
+	}
+
+	result := make([]string, len(configuration.AdditionalClientCASecrets))
+	for idx, secret := range configuration.AdditionalClientCASecrets {
+		result[idx] = secret.Name
+	}
+
+	return result
+}
+
+// quoteConfigurationToken quotes a token of pg_hba.conf or pg_ident.conf,
+// so that it's used literally, without any special meaning
+func quoteConfigurationToken(token string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(token, `"`, `""`))
+}
+
+// PostgresTLSConfiguration contains the TLS settings of PostgreSQL,
+// written in `ssl_min_protocol_version` and `ssl_ciphers`. The minimum
+// protocol version is also required by the instances when connecting
+// to the primary
+type PostgresTLSConfiguration struct {
+	// The minimum TLS protocol version accepted by PostgreSQL.
+	// Defaults to `TLSv1.3`
+	// +kubebuilder:validation:Enum=TLSv1;TLSv1.1;TLSv1.2;TLSv1.3
+	// +optional
+	MinProtocolVersion string `json:"minProtocolVersion,omitempty"`
+
+	// The list of the TLS ciphers accepted by PostgreSQL, as OpenSSL
+	// cipher names like `ECDHE-RSA-AES256-GCM-SHA384`. They are only
+	// used by TLS 1.2 and older versions, as the ciphers of TLS 1.3
+	// are not configurable
+	// +optional
+	Ciphers []string `json:"ciphers,omitempty"`
+}
+
+// TimeoutsConfiguration contains the cluster-wide defaults of
+// statement_timeout and idle_in_transaction_session_timeout.
+// They are written in the PostgreSQL configuration, while the
+// exempted roles, together with the superuser and the streaming
+// replication user, have them disabled with `ALTER ROLE ... SET`,
+// which takes precedence over the configuration files
+type TimeoutsConfiguration struct {
+	// The default value of `statement_timeout`, as a duration like
+	// `30s` or `5m`. Zero disables the timeout
+	// +optional
+	StatementTimeout string `json:"statementTimeout,omitempty"`
+
+	// The default value of `idle_in_transaction_session_timeout`, as
+	// a duration like `30s` or `5m`. Zero disables the timeout
+	// +optional
+	IdleInTransactionSessionTimeout string `json:"idleInTransactionSessionTimeout,omitempty"`
+
+	// The roles which are not subject to the default timeouts,
+	// like the ones used by batch jobs or by the database migrations
+	// +optional
+	ExemptRoles []string `json:"exemptRoles,omitempty"`
+}
+
+// SlowQueryLoggingParameters are the PostgreSQL parameters controlled
+// by the slow query logging settings of the cluster
+var SlowQueryLoggingParameters = []string{
+	"log_autovacuum_min_duration",
+	"log_lock_waits",
+	"log_min_duration_statement",
+	"log_temp_files",
+}
+
+// SlowQueryLoggingConfiguration contains the settings of the logging
+// of the slow statements and of the related events. They all can be
+// changed by reloading the configuration of PostgreSQL
+type SlowQueryLoggingConfiguration struct {
+	// The duration above which the statements are logged, as a duration
+	// like `500ms` or `2s`, setting `log_min_duration_statement`.
+	// Zero logs every statement
+	// +optional
+	MinDuration string `json:"minDuration,omitempty"`
+
+	// The duration above which the actions of autovacuum are logged,
+	// as a duration like `1s` or `1m`, setting `log_autovacuum_min_duration`.
+	// Zero logs every action
+	// +optional
+	AutovacuumMinDuration string `json:"autovacuumMinDuration,omitempty"`
+
+	// Whether the sessions waiting for a lock longer than `deadlock_timeout`
+	// are logged, setting `log_lock_waits`
+	// +optional
+	LockWaits *bool `json:"lockWaits,omitempty"`
+
+	// The size above which the temporary files are logged when deleted,
+	// like `10Mi`, setting `log_temp_files`. Zero logs every temporary file
+	// +optional
+	TempFilesMinSize *resource.Quantity `json:"tempFilesMinSize,omitempty"`
+}
+
+// StatStatementsExtensionName is the name of the managed extension
+// configured by the `statStatements` section
+const StatStatementsExtensionName = "pg_stat_statements"
+
+// StatStatementsConfiguration contains the settings of the
+// `pg_stat_statements` extension
+type StatStatementsConfiguration struct {
+	// The maximum number of statements tracked, setting
+	// `pg_stat_statements.max`. Changing it requires a restart
+	// of the instances. Defaults to 10000
+	// +kubebuilder:validation:Minimum=100
+	// +optional
+	Max int32 `json:"max,omitempty"`
+
+	// The schedule of the reset of the statistics on the primary
+	// instance, to observe the statements executed in fresh windows.
+	// The schedule does not follow the same format used in Kubernetes
+	// CronJobs as it includes an additional seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format.
+	// The statistics are never reset when empty
+	// +optional
+	ResetSchedule string `json:"resetSchedule,omitempty"`
+}
+
+// AutovacuumProfile is a named set of autovacuum parameters
+type AutovacuumProfile string
+
+const (
+	// AutovacuumProfileDefault keeps the autovacuum defaults of PostgreSQL
+	AutovacuumProfileDefault AutovacuumProfile = "default"
+
+	// AutovacuumProfileAggressive vacuums and analyzes the tables more
+	// often and faster, for workloads with many updates and deletes
+	AutovacuumProfileAggressive AutovacuumProfile = "aggressive"
+
+	// AutovacuumProfileBulkLoad vacuums less often, reducing the overhead
+	// on workloads mostly loading data in large batches
+	AutovacuumProfileBulkLoad AutovacuumProfile = "bulk-load"
+)
+
+// AutovacuumConfiguration contains the autovacuum tuning profile
+type AutovacuumConfiguration struct {
+	// The name of the profile. Available options are `default`, which
+	// keeps the PostgreSQL defaults, `aggressive` and `bulk-load`
+	// +kubebuilder:validation:Enum=default;aggressive;bulk-load
+	// +kubebuilder:default:=default
+	// +optional
+	Profile AutovacuumProfile `json:"profile,omitempty"`
+}
+
+// CheckpointProfile is a named set of checkpoint and WAL size parameters
+type CheckpointProfile string
+
+const (
+	// CheckpointProfileDefault keeps the checkpoint defaults of PostgreSQL
+	CheckpointProfileDefault CheckpointProfile = "default"
+
+	// CheckpointProfileWriteIntensive spreads the checkpoints over a
+	// longer time and more WAL, for workloads with a sustained write rate
+	CheckpointProfileWriteIntensive CheckpointProfile = "write-intensive"
+
+	// CheckpointProfileBulkLoad makes the checkpoints rare, reducing the
+	// full-page writes on workloads loading data in large batches
+	CheckpointProfileBulkLoad CheckpointProfile = "bulk-load"
+)
+
+// CheckpointConfiguration contains the checkpoint tuning profile
+type CheckpointConfiguration struct {
+	// The name of the profile. Available options are `default`, which
+	// keeps the PostgreSQL defaults, `write-intensive` and `bulk-load`
+	// +kubebuilder:validation:Enum=default;write-intensive;bulk-load
+	// +kubebuilder:default:=default
+	// +optional
+	Profile CheckpointProfile `json:"profile,omitempty"`
+}
+
+// MemoryConfiguration contains the memory settings of PostgreSQL
+// expressed as a percentage of the memory limit of the container
+type MemoryConfiguration struct {
+	// The percentage of the memory limit assigned to `shared_buffers`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=80
+	// +optional
+	SharedBuffersPercentage int32 `json:"sharedBuffersPercentage,omitempty"`
+
+	// The percentage of the memory limit used as `effective_cache_size`,
+	// which includes the memory assigned to `shared_buffers`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	EffectiveCacheSizePercentage int32 `json:"effectiveCacheSizePercentage,omitempty"`
+}
+
+// DelayedReplicasConfiguration contains the replicas applying the
+// changes with a delay, through the `recovery_min_apply_delay` parameter
+type DelayedReplicasConfiguration struct {
+	// The names of the delayed instances, like `cluster-example-3`
+	// +kubebuilder:validation:MinItems=1
+	Instances []string `json:"instances"`
+
+	// The delay after which the changes are applied, as a duration
+	// like `1h` or `30m`
+	// +kubebuilder:validation:MinLength=1
+	MinApplyDelay string `json:"minApplyDelay"`
 }
 
+// ManagedExtensionConfiguration declares an extension to be managed by the operator
+type ManagedExtensionConfiguration struct {
+	// The name of the extension which, unless the recipe is set, must be
+	// one of the extensions supported by the operator, like
+	// `pg_stat_statements` or `pgaudit`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// How to manage an extension which is not supported by the operator.
+	// It can't be set for the supported extensions
+	// +optional
+	Recipe *ManagedExtensionRecipe `json:"recipe,omitempty"`
+}
+
+// ManagedExtensionRecipe describes how the operator
+// manages an extension it doesn't support natively
+type ManagedExtensionRecipe struct {
+	// The shared libraries needed by the extension, which
+	// are added to `shared_preload_libraries`
+	// +optional
+	SharedPreloadLibraries []string `json:"sharedPreloadLibraries,omitempty"`
+
+	// The default configuration of the extension. Only the parameters
+	// of the extensions, whose name contains a dot, like
+	// `cron.database_name`, are allowed. The parameters set in
+	// `parameters` take precedence
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Set it to true for the extensions made only of shared libraries,
+	// which don't need `CREATE EXTENSION`
+	// +optional
+	SkipCreateExtension bool `json:"skipCreateExtension,omitempty"`
+}
+
+// AdaptiveArchiveTimeoutConfiguration contains the configuration of the
+// automatic tuning of archive_timeout. The primary instance sets it to half
+// the time needed to fill a WAL segment at the observed WAL generation rate,
+// within the configured range
+type AdaptiveArchiveTimeoutConfiguration struct {
+	// Enabled tells the primary instance to tune archive_timeout
+	// depending on the WAL generation rate
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The lowest value of archive_timeout in seconds, used when the
+	// cluster is busy. Defaults to 60
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	MinTimeout int32 `json:"minTimeout,omitempty"`
+
+	// The highest value of archive_timeout in seconds, used when the
+	// cluster is idle. Defaults to 1800
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	MaxTimeout int32 `json:"maxTimeout,omitempty"`
+}
+
+// SyncReplicasUnavailablePolicy is the behavior of the synchronous
+// replication when fewer than `minSyncReplicas` standbys are ready
+type SyncReplicasUnavailablePolicy string
+
+const (
+	// SyncReplicasUnavailablePolicyBlock means that the primary keeps
+	// requiring `minSyncReplicas` synchronous standbys, blocking the
+	// write transactions until they are available
+	SyncReplicasUnavailablePolicyBlock SyncReplicasUnavailablePolicy = "block"
+
+	// SyncReplicasUnavailablePolicyRelax means that the number of
+	// synchronous standbys is lowered to the ready ones, allowing the
+	// write transactions to complete without them
+	SyncReplicasUnavailablePolicyRelax SyncReplicasUnavailablePolicy = "relax"
+)
+
+// LogDestination is the format of the log written by PostgreSQL
+type LogDestination string
+
+const (
+	// LogDestinationCSV means that PostgreSQL writes its log in CSV format
+	LogDestinationCSV LogDestination = "csvlog"
+
+	// LogDestinationJSON means that PostgreSQL writes its log in JSON format
+	LogDestinationJSON LogDestination = "jsonlog"
+)
+
 // BootstrapConfiguration contains information about how to create the PostgreSQL
 // cluster. Only a single bootstrap method can be defined among the supported
 // ones. `initdb` will be used as the bootstrap method if left
@@ -1560,6 +3630,11 @@ type CertificatesStatus struct {
 	// Expiration dates for all certificates.
 	// +optional
 	Expirations map[string]string `json:"expirations,omitempty"`
+
+	// The time of the last renewal of the certificates requested
+	// with the `cnpg.io/rotateCertificates` annotation
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
 }
 
 // BootstrapInitDB is the configuration of the bootstrap process when
@@ -1597,6 +3672,12 @@ type BootstrapInitDB struct {
 	// +optional
 	Encoding string `json:"encoding,omitempty"`
 
+	// The value to be passed as option `--locale` for initdb, setting the
+	// default for all the locale categories, like `en_US.UTF-8`. When set,
+	// `localeCollate` and `localeCType` are not defaulted to `C`
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
 	// The value to be passed as option `--lc-collate` for initdb (default:`C`)
 	// +optional
 	LocaleCollate string `json:"localeCollate,omitempty"`
@@ -1605,6 +3686,12 @@ type BootstrapInitDB struct {
 	// +optional
 	LocaleCType string `json:"localeCType,omitempty"`
 
+	// The default time zone of the instances, like `Europe/Rome`, written
+	// by initdb in the `timezone` and `log_timezone` parameters (default:
+	// the time zone of the operand image, usually `Etc/UTC`)
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+
 	// The value in megabytes (1 to 1024) to be passed to the `--wal-segsize`
 	// option for initdb (default: empty, resulting in PostgreSQL default: 16MB)
 	// +kubebuilder:validation:Minimum=1
@@ -1635,13 +3722,35 @@ type BootstrapInitDB struct {
 	// +optional
 	Import *Import `json:"import,omitempty"`
 
-	// PostInitApplicationSQLRefs points references to ConfigMaps or Secrets which
-	// contain SQL files, the general implementation order to these references is
-	// from all Secrets to all ConfigMaps, and inside Secrets or ConfigMaps,
-	// the implementation order is same as the order of each array
+	// List of references to ConfigMaps or Secrets containing SQL files
+	// to be executed as a superuser in the application database right after
+	// the database is created. The references are processed in a specific order:
+	// first, all Secrets are processed, followed by all ConfigMaps.
+	// Within each group, the processing order follows the sequence specified
+	// in their respective arrays.
+	// (by default empty)
+	// +optional
+	PostInitApplicationSQLRefs *SQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
+
+	// List of references to ConfigMaps or Secrets containing SQL files
+	// to be executed as a superuser in the `postgres` database right after
+	// the cluster has been created, after the queries in `postInitSQL`.
+	// The references are processed in a specific order: first, all Secrets
+	// are processed, followed by all ConfigMaps. Within each group, the
+	// processing order follows the sequence specified in their respective arrays.
 	// (by default empty)
 	// +optional
-	PostInitApplicationSQLRefs *PostInitApplicationSQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
+	PostInitSQLRefs *SQLRefs `json:"postInitSQLRefs,omitempty"`
+
+	// List of references to ConfigMaps or Secrets containing SQL files
+	// to be executed as a superuser in the `template1` database right after
+	// the cluster has been created, after the queries in `postInitTemplateSQL`.
+	// The references are processed in a specific order: first, all Secrets
+	// are processed, followed by all ConfigMaps. Within each group, the
+	// processing order follows the sequence specified in their respective arrays.
+	// (by default empty)
+	// +optional
+	PostInitTemplateSQLRefs *SQLRefs `json:"postInitTemplateSQLRefs,omitempty"`
 }
 
 // SnapshotType is a type of allowed import
@@ -1681,19 +3790,57 @@ type Import struct {
 	// `pg_restore` are invoked, avoiding data import. Default: `false`.
 	// +optional
 	SchemaOnly bool `json:"schemaOnly,omitempty"`
+
+	// The number of concurrent jobs used by `pg_restore` to import each
+	// database, speeding up the import of large databases. Not applicable
+	// when importing from a backup, as the dump is streamed into
+	// `pg_restore`. Default: `1`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestoreJobs int32 `json:"restoreJobs,omitempty"`
 }
 
 // ImportSource describes the source for the logical snapshot
 type ImportSource struct {
 	// The name of the externalCluster used for import
 	ExternalCluster string `json:"externalCluster"`
+
+	// When set, the databases are not imported by connecting to the
+	// external cluster: its base backup, stored in the object store defined
+	// in the `barmanObjectStore` section of the external cluster, is
+	// recovered into a temporary cluster, which is the source of the import
+	// and is deleted once the import is completed.
+	// Only available with the `microservice` import type.
+	// +optional
+	FromBackup *ImportFromBackup `json:"fromBackup,omitempty"`
 }
 
-// PostInitApplicationSQLRefs points references to ConfigMaps or Secrets which
-// contain SQL files, the general implementation order to these references is
-// from all Secrets to all ConfigMaps, and inside Secrets or ConfigMaps,
-// the implementation order is same as the order of each array
-type PostInitApplicationSQLRefs struct {
+// ImportFromBackup contains the configuration of the temporary cluster
+// used to import a database from a base backup
+type ImportFromBackup struct {
+	// The point in time to recover the base backup to, defaulting
+	// to the end of the WAL archive
+	// +optional
+	RecoveryTarget *RecoveryTarget `json:"recoveryTarget,omitempty"`
+
+	// The image of the temporary cluster, which must have the same
+	// PostgreSQL major version of the backup. Defaults to the image of
+	// this cluster
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// The storage of the temporary cluster, which must be large enough
+	// to contain the whole backup. Defaults to the storage of this cluster
+	// +optional
+	Storage *StorageConfiguration `json:"storage,omitempty"`
+}
+
+// SQLRefs holds references to ConfigMaps or Secrets
+// containing SQL files. The references are processed in a specific order:
+// first, all Secrets are processed, followed by all ConfigMaps.
+// Within each group, the processing order follows the sequence specified
+// in their respective arrays.
+type SQLRefs struct {
 	// SecretRefs holds a list of references to Secrets
 	// +optional
 	SecretRefs []SecretKeySelector `json:"secretRefs,omitempty"`
@@ -1760,6 +3907,53 @@ type BootstrapRecovery struct {
 	// created from scratch
 	// +optional
 	Secret *LocalObjectReference `json:"secret,omitempty"`
+
+	// The maintenance operations to be run on every database once the
+	// recovery completes, before the cluster is marked as ready.
+	// They are not run on replica clusters.
+	// +optional
+	PostRecovery *PostRecoveryConfiguration `json:"postRecovery,omitempty"`
+
+	// When enabled, the parameters set with `ALTER SYSTEM` in the origin
+	// cluster, and stored in the `postgresql.auto.conf` file of the backup,
+	// are kept in the recovered cluster, where they take precedence over
+	// `.spec.postgresql.parameters`. By default, they are removed.
+	// In both cases, they are reported in the status of the cluster.
+	// +optional
+	ReapplyAlterSystemSettings bool `json:"reapplyAlterSystemSettings,omitempty"`
+}
+
+// PostRecoveryConfiguration contains the maintenance operations to be run
+// after the recovery, to avoid relying on autovacuum to catch up with a
+// freshly restored instance. They make the bootstrap take longer, proportionally
+// to the size of the databases.
+type PostRecoveryConfiguration struct {
+	// Update the planner statistics of every database with `ANALYZE`
+	// +optional
+	Analyze bool `json:"analyze,omitempty"`
+
+	// Run `VACUUM` on every database, updating the visibility map
+	// and freezing the rows. Combined with `analyze`, it runs
+	// `VACUUM (ANALYZE)`
+	// +optional
+	Vacuum bool `json:"vacuum,omitempty"`
+}
+
+// GetMaintenanceCommand gets the SQL command to be run on every database after
+// the recovery, or an empty string if there's nothing to do
+func (configuration *PostRecoveryConfiguration) GetMaintenanceCommand() string {
+	switch {
+	case configuration == nil:
+		return ""
+	case configuration.Vacuum && configuration.Analyze:
+		return "VACUUM (ANALYZE)"
+	case configuration.Vacuum:
+		return "VACUUM"
+	case configuration.Analyze:
+		return "ANALYZE"
+	default:
+		return ""
+	}
 }
 
 // DataSource contains the configuration required to bootstrap a
@@ -1821,7 +4015,10 @@ type RecoveryTarget struct {
 	// +optional
 	BackupID string `json:"backupID,omitempty"`
 
-	// The target timeline ("latest" or a positive integer)
+	// The target timeline ("latest", "current" or a positive integer).
+	// When a positive integer is used, the timeline and its history file
+	// must be available in the WAL archive, and the backup must have been
+	// taken on that timeline or on one of its parents before the fork
 	// +optional
 	TargetTLI string `json:"targetTLI,omitempty"`
 
@@ -1900,6 +4097,37 @@ func (s *StorageConfiguration) GetSizeOrNil() *resource.Quantity {
 	return nil
 }
 
+// PodDisruptionBudgetConfiguration contains the disruption policies for the
+// instances of the cluster
+type PodDisruptionBudgetConfiguration struct {
+	// The disruption policy of the primary instance.
+	// Defaults to `minAvailable: 1`
+	// +optional
+	Primary *PodDisruptionBudgetPolicy `json:"primary,omitempty"`
+
+	// The disruption policy of the replicas.
+	// Defaults to `minAvailable` being the number of replicas minus one,
+	// when the cluster has at least two replicas
+	// +optional
+	Replicas *PodDisruptionBudgetPolicy `json:"replicas,omitempty"`
+}
+
+// PodDisruptionBudgetPolicy is the policy used to build a
+// `PodDisruptionBudget`. Only one of `minAvailable` and `maxUnavailable`
+// can be set.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetPolicy struct {
+	// The number or percentage of the selected instances that must be
+	// available after an eviction
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// The number or percentage of the selected instances that can be
+	// unavailable after an eviction
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
 // TablespaceConfiguration is the configuration of a tablespace, and includes
 // the storage specification for the tablespace
 type TablespaceConfiguration struct {
@@ -1992,6 +4220,101 @@ type AffinityConfiguration struct {
 	AdditionalPodAffinity *corev1.PodAffinity `json:"additionalPodAffinity,omitempty"`
 }
 
+// ParameterChangeRestartConfiguration tells when the instances are restarted
+// to apply the changes to the PostgreSQL parameters requiring a restart.
+// The restarts can always be approved through the `cnpg.io/approveRestart`
+// annotation. Other causes of a rolling update, like a change of the image,
+// are not affected
+type ParameterChangeRestartConfiguration struct {
+	// The strategy to follow: restart the instances immediately (`immediate`,
+	// default), during the next maintenance window (`maintenanceWindow`), or
+	// only after the approval of the user (`manual`)
+	// +kubebuilder:default:=immediate
+	// +kubebuilder:validation:Enum:=immediate;maintenanceWindow;manual
+	// +optional
+	Strategy ParameterChangeRestartStrategy `json:"strategy,omitempty"`
+
+	// The maintenance window, required by the `maintenanceWindow` strategy
+	// +optional
+	MaintenanceWindow *MaintenanceWindowConfiguration `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindowConfiguration is a recurring time window during
+// which disruptive operations can happen
+type MaintenanceWindowConfiguration struct {
+	// When the maintenance window opens. The schedule does not follow the same
+	// format used in Kubernetes CronJobs as it includes an additional seconds
+	// specifier, see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// How long the maintenance window stays open, expressed as a
+	// Go duration like `30m` or `2h`. Defaults to `1h`
+	// +optional
+	Duration string `json:"duration,omitempty"`
+}
+
+// PendingRestartStatus is the restart required to apply the changes to
+// the PostgreSQL parameters, which has been deferred
+type PendingRestartStatus struct {
+	// The strategy deferring the restart
+	Strategy ParameterChangeRestartStrategy `json:"strategy"`
+
+	// The instances waiting to be restarted
+	// +optional
+	Instances []string `json:"instances,omitempty"`
+
+	// The PostgreSQL parameters whose changes require the restart
+	// +optional
+	Parameters []string `json:"parameters,omitempty"`
+
+	// When the next maintenance window opens, if any
+	// +optional
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+}
+
+// RolePlacementConfiguration contains the placement rules of the pods
+// depending on the role of the instance they are running
+type RolePlacementConfiguration struct {
+	// The placement rules of the pod of the primary instance
+	// +optional
+	Primary *InstancePlacement `json:"primary,omitempty"`
+
+	// The placement rules of the pods of the replicas
+	// +optional
+	Replica *InstancePlacement `json:"replica,omitempty"`
+}
+
+// InstancePlacement contains the placement rules of the pods of the
+// instances having a certain role. Every field that is set replaces
+// the corresponding cluster-wide one
+type InstancePlacement struct {
+	// NodeSelector is map of key-value pairs used to define the nodes on which
+	// the pods can run, replacing the one in the affinity section.
+	// More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// NodeAffinity describes node affinity scheduling rules for the pods,
+	// replacing the one in the affinity section.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#node-affinity
+	// +optional
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// Tolerations is a list of Tolerations that should be set for the pods,
+	// replacing the ones in the affinity section.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints specifies how to spread the pods among the given
+	// topology, replacing the cluster-wide constraints.
+	// More info:
+	// https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
 // RollingUpdateStatus contains the information about an instance which is
 // being updated
 type RollingUpdateStatus struct {
@@ -2030,8 +4353,17 @@ const (
 	// CompressionTypeBzip2 means bzip2 compression is performed
 	CompressionTypeBzip2 = CompressionType("bzip2")
 
-	// CompressionTypeSnappy means snappy compression is performed
-	CompressionTypeSnappy = CompressionType("snappy")
+	// CompressionTypeSnappy means snappy compression is performed
+	CompressionTypeSnappy = CompressionType("snappy")
+
+	// CompressionTypeZstd means zstd compression is performed
+	CompressionTypeZstd = CompressionType("zstd")
+
+	// CompressionTypeLz4 means lz4 compression is performed
+	CompressionTypeLz4 = CompressionType("lz4")
+
+	// CompressionTypeXz means xz compression is performed
+	CompressionTypeXz = CompressionType("xz")
 )
 
 // EncryptionType encapsulated the available types of encryption
@@ -2086,6 +4418,13 @@ type BarmanObjectStoreConfiguration struct {
 	// +optional
 	EndpointCA *SecretKeySelector `json:"endpointCA,omitempty"`
 
+	// ForcePathStyle addresses the buckets with path-style URLs, like
+	// `https://endpoint/bucket`, instead of virtual-hosted-style ones,
+	// as required by many S3-compatible object stores. Only available
+	// with the S3 credentials
+	// +optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+
 	// The path where to store the backup (i.e. s3://bucket/path/to/folder)
 	// this path, with different destination folders, will be used for WALs
 	// and for data
@@ -2134,6 +4473,24 @@ type BackupConfiguration struct {
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// AdditionalWalDestinations is the list of further object stores where
+	// every WAL file is archived, together with the one defined in
+	// `barmanObjectStore`. Base backups are only taken on `barmanObjectStore`,
+	// and copied to the destination acting as archive tier, if any.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdditionalWalDestinations []WalArchiveDestination `json:"additionalWalDestinations,omitempty"`
+
+	// WalArchiveQuorum is the number of WAL destinations, including
+	// `barmanObjectStore`, where a WAL file must be archived before
+	// reporting success to PostgreSQL. A WAL file that couldn't be
+	// archived in the remaining destinations is kept aside and archived
+	// there later. Defaults to all the WAL destinations.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WalArchiveQuorum *int `json:"walArchiveQuorum,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
@@ -2151,17 +4508,252 @@ type BackupConfiguration struct {
 	// +kubebuilder:default:=prefer-standby
 	// +optional
 	Target BackupTarget `json:"target,omitempty"`
+
+	// Verification configures the periodic restore of the latest base
+	// backup into a scratch instance, to make sure it can be recovered.
+	// It's currently only applicable when using the BarmanObjectStore method.
+	// +optional
+	Verification *BackupVerificationConfiguration `json:"verification,omitempty"`
+
+	// WalArchiveCheck configures the periodic check of the WAL archive,
+	// looking for gaps in the sequence of the WAL files needed to recover
+	// from the oldest base backup.
+	// It's currently only applicable when using the BarmanObjectStore method.
+	// +optional
+	WalArchiveCheck *WalArchiveCheckConfiguration `json:"walArchiveCheck,omitempty"`
+
+	// The target recovery point objective, in seconds. When set, the
+	// operator reports in the `TargetRPOMet` condition whether the
+	// WAL files waiting to be archived by the primary are older than it
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetRPO int32 `json:"targetRPO,omitempty"`
+
+	// The monitoring of the WAL files waiting to be archived on the
+	// volume of the primary, and the reaction when they are too many
+	// +optional
+	WalArchiveBacklog *WalArchiveBacklogConfiguration `json:"walArchiveBacklog,omitempty"`
+
+	// Hooks are the commands executed before and after each base backup,
+	// inside the PostgreSQL container of the instance taking it.
+	// It's currently only applicable when using the BarmanObjectStore method.
+	// +optional
+	Hooks *BackupHooksConfiguration `json:"hooks,omitempty"`
+
+	// Archive configures the archive tier, where base backups are
+	// periodically exported to one of the additional WAL destinations
+	// and kept according to a longer retention policy.
+	// It's currently only applicable when using the BarmanObjectStore method.
+	// +optional
+	Archive *BackupArchiveConfiguration `json:"archive,omitempty"`
+}
+
+// BackupArchiveConfiguration contains the configuration of the archive
+// tier of the base backups
+type BackupArchiveConfiguration struct {
+	// The name of the destination, among the `additionalWalDestinations`,
+	// acting as archive tier. Being a WAL destination, it receives every
+	// WAL file needed to recover the base backups exported there
+	// +kubebuilder:validation:MinLength=1
+	DestinationName string `json:"destinationName"`
+
+	// The age after which the latest base backup in the archive tier is
+	// considered outdated, causing the next completed base backup to be
+	// exported there too (i.e. '30d'). It is expressed in the form of
+	// `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
+	// days, weeks, months.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	ArchiveAfter string `json:"archiveAfter"`
+
+	// RetentionPolicy is the retention policy to be used for the base
+	// backups and WALs in the archive tier (i.e. '12m'), in the same
+	// form of `archiveAfter`. It must be longer than the retention
+	// policy of `barmanObjectStore`.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+}
+
+// BackupHooksConfiguration contains the commands executed around a base
+// backup. Every hook receives the context of the backup in the
+// `CNPG_CLUSTER_NAME`, `CNPG_NAMESPACE`, `CNPG_BACKUP_NAME` and
+// `CNPG_BACKUP_PHASE` environment variables, while the post-backup hooks
+// also receive `CNPG_BACKUP_ID` or `CNPG_BACKUP_ERROR`, depending on the
+// outcome of the backup.
+type BackupHooksConfiguration struct {
+	// The hooks executed, in order, before starting the base backup.
+	// When one of them fails, or doesn't terminate within its timeout,
+	// the following ones are skipped and the backup is marked as failed
+	// without being taken.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PreBackup []BackupHook `json:"preBackup,omitempty"`
+
+	// The hooks executed, in order, when the backup is terminated, whatever
+	// its outcome, including the failure of a pre-backup hook. Their failures
+	// are reported as events, but don't change the outcome of the backup.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PostBackup []BackupHook `json:"postBackup,omitempty"`
+}
+
+// BackupHook is a command executed before or after a base backup
+type BackupHook struct {
+	// The name of the hook, used in the logs and in the events
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The command to be executed and its arguments. The command is not
+	// executed in a shell, so it needs to be explicitly invoked when needed.
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// The number of seconds after which the hook is terminated and
+	// considered failed. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// WalArchiveBacklogAction is the reaction of the operator when the
+// primary has too many WAL files waiting to be archived
+// +kubebuilder:validation:Enum=alert;throttle
+type WalArchiveBacklogAction string
+
+const (
+	// WalArchiveBacklogActionAlert means that the backlog is only
+	// reported in the status of the cluster
+	WalArchiveBacklogActionAlert WalArchiveBacklogAction = "alert"
+
+	// WalArchiveBacklogActionThrottle means that the write transactions
+	// on the primary are stopped until the backlog is back within
+	// the threshold, in addition to reporting it
+	WalArchiveBacklogActionThrottle WalArchiveBacklogAction = "throttle"
+)
+
+// WalArchiveBacklogConfiguration contains the configuration of the
+// monitoring of the WAL files waiting to be archived by the primary
+type WalArchiveBacklogConfiguration struct {
+	// The number of WAL files waiting to be archived on the primary above
+	// which the `WALArchiveBacklogWithinThreshold` condition is set to false
+	// +kubebuilder:validation:Minimum=1
+	MaxReadyWALFiles int32 `json:"maxReadyWALFiles"`
+
+	// The reaction when the threshold is exceeded: `alert` (default) only
+	// reports it in the condition, `throttle` also makes the primary
+	// read-only, terminating the write transactions in progress, until
+	// the backlog is back within 80% of the threshold
+	// +kubebuilder:default:=alert
+	// +optional
+	Action WalArchiveBacklogAction `json:"action,omitempty"`
+}
+
+// WalArchiveCheckConfiguration contains the configuration of the
+// periodic check of the WAL archive
+type WalArchiveCheckConfiguration struct {
+	// Enabled tells the primary instance to periodically check that the
+	// WAL archive contains every WAL file since the beginning of the oldest
+	// base backup
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The number of seconds between two checks. Every check downloads
+	// the WAL files archived since the previous one, so this option
+	// controls the calls made to the object store. Defaults to 3600
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	Interval int `json:"interval,omitempty"`
+}
+
+// WalArchiveDestination is an additional object store where the WAL
+// files are archived
+type WalArchiveDestination struct {
+	// The name of the destination, used to identify it in the logs
+	// and in the status of the cluster
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The configuration of the object store. The `data` section
+	// is not used, as base backups are not taken on this destination.
+	// Custom endpoint CA bundles are not supported.
+	BarmanObjectStoreConfiguration `json:",inline"`
+}
+
+// BackupVerificationConfiguration contains the configuration of the
+// periodic backup verification process
+type BackupVerificationConfiguration struct {
+	// Enabled tells the operator to periodically verify the latest base
+	// backup, restoring it together with the required WALs into a
+	// throwaway instance
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The schedule does not follow the same format used in Kubernetes CronJobs
+	// as it includes an additional seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format.
+	// Defaults to once a week, on Sunday at midnight
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// AfterBackup tells the operator to verify every base backup taken
+	// on the object store as soon as it is completed, regardless of the
+	// schedule. The outcome is also recorded in the status of the Backup
+	// +kubebuilder:default:=false
+	// +optional
+	AfterBackup bool `json:"afterBackup,omitempty"`
+
+	// ScratchSizeLimit is the maximum size of each of the ephemeral volumes
+	// where the backup is restored. When not set, every volume is limited to
+	// the size requested for the corresponding volume of the cluster
+	// +optional
+	ScratchSizeLimit *resource.Quantity `json:"scratchSizeLimit,omitempty"`
+}
+
+// WalRestoreMaxParallelConfiguration is the number of WAL files to be
+// restored in parallel in the different phases of a PostgreSQL instance
+type WalRestoreMaxParallelConfiguration struct {
+	// Number of WAL files to be restored in parallel while the instance
+	// is bootstrapping, that is until it is ready, like when a new
+	// replica is catching up with the primary.
+	// If not specified, `maxParallel` is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Bootstrap int `json:"bootstrap,omitempty"`
+
+	// Number of WAL files to be restored in parallel once the instance
+	// is ready, when the WAL files are usually received via streaming
+	// replication and restored only when it is not available.
+	// If not specified, `maxParallel` is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Streaming int `json:"streaming,omitempty"`
 }
 
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
 	// Compress a WAL file before sending it to the object store. Available
-	// options are empty string (no compression, default), `gzip`, `bzip2` or `snappy`.
-	// +kubebuilder:validation:Enum=gzip;bzip2;snappy
+	// options are empty string (no compression, default), `gzip`, `bzip2`,
+	// `snappy`, `zstd`, `lz4` or `xz`. The latter three require
+	// Barman 3.10 or higher in the operand image.
+	// +kubebuilder:validation:Enum=gzip;bzip2;snappy;zstd;lz4;xz
 	// +optional
 	Compression CompressionType `json:"compression,omitempty"`
 
+	// The compression level to be used for the WAL files. Its range
+	// depends on the algorithm: 1-9 for `gzip`, `bzip2` and `xz`,
+	// 1-22 for `zstd` and 1-12 for `lz4`. It is not supported
+	// with `snappy`, and it requires Barman 3.12 or higher in the
+	// operand image. If not specified, the default level of the
+	// algorithm is used.
+	// +optional
+	CompressionLevel *int `json:"compressionLevel,omitempty"`
+
 	// Whenever to force the encryption of files (if the bucket is
 	// not already configured for that).
 	// Allowed options are empty string (use the bucket policy, default),
@@ -2179,6 +4771,13 @@ type WalBackupConfiguration struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// The number of WAL files to be restored in parallel depending on the
+	// phase of the PostgreSQL instance, overriding `maxParallel` when a
+	// standby is fetching WAL files from the object store
+	// +optional
+	RestoreMaxParallel *WalRestoreMaxParallelConfiguration `json:"restoreMaxParallel,omitempty"`
+
 	// AdditionalCommandArgs represents additional arguments that can be appended
 	// to the 'barman-cloud-wal-archive' command-line invocation. These arguments
 	// provide flexibility to customize the backup process further according to
@@ -2228,6 +4827,12 @@ type DataBackupConfiguration struct {
 	// +optional
 	ImmediateCheckpoint bool `json:"immediateCheckpoint,omitempty"`
 
+	// The maximum amount of data, in bytes per second, that can be uploaded
+	// to the object store while taking a backup (i.e. `50Mi`). Requires
+	// Barman >= 3.10, it is ignored by older versions. Unlimited by default.
+	// +optional
+	MaxBandwidth *resource.Quantity `json:"maxBandwidth,omitempty"`
+
 	// AdditionalCommandArgs represents additional arguments that can be appended
 	// to the 'barman-cloud-backup' command-line invocation. These arguments
 	// provide flexibility to customize the backup process further according to
@@ -2349,6 +4954,192 @@ type MonitoringConfiguration struct {
 	// The list of relabelings for the `PodMonitor`. Applied to samples before scraping.
 	// +optional
 	PodMonitorRelabelConfigs []monitoringv1.RelabelConfig `json:"podMonitorRelabelings,omitempty"`
+
+	// The `PrometheusRule` with the default alerts of the cluster
+	// +optional
+	PrometheusRule *PrometheusRuleConfiguration `json:"prometheusRule,omitempty"`
+
+	// The metrics about the size and the estimated bloat of the
+	// largest tables of every database
+	// +optional
+	TableStatistics *TableStatisticsConfiguration `json:"tableStatistics,omitempty"`
+
+	// The thresholds on the age of the oldest unfrozen transaction or
+	// multixact ID of the databases, above which the
+	// `TransactionIDAgeWithinThreshold` condition is set to false
+	// +optional
+	TransactionIDWraparound *TransactionIDWraparoundConfiguration `json:"transactionIDWraparound,omitempty"`
+
+	// The projection of the disk usage of the volumes of the instances,
+	// setting the `DiskTimeToFullAboveThreshold` condition to false when
+	// a volume is going to be full soon
+	// +optional
+	DiskUsage *DiskUsageConfiguration `json:"diskUsage,omitempty"`
+}
+
+// DiskUsageConfiguration contains the configuration of the projection
+// of the disk usage. The instances periodically sample the usage of their
+// volumes, and compute their growth rate over the projection window
+type DiskUsageConfiguration struct {
+	// The number of seconds of samples used to compute the growth rate
+	// of the volumes. Defaults to 3600
+	// +kubebuilder:validation:Minimum=300
+	// +optional
+	ProjectionWindow int `json:"projectionWindow,omitempty"`
+
+	// The number of seconds below which the projected time needed to
+	// fill a volume sets the condition to false. Defaults to 86400
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	TimeToFullThreshold int `json:"timeToFullThreshold,omitempty"`
+}
+
+// PasswordRotationConfiguration contains the schedule of the rotation of
+// the passwords stored in the secrets generated by the operator. The new
+// passwords are written in the secrets and applied to PostgreSQL by the
+// primary instance
+type PasswordRotationConfiguration struct {
+	// The number of days between two rotations of the passwords
+	// +kubebuilder:validation:Minimum=1
+	IntervalDays int `json:"intervalDays"`
+
+	// The number of minutes before a rotation during which the new
+	// passwords are published in the secrets, under the `next-password`
+	// key, while PostgreSQL still accepts the current ones
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OverlapMinutes int `json:"overlapMinutes,omitempty"`
+}
+
+// TransactionIDWraparoundConfiguration contains the thresholds on the age
+// of the oldest unfrozen transaction ID, warning about the risk of a
+// transaction ID wraparound before PostgreSQL stops accepting writes
+type TransactionIDWraparoundConfiguration struct {
+	// The age above which the condition reports a warning.
+	// Defaults to 1000000000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	WarningAge int64 `json:"warningAge,omitempty"`
+
+	// The age above which the condition reports a critical risk of
+	// wraparound. Defaults to 1500000000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	CriticalAge int64 `json:"criticalAge,omitempty"`
+}
+
+// TableStatisticsConfiguration contains the configuration of the metrics
+// about the size and the estimated bloat of the tables. They are sampled
+// by the replicas, to avoid any load on the primary, unless the cluster
+// has a single instance
+type TableStatisticsConfiguration struct {
+	// Enabled tells the instances to report the size of the largest
+	// tables of every database
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// EstimateBloat tells the instances to also report the bloat of the
+	// tables, estimated from the planner statistics, which requires
+	// reading the statistics of every column
+	// +kubebuilder:default:=false
+	// +optional
+	EstimateBloat bool `json:"estimateBloat,omitempty"`
+
+	// The number of seconds between two samples. The metrics keep the
+	// values of the last sample in between. Defaults to 900
+	// +kubebuilder:validation:Minimum=60
+	// +optional
+	Interval int `json:"interval,omitempty"`
+
+	// The number of the largest tables reported for each database.
+	// Defaults to 10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	MaxTables int `json:"maxTables,omitempty"`
+}
+
+// PrometheusAlertName is the name of one of the default alerts of a cluster
+// +kubebuilder:validation:Enum=CNPGClusterReplicationLag;CNPGClusterArchiveFailing;CNPGClusterDiskPressure
+type PrometheusAlertName string
+
+const (
+	// PrometheusAlertReplicationLag fires when a replica is lagging
+	// behind the primary
+	PrometheusAlertReplicationLag PrometheusAlertName = "CNPGClusterReplicationLag"
+
+	// PrometheusAlertArchiveFailing fires when the WAL files can't be
+	// archived
+	PrometheusAlertArchiveFailing PrometheusAlertName = "CNPGClusterArchiveFailing"
+
+	// PrometheusAlertDiskPressure fires when a volume of an instance is
+	// running out of space
+	PrometheusAlertDiskPressure PrometheusAlertName = "CNPGClusterDiskPressure"
+)
+
+// PrometheusRuleConfiguration configures the `PrometheusRule` containing
+// the default alerts of the cluster
+type PrometheusRuleConfiguration struct {
+	// Enable or disable the `PrometheusRule`
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The customizations of the default alerts. The alerts which are
+	// not listed here are created with their default configuration
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Alerts []PrometheusAlertConfiguration `json:"alerts,omitempty"`
+}
+
+// PrometheusAlertConfiguration customizes one of the default alerts
+type PrometheusAlertConfiguration struct {
+	// The name of the alert
+	Name PrometheusAlertName `json:"name"`
+
+	// Disabled removes the alert from the `PrometheusRule`
+	// +kubebuilder:default:=false
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// The PromQL expression replacing the default one
+	// +optional
+	Expr string `json:"expr,omitempty"`
+
+	// How long the expression needs to be true before the alert fires
+	// +optional
+	For *monitoringv1.Duration `json:"for,omitempty"`
+
+	// The severity of the alert, replacing the default one
+	// +optional
+	Severity string `json:"severity,omitempty"`
+
+	// The labels added to the alert
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// The annotations added to the alert
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GetAlert gets the customization of an alert, nil if there is none
+func (configuration *PrometheusRuleConfiguration) GetAlert(name PrometheusAlertName) *PrometheusAlertConfiguration {
+	if configuration == nil {
+		return nil
+	}
+
+	for i := range configuration.Alerts {
+		if configuration.Alerts[i].Name == name {
+			return &configuration.Alerts[i]
+		}
+	}
+
+	return nil
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -2356,6 +5147,79 @@ func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
 }
 
+// AreTableStatisticsEnabled checks whether the metrics about the
+// size of the tables have been requested
+func (m *MonitoringConfiguration) AreTableStatisticsEnabled() bool {
+	return m != nil && m.TableStatistics != nil && m.TableStatistics.Enabled
+}
+
+// GetTableStatisticsInterval gets the time between two samples of the
+// table statistics, defaulting to DefaultTableStatisticsInterval seconds
+func (m *MonitoringConfiguration) GetTableStatisticsInterval() time.Duration {
+	interval := DefaultTableStatisticsInterval
+	if m != nil && m.TableStatistics != nil && m.TableStatistics.Interval > 0 {
+		interval = m.TableStatistics.Interval
+	}
+
+	return time.Duration(interval) * time.Second
+}
+
+// GetTransactionIDWarningAge gets the age of the oldest unfrozen
+// transaction ID above which a warning is reported, defaulting to
+// DefaultTransactionIDWarningAge
+func (m *MonitoringConfiguration) GetTransactionIDWarningAge() int64 {
+	if m != nil && m.TransactionIDWraparound != nil && m.TransactionIDWraparound.WarningAge > 0 {
+		return m.TransactionIDWraparound.WarningAge
+	}
+
+	return DefaultTransactionIDWarningAge
+}
+
+// GetTransactionIDCriticalAge gets the age of the oldest unfrozen
+// transaction ID above which the risk of wraparound is critical,
+// defaulting to DefaultTransactionIDCriticalAge
+func (m *MonitoringConfiguration) GetTransactionIDCriticalAge() int64 {
+	if m != nil && m.TransactionIDWraparound != nil && m.TransactionIDWraparound.CriticalAge > 0 {
+		return m.TransactionIDWraparound.CriticalAge
+	}
+
+	return DefaultTransactionIDCriticalAge
+}
+
+// GetDiskUsageProjectionWindow gets the time span of the samples used to
+// compute the growth rate of the volumes, defaulting to
+// DefaultDiskUsageProjectionWindow seconds
+func (m *MonitoringConfiguration) GetDiskUsageProjectionWindow() time.Duration {
+	window := DefaultDiskUsageProjectionWindow
+	if m != nil && m.DiskUsage != nil && m.DiskUsage.ProjectionWindow > 0 {
+		window = m.DiskUsage.ProjectionWindow
+	}
+
+	return time.Duration(window) * time.Second
+}
+
+// GetDiskTimeToFullThreshold gets the projected time needed to fill a
+// volume below which it is reported, defaulting to
+// DefaultDiskTimeToFullThreshold seconds
+func (m *MonitoringConfiguration) GetDiskTimeToFullThreshold() time.Duration {
+	threshold := DefaultDiskTimeToFullThreshold
+	if m != nil && m.DiskUsage != nil && m.DiskUsage.TimeToFullThreshold > 0 {
+		threshold = m.DiskUsage.TimeToFullThreshold
+	}
+
+	return time.Duration(threshold) * time.Second
+}
+
+// GetTableStatisticsMaxTables gets the number of tables reported for
+// each database, defaulting to DefaultTableStatisticsMaxTables
+func (m *MonitoringConfiguration) GetTableStatisticsMaxTables() int {
+	if m != nil && m.TableStatistics != nil && m.TableStatistics.MaxTables > 0 {
+		return m.TableStatistics.MaxTables
+	}
+
+	return DefaultTableStatisticsMaxTables
+}
+
 // ExternalCluster represents the connection parameters to an
 // external cluster which is used in the other sections of the configuration
 type ExternalCluster struct {
@@ -2394,6 +5258,11 @@ type ExternalCluster struct {
 	// The configuration for the barman-cloud tool suite
 	// +optional
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+
+	// The archive tier of the object store, where the base backup to
+	// recover is looked up when it can't be found in `barmanObjectStore`
+	// +optional
+	ArchiveObjectStore *BarmanObjectStoreConfiguration `json:"archiveObjectStore,omitempty"`
 }
 
 // AppendAdditionalCommandArgs adds custom arguments as barman-cloud-backup command-line options
@@ -2412,6 +5281,27 @@ func (cfg *WalBackupConfiguration) AppendAdditionalCommandArgs(options []string)
 	return appendAdditionalCommandArgs(cfg.AdditionalCommandArgs, options)
 }
 
+// GetRestoreMaxParallel gets the number of WAL files to be restored in
+// parallel, depending on whether the instance is still bootstrapping
+func (cfg *WalBackupConfiguration) GetRestoreMaxParallel(bootstrapping bool) int {
+	if cfg == nil {
+		return 1
+	}
+
+	maxParallel := cfg.MaxParallel
+	if cfg.RestoreMaxParallel != nil {
+		phaseMaxParallel := cfg.RestoreMaxParallel.Streaming
+		if bootstrapping {
+			phaseMaxParallel = cfg.RestoreMaxParallel.Bootstrap
+		}
+		if phaseMaxParallel > 0 {
+			maxParallel = phaseMaxParallel
+		}
+	}
+
+	return max(maxParallel, 1)
+}
+
 func appendAdditionalCommandArgs(additionalCommandArgs []string, options []string) []string {
 	optionKeys := map[string]bool{}
 	for _, option := range options {
@@ -2660,7 +5550,7 @@ func (roleConfiguration *RoleConfiguration) GetRoleInherit() bool {
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +kubebuilder:subresource:status
-// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances
+// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances,selectorpath=.status.selector
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Instances",type="integer",JSONPath=".status.instances",description="Number of instances"
 // +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyInstances",description="Number of ready instances"
@@ -2827,6 +5717,30 @@ func (cluster *Cluster) GetPostgresqlMajorVersion() (int, error) {
 	return postgres.GetPostgresMajorVersion(version), nil
 }
 
+// CheckBackupMajorVersion checks if the passed backup can be restored
+// using the PostgreSQL image of this cluster. A physical backup can only
+// be restored by the same PostgreSQL major version that produced it.
+// Backups not reporting their major version are accepted.
+func (cluster *Cluster) CheckBackupMajorVersion(backup *Backup) error {
+	if backup == nil || backup.Status.MajorVersion == 0 {
+		return nil
+	}
+
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return err
+	}
+
+	if imageMajorVersion := version / 10000; imageMajorVersion != backup.Status.MajorVersion {
+		return fmt.Errorf(
+			"backup %s has been taken with PostgreSQL %d and can't be restored using PostgreSQL %d: "+
+				"use an image with the same major version of the backup",
+			backup.Status.BackupID, backup.Status.MajorVersion, imageMajorVersion)
+	}
+
+	return nil
+}
+
 // GetImagePullSecret get the name of the pull secret to use
 // to download the PostgreSQL image
 func (cluster *Cluster) GetImagePullSecret() string {
@@ -3032,6 +5946,60 @@ func (cluster *Cluster) GetServiceAnyName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
 }
 
+// GetBackupVerificationJobName returns the name of the job used to verify
+// the latest base backup of this cluster
+func (cluster *Cluster) GetBackupVerificationJobName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, BackupVerificationJobSuffix)
+}
+
+// GetImportSourceClusterName returns the name of the temporary cluster
+// used to import a database from a base backup
+func (cluster *Cluster) GetImportSourceClusterName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ImportSourceClusterSuffix)
+}
+
+// ShouldImportFromBackup returns true if the database of this cluster
+// has to be imported from a base backup, recovered into a temporary cluster
+func (cluster *Cluster) ShouldImportFromBackup() bool {
+	return cluster.Spec.Bootstrap != nil &&
+		cluster.Spec.Bootstrap.InitDB != nil &&
+		cluster.Spec.Bootstrap.InitDB.Import != nil &&
+		cluster.Spec.Bootstrap.InitDB.Import.Source.FromBackup != nil
+}
+
+// GetImportSourceExternalCluster gets the connection to the temporary
+// cluster used to import a database from a base backup, as the superuser
+func (cluster *Cluster) GetImportSourceExternalCluster() ExternalCluster {
+	sourceClusterName := cluster.GetImportSourceClusterName()
+	return ExternalCluster{
+		Name: sourceClusterName,
+		ConnectionParameters: map[string]string{
+			"host":    fmt.Sprintf("%v%v", sourceClusterName, ServiceReadWriteSuffix),
+			"user":    "postgres",
+			"sslmode": "require",
+		},
+		Password: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: fmt.Sprintf("%v%v", sourceClusterName, SuperUserSecretSuffix),
+			},
+			Key: "password",
+		},
+	}
+}
+
+// GetInstances gets the number of instances required in the cluster, which
+// is the one chosen by the automatic scaling of the replicas, when enabled,
+// or the one in the specification otherwise
+func (cluster *Cluster) GetInstances() int {
+	if cluster.Spec.ReplicaAutoscaling != nil {
+		if desiredInstances := cluster.Status.ReplicaAutoscaling.GetDesiredInstances(); desiredInstances > 0 {
+			return desiredInstances
+		}
+	}
+
+	return cluster.Spec.Instances
+}
+
 // GetServiceReadName return the default name of the service that is used for
 // read transactions (including the primary)
 func (cluster *Cluster) GetServiceReadName() string {
@@ -3088,6 +6056,15 @@ func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	return DefaultMaxSwitchoverDelay
 }
 
+// IsDrainingPrimaryConnections checks whether the connections to the current
+// primary are being drained before shutting it down for a switchover
+func (cluster *Cluster) IsDrainingPrimaryConnections() bool {
+	return cluster.Spec.SwitchoverDrainTimeout > 0 &&
+		cluster.Status.TargetPrimary != "" &&
+		cluster.Status.TargetPrimary != PendingFailoverMarker &&
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary
+}
+
 // GetPrimaryUpdateStrategy get the cluster primary update strategy,
 // defaulting to unsupervised
 func (cluster *Cluster) GetPrimaryUpdateStrategy() PrimaryUpdateStrategy {
@@ -3110,6 +6087,31 @@ func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
 	return strategy
 }
 
+// GetPrimaryPodDisruptionBudgetPolicy gets the disruption policy for the
+// primary instance, if configured
+func (cluster *Cluster) GetPrimaryPodDisruptionBudgetPolicy() *PodDisruptionBudgetPolicy {
+	if cluster.Spec.PodDisruptionBudget == nil || cluster.Spec.PodDisruptionBudget.Primary.IsEmpty() {
+		return nil
+	}
+
+	return cluster.Spec.PodDisruptionBudget.Primary
+}
+
+// GetReplicasPodDisruptionBudgetPolicy gets the disruption policy for the
+// replicas, if configured
+func (cluster *Cluster) GetReplicasPodDisruptionBudgetPolicy() *PodDisruptionBudgetPolicy {
+	if cluster.Spec.PodDisruptionBudget == nil || cluster.Spec.PodDisruptionBudget.Replicas.IsEmpty() {
+		return nil
+	}
+
+	return cluster.Spec.PodDisruptionBudget.Replicas
+}
+
+// IsEmpty checks if the policy doesn't set any constraint
+func (policy *PodDisruptionBudgetPolicy) IsEmpty() bool {
+	return policy == nil || (policy.MinAvailable == nil && policy.MaxUnavailable == nil)
+}
+
 // GetEnablePDB get the cluster EnablePDB value, defaults to true
 func (cluster *Cluster) GetEnablePDB() bool {
 	if cluster.Spec.EnablePDB == nil {
@@ -3143,6 +6145,13 @@ func (cluster *Cluster) IsReusePVCEnabled() bool {
 	return reusePVC
 }
 
+// IsInstanceBootstrapping checks if a given instance has not been
+// reported as ready yet, like a new replica which is still catching
+// up with the primary
+func (cluster *Cluster) IsInstanceBootstrapping(instance string) bool {
+	return !slices.Contains(cluster.Status.InstancesStatus[utils.PodHealthy], instance)
+}
+
 // IsInstanceFenced check if in a given instance should be fenced
 func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
@@ -3156,6 +6165,26 @@ func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	return fencedInstances.Has(instance)
 }
 
+// GetFencingMode gets how the fenced instances of the cluster are isolated,
+// which is stopping PostgreSQL unless the read-only mode has been requested
+func (cluster *Cluster) GetFencingMode() utils.FencingMode {
+	return utils.GetFencingMode(cluster.Annotations)
+}
+
+// GetInstanceFencingMode gets how a given instance should be fenced, or an
+// empty string if it should not. The quarantined instances are always
+// stopped, as they would keep crashing otherwise
+func (cluster *Cluster) GetInstanceFencingMode(instance string) utils.FencingMode {
+	if !cluster.IsInstanceFenced(instance) {
+		return ""
+	}
+	if slices.Contains(cluster.Status.QuarantinedInstances, instance) {
+		return utils.FencingModeStop
+	}
+
+	return cluster.GetFencingMode()
+}
+
 // ShouldResizeInUseVolumes is true when we should resize PVC we already
 // created
 func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
@@ -3206,24 +6235,47 @@ func (cluster *Cluster) ShouldCreateApplicationDatabase() bool {
 		cluster.ShouldPgBaseBackupCreateApplicationDatabase()
 }
 
-// ShouldInitDBRunPostInitApplicationSQLRefs returns true if for this cluster,
-// during the bootstrap phase using initDB, we need to run post application
-// SQL files from provided references.
-func (cluster *Cluster) ShouldInitDBRunPostInitApplicationSQLRefs() bool {
-	if cluster.Spec.Bootstrap == nil {
+// ShouldInitDBRunPostInitApplicationSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post application
+// SQL files from provided references.
+func (cluster *Cluster) ShouldInitDBRunPostInitApplicationSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
+		return false
+	}
+
+	return cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.HasReferences()
+}
+
+// ShouldInitDBRunPostInitSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post init
+// SQL files in the `postgres` database from provided references.
+func (cluster *Cluster) ShouldInitDBRunPostInitSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
 		return false
 	}
 
-	if cluster.Spec.Bootstrap.InitDB == nil {
+	return cluster.Spec.Bootstrap.InitDB.PostInitSQLRefs.HasReferences()
+}
+
+// ShouldInitDBRunPostInitTemplateSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post init
+// SQL files in the `template1` database from provided references.
+func (cluster *Cluster) ShouldInitDBRunPostInitTemplateSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
 		return false
 	}
 
-	if cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs == nil {
+	return cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQLRefs.HasReferences()
+}
+
+// HasReferences returns true if at least a Secret or a ConfigMap
+// is referenced
+func (refs *SQLRefs) HasReferences() bool {
+	if refs == nil {
 		return false
 	}
 
-	return (len(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.ConfigMapRefs) != 0 ||
-		len(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.SecretRefs) != 0)
+	return len(refs.ConfigMapRefs) != 0 || len(refs.SecretRefs) != 0
 }
 
 // ShouldInitDBCreateApplicationDatabase returns true if the application database needs to be created during initdb
@@ -3285,11 +6337,49 @@ func (cluster *Cluster) ShouldCreateProjectedVolume() bool {
 	return cluster.Spec.ProjectedVolumeTemplate != nil
 }
 
+// ShouldMountWalRestoreCache returns whether we should mount the shared
+// WAL restore cache volume, which happens only once the operator verified
+// the claim is usable, so that the instances can always start
+func (cluster *Cluster) ShouldMountWalRestoreCache() bool {
+	return cluster.Spec.WalRestoreCache != nil &&
+		cluster.Status.WalRestoreCacheClaim == cluster.Spec.WalRestoreCache.ClaimName
+}
+
+// DefaultWalRestoreCacheRetentionPeriod is how long the WAL files are
+// kept in the shared WAL restore cache, if not specified
+const DefaultWalRestoreCacheRetentionPeriod = time.Hour
+
+// GetRetentionPeriod gets how long the WAL files are kept in the
+// shared WAL restore cache. Invalid durations are rejected by the
+// webhook, and are replaced by the default here
+func (configuration *WalRestoreCacheConfiguration) GetRetentionPeriod() time.Duration {
+	if configuration == nil || configuration.RetentionPeriod == "" {
+		return DefaultWalRestoreCacheRetentionPeriod
+	}
+
+	retentionPeriod, err := time.ParseDuration(configuration.RetentionPeriod)
+	if err != nil || retentionPeriod <= 0 {
+		return DefaultWalRestoreCacheRetentionPeriod
+	}
+
+	return retentionPeriod
+}
+
 // ShouldCreateWalArchiveVolume returns whether we should create the wal archive volume
 func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// GetSidecarVolumeNames returns the names of the volumes of the instance
+// pods which can be mounted by the sidecar containers
+func (cluster *Cluster) GetSidecarVolumeNames() []string {
+	volumeNames := []string{"pgdata", "scratch-data", "shm"}
+	if cluster.ShouldCreateWalArchiveVolume() {
+		volumeNames = append(volumeNames, "pg-wal")
+	}
+	return volumeNames
+}
+
 // ShouldPromoteFromReplicaCluster returns true if the cluster should promote
 func (cluster *Cluster) ShouldPromoteFromReplicaCluster() bool {
 	// If there's no replica cluster configuration there's no
@@ -3489,6 +6579,13 @@ func (cluster *Cluster) IsPodMonitorEnabled() bool {
 	return false
 }
 
+// IsPrometheusRuleEnabled checks if the PrometheusRule object needs to be created
+func (cluster *Cluster) IsPrometheusRuleEnabled() bool {
+	return cluster.Spec.Monitoring != nil &&
+		cluster.Spec.Monitoring.PrometheusRule != nil &&
+		cluster.Spec.Monitoring.PrometheusRule.Enabled
+}
+
 // GetEnableSuperuserAccess returns if the superuser access is enabled or not
 func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	if cluster.Spec.EnableSuperuserAccess != nil {
@@ -3498,6 +6595,26 @@ func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	return false
 }
 
+// GetPasswordRotationInterval gets the time between two rotations of the
+// generated passwords, zero when the scheduled rotation is disabled
+func (cluster *Cluster) GetPasswordRotationInterval() time.Duration {
+	if cluster.Spec.PasswordRotation == nil || cluster.Spec.PasswordRotation.IntervalDays <= 0 {
+		return 0
+	}
+
+	return time.Duration(cluster.Spec.PasswordRotation.IntervalDays) * 24 * time.Hour
+}
+
+// GetPasswordRotationOverlap gets the time during which the new passwords
+// are published in the secrets before being applied
+func (cluster *Cluster) GetPasswordRotationOverlap() time.Duration {
+	if cluster.Spec.PasswordRotation == nil || cluster.Spec.PasswordRotation.OverlapMinutes <= 0 {
+		return 0
+	}
+
+	return time.Duration(cluster.Spec.PasswordRotation.OverlapMinutes) * time.Minute
+}
+
 // LogTimestampsWithMessage prints useful information about timestamps in stdout
 func (cluster *Cluster) LogTimestampsWithMessage(ctx context.Context, logMessage string) {
 	contextLogger := log.FromContext(ctx)
@@ -3596,6 +6713,90 @@ func (cluster *Cluster) GetSeccompProfile() *corev1.SeccompProfile {
 	}
 }
 
+// DefaultMaintenanceWindowDuration is how long a maintenance window
+// stays open, if not specified
+const DefaultMaintenanceWindowDuration = time.Hour
+
+// GetDuration gets how long the maintenance window stays open. Invalid
+// durations are rejected by the webhook, and are replaced by the default here
+func (window *MaintenanceWindowConfiguration) GetDuration() time.Duration {
+	if window.Duration == "" {
+		return DefaultMaintenanceWindowDuration
+	}
+
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil || duration <= 0 {
+		return DefaultMaintenanceWindowDuration
+	}
+
+	return duration
+}
+
+// IsOpen checks whether the maintenance window is open at the passed time
+func (window *MaintenanceWindowConfiguration) IsOpen(now time.Time) bool {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return false
+	}
+
+	return !schedule.Next(now.Add(-window.GetDuration())).After(now)
+}
+
+// GetNextStart gets when the maintenance window opens after the passed time
+func (window *MaintenanceWindowConfiguration) GetNextStart(now time.Time) (time.Time, error) {
+	schedule, err := cron.Parse(window.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return schedule.Next(now), nil
+}
+
+// GetParameterChangeRestartStrategy gets the strategy followed to restart the
+// instances when changing the PostgreSQL parameters
+func (cluster *Cluster) GetParameterChangeRestartStrategy() ParameterChangeRestartStrategy {
+	if cluster.Spec.ParameterChangeRestart == nil || cluster.Spec.ParameterChangeRestart.Strategy == "" {
+		return ParameterChangeRestartStrategyImmediate
+	}
+
+	return cluster.Spec.ParameterChangeRestart.Strategy
+}
+
+// IsParameterChangeRestartAllowed checks whether the instances can be
+// restarted at the passed time to apply the changes to the PostgreSQL
+// parameters, according to the restart strategy
+func (cluster *Cluster) IsParameterChangeRestartAllowed(now time.Time) bool {
+	if _, approved := cluster.Annotations[utils.RestartApprovalAnnotationName]; approved {
+		return true
+	}
+
+	switch cluster.GetParameterChangeRestartStrategy() {
+	case ParameterChangeRestartStrategyManual:
+		return false
+	case ParameterChangeRestartStrategyMaintenanceWindow:
+		window := cluster.Spec.ParameterChangeRestart.MaintenanceWindow
+		return window != nil && window.IsOpen(now)
+	default:
+		return true
+	}
+}
+
+// GetInstancePlacement gets the placement rules to be applied to the pod
+// of an instance depending on its role, or nil if there are none. An instance
+// is the primary when it's the target primary, or when the cluster has no
+// primary yet, as it happens while bootstrapping it
+func (cluster *Cluster) GetInstancePlacement(instanceName string) *InstancePlacement {
+	if cluster.Spec.RolePlacement == nil {
+		return nil
+	}
+
+	if cluster.Status.TargetPrimary == "" || cluster.Status.TargetPrimary == instanceName {
+		return cluster.Spec.RolePlacement.Primary
+	}
+
+	return cluster.Spec.RolePlacement.Replica
+}
+
 // GetCoredumpFilter get the coredump filter value from the cluster annotation
 func (cluster *Cluster) GetCoredumpFilter() string {
 	value, ok := cluster.Annotations[utils.CoredumpFilter]
@@ -3636,6 +6837,459 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// IsBackupVerificationEnabled returns true if the periodic verification
+// of the base backups has been requested, false otherwise
+func (backupConfiguration *BackupConfiguration) IsBackupVerificationEnabled() bool {
+	return backupConfiguration != nil &&
+		backupConfiguration.Verification != nil &&
+		backupConfiguration.Verification.Enabled
+}
+
+// IsBackupVerificationAfterBackupEnabled returns true if the base backups
+// need to be verified as soon as they are completed, false otherwise
+func (backupConfiguration *BackupConfiguration) IsBackupVerificationAfterBackupEnabled() bool {
+	return backupConfiguration != nil &&
+		backupConfiguration.Verification != nil &&
+		backupConfiguration.Verification.AfterBackup
+}
+
+// GetBackupVerificationSchedule gets the schedule of the backup
+// verification, defaulting to DefaultBackupVerificationSchedule
+func (backupConfiguration *BackupConfiguration) GetBackupVerificationSchedule() string {
+	if backupConfiguration == nil ||
+		backupConfiguration.Verification == nil ||
+		backupConfiguration.Verification.Schedule == "" {
+		return DefaultBackupVerificationSchedule
+	}
+
+	return backupConfiguration.Verification.Schedule
+}
+
+// IsWalArchiveCheckEnabled returns true if the periodic check of the
+// WAL archive has been requested, false otherwise
+func (backupConfiguration *BackupConfiguration) IsWalArchiveCheckEnabled() bool {
+	return backupConfiguration.IsBarmanBackupConfigured() &&
+		backupConfiguration.WalArchiveCheck != nil &&
+		backupConfiguration.WalArchiveCheck.Enabled
+}
+
+// GetWalArchiveCheckInterval gets the time between two checks of the
+// WAL archive, defaulting to DefaultWalArchiveCheckInterval seconds
+func (backupConfiguration *BackupConfiguration) GetWalArchiveCheckInterval() time.Duration {
+	interval := DefaultWalArchiveCheckInterval
+	if backupConfiguration != nil &&
+		backupConfiguration.WalArchiveCheck != nil &&
+		backupConfiguration.WalArchiveCheck.Interval > 0 {
+		interval = backupConfiguration.WalArchiveCheck.Interval
+	}
+
+	return time.Duration(interval) * time.Second
+}
+
+// GetTargetRPO gets the target recovery point objective, returning
+// zero when it has not been set
+func (backupConfiguration *BackupConfiguration) GetTargetRPO() time.Duration {
+	if backupConfiguration == nil {
+		return 0
+	}
+
+	return time.Duration(backupConfiguration.TargetRPO) * time.Second
+}
+
+// GetWalArchiveBacklog gets the configuration of the monitoring of the
+// WAL files waiting to be archived, or nil if it is not enabled
+func (backupConfiguration *BackupConfiguration) GetWalArchiveBacklog() *WalArchiveBacklogConfiguration {
+	if backupConfiguration == nil {
+		return nil
+	}
+
+	return backupConfiguration.WalArchiveBacklog
+}
+
+// ShouldThrottle returns true if the write transactions need to be
+// throttled when the backlog exceeds the threshold
+func (configuration *WalArchiveBacklogConfiguration) ShouldThrottle() bool {
+	return configuration != nil && configuration.Action == WalArchiveBacklogActionThrottle
+}
+
+// GetPreBackupHooks gets the hooks to be executed before a base backup
+func (backupConfiguration *BackupConfiguration) GetPreBackupHooks() []BackupHook {
+	if backupConfiguration == nil || backupConfiguration.Hooks == nil {
+		return nil
+	}
+
+	return backupConfiguration.Hooks.PreBackup
+}
+
+// GetPostBackupHooks gets the hooks to be executed after a base backup
+func (backupConfiguration *BackupConfiguration) GetPostBackupHooks() []BackupHook {
+	if backupConfiguration == nil || backupConfiguration.Hooks == nil {
+		return nil
+	}
+
+	return backupConfiguration.Hooks.PostBackup
+}
+
+// GetArchiveDestination gets the WAL destination acting as archive
+// tier, or nil when the archive tier is not configured
+func (backupConfiguration *BackupConfiguration) GetArchiveDestination() *WalArchiveDestination {
+	if backupConfiguration == nil || backupConfiguration.Archive == nil {
+		return nil
+	}
+
+	for idx := range backupConfiguration.AdditionalWalDestinations {
+		destination := &backupConfiguration.AdditionalWalDestinations[idx]
+		if destination.Name == backupConfiguration.Archive.DestinationName {
+			return destination
+		}
+	}
+
+	return nil
+}
+
+// GetTimeout gets the time after which the hook is terminated,
+// defaulting to DefaultBackupHookTimeout
+func (hook *BackupHook) GetTimeout() time.Duration {
+	if hook.Timeout == 0 {
+		return DefaultBackupHookTimeout * time.Second
+	}
+
+	return time.Duration(hook.Timeout) * time.Second
+}
+
+// IsEnabled returns true if the automatic tuning of archive_timeout
+// has been requested, false otherwise
+func (configuration *AdaptiveArchiveTimeoutConfiguration) IsEnabled() bool {
+	return configuration != nil && configuration.Enabled
+}
+
+// GetMinTimeout gets the lowest value of archive_timeout,
+// defaulting to DefaultAdaptiveArchiveTimeoutMin seconds
+func (configuration *AdaptiveArchiveTimeoutConfiguration) GetMinTimeout() time.Duration {
+	timeout := int32(DefaultAdaptiveArchiveTimeoutMin)
+	if configuration != nil && configuration.MinTimeout > 0 {
+		timeout = configuration.MinTimeout
+	}
+
+	return time.Duration(timeout) * time.Second
+}
+
+// GetMaxTimeout gets the highest value of archive_timeout,
+// defaulting to DefaultAdaptiveArchiveTimeoutMax seconds
+func (configuration *AdaptiveArchiveTimeoutConfiguration) GetMaxTimeout() time.Duration {
+	timeout := int32(DefaultAdaptiveArchiveTimeoutMax)
+	if configuration != nil && configuration.MaxTimeout > 0 {
+		timeout = configuration.MaxTimeout
+	}
+
+	return time.Duration(timeout) * time.Second
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// default timeouts which have been set, in milliseconds. Invalid
+// durations are skipped, as they are rejected by the webhook
+func (configuration *TimeoutsConfiguration) GetParameters() map[string]string {
+	result := make(map[string]string)
+	if configuration == nil {
+		return result
+	}
+
+	for key, value := range map[string]string{
+		"statement_timeout":                   configuration.StatementTimeout,
+		"idle_in_transaction_session_timeout": configuration.IdleInTransactionSessionTimeout,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			continue
+		}
+		result[key] = fmt.Sprintf("%dms", duration.Milliseconds())
+	}
+
+	return result
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// slow query logging settings which have been set. The values are
+// expressed in the unit used by pg_settings, so that they can be
+// compared with the ones in effect on the instances. Invalid values
+// are skipped, as they are rejected by the webhook
+func (configuration *SlowQueryLoggingConfiguration) GetParameters() map[string]string {
+	result := make(map[string]string)
+	if configuration == nil {
+		return result
+	}
+
+	for key, value := range map[string]string{
+		"log_min_duration_statement":  configuration.MinDuration,
+		"log_autovacuum_min_duration": configuration.AutovacuumMinDuration,
+	} {
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			continue
+		}
+		result[key] = strconv.FormatInt(duration.Milliseconds(), 10)
+	}
+
+	if configuration.LockWaits != nil {
+		result["log_lock_waits"] = "off"
+		if *configuration.LockWaits {
+			result["log_lock_waits"] = "on"
+		}
+	}
+
+	if configuration.TempFilesMinSize != nil && configuration.TempFilesMinSize.Sign() >= 0 {
+		result["log_temp_files"] = strconv.FormatInt(configuration.TempFilesMinSize.Value()/1024, 10)
+	}
+
+	return result
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// `pg_stat_statements` settings which have been set
+func (configuration *StatStatementsConfiguration) GetParameters() map[string]string {
+	result := make(map[string]string)
+	if configuration == nil {
+		return result
+	}
+
+	if configuration.Max > 0 {
+		result["pg_stat_statements.max"] = strconv.Itoa(int(configuration.Max))
+	}
+
+	return result
+}
+
+// IsResetScheduled returns true if the periodic reset of the
+// `pg_stat_statements` statistics has been requested
+func (configuration *StatStatementsConfiguration) IsResetScheduled() bool {
+	return configuration != nil && configuration.ResetSchedule != ""
+}
+
+// IsDelayedReplica checks whether the instance with the passed
+// name applies the changes with a delay
+func (configuration *DelayedReplicasConfiguration) IsDelayedReplica(instanceName string) bool {
+	if configuration == nil {
+		return false
+	}
+
+	return slices.Contains(configuration.Instances, instanceName)
+}
+
+// GetManagedExtensions gets the extensions managed by the operator for
+// the cluster: the ones it supports, followed by the ones declared with
+// a recipe
+func (configuration *PostgresConfiguration) GetManagedExtensions() []postgres.ManagedExtension {
+	result := slices.Clone(postgres.ManagedExtensions)
+	if configuration == nil {
+		return result
+	}
+
+	for _, extension := range configuration.Extensions {
+		if extension.Recipe == nil {
+			continue
+		}
+		if _, found := postgres.GetManagedExtension(extension.Name); found {
+			// Prevented by the validation webhook
+			continue
+		}
+
+		result = append(result, postgres.ManagedExtension{
+			Name:                   extension.Name,
+			SharedPreloadLibraries: extension.Recipe.SharedPreloadLibraries,
+			SkipCreateExtension:    extension.Recipe.SkipCreateExtension,
+			DefaultParameters:      extension.Recipe.Parameters,
+		})
+	}
+
+	return result
+}
+
+// GetDeclaredExtensions gets the names of the extensions declared
+// to be managed by the operator, including `pg_stat_statements` when
+// its settings are present
+func (configuration *PostgresConfiguration) GetDeclaredExtensions() []string {
+	if configuration == nil || (len(configuration.Extensions) == 0 && configuration.StatStatements == nil) {
+		return nil
+	}
+
+	result := make([]string, 0, len(configuration.Extensions)+1)
+	for _, extension := range configuration.Extensions {
+		result = append(result, extension.Name)
+	}
+	if configuration.StatStatements != nil && !slices.Contains(result, StatStatementsExtensionName) {
+		result = append(result, StatStatementsExtensionName)
+	}
+	return result
+}
+
+// GetMinApplyDelay gets the value of `recovery_min_apply_delay` for
+// the delayed replicas, or an empty string if it is not valid
+func (configuration *DelayedReplicasConfiguration) GetMinApplyDelay() string {
+	if configuration == nil {
+		return ""
+	}
+
+	duration, err := time.ParseDuration(configuration.MinApplyDelay)
+	if err != nil || duration <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%dms", duration.Milliseconds())
+}
+
+// autovacuumProfileParameters are the parameters set by each
+// autovacuum profile, for every supported PostgreSQL version
+var autovacuumProfileParameters = map[AutovacuumProfile]map[string]string{
+	AutovacuumProfileAggressive: {
+		"autovacuum_naptime":              "15s",
+		"autovacuum_vacuum_scale_factor":  "0.05",
+		"autovacuum_analyze_scale_factor": "0.02",
+		"autovacuum_vacuum_cost_delay":    "2ms",
+		"autovacuum_vacuum_cost_limit":    "2000",
+	},
+	AutovacuumProfileBulkLoad: {
+		"autovacuum_naptime":              "5min",
+		"autovacuum_vacuum_scale_factor":  "0.4",
+		"autovacuum_analyze_scale_factor": "0.2",
+		"autovacuum_vacuum_cost_delay":    "2ms",
+		"autovacuum_vacuum_cost_limit":    "1000",
+	},
+}
+
+// autovacuumProfileInsertScaleFactor is the value of
+// autovacuum_vacuum_insert_scale_factor set by the profiles,
+// which is only available since PostgreSQL 13
+var autovacuumProfileInsertScaleFactor = map[AutovacuumProfile]string{
+	AutovacuumProfileAggressive: "0.05",
+	AutovacuumProfileBulkLoad:   "0.1",
+}
+
+// GetParameters gets the PostgreSQL parameters set by the autovacuum
+// profile for the passed PostgreSQL version, such as 170002
+func (configuration *AutovacuumConfiguration) GetParameters(postgresVersion int) map[string]string {
+	result := make(map[string]string)
+	if configuration == nil {
+		return result
+	}
+
+	for key, value := range autovacuumProfileParameters[configuration.Profile] {
+		result[key] = value
+	}
+	if value, ok := autovacuumProfileInsertScaleFactor[configuration.Profile]; ok && postgresVersion >= 130000 {
+		result["autovacuum_vacuum_insert_scale_factor"] = value
+	}
+
+	return result
+}
+
+// checkpointProfileParameters are the parameters set by each checkpoint profile
+var checkpointProfileParameters = map[CheckpointProfile]map[string]string{
+	CheckpointProfileWriteIntensive: {
+		"min_wal_size":                 "1GB",
+		"max_wal_size":                 "4GB",
+		"checkpoint_timeout":           "15min",
+		"checkpoint_completion_target": "0.9",
+	},
+	CheckpointProfileBulkLoad: {
+		"min_wal_size":                 "2GB",
+		"max_wal_size":                 "16GB",
+		"checkpoint_timeout":           "30min",
+		"checkpoint_completion_target": "0.9",
+	},
+}
+
+// GetParameters gets the PostgreSQL parameters set by the checkpoint profile
+func (configuration *CheckpointConfiguration) GetParameters() map[string]string {
+	result := make(map[string]string)
+	if configuration == nil {
+		return result
+	}
+
+	for key, value := range checkpointProfileParameters[configuration.Profile] {
+		result[key] = value
+	}
+
+	return result
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// TLS settings which have been set
+func (configuration *PostgresTLSConfiguration) GetParameters() map[string]string {
+	result := make(map[string]string)
+	if configuration == nil {
+		return result
+	}
+
+	if configuration.MinProtocolVersion != "" {
+		result["ssl_min_protocol_version"] = configuration.MinProtocolVersion
+	}
+	if len(configuration.Ciphers) > 0 {
+		result["ssl_ciphers"] = strings.Join(configuration.Ciphers, ":")
+	}
+
+	return result
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// memory settings which have been set, computed from the passed
+// memory limit of the container. It's empty when there's no limit
+func (configuration *MemoryConfiguration) GetParameters(memoryLimit *resource.Quantity) map[string]string {
+	result := make(map[string]string)
+	if configuration == nil || memoryLimit == nil || memoryLimit.IsZero() {
+		return result
+	}
+
+	// PostgreSQL accepts the memory settings in kB, which keeps the
+	// computed values precise enough for any container size
+	limitKB := memoryLimit.Value() / 1024
+	for key, percentage := range map[string]int32{
+		"shared_buffers":       configuration.SharedBuffersPercentage,
+		"effective_cache_size": configuration.EffectiveCacheSizePercentage,
+	} {
+		if percentage <= 0 {
+			continue
+		}
+		result[key] = fmt.Sprintf("%dkB", limitKB*int64(percentage)/100)
+	}
+
+	return result
+}
+
+// GetClientSSLMinProtocolVersion gets the minimum TLS protocol version the
+// instances require when connecting to the primary. It's empty when it
+// hasn't been configured or when the libpq of the PostgreSQL version in
+// use doesn't support it, i.e. before PostgreSQL 13
+func (cluster *Cluster) GetClientSSLMinProtocolVersion() string {
+	configuration := cluster.Spec.PostgresConfiguration.TLS
+	if configuration == nil || configuration.MinProtocolVersion == "" {
+		return ""
+	}
+
+	if version, err := cluster.GetPostgresqlVersion(); err != nil || version < 130000 {
+		return ""
+	}
+
+	return configuration.MinProtocolVersion
+}
+
+// GetWalArchiveQuorum gets the number of WAL destinations where a WAL file
+// must be archived before reporting success to PostgreSQL, defaulting to
+// all of them
+func (backupConfiguration *BackupConfiguration) GetWalArchiveQuorum() int {
+	destinations := 1 + len(backupConfiguration.AdditionalWalDestinations)
+	if backupConfiguration.WalArchiveQuorum == nil || *backupConfiguration.WalArchiveQuorum > destinations {
+		return destinations
+	}
+
+	return *backupConfiguration.WalArchiveQuorum
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -3734,6 +7388,22 @@ func (cluster *Cluster) IsReadOnlyServiceEnabled() bool {
 	return !slices.Contains(cluster.Spec.Managed.Services.DisabledDefaultServices, ServiceSelectorTypeRO)
 }
 
+// GetTargetTimeline gets the timeline the recovery should follow when
+// it has been explicitly chosen, 0 if the recovery follows the latest or
+// the current timeline
+func (target *RecoveryTarget) GetTargetTimeline() int {
+	if target == nil {
+		return 0
+	}
+
+	timeline, err := strconv.Atoi(target.TargetTLI)
+	if err != nil || timeline < 1 {
+		return 0
+	}
+
+	return timeline
+}
+
 // BuildPostgresOptions create the list of options that
 // should be added to the PostgreSQL configuration to
 // recover given a certain target
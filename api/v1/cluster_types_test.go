@@ -18,12 +18,14 @@ package v1
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -177,7 +179,7 @@ var _ = Describe("Bootstrap via initdb", func() {
 						Secret: &LocalObjectReference{
 							Name: "appSecret",
 						},
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							SecretRefs: []SecretKeySelector{
 								{
 									Key: "secretKey",
@@ -208,7 +210,7 @@ var _ = Describe("Bootstrap via initdb", func() {
 						Secret: &LocalObjectReference{
 							Name: "appSecret",
 						},
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									Key: "configMapKey",
@@ -247,6 +249,35 @@ var _ = Describe("Bootstrap via initdb", func() {
 		Expect(cluster.ShouldInitDBRunPostInitApplicationSQLRefs()).To(BeFalse())
 	})
 
+	It("will run the post init sql refs only for the databases where they are specified", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "clusterName",
+			},
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						PostInitSQLRefs: &SQLRefs{
+							ConfigMapRefs: []ConfigMapKeySelector{
+								{
+									Key: "configMapKey",
+									LocalObjectReference: LocalObjectReference{
+										Name: "configMapName",
+									},
+								},
+							},
+						},
+						PostInitTemplateSQLRefs: &SQLRefs{},
+					},
+				},
+			},
+		}
+
+		Expect(cluster.ShouldInitDBRunPostInitSQLRefs()).To(BeTrue())
+		Expect(cluster.ShouldInitDBRunPostInitTemplateSQLRefs()).To(BeFalse())
+		Expect(cluster.ShouldInitDBRunPostInitApplicationSQLRefs()).To(BeFalse())
+	})
+
 	It("will not create an application database if not requested", func() {
 		cluster := Cluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -449,6 +480,36 @@ var _ = Describe("external cluster list", func() {
 	})
 })
 
+var _ = Describe("managed extensions", func() {
+	It("returns the extensions supported by the operator", func() {
+		var configuration *PostgresConfiguration
+		Expect(configuration.GetManagedExtensions()).To(Equal(postgres.ManagedExtensions))
+	})
+
+	It("appends the extensions declared with a recipe", func() {
+		configuration := &PostgresConfiguration{
+			Extensions: []ManagedExtensionConfiguration{
+				{Name: "pgaudit"},
+				{
+					Name: "pg_cron",
+					Recipe: &ManagedExtensionRecipe{
+						SharedPreloadLibraries: []string{"pg_cron"},
+						Parameters:             map[string]string{"cron.database_name": "app"},
+					},
+				},
+			},
+		}
+		extensions := configuration.GetManagedExtensions()
+		Expect(extensions).To(HaveLen(len(postgres.ManagedExtensions) + 1))
+		Expect(extensions[len(extensions)-1]).To(Equal(postgres.ManagedExtension{
+			Name:                   "pg_cron",
+			SharedPreloadLibraries: []string{"pg_cron"},
+			DefaultParameters:      map[string]string{"cron.database_name": "app"},
+		}))
+		Expect(postgres.ManagedExtensions).ToNot(ContainElement(HaveField("Name", "pg_cron")))
+	})
+})
+
 var _ = Describe("look up for secrets", func() {
 	cluster := Cluster{
 		ObjectMeta: metav1.ObjectMeta{
@@ -804,6 +865,30 @@ var _ = Describe("PostgreSQL version detection", func() {
 	})
 })
 
+var _ = Describe("Backup major version compatibility", func() {
+	cluster := Cluster{
+		Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+		},
+	}
+
+	It("accepts backups taken with the same major version", func() {
+		backup := &Backup{Status: BackupStatus{BackupID: "20240101T000000", MajorVersion: 16}}
+		Expect(cluster.CheckBackupMajorVersion(backup)).To(Succeed())
+	})
+
+	It("accepts backups not reporting their major version", func() {
+		Expect(cluster.CheckBackupMajorVersion(&Backup{})).To(Succeed())
+		Expect(cluster.CheckBackupMajorVersion(nil)).To(Succeed())
+	})
+
+	It("rejects backups taken with a different major version", func() {
+		backup := &Backup{Status: BackupStatus{BackupID: "20240101T000000", MajorVersion: 15}}
+		Expect(cluster.CheckBackupMajorVersion(backup)).To(MatchError(
+			ContainSubstring("taken with PostgreSQL 15 and can't be restored using PostgreSQL 16")))
+	})
+})
+
 var _ = Describe("Default Metrics", func() {
 	It("correctly says default metrics are not disabled when no monitoring is passed", func() {
 		cluster := Cluster{
@@ -932,6 +1017,67 @@ var _ = Describe("Fencing annotation", func() {
 			Expect(cluster.IsInstanceFenced("one")).To(BeFalse())
 		})
 	})
+	When("the instances are fenced in read-only mode", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation:  "[\"*\"]",
+					utils.FencingModeAnnotationName: string(utils.FencingModeReadOnly),
+				},
+			},
+			Status: ClusterStatus{
+				QuarantinedInstances: []string{"two"},
+			},
+		}
+
+		It("keeps PostgreSQL running on the fenced instances", func() {
+			Expect(cluster.GetInstanceFencingMode("one")).To(Equal(utils.FencingModeReadOnly))
+		})
+
+		It("stops the quarantined instances", func() {
+			Expect(cluster.GetInstanceFencingMode("two")).To(Equal(utils.FencingModeStop))
+		})
+	})
+})
+
+var _ = Describe("WAL restore parallelism", func() {
+	It("restores one WAL file at a time without a configuration", func() {
+		var configuration *WalBackupConfiguration
+		Expect(configuration.GetRestoreMaxParallel(true)).To(Equal(1))
+		Expect((&WalBackupConfiguration{}).GetRestoreMaxParallel(false)).To(Equal(1))
+	})
+
+	It("uses maxParallel when the parallelism of the phase is not specified", func() {
+		configuration := &WalBackupConfiguration{
+			MaxParallel:        4,
+			RestoreMaxParallel: &WalRestoreMaxParallelConfiguration{Bootstrap: 16},
+		}
+		Expect(configuration.GetRestoreMaxParallel(true)).To(Equal(16))
+		Expect(configuration.GetRestoreMaxParallel(false)).To(Equal(4))
+	})
+
+	It("uses the parallelism of each phase", func() {
+		configuration := &WalBackupConfiguration{
+			MaxParallel:        4,
+			RestoreMaxParallel: &WalRestoreMaxParallelConfiguration{Bootstrap: 16, Streaming: 2},
+		}
+		Expect(configuration.GetRestoreMaxParallel(true)).To(Equal(16))
+		Expect(configuration.GetRestoreMaxParallel(false)).To(Equal(2))
+	})
+
+	It("considers an instance bootstrapping until it is ready", func() {
+		cluster := Cluster{
+			Status: ClusterStatus{
+				InstancesStatus: map[utils.PodStatus][]string{
+					utils.PodHealthy:     {"cluster-example-1"},
+					utils.PodReplicating: {"cluster-example-2"},
+				},
+			},
+		}
+		Expect(cluster.IsInstanceBootstrapping("cluster-example-1")).To(BeFalse())
+		Expect(cluster.IsInstanceBootstrapping("cluster-example-2")).To(BeTrue())
+		Expect(cluster.IsInstanceBootstrapping("cluster-example-3")).To(BeTrue())
+	})
 })
 
 var _ = Describe("Barman credentials", func() {
@@ -1648,3 +1794,473 @@ var _ = Describe("Cluster Managed Service Enablement", func() {
 		})
 	})
 })
+
+var _ = Describe("WAL archive quorum", func() {
+	backupConfiguration := &BackupConfiguration{
+		AdditionalWalDestinations: []WalArchiveDestination{{Name: "one"}, {Name: "two"}},
+	}
+
+	It("defaults to every WAL destination", func() {
+		Expect(backupConfiguration.GetWalArchiveQuorum()).To(Equal(3))
+	})
+
+	It("uses the configured quorum", func() {
+		backupConfiguration.WalArchiveQuorum = ptr.To(2)
+		Expect(backupConfiguration.GetWalArchiveQuorum()).To(Equal(2))
+	})
+
+	It("can't require more than every WAL destination", func() {
+		backupConfiguration.WalArchiveQuorum = ptr.To(5)
+		Expect(backupConfiguration.GetWalArchiveQuorum()).To(Equal(3))
+	})
+})
+
+var _ = Describe("default timeouts", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *TimeoutsConfiguration
+		Expect(configuration.GetParameters()).To(BeEmpty())
+	})
+
+	It("renders the timeouts in milliseconds", func() {
+		configuration := &TimeoutsConfiguration{
+			StatementTimeout:                "1m30s",
+			IdleInTransactionSessionTimeout: "0",
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"statement_timeout":                   "90000ms",
+			"idle_in_transaction_session_timeout": "0ms",
+		}))
+	})
+})
+
+var _ = Describe("slow query logging", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *SlowQueryLoggingConfiguration
+		Expect(configuration.GetParameters()).To(BeEmpty())
+	})
+
+	It("renders the parameters in the unit of pg_settings", func() {
+		tempFilesMinSize := resource.MustParse("10Mi")
+		configuration := &SlowQueryLoggingConfiguration{
+			MinDuration:           "1.5s",
+			AutovacuumMinDuration: "0",
+			LockWaits:             ptr.To(true),
+			TempFilesMinSize:      &tempFilesMinSize,
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"log_min_duration_statement":  "1500",
+			"log_autovacuum_min_duration": "0",
+			"log_lock_waits":              "on",
+			"log_temp_files":              "10240",
+		}))
+	})
+
+	It("only renders the settings which have been set", func() {
+		configuration := &SlowQueryLoggingConfiguration{LockWaits: ptr.To(false)}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"log_lock_waits": "off",
+		}))
+	})
+})
+
+var _ = Describe("pg_stat_statements settings", func() {
+	It("has no parameters and no reset when not configured", func() {
+		var configuration *StatStatementsConfiguration
+		Expect(configuration.GetParameters()).To(BeEmpty())
+		Expect(configuration.IsResetScheduled()).To(BeFalse())
+		Expect((&StatStatementsConfiguration{}).GetParameters()).To(BeEmpty())
+	})
+
+	It("sets the maximum number of tracked statements", func() {
+		configuration := &StatStatementsConfiguration{Max: 5000, ResetSchedule: "0 0 * * * *"}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"pg_stat_statements.max": "5000",
+		}))
+		Expect(configuration.IsResetScheduled()).To(BeTrue())
+	})
+
+	It("declares the extension once when configured", func() {
+		configuration := &PostgresConfiguration{}
+		Expect(configuration.GetDeclaredExtensions()).To(BeEmpty())
+
+		configuration.StatStatements = &StatStatementsConfiguration{}
+		Expect(configuration.GetDeclaredExtensions()).To(ConsistOf("pg_stat_statements"))
+
+		configuration.Extensions = []ManagedExtensionConfiguration{{Name: "pgaudit"}, {Name: "pg_stat_statements"}}
+		Expect(configuration.GetDeclaredExtensions()).To(ConsistOf("pgaudit", "pg_stat_statements"))
+	})
+})
+
+var _ = Describe("table statistics configuration", func() {
+	It("is disabled by default", func() {
+		var monitoring *MonitoringConfiguration
+		Expect(monitoring.AreTableStatisticsEnabled()).To(BeFalse())
+		Expect(monitoring.GetTableStatisticsInterval()).To(Equal(DefaultTableStatisticsInterval * time.Second))
+		Expect(monitoring.GetTableStatisticsMaxTables()).To(Equal(DefaultTableStatisticsMaxTables))
+	})
+
+	It("uses the requested interval and number of tables", func() {
+		monitoring := &MonitoringConfiguration{
+			TableStatistics: &TableStatisticsConfiguration{
+				Enabled:   true,
+				Interval:  120,
+				MaxTables: 50,
+			},
+		}
+		Expect(monitoring.AreTableStatisticsEnabled()).To(BeTrue())
+		Expect(monitoring.GetTableStatisticsInterval()).To(Equal(2 * time.Minute))
+		Expect(monitoring.GetTableStatisticsMaxTables()).To(Equal(50))
+	})
+})
+
+var _ = Describe("autovacuum tuning profiles", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *AutovacuumConfiguration
+		Expect(configuration.GetParameters(160000)).To(BeEmpty())
+	})
+
+	It("keeps the PostgreSQL defaults with the default profile", func() {
+		configuration := &AutovacuumConfiguration{Profile: AutovacuumProfileDefault}
+		Expect(configuration.GetParameters(160000)).To(BeEmpty())
+	})
+
+	It("sets the parameters of the bulk-load profile", func() {
+		configuration := &AutovacuumConfiguration{Profile: AutovacuumProfileBulkLoad}
+		Expect(configuration.GetParameters(160000)).To(Equal(map[string]string{
+			"autovacuum_naptime":                    "5min",
+			"autovacuum_vacuum_scale_factor":        "0.4",
+			"autovacuum_analyze_scale_factor":       "0.2",
+			"autovacuum_vacuum_insert_scale_factor": "0.1",
+			"autovacuum_vacuum_cost_delay":          "2ms",
+			"autovacuum_vacuum_cost_limit":          "1000",
+		}))
+	})
+
+	It("doesn't set the insert scale factor before PostgreSQL 13", func() {
+		configuration := &AutovacuumConfiguration{Profile: AutovacuumProfileAggressive}
+		Expect(configuration.GetParameters(120000)).ToNot(HaveKey("autovacuum_vacuum_insert_scale_factor"))
+		Expect(configuration.GetParameters(120000)).To(HaveKeyWithValue("autovacuum_naptime", "15s"))
+	})
+})
+
+var _ = Describe("checkpoint tuning profiles", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *CheckpointConfiguration
+		Expect(configuration.GetParameters()).To(BeEmpty())
+	})
+
+	It("keeps the PostgreSQL defaults with the default profile", func() {
+		configuration := &CheckpointConfiguration{Profile: CheckpointProfileDefault}
+		Expect(configuration.GetParameters()).To(BeEmpty())
+	})
+
+	It("sets the parameters of the bulk-load profile", func() {
+		configuration := &CheckpointConfiguration{Profile: CheckpointProfileBulkLoad}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"min_wal_size":                 "2GB",
+			"max_wal_size":                 "16GB",
+			"checkpoint_timeout":           "30min",
+			"checkpoint_completion_target": "0.9",
+		}))
+	})
+})
+
+var _ = Describe("memory settings", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *MemoryConfiguration
+		Expect(configuration.GetParameters(resource.NewQuantity(1<<30, resource.BinarySI))).To(BeEmpty())
+	})
+
+	It("has no parameters without a memory limit", func() {
+		configuration := &MemoryConfiguration{SharedBuffersPercentage: 25}
+		Expect(configuration.GetParameters(nil)).To(BeEmpty())
+		Expect(configuration.GetParameters(&resource.Quantity{})).To(BeEmpty())
+	})
+
+	It("computes the parameters from the memory limit", func() {
+		configuration := &MemoryConfiguration{
+			SharedBuffersPercentage:      25,
+			EffectiveCacheSizePercentage: 75,
+		}
+		memoryLimit := resource.MustParse("4Gi")
+		Expect(configuration.GetParameters(&memoryLimit)).To(Equal(map[string]string{
+			"shared_buffers":       "1048576kB",
+			"effective_cache_size": "3145728kB",
+		}))
+	})
+
+	It("sets only the requested parameters", func() {
+		configuration := &MemoryConfiguration{SharedBuffersPercentage: 30}
+		memoryLimit := resource.MustParse("1G")
+		Expect(configuration.GetParameters(&memoryLimit)).To(Equal(map[string]string{
+			"shared_buffers": "292968kB",
+		}))
+	})
+})
+
+var _ = DescribeTable("post-recovery maintenance command",
+	func(configuration *PostRecoveryConfiguration, expected string) {
+		Expect(configuration.GetMaintenanceCommand()).To(Equal(expected))
+	},
+	Entry("not configured", nil, ""),
+	Entry("nothing requested", &PostRecoveryConfiguration{}, ""),
+	Entry("analyze", &PostRecoveryConfiguration{Analyze: true}, "ANALYZE"),
+	Entry("vacuum", &PostRecoveryConfiguration{Vacuum: true}, "VACUUM"),
+	Entry("vacuum and analyze", &PostRecoveryConfiguration{Analyze: true, Vacuum: true}, "VACUUM (ANALYZE)"),
+)
+
+var _ = Describe("TLS configuration of PostgreSQL", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				PostgresConfiguration: PostgresConfiguration{
+					TLS: &PostgresTLSConfiguration{
+						MinProtocolVersion: "TLSv1.2",
+						Ciphers:            []string{"ECDHE-RSA-AES256-GCM-SHA384", "ECDHE-ECDSA-AES256-GCM-SHA384"},
+					},
+				},
+			},
+		}
+	})
+
+	It("has no parameters when not configured", func() {
+		var configuration *PostgresTLSConfiguration
+		Expect(configuration.GetParameters()).To(BeEmpty())
+	})
+
+	It("renders the protocol version and the ciphers", func() {
+		Expect(cluster.Spec.PostgresConfiguration.TLS.GetParameters()).To(Equal(map[string]string{
+			"ssl_min_protocol_version": "TLSv1.2",
+			"ssl_ciphers":              "ECDHE-RSA-AES256-GCM-SHA384:ECDHE-ECDSA-AES256-GCM-SHA384",
+		}))
+	})
+
+	It("requires the minimum protocol version when connecting to the primary", func() {
+		Expect(cluster.GetClientSSLMinProtocolVersion()).To(Equal("TLSv1.2"))
+
+		cluster.Spec.PostgresConfiguration.TLS.MinProtocolVersion = ""
+		Expect(cluster.GetClientSSLMinProtocolVersion()).To(BeEmpty())
+	})
+
+	It("doesn't require the minimum protocol version when libpq doesn't support it", func() {
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:12.20"
+		Expect(cluster.GetClientSSLMinProtocolVersion()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("client certificate authentication", func() {
+	configuration := &CertificateAuthenticationConfiguration{
+		Mappings: []CertificateMapping{
+			{CommonName: "billing service", Role: "billing"},
+			{CommonName: `/^billing-\d+$`, Role: "billing"},
+			{CommonName: "reporting", Role: "reporting"},
+		},
+		AdditionalClientCASecrets: []LocalObjectReference{{Name: "previous-client-ca"}},
+	}
+
+	It("has nothing to render when not configured", func() {
+		var empty *CertificateAuthenticationConfiguration
+		Expect(empty.GetIdentMappings()).To(BeEmpty())
+		Expect(empty.GetHBARules()).To(BeEmpty())
+		Expect(empty.GetAdditionalClientCASecretNames()).To(BeEmpty())
+	})
+
+	It("maps the common names to the roles", func() {
+		Expect(configuration.GetIdentMappings()).To(Equal([]string{
+			`cnpg_client_certificate "billing service" "billing"`,
+			`cnpg_client_certificate /^billing-\d+$ "billing"`,
+			`cnpg_client_certificate "reporting" "reporting"`,
+		}))
+	})
+
+	It("authenticates each mapped role with its certificate", func() {
+		Expect(configuration.GetHBARules()).To(Equal([]string{
+			`hostssl all "billing" all cert map=cnpg_client_certificate`,
+			`hostssl all "reporting" all cert map=cnpg_client_certificate`,
+		}))
+	})
+
+	It("lists the additional client CA secrets", func() {
+		Expect(configuration.GetAdditionalClientCASecretNames()).To(Equal([]string{"previous-client-ca"}))
+	})
+})
+
+var _ = Describe("parameter change restart strategy", func() {
+	// Every day at 2 AM, for two hours
+	window := &MaintenanceWindowConfiguration{Schedule: "0 0 2 * * *", Duration: "2h"}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 10, hour, minute, 0, 0, time.Local)
+	}
+
+	It("defaults the duration of the maintenance window", func() {
+		Expect((&MaintenanceWindowConfiguration{}).GetDuration()).To(Equal(DefaultMaintenanceWindowDuration))
+		Expect((&MaintenanceWindowConfiguration{Duration: "wrong"}).GetDuration()).
+			To(Equal(DefaultMaintenanceWindowDuration))
+		Expect(window.GetDuration()).To(Equal(2 * time.Hour))
+	})
+
+	It("detects when the maintenance window is open", func() {
+		Expect(window.IsOpen(at(1, 59))).To(BeFalse())
+		Expect(window.IsOpen(at(2, 0))).To(BeTrue())
+		Expect(window.IsOpen(at(3, 30))).To(BeTrue())
+		Expect(window.IsOpen(at(4, 1))).To(BeFalse())
+
+		nextStart, err := window.GetNextStart(at(4, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextStart).To(Equal(at(2, 0).AddDate(0, 0, 1)))
+	})
+
+	It("allows the restarts immediately by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetParameterChangeRestartStrategy()).To(Equal(ParameterChangeRestartStrategyImmediate))
+		Expect(cluster.IsParameterChangeRestartAllowed(at(12, 0))).To(BeTrue())
+	})
+
+	It("allows the restarts only during the maintenance window", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ParameterChangeRestart: &ParameterChangeRestartConfiguration{
+					Strategy:          ParameterChangeRestartStrategyMaintenanceWindow,
+					MaintenanceWindow: window,
+				},
+			},
+		}
+		Expect(cluster.IsParameterChangeRestartAllowed(at(12, 0))).To(BeFalse())
+		Expect(cluster.IsParameterChangeRestartAllowed(at(2, 30))).To(BeTrue())
+	})
+
+	It("allows the restarts only after the approval of the user", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ParameterChangeRestart: &ParameterChangeRestartConfiguration{
+					Strategy: ParameterChangeRestartStrategyManual,
+				},
+			},
+		}
+		Expect(cluster.IsParameterChangeRestartAllowed(at(12, 0))).To(BeFalse())
+
+		cluster.Annotations = map[string]string{utils.RestartApprovalAnnotationName: "true"}
+		Expect(cluster.IsParameterChangeRestartAllowed(at(12, 0))).To(BeTrue())
+	})
+})
+
+var _ = Describe("failover priority", func() {
+	It("assigns priority 0 to the instances which are not listed", func() {
+		var nilConfiguration *FailoverPriorityConfiguration
+		Expect(nilConfiguration.GetPriority("cluster-example-1")).To(BeZero())
+
+		configuration := &FailoverPriorityConfiguration{
+			Instances: []InstanceFailoverPriority{{Name: "cluster-example-2", Priority: 10}},
+		}
+		Expect(configuration.GetPriority("cluster-example-1")).To(BeZero())
+		Expect(configuration.GetPriority("cluster-example-2")).To(BeEquivalentTo(10))
+	})
+
+	It("defaults the LSN tolerance to the size of a WAL segment", func() {
+		configuration := &FailoverPriorityConfiguration{}
+		Expect(configuration.GetLSNTolerance()).To(BeEquivalentTo(16 * 1024 * 1024))
+
+		tolerance := resource.MustParse("1Gi")
+		configuration.LSNTolerance = &tolerance
+		Expect(configuration.GetLSNTolerance()).To(BeEquivalentTo(1024 * 1024 * 1024))
+	})
+})
+
+var _ = Describe("replica autoscaling", func() {
+	It("uses the default scale down delay and drain timeout", func() {
+		configuration := &ReplicaAutoscalingConfiguration{}
+		Expect(configuration.GetScaleDownDelay()).To(Equal(DefaultReplicaAutoscalingScaleDownDelay))
+		Expect(configuration.GetDrainTimeout()).To(Equal(DefaultReplicaAutoscalingDrainTimeout))
+
+		configuration.ScaleDownDelay = ptr.To(int32(600))
+		configuration.DrainTimeout = ptr.To(int32(0))
+		Expect(configuration.GetScaleDownDelay()).To(Equal(10 * time.Minute))
+		Expect(configuration.GetDrainTimeout()).To(BeZero())
+	})
+
+	It("gets the draining instance from a missing status", func() {
+		var status *ReplicaAutoscalingStatus
+		Expect(status.GetDrainingInstance()).To(BeEmpty())
+
+		status = &ReplicaAutoscalingStatus{DrainingInstance: "cluster-example-3"}
+		Expect(status.GetDrainingInstance()).To(Equal("cluster-example-3"))
+	})
+
+	It("uses the number of instances chosen by the operator", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.GetInstances()).To(Equal(3))
+
+		cluster.Status.ReplicaAutoscaling = &ReplicaAutoscalingStatus{DesiredInstances: 5}
+		Expect(cluster.GetInstances()).To(Equal(3))
+
+		cluster.Spec.ReplicaAutoscaling = &ReplicaAutoscalingConfiguration{MinInstances: 2, MaxInstances: 6}
+		Expect(cluster.GetInstances()).To(Equal(5))
+
+		cluster.Status.ReplicaAutoscaling = nil
+		Expect(cluster.GetInstances()).To(Equal(3))
+	})
+})
+
+var _ = Describe("deletion policy", func() {
+	It("retains everything by default", func() {
+		var policy *DeletionPolicyConfiguration
+		Expect(policy.GetPVCsReclaimPolicy()).To(Equal(ClusterReclaimRetain))
+		Expect(policy.GetBackupsReclaimPolicy()).To(Equal(ClusterReclaimRetain))
+		Expect(policy.IsConfirmationRequired()).To(BeFalse())
+
+		policy = &DeletionPolicyConfiguration{}
+		Expect(policy.GetPVCsReclaimPolicy()).To(Equal(ClusterReclaimRetain))
+		Expect(policy.GetBackupsReclaimPolicy()).To(Equal(ClusterReclaimRetain))
+	})
+
+	It("applies the configured reclaim policies", func() {
+		policy := &DeletionPolicyConfiguration{
+			PVCs:                ClusterReclaimDelete,
+			Backups:             ClusterReclaimDelete,
+			RequireConfirmation: true,
+		}
+		Expect(policy.GetPVCsReclaimPolicy()).To(Equal(ClusterReclaimDelete))
+		Expect(policy.GetBackupsReclaimPolicy()).To(Equal(ClusterReclaimDelete))
+		Expect(policy.IsConfirmationRequired()).To(BeTrue())
+	})
+})
+
+var _ = Describe("recovery target timeline", func() {
+	It("is only set when a timeline number has been chosen", func() {
+		var target *RecoveryTarget
+		Expect(target.GetTargetTimeline()).To(BeZero())
+		Expect((&RecoveryTarget{}).GetTargetTimeline()).To(BeZero())
+		Expect((&RecoveryTarget{TargetTLI: "latest"}).GetTargetTimeline()).To(BeZero())
+		Expect((&RecoveryTarget{TargetTLI: "current"}).GetTargetTimeline()).To(BeZero())
+		Expect((&RecoveryTarget{TargetTLI: "3"}).GetTargetTimeline()).To(Equal(3))
+	})
+})
+
+var _ = Describe("PrometheusRule configuration", func() {
+	It("is disabled by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.IsPrometheusRuleEnabled()).To(BeFalse())
+
+		cluster.Spec.Monitoring = &MonitoringConfiguration{PrometheusRule: &PrometheusRuleConfiguration{}}
+		Expect(cluster.IsPrometheusRuleEnabled()).To(BeFalse())
+
+		cluster.Spec.Monitoring.PrometheusRule.Enabled = true
+		Expect(cluster.IsPrometheusRuleEnabled()).To(BeTrue())
+	})
+
+	It("finds the customization of an alert", func() {
+		var configuration *PrometheusRuleConfiguration
+		Expect(configuration.GetAlert(PrometheusAlertReplicationLag)).To(BeNil())
+
+		configuration = &PrometheusRuleConfiguration{
+			Alerts: []PrometheusAlertConfiguration{
+				{Name: PrometheusAlertDiskPressure, Disabled: true},
+			},
+		}
+		Expect(configuration.GetAlert(PrometheusAlertReplicationLag)).To(BeNil())
+		Expect(configuration.GetAlert(PrometheusAlertDiskPressure).Disabled).To(BeTrue())
+	})
+})
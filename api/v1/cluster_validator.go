@@ -0,0 +1,233 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// recoveryStoreDialTimeout is the time the validating webhook waits for
+// the object store used by the recovery to accept a connection
+const recoveryStoreDialTimeout = 3 * time.Second
+
+// dialFunc opens a network connection to the passed address
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// clusterValidator is the validating webhook of the Cluster resource.
+// Other than the checks on the Cluster itself, it validates the origin
+// of the recovery against the Kubernetes API and, when requested, the
+// object store
+type clusterValidator struct {
+	reader client.Reader
+	dial   dialFunc
+}
+
+var _ admission.CustomValidator = &clusterValidator{}
+
+// newClusterValidator creates a new validating webhook for the Cluster resource
+func newClusterValidator(reader client.Reader) *clusterValidator {
+	dialer := &net.Dialer{Timeout: recoveryStoreDialTimeout}
+	return &clusterValidator{
+		reader: reader,
+		dial:   dialer.DialContext,
+	}
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *clusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a Cluster but got a %T", obj)
+	}
+
+	warnings, err := cluster.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	originWarnings, allErrs := v.validateRecoveryOrigin(ctx, cluster)
+	warnings = append(warnings, originWarnings...)
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	return nil, apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Cluster"},
+		cluster.Name, allErrs)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *clusterValidator) ValidateUpdate(
+	_ context.Context,
+	oldObj, newObj runtime.Object,
+) (admission.Warnings, error) {
+	cluster, ok := newObj.(*Cluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a Cluster but got a %T", newObj)
+	}
+
+	return cluster.ValidateUpdate(oldObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *clusterValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*Cluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a Cluster but got a %T", obj)
+	}
+
+	return cluster.ValidateDelete()
+}
+
+// validateRecoveryOrigin checks that the Backup to be restored has been
+// taken with the PostgreSQL major version of the cluster and, when requested
+// via annotation, that the object store of the recovery source is reachable
+func (v *clusterValidator) validateRecoveryOrigin(
+	ctx context.Context,
+	cluster *Cluster,
+) (admission.Warnings, field.ErrorList) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+		return nil, nil
+	}
+
+	recoveryPath := field.NewPath("spec", "bootstrap", "recovery")
+	recovery := cluster.Spec.Bootstrap.Recovery
+
+	var warnings admission.Warnings
+	var allErrs field.ErrorList
+
+	if recovery.Backup != nil && recovery.Backup.Name != "" {
+		var backup Backup
+		err := v.reader.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: recovery.Backup.Name}, &backup)
+		switch {
+		case apierrors.IsNotFound(err):
+			// The operator waits for the backup to be created
+			warnings = append(warnings, fmt.Sprintf(
+				"Backup %q doesn't exist yet: the recovery will start once it is completed",
+				recovery.Backup.Name))
+		case err != nil:
+			return nil, field.ErrorList{
+				field.InternalError(recoveryPath.Child("backup"), err),
+			}
+		default:
+			if err := cluster.CheckBackupMajorVersion(&backup); err != nil {
+				allErrs = append(allErrs, field.Invalid(
+					recoveryPath.Child("backup", "name"),
+					recovery.Backup.Name,
+					err.Error()))
+			}
+		}
+	}
+
+	if cluster.Annotations[utils.ValidateRecoveryStoreAnnotationName] != "enabled" || recovery.Source == "" {
+		return warnings, allErrs
+	}
+
+	externalCluster, found := cluster.ExternalCluster(recovery.Source)
+	if !found || externalCluster.BarmanObjectStore == nil {
+		return warnings, allErrs
+	}
+
+	if err := v.checkObjectStoreReachable(ctx, externalCluster.BarmanObjectStore); err != nil {
+		allErrs = append(allErrs, field.Invalid(
+			recoveryPath.Child("source"),
+			recovery.Source,
+			fmt.Sprintf("The object store of external cluster %v is not reachable: %v", recovery.Source, err)))
+	}
+
+	return warnings, allErrs
+}
+
+// checkObjectStoreReachable checks that the endpoint of the passed object
+// store accepts connections. It doesn't check the credentials, which are
+// verified by the recovery job
+func (v *clusterValidator) checkObjectStoreReachable(
+	ctx context.Context,
+	configuration *BarmanObjectStoreConfiguration,
+) error {
+	address, err := getObjectStoreAddress(configuration)
+	if err != nil {
+		return err
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, recoveryStoreDialTimeout)
+	defer cancel()
+
+	conn, err := v.dial(dialCtx, "tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// getObjectStoreAddress gets the host and port of the endpoint
+// of the passed object store
+func getObjectStoreAddress(configuration *BarmanObjectStoreConfiguration) (string, error) {
+	endpoint := configuration.EndpointURL
+	if endpoint == "" {
+		destination, err := url.Parse(configuration.DestinationPath)
+		if err != nil {
+			return "", err
+		}
+
+		switch destination.Scheme {
+		case "s3":
+			endpoint = "https://s3.amazonaws.com"
+		case "gs":
+			endpoint = "https://storage.googleapis.com"
+		default:
+			endpoint = configuration.DestinationPath
+		}
+	}
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	if endpointURL.Hostname() == "" {
+		return "", fmt.Errorf("can't find the endpoint of the object store in %q", endpoint)
+	}
+
+	port := endpointURL.Port()
+	if port == "" {
+		switch strings.ToLower(endpointURL.Scheme) {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return "", fmt.Errorf("can't find the port of the object store endpoint %q", endpoint)
+		}
+	}
+
+	return net.JoinHostPort(endpointURL.Hostname(), port), nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("recovery origin validation", func() {
+	var (
+		validator *clusterValidator
+		dialed    []string
+		dialErr   error
+	)
+
+	newCluster := func(recovery *BootstrapRecovery) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-restore", Namespace: "default"},
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				Bootstrap: &BootstrapConfiguration{Recovery: recovery},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://backups/",
+							EndpointURL:     "http://minio:9000",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+
+		dialed = nil
+		dialErr = nil
+		validator = &clusterValidator{
+			reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&Backup{
+					ObjectMeta: metav1.ObjectMeta{Name: "backup-16", Namespace: "default"},
+					Status:     BackupStatus{BackupID: "20240101T000000", MajorVersion: 16},
+				},
+				&Backup{
+					ObjectMeta: metav1.ObjectMeta{Name: "backup-15", Namespace: "default"},
+					Status:     BackupStatus{BackupID: "20240101T000000", MajorVersion: 15},
+				},
+			).Build(),
+			dial: func(_ context.Context, _, address string) (net.Conn, error) {
+				dialed = append(dialed, address)
+				if dialErr != nil {
+					return nil, dialErr
+				}
+				client, server := net.Pipe()
+				_ = server.Close()
+				return client, nil
+			},
+		}
+	})
+
+	It("accepts a backup taken with the same major version", func(ctx SpecContext) {
+		cluster := newCluster(&BootstrapRecovery{Backup: &BackupSource{
+			LocalObjectReference: LocalObjectReference{Name: "backup-16"},
+		}})
+		warnings, errs := validator.validateRecoveryOrigin(ctx, cluster)
+		Expect(warnings).To(BeEmpty())
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("rejects a backup taken with a different major version", func(ctx SpecContext) {
+		cluster := newCluster(&BootstrapRecovery{Backup: &BackupSource{
+			LocalObjectReference: LocalObjectReference{Name: "backup-15"},
+		}})
+		_, errs := validator.validateRecoveryOrigin(ctx, cluster)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.backup.name"))
+	})
+
+	It("warns about a backup that doesn't exist yet", func(ctx SpecContext) {
+		cluster := newCluster(&BootstrapRecovery{Backup: &BackupSource{
+			LocalObjectReference: LocalObjectReference{Name: "backup-missing"},
+		}})
+		warnings, errs := validator.validateRecoveryOrigin(ctx, cluster)
+		Expect(warnings).To(HaveLen(1))
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("checks the object store only when requested", func(ctx SpecContext) {
+		cluster := newCluster(&BootstrapRecovery{Source: "origin"})
+		_, errs := validator.validateRecoveryOrigin(ctx, cluster)
+		Expect(errs).To(BeEmpty())
+		Expect(dialed).To(BeEmpty())
+
+		cluster.Annotations = map[string]string{utils.ValidateRecoveryStoreAnnotationName: "enabled"}
+		_, errs = validator.validateRecoveryOrigin(ctx, cluster)
+		Expect(errs).To(BeEmpty())
+		Expect(dialed).To(ConsistOf("minio:9000"))
+	})
+
+	It("rejects an unreachable object store", func(ctx SpecContext) {
+		dialErr = fmt.Errorf("connection refused")
+		cluster := newCluster(&BootstrapRecovery{Source: "origin"})
+		cluster.Annotations = map[string]string{utils.ValidateRecoveryStoreAnnotationName: "enabled"}
+		_, errs := validator.validateRecoveryOrigin(ctx, cluster)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.source"))
+	})
+
+	DescribeTable("finds the address of the object store",
+		func(configuration BarmanObjectStoreConfiguration, expected string) {
+			Expect(getObjectStoreAddress(&configuration)).To(Equal(expected))
+		},
+		Entry("with an endpoint URL",
+			BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/", EndpointURL: "https://minio:9000"},
+			"minio:9000"),
+		Entry("with an endpoint URL without port",
+			BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/", EndpointURL: "http://minio"},
+			"minio:80"),
+		Entry("with AWS S3",
+			BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+			"s3.amazonaws.com:443"),
+		Entry("with Google Cloud Storage",
+			BarmanObjectStoreConfiguration{DestinationPath: "gs://backups/"},
+			"storage.googleapis.com:443"),
+		Entry("with Azure Blob Storage",
+			BarmanObjectStoreConfiguration{DestinationPath: "https://account.blob.core.windows.net/backups"},
+			"account.blob.core.windows.net:443"),
+	)
+})
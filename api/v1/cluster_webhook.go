@@ -20,17 +20,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"net/url"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	validationutil "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
@@ -40,6 +46,7 @@ import (
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	pkgurl "github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -60,7 +67,25 @@ const (
 	DefaultApplicationUserName = DefaultApplicationDatabaseName
 )
 
-const sharedBuffersParameter = "shared_buffers"
+const (
+	sharedBuffersParameter  = "shared_buffers"
+	maxConnectionsParameter = "max_connections"
+	workMemParameter        = "work_mem"
+
+	// maxConnectionsUpperBound is the highest value PostgreSQL accepts
+	// for max_connections
+	maxConnectionsUpperBound = 262143
+
+	// The PostgreSQL defaults used to estimate the memory needed by
+	// the configured connections
+	defaultSharedBuffers = "128MB"
+	defaultWorkMem       = "4MB"
+
+	// connectionMemoryOverhead is a conservative estimate of the memory
+	// used by each backend besides work_mem (catalog caches, buffers
+	// for the query plans, lock table entries)
+	connectionMemoryOverhead = "2Mi"
+)
 
 // clusterLog is for logging in this package.
 var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
@@ -69,6 +94,8 @@ var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&clusterDefaulter{reader: mgr.GetAPIReader()}).
+		WithValidator(newClusterValidator(mgr.GetAPIReader())).
 		Complete()
 }
 
@@ -130,6 +157,7 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 			IsReplicaCluster:              r.IsReplica(),
 			PreserveFixedSettingsFromUser: preserveUserSettings,
 			IsWalArchivingDisabled:        utils.IsWalArchivingDisabled(&r.ObjectMeta),
+			IsUnsafeDurabilityAllowed:     utils.IsUnsafeDurabilityAllowed(&r.ObjectMeta),
 		}
 		sanitizedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
 		r.Spec.PostgresConfiguration.Parameters = sanitizedParameters
@@ -272,10 +300,11 @@ func (r *Cluster) defaultInitDB() {
 	if r.Spec.Bootstrap.InitDB.Encoding == "" {
 		r.Spec.Bootstrap.InitDB.Encoding = "UTF8"
 	}
-	if r.Spec.Bootstrap.InitDB.LocaleCollate == "" {
+	// The categories not explicitly set inherit the locale, if any
+	if r.Spec.Bootstrap.InitDB.LocaleCollate == "" && r.Spec.Bootstrap.InitDB.Locale == "" {
 		r.Spec.Bootstrap.InitDB.LocaleCollate = "C"
 	}
-	if r.Spec.Bootstrap.InitDB.LocaleCType == "" {
+	if r.Spec.Bootstrap.InitDB.LocaleCType == "" && r.Spec.Bootstrap.InitDB.Locale == "" {
 		r.Spec.Bootstrap.InitDB.LocaleCType = "C"
 	}
 }
@@ -300,15 +329,22 @@ func (r *Cluster) defaultPgBaseBackup() {
 	}
 }
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-cluster,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vcluster.cnpg.io,sideEffects=None
 
+// The deletions are validated by a different webhook, ignoring the failures,
+// so that an unavailable operator doesn't block the deletion of the clusters,
+// and therefore of their namespace
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=delete,path=/validate-postgresql-cnpg-io-v1-cluster,mutating=false,failurePolicy=ignore,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vclusterdelete.cnpg.io,sideEffects=None
+
 var _ webhook.Validator = &Cluster{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateCreate() (admission.Warnings, error) {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	allErrs := r.Validate()
+	allErrs := append(
+		r.Validate(),
+		r.ValidateBootstrap()...,
+	)
 
 	// Call the plugins to help validating this cluster creation
 	ctx := context.Background()
@@ -347,6 +383,7 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateRecoveryApplicationDatabase,
 		r.validatePgBaseBackupApplicationDatabase,
 		r.validateImport,
+		r.validateImportFromBackup,
 		r.validateSuperuserSecret,
 		r.validateCerts,
 		r.validateBootstrapMethod,
@@ -368,18 +405,47 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateBootstrapRecoveryDataSource,
 		r.validateExternalClusters,
 		r.validateTolerations,
+		r.validateRolePlacement,
+		r.validateParameterChangeRestart,
+		r.validateFailoverPriority,
+		r.validateReplicaAutoscaling,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
+		r.validateBackupVerification,
+		r.validateWalArchiveCheck,
+		r.validateWalCompression,
+		r.validateAdditionalWalDestinations,
+		r.validateBackupArchive,
+		r.validateWalRestoreCache,
+		r.validatePodDisruptionBudget,
 		r.validateConfiguration,
+		r.validateParameterNames,
+		r.validateHotStandbyFeedbackInstances,
+		r.validateDelayedReplicas,
+		r.validateLogDestination,
+		r.validateAdaptiveArchiveTimeout,
+		r.validateTimeouts,
+		r.validateSlowQueryLogging,
+		r.validateStatStatements,
+		r.validateMemory,
+		r.validateTransactionIDWraparound,
+		r.validatePostgresTLS,
+		r.validateCertificateAuthentication,
+		r.validatePgHBA,
 		r.validateLDAP,
 		r.validateReplicationSlots,
+		r.validateLogicalReplicationSlots,
+		r.validateScheduledMaintenance,
 		r.validateEnv,
 		r.validateManagedServices,
 		r.validateManagedRoles,
 		r.validateManagedExtensions,
 		r.validateResources,
+		r.validateSidecars,
+		r.validateMaxConnections,
 		r.validateHibernationAnnotation,
+		r.validateFencingModeAnnotation,
 		r.validatePromotionToken,
 	}
 
@@ -390,6 +456,22 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 	return allErrs
 }
 
+// ValidateBootstrap groups the validation logic for the bootstrap section
+// which is applied only when the cluster is created, as the bootstrap
+// section is not used anymore once the cluster is running
+func (r *Cluster) ValidateBootstrap() (allErrs field.ErrorList) {
+	type validationFunc func() field.ErrorList
+	validations := []validationFunc{
+		r.validateBootstrapRecoveryOrigin,
+	}
+
+	for _, validate := range validations {
+		allErrs = append(allErrs, validate()...)
+	}
+
+	return allErrs
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	clusterLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
@@ -450,6 +532,7 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 		r.validateReplicationSlotsChange,
 		r.validateWALLevelChange,
 		r.validateReplicaClusterChange,
+		r.validateReadOnlyFencingChange,
 	}
 	for _, validate := range validations {
 		allErrs = append(allErrs, validate(old)...)
@@ -460,12 +543,55 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *Cluster) ValidateDelete() (admission.Warnings, error) {
-	clusterLog.Info("validate delete", "name", r.Name)
+	clusterLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+
+	if err := r.validateDeletionConfirmation(); err != nil {
+		return nil, apierrors.NewForbidden(
+			schema.GroupResource{Group: "postgresql.cnpg.io", Resource: "clusters"},
+			r.Name, err)
+	}
 
-	// TODO(user): fill in your validation logic upon object deletion.
 	return nil, nil
 }
 
+// validateDeletionConfirmation checks that the deletion of the cluster
+// has been confirmed, when required by the deletion policy
+func (r *Cluster) validateDeletionConfirmation() error {
+	if !r.IsDeletionConfirmed() {
+		return fmt.Errorf(
+			"the deletion of the cluster must be confirmed by setting the %s annotation to %q",
+			utils.DeletionConfirmationAnnotationName, r.Name)
+	}
+
+	return nil
+}
+
+// validatePgHBA validates the syntax of the user-defined pg_hba rules
+func (r *Cluster) validatePgHBA() field.ErrorList {
+	var result field.ErrorList
+
+	basePath := field.NewPath("spec", "postgresql")
+	rulesByPath := []struct {
+		path  *field.Path
+		rules []string
+	}{
+		{path: basePath.Child("pg_hba_prepend"), rules: r.Spec.PostgresConfiguration.PgHBAPrepend},
+		{path: basePath.Child("pg_hba"), rules: r.Spec.PostgresConfiguration.PgHBA},
+	}
+	for _, item := range rulesByPath {
+		for idx, rule := range item.rules {
+			if err := postgres.ValidateHBARule(rule); err != nil {
+				result = append(result, field.Invalid(
+					item.path.Index(idx),
+					rule,
+					fmt.Sprintf("Invalid pg_hba rule: %v", err)))
+			}
+		}
+	}
+
+	return result
+}
+
 // validateLDAP validates the ldap postgres configuration
 func (r *Cluster) validateLDAP() field.ErrorList {
 	// No validating if not specified
@@ -533,6 +659,14 @@ func isReservedEnvironmentVariable(name string) bool {
 	return false
 }
 
+// localeNameRegex matches a locale name, like `C`, `en_US.UTF-8`
+// or `de_DE@euro`
+var localeNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.@-]+$`)
+
+// timezoneNameRegex matches a time zone name, like `UTC`,
+// `Europe/Rome` or `Etc/GMT+2`
+var timezoneNameRegex = regexp.MustCompile(`^[A-Za-z0-9_+-]+(/[A-Za-z0-9_+-]+)*$`)
+
 // validateInitDB validate the bootstrapping options when initdb
 // method is used
 func (r *Cluster) validateInitDB() field.ErrorList {
@@ -553,36 +687,76 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 	result = r.validateApplicationDatabase(initDBOptions.Database, initDBOptions.Owner,
 		"initdb")
 
-	if initDBOptions.WalSegmentSize != 0 && !utils.IsPowerOfTwo(initDBOptions.WalSegmentSize) {
+	if initDBOptions.WalSegmentSize != 0 {
+		result = append(result, validateWalSegmentSize(
+			initDBOptions.WalSegmentSize, r.Spec.PostgresConfiguration)...)
+	}
+
+	basePath := field.NewPath("spec", "bootstrap", "initdb")
+	for _, locale := range []struct {
+		name  string
+		value string
+	}{
+		{name: "locale", value: initDBOptions.Locale},
+		{name: "localeCollate", value: initDBOptions.LocaleCollate},
+		{name: "localeCType", value: initDBOptions.LocaleCType},
+	} {
+		if locale.value != "" && !localeNameRegex.MatchString(locale.value) {
+			result = append(
+				result,
+				field.Invalid(
+					basePath.Child(locale.name),
+					locale.value,
+					"must be a locale name, like en_US.UTF-8"))
+		}
+	}
+
+	if initDBOptions.Timezone != "" && !timezoneNameRegex.MatchString(initDBOptions.Timezone) {
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "bootstrap", "initdb", "walSegmentSize"),
-				initDBOptions.WalSegmentSize,
-				"WAL segment size must be a power of 2"))
+				basePath.Child("timezone"),
+				initDBOptions.Timezone,
+				"must be a time zone name, like Europe/Rome"))
 	}
 
-	if initDBOptions.PostInitApplicationSQLRefs != nil {
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "secretRefs"),
-						item,
-						"key and name must be specified"))
-			}
+	result = append(result, validateSQLRefs(
+		basePath.Child("postInitApplicationSQLRefs"), initDBOptions.PostInitApplicationSQLRefs)...)
+	result = append(result, validateSQLRefs(
+		basePath.Child("postInitSQLRefs"), initDBOptions.PostInitSQLRefs)...)
+	result = append(result, validateSQLRefs(
+		basePath.Child("postInitTemplateSQLRefs"), initDBOptions.PostInitTemplateSQLRefs)...)
+
+	return result
+}
+
+// validateSQLRefs checks that every referenced SQL file has both
+// a name and a key
+func validateSQLRefs(path *field.Path, refs *SQLRefs) field.ErrorList {
+	if refs == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	for _, item := range refs.SecretRefs {
+		if item.Name == "" || item.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					path.Child("secretRefs"),
+					item,
+					"key and name must be specified"))
 		}
+	}
 
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.ConfigMapRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "configMapRefs"),
-						item,
-						"key and name must be specified"))
-			}
+	for _, item := range refs.ConfigMapRefs {
+		if item.Name == "" || item.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					path.Child("configMapRefs"),
+					item,
+					"key and name must be specified"))
 		}
 	}
 
@@ -655,6 +829,58 @@ func (s Import) validateMicroservice() field.ErrorList {
 	return result
 }
 
+// validateImportFromBackup checks that a database can be imported
+// from the base backup of the source of the import
+func (r *Cluster) validateImportFromBackup() field.ErrorList {
+	if !r.ShouldImportFromBackup() {
+		return nil
+	}
+
+	var result field.ErrorList
+	importSpec := r.Spec.Bootstrap.InitDB.Import
+	basePath := field.NewPath("spec", "bootstrap", "initdb", "import", "source", "fromBackup")
+
+	if importSpec.Type != MicroserviceSnapshotType {
+		result = append(
+			result,
+			field.Invalid(
+				basePath,
+				importSpec.Type,
+				"Importing from a base backup is only available for the `microservice` import type"))
+	}
+
+	externalCluster, found := r.ExternalCluster(importSpec.Source.ExternalCluster)
+	if !found || externalCluster.BarmanObjectStore == nil {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "source", "externalCluster"),
+				importSpec.Source.ExternalCluster,
+				"Importing from a base backup requires an external cluster with a barmanObjectStore section"))
+	}
+
+	if importSpec.RestoreJobs > 1 {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "restoreJobs"),
+				importSpec.RestoreJobs,
+				"Concurrent restore jobs are not available when importing from a base backup"))
+	}
+
+	recoveryTarget := importSpec.Source.FromBackup.RecoveryTarget
+	if recoveryTarget != nil && recoveryTarget.TargetTime != "" {
+		if _, err := utils.ParseTargetTime(nil, recoveryTarget.TargetTime); err != nil {
+			result = append(result, field.Invalid(
+				basePath.Child("recoveryTarget", "targetTime"),
+				recoveryTarget.TargetTime,
+				"The format of TargetTime is invalid"))
+		}
+	}
+
+	return result
+}
+
 func (s Import) validateMonolith() field.ErrorList {
 	var result field.ErrorList
 
@@ -913,6 +1139,68 @@ func (r *Cluster) validateBootstrapRecoverySource() field.ErrorList {
 	return result
 }
 
+// validateBootstrapRecoveryOrigin is used to ensure that the recovery
+// section defines exactly one origin of the backup to be restored, and
+// that the WAL files can be fetched from an object store when needed
+func (r *Cluster) validateBootstrapRecoveryOrigin() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil {
+		return nil
+	}
+
+	recoveryPath := field.NewPath("spec", "bootstrap", "recovery")
+	recovery := r.Spec.Bootstrap.Recovery
+
+	switch {
+	case recovery.Backup == nil && recovery.Source == "" && recovery.VolumeSnapshots == nil:
+		return field.ErrorList{
+			field.Required(
+				recoveryPath,
+				"One of backup, source or volumeSnapshots is required to recover a cluster"),
+		}
+
+	case recovery.Backup != nil && recovery.Source != "":
+		return field.ErrorList{
+			field.Invalid(
+				recoveryPath.Child("source"),
+				recovery.Source,
+				"Recovery from a backup object is not compatible with a recovery source"),
+		}
+
+	case recovery.Backup != nil && recovery.Backup.Name == "":
+		return field.ErrorList{
+			field.Required(
+				recoveryPath.Child("backup", "name"),
+				"The name of the backup to be restored is required"),
+		}
+	}
+
+	if recovery.Source == "" {
+		return nil
+	}
+
+	// The existence of the external cluster is checked by
+	// validateBootstrapRecoverySource
+	externalCluster, found := r.ExternalCluster(recovery.Source)
+	if !found || externalCluster.BarmanObjectStore != nil {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"External cluster %v has no barmanObjectStore section, which is needed to find the backup to be restored",
+		recovery.Source)
+	if recovery.VolumeSnapshots != nil {
+		message = fmt.Sprintf(
+			"External cluster %v has no barmanObjectStore section, which is needed to fetch the WAL files "+
+				"to be replayed after restoring the volume snapshots. Remove the source to only restore "+
+				"the volume snapshots",
+			recovery.Source)
+	}
+
+	return field.ErrorList{
+		field.Invalid(recoveryPath.Child("source"), recovery.Source, message),
+	}
+}
+
 // validateBootstrapRecoveryDataSource is used to ensure that the data
 // source is correctly defined
 func (r *Cluster) validateBootstrapRecoveryDataSource() field.ErrorList {
@@ -1108,6 +1396,147 @@ func (r *Cluster) validateResources() field.ErrorList {
 	return result
 }
 
+// reservedContainerNames are the names of the containers created by the
+// operator in the instance pods
+var reservedContainerNames = stringset.From([]string{"postgres", "bootstrap-controller"})
+
+// reservedContainerPorts are the ports used by PostgreSQL and by the
+// instance manager, which share the network namespace with the sidecars
+var reservedContainerPorts = map[int32]string{
+	postgres.ServerPort:        "postgresql",
+	pkgurl.PostgresMetricsPort: "metrics",
+	pkgurl.StatusPort:          "status",
+	pkgurl.LocalPort:           "local",
+}
+
+// validateSidecars checks that the sidecar containers don't conflict
+// with the containers, the ports and the volumes of the instance pods,
+// and that their resource requests don't exceed their limits
+func (r *Cluster) validateSidecars() field.ErrorList {
+	var result field.ErrorList
+
+	volumeNames := stringset.From(r.GetSidecarVolumeNames())
+	containerNames := stringset.New()
+	for idx, sidecar := range r.Spec.Sidecars {
+		path := field.NewPath("spec", "sidecars").Index(idx)
+
+		switch {
+		case reservedContainerNames.Has(sidecar.Name):
+			result = append(result, field.Invalid(
+				path.Child("name"),
+				sidecar.Name,
+				"the name is reserved for the containers of the operator"))
+		case containerNames.Has(sidecar.Name):
+			result = append(result, field.Duplicate(path.Child("name"), sidecar.Name))
+		default:
+			for _, msg := range validationutil.IsDNS1123Label(sidecar.Name) {
+				result = append(result, field.Invalid(path.Child("name"), sidecar.Name, msg))
+			}
+		}
+		containerNames.Put(sidecar.Name)
+
+		if sidecar.Image == "" {
+			result = append(result, field.Required(path.Child("image"), "the image of the sidecar is required"))
+		}
+
+		if sidecar.ReadinessProbe != nil {
+			result = append(result, field.Forbidden(
+				path.Child("readinessProbe"),
+				"the readiness of the instance is only determined by PostgreSQL"))
+		}
+
+		for portIdx, port := range sidecar.Ports {
+			if portName, reserved := reservedContainerPorts[port.ContainerPort]; reserved {
+				result = append(result, field.Invalid(
+					path.Child("ports").Index(portIdx).Child("containerPort"),
+					port.ContainerPort,
+					fmt.Sprintf("the port is used by the instance (%s)", portName)))
+			}
+		}
+
+		for mountIdx, volumeMount := range sidecar.VolumeMounts {
+			if !volumeNames.Has(volumeMount.Name) {
+				result = append(result, field.NotSupported(
+					path.Child("volumeMounts").Index(mountIdx).Child("name"),
+					volumeMount.Name,
+					volumeNames.ToSortedList()))
+			}
+		}
+
+		for resourceName, request := range sidecar.Resources.Requests {
+			limit, ok := sidecar.Resources.Limits[resourceName]
+			if ok && request.Cmp(limit) > 0 {
+				result = append(result, field.Invalid(
+					path.Child("resources", "requests").Key(string(resourceName)),
+					request.String(),
+					"the request is greater than the limit"))
+			}
+		}
+	}
+
+	return result
+}
+
+// validateMaxConnections checks that max_connections, when set, is an
+// integer PostgreSQL can accept
+func (r *Cluster) validateMaxConnections() field.ErrorList {
+	rawMaxConnections, ok := r.Spec.PostgresConfiguration.Parameters[maxConnectionsParameter]
+	if !ok {
+		return nil
+	}
+
+	maxConnections, err := strconv.Atoi(strings.TrimSpace(rawMaxConnections))
+	if err != nil || maxConnections < 1 || maxConnections > maxConnectionsUpperBound {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", maxConnectionsParameter),
+				rawMaxConnections,
+				fmt.Sprintf("must be an integer between 1 and %d", maxConnectionsUpperBound)),
+		}
+	}
+
+	return nil
+}
+
+// validateParameterNames rejects the parameters which are not supported by
+// the PostgreSQL major version of the cluster, unless explicitly allowed
+func (r *Cluster) validateParameterNames() field.ErrorList {
+	pgVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
+
+	allowed := stringset.New()
+	for _, name := range r.Spec.PostgresConfiguration.AllowedUnknownParameters {
+		allowed.Put(strings.ToLower(name))
+	}
+
+	var result field.ErrorList
+	for _, name := range stringset.FromKeys(r.Spec.PostgresConfiguration.Parameters).ToSortedList() {
+		if allowed.Has(strings.ToLower(name)) {
+			continue
+		}
+
+		found, hasCatalog := postgres.IsParameterInCatalog(pgVersion, name)
+		if !hasCatalog {
+			// We don't know the parameters supported by this major version
+			return nil
+		}
+		if !found {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", name),
+				r.Spec.PostgresConfiguration.Parameters[name],
+				fmt.Sprintf("unknown parameter for PostgreSQL %d: check its name, or add it to "+
+					"`allowedUnknownParameters` if supported by the image",
+					postgres.GetPostgresMajorVersion(pgVersion)/10000)))
+		}
+	}
+
+	return result
+}
+
 // validateConfiguration determines whether a PostgreSQL configuration is valid
 func (r *Cluster) validateConfiguration() field.ErrorList {
 	var result field.ErrorList
@@ -1126,16 +1555,36 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 				"Unsupported PostgreSQL version. Versions 11 or newer are supported"))
 	}
 	info := postgres.ConfigurationInfo{
-		Settings:               postgres.CnpgConfigurationSettings,
-		MajorVersion:           pgVersion,
-		UserSettings:           r.Spec.PostgresConfiguration.Parameters,
-		IsReplicaCluster:       r.IsReplica(),
-		IsWalArchivingDisabled: utils.IsWalArchivingDisabled(&r.ObjectMeta),
+		Settings:                  postgres.CnpgConfigurationSettings,
+		MajorVersion:              pgVersion,
+		UserSettings:              r.Spec.PostgresConfiguration.Parameters,
+		IsReplicaCluster:          r.IsReplica(),
+		IsWalArchivingDisabled:    utils.IsWalArchivingDisabled(&r.ObjectMeta),
+		IsUnsafeDurabilityAllowed: utils.IsUnsafeDurabilityAllowed(&r.ObjectMeta),
 	}
 	sanitizedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
 
+	var disabledUnsafeDurabilityParameters []string
+	if !info.IsUnsafeDurabilityAllowed {
+		disabledUnsafeDurabilityParameters = postgres.GetDisabledUnsafeDurabilityParameters(
+			r.Spec.PostgresConfiguration.Parameters)
+		for _, key := range disabledUnsafeDurabilityParameters {
+			result = append(
+				result,
+				field.Invalid(
+					field.NewPath("spec", "postgresql", "parameters", key),
+					r.Spec.PostgresConfiguration.Parameters[key],
+					fmt.Sprintf("Disabling %s risks data loss and corruption: set the %s annotation "+
+						"to 'enabled' to acknowledge this risk", key, utils.UnsafeDurabilityAnnotationName)))
+		}
+	}
+
 	for key, value := range r.Spec.PostgresConfiguration.Parameters {
 		_, isFixed := postgres.FixedConfigurationParameters[key]
+		if isFixed && slices.Contains(disabledUnsafeDurabilityParameters, key) {
+			// already reported as an unsafe setting
+			continue
+		}
 		sanitizedValue, presentInSanitizedConfiguration := sanitizedParameters[key]
 		if isFixed && (!presentInSanitizedConfiguration || value != sanitizedValue) {
 			result = append(
@@ -1231,45 +1680,58 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 	return result
 }
 
+const (
+	minWalSizeKey     = "min_wal_size"
+	minWalSizeDefault = "80MB"
+	maxWalSizeKey     = "max_wal_size"
+	maxWalSizeDefault = "1GB"
+)
+
+// getWalSizeParameter gets the value of a WAL size parameter, as set by the
+// user or by the checkpoint tuning profile, together with the path of the
+// field setting it and whether it is set at all. The PostgreSQL default is
+// returned when the parameter is not set
+func getWalSizeParameter(
+	postgresConfig PostgresConfiguration,
+	key string,
+	defaultValue string,
+) (string, *field.Path, bool) {
+	parameterPath := field.NewPath("spec", "postgresql", "parameters", key)
+	if value := postgresConfig.Parameters[key]; value != "" {
+		return value, parameterPath, true
+	}
+
+	if value := postgresConfig.Checkpoint.GetParameters()[key]; value != "" {
+		return value, field.NewPath("spec", "postgresql", "checkpoint", "profile"), true
+	}
+
+	return defaultValue, parameterPath, false
+}
+
 // validateWalSizeConfiguration verifies that min_wal_size < max_wal_size < wal volume size
 func validateWalSizeConfiguration(
 	postgresConfig PostgresConfiguration, walVolumeSize *resource.Quantity,
 ) field.ErrorList {
-	const (
-		minWalSizeKey     = "min_wal_size"
-		minWalSizeDefault = "80MB"
-		maxWalSizeKey     = "max_wal_size"
-		maxWalSizeDefault = "1GB"
-	)
-
 	var result field.ErrorList
 
-	minWalSize, hasMinWalSize := postgresConfig.Parameters[minWalSizeKey]
-	if minWalSize == "" {
-		minWalSize = minWalSizeDefault
-		hasMinWalSize = false
-	}
+	minWalSize, minWalSizePath, hasMinWalSize := getWalSizeParameter(postgresConfig, minWalSizeKey, minWalSizeDefault)
 	minWalSizeValue, err := parsePostgresQuantityValue(minWalSize)
 	if err != nil {
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "postgresql", "parameters", minWalSizeKey),
+				minWalSizePath,
 				minWalSize,
 				fmt.Sprintf("Invalid value for configuration parameter %s", minWalSizeKey)))
 	}
 
-	maxWalSize, hasMaxWalSize := postgresConfig.Parameters[maxWalSizeKey]
-	if maxWalSize == "" {
-		maxWalSize = maxWalSizeDefault
-		hasMaxWalSize = false
-	}
+	maxWalSize, maxWalSizePath, hasMaxWalSize := getWalSizeParameter(postgresConfig, maxWalSizeKey, maxWalSizeDefault)
 	maxWalSizeValue, err := parsePostgresQuantityValue(maxWalSize)
 	if err != nil {
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "postgresql", "parameters", maxWalSizeKey),
+				maxWalSizePath,
 				maxWalSize,
 				fmt.Sprintf("Invalid value for configuration parameter %s", maxWalSizeKey)))
 	}
@@ -1279,7 +1741,7 @@ func validateWalSizeConfiguration(
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "postgresql", "parameters", minWalSizeKey),
+				minWalSizePath,
 				minWalSize,
 				fmt.Sprintf("Invalid vale. Parameter %s (default %s) should be smaller than parameter %s (default %s)",
 					minWalSizeKey, minWalSizeDefault, maxWalSizeKey, maxWalSizeDefault)))
@@ -1295,7 +1757,7 @@ func validateWalSizeConfiguration(
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "postgresql", "parameters", minWalSizeKey),
+				minWalSizePath,
 				minWalSize,
 				fmt.Sprintf("Invalid value. Parameter %s (default %s) should be smaller than WAL volume size",
 					minWalSizeKey, minWalSizeDefault)))
@@ -1307,7 +1769,7 @@ func validateWalSizeConfiguration(
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "postgresql", "parameters", maxWalSizeKey),
+				maxWalSizePath,
 				maxWalSize,
 				fmt.Sprintf("Invalid value. Parameter %s (default %s) should be smaller than WAL volume size",
 					maxWalSizeKey, maxWalSizeDefault)))
@@ -1316,6 +1778,64 @@ func validateWalSizeConfiguration(
 	return result
 }
 
+// validateWalSegmentSize verifies that the WAL segment size, in megabytes,
+// is a power of 2 between 1 and 1024, as supported by PostgreSQL, and
+// that min_wal_size and max_wal_size are at least twice its value, as
+// otherwise PostgreSQL refuses to start
+func validateWalSegmentSize(walSegmentSize int, postgresConfig PostgresConfiguration) field.ErrorList {
+	var result field.ErrorList
+
+	walSegmentSizePath := field.NewPath("spec", "bootstrap", "initdb", "walSegmentSize")
+	if !utils.IsPowerOfTwo(walSegmentSize) {
+		result = append(
+			result,
+			field.Invalid(
+				walSegmentSizePath,
+				walSegmentSize,
+				"WAL segment size must be a power of 2"))
+	}
+
+	if walSegmentSize < 1 || walSegmentSize > 1024 {
+		result = append(
+			result,
+			field.Invalid(
+				walSegmentSizePath,
+				walSegmentSize,
+				"WAL segment size must be between 1 and 1024 megabytes"))
+	}
+
+	if len(result) > 0 {
+		return result
+	}
+
+	minimumSize := resource.MustParse(fmt.Sprintf("%dMi", 2*walSegmentSize))
+	for _, parameter := range []struct {
+		key          string
+		defaultValue string
+	}{
+		{key: minWalSizeKey, defaultValue: minWalSizeDefault},
+		{key: maxWalSizeKey, defaultValue: maxWalSizeDefault},
+	} {
+		value, valuePath, _ := getWalSizeParameter(postgresConfig, parameter.key, parameter.defaultValue)
+
+		// Invalid values are already reported by validateWalSizeConfiguration
+		quantity, err := parsePostgresQuantityValue(value)
+		if err != nil || quantity.Cmp(minimumSize) >= 0 {
+			continue
+		}
+
+		result = append(
+			result,
+			field.Invalid(
+				valuePath,
+				value,
+				fmt.Sprintf("Parameter %s (default %s) must be at least twice the WAL segment size (%dMB)",
+					parameter.key, parameter.defaultValue, walSegmentSize)))
+	}
+
+	return result
+}
+
 // parsePostgresQuantityValue converts the  sizes in the PostgreSQL configuration
 // into kubernetes resource.Quantity values
 // Ref: Numeric with Unit @ https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
@@ -1417,16 +1937,37 @@ func (r *Cluster) validateImageChange(old *Cluster) field.ErrorList {
 		return result
 	}
 
-	status := postgres.IsUpgradePossible(oldMajor, newMajor)
+	if postgres.IsUpgradePossible(oldMajor, newMajor) {
+		return result
+	}
+
+	if newMajor < oldMajor {
+		// Going back to the major version of the data directory is allowed,
+		// as it aborts a major upgrade that hasn't been completed
+		if old.Status.PGDataImageInfo == nil ||
+			old.Status.PGDataImageInfo.MajorVersion != newMajor/10000 {
+			result = append(
+				result,
+				field.Invalid(
+					newImagePath,
+					newMajor,
+					fmt.Sprintf("can't downgrade from major %v to %v",
+						oldMajor/10000, newMajor/10000)))
+		}
+		return result
+	}
 
-	if !status {
+	// A replica cluster needs to follow the major version of its source,
+	// as the data directory is streamed or recovered from it
+	if r.IsReplica() {
 		result = append(
 			result,
 			field.Invalid(
 				newImagePath,
 				newMajor,
-				fmt.Sprintf("can't upgrade between majors %v and %v",
-					oldMajor, newMajor)))
+				fmt.Sprintf("can't upgrade a replica cluster from major %v to %v, "+
+					"its major version must match the one of the source",
+					oldMajor/10000, newMajor/10000)))
 	}
 
 	return result
@@ -1485,7 +2026,7 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 	}
 
 	switch recoveryTarget.TargetTLI {
-	case "", "latest":
+	case "", "latest", "current":
 		// Allowed non-numeric values
 	default:
 		// Everything else must be a valid positive integer
@@ -1493,7 +2034,7 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 			result = append(result, field.Invalid(
 				field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget", "targetTLI"),
 				recoveryTarget,
-				"recovery target timeline can be set to 'latest' or a positive integer"))
+				"recovery target timeline can be set to 'latest', 'current' or a positive integer"))
 		}
 	}
 
@@ -1883,6 +2424,24 @@ func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path
 				"one of connectionParameters and barmanObjectStore is required"))
 	}
 
+	if externalCluster.BarmanObjectStore != nil {
+		result = append(result, validateObjectStoreEndpoint(
+			path.Child("barmanObjectStore"),
+			externalCluster.BarmanObjectStore)...)
+	}
+
+	if externalCluster.ArchiveObjectStore != nil {
+		if externalCluster.BarmanObjectStore == nil {
+			result = append(result, field.Invalid(
+				path.Child("archiveObjectStore"),
+				"",
+				"the archive object store requires barmanObjectStore to be configured"))
+		}
+		result = append(result, validateObjectStoreEndpoint(
+			path.Child("archiveObjectStore"),
+			externalCluster.ArchiveObjectStore)...)
+	}
+
 	return result
 }
 
@@ -2011,15 +2570,56 @@ func (r *Cluster) validateReplicaMode() field.ErrorList {
 	}
 
 	result = append(result, r.validateReplicaClusterExternalClusters()...)
+	result = append(result, r.validateReplicaClusterArchive()...)
 
 	return result
 }
 
-func (r *Cluster) validateReplicaClusterExternalClusters() field.ErrorList {
-	var result field.ErrorList
+// validateReplicaClusterArchive ensures that a replica cluster archives
+// its WAL files in a different location than the one of its source.
+// Once promoted, the replica cluster writes to its own archive, and
+// sharing it with the source would mix the WAL files of two clusters
+func (r *Cluster) validateReplicaClusterArchive() field.ErrorList {
 	replicaClusterConf := r.Spec.ReplicaCluster
-	if replicaClusterConf == nil {
-		return result
+	if replicaClusterConf == nil || r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return nil
+	}
+
+	source, found := r.ExternalCluster(replicaClusterConf.Source)
+	if !found || source.BarmanObjectStore == nil {
+		return nil
+	}
+	sourceLocation := getObjectStoreLocation(source.BarmanObjectStore, source.GetServerName())
+
+	var result field.ErrorList
+	backupPath := field.NewPath("spec", "backup")
+	if getObjectStoreLocation(r.Spec.Backup.BarmanObjectStore, r.Name) == sourceLocation {
+		result = append(result, field.Invalid(
+			backupPath.Child("barmanObjectStore", "destinationPath"),
+			r.Spec.Backup.BarmanObjectStore.DestinationPath,
+			fmt.Sprintf("a replica cluster can't archive in the same location as its source %q, "+
+				"use a different destinationPath or serverName", replicaClusterConf.Source)))
+	}
+
+	for idx := range r.Spec.Backup.AdditionalWalDestinations {
+		destination := &r.Spec.Backup.AdditionalWalDestinations[idx].BarmanObjectStoreConfiguration
+		if getObjectStoreLocation(destination, r.Name) == sourceLocation {
+			result = append(result, field.Invalid(
+				backupPath.Child("additionalWalDestinations").Index(idx).Child("destinationPath"),
+				destination.DestinationPath,
+				fmt.Sprintf("a replica cluster can't archive in the same location as its source %q, "+
+					"use a different destinationPath or serverName", replicaClusterConf.Source)))
+		}
+	}
+
+	return result
+}
+
+func (r *Cluster) validateReplicaClusterExternalClusters() field.ErrorList {
+	var result field.ErrorList
+	replicaClusterConf := r.Spec.ReplicaCluster
+	if replicaClusterConf == nil {
+		return result
 	}
 
 	// Check that the externalCluster references are correct
@@ -2063,9 +2663,34 @@ func (r *Cluster) validateReplicaClusterExternalClusters() field.ErrorList {
 // This code is almost a verbatim copy of
 // https://github.com/kubernetes/kubernetes/blob/4d38d21/pkg/apis/core/validation/validation.go#L3147
 func (r *Cluster) validateTolerations() field.ErrorList {
-	path := field.NewPath("spec", "affinity", "toleration")
+	return validateTolerationList(field.NewPath("spec", "affinity", "toleration"), r.Spec.Affinity.Tolerations)
+}
+
+// validateRolePlacement check and validate the placement rules
+// of the pods depending on the role of the instances
+func (r *Cluster) validateRolePlacement() field.ErrorList {
+	if r.Spec.RolePlacement == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "rolePlacement")
+	var result field.ErrorList
+	if r.Spec.RolePlacement.Primary != nil {
+		result = append(result, validateTolerationList(
+			path.Child("primary", "tolerations"), r.Spec.RolePlacement.Primary.Tolerations)...)
+	}
+	if r.Spec.RolePlacement.Replica != nil {
+		result = append(result, validateTolerationList(
+			path.Child("replica", "tolerations"), r.Spec.RolePlacement.Replica.Tolerations)...)
+	}
+
+	return result
+}
+
+// validateTolerationList validates the passed list of tolerations
+func validateTolerationList(path *field.Path, tolerations []v1.Toleration) field.ErrorList {
 	allErrors := field.ErrorList{}
-	for i, toleration := range r.Spec.Affinity.Tolerations {
+	for i, toleration := range tolerations {
 		toleration := toleration
 		idxPath := path.Index(i)
 		// validate the toleration key
@@ -2215,9 +2840,340 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		}
 	}
 
+	if data := r.Spec.Backup.BarmanObjectStore.Data; data != nil && data.MaxBandwidth != nil &&
+		data.MaxBandwidth.Sign() <= 0 {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backup", "barmanObjectStore", "data", "maxBandwidth"),
+			data.MaxBandwidth.String(),
+			"the backup bandwidth limit must be greater than zero",
+		))
+	}
+
+	allErrors = append(allErrors, validateObjectStoreEndpoint(
+		field.NewPath("spec", "backup", "barmanObjectStore"),
+		r.Spec.Backup.BarmanObjectStore)...)
+
 	return allErrors
 }
 
+// validateObjectStoreEndpoint checks the endpoint of an object store,
+// together with its CA bundle and the addressing style of the buckets
+func validateObjectStoreEndpoint(
+	path *field.Path,
+	configuration *BarmanObjectStoreConfiguration,
+) field.ErrorList {
+	var result field.ErrorList
+
+	var endpoint *url.URL
+	if configuration.EndpointURL != "" {
+		parsedURL, err := url.Parse(configuration.EndpointURL)
+		switch {
+		case err != nil:
+			result = append(result, field.Invalid(
+				path.Child("endpointURL"),
+				configuration.EndpointURL,
+				fmt.Sprintf("not a valid URL: %v", err)))
+		case parsedURL.Scheme != "http" && parsedURL.Scheme != "https":
+			result = append(result, field.Invalid(
+				path.Child("endpointURL"),
+				configuration.EndpointURL,
+				"the endpoint must use the http or https scheme"))
+		case parsedURL.Host == "":
+			result = append(result, field.Invalid(
+				path.Child("endpointURL"),
+				configuration.EndpointURL,
+				"the endpoint must contain a host name"))
+		default:
+			endpoint = parsedURL
+		}
+	}
+
+	if configuration.EndpointCA != nil {
+		if configuration.EndpointCA.Name == "" || configuration.EndpointCA.Key == "" {
+			result = append(result, field.Invalid(
+				path.Child("endpointCA"),
+				configuration.EndpointCA,
+				"the CA bundle requires both the name and the key of the secret"))
+		}
+		if endpoint != nil && endpoint.Scheme != "https" {
+			result = append(result, field.Invalid(
+				path.Child("endpointCA"),
+				configuration.EndpointCA.Name,
+				"the CA bundle requires an endpoint using the https scheme"))
+		}
+	}
+
+	if configuration.ForcePathStyle && configuration.BarmanCredentials.AWS == nil {
+		result = append(result, field.Invalid(
+			path.Child("forcePathStyle"),
+			configuration.ForcePathStyle,
+			"path-style addressing is only available with s3Credentials"))
+	}
+
+	return result
+}
+
+// compressionLevelRanges are the compression levels accepted by
+// every compression algorithm supporting them
+var compressionLevelRanges = map[CompressionType][2]int{
+	CompressionTypeGzip:  {1, 9},
+	CompressionTypeBzip2: {1, 9},
+	CompressionTypeXz:    {1, 9},
+	CompressionTypeZstd:  {1, 22},
+	CompressionTypeLz4:   {1, 12},
+}
+
+// validateWalCompression validates the compression level of the WAL
+// files, for every WAL destination
+func (r *Cluster) validateWalCompression() field.ErrorList {
+	if r.Spec.Backup == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	backupPath := field.NewPath("spec", "backup")
+	if r.Spec.Backup.BarmanObjectStore != nil {
+		result = append(result, validateWalCompressionLevel(
+			backupPath.Child("barmanObjectStore", "wal"),
+			r.Spec.Backup.BarmanObjectStore.Wal)...)
+	}
+	for idx := range r.Spec.Backup.AdditionalWalDestinations {
+		result = append(result, validateWalCompressionLevel(
+			backupPath.Child("additionalWalDestinations").Index(idx).Child("wal"),
+			r.Spec.Backup.AdditionalWalDestinations[idx].Wal)...)
+	}
+
+	return result
+}
+
+func validateWalCompressionLevel(path *field.Path, configuration *WalBackupConfiguration) field.ErrorList {
+	if configuration == nil || configuration.CompressionLevel == nil {
+		return nil
+	}
+
+	level := *configuration.CompressionLevel
+	if configuration.Compression == CompressionTypeNone {
+		return field.ErrorList{field.Invalid(
+			path.Child("compressionLevel"),
+			level,
+			"the compression level requires a compression algorithm")}
+	}
+
+	levelRange, ok := compressionLevelRanges[configuration.Compression]
+	if !ok {
+		return field.ErrorList{field.Invalid(
+			path.Child("compressionLevel"),
+			level,
+			fmt.Sprintf("the compression level is not supported with %v", configuration.Compression))}
+	}
+
+	if level < levelRange[0] || level > levelRange[1] {
+		return field.ErrorList{field.Invalid(
+			path.Child("compressionLevel"),
+			level,
+			fmt.Sprintf("the compression level of %v must be between %d and %d",
+				configuration.Compression, levelRange[0], levelRange[1]))}
+	}
+
+	return nil
+}
+
+// validateBackupVerification validates the configuration of the
+// periodic backup verification
+func (r *Cluster) validateBackupVerification() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.Verification == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	verificationPath := field.NewPath("spec", "backup", "verification")
+
+	if r.Spec.Backup.Verification.Schedule != "" {
+		if _, err := cron.Parse(r.Spec.Backup.Verification.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				verificationPath.Child("schedule"),
+				r.Spec.Backup.Verification.Schedule,
+				err.Error()))
+		}
+	}
+
+	if r.Spec.Backup.Verification.Enabled && r.Spec.Backup.BarmanObjectStore == nil {
+		result = append(result, field.Invalid(
+			verificationPath.Child("enabled"),
+			r.Spec.Backup.Verification.Enabled,
+			"backup verification requires barmanObjectStore to be configured"))
+	}
+
+	return result
+}
+
+// validateWalArchiveCheck validates the configuration of the periodic
+// check of the WAL archive
+func (r *Cluster) validateWalArchiveCheck() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.WalArchiveCheck == nil {
+		return nil
+	}
+
+	if r.Spec.Backup.WalArchiveCheck.Enabled && r.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "backup", "walArchiveCheck", "enabled"),
+				r.Spec.Backup.WalArchiveCheck.Enabled,
+				"the WAL archive check requires barmanObjectStore to be configured"),
+		}
+	}
+
+	return nil
+}
+
+// validateAdditionalWalDestinations validates the further object stores
+// where the WAL files are archived
+func (r *Cluster) validateAdditionalWalDestinations() field.ErrorList {
+	if r.Spec.Backup == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	backupPath := field.NewPath("spec", "backup")
+	destinationsPath := backupPath.Child("additionalWalDestinations")
+
+	if r.Spec.Backup.WalArchiveQuorum != nil {
+		destinations := 1 + len(r.Spec.Backup.AdditionalWalDestinations)
+		if *r.Spec.Backup.WalArchiveQuorum > destinations {
+			result = append(result, field.Invalid(
+				backupPath.Child("walArchiveQuorum"),
+				*r.Spec.Backup.WalArchiveQuorum,
+				fmt.Sprintf("the quorum can't be greater than the number of WAL destinations (%d)", destinations)))
+		}
+	}
+
+	if len(r.Spec.Backup.AdditionalWalDestinations) == 0 {
+		return result
+	}
+
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		return append(result, field.Invalid(
+			destinationsPath,
+			"",
+			"additional WAL destinations require barmanObjectStore to be configured"))
+	}
+
+	// The object store location of every destination, used to detect
+	// destinations archiving in the same place
+	getLocation := func(configuration *BarmanObjectStoreConfiguration) string {
+		return getObjectStoreLocation(configuration, r.Name)
+	}
+	locations := stringset.From([]string{getLocation(r.Spec.Backup.BarmanObjectStore)})
+
+	for idx := range r.Spec.Backup.AdditionalWalDestinations {
+		destination := &r.Spec.Backup.AdditionalWalDestinations[idx]
+		destinationPath := destinationsPath.Index(idx)
+
+		credentialsCount := 0
+		if destination.BarmanCredentials.Azure != nil {
+			credentialsCount++
+			result = append(result, destination.BarmanCredentials.Azure.validateAzureCredentials(
+				destinationPath.Child("azureCredentials"))...)
+		}
+		if destination.BarmanCredentials.AWS != nil {
+			credentialsCount++
+			result = append(result, destination.BarmanCredentials.AWS.validateAwsCredentials(
+				destinationPath.Child("s3Credentials"))...)
+		}
+		if destination.BarmanCredentials.Google != nil {
+			credentialsCount++
+			result = append(result, destination.BarmanCredentials.Google.validateGCSCredentials(
+				destinationPath.Child("googleCredentials"))...)
+		}
+		if credentialsCount != 1 {
+			result = append(result, field.Invalid(
+				destinationPath,
+				destination.Name,
+				"one and only one of azureCredentials, s3Credentials and googleCredentials are required"))
+		}
+
+		if destination.EndpointCA != nil {
+			result = append(result, field.Invalid(
+				destinationPath.Child("endpointCA"),
+				destination.EndpointCA.Name,
+				"custom endpoint CA bundles are not supported for additional WAL destinations"))
+		}
+		result = append(result, validateObjectStoreEndpoint(
+			destinationPath,
+			&destination.BarmanObjectStoreConfiguration)...)
+
+		location := getLocation(&destination.BarmanObjectStoreConfiguration)
+		if locations.Has(location) {
+			result = append(result, field.Invalid(
+				destinationPath.Child("destinationPath"),
+				destination.DestinationPath,
+				"every WAL destination must archive in a different location"))
+		}
+		locations.Put(location)
+	}
+
+	return result
+}
+
+// getObjectStoreLocation gets the location where the WAL files and the
+// base backups are archived in an object store, using the passed server
+// name when the configuration doesn't specify one
+func getObjectStoreLocation(configuration *BarmanObjectStoreConfiguration, defaultServerName string) string {
+	serverName := configuration.ServerName
+	if serverName == "" {
+		serverName = defaultServerName
+	}
+	return strings.TrimSuffix(configuration.DestinationPath, "/") + "/" + serverName
+}
+
+// validatePodDisruptionBudget validates the disruption policies
+// used to generate the PodDisruptionBudget resources
+func (r *Cluster) validatePodDisruptionBudget() field.ErrorList {
+	if r.Spec.PodDisruptionBudget == nil {
+		return nil
+	}
+
+	pdbPath := field.NewPath("spec", "podDisruptionBudget")
+	return append(
+		validatePodDisruptionBudgetPolicy(r.Spec.PodDisruptionBudget.Primary, pdbPath.Child("primary")),
+		validatePodDisruptionBudgetPolicy(r.Spec.PodDisruptionBudget.Replicas, pdbPath.Child("replicas"))...,
+	)
+}
+
+func validatePodDisruptionBudgetPolicy(policy *PodDisruptionBudgetPolicy, path *field.Path) field.ErrorList {
+	if policy == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	if policy.MinAvailable != nil && policy.MaxUnavailable != nil {
+		result = append(result, field.Invalid(
+			path,
+			"",
+			"minAvailable and maxUnavailable are mutually exclusive"))
+	}
+
+	validateValue := func(value *intstr.IntOrString, valuePath *field.Path) {
+		if value == nil {
+			return
+		}
+
+		scaledValue, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+		switch {
+		case err != nil:
+			result = append(result, field.Invalid(valuePath, value.String(), err.Error()))
+		case scaledValue < 0:
+			result = append(result, field.Invalid(valuePath, value.String(), "must not be negative"))
+		case value.Type == intstr.String && scaledValue > 100:
+			result = append(result, field.Invalid(valuePath, value.String(), "must not be greater than 100%"))
+		}
+	}
+	validateValue(policy.MinAvailable, path.Child("minAvailable"))
+	validateValue(policy.MaxUnavailable, path.Child("maxUnavailable"))
+
+	return result
+}
+
 func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	if r.Spec.ReplicationSlots == nil {
 		r.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
@@ -2274,6 +3230,83 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	return nil
 }
 
+// validateLogicalReplicationSlots validates the managed logical replication
+// slots, which can't be confused with the HA ones and require the
+// logical wal_level
+func (r *Cluster) validateLogicalReplicationSlots() field.ErrorList {
+	slots := r.Spec.ReplicationSlots.GetLogicalSlots()
+	if len(slots) == 0 {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "replicationSlots", "logical")
+
+	if walLevel, ok := r.Spec.PostgresConfiguration.Parameters[postgres.ParameterWalLevel]; ok &&
+		walLevel != "logical" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "parameters", postgres.ParameterWalLevel),
+			walLevel,
+			"Managed logical replication slots require `wal_level` to be set to `logical`"))
+	}
+
+	slotPrefix := r.Spec.ReplicationSlots.HighAvailability.GetSlotPrefix()
+	for idx, slot := range slots {
+		if strings.HasPrefix(slot.Name, slotPrefix) {
+			result = append(result, field.Invalid(
+				basePath.Index(idx).Child("name"),
+				slot.Name,
+				fmt.Sprintf("The name of the logical replication slot can't start with %q, "+
+					"reserved for the high availability replication slots", slotPrefix)))
+		}
+	}
+
+	return result
+}
+
+// validateScheduledMaintenance validates the configuration of the
+// scheduled maintenance of the databases
+func (r *Cluster) validateScheduledMaintenance() field.ErrorList {
+	configuration := r.Spec.ScheduledMaintenance
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "scheduledMaintenance")
+
+	if configuration.Schedule != "" {
+		if _, err := cron.Parse(configuration.Schedule); err != nil {
+			result = append(result, field.Invalid(
+				basePath.Child("schedule"),
+				configuration.Schedule,
+				err.Error()))
+		}
+	}
+
+	for idx, statement := range configuration.SQL {
+		if strings.TrimSpace(statement) == "" {
+			result = append(result, field.Invalid(
+				basePath.Child("sql").Index(idx),
+				statement,
+				"the maintenance statement can't be empty"))
+		}
+	}
+
+	// REINDEX CONCURRENTLY has been introduced in PostgreSQL 12
+	if configuration.Enabled && len(configuration.SQL) == 0 {
+		if pgVersion, err := r.GetPostgresqlVersion(); err == nil && pgVersion < 120000 {
+			result = append(result, field.Invalid(
+				basePath.Child("sql"),
+				configuration.SQL,
+				"the default maintenance statement, REINDEX DATABASE CONCURRENTLY, "+
+					"requires PostgreSQL 12 or newer"))
+		}
+	}
+
+	return result
+}
+
 func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	newReplicationSlots := r.Spec.ReplicationSlots
 	oldReplicationSlots := old.Spec.ReplicationSlots
@@ -2582,20 +3615,95 @@ func (r *Cluster) validateManagedRoles() field.ErrorList {
 func (r *Cluster) validateManagedExtensions() field.ErrorList {
 	allErrors := field.ErrorList{}
 
+	allErrors = append(allErrors, r.validateDeclaredExtensions()...)
 	allErrors = append(allErrors, r.validatePgFailoverSlots()...)
 	return allErrors
 }
 
-func (r *Cluster) validatePgFailoverSlots() field.ErrorList {
+// extensionNameRegex matches the names of the extensions which
+// can be declared with a recipe, which are used unquoted in SQL
+var extensionNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// sharedPreloadLibraryRegex matches the names of the libraries which can be
+// added to shared_preload_libraries, which is a comma-separated list
+var sharedPreloadLibraryRegex = regexp.MustCompile(`^[A-Za-z0-9_$./-]+$`)
+
+// validateDeclaredExtensions checks that the declared extensions are
+// supported by the operator, or that they have a valid recipe
+func (r *Cluster) validateDeclaredExtensions() field.ErrorList {
+	var result field.ErrorList
+
+	for i, extension := range r.Spec.PostgresConfiguration.Extensions {
+		fieldPath := field.NewPath("spec", "postgresql", "extensions").Index(i)
+		_, found := postgres.GetManagedExtension(extension.Name)
+		switch {
+		case extension.Recipe == nil && !found:
+			result = append(result, field.NotSupported(
+				fieldPath.Child("name"),
+				extension.Name,
+				getManagedExtensionNames()))
+		case extension.Recipe != nil && found:
+			result = append(result, field.Forbidden(
+				fieldPath.Child("recipe"),
+				"the recipe can't be set for the extensions supported by the operator"))
+		case extension.Recipe != nil:
+			result = append(result, validateManagedExtensionRecipe(fieldPath, extension)...)
+		}
+	}
+
+	return result
+}
+
+// validateManagedExtensionRecipe checks the recipe of an
+// extension which is not supported by the operator
+func validateManagedExtensionRecipe(fieldPath *field.Path, extension ManagedExtensionConfiguration) field.ErrorList {
 	var result field.ErrorList
-	var pgFailoverSlots postgres.ManagedExtension
 
-	for i, ext := range postgres.ManagedExtensions {
-		if ext.Name == "pg_failover_slots" {
-			pgFailoverSlots = postgres.ManagedExtensions[i]
+	if !extensionNameRegex.MatchString(extension.Name) {
+		result = append(result, field.Invalid(
+			fieldPath.Child("name"),
+			extension.Name,
+			"must be made of lowercase letters, digits and underscores"))
+	}
+
+	for i, library := range extension.Recipe.SharedPreloadLibraries {
+		if !sharedPreloadLibraryRegex.MatchString(library) {
+			result = append(result, field.Invalid(
+				fieldPath.Child("recipe", "sharedPreloadLibraries").Index(i),
+				library,
+				"is not a valid library name"))
+		}
+	}
+
+	for _, name := range stringset.FromKeys(extension.Recipe.Parameters).ToSortedList() {
+		if !strings.Contains(name, ".") {
+			result = append(result, field.Invalid(
+				fieldPath.Child("recipe", "parameters").Key(name),
+				extension.Recipe.Parameters[name],
+				"only the parameters of the extensions, like cron.database_name, are allowed"))
 		}
 	}
-	if !pgFailoverSlots.IsUsed(r.Spec.PostgresConfiguration.Parameters) {
+
+	return result
+}
+
+// getManagedExtensionNames gets the names of the extensions
+// managed by the operator
+func getManagedExtensionNames() []string {
+	result := make([]string, 0, len(postgres.ManagedExtensions))
+	for _, extension := range postgres.ManagedExtensions {
+		result = append(result, extension.Name)
+	}
+	return result
+}
+
+func (r *Cluster) validatePgFailoverSlots() field.ErrorList {
+	var result field.ErrorList
+	pgFailoverSlots, _ := postgres.GetManagedExtension("pg_failover_slots")
+	if !pgFailoverSlots.IsEnabled(
+		r.Spec.PostgresConfiguration.GetDeclaredExtensions(),
+		r.Spec.PostgresConfiguration.Parameters,
+	) {
 		return nil
 	}
 
@@ -2646,19 +3754,1008 @@ func (r *Cluster) validatePgFailoverSlots() field.ErrorList {
 	return result
 }
 
-func (r *Cluster) getAdmissionWarnings() admission.Warnings {
-	return r.getMaintenanceWindowsAdmissionWarnings()
-}
+// validateLogDestination checks that the requested log format
+// is supported by the PostgreSQL version in use
+func (r *Cluster) validateLogDestination() field.ErrorList {
+	if r.Spec.PostgresConfiguration.LogDestination != LogDestinationJSON {
+		return nil
+	}
 
-func (r *Cluster) getMaintenanceWindowsAdmissionWarnings() admission.Warnings {
-	var result admission.Warnings
+	pgVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
 
-	if r.Spec.NodeMaintenanceWindow != nil {
-		result = append(
-			result,
-			"Consider using `.spec.enablePDB` instead of the node maintenance window feature")
+	if pgVersion < 150000 {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "postgresql", "logDestination"),
+				r.Spec.PostgresConfiguration.LogDestination,
+				"jsonlog requires PostgreSQL 15 or newer"),
+		}
+	}
+
+	return nil
+}
+
+// validateHotStandbyFeedbackInstances checks that the instances where
+// hot_standby_feedback is selectively enabled belong to the cluster
+func (r *Cluster) validateHotStandbyFeedbackInstances() field.ErrorList {
+	var result field.ErrorList
+
+	instances := r.Spec.PostgresConfiguration.HotStandbyFeedbackInstances
+	if len(instances) == 0 {
+		return nil
+	}
+
+	basePath := field.NewPath("spec", "postgresql", "hotStandbyFeedbackInstances")
+
+	const hotStandbyFeedbackKey = "hot_standby_feedback"
+	if _, ok := r.Spec.PostgresConfiguration.Parameters[hotStandbyFeedbackKey]; ok {
+		result = append(
+			result,
+			field.Forbidden(
+				basePath,
+				fmt.Sprintf("cannot be used together with the `%s` parameter", hotStandbyFeedbackKey)))
+	}
+
+	for idx, instanceName := range instances {
+		if err := r.validateInstanceName(basePath.Index(idx), instanceName); err != nil {
+			result = append(result, err)
+		}
+	}
+
+	return result
+}
+
+// validateInstanceName checks that the passed name is the one of an
+// instance of the cluster, that is the cluster name followed by a
+// positive serial number
+func (r *Cluster) validateInstanceName(fieldPath *field.Path, instanceName string) *field.Error {
+	serial, found := strings.CutPrefix(instanceName, r.Name+"-")
+	if number, err := strconv.Atoi(serial); found && err == nil && number >= 1 {
+		return nil
+	}
+
+	return field.Invalid(
+		fieldPath,
+		instanceName,
+		fmt.Sprintf("must be the name of an instance of the cluster, like %s-1", r.Name))
+}
+
+// validateDelayedReplicas checks that the delayed replicas belong to the
+// cluster, leaving at least an instance which can be promoted, and that
+// the delay is a valid duration
+func (r *Cluster) validateDelayedReplicas() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.DelayedReplicas
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "delayedReplicas")
+
+	delayedInstances := stringset.New()
+	for idx, instanceName := range configuration.Instances {
+		fieldPath := basePath.Child("instances").Index(idx)
+		if err := r.validateInstanceName(fieldPath, instanceName); err != nil {
+			result = append(result, err)
+		}
+		if delayedInstances.Has(instanceName) {
+			result = append(result, field.Duplicate(fieldPath, instanceName))
+		}
+		delayedInstances.Put(instanceName)
+	}
+
+	if delayedInstances.Len() >= r.Spec.Instances {
+		result = append(
+			result,
+			field.Invalid(
+				basePath.Child("instances"),
+				configuration.Instances,
+				"at least one instance must not be delayed"))
+	}
+
+	// PostgreSQL stores recovery_min_apply_delay as an integer number of milliseconds
+	const maxDelay = math.MaxInt32 * time.Millisecond
+
+	fieldPath := basePath.Child("minApplyDelay")
+	duration, err := time.ParseDuration(configuration.MinApplyDelay)
+	switch {
+	case err != nil:
+		result = append(result, field.Invalid(fieldPath, configuration.MinApplyDelay, err.Error()))
+	case duration < time.Millisecond:
+		result = append(result, field.Invalid(fieldPath, configuration.MinApplyDelay, "must be at least 1ms"))
+	case duration > maxDelay:
+		result = append(result, field.Invalid(fieldPath, configuration.MinApplyDelay,
+			fmt.Sprintf("cannot be greater than %v", maxDelay)))
+	}
+
+	return result
+}
+
+// validateAdaptiveArchiveTimeout checks the range where archive_timeout
+// is automatically tuned
+func (r *Cluster) validateAdaptiveArchiveTimeout() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.AdaptiveArchiveTimeout
+	if !configuration.IsEnabled() {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "adaptiveArchiveTimeout")
+
+	const archiveTimeoutKey = "archive_timeout"
+	if _, ok := r.Spec.PostgresConfiguration.Parameters[archiveTimeoutKey]; ok {
+		result = append(
+			result,
+			field.Forbidden(
+				basePath,
+				fmt.Sprintf("cannot be used together with the `%s` parameter", archiveTimeoutKey)))
+	}
+
+	// The WAL generation rate is measured with pg_stat_wal
+	if pgVersion, err := r.GetPostgresqlVersion(); err == nil && pgVersion < 140000 {
+		result = append(
+			result,
+			field.Invalid(
+				basePath.Child("enabled"),
+				configuration.Enabled,
+				"the automatic tuning of archive_timeout requires PostgreSQL 14 or newer"))
+	}
+
+	if configuration.GetMinTimeout() > configuration.GetMaxTimeout() {
+		result = append(
+			result,
+			field.Invalid(
+				basePath.Child("minTimeout"),
+				configuration.MinTimeout,
+				"cannot be greater than maxTimeout"))
+	}
+
+	return result
+}
+
+// tlsProtocolVersions are the TLS protocol versions supported
+// by PostgreSQL, in ascending order
+var tlsProtocolVersions = []string{"TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}
+
+// tlsCipherRegex matches an OpenSSL cipher name, or a cipher string
+// like `HIGH` or `!aNULL`
+var tlsCipherRegex = regexp.MustCompile(`^[A-Za-z0-9!+@=_.-]+$`)
+
+// validatePostgresTLS validates the TLS settings of PostgreSQL
+func (r *Cluster) validatePostgresTLS() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.TLS
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "tls")
+	parameters := r.Spec.PostgresConfiguration.Parameters
+
+	if configuration.MinProtocolVersion != "" || len(configuration.Ciphers) > 0 {
+		// ssl_min_protocol_version and ssl_max_protocol_version have
+		// been introduced in PostgreSQL 12
+		if pgVersion, err := r.GetPostgresqlVersion(); err == nil && pgVersion < 120000 {
+			result = append(result, field.Invalid(
+				basePath,
+				configuration,
+				"the TLS configuration requires PostgreSQL 12 or newer"))
+		}
+	}
+
+	if configuration.MinProtocolVersion != "" {
+		fieldPath := basePath.Child("minProtocolVersion")
+		minIndex := slices.Index(tlsProtocolVersions, configuration.MinProtocolVersion)
+		if _, ok := parameters["ssl_min_protocol_version"]; ok {
+			result = append(result, field.Forbidden(
+				fieldPath,
+				"cannot be used together with the `ssl_min_protocol_version` parameter"))
+		}
+
+		maxProtocolVersion, ok := parameters["ssl_max_protocol_version"]
+		maxIndex := slices.Index(tlsProtocolVersions, maxProtocolVersion)
+		switch {
+		case minIndex < 0:
+			result = append(result, field.NotSupported(
+				fieldPath,
+				configuration.MinProtocolVersion,
+				tlsProtocolVersions))
+		case ok && maxIndex >= 0 && maxIndex < minIndex:
+			result = append(result, field.Invalid(
+				fieldPath,
+				configuration.MinProtocolVersion,
+				fmt.Sprintf("cannot be higher than the `ssl_max_protocol_version` parameter (%s)",
+					maxProtocolVersion)))
+		}
+	}
+
+	if len(configuration.Ciphers) > 0 {
+		if _, ok := parameters["ssl_ciphers"]; ok {
+			result = append(result, field.Forbidden(
+				basePath.Child("ciphers"),
+				"cannot be used together with the `ssl_ciphers` parameter"))
+		}
+	}
+	for idx, cipher := range configuration.Ciphers {
+		if !tlsCipherRegex.MatchString(cipher) {
+			result = append(result, field.Invalid(
+				basePath.Child("ciphers").Index(idx),
+				cipher,
+				"is not a valid OpenSSL cipher name"))
+		}
+	}
+
+	return result
+}
+
+// validateCertificateAuthentication validates the mappings between
+// the common names of the client certificates and the PostgreSQL roles
+func (r *Cluster) validateCertificateAuthentication() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.CertificateAuthentication
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "certificateAuthentication")
+
+	knownRoles := make(map[string]bool)
+	if owner := r.GetApplicationDatabaseOwner(); owner != "" {
+		knownRoles[owner] = true
+	}
+	if r.Spec.Managed != nil {
+		for _, role := range r.Spec.Managed.Roles {
+			if role.Ensure != EnsureAbsent {
+				knownRoles[role.Name] = true
+			}
+		}
+	}
+
+	for idx, mapping := range configuration.Mappings {
+		mappingPath := basePath.Child("mappings").Index(idx)
+
+		switch {
+		case mapping.CommonName == "" || mapping.CommonName == "/":
+			result = append(result, field.Invalid(
+				mappingPath.Child("commonName"),
+				mapping.CommonName,
+				"the common name can't be empty"))
+		case strings.ContainsAny(mapping.CommonName, "\"#\n"):
+			result = append(result, field.Invalid(
+				mappingPath.Child("commonName"),
+				mapping.CommonName,
+				"the common name can't contain double quotes, hash signs or new lines"))
+		case strings.HasPrefix(mapping.CommonName, "/") && strings.ContainsAny(mapping.CommonName, " \t"):
+			// The regular expressions are not quoted, as this would
+			// prevent PostgreSQL from recognizing them
+			result = append(result, field.Invalid(
+				mappingPath.Child("commonName"),
+				mapping.CommonName,
+				"the regular expression can't contain spaces, use \\s instead"))
+		}
+
+		if !knownRoles[mapping.Role] {
+			result = append(result, field.Invalid(
+				mappingPath.Child("role"),
+				mapping.Role,
+				"the role must be a managed role or the owner of the application database"))
+		}
+	}
+
+	for idx, secret := range configuration.AdditionalClientCASecrets {
+		if secret.Name == "" {
+			result = append(result, field.Required(
+				basePath.Child("additionalClientCASecrets").Index(idx).Child("name"),
+				"the name of the secret is required"))
+		}
+	}
+
+	return result
+}
+
+// validateTimeouts checks the default timeouts of the cluster
+// and the roles which are exempted from them
+// validateBackupArchive validates the configuration of the archive
+// tier of the base backups
+func (r *Cluster) validateBackupArchive() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.Archive == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	archive := r.Spec.Backup.Archive
+	archivePath := field.NewPath("spec", "backup", "archive")
+
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		return append(result, field.Invalid(
+			archivePath,
+			"",
+			"the archive tier requires barmanObjectStore to be configured"))
+	}
+
+	if r.Spec.Backup.GetArchiveDestination() == nil {
+		result = append(result, field.Invalid(
+			archivePath.Child("destinationName"),
+			archive.DestinationName,
+			"the archive tier must be one of the additionalWalDestinations"))
+	}
+
+	archiveAfter, err := utils.ParsePolicyDuration(archive.ArchiveAfter)
+	if err != nil {
+		result = append(result, field.Invalid(
+			archivePath.Child("archiveAfter"),
+			archive.ArchiveAfter,
+			"not a valid age"))
+	}
+
+	if archive.RetentionPolicy == "" {
+		return result
+	}
+
+	archiveRetention, err := utils.ParsePolicyDuration(archive.RetentionPolicy)
+	if err != nil {
+		return append(result, field.Invalid(
+			archivePath.Child("retentionPolicy"),
+			archive.RetentionPolicy,
+			"not a valid retention policy"))
+	}
+
+	if archiveAfter > 0 && archiveRetention <= archiveAfter {
+		result = append(result, field.Invalid(
+			archivePath.Child("retentionPolicy"),
+			archive.RetentionPolicy,
+			"the retention policy of the archive tier must be longer than archiveAfter"))
+	}
+
+	// The archive tier is meant to keep the base backups after they
+	// are removed from barmanObjectStore, not the other way around
+	if retention, err := utils.ParsePolicyDuration(r.Spec.Backup.RetentionPolicy); err == nil &&
+		archiveRetention <= retention {
+		result = append(result, field.Invalid(
+			archivePath.Child("retentionPolicy"),
+			archive.RetentionPolicy,
+			"the retention policy of the archive tier must be longer than the one of barmanObjectStore"))
+	}
+
+	return result
+}
+
+// validateWalRestoreCache validates the configuration of the shared
+// WAL restore cache
+func (r *Cluster) validateWalRestoreCache() field.ErrorList {
+	configuration := r.Spec.WalRestoreCache
+	if configuration == nil || configuration.RetentionPeriod == "" {
+		return nil
+	}
+
+	fieldPath := field.NewPath("spec", "walRestoreCache", "retentionPeriod")
+	retentionPeriod, err := time.ParseDuration(configuration.RetentionPeriod)
+	switch {
+	case err != nil:
+		return field.ErrorList{field.Invalid(fieldPath, configuration.RetentionPeriod, err.Error())}
+	case retentionPeriod < time.Minute:
+		return field.ErrorList{field.Invalid(fieldPath, configuration.RetentionPeriod, "must be at least 1m")}
+	}
+
+	return nil
+}
+
+// maxFailoverLSNTolerance is the maximum amount of WAL by which a replica
+// can be behind the most advanced one to be preferred for its failover
+// priority, to avoid promoting a replica which is significantly behind
+var maxFailoverLSNTolerance = resource.MustParse("1Gi")
+
+// validateFailoverPriority checks that the failover priority refers to
+// the instances of the cluster, and that the LSN tolerance is bounded
+func (r *Cluster) validateFailoverPriority() field.ErrorList {
+	configuration := r.Spec.FailoverPriority
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "failoverPriority")
+
+	instanceNames := stringset.New()
+	for idx, instance := range configuration.Instances {
+		fieldPath := basePath.Child("instances").Index(idx).Child("name")
+		if err := r.validateInstanceName(fieldPath, instance.Name); err != nil {
+			result = append(result, err)
+		}
+		if instanceNames.Has(instance.Name) {
+			result = append(result, field.Duplicate(fieldPath, instance.Name))
+		}
+		instanceNames.Put(instance.Name)
+	}
+
+	if tolerance := configuration.LSNTolerance; tolerance != nil {
+		fieldPath := basePath.Child("lsnTolerance")
+		switch {
+		case tolerance.Sign() < 0:
+			result = append(result, field.Invalid(fieldPath, tolerance.String(), "must not be negative"))
+		case tolerance.Cmp(maxFailoverLSNTolerance) > 0:
+			result = append(result, field.Invalid(fieldPath, tolerance.String(),
+				fmt.Sprintf("cannot be greater than %s", maxFailoverLSNTolerance.String())))
+		}
+	}
+
+	return result
+}
+
+// validateReplicaAutoscaling checks that the bounds of the automatic
+// scaling leave room for the synchronous and the delayed replicas
+func (r *Cluster) validateReplicaAutoscaling() field.ErrorList {
+	configuration := r.Spec.ReplicaAutoscaling
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "replicaAutoscaling")
+
+	if configuration.MinInstances < 1 {
+		result = append(result, field.Invalid(
+			basePath.Child("minInstances"),
+			configuration.MinInstances,
+			"must be at least 1"))
+	}
+
+	if configuration.TargetActiveConnections < 1 {
+		result = append(result, field.Invalid(
+			basePath.Child("targetActiveConnections"),
+			configuration.TargetActiveConnections,
+			"must be at least 1"))
+	}
+
+	if configuration.MaxInstances < configuration.MinInstances {
+		result = append(result, field.Invalid(
+			basePath.Child("maxInstances"),
+			configuration.MaxInstances,
+			"cannot be lower than minInstances"))
+	}
+
+	if configuration.MinInstances <= r.Spec.MaxSyncReplicas {
+		result = append(result, field.Invalid(
+			basePath.Child("minInstances"),
+			configuration.MinInstances,
+			"must be greater than maxSyncReplicas"))
+	}
+
+	if delayedReplicas := r.Spec.PostgresConfiguration.DelayedReplicas; delayedReplicas != nil &&
+		configuration.MinInstances <= len(delayedReplicas.Instances) {
+		result = append(result, field.Invalid(
+			basePath.Child("minInstances"),
+			configuration.MinInstances,
+			"must be greater than the number of delayed replicas"))
+	}
+
+	return result
+}
+
+func (r *Cluster) validateParameterChangeRestart() field.ErrorList {
+	configuration := r.Spec.ParameterChangeRestart
+	if configuration == nil {
+		return nil
+	}
+
+	basePath := field.NewPath("spec", "parameterChangeRestart")
+	window := configuration.MaintenanceWindow
+	if window == nil {
+		if configuration.Strategy == ParameterChangeRestartStrategyMaintenanceWindow {
+			return field.ErrorList{field.Required(basePath.Child("maintenanceWindow"),
+				"the maintenanceWindow strategy requires a maintenance window")}
+		}
+		return nil
+	}
+
+	var result field.ErrorList
+	if _, err := cron.Parse(window.Schedule); err != nil {
+		result = append(result, field.Invalid(
+			basePath.Child("maintenanceWindow", "schedule"), window.Schedule, err.Error()))
+	}
+
+	if window.Duration != "" {
+		fieldPath := basePath.Child("maintenanceWindow", "duration")
+		duration, err := time.ParseDuration(window.Duration)
+		switch {
+		case err != nil:
+			result = append(result, field.Invalid(fieldPath, window.Duration, err.Error()))
+		case duration < time.Minute:
+			result = append(result, field.Invalid(fieldPath, window.Duration, "must be at least 1m"))
+		}
+	}
+
+	return result
+}
+
+// validateMemory checks the memory settings expressed as a percentage
+// of the memory limit of the container
+func (r *Cluster) validateMemory() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.Memory
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "memory")
+
+	for _, setting := range []struct {
+		fieldName  string
+		parameter  string
+		percentage int32
+	}{
+		{"sharedBuffersPercentage", "shared_buffers", configuration.SharedBuffersPercentage},
+		{"effectiveCacheSizePercentage", "effective_cache_size", configuration.EffectiveCacheSizePercentage},
+	} {
+		if setting.percentage == 0 {
+			continue
+		}
+
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[setting.parameter]; ok {
+			result = append(
+				result,
+				field.Forbidden(
+					basePath.Child(setting.fieldName),
+					fmt.Sprintf("cannot be used together with the `%s` parameter", setting.parameter)))
+		}
+	}
+
+	if configuration.SharedBuffersPercentage > 0 && configuration.EffectiveCacheSizePercentage > 0 &&
+		configuration.SharedBuffersPercentage > configuration.EffectiveCacheSizePercentage {
+		result = append(
+			result,
+			field.Invalid(
+				basePath.Child("effectiveCacheSizePercentage"),
+				configuration.EffectiveCacheSizePercentage,
+				"must include the memory assigned to shared_buffers, "+
+					"and cannot be lower than sharedBuffersPercentage"))
+	}
+
+	if memoryLimit := r.Spec.Resources.Limits.Memory(); memoryLimit.IsZero() {
+		result = append(
+			result,
+			field.Required(
+				field.NewPath("spec", "resources", "limits", "memory"),
+				"is required to compute the memory settings of PostgreSQL"))
+	}
+
+	return result
+}
+
+// validateTransactionIDWraparound checks that the warning threshold on the
+// age of the oldest unfrozen transaction ID is below the critical one
+func (r *Cluster) validateTransactionIDWraparound() field.ErrorList {
+	if r.Spec.Monitoring == nil || r.Spec.Monitoring.TransactionIDWraparound == nil {
+		return nil
+	}
+
+	warningAge := r.Spec.Monitoring.GetTransactionIDWarningAge()
+	criticalAge := r.Spec.Monitoring.GetTransactionIDCriticalAge()
+	if warningAge < criticalAge {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "monitoring", "transactionIDWraparound", "warningAge"),
+			warningAge,
+			fmt.Sprintf("must be lower than the critical age, which is %d", criticalAge)),
+	}
+}
+
+// validateSlowQueryLogging validates the slow query logging settings,
+// which cannot be used together with the parameters they set
+func (r *Cluster) validateSlowQueryLogging() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.SlowQueryLogging
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "slowQueryLogging")
+
+	// PostgreSQL stores these durations as an integer number of milliseconds
+	const maxDuration = math.MaxInt32 * time.Millisecond
+
+	for _, setting := range []struct {
+		fieldName string
+		parameter string
+		value     string
+	}{
+		{"minDuration", "log_min_duration_statement", configuration.MinDuration},
+		{"autovacuumMinDuration", "log_autovacuum_min_duration", configuration.AutovacuumMinDuration},
+	} {
+		if setting.value == "" {
+			continue
+		}
+
+		fieldPath := basePath.Child(setting.fieldName)
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[setting.parameter]; ok {
+			result = append(
+				result,
+				field.Forbidden(
+					fieldPath,
+					fmt.Sprintf("cannot be used together with the `%s` parameter", setting.parameter)))
+		}
+
+		duration, err := time.ParseDuration(setting.value)
+		switch {
+		case err != nil:
+			result = append(result, field.Invalid(fieldPath, setting.value, err.Error()))
+		case duration < 0:
+			result = append(result, field.Invalid(fieldPath, setting.value, "cannot be negative"))
+		case duration > 0 && duration < time.Millisecond:
+			result = append(result, field.Invalid(fieldPath, setting.value, "must be at least 1ms"))
+		case duration > maxDuration:
+			result = append(result, field.Invalid(fieldPath, setting.value,
+				fmt.Sprintf("cannot be greater than %v", maxDuration)))
+		}
+	}
+
+	if configuration.LockWaits != nil {
+		if _, ok := r.Spec.PostgresConfiguration.Parameters["log_lock_waits"]; ok {
+			result = append(
+				result,
+				field.Forbidden(
+					basePath.Child("lockWaits"),
+					"cannot be used together with the `log_lock_waits` parameter"))
+		}
+	}
+
+	if configuration.TempFilesMinSize != nil {
+		fieldPath := basePath.Child("tempFilesMinSize")
+		if _, ok := r.Spec.PostgresConfiguration.Parameters["log_temp_files"]; ok {
+			result = append(
+				result,
+				field.Forbidden(
+					fieldPath,
+					"cannot be used together with the `log_temp_files` parameter"))
+		}
+
+		// PostgreSQL stores this size as an integer number of kilobytes
+		size := configuration.TempFilesMinSize.Value()
+		switch {
+		case configuration.TempFilesMinSize.Sign() < 0:
+			result = append(result, field.Invalid(fieldPath, configuration.TempFilesMinSize.String(),
+				"cannot be negative"))
+		case size/1024 > math.MaxInt32:
+			result = append(result, field.Invalid(fieldPath, configuration.TempFilesMinSize.String(),
+				"cannot be greater than 2TiB"))
+		}
+	}
+
+	return result
+}
+
+// validateStatStatements validates the settings of pg_stat_statements,
+// whose maximum number of statements cannot be used together with the
+// parameter it sets
+func (r *Cluster) validateStatStatements() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.StatStatements
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "statStatements")
+
+	if configuration.Max > 0 {
+		if _, ok := r.Spec.PostgresConfiguration.Parameters["pg_stat_statements.max"]; ok {
+			result = append(
+				result,
+				field.Forbidden(
+					basePath.Child("max"),
+					"cannot be used together with the `pg_stat_statements.max` parameter"))
+		}
+	}
+
+	if configuration.ResetSchedule != "" {
+		if _, err := cron.Parse(configuration.ResetSchedule); err != nil {
+			result = append(result, field.Invalid(
+				basePath.Child("resetSchedule"),
+				configuration.ResetSchedule,
+				err.Error()))
+		}
+	}
+
+	return result
+}
+
+func (r *Cluster) validateTimeouts() field.ErrorList {
+	configuration := r.Spec.PostgresConfiguration.Timeouts
+	if configuration == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "timeouts")
+
+	// PostgreSQL stores these timeouts as an integer number of milliseconds
+	const maxTimeout = math.MaxInt32 * time.Millisecond
+
+	for _, timeout := range []struct {
+		fieldName string
+		parameter string
+		value     string
+	}{
+		{"statementTimeout", "statement_timeout", configuration.StatementTimeout},
+		{"idleInTransactionSessionTimeout", "idle_in_transaction_session_timeout",
+			configuration.IdleInTransactionSessionTimeout},
+	} {
+		if timeout.value == "" {
+			continue
+		}
+
+		fieldPath := basePath.Child(timeout.fieldName)
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[timeout.parameter]; ok {
+			result = append(
+				result,
+				field.Forbidden(
+					fieldPath,
+					fmt.Sprintf("cannot be used together with the `%s` parameter", timeout.parameter)))
+		}
+
+		duration, err := time.ParseDuration(timeout.value)
+		switch {
+		case err != nil:
+			result = append(result, field.Invalid(fieldPath, timeout.value, err.Error()))
+		case duration < 0:
+			result = append(result, field.Invalid(fieldPath, timeout.value, "cannot be negative"))
+		case duration > 0 && duration < time.Millisecond:
+			result = append(result, field.Invalid(fieldPath, timeout.value, "must be at least 1ms"))
+		case duration > maxTimeout:
+			result = append(result, field.Invalid(fieldPath, timeout.value,
+				fmt.Sprintf("cannot be greater than %v", maxTimeout)))
+		}
+	}
+
+	if len(configuration.ExemptRoles) > 0 &&
+		configuration.StatementTimeout == "" && configuration.IdleInTransactionSessionTimeout == "" {
+		result = append(
+			result,
+			field.Invalid(
+				basePath.Child("exemptRoles"),
+				configuration.ExemptRoles,
+				"requires statementTimeout or idleInTransactionSessionTimeout to be set"))
+	}
+
+	seenRoles := stringset.New()
+	for idx, role := range configuration.ExemptRoles {
+		switch {
+		case role == "":
+			result = append(result, field.Invalid(basePath.Child("exemptRoles").Index(idx), role,
+				"cannot be empty"))
+		case seenRoles.Has(role):
+			result = append(result, field.Duplicate(basePath.Child("exemptRoles").Index(idx), role))
+		}
+		seenRoles.Put(role)
+	}
+
+	return result
+}
+
+func (r *Cluster) getAdmissionWarnings() admission.Warnings {
+	result := r.getMaintenanceWindowsAdmissionWarnings()
+	result = append(result, r.getUnsafeDurabilityAdmissionWarnings()...)
+	result = append(result, r.getMaxConnectionsAdmissionWarnings()...)
+	result = append(result, r.getLogicalReplicationSlotsAdmissionWarnings()...)
+	result = append(result, r.getPgHBAPrependAdmissionWarnings()...)
+	return append(result, r.getPostgresTLSAdmissionWarnings()...)
+}
+
+// getPgHBAPrependAdmissionWarnings warns when a rule placed before the fixed
+// ones matches the connections of the operator, like the streaming
+// replication ones, which may not be authenticated as expected anymore
+func (r *Cluster) getPgHBAPrependAdmissionWarnings() admission.Warnings {
+	var result admission.Warnings
+	for idx, rule := range r.Spec.PostgresConfiguration.PgHBAPrepend {
+		if postgres.HBARuleShadowsFixedRules(rule) {
+			result = append(result, fmt.Sprintf(
+				"The rule %q in `.spec.postgresql.pg_hba_prepend[%d]` matches the connections of the "+
+					"operator, the instance manager or the streaming replicas, which are authenticated "+
+					"by the fixed rules: make sure they are not rejected", rule, idx))
+		}
+	}
+
+	return result
+}
+
+// getPostgresTLSAdmissionWarnings warns when the configured TLS ciphers
+// can't be used, as only TLS 1.3 connections are accepted
+func (r *Cluster) getPostgresTLSAdmissionWarnings() admission.Warnings {
+	configuration := r.Spec.PostgresConfiguration.TLS
+	if configuration == nil || len(configuration.Ciphers) == 0 {
+		return nil
+	}
+
+	minProtocolVersion := configuration.MinProtocolVersion
+	if minProtocolVersion == "" {
+		minProtocolVersion = r.Spec.PostgresConfiguration.Parameters["ssl_min_protocol_version"]
+	}
+	if minProtocolVersion == "" || minProtocolVersion == "TLSv1.3" {
+		return admission.Warnings{
+			"The TLS ciphers in `.spec.postgresql.tls.ciphers` are not used by TLS 1.3, " +
+				"which is the only protocol version accepted by PostgreSQL",
+		}
+	}
+
+	return nil
+}
+
+// getLogicalReplicationSlotsAdmissionWarnings warns when the managed logical
+// replication slots can't be synchronized on the standbys, as the subscribers
+// won't be able to resume from where they left after a failover
+func (r *Cluster) getLogicalReplicationSlotsAdmissionWarnings() admission.Warnings {
+	if len(r.Spec.ReplicationSlots.GetLogicalSlots()) == 0 {
+		return nil
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
+
+	if psqlVersion < 170000 {
+		return admission.Warnings{
+			"Logical replication slots can only be synchronized on the standbys from PostgreSQL 17: " +
+				"after a failover they will be recreated on the new primary, and the subscribers " +
+				"may miss the changes in between",
+		}
+	}
+
+	if !r.Spec.ReplicationSlots.HighAvailability.GetEnabled() {
+		return admission.Warnings{
+			"Logical replication slots are synchronized on the standbys only when " +
+				"`.spec.replicationSlots.highAvailability` is enabled",
+		}
+	}
+
+	return nil
+}
+
+// getMaxConnectionsAdmissionWarnings warns when the memory limit (or the
+// memory request, when no limit is set) can't accommodate the configured
+// max_connections. Every connection may use up to work_mem on top of its
+// own overhead, and all of them share the shared_buffers area.
+func (r *Cluster) getMaxConnectionsAdmissionWarnings() admission.Warnings {
+	rawMaxConnections, ok := r.Spec.PostgresConfiguration.Parameters[maxConnectionsParameter]
+	if !ok {
+		return nil
+	}
+	maxConnections, err := strconv.Atoi(strings.TrimSpace(rawMaxConnections))
+	if err != nil || maxConnections < 1 {
+		// The validation error is already raised by validateMaxConnections
+		return nil
+	}
+
+	memory := r.Spec.Resources.Limits.Memory()
+	if memory.IsZero() {
+		memory = r.Spec.Resources.Requests.Memory()
+	}
+	if memory.IsZero() {
+		return nil
+	}
+
+	// Without a unit, shared_buffers is expressed in 8kB pages
+	// and work_mem in kB
+	rawSharedBuffers := r.getParameterOrDefault(sharedBuffersParameter, defaultSharedBuffers)
+	if pages, err := strconv.Atoi(rawSharedBuffers); err == nil {
+		rawSharedBuffers = fmt.Sprintf("%dkB", pages*8)
+	}
+	rawWorkMem := r.getParameterOrDefault(workMemParameter, defaultWorkMem)
+	if _, err := strconv.Atoi(rawWorkMem); err == nil {
+		rawWorkMem += "kB"
+	}
+
+	sharedBuffers, err := parsePostgresQuantityValue(rawSharedBuffers)
+	if err != nil {
+		return nil
+	}
+	workMem, err := parsePostgresQuantityValue(rawWorkMem)
+	if err != nil {
+		return nil
+	}
+
+	perConnection := resource.MustParse(connectionMemoryOverhead)
+	perConnection.Add(workMem)
+
+	estimated := sharedBuffers.DeepCopy()
+	estimated.Add(*resource.NewQuantity(perConnection.Value()*int64(maxConnections), resource.BinarySI))
+	if estimated.Cmp(*memory) <= 0 {
+		return nil
+	}
+
+	return admission.Warnings{
+		fmt.Sprintf("`max_connections` is set to %d: the estimated memory needed by PostgreSQL "+
+			"(%s of shared_buffers plus %s per connection) is %s, more than the %s available "+
+			"to the instances. Lower max_connections or raise the memory resources.",
+			maxConnections, sharedBuffers.String(), perConnection.String(),
+			estimated.String(), memory.String()),
+	}
+}
+
+// getParameterOrDefault returns the value of the passed PostgreSQL
+// parameter, or the passed default value when it's not set
+func (r *Cluster) getParameterOrDefault(name, defaultValue string) string {
+	if value, ok := r.Spec.PostgresConfiguration.Parameters[name]; ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (r *Cluster) getUnsafeDurabilityAdmissionWarnings() admission.Warnings {
+	if !utils.IsUnsafeDurabilityAllowed(&r.ObjectMeta) {
+		return nil
+	}
+
+	var result admission.Warnings
+	for _, key := range postgres.GetDisabledUnsafeDurabilityParameters(r.Spec.PostgresConfiguration.Parameters) {
+		result = append(
+			result,
+			fmt.Sprintf("`%s` is disabled: a crash may lead to unrecoverable data corruption, "+
+				"never use this setting in production", key))
+	}
+	return result
+}
+
+func (r *Cluster) getMaintenanceWindowsAdmissionWarnings() admission.Warnings {
+	var result admission.Warnings
+
+	if r.Spec.NodeMaintenanceWindow != nil {
+		result = append(
+			result,
+			"Consider using `.spec.enablePDB` instead of the node maintenance window feature")
+	}
+	return result
+}
+
+// validate whether the fencing mode is known
+func (r *Cluster) validateFencingModeAnnotation() field.ErrorList {
+	value, ok := r.Annotations[utils.FencingModeAnnotationName]
+	isKnownValue := value == string(utils.FencingModeStop) ||
+		value == string(utils.FencingModeReadOnly)
+	if !ok || isKnownValue {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("metadata", "annotations", utils.FencingModeAnnotationName),
+			value,
+			fmt.Sprintf("Annotation value for the fencing mode should be %q or %q",
+				utils.FencingModeStop,
+				utils.FencingModeReadOnly,
+			),
+		),
+	}
+}
+
+// validateReadOnlyFencingChange rejects the read-only fencing of the current
+// primary instance, whose clients could still write by overriding the
+// default_transaction_read_only setting
+func (r *Cluster) validateReadOnlyFencingChange(old *Cluster) field.ErrorList {
+	primary := r.Status.CurrentPrimary
+	if primary == "" ||
+		r.GetInstanceFencingMode(primary) != utils.FencingModeReadOnly ||
+		old.GetInstanceFencingMode(primary) == utils.FencingModeReadOnly {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Forbidden(
+			field.NewPath("metadata", "annotations", utils.FencedInstanceAnnotation),
+			fmt.Sprintf("the primary instance %s can't be fenced in read-only mode, as its clients "+
+				"can still write: switch over to another instance first, or use the %q fencing mode",
+				primary, utils.FencingModeStop),
+		),
 	}
-	return result
 }
 
 // validate whether the hibernation configuration is valid
@@ -21,11 +21,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
@@ -294,7 +297,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							SecretRefs: []SecretKeySelector{
 								{
 									LocalObjectReference: LocalObjectReference{Name: "secret1"},
@@ -317,7 +320,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							SecretRefs: []SecretKeySelector{
 								{
 									Key: "key",
@@ -340,7 +343,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									LocalObjectReference: LocalObjectReference{Name: "configmap1"},
@@ -363,7 +366,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									Key: "key",
@@ -386,7 +389,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									LocalObjectReference: LocalObjectReference{Name: "configmap1"},
@@ -417,6 +420,81 @@ var _ = Describe("initdb options validation", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("complain if the references for the postgres and template1 databases are not valid", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						PostInitSQLRefs: &SQLRefs{
+							SecretRefs: []SecretKeySelector{
+								{
+									Key: "key",
+								},
+							},
+						},
+						PostInitTemplateSQLRefs: &SQLRefs{
+							ConfigMapRefs: []ConfigMapKeySelector{
+								{
+									LocalObjectReference: LocalObjectReference{Name: "configmap1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.postInitSQLRefs.secretRefs"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.initdb.postInitTemplateSQLRefs.configMapRefs"))
+	})
+
+	It("complains if the WAL segment size is not supported by PostgreSQL", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{WalSegmentSize: 24},
+				},
+			},
+		}
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.walSegmentSize"))
+
+		cluster.Spec.Bootstrap.InitDB.WalSegmentSize = 2048
+		result = cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring("between 1 and 1024"))
+	})
+
+	It("complains if the WAL size parameters are lower than two WAL segments", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{WalSegmentSize: 64},
+				},
+			},
+		}
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.min_wal_size"))
+
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{"min_wal_size": "128MB"}
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+
+		cluster.Spec.Bootstrap.InitDB.WalSegmentSize = 1024
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"min_wal_size": "2GB",
+			"max_wal_size": "1GB",
+		}
+		result = cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.max_wal_size"))
+	})
+
 	It("doesn't complain if superuser secret it's empty", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{},
@@ -464,6 +542,30 @@ var _ = Describe("cluster configuration", func() {
 		Expect(cluster.Spec.Bootstrap.InitDB.Owner).To(Equal("appdb"))
 	})
 
+	It("defaults the collation and the character classification to C", func() {
+		cluster := Cluster{}
+		cluster.Default()
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCollate).To(Equal("C"))
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCType).To(Equal("C"))
+	})
+
+	It("lets the collation and the character classification inherit the locale", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Locale:        "en_US.UTF-8",
+						LocaleCollate: "de_DE.UTF-8",
+					},
+				},
+			},
+		}
+
+		cluster.Default()
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCollate).To(Equal("de_DE.UTF-8"))
+		Expect(cluster.Spec.Bootstrap.InitDB.LocaleCType).To(BeEmpty())
+	})
+
 	It("defaults to create an application database if recovery is used", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
@@ -1057,6 +1159,39 @@ var _ = Describe("configuration change validation", func() {
 		Expect(clusterNew.validateConfiguration()).To(HaveLen(1))
 	})
 
+	It("compares the WAL sizes set by the checkpoint profile with WalStorage", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Checkpoint: &CheckpointConfiguration{Profile: CheckpointProfileBulkLoad},
+				},
+				WalStorage: &StorageConfiguration{
+					Size: "8Gi",
+				},
+			},
+		}
+		result := clusterNew.validateConfiguration()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.checkpoint.profile"))
+
+		clusterNew.Spec.PostgresConfiguration.Parameters = map[string]string{"max_wal_size": "6GB"}
+		Expect(clusterNew.validateConfiguration()).To(BeEmpty())
+	})
+
+	It("compares the WAL sizes set by the user with the ones of the checkpoint profile", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"min_wal_size": "8GB"},
+					Checkpoint: &CheckpointConfiguration{Profile: CheckpointProfileWriteIntensive},
+				},
+			},
+		}
+		result := clusterNew.validateConfiguration()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.min_wal_size"))
+	})
+
 	It("should detect an invalid `shared_buffers` value", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
@@ -1435,7 +1570,7 @@ var _ = Describe("validate image name change", func() {
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 
-		It("complains if it can't upgrade between mayor versions", func() {
+		It("complains on major downgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageName: "postgres:12.0",
@@ -1449,6 +1584,47 @@ var _ = Describe("validate image name change", func() {
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
 		})
 
+		It("allows going back to the major version of the data directory", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:17.0",
+				},
+				Status: ClusterStatus{
+					PGDataImageInfo: &ImageInfo{
+						Image:        "postgres:16.4",
+						MajorVersion: 16,
+					},
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:16.4",
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
+
+			clusterNew.Spec.ImageName = "postgres:15.8"
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
+
+		It("complains when upgrading the major version of a replica cluster", func() {
+			clusterOld := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:16.4",
+				},
+			}
+			clusterNew := Cluster{
+				Spec: ClusterSpec{
+					ImageName: "postgres:17.0",
+					ReplicaCluster: &ReplicaClusterConfiguration{
+						Enabled: ptr.To(true),
+						Source:  "source",
+					},
+				},
+			}
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+		})
+
 		It("doesn't complain if image change is valid", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
@@ -1464,7 +1640,7 @@ var _ = Describe("validate image name change", func() {
 		})
 	})
 	Context("using image catalog", func() {
-		It("complains on major upgrades", func() {
+		It("allows major upgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageCatalogRef: &ImageCatalogRef{
@@ -1487,7 +1663,7 @@ var _ = Describe("validate image name change", func() {
 					},
 				},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 	})
 	Context("changing from imageName to imageCatalogRef", func() {
@@ -1510,7 +1686,7 @@ var _ = Describe("validate image name change", func() {
 			}
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
-		It("complains on major upgrades", func() {
+		It("allows major upgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageName: "postgres:15.1",
@@ -1527,7 +1703,7 @@ var _ = Describe("validate image name change", func() {
 					},
 				},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 		It("complains going from default imageName to different major imageCatalogRef", func() {
 			clusterOld := Cluster{
@@ -1585,7 +1761,7 @@ var _ = Describe("validate image name change", func() {
 			}
 			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
-		It("complains on major upgrades", func() {
+		It("allows major upgrades", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageCatalogRef: &ImageCatalogRef{
@@ -1602,9 +1778,9 @@ var _ = Describe("validate image name change", func() {
 					ImageName: "postgres:16.1",
 				},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
-		It("complains going from default imageName to different major imageCatalogRef", func() {
+		It("doesn't complain going from an older major imageCatalogRef to default imageName", func() {
 			clusterOld := Cluster{
 				Spec: ClusterSpec{
 					ImageCatalogRef: &ImageCatalogRef{
@@ -1619,7 +1795,7 @@ var _ = Describe("validate image name change", func() {
 			clusterNew := Cluster{
 				Spec: ClusterSpec{},
 			}
-			Expect(clusterNew.validateImageChange(&clusterOld)).To(HaveLen(1))
+			Expect(clusterNew.validateImageChange(&clusterOld)).To(BeEmpty())
 		})
 		It("doesn't complain going from default imageName to same major imageCatalogRef", func() {
 			clusterOld := Cluster{
@@ -1852,6 +2028,21 @@ var _ = Describe("recovery target", func() {
 			Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
 		})
 
+		It("allows 'current'", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Bootstrap: &BootstrapConfiguration{
+						Recovery: &BootstrapRecovery{
+							RecoveryTarget: &RecoveryTarget{
+								TargetTLI: "current",
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+		})
+
 		It("allows a positive integer", func() {
 			cluster := Cluster{
 				Spec: ClusterSpec{
@@ -2373,6 +2564,81 @@ var _ = Describe("bootstrap recovery validation", func() {
 		errorsList := recoveryCluster.validateBootstrapRecoverySource()
 		Expect(errorsList).ToNot(BeEmpty())
 	})
+
+	Context("recovery origin", func() {
+		backupSource := &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}}
+
+		buildCluster := func(recovery *BootstrapRecovery, externalClusters ...ExternalCluster) *Cluster {
+			return &Cluster{
+				Spec: ClusterSpec{
+					Bootstrap:        &BootstrapConfiguration{Recovery: recovery},
+					ExternalClusters: externalClusters,
+				},
+			}
+		}
+
+		It("doesn't complain when not recovering", func() {
+			cluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{}}}
+			Expect(cluster.validateBootstrapRecoveryOrigin()).To(BeEmpty())
+		})
+
+		It("complains when there's nothing to recover from", func() {
+			result := buildCluster(&BootstrapRecovery{}).validateBootstrapRecoveryOrigin()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.bootstrap.recovery"))
+		})
+
+		It("complains when both a backup and a source are specified", func() {
+			result := buildCluster(
+				&BootstrapRecovery{Backup: backupSource, Source: "origin"},
+				ExternalCluster{Name: "origin", BarmanObjectStore: &BarmanObjectStoreConfiguration{}},
+			).validateBootstrapRecoveryOrigin()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.source"))
+		})
+
+		It("complains when the name of the backup is missing", func() {
+			result := buildCluster(&BootstrapRecovery{Backup: &BackupSource{}}).validateBootstrapRecoveryOrigin()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.bootstrap.recovery.backup.name"))
+		})
+
+		It("doesn't complain when recovering from a backup object", func() {
+			result := buildCluster(&BootstrapRecovery{Backup: backupSource}).validateBootstrapRecoveryOrigin()
+			Expect(result).To(BeEmpty())
+		})
+
+		It("complains when the source has no object store", func() {
+			result := buildCluster(
+				&BootstrapRecovery{Source: "origin"},
+				ExternalCluster{Name: "origin"},
+			).validateBootstrapRecoveryOrigin()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Detail).To(ContainSubstring("find the backup"))
+		})
+
+		It("complains when the source of the WAL files of a volume snapshot recovery has no object store", func() {
+			result := buildCluster(
+				&BootstrapRecovery{Source: "origin", VolumeSnapshots: &DataSource{}},
+				ExternalCluster{Name: "origin"},
+			).validateBootstrapRecoveryOrigin()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Detail).To(ContainSubstring("fetch the WAL files"))
+		})
+
+		It("doesn't complain when recovering from an object store", func() {
+			result := buildCluster(
+				&BootstrapRecovery{Source: "origin"},
+				ExternalCluster{Name: "origin", BarmanObjectStore: &BarmanObjectStoreConfiguration{}},
+			).validateBootstrapRecoveryOrigin()
+			Expect(result).To(BeEmpty())
+		})
+
+		It("doesn't complain when recovering only from volume snapshots", func() {
+			result := buildCluster(&BootstrapRecovery{VolumeSnapshots: &DataSource{}}).validateBootstrapRecoveryOrigin()
+			Expect(result).To(BeEmpty())
+		})
+	})
 })
 
 var _ = Describe("toleration validation", func() {
@@ -3069,6 +3335,68 @@ var _ = Describe("validate the replica cluster external clusters", func() {
 	})
 })
 
+var _ = Describe("validate the archive of a replica cluster", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-eu-central"},
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: ptr.To(true),
+					Source:  "cluster-eu-south",
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "cluster-eu-south",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://backups/",
+						},
+					},
+				},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://backups",
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a replica cluster archiving in its own location", func() {
+		Expect(cluster.validateReplicaClusterArchive()).To(BeEmpty())
+	})
+
+	It("complains when the replica cluster archives in the location of its source", func() {
+		cluster.Spec.Backup.BarmanObjectStore.ServerName = "cluster-eu-south"
+		result := cluster.validateReplicaClusterArchive()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.barmanObjectStore.destinationPath"))
+	})
+
+	It("complains when an additional WAL destination is the location of the source", func() {
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore.ServerName = "origin"
+		cluster.Spec.Backup.AdditionalWalDestinations = []WalArchiveDestination{
+			{
+				Name: "secondary",
+				BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://backups",
+					ServerName:      "origin",
+				},
+			},
+		}
+		result := cluster.validateReplicaClusterArchive()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.additionalWalDestinations[0].destinationPath"))
+	})
+
+	It("ignores the sources without an object store", func() {
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		cluster.Spec.Backup.BarmanObjectStore.ServerName = "cluster-eu-south"
+		Expect(cluster.validateReplicaClusterArchive()).To(BeEmpty())
+	})
+})
+
 var _ = Describe("Validation changes", func() {
 	It("doesn't complain if given old cluster is nil", func() {
 		newCluster := &Cluster{}
@@ -3124,38 +3452,227 @@ var _ = Describe("Backup validation", func() {
 		err := cluster.validateBackupConfiguration()
 		Expect(err).To(HaveLen(2))
 	})
+
+	It("complains if the backup bandwidth limit is not positive", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+						Data: &DataBackupConfiguration{
+							MaxBandwidth: ptr.To(resource.MustParse("0")),
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+
+		cluster.Spec.Backup.BarmanObjectStore.Data.MaxBandwidth = ptr.To(resource.MustParse("50Mi"))
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+	})
 })
 
-var _ = Describe("Default monitoring queries", func() {
-	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
-		cluster := &Cluster{}
-		cluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret:    "test-secret",
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  DefaultMonitoringKey,
-			}))
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  DefaultMonitoringKey,
-			}))
+var _ = Describe("Backup verification validation", func() {
+	It("doesn't complain if the verification is not configured", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{},
+			},
+		}
+		Expect(cluster.validateBackupVerification()).To(BeEmpty())
 	})
-	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
-		CustomQueriesConfigMap: []ConfigMapKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
+
+	It("accepts a valid schedule", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					Verification: &BackupVerificationConfiguration{
+						Enabled:  true,
+						Schedule: "0 0 4 * * *",
+					},
+				},
 			},
-		},
-		CustomQueriesSecret: []SecretKeySelector{
-			{
+		}
+		Expect(cluster.validateBackupVerification()).To(BeEmpty())
+	})
+
+	It("complains if the schedule is not valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					Verification: &BackupVerificationConfiguration{
+						Enabled:  true,
+						Schedule: "every sunday",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupVerification()).To(HaveLen(1))
+	})
+
+	It("complains if there's no object store to verify", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					Verification: &BackupVerificationConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupVerification()).To(HaveLen(1))
+	})
+
+	It("uses the default schedule when none is specified", func() {
+		backup := &BackupConfiguration{
+			Verification: &BackupVerificationConfiguration{
+				Enabled: true,
+			},
+		}
+		Expect(backup.IsBackupVerificationEnabled()).To(BeTrue())
+		Expect(backup.GetBackupVerificationSchedule()).To(Equal(DefaultBackupVerificationSchedule))
+
+		var nilBackup *BackupConfiguration
+		Expect(nilBackup.IsBackupVerificationEnabled()).To(BeFalse())
+		Expect(nilBackup.GetBackupVerificationSchedule()).To(Equal(DefaultBackupVerificationSchedule))
+	})
+})
+
+var _ = Describe("WAL archive check validation", func() {
+	It("doesn't complain if the check is not configured", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{},
+			},
+		}
+		Expect(cluster.validateWalArchiveCheck()).To(BeEmpty())
+	})
+
+	It("accepts the check when there's an object store", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					WalArchiveCheck: &WalArchiveCheckConfiguration{
+						Enabled:  true,
+						Interval: 600,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateWalArchiveCheck()).To(BeEmpty())
+	})
+
+	It("complains if there's no object store to check", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					WalArchiveCheck: &WalArchiveCheckConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateWalArchiveCheck()).To(HaveLen(1))
+	})
+
+	It("uses the default interval when none is specified", func() {
+		backup := &BackupConfiguration{
+			WalArchiveCheck: &WalArchiveCheckConfiguration{
+				Enabled: true,
+			},
+		}
+		Expect(backup.GetWalArchiveCheckInterval()).To(Equal(DefaultWalArchiveCheckInterval * time.Second))
+
+		backup.WalArchiveCheck.Interval = 600
+		Expect(backup.GetWalArchiveCheckInterval()).To(Equal(10 * time.Minute))
+
+		var nilBackup *BackupConfiguration
+		Expect(nilBackup.IsWalArchiveCheckEnabled()).To(BeFalse())
+		Expect(nilBackup.GetWalArchiveCheckInterval()).To(Equal(DefaultWalArchiveCheckInterval * time.Second))
+	})
+})
+
+var _ = Describe("PodDisruptionBudget validation", func() {
+	buildCluster := func(primary, replicas *PodDisruptionBudgetPolicy) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PodDisruptionBudget: &PodDisruptionBudgetConfiguration{
+					Primary:  primary,
+					Replicas: replicas,
+				},
+			},
+		}
+	}
+
+	It("doesn't complain if the policies are not configured", func() {
+		Expect((&Cluster{}).validatePodDisruptionBudget()).To(BeEmpty())
+		Expect(buildCluster(nil, nil).validatePodDisruptionBudget()).To(BeEmpty())
+	})
+
+	It("accepts numbers and percentages", func() {
+		cluster := buildCluster(
+			&PodDisruptionBudgetPolicy{MinAvailable: ptr.To(intstr.FromInt32(0))},
+			&PodDisruptionBudgetPolicy{MaxUnavailable: ptr.To(intstr.FromString("50%"))},
+		)
+		Expect(cluster.validatePodDisruptionBudget()).To(BeEmpty())
+	})
+
+	It("complains if both minAvailable and maxUnavailable are set", func() {
+		cluster := buildCluster(nil, &PodDisruptionBudgetPolicy{
+			MinAvailable:   ptr.To(intstr.FromInt32(1)),
+			MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+		})
+		Expect(cluster.validatePodDisruptionBudget()).To(HaveLen(1))
+	})
+
+	It("complains about invalid values", func() {
+		cluster := buildCluster(
+			&PodDisruptionBudgetPolicy{MinAvailable: ptr.To(intstr.FromInt32(-1))},
+			&PodDisruptionBudgetPolicy{MaxUnavailable: ptr.To(intstr.FromString("half"))},
+		)
+		Expect(cluster.validatePodDisruptionBudget()).To(HaveLen(2))
+
+		cluster = buildCluster(nil, &PodDisruptionBudgetPolicy{MaxUnavailable: ptr.To(intstr.FromString("150%"))})
+		Expect(cluster.validatePodDisruptionBudget()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Default monitoring queries", func() {
+	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
+		cluster := &Cluster{}
+		cluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret:    "test-secret",
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  DefaultMonitoringKey,
+			}))
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  DefaultMonitoringKey,
+			}))
+	})
+	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+		CustomQueriesConfigMap: []ConfigMapKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			},
+		},
+		CustomQueriesSecret: []SecretKeySelector{
+			{
 				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
 				Key:                  "test3",
 			},
@@ -3426,6 +3943,71 @@ var _ = Describe("validation of imports", func() {
 	})
 })
 
+var _ = Describe("validation of imports from a base backup", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"app"},
+							Source: ImportSource{
+								ExternalCluster: "origin",
+								FromBackup:      &ImportFromBackup{},
+							},
+						},
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://backups/",
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(cluster.validateImportFromBackup()).To(BeEmpty())
+	})
+
+	It("is not available for the monolith import type", func() {
+		cluster.Spec.Bootstrap.InitDB.Import.Type = MonolithSnapshotType
+		Expect(cluster.validateImportFromBackup()).To(HaveLen(1))
+	})
+
+	It("requires an external cluster with an object store", func() {
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		Expect(cluster.validateImportFromBackup()).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.InitDB.Import.Source.ExternalCluster = "missing"
+		Expect(cluster.validateImportFromBackup()).To(HaveLen(1))
+	})
+
+	It("rejects an invalid target time", func() {
+		cluster.Spec.Bootstrap.InitDB.Import.Source.FromBackup.RecoveryTarget = &RecoveryTarget{
+			TargetTime: "yesterday",
+		}
+		Expect(cluster.validateImportFromBackup()).To(HaveLen(1))
+	})
+
+	It("rejects concurrent restore jobs", func() {
+		cluster.Spec.Bootstrap.InitDB.Import.RestoreJobs = 1
+		Expect(cluster.validateImportFromBackup()).To(BeEmpty())
+
+		cluster.Spec.Bootstrap.InitDB.Import.RestoreJobs = 4
+		Expect(cluster.validateImportFromBackup()).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("validation of replication slots configuration", func() {
 	It("prevents using replication slots on PostgreSQL 10 and older", func() {
 		cluster := &Cluster{
@@ -3937,6 +4519,94 @@ var _ = Describe("Managed Extensions validation", func() {
 		Expect(cluster.validatePgFailoverSlots()).To(HaveLen(2))
 	})
 
+	It("should produce two errors if pg_failover_slots is declared and its prerequisites are disabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ManagedExtensionConfiguration{{Name: "pg_failover_slots"}},
+				},
+			},
+		}
+		Expect(cluster.validatePgFailoverSlots()).To(HaveLen(2))
+	})
+
+	It("should accept the declared extensions managed by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ManagedExtensionConfiguration{
+						{Name: "pg_stat_statements"},
+						{Name: "pgaudit"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateDeclaredExtensions()).To(BeEmpty())
+	})
+
+	It("should reject the declared extensions not managed by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ManagedExtensionConfiguration{
+						{Name: "pg_stat_statements"},
+						{Name: "postgis"},
+					},
+				},
+			},
+		}
+		errs := cluster.validateDeclaredExtensions()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.extensions[1].name"))
+	})
+
+	It("should accept the extensions declared with a recipe", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ManagedExtensionConfiguration{
+						{
+							Name: "pg_cron",
+							Recipe: &ManagedExtensionRecipe{
+								SharedPreloadLibraries: []string{"pg_cron"},
+								Parameters:             map[string]string{"cron.database_name": "app"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateDeclaredExtensions()).To(BeEmpty())
+	})
+
+	It("should reject the invalid recipes", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Extensions: []ManagedExtensionConfiguration{
+						{
+							Name:   "pgaudit",
+							Recipe: &ManagedExtensionRecipe{},
+						},
+						{
+							Name: "pg-cron",
+							Recipe: &ManagedExtensionRecipe{
+								SharedPreloadLibraries: []string{"pg_cron,auto_explain"},
+								Parameters:             map[string]string{"fsync": "off"},
+							},
+						},
+					},
+				},
+			},
+		}
+		errs := cluster.validateDeclaredExtensions()
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.extensions[0].recipe"))
+		Expect(errs[1].Field).To(Equal("spec.postgresql.extensions[1].name"))
+		Expect(errs[2].Field).To(Equal("spec.postgresql.extensions[1].recipe.sharedPreloadLibraries[0]"))
+		Expect(errs[3].Field).To(Equal("spec.postgresql.extensions[1].recipe.parameters[fsync]"))
+	})
+
 	It("should produce an error if pg_failover_slots is enabled and HA slots are disabled", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
@@ -3970,54 +4640,506 @@ var _ = Describe("Managed Extensions validation", func() {
 	})
 })
 
-var _ = Describe("Recovery from volume snapshot validation", func() {
-	clusterFromRecovery := func(recovery *BootstrapRecovery) *Cluster {
+var _ = Describe("logDestination validation", func() {
+	buildCluster := func(imageName string, logDestination LogDestination) *Cluster {
 		return &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: recovery,
+				ImageName: imageName,
+				PostgresConfiguration: PostgresConfiguration{
+					LogDestination: logDestination,
 				},
-				WalStorage: &StorageConfiguration{},
 			},
 		}
 	}
 
-	It("should produce an error when defining two recovery sources at the same time", func() {
-		cluster := Cluster{
+	It("accepts the default log format", func() {
+		Expect(buildCluster("postgres:13", "").validateLogDestination()).To(BeEmpty())
+		Expect(buildCluster("postgres:13", LogDestinationCSV).validateLogDestination()).To(BeEmpty())
+	})
+
+	It("accepts jsonlog since PostgreSQL 15", func() {
+		Expect(buildCluster("postgres:15", LogDestinationJSON).validateLogDestination()).To(BeEmpty())
+	})
+
+	It("rejects jsonlog before PostgreSQL 15", func() {
+		Expect(buildCluster("postgres:14", LogDestinationJSON).validateLogDestination()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("adaptiveArchiveTimeout validation", func() {
+	buildCluster := func(configuration *AdaptiveArchiveTimeoutConfiguration) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source:          "sourceName",
-						Backup:          &BackupSource{},
-						VolumeSnapshots: &DataSource{},
-					},
+				ImageName: "postgres:16",
+				PostgresConfiguration: PostgresConfiguration{
+					AdaptiveArchiveTimeout: configuration,
 				},
 			},
 		}
-		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(1))
+	}
+
+	It("accepts a cluster without the automatic tuning", func() {
+		Expect(buildCluster(nil).validateAdaptiveArchiveTimeout()).To(BeEmpty())
+		Expect(buildCluster(&AdaptiveArchiveTimeoutConfiguration{
+			MinTimeout: 600,
+			MaxTimeout: 60,
+		}).validateAdaptiveArchiveTimeout()).To(BeEmpty())
 	})
 
-	It("should produce an error when defining a backupID while recovering using a DataSource", func() {
-		cluster := Cluster{
+	It("accepts the default range", func() {
+		Expect(buildCluster(&AdaptiveArchiveTimeoutConfiguration{
+			Enabled: true,
+		}).validateAdaptiveArchiveTimeout()).To(BeEmpty())
+	})
+
+	It("rejects a range whose minimum is greater than the maximum", func() {
+		Expect(buildCluster(&AdaptiveArchiveTimeoutConfiguration{
+			Enabled:    true,
+			MinTimeout: 3600,
+		}).validateAdaptiveArchiveTimeout()).To(HaveLen(1))
+	})
+
+	It("rejects the automatic tuning before PostgreSQL 14", func() {
+		cluster := buildCluster(&AdaptiveArchiveTimeoutConfiguration{Enabled: true})
+		cluster.Spec.ImageName = "postgres:13"
+		Expect(cluster.validateAdaptiveArchiveTimeout()).To(HaveLen(1))
+	})
+
+	It("rejects the automatic tuning together with the archive_timeout parameter", func() {
+		cluster := buildCluster(&AdaptiveArchiveTimeoutConfiguration{Enabled: true})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{"archive_timeout": "5min"}
+		Expect(cluster.validateAdaptiveArchiveTimeout()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("slow query logging validation", func() {
+	buildCluster := func(configuration *SlowQueryLoggingConfiguration) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						RecoveryTarget: &RecoveryTarget{
-							BackupID: "20220616T031500",
-						},
-						VolumeSnapshots: &DataSource{
-							Storage: corev1.TypedLocalObjectReference{
-								APIGroup: ptr.To(""),
-								Kind:     "PersistentVolumeClaim",
-								Name:     "pgdata",
-							},
-						},
-					},
+				PostgresConfiguration: PostgresConfiguration{
+					SlowQueryLogging: configuration,
 				},
 			},
 		}
-		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(1))
-	})
+	}
+
+	It("accepts a cluster without slow query logging settings", func() {
+		Expect(buildCluster(nil).validateSlowQueryLogging()).To(BeEmpty())
+	})
+
+	It("accepts valid settings", func() {
+		tempFilesMinSize := resource.MustParse("0")
+		Expect(buildCluster(&SlowQueryLoggingConfiguration{
+			MinDuration:           "500ms",
+			AutovacuumMinDuration: "1m",
+			LockWaits:             ptr.To(true),
+			TempFilesMinSize:      &tempFilesMinSize,
+		}).validateSlowQueryLogging()).To(BeEmpty())
+	})
+
+	It("rejects invalid durations", func() {
+		result := buildCluster(&SlowQueryLoggingConfiguration{
+			MinDuration:           "half a second",
+			AutovacuumMinDuration: "-1s",
+		}).validateSlowQueryLogging()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.slowQueryLogging.minDuration"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.slowQueryLogging.autovacuumMinDuration"))
+	})
+
+	It("rejects invalid sizes", func() {
+		negativeSize := resource.MustParse("-1Mi")
+		Expect(buildCluster(&SlowQueryLoggingConfiguration{
+			TempFilesMinSize: &negativeSize,
+		}).validateSlowQueryLogging()).To(HaveLen(1))
+
+		hugeSize := resource.MustParse("4Ti")
+		Expect(buildCluster(&SlowQueryLoggingConfiguration{
+			TempFilesMinSize: &hugeSize,
+		}).validateSlowQueryLogging()).To(HaveLen(1))
+	})
+
+	It("rejects the settings together with the corresponding parameters", func() {
+		tempFilesMinSize := resource.MustParse("1Mi")
+		cluster := buildCluster(&SlowQueryLoggingConfiguration{
+			MinDuration:      "1s",
+			LockWaits:        ptr.To(true),
+			TempFilesMinSize: &tempFilesMinSize,
+		})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"log_min_duration_statement":  "100",
+			"log_autovacuum_min_duration": "100",
+			"log_lock_waits":              "on",
+			"log_temp_files":              "0",
+		}
+		Expect(cluster.validateSlowQueryLogging()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("pg_stat_statements settings validation", func() {
+	buildCluster := func(configuration *StatStatementsConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					StatStatements: configuration,
+				},
+			},
+		}
+	}
+
+	It("accepts valid settings", func() {
+		Expect(buildCluster(nil).validateStatStatements()).To(BeEmpty())
+		Expect(buildCluster(&StatStatementsConfiguration{}).validateStatStatements()).To(BeEmpty())
+		Expect(buildCluster(&StatStatementsConfiguration{
+			Max:           5000,
+			ResetSchedule: "0 0 * * * *",
+		}).validateStatStatements()).To(BeEmpty())
+	})
+
+	It("rejects an invalid reset schedule", func() {
+		result := buildCluster(&StatStatementsConfiguration{
+			ResetSchedule: "every hour",
+		}).validateStatStatements()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.statStatements.resetSchedule"))
+	})
+
+	It("rejects the maximum together with the corresponding parameter", func() {
+		cluster := buildCluster(&StatStatementsConfiguration{Max: 5000})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"pg_stat_statements.max": "1000",
+		}
+		Expect(cluster.validateStatStatements()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.StatStatements.Max = 0
+		Expect(cluster.validateStatStatements()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("timeouts validation", func() {
+	buildCluster := func(configuration *TimeoutsConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Timeouts: configuration,
+				},
+			},
+		}
+	}
+
+	It("accepts a cluster without default timeouts", func() {
+		Expect(buildCluster(nil).validateTimeouts()).To(BeEmpty())
+	})
+
+	It("accepts valid durations and exempted roles", func() {
+		Expect(buildCluster(&TimeoutsConfiguration{
+			StatementTimeout:                "30s",
+			IdleInTransactionSessionTimeout: "0",
+			ExemptRoles:                     []string{"batch", "migrations"},
+		}).validateTimeouts()).To(BeEmpty())
+	})
+
+	It("rejects invalid durations", func() {
+		result := buildCluster(&TimeoutsConfiguration{
+			StatementTimeout:                "30 seconds",
+			IdleInTransactionSessionTimeout: "-1s",
+		}).validateTimeouts()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.timeouts.statementTimeout"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.timeouts.idleInTransactionSessionTimeout"))
+	})
+
+	It("rejects durations which cannot be expressed in milliseconds", func() {
+		Expect(buildCluster(&TimeoutsConfiguration{StatementTimeout: "10us"}).validateTimeouts()).To(HaveLen(1))
+		Expect(buildCluster(&TimeoutsConfiguration{StatementTimeout: "1000h"}).validateTimeouts()).To(HaveLen(1))
+	})
+
+	It("rejects the default timeouts together with the corresponding parameters", func() {
+		cluster := buildCluster(&TimeoutsConfiguration{StatementTimeout: "30s"})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"statement_timeout":                   "10s",
+			"idle_in_transaction_session_timeout": "10s",
+		}
+		Expect(cluster.validateTimeouts()).To(HaveLen(1))
+	})
+
+	It("rejects exempted roles without default timeouts", func() {
+		Expect(buildCluster(&TimeoutsConfiguration{
+			ExemptRoles: []string{"batch"},
+		}).validateTimeouts()).To(HaveLen(1))
+	})
+
+	It("rejects empty and duplicated exempted roles", func() {
+		result := buildCluster(&TimeoutsConfiguration{
+			StatementTimeout: "30s",
+			ExemptRoles:      []string{"batch", "", "batch"},
+		}).validateTimeouts()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.timeouts.exemptRoles[1]"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.timeouts.exemptRoles[2]"))
+	})
+})
+
+var _ = Describe("memory settings validation", func() {
+	buildCluster := func(configuration *MemoryConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					Memory: configuration,
+				},
+			},
+		}
+	}
+
+	It("accepts a missing configuration", func() {
+		Expect(buildCluster(nil).validateMemory()).To(BeEmpty())
+	})
+
+	It("accepts valid percentages", func() {
+		Expect(buildCluster(&MemoryConfiguration{
+			SharedBuffersPercentage:      25,
+			EffectiveCacheSizePercentage: 75,
+		}).validateMemory()).To(BeEmpty())
+		Expect(buildCluster(&MemoryConfiguration{
+			SharedBuffersPercentage: 40,
+		}).validateMemory()).To(BeEmpty())
+	})
+
+	It("rejects an effective cache size lower than the shared buffers", func() {
+		errs := buildCluster(&MemoryConfiguration{
+			SharedBuffersPercentage:      50,
+			EffectiveCacheSizePercentage: 40,
+		}).validateMemory()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.memory.effectiveCacheSizePercentage"))
+	})
+
+	It("rejects the percentages together with the corresponding parameters", func() {
+		cluster := buildCluster(&MemoryConfiguration{
+			SharedBuffersPercentage:      25,
+			EffectiveCacheSizePercentage: 75,
+		})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"shared_buffers":       "1GB",
+			"effective_cache_size": "3GB",
+		}
+		Expect(cluster.validateMemory()).To(HaveLen(2))
+	})
+
+	It("requires a memory limit", func() {
+		cluster := buildCluster(&MemoryConfiguration{SharedBuffersPercentage: 25})
+		cluster.Spec.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		}
+		errs := cluster.validateMemory()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.resources.limits.memory"))
+	})
+})
+
+var _ = Describe("transactionIDWraparound validation", func() {
+	buildCluster := func(configuration *TransactionIDWraparoundConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					TransactionIDWraparound: configuration,
+				},
+			},
+		}
+	}
+
+	It("accepts a missing configuration", func() {
+		Expect(buildCluster(nil).validateTransactionIDWraparound()).To(BeEmpty())
+	})
+
+	It("accepts a warning age lower than the critical one", func() {
+		Expect(buildCluster(&TransactionIDWraparoundConfiguration{
+			WarningAge:  500000000,
+			CriticalAge: 800000000,
+		}).validateTransactionIDWraparound()).To(BeEmpty())
+		Expect(buildCluster(&TransactionIDWraparoundConfiguration{
+			WarningAge: 500000000,
+		}).validateTransactionIDWraparound()).To(BeEmpty())
+	})
+
+	It("rejects a warning age not lower than the critical one", func() {
+		Expect(buildCluster(&TransactionIDWraparoundConfiguration{
+			WarningAge:  800000000,
+			CriticalAge: 800000000,
+		}).validateTransactionIDWraparound()).To(HaveLen(1))
+		Expect(buildCluster(&TransactionIDWraparoundConfiguration{
+			CriticalAge: 500000000,
+		}).validateTransactionIDWraparound()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("hotStandbyFeedbackInstances validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					HotStandbyFeedbackInstances: []string{"cluster-example-3"},
+				},
+			},
+		}
+	})
+
+	It("accepts the instances of the cluster", func() {
+		Expect(cluster.validateHotStandbyFeedbackInstances()).To(BeEmpty())
+	})
+
+	It("accepts an empty list", func() {
+		cluster.Spec.PostgresConfiguration.HotStandbyFeedbackInstances = nil
+		Expect(cluster.validateHotStandbyFeedbackInstances()).To(BeEmpty())
+	})
+
+	It("rejects names not belonging to the cluster", func() {
+		cluster.Spec.PostgresConfiguration.HotStandbyFeedbackInstances = []string{
+			"cluster-example-3",
+			"other-cluster-1",
+			"cluster-example-0",
+			"cluster-example-x",
+		}
+		errs := cluster.validateHotStandbyFeedbackInstances()
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.hotStandbyFeedbackInstances[1]"))
+	})
+
+	It("rejects the usage together with the hot_standby_feedback parameter", func() {
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"hot_standby_feedback": "off",
+		}
+		errs := cluster.validateHotStandbyFeedbackInstances()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	})
+})
+
+var _ = Describe("delayedReplicas validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				Instances: 3,
+				PostgresConfiguration: PostgresConfiguration{
+					DelayedReplicas: &DelayedReplicasConfiguration{
+						Instances:     []string{"cluster-example-3"},
+						MinApplyDelay: "1h",
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(cluster.validateDelayedReplicas()).To(BeEmpty())
+	})
+
+	It("accepts a cluster without delayed replicas", func() {
+		cluster.Spec.PostgresConfiguration.DelayedReplicas = nil
+		Expect(cluster.validateDelayedReplicas()).To(BeEmpty())
+	})
+
+	It("rejects names not belonging to the cluster", func() {
+		cluster.Spec.PostgresConfiguration.DelayedReplicas.Instances = []string{"other-cluster-2"}
+		errs := cluster.validateDelayedReplicas()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.delayedReplicas.instances[0]"))
+	})
+
+	It("rejects duplicate instances", func() {
+		cluster.Spec.PostgresConfiguration.DelayedReplicas.Instances = []string{"cluster-example-3", "cluster-example-3"}
+		errs := cluster.validateDelayedReplicas()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.delayedReplicas.instances[1]"))
+	})
+
+	It("rejects delaying every instance", func() {
+		cluster.Spec.Instances = 1
+		errs := cluster.validateDelayedReplicas()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.delayedReplicas.instances"))
+	})
+
+	DescribeTable("rejects invalid delays",
+		func(delay string) {
+			cluster.Spec.PostgresConfiguration.DelayedReplicas.MinApplyDelay = delay
+			errs := cluster.validateDelayedReplicas()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.postgresql.delayedReplicas.minApplyDelay"))
+		},
+		Entry("not a duration", "one hour"),
+		Entry("zero", "0s"),
+		Entry("negative", "-1h"),
+		Entry("too long", "1000h"),
+	)
+})
+
+var _ = Describe("Recovery from volume snapshot validation", func() {
+	clusterFromRecovery := func(recovery *BootstrapRecovery) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: recovery,
+				},
+				WalStorage: &StorageConfiguration{},
+			},
+		}
+	}
+
+	It("should produce an error when defining two recovery sources at the same time", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source:          "sourceName",
+						Backup:          &BackupSource{},
+						VolumeSnapshots: &DataSource{},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(1))
+	})
+
+	It("should produce an error when defining a backupID while recovering using a DataSource", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{
+							BackupID: "20220616T031500",
+						},
+						VolumeSnapshots: &DataSource{
+							Storage: corev1.TypedLocalObjectReference{
+								APIGroup: ptr.To(""),
+								Kind:     "PersistentVolumeClaim",
+								Name:     "pgdata",
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapRecoveryDataSource()).To(HaveLen(1))
+	})
 
 	It("should produce an error when asking to recovery WALs from a snapshot without having storage for it", func() {
 		cluster := Cluster{
@@ -4304,6 +5426,93 @@ var _ = Describe("validateResources", func() {
 	})
 })
 
+var _ = Describe("max_connections", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{},
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: map[corev1.ResourceName]resource.Quantity{},
+					Limits:   map[corev1.ResourceName]resource.Quantity{},
+				},
+			},
+		}
+	})
+
+	It("accepts a cluster without max_connections", func() {
+		Expect(cluster.validateMaxConnections()).To(BeEmpty())
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("accepts a valid max_connections", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "200"
+		Expect(cluster.validateMaxConnections()).To(BeEmpty())
+	})
+
+	DescribeTable("rejects invalid values",
+		func(value string) {
+			cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = value
+			errors := cluster.validateMaxConnections()
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("spec.postgresql.parameters.max_connections"))
+		},
+		Entry("not a number", "many"),
+		Entry("zero", "0"),
+		Entry("negative", "-10"),
+		Entry("too high", "262144"),
+	)
+
+	It("doesn't warn without memory resources", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "10000"
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when the memory is enough", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "100"
+		cluster.Spec.Resources.Limits["memory"] = resource.MustParse("1Gi")
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("warns when the memory limit is not enough", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "500"
+		cluster.Spec.Resources.Requests["memory"] = resource.MustParse("8Gi")
+		cluster.Spec.Resources.Limits["memory"] = resource.MustParse("2Gi")
+		warnings := cluster.getMaxConnectionsAdmissionWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("`max_connections` is set to 500"))
+		Expect(warnings[0]).To(ContainSubstring("2Gi"))
+		Expect(cluster.getAdmissionWarnings()).To(Equal(warnings))
+	})
+
+	It("falls back to the memory request without a limit", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "500"
+		cluster.Spec.Resources.Requests["memory"] = resource.MustParse("2Gi")
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(HaveLen(1))
+	})
+
+	It("considers shared_buffers and work_mem", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "100"
+		cluster.Spec.Resources.Limits["memory"] = resource.MustParse("2Gi")
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+
+		cluster.Spec.PostgresConfiguration.Parameters["shared_buffers"] = "1GB"
+		cluster.Spec.PostgresConfiguration.Parameters["work_mem"] = "16MB"
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(HaveLen(1))
+	})
+
+	It("uses the PostgreSQL default units for values without a unit", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["max_connections"] = "100"
+		cluster.Spec.PostgresConfiguration.Parameters["shared_buffers"] = "16384"
+		cluster.Spec.PostgresConfiguration.Parameters["work_mem"] = "4096"
+		cluster.Spec.Resources.Limits["memory"] = resource.MustParse("1Gi")
+		Expect(cluster.getMaxConnectionsAdmissionWarnings()).To(BeEmpty())
+	})
+})
+
 var _ = Describe("Tablespaces validation", func() {
 	createFakeTemporaryTbsConf := func(name string) TablespaceConfiguration {
 		return TablespaceConfiguration{
@@ -4665,11 +5874,85 @@ var _ = Describe("Validate hibernation", func() {
 	})
 })
 
-var _ = Describe("validateManagedServices", func() {
-	var cluster *Cluster
+var _ = Describe("validateFencingModeAnnotation", func() {
+	It("should succeed if the fencing mode is not set", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateFencingModeAnnotation()).To(BeEmpty())
+	})
 
-	BeforeEach(func() {
-		cluster = &Cluster{
+	It("should succeed if the fencing mode is 'readOnly'", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencingModeAnnotationName: string(utils.FencingModeReadOnly),
+				},
+			},
+		}
+		Expect(cluster.validateFencingModeAnnotation()).To(BeEmpty())
+	})
+
+	It("should fail if the fencing mode is set to an invalid value", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencingModeAnnotationName: "read-only",
+				},
+			},
+		}
+		Expect(cluster.validateFencingModeAnnotation()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validateReadOnlyFencingChange", func() {
+	var oldCluster *Cluster
+
+	newCluster := func(fencedInstances string, mode utils.FencingMode) *Cluster {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation:  fencedInstances,
+					utils.FencingModeAnnotationName: string(mode),
+				},
+			},
+		}
+		cluster.Status.CurrentPrimary = "cluster-example-1"
+		return cluster
+	}
+
+	BeforeEach(func() {
+		oldCluster = newCluster("[]", utils.FencingModeReadOnly)
+	})
+
+	It("allows fencing a replica in read-only mode", func() {
+		cluster := newCluster(`["cluster-example-2"]`, utils.FencingModeReadOnly)
+		Expect(cluster.validateReadOnlyFencingChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("allows stopping the primary", func() {
+		cluster := newCluster(`["cluster-example-1"]`, utils.FencingModeStop)
+		Expect(cluster.validateReadOnlyFencingChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("rejects fencing the primary in read-only mode", func() {
+		cluster := newCluster(`["cluster-example-1"]`, utils.FencingModeReadOnly)
+		Expect(cluster.validateReadOnlyFencingChange(oldCluster)).To(HaveLen(1))
+
+		cluster = newCluster(`["*"]`, utils.FencingModeReadOnly)
+		Expect(cluster.validateReadOnlyFencingChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("rejects switching the fence of the primary to read-only mode", func() {
+		oldCluster = newCluster(`["cluster-example-1"]`, utils.FencingModeStop)
+		cluster := newCluster(`["cluster-example-1"]`, utils.FencingModeReadOnly)
+		Expect(cluster.validateReadOnlyFencingChange(oldCluster)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validateManagedServices", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "test",
 			},
@@ -4874,3 +6157,1076 @@ var _ = Describe("ServiceTemplate Validation", func() {
 		})
 	})
 })
+
+var _ = Describe("Additional WAL destinations validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket-one/",
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+					},
+					AdditionalWalDestinations: []WalArchiveDestination{
+						{
+							Name: "offsite",
+							BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{
+								DestinationPath: "s3://bucket-two/",
+								BarmanCredentials: BarmanCredentials{
+									AWS: &S3Credentials{InheritFromIAMRole: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a valid configuration", func() {
+		cluster.Spec.Backup.WalArchiveQuorum = ptr.To(1)
+		Expect(cluster.validateAdditionalWalDestinations()).To(BeEmpty())
+	})
+
+	It("requires barmanObjectStore to be configured", func() {
+		cluster.Spec.Backup.BarmanObjectStore = nil
+		Expect(cluster.validateAdditionalWalDestinations()).To(HaveLen(1))
+	})
+
+	It("rejects a quorum greater than the number of destinations", func() {
+		cluster.Spec.Backup.WalArchiveQuorum = ptr.To(3)
+		Expect(cluster.validateAdditionalWalDestinations()).To(HaveLen(1))
+	})
+
+	It("requires exactly one set of credentials", func() {
+		cluster.Spec.Backup.AdditionalWalDestinations[0].BarmanCredentials = BarmanCredentials{}
+		Expect(cluster.validateAdditionalWalDestinations()).To(HaveLen(1))
+	})
+
+	It("rejects custom endpoint CA bundles", func() {
+		cluster.Spec.Backup.AdditionalWalDestinations[0].EndpointCA = &SecretKeySelector{
+			LocalObjectReference: LocalObjectReference{Name: "ca"},
+			Key:                  "ca.crt",
+		}
+		Expect(cluster.validateAdditionalWalDestinations()).To(HaveLen(1))
+	})
+
+	It("rejects destinations archiving in the same location", func() {
+		cluster.Spec.Backup.AdditionalWalDestinations[0].DestinationPath = "s3://bucket-one"
+		Expect(cluster.validateAdditionalWalDestinations()).To(HaveLen(1))
+
+		cluster.Spec.Backup.AdditionalWalDestinations[0].ServerName = "other-name"
+		Expect(cluster.validateAdditionalWalDestinations()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("backup archive tier validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket-one/",
+					},
+					RetentionPolicy: "30d",
+					AdditionalWalDestinations: []WalArchiveDestination{
+						{
+							Name: "cold",
+							BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{
+								DestinationPath: "s3://bucket-two/",
+							},
+						},
+					},
+					Archive: &BackupArchiveConfiguration{
+						DestinationName: "cold",
+						ArchiveAfter:    "4w",
+						RetentionPolicy: "12m",
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(cluster.validateBackupArchive()).To(BeEmpty())
+		Expect(cluster.Spec.Backup.GetArchiveDestination().Name).To(Equal("cold"))
+	})
+
+	It("requires barmanObjectStore to be configured", func() {
+		cluster.Spec.Backup.BarmanObjectStore = nil
+		Expect(cluster.validateBackupArchive()).To(HaveLen(1))
+	})
+
+	It("requires the archive tier to be an additional WAL destination", func() {
+		cluster.Spec.Backup.Archive.DestinationName = "unknown"
+		errs := cluster.validateBackupArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.archive.destinationName"))
+		Expect(cluster.Spec.Backup.GetArchiveDestination()).To(BeNil())
+	})
+
+	It("requires a retention policy longer than archiveAfter", func() {
+		cluster.Spec.Backup.RetentionPolicy = ""
+		cluster.Spec.Backup.Archive.RetentionPolicy = "28d"
+		errs := cluster.validateBackupArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.archive.retentionPolicy"))
+	})
+
+	It("requires a retention policy longer than the one of barmanObjectStore", func() {
+		cluster.Spec.Backup.RetentionPolicy = "6m"
+		cluster.Spec.Backup.Archive.RetentionPolicy = "5m"
+		errs := cluster.validateBackupArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.archive.retentionPolicy"))
+	})
+
+	It("accepts an archive tier without retention policy", func() {
+		cluster.Spec.Backup.Archive.RetentionPolicy = ""
+		Expect(cluster.validateBackupArchive()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("object store endpoint validation", func() {
+	var configuration *BarmanObjectStoreConfiguration
+	path := field.NewPath("spec", "backup", "barmanObjectStore")
+
+	BeforeEach(func() {
+		configuration = &BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://bucket/",
+			EndpointURL:     "https://minio.example.com:9000",
+			EndpointCA: &SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: "minio-ca"},
+				Key:                  "ca.crt",
+			},
+			ForcePathStyle: true,
+			BarmanCredentials: BarmanCredentials{
+				AWS: &S3Credentials{InheritFromIAMRole: true},
+			},
+		}
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(validateObjectStoreEndpoint(path, configuration)).To(BeEmpty())
+	})
+
+	DescribeTable("rejects invalid endpoints",
+		func(endpointURL string) {
+			configuration.EndpointURL = endpointURL
+			errs := validateObjectStoreEndpoint(path, configuration)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.endpointURL"))
+		},
+		Entry("not a URL", "https://minio:port"),
+		Entry("without a scheme", "minio.example.com"),
+		Entry("with an unsupported scheme", "ftp://minio.example.com"),
+		Entry("without a host", "https://"),
+	)
+
+	It("requires the name and the key of the CA bundle", func() {
+		configuration.EndpointCA.Key = ""
+		errs := validateObjectStoreEndpoint(path, configuration)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.endpointCA"))
+	})
+
+	It("rejects a CA bundle with an http endpoint", func() {
+		configuration.EndpointURL = "http://minio.example.com:9000"
+		errs := validateObjectStoreEndpoint(path, configuration)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.endpointCA"))
+	})
+
+	It("allows path-style addressing only with the S3 credentials", func() {
+		configuration.BarmanCredentials = BarmanCredentials{
+			Azure: &AzureCredentials{InheritFromAzureAD: true},
+		}
+		errs := validateObjectStoreEndpoint(path, configuration)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.forcePathStyle"))
+	})
+
+	It("is applied to the object stores of the external clusters", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{Name: "origin", BarmanObjectStore: configuration},
+				},
+			},
+		}
+		configuration.EndpointURL = "minio.example.com"
+		errs := cluster.validateExternalClusters()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.externalClusters[0].barmanObjectStore.endpointURL"))
+	})
+})
+
+var _ = Describe("unsafe durability settings validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:16",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"fsync":            "off",
+						"full_page_writes": "off",
+					},
+				},
+			},
+		}
+	})
+
+	It("rejects disabling fsync and full_page_writes without the annotation", func() {
+		result := cluster.validateConfiguration()
+		Expect(result).To(HaveLen(2))
+		for _, err := range result {
+			Expect(err.Detail).To(ContainSubstring(utils.UnsafeDurabilityAnnotationName))
+		}
+		Expect(cluster.getAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("allows enabling fsync without the annotation", func() {
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"fsync": "on",
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+	})
+
+	It("allows disabling fsync and full_page_writes with the annotation, warning about it", func() {
+		cluster.Annotations = map[string]string{
+			utils.UnsafeDurabilityAnnotationName: "enabled",
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+
+		warnings := cluster.getAdmissionWarnings()
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0]).To(ContainSubstring("fsync"))
+		Expect(warnings[1]).To(ContainSubstring("full_page_writes"))
+	})
+})
+
+var _ = Describe("pg_hba rules validation", func() {
+	It("accepts valid rules", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBAPrepend: []string{"host all all 10.1.0.0/16 reject"},
+					PgHBA:        []string{"hostssl app app 10.244.0.0/16 scram-sha-256"},
+				},
+			},
+		}
+		Expect(cluster.validatePgHBA()).To(BeEmpty())
+	})
+
+	It("rejects invalid rules, reporting their position", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBAPrepend: []string{"host all all 10.1.0.0/16 reject", "host all all 10.1.0.0/99 reject"},
+					PgHBA:        []string{"hostssl app app all scram"},
+				},
+			},
+		}
+		result := cluster.validatePgHBA()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.pg_hba_prepend[1]"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.pg_hba[0]"))
+	})
+
+	It("warns about the prepended rules shadowing the fixed ones", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBAPrepend: []string{"hostssl app app all scram-sha-256", "host all all 10.1.0.0/16 reject"},
+					PgHBA:        []string{"host all all all reject"},
+				},
+			},
+		}
+		warnings := cluster.getPgHBAPrependAdmissionWarnings()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("pg_hba_prepend[1]"))
+	})
+})
+
+var _ = Describe("shared WAL restore cache validation", func() {
+	It("accepts the default retention period", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalRestoreCache: &WalRestoreCacheConfiguration{ClaimName: "shared-wal-cache"},
+			},
+		}
+		Expect(cluster.validateWalRestoreCache()).To(BeEmpty())
+		Expect(cluster.Spec.WalRestoreCache.GetRetentionPeriod()).To(Equal(time.Hour))
+	})
+
+	It("accepts a valid retention period", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				WalRestoreCache: &WalRestoreCacheConfiguration{
+					ClaimName:       "shared-wal-cache",
+					RetentionPeriod: "30m",
+				},
+			},
+		}
+		Expect(cluster.validateWalRestoreCache()).To(BeEmpty())
+		Expect(cluster.Spec.WalRestoreCache.GetRetentionPeriod()).To(Equal(30 * time.Minute))
+	})
+
+	DescribeTable("rejects invalid retention periods",
+		func(retentionPeriod string) {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					WalRestoreCache: &WalRestoreCacheConfiguration{
+						ClaimName:       "shared-wal-cache",
+						RetentionPeriod: retentionPeriod,
+					},
+				},
+			}
+			errors := cluster.validateWalRestoreCache()
+			Expect(errors).To(HaveLen(1))
+			Expect(errors[0].Field).To(Equal("spec.walRestoreCache.retentionPeriod"))
+		},
+		Entry("not a duration", "one hour"),
+		Entry("too short", "10s"),
+		Entry("negative", "-1h"),
+	)
+})
+
+var _ = Describe("PostgreSQL parameter names validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:13.15",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"shared_buffers":         "256MB",
+						"pg_stat_statements.max": "10000",
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts the parameters supported by the major version", func() {
+		Expect(cluster.validateParameterNames()).To(BeEmpty())
+	})
+
+	It("rejects the unknown parameters", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["shared_bufers"] = "256MB"
+		cluster.Spec.PostgresConfiguration.Parameters["wal_keep_segments"] = "32"
+
+		errors := cluster.validateParameterNames()
+		Expect(errors).To(HaveLen(2))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.parameters.shared_bufers"))
+		Expect(errors[0].Detail).To(ContainSubstring("PostgreSQL 13"))
+		Expect(errors[1].Field).To(Equal("spec.postgresql.parameters.wal_keep_segments"))
+	})
+
+	It("accepts the unknown parameters in the allowlist", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["vendor_patch_setting"] = "on"
+		cluster.Spec.PostgresConfiguration.AllowedUnknownParameters = []string{"Vendor_Patch_Setting"}
+		Expect(cluster.validateParameterNames()).To(BeEmpty())
+	})
+
+	It("doesn't check the parameters of an unknown major version", func() {
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:99.1"
+		cluster.Spec.PostgresConfiguration.Parameters["future_setting"] = "on"
+		Expect(cluster.validateParameterNames()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("managed logical replication slots", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{},
+				},
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: ptr.To(true),
+					},
+					Logical: []LogicalReplicationSlotConfiguration{
+						{Name: "cdc", Database: "app"},
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a cluster without managed logical replication slots", func() {
+		cluster.Spec.ReplicationSlots = nil
+		Expect(cluster.validateLogicalReplicationSlots()).To(BeEmpty())
+		Expect(cluster.getLogicalReplicationSlotsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("accepts valid logical replication slots", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["wal_level"] = "logical"
+		Expect(cluster.validateLogicalReplicationSlots()).To(BeEmpty())
+		Expect(cluster.getLogicalReplicationSlotsAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("rejects a wal_level not supporting logical decoding", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["wal_level"] = "replica"
+		errors := cluster.validateLogicalReplicationSlots()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.parameters.wal_level"))
+	})
+
+	It("rejects the names reserved for the high availability replication slots", func() {
+		cluster.Spec.ReplicationSlots.Logical = append(cluster.Spec.ReplicationSlots.Logical,
+			LogicalReplicationSlotConfiguration{Name: "_cnpg_cdc", Database: "app"})
+		errors := cluster.validateLogicalReplicationSlots()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.replicationSlots.logical[1].name"))
+	})
+
+	It("warns when the slots can't be synchronized on the standbys", func() {
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.4"
+		Expect(cluster.getLogicalReplicationSlotsAdmissionWarnings()).To(HaveLen(1))
+	})
+
+	It("warns when the high availability replication slots are disabled", func() {
+		cluster.Spec.ReplicationSlots.HighAvailability.Enabled = ptr.To(false)
+		Expect(cluster.getLogicalReplicationSlotsAdmissionWarnings()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("scheduled maintenance validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				ScheduledMaintenance: &ScheduledMaintenanceConfiguration{
+					Enabled: true,
+				},
+			},
+		}
+	})
+
+	It("accepts a cluster without scheduled maintenance", func() {
+		cluster.Spec.ScheduledMaintenance = nil
+		Expect(cluster.validateScheduledMaintenance()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		cluster.Spec.ScheduledMaintenance.Schedule = "0 30 3 * * 6"
+		cluster.Spec.ScheduledMaintenance.SQL = []string{"VACUUM (ANALYZE)"}
+		Expect(cluster.validateScheduledMaintenance()).To(BeEmpty())
+	})
+
+	It("rejects an invalid schedule", func() {
+		cluster.Spec.ScheduledMaintenance.Schedule = "every sunday"
+		errors := cluster.validateScheduledMaintenance()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.scheduledMaintenance.schedule"))
+	})
+
+	It("rejects empty maintenance statements", func() {
+		cluster.Spec.ScheduledMaintenance.SQL = []string{"VACUUM", " "}
+		errors := cluster.validateScheduledMaintenance()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.scheduledMaintenance.sql[1]"))
+	})
+
+	It("requires PostgreSQL 12 for the default maintenance statement", func() {
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:11.22"
+		Expect(cluster.validateScheduledMaintenance()).To(HaveLen(1))
+
+		cluster.Spec.ScheduledMaintenance.SQL = []string{"REINDEX DATABASE app"}
+		Expect(cluster.validateScheduledMaintenance()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("TLS configuration validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{},
+					TLS: &PostgresTLSConfiguration{
+						MinProtocolVersion: "TLSv1.2",
+						Ciphers:            []string{"ECDHE-RSA-AES256-GCM-SHA384", "!aNULL"},
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(cluster.validatePostgresTLS()).To(BeEmpty())
+		Expect(cluster.getPostgresTLSAdmissionWarnings()).To(BeEmpty())
+	})
+
+	It("rejects an unknown protocol version", func() {
+		cluster.Spec.PostgresConfiguration.TLS.MinProtocolVersion = "SSLv3"
+		errors := cluster.validatePostgresTLS()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.tls.minProtocolVersion"))
+	})
+
+	It("rejects a minimum protocol version higher than the maximum one", func() {
+		cluster.Spec.PostgresConfiguration.TLS.MinProtocolVersion = "TLSv1.3"
+		cluster.Spec.PostgresConfiguration.Parameters["ssl_max_protocol_version"] = "TLSv1.2"
+		errors := cluster.validatePostgresTLS()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.tls.minProtocolVersion"))
+	})
+
+	It("rejects the TLS settings also set in the parameters", func() {
+		cluster.Spec.PostgresConfiguration.Parameters["ssl_min_protocol_version"] = "TLSv1.2"
+		cluster.Spec.PostgresConfiguration.Parameters["ssl_ciphers"] = "HIGH"
+		Expect(cluster.validatePostgresTLS()).To(HaveLen(2))
+	})
+
+	It("rejects invalid cipher names", func() {
+		cluster.Spec.PostgresConfiguration.TLS.Ciphers = []string{"HIGH:MEDIUM"}
+		errors := cluster.validatePostgresTLS()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.tls.ciphers[0]"))
+	})
+
+	It("requires PostgreSQL 12", func() {
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:11.22"
+		Expect(cluster.validatePostgresTLS()).To(HaveLen(1))
+	})
+
+	It("warns when the ciphers are not used", func() {
+		cluster.Spec.PostgresConfiguration.TLS.MinProtocolVersion = ""
+		Expect(cluster.getPostgresTLSAdmissionWarnings()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("client certificate authentication validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{Name: "billing", Ensure: EnsurePresent},
+						{Name: "legacy", Ensure: EnsureAbsent},
+					},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					CertificateAuthentication: &CertificateAuthenticationConfiguration{
+						Mappings: []CertificateMapping{
+							{CommonName: "billing service", Role: "billing"},
+							{CommonName: `/^app-\d+$`, Role: "app"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts the mappings to the managed roles and to the application owner", func() {
+		Expect(cluster.validateCertificateAuthentication()).To(BeEmpty())
+	})
+
+	It("rejects the mappings to unknown or removed roles", func() {
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.Mappings = []CertificateMapping{
+			{CommonName: "reporting", Role: "reporting"},
+			{CommonName: "legacy", Role: "legacy"},
+		}
+		errors := cluster.validateCertificateAuthentication()
+		Expect(errors).To(HaveLen(2))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.certificateAuthentication.mappings[0].role"))
+		Expect(errors[1].Field).To(Equal("spec.postgresql.certificateAuthentication.mappings[1].role"))
+	})
+
+	It("rejects invalid common names", func() {
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.Mappings = []CertificateMapping{
+			{CommonName: "", Role: "app"},
+			{CommonName: `billing"service`, Role: "app"},
+			{CommonName: "/^billing service$", Role: "app"},
+		}
+		Expect(cluster.validateCertificateAuthentication()).To(HaveLen(3))
+	})
+
+	It("requires the names of the additional client CA secrets", func() {
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.AdditionalClientCASecrets =
+			[]LocalObjectReference{{Name: ""}}
+		errors := cluster.validateCertificateAuthentication()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Field).To(Equal("spec.postgresql.certificateAuthentication.additionalClientCASecrets[0].name"))
+	})
+})
+
+var _ = Describe("validate role placement", func() {
+	It("doesn't complain without role placement rules", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateRolePlacement()).To(BeEmpty())
+	})
+
+	It("doesn't complain with valid tolerations", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				RolePlacement: &RolePlacementConfiguration{
+					Primary: &InstancePlacement{
+						Tolerations: []corev1.Toleration{
+							{Key: "dedicated", Operator: "Equal", Value: "postgres", Effect: "NoSchedule"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRolePlacement()).To(BeEmpty())
+	})
+
+	It("complains for invalid tolerations of the replicas", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				RolePlacement: &RolePlacementConfiguration{
+					Replica: &InstancePlacement{
+						Tolerations: []corev1.Toleration{
+							{Key: "", Operator: "Equal", Effect: "NoSchedule"},
+						},
+					},
+				},
+			},
+		}
+		result := cluster.validateRolePlacement()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.rolePlacement.replica.tolerations[0].operator"))
+	})
+})
+
+var _ = Describe("validate the parameter change restart strategy", func() {
+	It("doesn't complain without a configuration", func() {
+		Expect((&Cluster{}).validateParameterChangeRestart()).To(BeEmpty())
+	})
+
+	It("requires a maintenance window for the maintenanceWindow strategy", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ParameterChangeRestart: &ParameterChangeRestartConfiguration{
+					Strategy: ParameterChangeRestartStrategyMaintenanceWindow,
+				},
+			},
+		}
+		result := cluster.validateParameterChangeRestart()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.parameterChangeRestart.maintenanceWindow"))
+	})
+
+	It("accepts a valid maintenance window", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ParameterChangeRestart: &ParameterChangeRestartConfiguration{
+					Strategy: ParameterChangeRestartStrategyMaintenanceWindow,
+					MaintenanceWindow: &MaintenanceWindowConfiguration{
+						Schedule: "0 0 2 * * 6",
+						Duration: "3h",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateParameterChangeRestart()).To(BeEmpty())
+	})
+
+	It("complains about an invalid schedule and duration", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ParameterChangeRestart: &ParameterChangeRestartConfiguration{
+					Strategy: ParameterChangeRestartStrategyMaintenanceWindow,
+					MaintenanceWindow: &MaintenanceWindowConfiguration{
+						Schedule: "every night",
+						Duration: "30s",
+					},
+				},
+			},
+		}
+		result := cluster.validateParameterChangeRestart()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.parameterChangeRestart.maintenanceWindow.schedule"))
+		Expect(result[1].Field).To(Equal("spec.parameterChangeRestart.maintenanceWindow.duration"))
+	})
+})
+
+var _ = Describe("WAL compression validation", func() {
+	buildCluster := func(compression CompressionType, level *int) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						Wal: &WalBackupConfiguration{
+							Compression:      compression,
+							CompressionLevel: level,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("accepts a compression without a level", func() {
+		Expect(buildCluster(CompressionTypeZstd, nil).validateWalCompression()).To(BeEmpty())
+	})
+
+	It("accepts a level in the range of the algorithm", func() {
+		Expect(buildCluster(CompressionTypeZstd, ptr.To(19)).validateWalCompression()).To(BeEmpty())
+		Expect(buildCluster(CompressionTypeGzip, ptr.To(9)).validateWalCompression()).To(BeEmpty())
+	})
+
+	It("complains about a level out of the range of the algorithm", func() {
+		Expect(buildCluster(CompressionTypeGzip, ptr.To(19)).validateWalCompression()).To(HaveLen(1))
+		Expect(buildCluster(CompressionTypeLz4, ptr.To(0)).validateWalCompression()).To(HaveLen(1))
+	})
+
+	It("complains about a level without an algorithm supporting it", func() {
+		Expect(buildCluster(CompressionTypeNone, ptr.To(3)).validateWalCompression()).To(HaveLen(1))
+		Expect(buildCluster(CompressionTypeSnappy, ptr.To(3)).validateWalCompression()).To(HaveLen(1))
+	})
+
+	It("validates the additional WAL destinations", func() {
+		cluster := buildCluster(CompressionTypeZstd, ptr.To(3))
+		cluster.Spec.Backup.AdditionalWalDestinations = []WalArchiveDestination{
+			{
+				Name: "offsite",
+				BarmanObjectStoreConfiguration: BarmanObjectStoreConfiguration{
+					Wal: &WalBackupConfiguration{
+						Compression:      CompressionTypeXz,
+						CompressionLevel: ptr.To(12),
+					},
+				},
+			},
+		}
+		errs := cluster.validateWalCompression()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.additionalWalDestinations[0].wal.compressionLevel"))
+	})
+})
+
+var _ = Describe("sidecars validation", func() {
+	buildCluster := func(sidecars ...corev1.Container) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Sidecars: sidecars,
+			},
+		}
+	}
+
+	It("accepts a cluster without sidecars", func() {
+		Expect(buildCluster().validateSidecars()).To(BeEmpty())
+	})
+
+	It("accepts valid sidecars", func() {
+		Expect(buildCluster(corev1.Container{
+			Name:  "log-shipper",
+			Image: "example.com/log-shipper:1.0",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "pgdata", MountPath: "/pgdata", ReadOnly: true},
+				{Name: "scratch-data", MountPath: "/run"},
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+			},
+		}).validateSidecars()).To(BeEmpty())
+	})
+
+	It("rejects reserved, duplicated and invalid names", func() {
+		result := buildCluster(
+			corev1.Container{Name: "postgres", Image: "example.com/a:1.0"},
+			corev1.Container{Name: "agent", Image: "example.com/a:1.0"},
+			corev1.Container{Name: "agent", Image: "example.com/a:1.0"},
+			corev1.Container{Name: "Agent_1", Image: "example.com/a:1.0"},
+		).validateSidecars()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Field).To(Equal("spec.sidecars[0].name"))
+		Expect(result[1].Field).To(Equal("spec.sidecars[2].name"))
+		Expect(result[1].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(result[2].Field).To(Equal("spec.sidecars[3].name"))
+	})
+
+	It("requires the image", func() {
+		result := buildCluster(corev1.Container{Name: "agent"}).validateSidecars()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.sidecars[0].image"))
+	})
+
+	It("rejects the readiness probe", func() {
+		result := buildCluster(corev1.Container{
+			Name:           "agent",
+			Image:          "example.com/agent:1.0",
+			ReadinessProbe: &corev1.Probe{},
+		}).validateSidecars()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeForbidden))
+	})
+
+	It("rejects the ports used by the instance", func() {
+		result := buildCluster(corev1.Container{
+			Name:  "agent",
+			Image: "example.com/agent:1.0",
+			Ports: []corev1.ContainerPort{
+				{ContainerPort: 5432},
+				{ContainerPort: 9000},
+				{ContainerPort: 9187},
+			},
+		}).validateSidecars()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.sidecars[0].ports[0].containerPort"))
+		Expect(result[1].Field).To(Equal("spec.sidecars[0].ports[2].containerPort"))
+	})
+
+	It("only allows mounting the shared volumes of the instance", func() {
+		sidecar := corev1.Container{
+			Name:         "agent",
+			Image:        "example.com/agent:1.0",
+			VolumeMounts: []corev1.VolumeMount{{Name: "pg-wal", MountPath: "/pgwal"}},
+		}
+
+		result := buildCluster(sidecar).validateSidecars()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.sidecars[0].volumeMounts[0].name"))
+
+		cluster := buildCluster(sidecar)
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "1Gi"}
+		Expect(cluster.validateSidecars()).To(BeEmpty())
+
+		sidecar.VolumeMounts = []corev1.VolumeMount{{Name: "superuser-secret", MountPath: "/secret"}}
+		Expect(buildCluster(sidecar).validateSidecars()).To(HaveLen(1))
+	})
+
+	It("rejects requests greater than the limits", func() {
+		result := buildCluster(corev1.Container{
+			Name:  "agent",
+			Image: "example.com/agent:1.0",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		}).validateSidecars()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.sidecars[0].resources.requests[cpu]"))
+	})
+})
+
+var _ = Describe("failover priority validation", func() {
+	buildCluster := func(configuration *FailoverPriorityConfiguration) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Instances:        3,
+				FailoverPriority: configuration,
+			},
+		}
+	}
+
+	It("accepts a cluster without failover priority", func() {
+		Expect(buildCluster(nil).validateFailoverPriority()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		tolerance := resource.MustParse("64Mi")
+		Expect(buildCluster(&FailoverPriorityConfiguration{
+			Instances: []InstanceFailoverPriority{
+				{Name: "cluster-example-2", Priority: 10},
+				{Name: "cluster-example-3", Priority: -1},
+			},
+			LSNTolerance: &tolerance,
+		}).validateFailoverPriority()).To(BeEmpty())
+	})
+
+	It("rejects invalid and duplicated instance names", func() {
+		result := buildCluster(&FailoverPriorityConfiguration{
+			Instances: []InstanceFailoverPriority{
+				{Name: "cluster-example-2", Priority: 10},
+				{Name: "another-cluster-2", Priority: 5},
+				{Name: "cluster-example-2", Priority: 1},
+			},
+		}).validateFailoverPriority()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.failoverPriority.instances[1].name"))
+		Expect(result[1].Type).To(Equal(field.ErrorTypeDuplicate))
+	})
+
+	It("rejects negative and too large LSN tolerances", func() {
+		negative := resource.MustParse("-1Mi")
+		Expect(buildCluster(&FailoverPriorityConfiguration{
+			Instances:    []InstanceFailoverPriority{{Name: "cluster-example-2", Priority: 10}},
+			LSNTolerance: &negative,
+		}).validateFailoverPriority()).To(HaveLen(1))
+
+		tooLarge := resource.MustParse("2Gi")
+		Expect(buildCluster(&FailoverPriorityConfiguration{
+			Instances:    []InstanceFailoverPriority{{Name: "cluster-example-2", Priority: 10}},
+			LSNTolerance: &tooLarge,
+		}).validateFailoverPriority()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("replica autoscaling validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Instances: 3,
+				ReplicaAutoscaling: &ReplicaAutoscalingConfiguration{
+					MinInstances:            2,
+					MaxInstances:            5,
+					TargetActiveConnections: 20,
+				},
+			},
+		}
+	})
+
+	It("accepts a cluster without replica autoscaling", func() {
+		cluster.Spec.ReplicaAutoscaling = nil
+		Expect(cluster.validateReplicaAutoscaling()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		Expect(cluster.validateReplicaAutoscaling()).To(BeEmpty())
+	})
+
+	It("rejects a minimum lower than one", func() {
+		cluster.Spec.ReplicaAutoscaling.MinInstances = 0
+		result := cluster.validateReplicaAutoscaling()
+		Expect(result).ToNot(BeEmpty())
+		Expect(result[0].Field).To(Equal("spec.replicaAutoscaling.minInstances"))
+		Expect(result[0].Detail).To(Equal("must be at least 1"))
+	})
+
+	It("rejects a target of active connections lower than one", func() {
+		cluster.Spec.ReplicaAutoscaling.TargetActiveConnections = 0
+		result := cluster.validateReplicaAutoscaling()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicaAutoscaling.targetActiveConnections"))
+	})
+
+	It("rejects a maximum lower than the minimum", func() {
+		cluster.Spec.ReplicaAutoscaling.MaxInstances = 1
+		result := cluster.validateReplicaAutoscaling()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicaAutoscaling.maxInstances"))
+	})
+
+	It("rejects a minimum not leaving room for the synchronous replicas", func() {
+		cluster.Spec.MaxSyncReplicas = 2
+		result := cluster.validateReplicaAutoscaling()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicaAutoscaling.minInstances"))
+	})
+
+	It("rejects a minimum not leaving room for the delayed replicas", func() {
+		cluster.Spec.PostgresConfiguration.DelayedReplicas = &DelayedReplicasConfiguration{
+			Instances:     []string{"cluster-example-2", "cluster-example-3"},
+			MinApplyDelay: "1h",
+		}
+		result := cluster.validateReplicaAutoscaling()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicaAutoscaling.minInstances"))
+	})
+})
+
+var _ = Describe("initdb locales and time zone validation", func() {
+	buildCluster := func(initDB *BootstrapInitDB) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{InitDB: initDB},
+			},
+		}
+	}
+
+	It("accepts valid locales and time zones", func() {
+		Expect(buildCluster(&BootstrapInitDB{
+			Locale:        "en_US.UTF-8",
+			LocaleCollate: "de_DE@euro",
+			LocaleCType:   "C",
+			Timezone:      "America/Argentina/Buenos_Aires",
+		}).validateInitDB()).To(BeEmpty())
+		Expect(buildCluster(&BootstrapInitDB{Timezone: "Etc/GMT+2"}).validateInitDB()).To(BeEmpty())
+	})
+
+	It("rejects malformed locales", func() {
+		result := buildCluster(&BootstrapInitDB{
+			Locale:      "en_US.UTF-8 --auth=trust",
+			LocaleCType: "it_IT'",
+		}).validateInitDB()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.locale"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.initdb.localeCType"))
+	})
+
+	It("rejects malformed time zones", func() {
+		for _, timezone := range []string{"Europe/", "/etc/passwd", "Europe Rome"} {
+			result := buildCluster(&BootstrapInitDB{Timezone: timezone}).validateInitDB()
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.timezone"))
+		}
+	})
+})
+
+var _ = Describe("deletion confirmation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				DeletionPolicy: &DeletionPolicyConfiguration{
+					RequireConfirmation: true,
+				},
+			},
+		}
+	})
+
+	It("allows the deletion when no confirmation is required", func() {
+		cluster.Spec.DeletionPolicy = nil
+		_, err := cluster.ValidateDelete()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("forbids the deletion without the confirmation annotation", func() {
+		_, err := cluster.ValidateDelete()
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	It("forbids the deletion when the annotation doesn't match the cluster name", func() {
+		cluster.Annotations = map[string]string{
+			utils.DeletionConfirmationAnnotationName: "another-cluster",
+		}
+		_, err := cluster.ValidateDelete()
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	It("allows the confirmed deletion", func() {
+		cluster.Annotations = map[string]string{
+			utils.DeletionConfirmationAnnotationName: "cluster-example",
+		}
+		_, err := cluster.ValidateDelete()
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
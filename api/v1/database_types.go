@@ -0,0 +1,341 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// DatabaseReclaimPolicy describes a policy for end-of-life maintenance of databases.
+// +enum
+type DatabaseReclaimPolicy string
+
+const (
+	// DatabaseReclaimDelete means the database will be deleted from its PostgreSQL Cluster on release
+	// from its claim.
+	DatabaseReclaimDelete DatabaseReclaimPolicy = "delete"
+
+	// DatabaseReclaimRetain means the database will be left in its current phase for manual
+	// reclamation by the administrator. The default policy is Retain.
+	DatabaseReclaimRetain DatabaseReclaimPolicy = "retain"
+)
+
+// DatabaseFinalizerName is the name of the finalizer used to drop the
+// database when the Database object is deleted with the `delete` reclaim policy
+const DatabaseFinalizerName = utils.MetadataNamespace + "/deleteDatabase"
+
+// DatabaseSpec is the specification of a Postgresql Database
+type DatabaseSpec struct {
+	// The corresponding cluster
+	ClusterRef corev1.LocalObjectReference `json:"cluster"`
+
+	// Ensure the PostgreSQL database is `present` or `absent` - defaults to "present".
+	// Setting it to `absent` drops the database and all its data.
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The name inside PostgreSQL
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	// +kubebuilder:validation:XValidation:rule="self != 'postgres'",message="the name postgres is reserved"
+	// +kubebuilder:validation:XValidation:rule="self != 'template0'",message="the name template0 is reserved"
+	// +kubebuilder:validation:XValidation:rule="self != 'template1'",message="the name template1 is reserved"
+	Name string `json:"name"`
+
+	// The owner
+	Owner string `json:"owner"`
+
+	// The name of the template from which to create the new database
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="template is immutable"
+	Template string `json:"template,omitempty"`
+
+	// The encoding (cannot be changed)
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="encoding is immutable"
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The locale (cannot be changed)
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="locale is immutable"
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// True when the database is a template
+	// +optional
+	IsTemplate *bool `json:"isTemplate,omitempty"`
+
+	// True when connections to this database are allowed
+	// +optional
+	AllowConnections *bool `json:"allowConnections,omitempty"`
+
+	// Connection limit, -1 means no limit and -2 means the
+	// database is not valid
+	// +optional
+	ConnectionLimit *int `json:"connectionLimit,omitempty"`
+
+	// The default tablespace of this database
+	// +optional
+	Tablespace string `json:"tablespace,omitempty"`
+
+	// The policy for end-of-life maintenance of this database.
+	// Only when set to `delete` the database is dropped from PostgreSQL
+	// when this object is removed.
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=retain
+	// +optional
+	ReclaimPolicy DatabaseReclaimPolicy `json:"databaseReclaimPolicy,omitempty"`
+
+	// The list of extensions to be managed in this database.
+	// Extensions are reconciled after the database has been created.
+	// +optional
+	Extensions []ExtensionSpec `json:"extensions,omitempty"`
+
+	// The list of foreign servers to be managed in this database.
+	// Foreign servers are reconciled after the extensions, which
+	// provide their foreign-data wrappers
+	// +optional
+	Servers []ServerSpec `json:"servers,omitempty"`
+}
+
+// ExtensionSpec configures an extension in a database
+type ExtensionSpec struct {
+	// Name of the extension
+	Name string `json:"name"`
+
+	// Ensure the extension is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The version of the extension to install, defaults to the
+	// default version available in the image
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema where the extension objects are installed, defaults
+	// to the current schema of the database
+	// +optional
+	Schema string `json:"schema,omitempty"`
+}
+
+// ServerSpec configures a foreign server in a database
+type ServerSpec struct {
+	// Name of the foreign server
+	Name string `json:"name"`
+
+	// Ensure the foreign server is `present` or `absent` - defaults to "present".
+	// Foreign servers removed from the list are left untouched, and dropping
+	// one fails while other objects, like foreign tables, depend on it
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The foreign-data wrapper used by the server, like `postgres_fdw`
+	// (cannot be changed)
+	ForeignDataWrapper string `json:"fdw"`
+
+	// The options of the foreign server, like `host`, `port` and `dbname`
+	// for `postgres_fdw`
+	// +optional
+	Options []OptionSpec `json:"options,omitempty"`
+
+	// The mappings of the local roles to the credentials used
+	// to connect to the foreign server
+	// +optional
+	UserMappings []UserMappingSpec `json:"userMappings,omitempty"`
+}
+
+// UserMappingSpec configures the mapping of a local role
+// to the credentials used to connect to a foreign server
+type UserMappingSpec struct {
+	// The local role, or `PUBLIC` to map every role
+	User string `json:"user"`
+
+	// Ensure the user mapping is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The secret containing the `username` and `password` keys, set as the
+	// `user` and `password` options of the user mapping. Changes to the
+	// secret are applied to the user mapping
+	// +optional
+	CredentialsSecret *LocalObjectReference `json:"credentialsSecret,omitempty"`
+
+	// The options of the user mapping
+	// +optional
+	Options []OptionSpec `json:"options,omitempty"`
+}
+
+// OptionSpec is an option of a foreign object
+type OptionSpec struct {
+	// The name of the option
+	Name string `json:"name"`
+
+	// The value of the option
+	Value string `json:"value"`
+}
+
+// DatabaseStatus defines the observed state of Database
+type DatabaseStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Ready is true if the database was reconciled correctly
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Error is the reconciliation error message
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Extensions is the status of the managed extensions
+	// +optional
+	Extensions []ExtensionStatus `json:"extensions,omitempty"`
+
+	// Servers is the status of the managed foreign servers
+	// and of their user mappings
+	// +optional
+	Servers []ServerStatus `json:"servers,omitempty"`
+}
+
+// ExtensionStatus is the status of a managed extension
+type ExtensionStatus struct {
+	// The name of the extension
+	Name string `json:"name"`
+
+	// Ready is true if the extension was reconciled correctly
+	Ready bool `json:"ready"`
+
+	// Error is the reconciliation error message, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ServerStatus is the status of a managed foreign server
+type ServerStatus struct {
+	// The name of the foreign server
+	Name string `json:"name"`
+
+	// Ready is true if the foreign server and its
+	// user mappings were reconciled correctly
+	Ready bool `json:"ready"`
+
+	// Error is the reconciliation error message, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.error",description="Latest error message"
+
+// Database is the Schema for the databases API
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Database.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec DatabaseSpec `json:"spec"`
+	// Most recently observed status of the Database. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status DatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseList contains a list of Database
+type DatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Database `json:"items"`
+}
+
+// GetEnsure gets the desired state of the database, defaulting to present
+func (db *Database) GetEnsure() EnsureOption {
+	if db.Spec.Ensure == "" {
+		return EnsurePresent
+	}
+
+	return db.Spec.Ensure
+}
+
+// GetEnsure gets the desired state of the extension, defaulting to present
+func (ext ExtensionSpec) GetEnsure() EnsureOption {
+	if ext.Ensure == "" {
+		return EnsurePresent
+	}
+
+	return ext.Ensure
+}
+
+// GetEnsure gets the desired state of the foreign server, defaulting to present
+func (server ServerSpec) GetEnsure() EnsureOption {
+	if server.Ensure == "" {
+		return EnsurePresent
+	}
+
+	return server.Ensure
+}
+
+// GetEnsure gets the desired state of the user mapping, defaulting to present
+func (mapping UserMappingSpec) GetEnsure() EnsureOption {
+	if mapping.Ensure == "" {
+		return EnsurePresent
+	}
+
+	return mapping.Ensure
+}
+
+// GetCredentialsSecretNames gets the names of the secrets
+// containing the credentials of the user mappings
+func (db *Database) GetCredentialsSecretNames() []string {
+	var result []string
+	for _, server := range db.Spec.Servers {
+		for _, mapping := range server.UserMappings {
+			if mapping.CredentialsSecret != nil && mapping.CredentialsSecret.Name != "" {
+				result = append(result, mapping.CredentialsSecret.Name)
+			}
+		}
+	}
+
+	return result
+}
+
+func init() {
+	SchemeBuilder.Register(&Database{}, &DatabaseList{})
+}
@@ -21,6 +21,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // PoolerType is the type of the connection pool, meaning the service
@@ -51,6 +54,25 @@ const (
 	PgBouncerPoolModeTransaction = PgBouncerPoolMode("transaction")
 )
 
+// PgBouncerClientAuthMethod is the method used by PgBouncer to
+// authenticate the clients connecting through the network
+// +kubebuilder:validation:Enum=md5;scram-sha-256;cert
+type PgBouncerClientAuthMethod string
+
+const (
+	// PgBouncerClientAuthMethodMD5 accepts both MD5 and SCRAM-SHA-256
+	// authentication, depending on how the password of the user is stored
+	PgBouncerClientAuthMethodMD5 = PgBouncerClientAuthMethod("md5")
+
+	// PgBouncerClientAuthMethodScram only accepts SCRAM-SHA-256 authentication
+	PgBouncerClientAuthMethodScram = PgBouncerClientAuthMethod("scram-sha-256")
+
+	// PgBouncerClientAuthMethodCert requires a TLS connection and a client
+	// certificate, signed by the client CA of the cluster, whose common name
+	// is the name of the user
+	PgBouncerClientAuthMethodCert = PgBouncerClientAuthMethod("cert")
+)
+
 // PoolerSpec defines the desired state of Pooler
 type PoolerSpec struct {
 	// This is the cluster reference on which the Pooler will work.
@@ -85,6 +107,11 @@ type PoolerSpec struct {
 	// Template for the Service to be created
 	// +optional
 	ServiceTemplate *ServiceTemplateSpec `json:"serviceTemplate,omitempty"`
+
+	// Metadata that will be inherited by all objects related to the Pooler,
+	// like the deployment, the pods and the service
+	// +optional
+	InheritedMetadata *EmbeddedObjectMetadata `json:"inheritedMetadata,omitempty"`
 }
 
 // PoolerMonitoringConfiguration is the type containing all the monitoring
@@ -165,6 +192,18 @@ type PgBouncerSpec struct {
 	// +optional
 	AuthQuery string `json:"authQuery,omitempty"`
 
+	// The database where the authentication query is executed, instead
+	// of the one the client is connecting to. It corresponds to
+	// the `auth_dbname` option of PgBouncer
+	// +optional
+	AuthDBName string `json:"authDBName,omitempty"`
+
+	// The method used to authenticate the clients connecting to PgBouncer
+	// through the network, after the rules specified in `pg_hba`.
+	// Default: `md5`, which accepts both MD5 and SCRAM-SHA-256 authentication
+	// +optional
+	ClientAuthMethod PgBouncerClientAuthMethod `json:"clientAuthMethod,omitempty"`
+
 	// Additional parameters to be passed to PgBouncer - please check
 	// the CNPG documentation for a list of options you can configure
 	// +optional
@@ -189,6 +228,16 @@ func (in PgBouncerSpec) IsPaused() bool {
 	return in.Paused != nil && *in.Paused
 }
 
+// GetClientAuthMethod returns the method used to authenticate the
+// clients, defaulting to md5
+func (in PgBouncerSpec) GetClientAuthMethod() PgBouncerClientAuthMethod {
+	if in.ClientAuthMethod == "" {
+		return PgBouncerClientAuthMethodMD5
+	}
+
+	return in.ClientAuthMethod
+}
+
 // PoolerStatus defines the observed state of Pooler
 type PoolerStatus struct {
 	// The resource version of the config object
@@ -307,3 +356,14 @@ func (in *Pooler) IsAutomatedIntegration() bool {
 	}
 	return true
 }
+
+// SetInheritedData sets the annotations and the labels that all the
+// objects related to the Pooler inherit from its specification
+func (in *Pooler) SetInheritedData(obj *metav1.ObjectMeta) {
+	if in.Spec.InheritedMetadata == nil {
+		return
+	}
+
+	utils.InheritAnnotations(obj, nil, in.Spec.InheritedMetadata.Annotations, configuration.Current)
+	utils.InheritLabels(obj, nil, in.Spec.InheritedMetadata.Labels, configuration.Current)
+}
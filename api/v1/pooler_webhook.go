@@ -18,6 +18,8 @@ package v1
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +33,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
+// pgBouncerAdminDatabase is the name of the administrative console of PgBouncer
+const pgBouncerAdminDatabase = "pgbouncer"
+
 var (
 	// poolerLog is for logging in this package.
 	poolerLog = log.WithName("pooler-resource").WithValues("version", "v1")
@@ -76,6 +81,35 @@ var (
 		"tcp_user_timeout",
 		"verbose",
 	})
+
+	// pgbouncerConnectionLimitParameters are the PgBouncer parameters limiting
+	// the number of connections, which must be non-negative integers
+	pgbouncerConnectionLimitParameters = stringset.From([]string{
+		"default_pool_size",
+		"max_client_conn",
+		"max_db_connections",
+		"max_prepared_statements",
+		"max_user_connections",
+		"min_pool_size",
+		"reserve_pool_size",
+	})
+
+	// pgbouncerTimeoutParameters are the PgBouncer parameters expressed in
+	// seconds, which must be non-negative numbers
+	pgbouncerTimeoutParameters = stringset.From([]string{
+		"autodb_idle_timeout",
+		"client_idle_timeout",
+		"client_login_timeout",
+		"idle_transaction_timeout",
+		"query_timeout",
+		"query_wait_timeout",
+		"reserve_pool_timeout",
+		"server_check_delay",
+		"server_connect_timeout",
+		"server_idle_timeout",
+		"server_lifetime",
+		"server_login_retry",
+	})
 )
 
 // SetupWebhookWithManager setup the webhook inside the controller manager
@@ -163,6 +197,14 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 				"", "must specify an existing auth query secret when providing an auth query secret"))
 	}
 
+	if r.Spec.PgBouncer != nil && r.Spec.PgBouncer.AuthDBName == pgBouncerAdminDatabase {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "pgbouncer", "authDBName"),
+				r.Spec.PgBouncer.AuthDBName, "the PgBouncer administrative database cannot be used "+
+					"to run the authentication query"))
+	}
+
 	if r.Spec.PgBouncer != nil && len(r.Spec.PgBouncer.Parameters) > 0 {
 		result = append(result, r.validatePgbouncerGenericParameters()...)
 	}
@@ -184,6 +226,19 @@ func (r *Pooler) validateCluster() field.ErrorList {
 				field.NewPath("metadata", "name"),
 				r.Name, "the pooler resource cannot have the same name of a cluster"))
 	}
+	for _, suffix := range []string{
+		ServiceReadWriteSuffix,
+		ServiceReadOnlySuffix,
+		ServiceReadSuffix,
+		ServiceAnySuffix,
+	} {
+		if r.Spec.Cluster.Name != "" && r.Name == r.Spec.Cluster.Name+suffix {
+			result = append(result,
+				field.Invalid(
+					field.NewPath("metadata", "name"),
+					r.Name, "the pooler resource cannot have the same name of a service of the cluster"))
+		}
+	}
 	return result
 }
 
@@ -199,13 +254,45 @@ func (r *Pooler) Validate() (allErrs field.ErrorList) {
 func (r *Pooler) validatePgbouncerGenericParameters() field.ErrorList {
 	var result field.ErrorList
 
-	for param := range r.Spec.PgBouncer.Parameters {
+	for param, value := range r.Spec.PgBouncer.Parameters {
 		if !AllowedPgbouncerGenericConfigurationParameters.Has(param) {
 			result = append(result,
 				field.Invalid(
 					field.NewPath("spec", "cluster", "parameters"),
 					param, "Invalid or reserved parameter"))
+			continue
+		}
+
+		if message := validatePgbouncerParameterValue(param, value); message != "" {
+			result = append(result,
+				field.Invalid(
+					field.NewPath("spec", "pgbouncer", "parameters").Key(param),
+					value, message))
 		}
 	}
 	return result
 }
+
+// validatePgbouncerParameterValue validates the value of the PgBouncer
+// parameters limiting the connections and terminating the idle ones,
+// returning a description of the problem, if any
+func validatePgbouncerParameterValue(param, value string) string {
+	switch {
+	case pgbouncerConnectionLimitParameters.Has(param):
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return "must be a non-negative integer"
+		}
+		if param == "max_client_conn" && limit == 0 {
+			return "must be greater than zero"
+		}
+
+	case pgbouncerTimeoutParameters.Has(param):
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+			return "must be a non-negative number of seconds"
+		}
+	}
+
+	return ""
+}
@@ -98,6 +98,23 @@ var _ = Describe("Pooler validation", func() {
 		Expect(pooler.validateCluster()).NotTo(BeEmpty())
 	})
 
+	It("doesn't allow to have a pooler with the same name of a service of the cluster", func() {
+		pooler := Pooler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-rw",
+			},
+			Spec: PoolerSpec{
+				Cluster: LocalObjectReference{
+					Name: "test",
+				},
+			},
+		}
+		Expect(pooler.validateCluster()).NotTo(BeEmpty())
+
+		pooler.Name = "test-pooler-rw"
+		Expect(pooler.validateCluster()).To(BeEmpty())
+	})
+
 	It("doesn't complain when specifying a cluster name", func() {
 		pooler := Pooler{
 			Spec: PoolerSpec{
@@ -128,4 +145,69 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("doesn't allow running the authentication query in the administrative database", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					AuthDBName: "pgbouncer",
+				},
+			},
+		}
+		Expect(pooler.validatePgBouncer()).NotTo(BeEmpty())
+
+		pooler.Spec.PgBouncer.AuthDBName = "app"
+		Expect(pooler.validatePgBouncer()).To(BeEmpty())
+	})
+
+	It("does complain when given an authentication parameter", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Parameters: map[string]string{"auth_type": "trust"},
+				},
+			},
+		}
+		Expect(pooler.validatePgbouncerGenericParameters()).NotTo(BeEmpty())
+	})
+
+	It("validates the values of the connection limits and of the timeouts", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Parameters: map[string]string{
+						"max_client_conn":     "1000",
+						"default_pool_size":   "0",
+						"server_idle_timeout": "600",
+						"client_idle_timeout": "0.5",
+					},
+				},
+			},
+		}
+		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
+
+		pooler.Spec.PgBouncer.Parameters = map[string]string{
+			"max_client_conn":          "0",
+			"max_db_connections":       "-1",
+			"default_pool_size":        "ten",
+			"server_idle_timeout":      "10m",
+			"idle_transaction_timeout": "-5",
+		}
+		result := pooler.validatePgbouncerGenericParameters()
+		Expect(result).To(HaveLen(5))
+		for _, err := range result {
+			Expect(err.Field).To(HavePrefix("spec.pgbouncer.parameters["))
+		}
+	})
+
+	It("doesn't validate the values of the other parameters", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					Parameters: map[string]string{"server_check_query": "select 1"},
+				},
+			},
+		}
+		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
+	})
 })
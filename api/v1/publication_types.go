@@ -0,0 +1,186 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// PublicationReclaimPolicy describes a policy for end-of-life maintenance of publications.
+// +enum
+type PublicationReclaimPolicy string
+
+const (
+	// PublicationReclaimDelete means the publication will be dropped from its PostgreSQL
+	// Cluster on release from its claim.
+	PublicationReclaimDelete PublicationReclaimPolicy = "delete"
+
+	// PublicationReclaimRetain means the publication will be left in its current phase for manual
+	// reclamation by the administrator. The default policy is Retain.
+	PublicationReclaimRetain PublicationReclaimPolicy = "retain"
+)
+
+// PublicationFinalizerName is the name of the finalizer used to drop the
+// publication when the Publication object is deleted with the `delete` reclaim policy
+const PublicationFinalizerName = utils.MetadataNamespace + "/deletePublication"
+
+// PublicationOperation is a DML operation that can be published
+// +kubebuilder:validation:Enum=insert;update;delete;truncate
+type PublicationOperation string
+
+const (
+	// PublicationOperationInsert publishes the INSERT operations
+	PublicationOperationInsert PublicationOperation = "insert"
+
+	// PublicationOperationUpdate publishes the UPDATE operations
+	PublicationOperationUpdate PublicationOperation = "update"
+
+	// PublicationOperationDelete publishes the DELETE operations
+	PublicationOperationDelete PublicationOperation = "delete"
+
+	// PublicationOperationTruncate publishes the TRUNCATE operations
+	PublicationOperationTruncate PublicationOperation = "truncate"
+)
+
+// PublicationSpec is the specification of a PostgreSQL publication
+type PublicationSpec struct {
+	// The corresponding cluster
+	ClusterRef corev1.LocalObjectReference `json:"cluster"`
+
+	// The name of the publication inside PostgreSQL
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name"`
+
+	// The name of the database where the publication will be installed in
+	// the "publisher" cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="dbname is immutable"
+	DBName string `json:"dbname"`
+
+	// Target of the publication as expected by PostgreSQL `CREATE PUBLICATION` command
+	Target PublicationTarget `json:"target"`
+
+	// The list of operations to be published, defaults to every one of them
+	// +optional
+	Publish []PublicationOperation `json:"publish,omitempty"`
+
+	// The policy for end-of-life maintenance of this publication.
+	// Only when set to `delete` the publication is dropped from PostgreSQL
+	// when this object is removed.
+	// +kubebuilder:validation:Enum=delete;retain
+	// +kubebuilder:default:=retain
+	// +optional
+	ReclaimPolicy PublicationReclaimPolicy `json:"publicationReclaimPolicy,omitempty"`
+}
+
+// PublicationTarget is what this publication should publish
+// +kubebuilder:validation:XValidation:rule="(has(self.allTables) && self.allTables) != (size(self.objects) > 0)",message="allTables and objects are mutually exclusive, and one of them is required"
+type PublicationTarget struct {
+	// Publish all the tables in the database, including the ones
+	// created in the future. Corresponds to `FOR ALL TABLES`.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="allTables is immutable"
+	// +optional
+	AllTables bool `json:"allTables,omitempty"`
+
+	// The list of tables and schemas to be published
+	// +kubebuilder:default:={}
+	// +optional
+	Objects []PublicationTargetObject `json:"objects,omitempty"`
+}
+
+// PublicationTargetObject is an object to publish
+// +kubebuilder:validation:XValidation:rule="(size(self.tablesInSchema) > 0) != has(self.table)",message="tablesInSchema and table are mutually exclusive, and one of them is required"
+type PublicationTargetObject struct {
+	// Publish all the tables in this schema, including the ones created
+	// in the future. Corresponds to `TABLES IN SCHEMA` and requires
+	// PostgreSQL 15 or later.
+	// +kubebuilder:default:=""
+	// +optional
+	TablesInSchema string `json:"tablesInSchema,omitempty"`
+
+	// Publish a single table. Corresponds to `TABLE`.
+	// +optional
+	Table *PublicationTargetTable `json:"table,omitempty"`
+}
+
+// PublicationTargetTable is a table to publish
+type PublicationTargetTable struct {
+	// The schema of the table, defaults to the search path
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The table name
+	Name string `json:"name"`
+}
+
+// PublicationStatus defines the observed state of Publication
+type PublicationStatus struct {
+	// A sequence number representing the latest
+	// desired state that was synchronized
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Ready is true if the publication was reconciled correctly
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Error is the reconciliation error message
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="PG Name",type="string",JSONPath=".spec.name"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.error",description="Latest error message"
+
+// Publication is the Schema for the publications API
+type Publication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired Publication.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec PublicationSpec `json:"spec"`
+	// Most recently observed status of the Publication. This data may not be up to
+	// date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status PublicationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PublicationList contains a list of Publication
+type PublicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Publication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Publication{}, &PublicationList{})
+}
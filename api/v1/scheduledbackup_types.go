@@ -81,6 +81,15 @@ type ScheduledBackupSpec struct {
 	// Overrides the default settings specified in the cluster '.backup.volumeSnapshot.onlineConfiguration' stanza
 	// +optional
 	OnlineConfiguration *OnlineConfiguration `json:"onlineConfiguration,omitempty"`
+
+	// Whether to request an immediate checkpoint when starting a backup with
+	// the `barmanObjectStore` method. An immediate checkpoint reduces the WAL
+	// to be replayed when restoring the backup, at the cost of an I/O spike
+	// on the instance being backed up.
+	// Overrides the default setting specified in the cluster field
+	// '.spec.backup.barmanObjectStore.data.immediateCheckpoint'
+	// +optional
+	ImmediateCheckpoint *bool `json:"immediateCheckpoint,omitempty"`
 }
 
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
@@ -184,6 +193,7 @@ func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 			Method:              scheduledBackup.Spec.Method,
 			Online:              scheduledBackup.Spec.Online,
 			OnlineConfiguration: scheduledBackup.Spec.OnlineConfiguration,
+			ImmediateCheckpoint: scheduledBackup.Spec.ImmediateCheckpoint,
 			PluginConfiguration: scheduledBackup.Spec.PluginConfiguration,
 		},
 	}
@@ -20,6 +20,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -90,4 +91,24 @@ var _ = Describe("Scheduled backup", func() {
 		Expect(result).To(HaveLen(1))
 		Expect(result[0].Field).To(Equal("spec.onlineConfiguration"))
 	})
+
+	It("complains if immediateCheckpoint is set on a volume snapshot backup", func() {
+		utils.SetVolumeSnapshot(true)
+		scheduledBackup := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Method:              BackupMethodVolumeSnapshot,
+				ImmediateCheckpoint: ptr.To(true),
+				Schedule:            "* * * * * *",
+			},
+		}
+		result := scheduledBackup.validate()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.immediateCheckpoint"))
+	})
+
+	It("propagates the immediate checkpoint setting to the backups", func() {
+		scheduledBackup.Spec.ImmediateCheckpoint = ptr.To(true)
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup.Spec.ImmediateCheckpoint).To(Equal(ptr.To(true)))
+	})
 })
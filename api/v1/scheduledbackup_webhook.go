@@ -118,5 +118,14 @@ func (r *ScheduledBackup) validate() field.ErrorList {
 		))
 	}
 
+	if (r.Spec.Method == BackupMethodVolumeSnapshot || r.Spec.Method == BackupMethodPlugin) &&
+		r.Spec.ImmediateCheckpoint != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "immediateCheckpoint"),
+			r.Spec.ImmediateCheckpoint,
+			"ImmediateCheckpoint parameter can be specified only if the method is barmanObjectStore",
+		))
+	}
+
 	return result
 }
@@ -40,6 +40,7 @@ func createFakeCluster(name string) *Cluster {
 	cluster.Spec.MinSyncReplicas = 1
 	cluster.Status = ClusterStatus{
 		CurrentPrimary: primaryPod,
+		InstanceNames:  []string{primaryPod, fmt.Sprintf("%s-2", name), fmt.Sprintf("%s-3", name)},
 		InstancesStatus: map[utils.PodStatus][]string{
 			utils.PodHealthy: {primaryPod, fmt.Sprintf("%s-2", name), fmt.Sprintf("%s-3", name)},
 			utils.PodFailed:  {},
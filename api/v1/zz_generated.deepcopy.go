@@ -27,8 +27,24 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdaptiveArchiveTimeoutConfiguration) DeepCopyInto(out *AdaptiveArchiveTimeoutConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptiveArchiveTimeoutConfiguration.
+func (in *AdaptiveArchiveTimeoutConfiguration) DeepCopy() *AdaptiveArchiveTimeoutConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AdaptiveArchiveTimeoutConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AffinityConfiguration) DeepCopyInto(out *AffinityConfiguration) {
 	*out = *in
@@ -128,6 +144,21 @@ func (in *AzureCredentials) DeepCopy() *AzureCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutovacuumConfiguration) DeepCopyInto(out *AutovacuumConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutovacuumConfiguration.
+func (in *AutovacuumConfiguration) DeepCopy() *AutovacuumConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AutovacuumConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
@@ -155,6 +186,44 @@ func (in *Backup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupArchiveConfiguration) DeepCopyInto(out *BackupArchiveConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArchiveConfiguration.
+func (in *BackupArchiveConfiguration) DeepCopy() *BackupArchiveConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupArchiveConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupArchiveTierExportStatus) DeepCopyInto(out *BackupArchiveTierExportStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArchiveTierExportStatus.
+func (in *BackupArchiveTierExportStatus) DeepCopy() *BackupArchiveTierExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupArchiveTierExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 	*out = *in
@@ -168,6 +237,43 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalWalDestinations != nil {
+		in, out := &in.AdditionalWalDestinations, &out.AdditionalWalDestinations
+		*out = make([]WalArchiveDestination, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WalArchiveQuorum != nil {
+		in, out := &in.WalArchiveQuorum, &out.WalArchiveQuorum
+		*out = new(int)
+		**out = **in
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WalArchiveCheck != nil {
+		in, out := &in.WalArchiveCheck, &out.WalArchiveCheck
+		*out = new(WalArchiveCheckConfiguration)
+		**out = **in
+	}
+	if in.WalArchiveBacklog != nil {
+		in, out := &in.WalArchiveBacklog, &out.WalArchiveBacklog
+		*out = new(WalArchiveBacklogConfiguration)
+		**out = **in
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooksConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(BackupArchiveConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -180,6 +286,55 @@ func (in *BackupConfiguration) DeepCopy() *BackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHook) DeepCopyInto(out *BackupHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHook.
+func (in *BackupHook) DeepCopy() *BackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHooksConfiguration) DeepCopyInto(out *BackupHooksConfiguration) {
+	*out = *in
+	if in.PreBackup != nil {
+		in, out := &in.PreBackup, &out.PreBackup
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostBackup != nil {
+		in, out := &in.PostBackup, &out.PostBackup
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHooksConfiguration.
+func (in *BackupHooksConfiguration) DeepCopy() *BackupHooksConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHooksConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupList) DeepCopyInto(out *BackupList) {
 	*out = *in
@@ -309,6 +464,11 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		*out = new(OnlineConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImmediateCheckpoint != nil {
+		in, out := &in.ImmediateCheckpoint, &out.ImmediateCheckpoint
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
@@ -359,6 +519,16 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArchiveTierExport != nil {
+		in, out := &in.ArchiveTierExport, &out.ArchiveTierExport
+		*out = new(BackupArchiveTierExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -371,6 +541,49 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationConfiguration) DeepCopyInto(out *BackupVerificationConfiguration) {
+	*out = *in
+	if in.ScratchSizeLimit != nil {
+		in, out := &in.ScratchSizeLimit, &out.ScratchSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationConfiguration.
+func (in *BackupVerificationConfiguration) DeepCopy() *BackupVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationStatus) DeepCopyInto(out *BackupVerificationStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationStatus.
+func (in *BackupVerificationStatus) DeepCopy() *BackupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BarmanCredentials) DeepCopyInto(out *BarmanCredentials) {
 	*out = *in
@@ -516,7 +729,17 @@ func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 	}
 	if in.PostInitApplicationSQLRefs != nil {
 		in, out := &in.PostInitApplicationSQLRefs, &out.PostInitApplicationSQLRefs
-		*out = new(PostInitApplicationSQLRefs)
+		*out = new(SQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostInitSQLRefs != nil {
+		in, out := &in.PostInitSQLRefs, &out.PostInitSQLRefs
+		*out = new(SQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostInitTemplateSQLRefs != nil {
+		in, out := &in.PostInitTemplateSQLRefs, &out.PostInitTemplateSQLRefs
+		*out = new(SQLRefs)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -574,6 +797,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.PostRecovery != nil {
+		in, out := &in.PostRecovery, &out.PostRecovery
+		*out = new(PostRecoveryConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapRecovery.
@@ -601,6 +829,46 @@ func (in *CatalogImage) DeepCopy() *CatalogImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateAuthenticationConfiguration) DeepCopyInto(out *CertificateAuthenticationConfiguration) {
+	*out = *in
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]CertificateMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalClientCASecrets != nil {
+		in, out := &in.AdditionalClientCASecrets, &out.AdditionalClientCASecrets
+		*out = make([]LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateAuthenticationConfiguration.
+func (in *CertificateAuthenticationConfiguration) DeepCopy() *CertificateAuthenticationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateAuthenticationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMapping) DeepCopyInto(out *CertificateMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMapping.
+func (in *CertificateMapping) DeepCopy() *CertificateMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -632,6 +900,10 @@ func (in *CertificatesStatus) DeepCopyInto(out *CertificatesStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesStatus.
@@ -644,6 +916,21 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckpointConfiguration) DeepCopyInto(out *CheckpointConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointConfiguration.
+func (in *CheckpointConfiguration) DeepCopy() *CheckpointConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -774,6 +1061,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(ImageCatalogRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicaAutoscaling != nil {
+		in, out := &in.ReplicaAutoscaling, &out.ReplicaAutoscaling
+		*out = new(ReplicaAutoscalingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
@@ -800,6 +1092,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotationConfiguration)
+		**out = **in
+	}
 	if in.Certificates != nil {
 		in, out := &in.Certificates, &out.Certificates
 		*out = new(CertificatesConfiguration)
@@ -821,16 +1118,56 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WalRestoreCache != nil {
+		in, out := &in.WalRestoreCache, &out.WalRestoreCache
+		*out = new(WalRestoreCacheConfiguration)
+		**out = **in
+	}
 	if in.EphemeralVolumeSource != nil {
 		in, out := &in.EphemeralVolumeSource, &out.EphemeralVolumeSource
 		*out = new(corev1.EphemeralVolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FailoverCooldown != nil {
+		in, out := &in.FailoverCooldown, &out.FailoverCooldown
+		*out = new(FailoverCooldownConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverPriority != nil {
+		in, out := &in.FailoverPriority, &out.FailoverPriority
+		*out = new(FailoverPriorityConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quarantine != nil {
+		in, out := &in.Quarantine, &out.Quarantine
+		*out = new(QuarantineConfiguration)
+		**out = **in
+	}
+	if in.ReplicaReadiness != nil {
+		in, out := &in.ReplicaReadiness, &out.ReplicaReadiness
+		*out = new(ReplicaReadinessConfiguration)
+		**out = **in
+	}
+	if in.ScheduledMaintenance != nil {
+		in, out := &in.ScheduledMaintenance, &out.ScheduledMaintenance
+		*out = new(ScheduledMaintenanceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicyConfiguration)
+		**out = **in
+	}
 	if in.LivenessProbeTimeout != nil {
 		in, out := &in.LivenessProbeTimeout, &out.LivenessProbeTimeout
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
 	if in.TopologySpreadConstraints != nil {
 		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
@@ -839,12 +1176,29 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RolePlacement != nil {
+		in, out := &in.RolePlacement, &out.RolePlacement
+		*out = new(RolePlacementConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.EphemeralVolumesSizeLimit != nil {
 		in, out := &in.EphemeralVolumesSizeLimit, &out.EphemeralVolumesSizeLimit
 		*out = new(EphemeralVolumesSizeLimitConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ParameterChangeRestart != nil {
+		in, out := &in.ParameterChangeRestart, &out.ParameterChangeRestart
+		*out = new(ParameterChangeRestartConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
@@ -908,6 +1262,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Plugins != nil {
 		in, out := &in.Plugins, &out.Plugins
 		*out = make(PluginConfigurationList, len(*in))
@@ -959,6 +1318,16 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]TablespaceState, len(*in))
 		copy(*out, *in)
 	}
+	if in.LogicalReplicationSlotsStatus != nil {
+		in, out := &in.LogicalReplicationSlotsStatus, &out.LogicalReplicationSlotsStatus
+		*out = make([]LogicalReplicationSlotStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutExemptRoles != nil {
+		in, out := &in.TimeoutExemptRoles, &out.TimeoutExemptRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -988,6 +1357,10 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	in.SecretsResourceVersion.DeepCopyInto(&out.SecretsResourceVersion)
 	in.ConfigMapResourceVersion.DeepCopyInto(&out.ConfigMapResourceVersion)
 	in.Certificates.DeepCopyInto(&out.Certificates)
+	if in.LastPasswordRotationTime != nil {
+		in, out := &in.LastPasswordRotationTime, &out.LastPasswordRotationTime
+		*out = (*in).DeepCopy()
+	}
 	if in.FirstRecoverabilityPointByMethod != nil {
 		in, out := &in.FirstRecoverabilityPointByMethod, &out.FirstRecoverabilityPointByMethod
 		*out = make(map[BackupMethod]metav1.Time, len(*in))
@@ -1024,6 +1397,11 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PGDataImageInfo != nil {
+		in, out := &in.PGDataImageInfo, &out.PGDataImageInfo
+		*out = new(ImageInfo)
+		**out = **in
+	}
 	if in.PluginStatus != nil {
 		in, out := &in.PluginStatus, &out.PluginStatus
 		*out = make([]PluginStatus, len(*in))
@@ -1032,6 +1410,63 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		}
 	}
 	out.SwitchReplicaClusterStatus = in.SwitchReplicaClusterStatus
+	if in.LastBackupVerification != nil {
+		in, out := &in.LastBackupVerification, &out.LastBackupVerification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WalArchiveCheck != nil {
+		in, out := &in.WalArchiveCheck, &out.WalArchiveCheck
+		*out = new(WalArchiveCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuarantinedInstances != nil {
+		in, out := &in.QuarantinedInstances, &out.QuarantinedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScheduledMaintenance != nil {
+		in, out := &in.ScheduledMaintenance, &out.ScheduledMaintenance
+		*out = new(ScheduledMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatStatementsReset != nil {
+		in, out := &in.StatStatementsReset, &out.StatStatementsReset
+		*out = new(StatStatementsResetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingRestart != nil {
+		in, out := &in.PendingRestart, &out.PendingRestart
+		*out = new(PendingRestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecoveredAlterSystemSettings != nil {
+		in, out := &in.RecoveredAlterSystemSettings, &out.RecoveredAlterSystemSettings
+		*out = new(RecoveredAlterSystemSettingsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RewindStatus != nil {
+		in, out := &in.RewindStatus, &out.RewindStatus
+		*out = make(map[PodName]InstanceRewindStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LogicalImport != nil {
+		in, out := &in.LogicalImport, &out.LogicalImport
+		*out = new(LogicalImportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaAutoscaling != nil {
+		in, out := &in.ReplicaAutoscaling, &out.ReplicaAutoscaling
+		*out = new(ReplicaAutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Switchover != nil {
+		in, out := &in.Switchover, &out.Switchover
+		*out = new(SwitchoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -1090,6 +1525,11 @@ func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxBandwidth != nil {
+		in, out := &in.MaxBandwidth, &out.MaxBandwidth
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	if in.AdditionalCommandArgs != nil {
 		in, out := &in.AdditionalCommandArgs, &out.AdditionalCommandArgs
 		*out = make([]string, len(*in))
@@ -1135,6 +1575,65 @@ func (in *DataSource) DeepCopy() *DataSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Database) DeepCopyInto(out *Database) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Database.
+func (in *Database) DeepCopy() *Database {
+	if in == nil {
+		return nil
+	}
+	out := new(Database)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Database) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseList) DeepCopyInto(out *DatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Database, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseList.
+func (in *DatabaseList) DeepCopy() *DatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseRoleRef) DeepCopyInto(out *DatabaseRoleRef) {
 	*out = *in
@@ -1150,6 +1649,124 @@ func (in *DatabaseRoleRef) DeepCopy() *DatabaseRoleRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.IsTemplate != nil {
+		in, out := &in.IsTemplate, &out.IsTemplate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowConnections != nil {
+		in, out := &in.AllowConnections, &out.AllowConnections
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]ServerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
+func (in *DatabaseSpec) DeepCopy() *DatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseStatus) DeepCopyInto(out *DatabaseStatus) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]ServerStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseStatus.
+func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DelayedReplicasConfiguration) DeepCopyInto(out *DelayedReplicasConfiguration) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DelayedReplicasConfiguration.
+func (in *DelayedReplicasConfiguration) DeepCopy() *DelayedReplicasConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DelayedReplicasConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicyConfiguration) DeepCopyInto(out *DeletionPolicyConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicyConfiguration.
+func (in *DeletionPolicyConfiguration) DeepCopy() *DeletionPolicyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskUsageConfiguration) DeepCopyInto(out *DiskUsageConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskUsageConfiguration.
+func (in *DiskUsageConfiguration) DeepCopy() *DiskUsageConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskUsageConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -1204,6 +1821,36 @@ func (in *EphemeralVolumesSizeLimitConfiguration) DeepCopy() *EphemeralVolumesSi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionSpec) DeepCopyInto(out *ExtensionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionSpec.
+func (in *ExtensionSpec) DeepCopy() *ExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionStatus) DeepCopyInto(out *ExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionStatus.
+func (in *ExtensionStatus) DeepCopy() *ExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -1239,6 +1886,11 @@ func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ArchiveObjectStore != nil {
+		in, out := &in.ArchiveObjectStore, &out.ArchiveObjectStore
+		*out = new(BarmanObjectStoreConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
@@ -1251,6 +1903,51 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverCooldownConfiguration) DeepCopyInto(out *FailoverCooldownConfiguration) {
+	*out = *in
+	if in.AllowOnHardFailure != nil {
+		in, out := &in.AllowOnHardFailure, &out.AllowOnHardFailure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverCooldownConfiguration.
+func (in *FailoverCooldownConfiguration) DeepCopy() *FailoverCooldownConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverCooldownConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverPriorityConfiguration) DeepCopyInto(out *FailoverPriorityConfiguration) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]InstanceFailoverPriority, len(*in))
+		copy(*out, *in)
+	}
+	if in.LSNTolerance != nil {
+		in, out := &in.LSNTolerance, &out.LSNTolerance
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverPriorityConfiguration.
+func (in *FailoverPriorityConfiguration) DeepCopy() *FailoverPriorityConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverPriorityConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
 	*out = *in
@@ -1365,10 +2062,25 @@ func (in *ImageCatalogSpec) DeepCopy() *ImageCatalogSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageInfo) DeepCopyInto(out *ImageInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageInfo.
+func (in *ImageInfo) DeepCopy() *ImageInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Import) DeepCopyInto(out *Import) {
 	*out = *in
-	out.Source = in.Source
+	in.Source.DeepCopyInto(&out.Source)
 	if in.Databases != nil {
 		in, out := &in.Databases, &out.Databases
 		*out = make([]string, len(*in))
@@ -1397,71 +2109,172 @@ func (in *Import) DeepCopy() *Import {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportSource) DeepCopyInto(out *ImportSource) {
+func (in *ImportFromBackup) DeepCopyInto(out *ImportFromBackup) {
 	*out = *in
+	if in.RecoveryTarget != nil {
+		in, out := &in.RecoveryTarget, &out.RecoveryTarget
+		*out = new(RecoveryTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
-func (in *ImportSource) DeepCopy() *ImportSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportFromBackup.
+func (in *ImportFromBackup) DeepCopy() *ImportFromBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportSource)
+	out := new(ImportFromBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+func (in *ImportSource) DeepCopyInto(out *ImportSource) {
 	*out = *in
+	if in.FromBackup != nil {
+		in, out := &in.FromBackup, &out.FromBackup
+		*out = new(ImportFromBackup)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
-func (in *InstanceID) DeepCopy() *InstanceID {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
+func (in *ImportSource) DeepCopy() *ImportSource {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceID)
+	out := new(ImportSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+func (in *InstanceFailoverPriority) DeepCopyInto(out *InstanceFailoverPriority) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
-func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceFailoverPriority.
+func (in *InstanceFailoverPriority) DeepCopy() *InstanceFailoverPriority {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceReportedState)
+	out := new(InstanceFailoverPriority)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+func (in *InstanceID) DeepCopyInto(out *InstanceID) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
-func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
+func (in *InstanceID) DeepCopy() *InstanceID {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindAsAuth)
+	out := new(InstanceID)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+func (in *InstancePlacement) DeepCopyInto(out *InstancePlacement) {
 	*out = *in
-	if in.BindPassword != nil {
-		in, out := &in.BindPassword, &out.BindPassword
-		*out = new(corev1.SecretKeySelector)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(corev1.NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancePlacement.
+func (in *InstancePlacement) DeepCopy() *InstancePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(InstancePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
+func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceReportedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRewindStatus) DeepCopyInto(out *InstanceRewindStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRewindStatus.
+func (in *InstanceRewindStatus) DeepCopy() *InstanceRewindStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRewindStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
+func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindAsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+	*out = *in
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -1516,6 +2329,88 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalImportStatus) DeepCopyInto(out *LogicalImportStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImportedDatabases != nil {
+		in, out := &in.ImportedDatabases, &out.ImportedDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StepStartedAt != nil {
+		in, out := &in.StepStartedAt, &out.StepStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalImportStatus.
+func (in *LogicalImportStatus) DeepCopy() *LogicalImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalReplicationSlotConfiguration) DeepCopyInto(out *LogicalReplicationSlotConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalReplicationSlotConfiguration.
+func (in *LogicalReplicationSlotConfiguration) DeepCopy() *LogicalReplicationSlotConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalReplicationSlotConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalReplicationSlotStatus) DeepCopyInto(out *LogicalReplicationSlotStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalReplicationSlotStatus.
+func (in *LogicalReplicationSlotStatus) DeepCopy() *LogicalReplicationSlotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalReplicationSlotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowConfiguration) DeepCopyInto(out *MaintenanceWindowConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowConfiguration.
+func (in *MaintenanceWindowConfiguration) DeepCopy() *MaintenanceWindowConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
 	*out = *in
@@ -1543,6 +2438,53 @@ func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedExtensionConfiguration) DeepCopyInto(out *ManagedExtensionConfiguration) {
+	*out = *in
+	if in.Recipe != nil {
+		in, out := &in.Recipe, &out.Recipe
+		*out = new(ManagedExtensionRecipe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedExtensionConfiguration.
+func (in *ManagedExtensionConfiguration) DeepCopy() *ManagedExtensionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedExtensionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedExtensionRecipe) DeepCopyInto(out *ManagedExtensionRecipe) {
+	*out = *in
+	if in.SharedPreloadLibraries != nil {
+		in, out := &in.SharedPreloadLibraries, &out.SharedPreloadLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedExtensionRecipe.
+func (in *ManagedExtensionRecipe) DeepCopy() *ManagedExtensionRecipe {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedExtensionRecipe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedRoles) DeepCopyInto(out *ManagedRoles) {
 	*out = *in
@@ -1640,6 +2582,21 @@ func (in *ManagedServices) DeepCopy() *ManagedServices {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryConfiguration) DeepCopyInto(out *MemoryConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryConfiguration.
+func (in *MemoryConfiguration) DeepCopy() *MemoryConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -1701,6 +2658,26 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(PrometheusRuleConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TableStatistics != nil {
+		in, out := &in.TableStatistics, &out.TableStatistics
+		*out = new(TableStatisticsConfiguration)
+		**out = **in
+	}
+	if in.TransactionIDWraparound != nil {
+		in, out := &in.TransactionIDWraparound, &out.TransactionIDWraparound
+		*out = new(TransactionIDWraparoundConfiguration)
+		**out = **in
+	}
+	if in.DiskUsage != nil {
+		in, out := &in.DiskUsage, &out.DiskUsage
+		*out = new(DiskUsageConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -1758,6 +2735,56 @@ func (in *OnlineConfiguration) DeepCopy() *OnlineConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptionSpec) DeepCopyInto(out *OptionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionSpec.
+func (in *OptionSpec) DeepCopy() *OptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterChangeRestartConfiguration) DeepCopyInto(out *ParameterChangeRestartConfiguration) {
+	*out = *in
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterChangeRestartConfiguration.
+func (in *ParameterChangeRestartConfiguration) DeepCopy() *ParameterChangeRestartConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterChangeRestartConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationConfiguration) DeepCopyInto(out *PasswordRotationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationConfiguration.
+func (in *PasswordRotationConfiguration) DeepCopy() *PasswordRotationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PasswordState) DeepCopyInto(out *PasswordState) {
 	*out = *in
@@ -1773,6 +2800,35 @@ func (in *PasswordState) DeepCopy() *PasswordState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingRestartStatus) DeepCopyInto(out *PendingRestartStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingRestartStatus.
+func (in *PendingRestartStatus) DeepCopy() *PendingRestartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingRestartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -1925,41 +2981,91 @@ func (in *PluginStatus) DeepCopy() *PluginStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
+func (in *PodDisruptionBudgetConfiguration) DeepCopyInto(out *PodDisruptionBudgetConfiguration) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Primary != nil {
+		in, out := &in.Primary, &out.Primary
+		*out = new(PodDisruptionBudgetPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(PodDisruptionBudgetPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateSpec.
-func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfiguration.
+func (in *PodDisruptionBudgetConfiguration) DeepCopy() *PodDisruptionBudgetConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PodTemplateSpec)
+	out := new(PodDisruptionBudgetConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in PodTopologyLabels) DeepCopyInto(out *PodTopologyLabels) {
-	{
-		in := &in
-		*out = make(PodTopologyLabels, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+func (in *PodDisruptionBudgetPolicy) DeepCopyInto(out *PodDisruptionBudgetPolicy) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTopologyLabels.
-func (in PodTopologyLabels) DeepCopy() PodTopologyLabels {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetPolicy.
+func (in *PodDisruptionBudgetPolicy) DeepCopy() *PodDisruptionBudgetPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(PodTopologyLabels)
+	out := new(PodDisruptionBudgetPolicy)
 	in.DeepCopyInto(out)
-	return *out
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTemplateSpec.
+func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in PodTopologyLabels) DeepCopyInto(out *PodTopologyLabels) {
+	{
+		in := &in
+		*out = make(PodTopologyLabels, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodTopologyLabels.
+func (in PodTopologyLabels) DeepCopy() PodTopologyLabels {
+	if in == nil {
+		return nil
+	}
+	out := new(PodTopologyLabels)
+	in.DeepCopyInto(out)
+	return *out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -2006,31 +3112,454 @@ func (in *PoolerIntegrations) DeepCopy() *PoolerIntegrations {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerList) DeepCopyInto(out *PoolerList) {
+func (in *PoolerList) DeepCopyInto(out *PoolerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pooler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
+func (in *PoolerList) DeepCopy() *PoolerList {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PoolerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
+	*out = *in
+	if in.PodMonitorMetricRelabelConfigs != nil {
+		in, out := &in.PodMonitorMetricRelabelConfigs, &out.PodMonitorMetricRelabelConfigs
+		*out = make([]monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodMonitorRelabelConfigs != nil {
+		in, out := &in.PodMonitorRelabelConfigs, &out.PodMonitorRelabelConfigs
+		*out = make([]monitoringv1.RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
+func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerMonitoringConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
+	*out = *in
+	out.ServerTLS = in.ServerTLS
+	out.ServerCA = in.ServerCA
+	out.ClientCA = in.ClientCA
+	if in.PgBouncerSecrets != nil {
+		in, out := &in.PgBouncerSecrets, &out.PgBouncerSecrets
+		*out = new(PgBouncerSecrets)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
+func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PgBouncer != nil {
+		in, out := &in.PgBouncer, &out.PgBouncer
+		*out = new(PgBouncerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentStrategy != nil {
+		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(PoolerMonitoringConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTemplate != nil {
+		in, out := &in.ServiceTemplate, &out.ServiceTemplate
+		*out = new(ServiceTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InheritedMetadata != nil {
+		in, out := &in.InheritedMetadata, &out.InheritedMetadata
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
+func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(PoolerSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
+func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostRecoveryConfiguration) DeepCopyInto(out *PostRecoveryConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostRecoveryConfiguration.
+func (in *PostRecoveryConfiguration) DeepCopy() *PostRecoveryConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostRecoveryConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedUnknownParameters != nil {
+		in, out := &in.AllowedUnknownParameters, &out.AllowedUnknownParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgHBA != nil {
+		in, out := &in.PgHBA, &out.PgHBA
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgHBAPrepend != nil {
+		in, out := &in.PgHBAPrepend, &out.PgHBAPrepend
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateAuthentication != nil {
+		in, out := &in.CertificateAuthentication, &out.CertificateAuthentication
+		*out = new(CertificateAuthenticationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
+	if in.HotStandbyFeedbackInstances != nil {
+		in, out := &in.HotStandbyFeedbackInstances, &out.HotStandbyFeedbackInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DelayedReplicas != nil {
+		in, out := &in.DelayedReplicas, &out.DelayedReplicas
+		*out = new(DelayedReplicasConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalLibraries != nil {
+		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ManagedExtensionConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdaptiveArchiveTimeout != nil {
+		in, out := &in.AdaptiveArchiveTimeout, &out.AdaptiveArchiveTimeout
+		*out = new(AdaptiveArchiveTimeoutConfiguration)
+		**out = **in
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(TimeoutsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SlowQueryLogging != nil {
+		in, out := &in.SlowQueryLogging, &out.SlowQueryLogging
+		*out = new(SlowQueryLoggingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatStatements != nil {
+		in, out := &in.StatStatements, &out.StatStatements
+		*out = new(StatStatementsConfiguration)
+		**out = **in
+	}
+	if in.Autovacuum != nil {
+		in, out := &in.Autovacuum, &out.Autovacuum
+		*out = new(AutovacuumConfiguration)
+		**out = **in
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(CheckpointConfiguration)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(MemoryConfiguration)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PostgresTLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
+func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresTLSConfiguration) DeepCopyInto(out *PostgresTLSConfiguration) {
+	*out = *in
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresTLSConfiguration.
+func (in *PostgresTLSConfiguration) DeepCopy() *PostgresTLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresTLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeConfiguration) DeepCopyInto(out *ProbeConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeConfiguration.
+func (in *ProbeConfiguration) DeepCopy() *ProbeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesConfiguration) DeepCopyInto(out *ProbesConfiguration) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfiguration.
+func (in *ProbesConfiguration) DeepCopy() *ProbesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusAlertConfiguration) DeepCopyInto(out *PrometheusAlertConfiguration) {
+	*out = *in
+	if in.For != nil {
+		in, out := &in.For, &out.For
+		*out = new(monitoringv1.Duration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAlertConfiguration.
+func (in *PrometheusAlertConfiguration) DeepCopy() *PrometheusAlertConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAlertConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleConfiguration) DeepCopyInto(out *PrometheusRuleConfiguration) {
+	*out = *in
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make([]PrometheusAlertConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleConfiguration.
+func (in *PrometheusRuleConfiguration) DeepCopy() *PrometheusRuleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Publication) DeepCopyInto(out *Publication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Publication.
+func (in *Publication) DeepCopy() *Publication {
+	if in == nil {
+		return nil
+	}
+	out := new(Publication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Publication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationList) DeepCopyInto(out *PublicationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Pooler, len(*in))
+		*out = make([]Publication, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
-func (in *PoolerList) DeepCopy() *PoolerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationList.
+func (in *PublicationList) DeepCopy() *PublicationList {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerList)
+	out := new(PublicationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PoolerList) DeepCopyObject() runtime.Object {
+func (in *PublicationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2038,150 +3567,116 @@ func (in *PoolerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
+func (in *PublicationSpec) DeepCopyInto(out *PublicationSpec) {
 	*out = *in
-	if in.PodMonitorMetricRelabelConfigs != nil {
-		in, out := &in.PodMonitorMetricRelabelConfigs, &out.PodMonitorMetricRelabelConfigs
-		*out = make([]monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.ClusterRef = in.ClusterRef
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = make([]PublicationOperation, len(*in))
+		copy(*out, *in)
 	}
-	if in.PodMonitorRelabelConfigs != nil {
-		in, out := &in.PodMonitorRelabelConfigs, &out.PodMonitorRelabelConfigs
-		*out = make([]monitoringv1.RelabelConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationSpec.
+func (in *PublicationSpec) DeepCopy() *PublicationSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(PublicationSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
-func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfiguration {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationStatus) DeepCopyInto(out *PublicationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationStatus.
+func (in *PublicationStatus) DeepCopy() *PublicationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerMonitoringConfiguration)
+	out := new(PublicationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerSecrets) DeepCopyInto(out *PoolerSecrets) {
+func (in *PublicationTarget) DeepCopyInto(out *PublicationTarget) {
 	*out = *in
-	out.ServerTLS = in.ServerTLS
-	out.ServerCA = in.ServerCA
-	out.ClientCA = in.ClientCA
-	if in.PgBouncerSecrets != nil {
-		in, out := &in.PgBouncerSecrets, &out.PgBouncerSecrets
-		*out = new(PgBouncerSecrets)
-		**out = **in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]PublicationTargetObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSecrets.
-func (in *PoolerSecrets) DeepCopy() *PoolerSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTarget.
+func (in *PublicationTarget) DeepCopy() *PublicationTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerSecrets)
+	out := new(PublicationTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
+func (in *PublicationTargetObject) DeepCopyInto(out *PublicationTargetObject) {
 	*out = *in
-	out.Cluster = in.Cluster
-	if in.Instances != nil {
-		in, out := &in.Instances, &out.Instances
-		*out = new(int32)
+	if in.Table != nil {
+		in, out := &in.Table, &out.Table
+		*out = new(PublicationTargetTable)
 		**out = **in
 	}
-	if in.Template != nil {
-		in, out := &in.Template, &out.Template
-		*out = new(PodTemplateSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PgBouncer != nil {
-		in, out := &in.PgBouncer, &out.PgBouncer
-		*out = new(PgBouncerSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DeploymentStrategy != nil {
-		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
-		*out = new(appsv1.DeploymentStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Monitoring != nil {
-		in, out := &in.Monitoring, &out.Monitoring
-		*out = new(PoolerMonitoringConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ServiceTemplate != nil {
-		in, out := &in.ServiceTemplate, &out.ServiceTemplate
-		*out = new(ServiceTemplateSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
-func (in *PoolerSpec) DeepCopy() *PoolerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTargetObject.
+func (in *PublicationTargetObject) DeepCopy() *PublicationTargetObject {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerSpec)
+	out := new(PublicationTargetObject)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PoolerStatus) DeepCopyInto(out *PoolerStatus) {
+func (in *PublicationTargetTable) DeepCopyInto(out *PublicationTargetTable) {
 	*out = *in
-	if in.Secrets != nil {
-		in, out := &in.Secrets, &out.Secrets
-		*out = new(PoolerSecrets)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerStatus.
-func (in *PoolerStatus) DeepCopy() *PoolerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationTargetTable.
+func (in *PublicationTargetTable) DeepCopy() *PublicationTargetTable {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerStatus)
+	out := new(PublicationTargetTable)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
+func (in *QuarantineConfiguration) DeepCopyInto(out *QuarantineConfiguration) {
 	*out = *in
-	if in.SecretRefs != nil {
-		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]SecretKeySelector, len(*in))
-		copy(*out, *in)
-	}
-	if in.ConfigMapRefs != nil {
-		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]ConfigMapKeySelector, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
-func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuarantineConfiguration.
+func (in *QuarantineConfiguration) DeepCopy() *QuarantineConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PostInitApplicationSQLRefs)
+	out := new(QuarantineConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
+func (in *RecoveredAlterSystemSettingsStatus) DeepCopyInto(out *RecoveredAlterSystemSettingsStatus) {
 	*out = *in
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
@@ -2190,35 +3685,19 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 			(*out)[key] = val
 		}
 	}
-	if in.PgHBA != nil {
-		in, out := &in.PgHBA, &out.PgHBA
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PgIdent != nil {
-		in, out := &in.PgIdent, &out.PgIdent
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
-	if in.AdditionalLibraries != nil {
-		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
+	if in.DivergingParameters != nil {
+		in, out := &in.DivergingParameters, &out.DivergingParameters
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.LDAP != nil {
-		in, out := &in.LDAP, &out.LDAP
-		*out = new(LDAPConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
-func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveredAlterSystemSettingsStatus.
+func (in *RecoveredAlterSystemSettingsStatus) DeepCopy() *RecoveredAlterSystemSettingsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresConfiguration)
+	out := new(RecoveredAlterSystemSettingsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2248,6 +3727,58 @@ func (in *RecoveryTarget) DeepCopy() *RecoveryTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaAutoscalingConfiguration) DeepCopyInto(out *ReplicaAutoscalingConfiguration) {
+	*out = *in
+	if in.ScaleDownDelay != nil {
+		in, out := &in.ScaleDownDelay, &out.ScaleDownDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaAutoscalingConfiguration.
+func (in *ReplicaAutoscalingConfiguration) DeepCopy() *ReplicaAutoscalingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaAutoscalingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaAutoscalingStatus) DeepCopyInto(out *ReplicaAutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ScaleDownPendingSince != nil {
+		in, out := &in.ScaleDownPendingSince, &out.ScaleDownPendingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.DrainStartedAt != nil {
+		in, out := &in.DrainStartedAt, &out.DrainStartedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaAutoscalingStatus.
+func (in *ReplicaAutoscalingStatus) DeepCopy() *ReplicaAutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaAutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicaClusterConfiguration) DeepCopyInto(out *ReplicaClusterConfiguration) {
 	*out = *in
@@ -2268,6 +3799,21 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaReadinessConfiguration) DeepCopyInto(out *ReplicaReadinessConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaReadinessConfiguration.
+func (in *ReplicaReadinessConfiguration) DeepCopy() *ReplicaReadinessConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaReadinessConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
@@ -2281,6 +3827,11 @@ func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfi
 		*out = new(SynchronizeReplicasConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Logical != nil {
+		in, out := &in.Logical, &out.Logical
+		*out = make([]LogicalReplicationSlotConfiguration, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSlotsConfiguration.
@@ -2347,6 +3898,31 @@ func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolePlacementConfiguration) DeepCopyInto(out *RolePlacementConfiguration) {
+	*out = *in
+	if in.Primary != nil {
+		in, out := &in.Primary, &out.Primary
+		*out = new(InstancePlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replica != nil {
+		in, out := &in.Replica, &out.Replica
+		*out = new(InstancePlacement)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolePlacementConfiguration.
+func (in *RolePlacementConfiguration) DeepCopy() *RolePlacementConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RolePlacementConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
@@ -2398,6 +3974,31 @@ func (in *S3Credentials) DeepCopy() *S3Credentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLRefs) DeepCopyInto(out *SQLRefs) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLRefs.
+func (in *SQLRefs) DeepCopy() *SQLRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	*out = *in
@@ -2481,46 +4082,99 @@ func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
 		*out = new(bool)
 		**out = **in
 	}
-	if in.OnlineConfiguration != nil {
-		in, out := &in.OnlineConfiguration, &out.OnlineConfiguration
-		*out = new(OnlineConfiguration)
-		(*in).DeepCopyInto(*out)
+	if in.OnlineConfiguration != nil {
+		in, out := &in.OnlineConfiguration, &out.OnlineConfiguration
+		*out = new(OnlineConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImmediateCheckpoint != nil {
+		in, out := &in.ImmediateCheckpoint, &out.ImmediateCheckpoint
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
+func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
+func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledMaintenanceConfiguration) DeepCopyInto(out *ScheduledMaintenanceConfiguration) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
-func (in *ScheduledBackupSpec) DeepCopy() *ScheduledBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledMaintenanceConfiguration.
+func (in *ScheduledMaintenanceConfiguration) DeepCopy() *ScheduledMaintenanceConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupSpec)
+	out := new(ScheduledMaintenanceConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
+func (in *ScheduledMaintenanceStatus) DeepCopyInto(out *ScheduledMaintenanceStatus) {
 	*out = *in
-	if in.LastCheckTime != nil {
-		in, out := &in.LastCheckTime, &out.LastCheckTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastScheduleTime != nil {
-		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
 		*out = (*in).DeepCopy()
 	}
-	if in.NextScheduleTime != nil {
-		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
 		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
-func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledMaintenanceStatus.
+func (in *ScheduledMaintenanceStatus) DeepCopy() *ScheduledMaintenanceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledBackupStatus)
+	out := new(ScheduledMaintenanceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2592,6 +4246,48 @@ func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]OptionSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserMappings != nil {
+		in, out := &in.UserMappings, &out.UserMappings
+		*out = make([]UserMappingSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
+func (in *ServerSpec) DeepCopy() *ServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerStatus) DeepCopyInto(out *ServerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerStatus.
+func (in *ServerStatus) DeepCopy() *ServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
 	*out = *in
@@ -2625,6 +4321,65 @@ func (in *ServiceTemplateSpec) DeepCopy() *ServiceTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlowQueryLoggingConfiguration) DeepCopyInto(out *SlowQueryLoggingConfiguration) {
+	*out = *in
+	if in.LockWaits != nil {
+		in, out := &in.LockWaits, &out.LockWaits
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TempFilesMinSize != nil {
+		in, out := &in.TempFilesMinSize, &out.TempFilesMinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlowQueryLoggingConfiguration.
+func (in *SlowQueryLoggingConfiguration) DeepCopy() *SlowQueryLoggingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SlowQueryLoggingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatStatementsConfiguration) DeepCopyInto(out *StatStatementsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatStatementsConfiguration.
+func (in *StatStatementsConfiguration) DeepCopy() *StatStatementsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StatStatementsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatStatementsResetStatus) DeepCopyInto(out *StatStatementsResetStatus) {
+	*out = *in
+	if in.ResetAt != nil {
+		in, out := &in.ResetAt, &out.ResetAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatStatementsResetStatus.
+func (in *StatStatementsResetStatus) DeepCopy() *StatStatementsResetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StatStatementsResetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -2670,6 +4425,33 @@ func (in *SwitchReplicaClusterStatus) DeepCopy() *SwitchReplicaClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverStatus) DeepCopyInto(out *SwitchoverStatus) {
+	*out = *in
+	if in.PhaseStartedAt != nil {
+		in, out := &in.PhaseStartedAt, &out.PhaseStartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverStatus.
+func (in *SwitchoverStatus) DeepCopy() *SwitchoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
@@ -2716,6 +4498,21 @@ func (in *SynchronizeReplicasConfiguration) DeepCopy() *SynchronizeReplicasConfi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TableStatisticsConfiguration) DeepCopyInto(out *TableStatisticsConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TableStatisticsConfiguration.
+func (in *TableStatisticsConfiguration) DeepCopy() *TableStatisticsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TableStatisticsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
 	*out = *in
@@ -2748,6 +4545,26 @@ func (in *TablespaceState) DeepCopy() *TablespaceState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutsConfiguration) DeepCopyInto(out *TimeoutsConfiguration) {
+	*out = *in
+	if in.ExemptRoles != nil {
+		in, out := &in.ExemptRoles, &out.ExemptRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutsConfiguration.
+func (in *TimeoutsConfiguration) DeepCopy() *TimeoutsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -2781,6 +4598,46 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransactionIDWraparoundConfiguration) DeepCopyInto(out *TransactionIDWraparoundConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransactionIDWraparoundConfiguration.
+func (in *TransactionIDWraparoundConfiguration) DeepCopy() *TransactionIDWraparoundConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TransactionIDWraparoundConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserMappingSpec) DeepCopyInto(out *UserMappingSpec) {
+	*out = *in
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]OptionSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserMappingSpec.
+func (in *UserMappingSpec) DeepCopy() *UserMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSnapshotConfiguration) DeepCopyInto(out *VolumeSnapshotConfiguration) {
 	*out = *in
@@ -2823,9 +4680,84 @@ func (in *VolumeSnapshotConfiguration) DeepCopy() *VolumeSnapshotConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalArchiveBacklogConfiguration) DeepCopyInto(out *WalArchiveBacklogConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalArchiveBacklogConfiguration.
+func (in *WalArchiveBacklogConfiguration) DeepCopy() *WalArchiveBacklogConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalArchiveBacklogConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalArchiveCheckConfiguration) DeepCopyInto(out *WalArchiveCheckConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalArchiveCheckConfiguration.
+func (in *WalArchiveCheckConfiguration) DeepCopy() *WalArchiveCheckConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalArchiveCheckConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalArchiveCheckStatus) DeepCopyInto(out *WalArchiveCheckStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalArchiveCheckStatus.
+func (in *WalArchiveCheckStatus) DeepCopy() *WalArchiveCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WalArchiveCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalArchiveDestination) DeepCopyInto(out *WalArchiveDestination) {
+	*out = *in
+	in.BarmanObjectStoreConfiguration.DeepCopyInto(&out.BarmanObjectStoreConfiguration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalArchiveDestination.
+func (in *WalArchiveDestination) DeepCopy() *WalArchiveDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(WalArchiveDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WalBackupConfiguration) DeepCopyInto(out *WalBackupConfiguration) {
 	*out = *in
+	if in.CompressionLevel != nil {
+		in, out := &in.CompressionLevel, &out.CompressionLevel
+		*out = new(int)
+		**out = **in
+	}
+	if in.RestoreMaxParallel != nil {
+		in, out := &in.RestoreMaxParallel, &out.RestoreMaxParallel
+		*out = new(WalRestoreMaxParallelConfiguration)
+		**out = **in
+	}
 	if in.AdditionalCommandArgs != nil {
 		in, out := &in.AdditionalCommandArgs, &out.AdditionalCommandArgs
 		*out = make([]string, len(*in))
@@ -2842,3 +4774,33 @@ func (in *WalBackupConfiguration) DeepCopy() *WalBackupConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalRestoreCacheConfiguration) DeepCopyInto(out *WalRestoreCacheConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalRestoreCacheConfiguration.
+func (in *WalRestoreCacheConfiguration) DeepCopy() *WalRestoreCacheConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalRestoreCacheConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalRestoreMaxParallelConfiguration) DeepCopyInto(out *WalRestoreMaxParallelConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalRestoreMaxParallelConfiguration.
+func (in *WalRestoreMaxParallelConfiguration) DeepCopy() *WalRestoreMaxParallelConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalRestoreMaxParallelConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -27,8 +27,11 @@ import (
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/backup"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/backupcatalog"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/certificate"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/clone"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/failovercandidates"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/hibernate"
@@ -42,10 +45,14 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/psql"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/reload"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/replaceinstance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/rotatecertificates"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/snapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/switchover"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/walstatus"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 
@@ -84,8 +91,11 @@ func main() {
 
 	subcommands := []*cobra.Command{
 		backup.NewCmd(),
+		backupcatalog.NewCmd(),
 		certificate.NewCmd(),
+		clone.NewCmd(),
 		destroy.NewCmd(),
+		failovercandidates.NewCmd(),
 		fence.NewCmd(),
 		fio.NewCmd(),
 		hibernate.NewCmd(),
@@ -98,12 +108,16 @@ func main() {
 		psql.NewCmd(),
 		publication.NewCmd(),
 		reload.NewCmd(),
+		replaceinstance.NewCmd(),
 		report.NewCmd(),
 		restart.NewCmd(),
+		rotatecertificates.NewCmd(),
 		snapshot.NewCmd(),
 		status.NewCmd(),
 		subscription.NewCmd(),
+		switchover.NewCmd(),
 		versions.NewCmd(),
+		walstatus.NewCmd(),
 	}
 
 	for _, cmd := range subcommands {
@@ -29,6 +29,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restoresnapshot"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/upgrade"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/verifybackup"
 )
 
 // NewCmd creates the "instance" command
@@ -48,6 +50,8 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(pgbasebackup.NewCmd())
 	cmd.AddCommand(restore.NewCmd())
 	cmd.AddCommand(restoresnapshot.NewCmd())
+	cmd.AddCommand(verifybackup.NewCmd())
+	cmd.AddCommand(upgrade.NewCmd())
 
 	return cmd
 }
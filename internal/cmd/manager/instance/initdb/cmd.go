@@ -47,6 +47,9 @@ func NewCmd() *cobra.Command {
 	var postInitApplicationSQLStr string
 	var postInitTemplateSQLStr string
 	var postInitApplicationSQLRefsFolder string
+	var postInitSQLRefsFolder string
+	var postInitTemplateSQLRefsFolder string
+	var timezone string
 
 	cmd := &cobra.Command{
 		Use: "init [options]",
@@ -99,6 +102,9 @@ func NewCmd() *cobra.Command {
 				// if the value to postInitApplicationSQLRefsFolder is empty,
 				// bootstrap will do nothing for post init application SQL refs.
 				PostInitApplicationSQLRefsFolder: postInitApplicationSQLRefsFolder,
+				PostInitSQLRefsFolder:            postInitSQLRefsFolder,
+				PostInitTemplateSQLRefsFolder:    postInitTemplateSQLRefsFolder,
+				Timezone:                         timezone,
 			}
 
 			return initSubCommand(ctx, info)
@@ -136,6 +142,14 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&postInitApplicationSQLRefsFolder, "post-init-application-sql-refs-folder",
 		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
 			"against the application database immediately after its creation")
+	cmd.Flags().StringVar(&postInitSQLRefsFolder, "post-init-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"against the postgres database to configure the new instance")
+	cmd.Flags().StringVar(&postInitTemplateSQLRefsFolder, "post-init-template-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"against the template1 database to configure the new instance")
+	cmd.Flags().StringVar(&timezone, "timezone", "",
+		"The default time zone of the new instance, like Europe/Rome")
 
 	return cmd
 }
@@ -146,6 +160,11 @@ func initSubCommand(ctx context.Context, info postgres.InitInfo) error {
 		return err
 	}
 
+	if err := info.VerifyLocalesAndTimezone(); err != nil {
+		log.Error(err, "Error while verifying the locales and the time zone")
+		return err
+	}
+
 	err = info.Bootstrap(ctx)
 	if err != nil {
 		log.Error(err, "Error while bootstrapping data directory")
@@ -38,11 +38,17 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/diskusage"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/externalservers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/maintenance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/statstatements"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/tablespaces"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/walarchivecheck"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
@@ -153,15 +159,28 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 						instance.Namespace: {},
 					},
 				},
+				&apiv1.Database{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
+				&apiv1.Publication{}: {
+					Namespaces: map[string]cache.Config{
+						instance.Namespace: {},
+					},
+				},
 			},
 		},
 		// We don't need a cache for secrets and configmap, as all reloads
-		// should be driven by changes in the Cluster we are watching
+		// should be driven by changes in the Cluster we are watching.
+		// The endpoints are only read while draining the connections
+		// to the former primary
 		Client: client.Options{
 			Cache: &client.CacheOptions{
 				DisableFor: []client.Object{
 					&corev1.Secret{},
 					&corev1.ConfigMap{},
+					&corev1.Endpoints{},
 				},
 			},
 		},
@@ -249,6 +268,42 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	walArchiveChecker := walarchivecheck.NewChecker(instance, reconciler.GetClient())
+	if err = mgr.Add(walArchiveChecker); err != nil {
+		setupLog.Error(err, "unable to create WAL archive checker")
+		return err
+	}
+
+	walPendingDrainer := walarchive.NewPendingDrainer(instance.PgData)
+	if err = mgr.Add(walPendingDrainer); err != nil {
+		setupLog.Error(err, "unable to create WAL archive pending drainer")
+		return err
+	}
+
+	walRestoreCacheCleaner := walrestore.NewSharedCacheCleaner()
+	if err = mgr.Add(walRestoreCacheCleaner); err != nil {
+		setupLog.Error(err, "unable to create WAL restore cache cleaner")
+		return err
+	}
+
+	maintenanceScheduler := maintenance.NewScheduler(instance, reconciler.GetClient())
+	if err = mgr.Add(maintenanceScheduler); err != nil {
+		setupLog.Error(err, "unable to create maintenance scheduler")
+		return err
+	}
+
+	statStatementsResetter := statstatements.NewResetter(instance, reconciler.GetClient())
+	if err = mgr.Add(statStatementsResetter); err != nil {
+		setupLog.Error(err, "unable to create pg_stat_statements resetter")
+		return err
+	}
+
+	diskUsageMonitor := diskusage.NewMonitor(instance)
+	if err = mgr.Add(diskUsageMonitor); err != nil {
+		setupLog.Error(err, "unable to create disk usage monitor")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
@@ -282,6 +337,20 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	setupLog.Info("starting database reconciler")
+	if err := controller.NewDatabaseReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create database reconciler")
+		return err
+	}
+
+	setupLog.Info("starting publication reconciler")
+	if err := controller.NewPublicationReconciler(instance, mgr.GetClient()).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create publication reconciler")
+		return err
+	}
+
 	setupLog.Info("starting external server manager")
 	if err := externalservers.NewReconciler(instance, mgr.GetClient()).
 		SetupWithManager(mgr); err != nil {
@@ -54,6 +54,13 @@ func (i *PostgresLifecycle) runPostgresAndWait(ctx context.Context) <-chan error
 		postgresContext, postgresContextCancel := context.WithCancel(ctx)
 		defer postgresContextCancel()
 
+		// An interrupted major upgrade, aborted by restoring the image of
+		// the previous major version, leaves the original data directory
+		// unusable until it is rolled back
+		if err := postgres.RollbackInterruptedMajorUpgrade(postgresContext, i.instance.PgData); err != nil {
+			return err
+		}
+
 		// Before starting the postmaster, we ensure we've the correct
 		// permissions and user maps to start it.
 		err := i.instance.VerifyPgDataCoherence(postgresContext)
@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade implements the "instance upgrade" subcommand of the operator
+package upgrade
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// NewCmd creates the "upgrade" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the data directory to a new PostgreSQL major version",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return fmt.Errorf("missing subcommand")
+		},
+	}
+
+	cmd.AddCommand(newPrepareCmd())
+	cmd.AddCommand(newExecuteCmd())
+
+	return cmd
+}
+
+// newPrepareCmd creates the "upgrade prepare" subcommand, which is
+// executed in the image of the previous major version
+func newPrepareCmd() *cobra.Command {
+	var destination string
+
+	cmd := &cobra.Command{
+		Use:           "prepare [flags]",
+		Short:         "Copy the PostgreSQL binaries of this image to be used by pg_upgrade",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return postgres.CopyPostgresBinaries(cmd.Context(), destination)
+		},
+	}
+
+	cmd.Flags().StringVar(&destination, "destination", postgresSpec.UpgradeOldBinariesDirectory,
+		"The directory where the binaries are copied")
+
+	return cmd
+}
+
+// newExecuteCmd creates the "upgrade execute" subcommand, which is
+// executed in the image of the new major version
+func newExecuteCmd() *cobra.Command {
+	var initDBFlagsString string
+	var oldBinaries string
+	var pgData string
+	var pgWal string
+
+	cmd := &cobra.Command{
+		Use:           "execute [flags]",
+		Short:         "Upgrade the data directory via pg_upgrade",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			initDBFlags, err := shellquote.Split(initDBFlagsString)
+			if err != nil {
+				log.Error(err, "Error while parsing initdb flags")
+				return err
+			}
+
+			info := postgres.InitInfo{
+				PgData:        pgData,
+				PgWal:         pgWal,
+				InitDBOptions: initDBFlags,
+			}
+
+			return info.UpgradeMajorVersion(ctx, oldBinaries)
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&initDBFlagsString, "initdb-flags", "", "The list of flags to be passed "+
+		"to initdb while creating the upgraded data directory")
+	cmd.Flags().StringVar(&oldBinaries, "old-binaries", postgresSpec.UpgradeOldBinariesDirectory,
+		"The directory where the binaries of the previous major version have been copied")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be upgraded")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The PGWAL of the data directory to be upgraded")
+
+	return cmd
+}
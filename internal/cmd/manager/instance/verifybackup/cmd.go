@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package verifybackup implements the "instance verifybackup" subcommand of the operator
+package verifybackup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/linkerd"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// NewCmd creates the "verifybackup" subcommand
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+	var pgData string
+	var pgWal string
+
+	cmd := &cobra.Command{
+		Use:           "verifybackup [flags]",
+		Short:         "Restore the latest base backup in a scratch data directory to verify it",
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return management.WaitKubernetesAPIServer(cmd.Context(), ctrl.ObjectKey{
+				Name:      clusterName,
+				Namespace: namespace,
+			})
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			info := postgres.InitInfo{
+				ClusterName: clusterName,
+				Namespace:   namespace,
+				PgData:      pgData,
+				PgWal:       pgWal,
+			}
+
+			if err := info.VerifyPGData(); err != nil {
+				return err
+			}
+
+			typedClient, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				return err
+			}
+
+			return info.VerifyBackup(ctx, typedClient)
+		},
+		PostRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := istio.TryInvokeQuitEndpoint(cmd.Context()); err != nil {
+				return err
+			}
+
+			return linkerd.TryInvokeShutdownEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"cluster whose backups should be verified")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and the Pod in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The scratch PGDATA where the backup is restored")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "The scratch PGWAL where the backup is restored")
+
+	return cmd
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backupcatalog implement the backup-catalog command
+package backupcatalog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	var refresh bool
+
+	cmd := cobra.Command{
+		Use:   "backup-catalog",
+		Short: "Prints the catalog of the base backups stored in the object store, as JSON",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return run(refresh)
+		},
+	}
+
+	cmd.Flags().BoolVar(&refresh, "refresh", false,
+		"Read the catalog from the object store, ignoring the cached one")
+
+	return &cmd
+}
+
+func run(refresh bool) error {
+	catalogURL := url.Local(url.PathPgBackupCatalog, url.LocalPort)
+	if refresh {
+		catalogURL += "?refresh=true"
+	}
+
+	resp, err := http.Get(catalogURL)
+	if err != nil {
+		log.Error(err, "Error while requesting the backup catalog")
+		return err
+	}
+
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			log.Error(err, "Can't close the connection",
+				"catalogURL", catalogURL,
+				"statusCode", resp.StatusCode,
+			)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error(err, "Error while reading the backup catalog response body",
+			"catalogURL", catalogURL,
+			"statusCode", resp.StatusCode,
+		)
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error while requesting the backup catalog (status code %v): %s",
+			resp.StatusCode, string(body))
+	}
+
+	_, err = os.Stdout.Write(body)
+	return err
+}
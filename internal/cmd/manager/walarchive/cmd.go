@@ -25,14 +25,15 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
@@ -87,6 +88,22 @@ func NewCmd() *cobra.Command {
 			}
 
 			err = run(ctx, podName, pgData, cluster, args)
+			var partialErr *partialArchivingError
+			if errors.As(err, &partialErr) {
+				// PostgreSQL can proceed, but we need to report the
+				// destinations which are not working
+				contextLog.Warning("WAL file archived, but not on every WAL destination", "err", err)
+				condition := metav1.Condition{
+					Type:    string(apiv1.ConditionContinuousArchiving),
+					Status:  metav1.ConditionTrue,
+					Reason:  string(apiv1.ConditionReasonContinuousArchivingDegraded),
+					Message: err.Error(),
+				}
+				if errCond := conditions.Patch(ctx, typedClient, cluster, &condition); errCond != nil {
+					log.Error(errCond, "Error changing wal archiving condition (wal archiving degraded)")
+				}
+				return nil
+			}
 			if err != nil {
 				if errors.Is(err, errSwitchoverInProgress) {
 					contextLog.Warning("Refusing to archive WALs until the switchover is not completed",
@@ -175,35 +192,52 @@ func run(
 		return nil
 	}
 
-	// Get environment from cache
-	env, err := cacheClient.GetEnv(cache.WALArchiveKey)
-	if err != nil {
-		return fmt.Errorf("failed to get envs: %w", err)
-	}
-
 	maxParallel := 1
 	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
 		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
 	}
 
-	// Create the archiver
-	var walArchiver *archiver.WALArchiver
-	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, pgData); err != nil {
-		return fmt.Errorf("while creating the archiver: %w", err)
+	// Create the archivers, one for each WAL destination
+	destinations, err := newWALDestinations(ctx, cluster, pgData, cacheClient.GetEnv)
+	if err != nil {
+		return err
+	}
+	quorum := cluster.Spec.Backup.GetWalArchiveQuorum()
+
+	// Step 1: report the WAL destinations still having WAL files to be
+	// archived, as they failed before. They are archived in the background
+	// by the instance manager, not to slow down the archiving
+	failures := make(map[string]error)
+	for _, destination := range destinations {
+		pendingFiles, err := destination.getPendingFiles()
+		if err != nil {
+			return err
+		}
+		if len(pendingFiles) > 0 {
+			failures[destination.name] = fmt.Errorf("%d WAL files still to be archived", len(pendingFiles))
+		}
 	}
 
-	// Step 1: check if this WAL file has not been already archived
-	var isDeletedFromSpool bool
-	isDeletedFromSpool, err = walArchiver.DeleteFromSpool(walName)
-	if err != nil {
-		return fmt.Errorf("while testing the existence of the WAL file in the spool directory: %w", err)
+	// Step 2: check if this WAL file has not been already archived
+	archivedCount := 0
+	var targetDestinations []*walDestination
+	for _, destination := range destinations {
+		isDeletedFromSpool, err := destination.archiver.DeleteFromSpool(walName)
+		if err != nil {
+			return fmt.Errorf("while testing the existence of the WAL file in the spool directory: %w", err)
+		}
+		if isDeletedFromSpool {
+			archivedCount++
+			continue
+		}
+		targetDestinations = append(targetDestinations, destination)
 	}
-	if isDeletedFromSpool {
+	if len(targetDestinations) == 0 {
 		contextLog.Info("Archived WAL file (parallel)",
 			"walName", walName,
 			"currentPrimary", cluster.Status.CurrentPrimary,
 			"targetPrimary", cluster.Status.TargetPrimary)
-		return nil
+		return newPartialArchivingError(failures)
 	}
 
 	// Step 3: gather the WAL files names to archive
@@ -211,33 +245,75 @@ func run(
 
 	// Step 4: Check if the archive location is safe to perform archiving
 	if utils.IsEmptyWalArchiveCheckEnabled(&cluster.ObjectMeta) {
-		if err := checkWalArchive(ctx, cluster, walArchiver, pgData); err != nil {
-			return err
+		for _, destination := range targetDestinations {
+			if err := checkWalArchive(ctx, cluster, destination, pgData); err != nil {
+				return err
+			}
 		}
 	}
 
-	options, err := barmanCloudWalArchiveOptions(cluster, cluster.Name)
-	if err != nil {
-		return err
-	}
-
-	// Step 5: archive the WAL files in parallel
+	// Step 5: archive the WAL files in parallel, on every WAL destination
 	uploadStartTime := time.Now()
-	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
-	if len(walStatus) > 1 {
+	walStatuses := make([][]archiver.WALArchiverResult, len(targetDestinations))
+	var waitGroup sync.WaitGroup
+	for idx, destination := range targetDestinations {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			destinationCtx := ctx
+			if len(destinations) > 1 {
+				destinationCtx = log.IntoContext(ctx, contextLog.WithValues("destination", destination.name))
+			}
+			walStatuses[idx] = destination.archiver.ArchiveList(destinationCtx, walFilesList, destination.options)
+		}()
+	}
+	waitGroup.Wait()
+	if len(walFilesList) > 1 || len(targetDestinations) > 1 {
 		contextLog.Info("Completed archive command (parallel)",
-			"walsCount", len(walStatus),
+			"walsCount", len(walFilesList),
+			"destinationsCount", len(targetDestinations),
 			"startTime", startTime,
 			"uploadStartTime", uploadStartTime,
 			"uploadTotalTime", time.Since(uploadStartTime),
 			"totalTime", time.Since(startTime))
 	}
 
-	// We return only the first error to PostgreSQL, because the first error
-	// is the one raised by the file that PostgreSQL has requested to archive.
-	// The other errors are related to WAL files that were pre-archived as
-	// a performance optimization and are just logged
-	return walStatus[0].Err
+	// We only consider the errors of the first file, because the first
+	// error is the one raised by the file that PostgreSQL has requested
+	// to archive. The other errors are related to WAL files that were
+	// pre-archived as a performance optimization and are just logged
+	var failedDestinations []*walDestination
+	var walErrors []error
+	for idx, destination := range targetDestinations {
+		if err := walStatuses[idx][0].Err; err != nil {
+			failures[destination.name] = err
+			failedDestinations = append(failedDestinations, destination)
+			walErrors = append(walErrors, err)
+			continue
+		}
+		archivedCount++
+	}
+
+	// With a single WAL destination, we report its error as is
+	if len(destinations) == 1 && len(walErrors) == 1 {
+		return walErrors[0]
+	}
+
+	if archivedCount < quorum {
+		return fmt.Errorf("WAL file archived on %d WAL destinations, while %d are required: %w",
+			archivedCount, quorum, errors.Join(walErrors...))
+	}
+
+	// PostgreSQL will consider this WAL file as archived, so we need to
+	// keep a copy of it for the destinations where it failed
+	for _, destination := range failedDestinations {
+		if err := destination.keepPending(path.Join(pgData, walName)); err != nil {
+			return fmt.Errorf("while keeping the WAL file to be archived on WAL destination %s: %w",
+				destination.name, err)
+		}
+	}
+
+	return newPartialArchivingError(failures)
 }
 
 // archiveWALViaPlugins requests every capable plugin to archive the passed
@@ -334,25 +410,35 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 }
 
 func barmanCloudWalArchiveOptions(
-	cluster *apiv1.Cluster,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
 	capabilities, err := barmanCapabilities.CurrentCapabilities()
 	if err != nil {
 		return nil, err
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
 
 	var options []string
 	if configuration.Wal != nil {
-		if configuration.Wal.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
-			return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
+		if !capabilities.SupportsCompression(configuration.Wal.Compression) {
+			return nil, fmt.Errorf("%v compression is not supported in Barman %v",
+				configuration.Wal.Compression, capabilities.Version)
 		}
 		if len(configuration.Wal.Compression) != 0 {
 			options = append(
 				options,
 				fmt.Sprintf("--%v", configuration.Wal.Compression))
 		}
+		if configuration.Wal.CompressionLevel != nil {
+			if !capabilities.HasCompressionLevel {
+				return nil, fmt.Errorf("the compression level is not supported in Barman %v",
+					capabilities.Version)
+			}
+			options = append(
+				options,
+				"--compression-level",
+				strconv.Itoa(*configuration.Wal.CompressionLevel))
+		}
 		if len(configuration.Wal.Encryption) != 0 {
 			options = append(
 				options,
@@ -402,10 +488,11 @@ func barmanCloudWalArchiveOptions(
 
 func checkWalArchive(ctx context.Context,
 	cluster *apiv1.Cluster,
-	walArchiver *archiver.WALArchiver,
+	destination *walDestination,
 	pgData string,
 ) error {
-	checkWalOptions, err := walArchiver.BarmanCloudCheckWalArchiveOptions(cluster, cluster.Name)
+	walArchiver := destination.archiver
+	checkWalOptions, err := walArchiver.BarmanCloudCheckWalArchiveOptions(destination.configuration, cluster.Name)
 	if err != nil {
 		log.Error(err, "while getting barman-cloud-wal-archive options")
 		return err
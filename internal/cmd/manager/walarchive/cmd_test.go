@@ -47,7 +47,7 @@ var _ = Describe("barmanCloudWalArchiveOptions", func() {
 	It("should generate correct arguments", func() {
 		extraOptions := []string{"--min-chunk-size=5MB", "--read-timeout=60", "-vv"}
 		cluster.Spec.Backup.BarmanObjectStore.Wal.AdditionalCommandArgs = extraOptions
-		options, err := barmanCloudWalArchiveOptions(cluster, "test-cluster")
+		options, err := barmanCloudWalArchiveOptions(cluster.Spec.Backup.BarmanObjectStore, "test-cluster")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(strings.Join(options, " ")).
 			To(
@@ -67,7 +67,7 @@ var _ = Describe("barmanCloudWalArchiveOptions", func() {
 			"aes256",
 		}
 		cluster.Spec.Backup.BarmanObjectStore.Wal.AdditionalCommandArgs = extraOptions
-		options, err := barmanCloudWalArchiveOptions(cluster, "test-cluster")
+		options, err := barmanCloudWalArchiveOptions(cluster.Spec.Backup.BarmanObjectStore, "test-cluster")
 		Expect(err).ToNot(HaveOccurred())
 
 		Expect(strings.Join(options, " ")).
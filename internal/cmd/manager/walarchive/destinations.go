@@ -0,0 +1,260 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// pendingDirectoryName is the name of the directory where we keep a copy
+	// of the WAL files that were archived on the required quorum of WAL
+	// destinations, but not on every one of them, to archive them there later
+	pendingDirectoryName = "wal-archive-pending"
+
+	// pendingFileSuffix is the suffix of a WAL file
+	// which is still being copied in the pending directory
+	pendingFileSuffix = ".tmp"
+
+	// mainDestinationName is the name used for the WAL destination
+	// defined in the barmanObjectStore section
+	mainDestinationName = "barmanObjectStore"
+)
+
+// envGetter gets the environment needed to access an object store
+type envGetter func(key string) ([]string, error)
+
+// getPendingDirectory gets the directory where the pending WAL files are
+// kept. It is in the volume of PGDATA, outside of the data directory, so
+// that the pending WAL files survive a restart of the Pod without being
+// included in the base backups
+func getPendingDirectory(pgData string) string {
+	return path.Join(path.Dir(pgData), pendingDirectoryName)
+}
+
+// walDestination is an object store where the WAL files are archived
+type walDestination struct {
+	// The name of the destination
+	name string
+
+	// The configuration of the object store
+	configuration *apiv1.BarmanObjectStoreConfiguration
+
+	// The archiver, having its own spool and environment
+	archiver *archiver.WALArchiver
+
+	// The options to be passed to barman-cloud-wal-archive
+	options []string
+
+	// The directory containing the WAL files still to be
+	// archived on this destination
+	pendingDirectory string
+}
+
+// partialArchivingError is returned when a WAL file has been archived
+// on the required quorum of WAL destinations, but at least one of them
+// is failing
+type partialArchivingError struct {
+	failures map[string]error
+}
+
+// newPartialArchivingError creates a new partialArchivingError, returning
+// nil when there are no failures
+func newPartialArchivingError(failures map[string]error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &partialArchivingError{failures: failures}
+}
+
+// Error implements the error interface
+func (e *partialArchivingError) Error() string {
+	names := make([]string, 0, len(e.failures))
+	for name := range e.failures {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	messages := make([]string, len(names))
+	for idx, name := range names {
+		messages[idx] = fmt.Sprintf("%s: %v", name, e.failures[name])
+	}
+
+	return fmt.Sprintf("WAL archiving is failing on some WAL destinations (%s)", strings.Join(messages, "; "))
+}
+
+// newWALDestinations creates the list of the WAL destinations of the cluster.
+// The first one is always the one defined in the barmanObjectStore section
+func newWALDestinations(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pgData string,
+	getEnv envGetter,
+) ([]*walDestination, error) {
+	mainDestination, err := newWALDestination(
+		ctx,
+		cluster,
+		pgData,
+		getEnv,
+		mainDestinationName,
+		cluster.Spec.Backup.BarmanObjectStore,
+		cache.WALArchiveKey,
+		SpoolDirectory,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*walDestination{mainDestination}
+	for idx := range cluster.Spec.Backup.AdditionalWalDestinations {
+		configuration := &cluster.Spec.Backup.AdditionalWalDestinations[idx]
+		destination, err := newWALDestination(
+			ctx,
+			cluster,
+			pgData,
+			getEnv,
+			configuration.Name,
+			&configuration.BarmanObjectStoreConfiguration,
+			cache.WALArchiveDestinationKey(configuration.Name),
+			SpoolDirectory+"-"+configuration.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, destination)
+	}
+
+	return result, nil
+}
+
+func newWALDestination(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pgData string,
+	getEnv envGetter,
+	name string,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	envCacheKey string,
+	spoolDirectory string,
+) (*walDestination, error) {
+	// Get environment from cache
+	env, err := getEnv(envCacheKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get envs for WAL destination %s: %w", name, err)
+	}
+
+	walArchiver, err := archiver.New(ctx, cluster, env, spoolDirectory, pgData)
+	if err != nil {
+		return nil, fmt.Errorf("while creating the archiver for WAL destination %s: %w", name, err)
+	}
+
+	options, err := barmanCloudWalArchiveOptions(configuration, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &walDestination{
+		name:             name,
+		configuration:    configuration,
+		archiver:         walArchiver,
+		options:          options,
+		pendingDirectory: path.Join(getPendingDirectory(pgData), name),
+	}, nil
+}
+
+// keepPending stores a copy of the passed WAL file, to archive it
+// on this destination later. The file is copied under a temporary name
+// and then renamed, so that it is never archived while incomplete
+func (destination *walDestination) keepPending(walPath string) error {
+	pendingDirectory := destination.pendingDirectory
+	if err := fileutils.EnsureDirectoryExists(pendingDirectory); err != nil {
+		return err
+	}
+
+	pendingPath := path.Join(pendingDirectory, path.Base(walPath))
+	if err := fileutils.CopyFile(walPath, pendingPath+pendingFileSuffix); err != nil {
+		return err
+	}
+
+	return os.Rename(pendingPath+pendingFileSuffix, pendingPath)
+}
+
+// getPendingFiles gets the names of the WAL files still pending
+// on this destination, in the order they were generated
+func (destination *walDestination) getPendingFiles() ([]string, error) {
+	entries, err := fileutils.GetDirectoryContent(destination.pendingDirectory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while reading the pending WAL files: %w", err)
+	}
+
+	walFiles := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry, pendingFileSuffix) {
+			walFiles = append(walFiles, entry)
+		}
+	}
+	slices.Sort(walFiles)
+
+	return walFiles, nil
+}
+
+// archivePending archives at most maxFiles of the WAL files still pending
+// on this destination, in the order they were generated. It stops at the
+// first failure, as it's likely that the destination is still not working.
+func (destination *walDestination) archivePending(ctx context.Context, maxFiles int) error {
+	contextLog := log.FromContext(ctx)
+	pendingDirectory := destination.pendingDirectory
+
+	walFiles, err := destination.getPendingFiles()
+	if err != nil {
+		return err
+	}
+
+	if len(walFiles) > maxFiles {
+		walFiles = walFiles[:maxFiles]
+	}
+
+	for _, walFile := range walFiles {
+		walPath := path.Join(pendingDirectory, walFile)
+		if err := destination.archiver.Archive(walPath, destination.options); err != nil {
+			return err
+		}
+		if err := fileutils.RemoveFile(walPath); err != nil {
+			return err
+		}
+		contextLog.Info("Archived pending WAL file",
+			"walName", walFile,
+			"destination", destination.name)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"errors"
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("partialArchivingError", func() {
+	It("is nil when there are no failures", func() {
+		Expect(newPartialArchivingError(nil)).To(Succeed())
+	})
+
+	It("reports every failing destination", func() {
+		err := newPartialArchivingError(map[string]error{
+			"offsite":           errors.New("timeout"),
+			mainDestinationName: errors.New("access denied"),
+		})
+		Expect(err).To(MatchError("WAL archiving is failing on some WAL destinations " +
+			"(barmanObjectStore: access denied; offsite: timeout)"))
+
+		var partialErr *partialArchivingError
+		Expect(errors.As(err, &partialErr)).To(BeTrue())
+	})
+})
+
+var _ = Describe("pending WAL files", func() {
+	var destination *walDestination
+
+	BeforeEach(func() {
+		destination = &walDestination{
+			name:             "offsite",
+			pendingDirectory: path.Join(GinkgoT().TempDir(), "offsite"),
+		}
+	})
+
+	It("does nothing when there are no pending WAL files", func(ctx SpecContext) {
+		Expect(destination.archivePending(ctx, 1)).To(Succeed())
+	})
+
+	It("keeps a copy of the WAL files", func() {
+		walPath := path.Join(GinkgoT().TempDir(), "000000010000000000000001")
+		Expect(os.WriteFile(walPath, []byte("wal content"), 0o600)).To(Succeed())
+
+		Expect(destination.keepPending(walPath)).To(Succeed())
+
+		content, err := fileutils.ReadFile(path.Join(destination.pendingDirectory, "000000010000000000000001"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("wal content"))
+
+		pendingFiles, err := destination.getPendingFiles()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pendingFiles).To(Equal([]string{"000000010000000000000001"}))
+	})
+
+	It("ignores the WAL files which are still being copied", func() {
+		Expect(fileutils.EnsureDirectoryExists(destination.pendingDirectory)).To(Succeed())
+		for _, name := range []string{"000000010000000000000002", "000000010000000000000001", "000000010000000000000003.tmp"} {
+			Expect(os.WriteFile(path.Join(destination.pendingDirectory, name), []byte("wal"), 0o600)).To(Succeed())
+		}
+
+		pendingFiles, err := destination.getPendingFiles()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pendingFiles).To(Equal([]string{"000000010000000000000001", "000000010000000000000002"}))
+	})
+
+	It("keeps the pending WAL files in the volume of PGDATA, outside of it", func() {
+		Expect(getPendingDirectory("/var/lib/postgresql/data/pgdata")).
+			To(Equal("/var/lib/postgresql/data/wal-archive-pending"))
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	// pendingDrainInterval is how often the PendingDrainer
+	// looks for WAL files still to be archived
+	pendingDrainInterval = 30 * time.Second
+
+	// pendingDrainBatchSize is the maximum number of WAL files archived
+	// on each WAL destination every time the PendingDrainer runs
+	pendingDrainBatchSize = 100
+)
+
+// A PendingDrainer is a Kubernetes manager.Runnable archiving, in the
+// background, the WAL files that were kept aside because they failed
+// on some WAL destinations, without slowing down the archive_command
+// run by PostgreSQL.
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type PendingDrainer struct {
+	pgData string
+}
+
+// NewPendingDrainer creates a new PendingDrainer
+// for the passed data directory
+func NewPendingDrainer(pgData string) *PendingDrainer {
+	return &PendingDrainer{
+		pgData: pgData,
+	}
+}
+
+// Start starts running the PendingDrainer
+func (d *PendingDrainer) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("wal_archive_pending_drainer")
+	ctx = log.IntoContext(ctx, contextLog)
+	ticker := time.NewTicker(pendingDrainInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated WAL archive pending drainer loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := d.drain(ctx); err != nil {
+			contextLog.Error(err, "while archiving the pending WAL files")
+		}
+	}
+}
+
+// drain archives the pending WAL files on every WAL destination
+func (d *PendingDrainer) drain(ctx context.Context) error {
+	contextLog := log.FromContext(ctx)
+
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil ||
+		len(cluster.Spec.Backup.AdditionalWalDestinations) == 0 {
+		return nil
+	}
+
+	destinations, err := newWALDestinations(ctx, cluster, d.pgData, cache.LoadEnv)
+	if err != nil {
+		return err
+	}
+
+	for _, destination := range destinations {
+		if err := destination.archivePending(ctx, pendingDrainBatchSize); err != nil {
+			contextLog.Info("WAL destination still failing, the pending WAL files will be archived later",
+				"destination", destination.name,
+				"err", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// sharedCacheCleanupInterval is how often the SharedCacheCleaner
+// looks for expired WAL files in the shared WAL restore cache
+const sharedCacheCleanupInterval = 5 * time.Minute
+
+// A SharedCacheCleaner is a Kubernetes manager.Runnable removing, in the
+// background, the expired WAL files from the shared WAL restore cache,
+// without slowing down the restore_command run by PostgreSQL.
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type SharedCacheCleaner struct{}
+
+// NewSharedCacheCleaner creates a new SharedCacheCleaner
+func NewSharedCacheCleaner() *SharedCacheCleaner {
+	return &SharedCacheCleaner{}
+}
+
+// Start starts running the SharedCacheCleaner
+func (c *SharedCacheCleaner) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("wal_restore_cache_cleaner")
+	ticker := time.NewTicker(sharedCacheCleanupInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated WAL restore cache cleaner loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := c.cleanup(); err != nil {
+			contextLog.Error(err, "while removing the expired WAL files from the shared WAL restore cache")
+		}
+	}
+}
+
+// cleanup removes the expired WAL files from the shared WAL restore cache
+func (c *SharedCacheCleaner) cleanup() error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !cluster.ShouldMountWalRestoreCache() {
+		return nil
+	}
+
+	return restorer.RemoveExpiredFromSharedCaches(
+		postgres.WALRestoreCacheDirectory,
+		cluster.Spec.WalRestoreCache.GetRetentionPeriod())
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"strings"
 	"time"
@@ -32,9 +33,11 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 var (
@@ -54,6 +57,10 @@ const (
 	// SpoolDirectory is the directory where we spool the WAL files that
 	// were pre-archived in parallel
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-restore-spool"
+
+	// StatusFile is the file where we keep track of the outcome of
+	// the most recent executions of this command
+	StatusFile = postgres.ScratchDataDirectory + "/wal-restore-status.json"
 )
 
 // NewCmd creates a new cobra command
@@ -69,15 +76,25 @@ func NewCmd() *cobra.Command {
 			contextLog := log.WithName("wal-restore")
 			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
 			err := run(ctx, pgData, podName, args)
+			recordOutcome(ctx, args[0], err)
 			if err == nil {
 				return nil
 			}
 
 			switch {
 			case errors.Is(err, restorer.ErrWALNotFound):
-				// Nothing to log here. The failure has already been logged.
+				// This is not a failure: PostgreSQL will either switch to
+				// streaming replication or complete the recovery, so there's
+				// no reason to wait before exiting
+				reportFileNotFound(ctx, args[0])
+				recordPgRewindMissingWAL(ctx, args[0])
+				return err
+			case errors.Is(err, barman.ErrBucketNotFound):
+				contextLog.Error(err, "the bucket of the object store doesn't exist, "+
+					"check the configuration of the recovery object store")
 			case errors.Is(err, ErrNoBackupConfigured):
 				contextLog.Info("tried restoring WALs, but no backup was configured")
+				recordPgRewindMissingWAL(ctx, args[0])
 			case errors.Is(err, ErrEndOfWALStreamReached):
 				contextLog.Info(
 					"end-of-wal-stream flag found." +
@@ -171,15 +188,35 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 		}
 	}
 
+	// Step 2b: check if another instance has already downloaded this WAL file
+	// into the shared cache, or wait for it if the download is in progress.
+	// Any problem with the shared cache makes us fall back to the spool
+	sharedCache := getSharedCache(ctx, cluster, barmanConfiguration, recoverClusterName)
+	if sharedCache != nil {
+		restored, unlock := fetchFromSharedCache(ctx, sharedCache, walName, destinationPath)
+		if restored {
+			contextLog.Info("Restored WAL file from the shared cache",
+				"walName", walName,
+				"currentPrimary", cluster.Status.CurrentPrimary,
+				"targetPrimary", cluster.Status.TargetPrimary)
+			return nil
+		}
+		if unlock != nil {
+			defer unlock()
+		}
+	}
+
 	// Step 3: gather the WAL files names to restore. If the required file isn't a regular WAL, we download it directly.
 	var walFilesList []string
-	maxParallel := 1
-	if barmanConfiguration.Wal != nil && barmanConfiguration.Wal.MaxParallel > 1 {
-		maxParallel = barmanConfiguration.Wal.MaxParallel
-	}
+	bootstrapping := cluster.IsInstanceBootstrapping(podName)
+	maxParallel := barmanConfiguration.Wal.GetRestoreMaxParallel(bootstrapping)
 	if postgres.IsWALFile(walName) {
 		// If this is a regular WAL file, we try to prefetch
-		if walFilesList, err = gatherWALFilesToRestore(walName, maxParallel); err != nil {
+		var walSegmentSize *int64
+		if maxParallel > 1 {
+			walSegmentSize = getWALSegmentSize(ctx, pgData)
+		}
+		if walFilesList, err = gatherWALFilesToRestore(walName, maxParallel, walSegmentSize); err != nil {
 			return fmt.Errorf("while generating the list of WAL files to restore: %w", err)
 		}
 	} else {
@@ -195,9 +232,20 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 	// is the one that PostgreSQL has requested to restore.
 	// The failure has already been logged in walRestorer.RestoreList method
 	if walStatus[0].Err != nil {
+		// barman-cloud-wal-restore fails in the same way when the bucket
+		// doesn't exist, which must not be mistaken for the end of the archive
+		if errors.Is(walStatus[0].Err, restorer.ErrWALNotFound) && postgres.IsWALFile(walName) {
+			if err := barman.CheckBucketExists(ctx, barmanConfiguration, recoverClusterName, env); err != nil {
+				return err
+			}
+		}
 		return walStatus[0].Err
 	}
 
+	if sharedCache != nil {
+		storeIntoSharedCache(ctx, sharedCache, walStatus)
+	}
+
 	// Step 5: set end-of-wal-stream flag if any download job returned file-not-found
 	// We skip this step if streaming connection is not available
 	endOfWALStream := isEndOfWALStream(walStatus)
@@ -221,6 +269,7 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 	contextLog.Info("WAL restore command completed (parallel)",
 		"walName", walName,
 		"maxParallel", maxParallel,
+		"bootstrapping", bootstrapping,
 		"successfulWalRestore", successfulWalRestore,
 		"failedWalRestore", maxParallel-successfulWalRestore,
 		"endOfWALStream", endOfWALStream,
@@ -232,6 +281,63 @@ func run(ctx context.Context, pgData string, podName string, args []string) erro
 	return nil
 }
 
+// recordOutcome stores the outcome of the restore_command in the status
+// file, to be reported by the instance manager. A WAL file that is not
+// in the archive is not a failure, as PostgreSQL requests the WAL files
+// until it reaches the end of the archive
+func recordOutcome(ctx context.Context, walName string, err error) {
+	var recordErr error
+	switch {
+	case err == nil:
+		recordErr = restorer.RecordSuccess(StatusFile, walName)
+	case errors.Is(err, restorer.ErrWALNotFound),
+		errors.Is(err, ErrNoBackupConfigured),
+		errors.Is(err, ErrEndOfWALStreamReached):
+		return
+	default:
+		recordErr = restorer.RecordFailure(StatusFile, walName, err)
+	}
+
+	if recordErr != nil {
+		log.FromContext(ctx).Warning("Cannot update the WAL restore status file", "error", recordErr.Error())
+	}
+}
+
+// reportFileNotFound logs that the file requested by PostgreSQL is not in
+// the archive. For a WAL segment this means that the end of the archive has
+// been reached, while PostgreSQL routinely looks for the history files of
+// the timelines which don't exist yet, and for partial WAL files.
+// Both happen constantly in a replica cluster following the archive, so
+// they are only logged at the debug level
+func reportFileNotFound(ctx context.Context, fileName string) {
+	contextLog := log.FromContext(ctx)
+	if !postgres.IsWALFile(fileName) {
+		contextLog.Debug("File not found in the archive", "fileName", fileName)
+		return
+	}
+
+	contextLog.Debug("End of the WAL archive reached", "walName", fileName)
+}
+
+// recordPgRewindMissingWAL records that the passed WAL file is not
+// available when this command is run by pg_rewind, which tells the
+// instance manager that retrying pg_rewind is useless
+func recordPgRewindMissingWAL(ctx context.Context, walName string) {
+	missingWALFile := os.Getenv(postgres.PgRewindMissingWALFileEnv)
+	if missingWALFile == "" || !postgres.IsWALFile(walName) {
+		return
+	}
+
+	file, err := os.OpenFile(missingWALFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304
+	if err == nil {
+		_, err = file.WriteString(walName + "\n")
+		err = errors.Join(err, file.Close())
+	}
+	if err != nil {
+		log.FromContext(ctx).Warning("Cannot record the WAL file not found for pg_rewind", "error", err.Error())
+	}
+}
+
 // restoreWALViaPlugins requests every capable plugin to restore the passed
 // WAL file, and returns an error if every plugin failed. It will not return
 // an error if there's no plugin capable of WAL archiving too
@@ -308,7 +414,6 @@ func GetRecoverConfiguration(
 	*apiv1.BarmanObjectStoreConfiguration,
 	error,
 ) {
-	var env []string
 	// If I am the designated primary. Let's use the recovery object store for this wal
 	if cluster.IsReplica() && cluster.Status.CurrentPrimary == podName {
 		sourceName := cluster.Spec.ReplicaCluster.Source
@@ -320,11 +425,12 @@ func GetRecoverConfiguration(
 		if externalCluster.BarmanObjectStore == nil {
 			return "", nil, nil, ErrNoBackupConfigured
 		}
-		configuration := externalCluster.BarmanObjectStore
-		if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
-			env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
-		} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
-			env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
+		env, err := barmanCredentials.EnvSetEndpointOptions(
+			externalCluster.BarmanObjectStore,
+			postgres.BarmanRestoreEndpointCACertificateLocation,
+			nil)
+		if err != nil {
+			return "", nil, nil, err
 		}
 		return externalCluster.Name, env, externalCluster.BarmanObjectStore, nil
 	}
@@ -332,11 +438,12 @@ func GetRecoverConfiguration(
 	// Otherwise, let's use the object store which we are using to
 	// back up this cluster
 	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil {
-		configuration := cluster.Spec.Backup.BarmanObjectStore
-		if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
-			env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
-		} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
-			env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
+		env, err := barmanCredentials.EnvSetEndpointOptions(
+			cluster.Spec.Backup.BarmanObjectStore,
+			postgres.BarmanBackupEndpointCACertificateLocation,
+			nil)
+		if err != nil {
+			return "", nil, nil, err
 		}
 		return cluster.Name, env, cluster.Spec.Backup.BarmanObjectStore, nil
 	}
@@ -344,9 +451,35 @@ func GetRecoverConfiguration(
 	return "", nil, nil, ErrNoBackupConfigured
 }
 
+// getWALSegmentSize detects the size of the WAL segments of the
+// PGDATA via pg_controldata, returning nil, which means the default
+// size, when it can't be detected
+func getWALSegmentSize(ctx context.Context, pgData string) *int64 {
+	contextLog := log.FromContext(ctx)
+
+	pgControlDataCmd := exec.Command("pg_controldata")
+	pgControlDataCmd.Env = append(os.Environ(), "PGDATA="+pgData)
+	out, err := pgControlDataCmd.Output()
+	if err != nil {
+		contextLog.Warning("Cannot run pg_controldata to detect the WAL segment size, using the default one",
+			"error", err)
+		return nil
+	}
+
+	walSegmentSize, err := utils.GetWALSegmentSizeFromPgControldata(utils.ParsePgControldataOutput(string(out)))
+	if err != nil {
+		contextLog.Warning("Cannot detect the WAL segment size, using the default one",
+			"error", err)
+		return nil
+	}
+
+	return &walSegmentSize
+}
+
 // gatherWALFilesToRestore files a list of possible WAL files to restore, always
-// including as the first one the requested WAL file
-func gatherWALFilesToRestore(walName string, parallel int) (walList []string, err error) {
+// including as the first one the requested WAL file. The segment size is
+// needed to generate the correct names when it is not the default one
+func gatherWALFilesToRestore(walName string, parallel int, walSegmentSize *int64) (walList []string, err error) {
 	var segment postgres.Segment
 
 	segment, err = postgres.SegmentFromName(walName)
@@ -357,9 +490,9 @@ func gatherWALFilesToRestore(walName string, parallel int) (walList []string, er
 		// Let's just avoid prefetching in this case
 		return []string{walName}, nil
 	}
-	// NextSegments would accept postgresVersion and segmentSize,
-	// but we do not have this info here, so we pass nil.
-	segmentList := segment.NextSegments(parallel, nil, nil)
+	// The PostgreSQL version is only needed for versions
+	// older than 9.3, which are not supported
+	segmentList := segment.NextSegments(parallel, nil, walSegmentSize)
 	walList = make([]string, len(segmentList))
 	for idx := range segmentList {
 		walList[idx] = segmentList[idx].Name()
@@ -110,3 +110,25 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function gatherWALFilesToRestore", func() {
+	It("does not prefetch files which are not WAL segments", func() {
+		Expect(gatherWALFilesToRestore("00000002.history", 4, nil)).To(Equal([]string{"00000002.history"}))
+	})
+
+	It("uses the default WAL segment size when it is not known", func() {
+		Expect(gatherWALFilesToRestore("0000000100000000000000FE", 3, nil)).To(Equal([]string{
+			"0000000100000000000000FE",
+			"0000000100000000000000FF",
+			"000000010000000100000000",
+		}))
+	})
+
+	It("uses the WAL segment size of the instance", func() {
+		Expect(gatherWALFilesToRestore("00000001000000000000003E", 3, ptr.To[int64](64*1024*1024))).To(Equal([]string{
+			"00000001000000000000003E",
+			"00000001000000000000003F",
+			"000000010000000100000000",
+		}))
+	})
+})
@@ -0,0 +1,149 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// sharedCacheWaitTimeout is how long we wait for another instance to
+	// download a WAL file into the shared cache before downloading it ourselves
+	sharedCacheWaitTimeout = 30 * time.Second
+
+	// sharedCachePollInterval is how often we check if a WAL file being
+	// downloaded by another instance is available
+	sharedCachePollInterval = 100 * time.Millisecond
+)
+
+// getSharedCache gets the shared WAL restore cache for the object store
+// we are restoring from, or nil if the cache is not enabled or it can't
+// be used
+func getSharedCache(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	clusterName string,
+) *restorer.SharedCache {
+	if !cluster.ShouldMountWalRestoreCache() {
+		return nil
+	}
+
+	sharedCache, err := restorer.NewSharedCache(
+		postgres.WALRestoreCacheDirectory,
+		sharedCacheName(configuration, clusterName),
+		cluster.Spec.WalRestoreCache.GetRetentionPeriod(),
+	)
+	if err != nil {
+		log.FromContext(ctx).Warning("The shared WAL restore cache is not available, "+
+			"using the local spool only", "error", err.Error())
+		return nil
+	}
+
+	return sharedCache
+}
+
+// sharedCacheName gets the name of the directory of the shared cache
+// containing the WAL files of the passed object store
+func sharedCacheName(configuration *apiv1.BarmanObjectStoreConfiguration, clusterName string) string {
+	serverName := clusterName
+	if len(configuration.ServerName) != 0 {
+		serverName = configuration.ServerName
+	}
+
+	hash := sha256.Sum256([]byte(configuration.DestinationPath + "/" + serverName))
+	return fmt.Sprintf("%x", hash[:8])
+}
+
+// fetchFromSharedCache restores the passed WAL file from the shared cache,
+// waiting for it if another instance is downloading it. When the file is
+// not available, it returns the function releasing the lock we took to
+// download it, or nil if we couldn't take it
+func fetchFromSharedCache(
+	ctx context.Context,
+	sharedCache *restorer.SharedCache,
+	walName string,
+	destinationPath string,
+) (restored bool, unlock func()) {
+	contextLog := log.FromContext(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, sharedCacheWaitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(sharedCachePollInterval)
+	defer ticker.Stop()
+
+	for {
+		restored, err := sharedCache.Fetch(walName, destinationPath)
+		if err != nil {
+			contextLog.Warning("Cannot read from the shared WAL restore cache",
+				"walName", walName, "error", err.Error())
+			return false, nil
+		}
+		if restored {
+			return true, nil
+		}
+
+		acquired, unlock, err := sharedCache.Lock(walName)
+		if err != nil {
+			contextLog.Warning("Cannot lock the WAL file in the shared WAL restore cache",
+				"walName", walName, "error", err.Error())
+			return false, nil
+		}
+		if acquired {
+			// The WAL file may have been stored by the instance
+			// releasing the lock we have just taken
+			restored, err := sharedCache.Fetch(walName, destinationPath)
+			if err == nil && restored {
+				unlock()
+				return true, nil
+			}
+			return false, unlock
+		}
+
+		contextLog.Debug("Waiting for another instance to download the WAL file", "walName", walName)
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// storeIntoSharedCache copies the WAL files we downloaded
+// into the shared cache for the other instances
+func storeIntoSharedCache(ctx context.Context, sharedCache *restorer.SharedCache, results []restorer.Result) {
+	contextLog := log.FromContext(ctx)
+
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		if err := sharedCache.Store(result.WalName, result.DestinationPath); err != nil {
+			contextLog.Warning("Cannot store the WAL file in the shared WAL restore cache",
+				"walName", result.WalName, "error", err.Error())
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"os"
+	"path"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shared WAL restore cache", func() {
+	It("is not used when not enabled", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{}
+		Expect(getSharedCache(ctx, cluster, &apiv1.BarmanObjectStoreConfiguration{}, "cluster-example")).To(BeNil())
+	})
+
+	It("uses a different directory for every object store", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"}
+		name := sharedCacheName(configuration, "cluster-example")
+		Expect(name).To(HaveLen(16))
+		Expect(sharedCacheName(configuration, "cluster-example")).To(Equal(name))
+		Expect(sharedCacheName(configuration, "cluster-origin")).ToNot(Equal(name))
+
+		configuration.ServerName = "cluster-example"
+		Expect(sharedCacheName(configuration, "cluster-origin")).To(Equal(name))
+
+		configuration.DestinationPath = "s3://other-backups/"
+		Expect(sharedCacheName(configuration, "cluster-example")).ToNot(Equal(name))
+	})
+
+	Context("fetchFromSharedCache", func() {
+		const walName = "000000010000000000000001"
+
+		var (
+			sharedCache     *restorer.SharedCache
+			sourcePath      string
+			destinationPath string
+		)
+
+		BeforeEach(func() {
+			var err error
+			sharedCache, err = restorer.NewSharedCache(GinkgoT().TempDir(), "store", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+
+			sourcePath = path.Join(GinkgoT().TempDir(), walName)
+			Expect(os.WriteFile(sourcePath, []byte("wal content"), 0o600)).To(Succeed())
+			destinationPath = path.Join(GinkgoT().TempDir(), "RECOVERYXLOG")
+		})
+
+		It("locks the WAL files which are not in the cache", func(ctx SpecContext) {
+			restored, unlock := fetchFromSharedCache(ctx, sharedCache, walName, destinationPath)
+			Expect(restored).To(BeFalse())
+			Expect(unlock).ToNot(BeNil())
+			unlock()
+		})
+
+		It("waits for the WAL files being downloaded by another instance", func(ctx SpecContext) {
+			acquired, unlock, err := sharedCache.Lock(walName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			go func() {
+				defer GinkgoRecover()
+				time.Sleep(200 * time.Millisecond)
+				Expect(sharedCache.Store(walName, sourcePath)).To(Succeed())
+				unlock()
+			}()
+
+			restored, ownUnlock := fetchFromSharedCache(ctx, sharedCache, walName, destinationPath)
+			Expect(restored).To(BeTrue())
+			Expect(ownUnlock).To(BeNil())
+		})
+
+		It("takes over the download when the other instance fails", func(ctx SpecContext) {
+			acquired, unlock, err := sharedCache.Lock(walName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			go func() {
+				time.Sleep(200 * time.Millisecond)
+				unlock()
+			}()
+
+			restored, ownUnlock := fetchFromSharedCache(ctx, sharedCache, walName, destinationPath)
+			Expect(restored).To(BeFalse())
+			Expect(ownUnlock).ToNot(BeNil())
+			ownUnlock()
+		})
+	})
+})
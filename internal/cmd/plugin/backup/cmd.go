@@ -46,16 +46,32 @@ type backupCommandOptions struct {
 }
 
 func (options backupCommandOptions) getOnlineConfiguration() *apiv1.OnlineConfiguration {
+	// The immediate checkpoint set in the backup spec
+	// is not part of the online configuration
+	immediateCheckpoint := options.immediateCheckpoint
+	if options.getImmediateCheckpoint() != nil {
+		immediateCheckpoint = nil
+	}
+
 	var onlineConfiguration *apiv1.OnlineConfiguration
-	if options.immediateCheckpoint != nil || options.waitForArchive != nil {
+	if immediateCheckpoint != nil || options.waitForArchive != nil {
 		onlineConfiguration = &apiv1.OnlineConfiguration{
 			WaitForArchive:      options.waitForArchive,
-			ImmediateCheckpoint: options.immediateCheckpoint,
+			ImmediateCheckpoint: immediateCheckpoint,
 		}
 	}
 	return onlineConfiguration
 }
 
+// getImmediateCheckpoint returns the value of the `.spec.immediateCheckpoint`
+// field of the Backup, which is used by every method but volumeSnapshot
+func (options backupCommandOptions) getImmediateCheckpoint() *bool {
+	if options.method == apiv1.BackupMethodVolumeSnapshot {
+		return nil
+	}
+	return options.immediateCheckpoint
+}
+
 // NewCmd creates the new "backup" subcommand
 func NewCmd() *cobra.Command {
 	var backupName, backupTarget, backupMethod, online, immediateCheckpoint, waitForArchive string
@@ -173,8 +189,11 @@ func NewCmd() *cobra.Command {
 
 	backupSubcommand.Flags().StringVar(&immediateCheckpoint, "immediate-checkpoint", "",
 		"Set the `.spec.onlineConfiguration.immediateCheckpoint` field of the "+
-			"Backup resource. If not specified, the value in the "+
-			"'.spec.backup.volumeSnapshot.onlineConfiguration' field "+
+			"Backup resource with the volumeSnapshot method, and the "+
+			"`.spec.immediateCheckpoint` field with the barmanObjectStore method. "+
+			"If not specified, the value in the "+
+			"'.spec.backup.volumeSnapshot.onlineConfiguration' or "+
+			"'.spec.backup.barmanObjectStore.data' field "+
 			"of the Cluster resource will be used. "+
 			optionalAcceptedValues,
 	)
@@ -204,6 +223,7 @@ func createBackup(ctx context.Context, options backupCommandOptions) error {
 			Method:              options.method,
 			Online:              options.online,
 			OnlineConfiguration: options.getOnlineConfiguration(),
+			ImmediateCheckpoint: options.getImmediateCheckpoint(),
 		},
 	}
 	utils.LabelClusterName(&backup.ObjectMeta, options.clusterName)
@@ -0,0 +1,153 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcatalog
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/logrusorgru/aurora/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// BackupCatalog implements the "backup-catalog" subcommand
+func BackupCatalog(ctx context.Context, clusterName string, refresh bool, format plugin.OutputFormat) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return fmt.Errorf("cluster %s has no barmanObjectStore configured", clusterName)
+	}
+
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("cluster %s has no primary instance", clusterName)
+	}
+
+	var pod corev1.Pod
+	err = plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&pod)
+	if err != nil {
+		return err
+	}
+
+	summary, err := getSummary(ctx, pod, refresh)
+	if err != nil {
+		return err
+	}
+
+	if format != plugin.OutputFormatText {
+		return plugin.Print(summary, format, os.Stdout)
+	}
+
+	printSummary(summary)
+	return nil
+}
+
+// getSummary asks the instance manager running in the passed pod for
+// the summary of the backup catalog
+func getSummary(ctx context.Context, pod corev1.Pod, refresh bool) (*catalog.Summary, error) {
+	command := []string{"/controller/manager", "show", "backup-catalog"}
+	if refresh {
+		command = append(command, "--refresh")
+	}
+
+	timeout := time.Minute
+	clientInterface := kubernetes.NewForConfigOrDie(plugin.Config)
+	stdout, stderr, err := utils.ExecCommand(
+		ctx,
+		clientInterface,
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		command...)
+	if err != nil {
+		return nil, fmt.Errorf("while reading the backup catalog from %s: %w (%s)", pod.Name, err, stderr)
+	}
+
+	var summary catalog.Summary
+	if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+		return nil, fmt.Errorf("while decoding the backup catalog: %w", err)
+	}
+
+	return &summary, nil
+}
+
+func printSummary(summary *catalog.Summary) {
+	fmt.Println(aurora.Green("Backup catalog"))
+	overview := tabby.New()
+	overview.AddLine("Server name:", summary.ServerName)
+	overview.AddLine("First Point of Recoverability:", formatTime(summary.FirstRecoverabilityPoint))
+	overview.AddLine("Last Successful Backup:", formatTime(summary.LastSuccessfulBackup))
+	overview.AddLine("First WAL:", cmp.Or(summary.FirstWAL, "-"))
+	if summary.LastArchivedWAL != "" {
+		overview.AddLine("Last Archived WAL:", summary.LastArchivedWAL)
+	}
+	overview.AddLine("Retrieved at:", summary.RetrievedAt.Format(time.RFC3339))
+	overview.Print()
+	fmt.Println()
+
+	fmt.Println(aurora.Green("Base backups"))
+	if len(summary.Backups) == 0 {
+		fmt.Println("No base backups found")
+		return
+	}
+
+	backups := tabby.New()
+	backups.AddHeader("ID", "Name", "Begin Time", "End Time", "Begin WAL", "End WAL", "Begin LSN", "End LSN",
+		"Timeline", "Error")
+	for _, backup := range summary.Backups {
+		backups.AddLine(
+			backup.ID,
+			cmp.Or(backup.Name, "-"),
+			formatTime(backup.BeginTime),
+			formatTime(backup.EndTime),
+			cmp.Or(backup.BeginWAL, "-"),
+			cmp.Or(backup.EndWAL, "-"),
+			cmp.Or(backup.BeginLSN, "-"),
+			cmp.Or(backup.EndLSN, "-"),
+			backup.TimeLine,
+			cmp.Or(backup.Error, "-"),
+		)
+	}
+	backups.Print()
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil {
+		return "-"
+	}
+	return value.Format(time.RFC3339)
+}
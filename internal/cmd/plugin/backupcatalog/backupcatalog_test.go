@@ -0,0 +1,35 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcatalog
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("formatTime", func() {
+	It("uses a placeholder for missing times", func() {
+		Expect(formatTime(nil)).To(Equal("-"))
+	})
+
+	It("formats the time as RFC3339", func() {
+		value := time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)
+		Expect(formatTime(&value)).To(Equal("2024-03-01T10:20:30Z"))
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcatalog
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd create the new "backup-catalog" subcommand
+func NewCmd() *cobra.Command {
+	backupCatalogCmd := &cobra.Command{
+		Use:   "backup-catalog [cluster]",
+		Short: "List the base backups of a PostgreSQL cluster stored in the object store",
+		Long: "Report the base backups of the cluster that are stored in the object store, " +
+			"together with their begin and end time, WAL files and LSNs, and " +
+			"the interval of time the cluster can be recovered to.",
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			return BackupCatalog(cmd.Context(), args[0], refresh, plugin.OutputFormat(output))
+		},
+	}
+
+	backupCatalogCmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json|yaml")
+	backupCatalogCmd.Flags().Bool(
+		"refresh", false, "Read the catalog from the object store, ignoring the one cached by the instance manager")
+
+	return backupCatalogCmd
+}
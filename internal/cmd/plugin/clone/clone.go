@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// errSourceWithoutObjectStore is raised when the source cluster
+// doesn't have backups to be recovered
+var errSourceWithoutObjectStore = errors.New(
+	"the source cluster has no barmanObjectStore configuration to recover from")
+
+type cloneRun struct {
+	sourceName string
+	cloneName  string
+	targetTime string
+	backupID   string
+	instances  int
+	dryRun     bool
+}
+
+var cloneExample = `
+  # Print the manifest of a copy of "cluster-example" recovered to the end of the WAL archive
+  kubectl-cnpg clone cluster-example cluster-example-clone --dry-run
+
+  # Create a copy of "cluster-example" as it was at the requested point in time
+  kubectl-cnpg clone cluster-example cluster-example-clone --target-time "2024-05-01 10:00:00.00000+00"
+
+  # Create a copy of "cluster-example" with three instances
+  kubectl-cnpg clone cluster-example cluster-example-clone --instances 3`
+
+func (cmd *cloneRun) execute(ctx context.Context) error {
+	var source apiv1.Cluster
+	err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: cmd.sourceName},
+		&source)
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %v", err)
+	}
+
+	clone, err := cmd.buildClone(&source)
+	if err != nil {
+		return err
+	}
+
+	if cmd.dryRun {
+		return plugin.Print(clone, plugin.OutputFormatYAML, os.Stdout)
+	}
+
+	if err := plugin.Client.Create(ctx, clone); err != nil {
+		return err
+	}
+
+	fmt.Printf("cluster/%v created\n", clone.Name)
+	return nil
+}
+
+// buildClone creates the definition of a cluster bootstrapped by recovering
+// the backups of the source one. The clone has the same PostgreSQL
+// configuration and storage of the source, but none of the settings that
+// would tie it to the source cluster, like the backup configuration.
+func (cmd *cloneRun) buildClone(source *apiv1.Cluster) (*apiv1.Cluster, error) {
+	if source.Spec.Backup == nil || source.Spec.Backup.BarmanObjectStore == nil {
+		return nil, errSourceWithoutObjectStore
+	}
+
+	objectStore := source.Spec.Backup.BarmanObjectStore.DeepCopy()
+	if objectStore.ServerName == "" {
+		objectStore.ServerName = source.Name
+	}
+
+	spec := source.Spec.DeepCopy()
+	spec.Instances = cmd.instances
+
+	// The clone must never write to the object store of the source
+	// cluster, and must not be bound to it in any other way
+	spec.Backup = nil
+	spec.ReplicaCluster = nil
+	spec.Plugins = nil
+	spec.Certificates = nil
+	spec.PostgresConfiguration.HotStandbyFeedbackInstances = nil
+	spec.PostgresConfiguration.DelayedReplicas = nil
+	spec.FailoverPriority = nil
+	if spec.Managed != nil {
+		spec.Managed.Services = nil
+	}
+
+	spec.ExternalClusters = []apiv1.ExternalCluster{
+		{
+			Name:              source.Name,
+			BarmanObjectStore: objectStore,
+		},
+	}
+
+	recovery := &apiv1.BootstrapRecovery{
+		Source:   source.Name,
+		Database: source.GetApplicationDatabaseName(),
+		Owner:    source.GetApplicationDatabaseOwner(),
+	}
+	if cmd.targetTime != "" || cmd.backupID != "" {
+		recovery.RecoveryTarget = &apiv1.RecoveryTarget{
+			TargetTime: cmd.targetTime,
+			BackupID:   cmd.backupID,
+		}
+	}
+	spec.Bootstrap = &apiv1.BootstrapConfiguration{Recovery: recovery}
+
+	return &apiv1.Cluster{
+		// To ensure we have the kind and the API version in --dry-run
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cmd.cloneName,
+			Namespace: source.Namespace,
+		},
+		Spec: *spec,
+	}, nil
+}
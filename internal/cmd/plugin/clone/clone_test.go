@@ -0,0 +1,138 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("buildClone", func() {
+	var source *apiv1.Cluster
+
+	BeforeEach(func() {
+		source = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					"cnpg.io/fencedInstances": `["*"]`,
+				},
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "postgres:16",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters:                  map[string]string{"work_mem": "8MB"},
+					HotStandbyFeedbackInstances: []string{"cluster-example-2"},
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "shop", Owner: "shopkeeper"},
+				},
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://backups/",
+					},
+				},
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{Source: "origin"},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{Name: "origin"},
+				},
+			},
+		}
+	})
+
+	It("requires the source cluster to have backups", func() {
+		source.Spec.Backup = nil
+		_, err := (&cloneRun{cloneName: "clone"}).buildClone(source)
+		Expect(err).To(MatchError(errSourceWithoutObjectStore))
+	})
+
+	It("recovers the backups of the source cluster", func() {
+		clone, err := (&cloneRun{cloneName: "clone", instances: 1}).buildClone(source)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(clone.Name).To(Equal("clone"))
+		Expect(clone.Namespace).To(Equal("default"))
+		Expect(clone.Kind).To(Equal(apiv1.ClusterKind))
+		Expect(clone.Annotations).To(BeEmpty())
+		Expect(clone.Spec.Instances).To(Equal(1))
+		Expect(clone.Spec.ImageName).To(Equal("postgres:16"))
+		Expect(clone.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("work_mem", "8MB"))
+
+		Expect(clone.Spec.ExternalClusters).To(HaveLen(1))
+		externalCluster := clone.Spec.ExternalClusters[0]
+		Expect(externalCluster.Name).To(Equal("cluster-example"))
+		Expect(externalCluster.BarmanObjectStore.DestinationPath).To(Equal("s3://backups/"))
+		Expect(externalCluster.BarmanObjectStore.ServerName).To(Equal("cluster-example"))
+
+		recovery := clone.Spec.Bootstrap.Recovery
+		Expect(clone.Spec.Bootstrap.InitDB).To(BeNil())
+		Expect(recovery.Source).To(Equal("cluster-example"))
+		Expect(recovery.Database).To(Equal("shop"))
+		Expect(recovery.Owner).To(Equal("shopkeeper"))
+		Expect(recovery.RecoveryTarget).To(BeNil())
+	})
+
+	It("never writes to the object store of the source cluster", func() {
+		clone, err := (&cloneRun{cloneName: "clone", instances: 1}).buildClone(source)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(clone.Spec.Backup).To(BeNil())
+		Expect(clone.Spec.ReplicaCluster).To(BeNil())
+		Expect(clone.Spec.PostgresConfiguration.HotStandbyFeedbackInstances).To(BeEmpty())
+		Expect(source.Spec.Backup).ToNot(BeNil())
+	})
+
+	It("doesn't refer to the instances of the source cluster", func() {
+		source.Spec.PostgresConfiguration.DelayedReplicas = &apiv1.DelayedReplicasConfiguration{
+			Instances:     []string{"cluster-example-3"},
+			MinApplyDelay: "1h",
+		}
+		source.Spec.FailoverPriority = &apiv1.FailoverPriorityConfiguration{
+			Instances: []apiv1.InstanceFailoverPriority{{Name: "cluster-example-2", Priority: 10}},
+		}
+
+		clone, err := (&cloneRun{cloneName: "clone", instances: 3}).buildClone(source)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(clone.Spec.PostgresConfiguration.DelayedReplicas).To(BeNil())
+		Expect(clone.Spec.FailoverPriority).To(BeNil())
+		Expect(source.Spec.FailoverPriority).ToNot(BeNil())
+	})
+
+	It("recovers up to the requested point in time", func() {
+		clone, err := (&cloneRun{
+			cloneName:  "clone",
+			instances:  1,
+			targetTime: "2024-05-01 10:00:00.00000+00",
+		}).buildClone(source)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clone.Spec.Bootstrap.Recovery.RecoveryTarget.TargetTime).To(Equal("2024-05-01 10:00:00.00000+00"))
+	})
+
+	It("keeps the server name of the source cluster", func() {
+		source.Spec.Backup.BarmanObjectStore.ServerName = "old-name"
+		clone, err := (&cloneRun{cloneName: "clone", instances: 1}).buildClone(source)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clone.Spec.ExternalClusters[0].BarmanObjectStore.ServerName).To(Equal("old-name"))
+	})
+})
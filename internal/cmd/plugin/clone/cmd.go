@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd initializes the clone command
+func NewCmd() *cobra.Command {
+	run := &cloneRun{}
+
+	cloneCmd := &cobra.Command{
+		Use:   "clone [cluster] [new cluster]",
+		Short: "Creates a copy of a cluster recovered from its backups",
+		Long: "Creates a new cluster recovered, up to the requested point in time, from the backups " +
+			"of the specified one. The new cluster has no backup configuration, so that it never " +
+			"writes in the object store of the source cluster.",
+		Example: cloneExample,
+		Args:    plugin.RequiresArguments(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			run.sourceName = args[0]
+			run.cloneName = args[1]
+
+			return run.execute(cmd.Context())
+		},
+	}
+
+	cloneCmd.Flags().StringVar(
+		&run.targetTime,
+		"target-time",
+		"",
+		"The point in time to recover to, in RFC 3339 format. Defaults to the end of the WAL archive",
+	)
+
+	cloneCmd.Flags().StringVar(
+		&run.backupID,
+		"backup-id",
+		"",
+		"The ID of the base backup to recover from. Defaults to the most recent one suitable for the target",
+	)
+
+	cloneCmd.Flags().IntVar(
+		&run.instances,
+		"instances",
+		1,
+		"The number of instances of the new cluster",
+	)
+
+	cloneCmd.Flags().BoolVar(
+		&run.dryRun,
+		"dry-run",
+		false,
+		"When true prints the cluster manifest instead of creating it",
+	)
+
+	return cloneCmd
+}
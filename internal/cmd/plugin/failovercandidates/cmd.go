@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failovercandidates
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd create the new "failover-candidates" subcommand
+func NewCmd() *cobra.Command {
+	failoverCandidatesCmd := &cobra.Command{
+		Use:   "failover-candidates [cluster]",
+		Short: "Simulate a failover, reporting how the candidates would be ranked",
+		Long: "Report the replicas of the cluster in the order in which they would be promoted " +
+			"if the primary failed now, using their replication position and failover priority, " +
+			"together with their lag. Nothing is changed in the cluster.",
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			return FailoverCandidates(cmd.Context(), args[0], plugin.OutputFormat(output))
+		},
+	}
+
+	failoverCandidatesCmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json|yaml")
+
+	return failoverCandidatesCmd
+}
@@ -0,0 +1,314 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failovercandidates
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/logrusorgru/aurora/v4"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// ClusterFailoverCandidates is the result of the simulation of a
+// failover of a cluster
+type ClusterFailoverCandidates struct {
+	// The name of the cluster
+	ClusterName string `json:"clusterName"`
+
+	// The name of the current primary
+	CurrentPrimary string `json:"currentPrimary"`
+
+	// The current LSN of the primary, if it is reporting its status
+	PrimaryLSN string `json:"primaryLSN,omitempty"`
+
+	// The LSN tolerance within which the replicas are ranked by
+	// priority, if the failover priority is configured
+	LSNTolerance string `json:"lsnTolerance,omitempty"`
+
+	// The name of the replica that would be promoted, if any
+	PromotedInstance string `json:"promotedInstance,omitempty"`
+
+	// The replicas, in the order in which they would be promoted
+	Candidates []Candidate `json:"candidates"`
+
+	// The errors found while getting the status of the instances
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Candidate is a replica ranked by the simulation of a failover
+type Candidate struct {
+	// The position of the replica in the ranking, starting from 1
+	Rank int `json:"rank"`
+
+	// The name of the replica
+	Name string `json:"name"`
+
+	// The failover priority of the replica
+	Priority int32 `json:"priority"`
+
+	// True if the replica can be promoted automatically
+	Eligible bool `json:"eligible"`
+
+	// The reason why the replica can't be promoted automatically
+	Reason string `json:"reason,omitempty"`
+
+	// True if the replica is within the LSN tolerance from the most
+	// advanced one, and can be preferred for its priority
+	WithinLSNTolerance bool `json:"withinLSNTolerance"`
+
+	// The last WAL location received and replayed by the replica
+	ReceivedLSN string `json:"receivedLSN,omitempty"`
+	ReplayLSN   string `json:"replayLSN,omitempty"`
+
+	// The amount of WAL, in bytes, by which the replica is behind the
+	// current LSN of the primary
+	LagFromPrimaryBytes *int64 `json:"lagFromPrimaryBytes,omitempty"`
+
+	// The amount of WAL, in bytes, by which the replica is behind the
+	// most advanced replica
+	LagFromMostAdvancedBytes *int64 `json:"lagFromMostAdvancedBytes,omitempty"`
+
+	// The replay lag and the synchronous state of the replica,
+	// as reported by the primary
+	ReplayLag string `json:"replayLag,omitempty"`
+	SyncState string `json:"syncState,omitempty"`
+
+	// True if the replica is streaming WAL from the primary
+	IsWalReceiverActive bool `json:"isWalReceiverActive"`
+}
+
+// FailoverCandidates implements the "failover-candidates" subcommand
+func FailoverCandidates(ctx context.Context, clusterName string, format plugin.OutputFormat) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	managedPods, _, err := resources.GetInstancePods(ctx, cluster.Name)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	instancesStatus, errList := resources.ExtractInstancesStatus(
+		ctx,
+		plugin.Config,
+		managedPods,
+		specs.PostgresContainerName)
+	errs = append(errs, errList...)
+
+	// The candidates are ranked starting from the status sorted
+	// in the same way as the operator does
+	sort.Sort(&instancesStatus)
+
+	result := newClusterFailoverCandidates(&cluster, instancesStatus, errs)
+	if format != plugin.OutputFormatText {
+		return plugin.Print(result, format, os.Stdout)
+	}
+
+	result.print()
+	return nil
+}
+
+// newClusterFailoverCandidates ranks the replicas of the cluster using
+// the same logic of the operator, from the sorted status of the instances
+func newClusterFailoverCandidates(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	errs []error,
+) *ClusterFailoverCandidates {
+	configuration := cluster.Spec.FailoverPriority
+	result := &ClusterFailoverCandidates{
+		ClusterName:    cluster.Name,
+		CurrentPrimary: cluster.Status.CurrentPrimary,
+	}
+	if configuration != nil {
+		result.LSNTolerance = resource.NewQuantity(cluster.GetFailoverLSNTolerance(), resource.BinarySI).String()
+	}
+
+	for _, err := range errs {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	var primary *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		if instancesStatus.Items[idx].IsPrimary {
+			primary = &instancesStatus.Items[idx]
+			result.PrimaryLSN = string(primary.CurrentLsn)
+			break
+		}
+	}
+
+	rankedCandidates := instancesStatus.RankFailoverCandidates(
+		configuration.GetPriority, cluster.GetFailoverLSNTolerance())
+	result.Candidates = make([]Candidate, 0, len(rankedCandidates))
+	for idx, rankedCandidate := range rankedCandidates {
+		candidate := newCandidate(idx+1, rankedCandidate, primary)
+		if candidate.Eligible && result.PromotedInstance == "" {
+			result.PromotedInstance = candidate.Name
+		}
+		result.Candidates = append(result.Candidates, candidate)
+	}
+
+	return result
+}
+
+// newCandidate gets the details of a ranked replica, including its
+// lag from the primary, when the primary is reporting its status
+func newCandidate(
+	rank int,
+	rankedCandidate postgres.FailoverCandidate,
+	primary *postgres.PostgresqlStatus,
+) Candidate {
+	status := rankedCandidate.Status
+	candidate := Candidate{
+		Rank:                rank,
+		Name:                status.Pod.Name,
+		Priority:            rankedCandidate.Priority,
+		Eligible:            rankedCandidate.Eligible,
+		WithinLSNTolerance:  rankedCandidate.WithinLSNTolerance,
+		ReceivedLSN:         string(status.ReceivedLsn),
+		ReplayLSN:           string(status.ReplayLsn),
+		IsWalReceiverActive: status.IsWalReceiverActive,
+	}
+
+	switch {
+	case !status.HasHTTPStatus():
+		candidate.Reason = fmt.Sprintf("not reporting its status: %v", status.Error)
+	case status.IsDelayedReplica:
+		candidate.Reason = "delayed replica, never promoted automatically"
+	}
+
+	if rankedCandidate.LagBytes >= 0 {
+		candidate.LagFromMostAdvancedBytes = ptr.To(rankedCandidate.LagBytes)
+	}
+
+	if primary == nil || !status.HasHTTPStatus() {
+		return candidate
+	}
+
+	primaryLSN, primaryErr := primary.CurrentLsn.Parse()
+	receivedLSN, receivedErr := status.ReceivedLsn.Parse()
+	if primaryErr == nil && receivedErr == nil {
+		candidate.LagFromPrimaryBytes = ptr.To(max(primaryLSN-receivedLSN, 0))
+	}
+
+	for _, replication := range primary.ReplicationInfo {
+		if replication.ApplicationName == candidate.Name {
+			candidate.ReplayLag = replication.ReplayLag
+			candidate.SyncState = replication.SyncState
+			break
+		}
+	}
+
+	return candidate
+}
+
+func (result *ClusterFailoverCandidates) print() {
+	summary := tabby.New()
+	fmt.Println(aurora.Green("Failover simulation"))
+	summary.AddLine("Cluster:", result.ClusterName)
+	currentPrimary := result.CurrentPrimary
+	if result.PrimaryLSN != "" {
+		currentPrimary = fmt.Sprintf("%s (LSN %s)", currentPrimary, result.PrimaryLSN)
+	}
+	summary.AddLine("Current Primary:", currentPrimary)
+	if result.LSNTolerance != "" {
+		summary.AddLine("LSN Tolerance:", result.LSNTolerance)
+	}
+	if result.PromotedInstance != "" {
+		summary.AddLine("Promoted Instance:", aurora.Green(result.PromotedInstance))
+	} else {
+		summary.AddLine("Promoted Instance:", aurora.Red("none, no replica can be promoted"))
+	}
+	summary.Print()
+	fmt.Println()
+
+	fmt.Println(aurora.Green("Failover candidates"))
+	if len(result.Candidates) == 0 {
+		fmt.Println("No replicas found")
+	} else {
+		candidates := tabby.New()
+		candidates.AddHeader("Rank", "Name", "Priority", "Received LSN", "Replay LSN",
+			"Behind Primary", "Behind Most Advanced", "Within Tolerance", "Replay Lag", "Sync State",
+			"WAL Receiver", "Status")
+		for _, candidate := range result.Candidates {
+			candidates.AddLine(
+				candidate.Rank,
+				candidate.Name,
+				candidate.Priority,
+				cmp.Or(candidate.ReceivedLSN, "-"),
+				cmp.Or(candidate.ReplayLSN, "-"),
+				formatBytes(candidate.LagFromPrimaryBytes),
+				formatBytes(candidate.LagFromMostAdvancedBytes),
+				formatBool(candidate.WithinLSNTolerance),
+				cmp.Or(candidate.ReplayLag, "-"),
+				cmp.Or(candidate.SyncState, "-"),
+				formatBool(candidate.IsWalReceiverActive),
+				formatStatus(candidate),
+			)
+		}
+		candidates.Print()
+	}
+	fmt.Println()
+
+	fmt.Println("Nothing has been changed in the cluster. On an actual failover, the operator also",
+		"waits for the failover delay and for the WAL receivers to stop before promoting.")
+
+	if len(result.Errors) > 0 {
+		fmt.Println()
+		fmt.Println(aurora.Red("Error(s) extracting status"))
+		for _, err := range result.Errors {
+			fmt.Println(err)
+		}
+	}
+}
+
+func formatStatus(candidate Candidate) string {
+	if candidate.Eligible {
+		return aurora.Green("OK").String()
+	}
+	return aurora.Red(candidate.Reason).String()
+}
+
+func formatBytes(value *int64) string {
+	if value == nil {
+		return "-"
+	}
+	return resource.NewQuantity(*value, resource.BinarySI).String()
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
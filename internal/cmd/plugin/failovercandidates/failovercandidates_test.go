@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failovercandidates
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover candidates", func() {
+	var (
+		cluster         *apiv1.Cluster
+		instancesStatus postgres.PostgresqlStatusList
+	)
+
+	instance := func(name string, receivedLSN postgres.LSN) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			ReceivedLsn:         receivedLSN,
+			ReplayLsn:           receivedLSN,
+			IsWalReceiverActive: true,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				FailoverPriority: &apiv1.FailoverPriorityConfiguration{
+					Instances: []apiv1.InstanceFailoverPriority{
+						{Name: "cluster-example-3", Priority: 10},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+
+		primary := postgres.PostgresqlStatus{
+			Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+			IsPrimary:  true,
+			CurrentLsn: "0/5000000",
+			ReplicationInfo: postgres.PgStatReplicationList{
+				{ApplicationName: "cluster-example-2", ReplayLag: "00:00:00.001", SyncState: "quorum"},
+			},
+		}
+		failed := instance("cluster-example-4", "")
+		failed.Error = errors.New("connection refused")
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				primary,
+				instance("cluster-example-2", "0/5000000"),
+				instance("cluster-example-3", "0/4800000"),
+				failed,
+			},
+		}
+	})
+
+	It("ranks the replicas as the operator does, with their lag", func() {
+		result := newClusterFailoverCandidates(cluster, instancesStatus, nil)
+		Expect(result.PrimaryLSN).To(Equal("0/5000000"))
+		Expect(result.LSNTolerance).To(Equal("16Mi"))
+		Expect(result.PromotedInstance).To(Equal("cluster-example-3"))
+		Expect(result.Candidates).To(HaveLen(3))
+
+		Expect(result.Candidates[0].Name).To(Equal("cluster-example-3"))
+		Expect(result.Candidates[0].Rank).To(Equal(1))
+		Expect(result.Candidates[0].Priority).To(BeEquivalentTo(10))
+		Expect(result.Candidates[0].LagFromPrimaryBytes).To(Equal(ptr.To[int64](0x800000)))
+		Expect(result.Candidates[0].LagFromMostAdvancedBytes).To(Equal(ptr.To[int64](0x800000)))
+
+		Expect(result.Candidates[1].Name).To(Equal("cluster-example-2"))
+		Expect(result.Candidates[1].LagFromPrimaryBytes).To(Equal(ptr.To[int64](0)))
+		Expect(result.Candidates[1].ReplayLag).To(Equal("00:00:00.001"))
+		Expect(result.Candidates[1].SyncState).To(Equal("quorum"))
+
+		Expect(result.Candidates[2].Name).To(Equal("cluster-example-4"))
+		Expect(result.Candidates[2].Eligible).To(BeFalse())
+		Expect(result.Candidates[2].Reason).To(ContainSubstring("connection refused"))
+		Expect(result.Candidates[2].LagFromPrimaryBytes).To(BeNil())
+	})
+
+	It("promotes the most advanced replica without failover priority", func() {
+		cluster.Spec.FailoverPriority = nil
+		result := newClusterFailoverCandidates(cluster, instancesStatus, nil)
+		Expect(result.LSNTolerance).To(BeEmpty())
+		Expect(result.PromotedInstance).To(Equal("cluster-example-2"))
+	})
+
+	It("reports when no replica can be promoted", func() {
+		instancesStatus.Items[1].IsDelayedReplica = true
+		instancesStatus.Items[2].IsDelayedReplica = true
+		result := newClusterFailoverCandidates(cluster, instancesStatus, []error{errors.New("boom")})
+		Expect(result.PromotedInstance).To(BeEmpty())
+		Expect(result.Candidates[0].Reason).To(ContainSubstring("delayed replica"))
+		Expect(result.Errors).To(ConsistOf("boom"))
+	})
+})
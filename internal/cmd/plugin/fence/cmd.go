@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 var (
@@ -37,7 +38,18 @@ var (
 				node = fmt.Sprintf("%s-%s", clusterName, node)
 			}
 
-			return fencingOn(cmd.Context(), clusterName, node)
+			mode, err := cmd.Flags().GetString("mode")
+			if err != nil {
+				return err
+			}
+			switch utils.FencingMode(mode) {
+			case "", utils.FencingModeStop, utils.FencingModeReadOnly:
+			default:
+				return fmt.Errorf("unknown fencing mode %q, expected %q or %q",
+					mode, utils.FencingModeStop, utils.FencingModeReadOnly)
+			}
+
+			return fencingOn(cmd.Context(), clusterName, node, utils.FencingMode(mode))
 		},
 	}
 
@@ -65,5 +77,11 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(fenceOnCmd)
 	cmd.AddCommand(fenceOffCmd)
 
+	fenceOnCmd.Flags().String(
+		"mode",
+		"",
+		"How the fenced instances of the cluster are isolated: 'stop' shuts PostgreSQL down, "+
+			"'readOnly' keeps it running in read-only mode. Defaults to the current mode of the cluster")
+
 	return cmd
 }
@@ -28,11 +28,13 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
-// fencingOn marks an instance in a cluster as fenced
-func fencingOn(ctx context.Context, clusterName string, serverName string) error {
+// fencingOn marks an instance in a cluster as fenced, changing the
+// fencing mode of the cluster if requested
+func fencingOn(ctx context.Context, clusterName string, serverName string, mode utils.FencingMode) error {
 	err := utils.NewFencingMetadataExecutor(plugin.Client).
 		AddFencing().
 		ForInstance(serverName).
+		WithMode(mode).
 		Execute(ctx,
 			types.NamespacedName{Name: clusterName, Namespace: plugin.Namespace},
 			&apiv1.Cluster{},
@@ -0,0 +1,54 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replaceinstance
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd create the new "replace-instance" subcommand
+func NewCmd() *cobra.Command {
+	replaceInstanceCmd := &cobra.Command{
+		Use:   "replace-instance [cluster] [node]",
+		Short: "Replace the instance named [cluster]-[node] or [node] with a new one",
+		Long: "Request the operator to replace the instance named [cluster]-[node] or [node] with a new one, " +
+			"cloned from the primary. The operator switches over first if the instance is the primary, and " +
+			"deletes the instance and its PVCs only when all the other instances are ready. The progress is " +
+			"reported in the LastInstanceReplacementSucceeded condition of the cluster.",
+		Args: plugin.RequiresArguments(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			node := args[1]
+			if _, err := strconv.Atoi(args[1]); err == nil {
+				node = fmt.Sprintf("%s-%s", clusterName, node)
+			}
+
+			wait, _ := cmd.Flags().GetBool("wait")
+			return ReplaceInstance(cmd.Context(), clusterName, node, wait)
+		},
+	}
+
+	replaceInstanceCmd.Flags().BoolP("wait", "w", false,
+		"Wait for the replacement to be completed, reporting its progress")
+
+	return replaceInstanceCmd
+}
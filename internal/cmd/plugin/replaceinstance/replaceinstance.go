@@ -0,0 +1,123 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replaceinstance implements a command to request the replacement
+// of an instance of a cluster with a new one
+package replaceinstance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// progressCheckInterval is how often the progress of the replacement
+// is checked when waiting for it to be completed
+const progressCheckInterval = 2 * time.Second
+
+// ReplaceInstance requests the replacement of an instance, setting the
+// replaceInstance annotation on the cluster
+func ReplaceInstance(ctx context.Context, clusterName, instanceName string, waitForCompletion bool) error {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
+	}
+
+	if requested, ok := cluster.Annotations[utils.ReplaceInstanceAnnotationName]; ok {
+		return fmt.Errorf("the replacement of instance %s is already in progress", requested)
+	}
+
+	// Check if the Pod exist
+	var pod corev1.Pod
+	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: instanceName}, &pod)
+	if err != nil {
+		return fmt.Errorf("instance %s not found in namespace %s", instanceName, plugin.Namespace)
+	}
+
+	origCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[utils.ReplaceInstanceAnnotationName] = instanceName
+	if err := plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Replacement of instance %s of cluster %s requested\n", instanceName, clusterName)
+	if !waitForCompletion {
+		return nil
+	}
+
+	return waitForReplacement(ctx, clusterName)
+}
+
+// waitForReplacement prints the progress of the replacement until the
+// operator removes the request, and reports its outcome
+func waitForReplacement(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+	lastMessage := ""
+	err := wait.PollUntilContextCancel(ctx, progressCheckInterval, true, func(ctx context.Context) (bool, error) {
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+			&cluster,
+		); err != nil {
+			return false, err
+		}
+
+		if _, requested := cluster.Annotations[utils.ReplaceInstanceAnnotationName]; !requested {
+			return true, nil
+		}
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionInstanceReplacement))
+		if condition != nil &&
+			condition.Reason == string(apiv1.ConditionReasonInstanceReplacementInProgress) &&
+			condition.Message != lastMessage {
+			lastMessage = condition.Message
+			fmt.Println(lastMessage)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionInstanceReplacement))
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		message := "unknown reason"
+		if condition != nil {
+			message = condition.Message
+		}
+		return fmt.Errorf("instance replacement aborted: %s", message)
+	}
+
+	fmt.Println(condition.Message)
+	return nil
+}
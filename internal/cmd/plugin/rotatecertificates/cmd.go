@@ -0,0 +1,46 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotatecertificates
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "rotate-certificates" command
+func NewCmd() *cobra.Command {
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-certificates [clusterName]",
+		Short: `Renew the certificates of the cluster`,
+		Long: `Requests the immediate renewal of the certificates managed by the operator for the cluster. ` +
+			`The instances will reload PostgreSQL without interrupting the existing connections.`,
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+			return RotateCertificates(ctx, clusterName)
+		},
+	}
+
+	return rotateCmd
+}
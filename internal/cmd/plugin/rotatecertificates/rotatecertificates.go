@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rotatecertificates implements a command to request the renewal
+// of the certificates of a cluster
+package rotatecertificates
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// RotateCertificates marks the cluster as needing to have its certificates renewed
+func RotateCertificates(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	clusterRotated := cluster.DeepCopy()
+	if clusterRotated.Annotations == nil {
+		clusterRotated.Annotations = make(map[string]string)
+	}
+	clusterRotated.Annotations[utils.CertificatesRotationAnnotationName] = utils.GetCurrentTimestamp()
+	clusterRotated.ManagedFields = nil
+
+	err = plugin.Client.Patch(ctx, clusterRotated, client.MergeFrom(&cluster))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The certificates of %s will be renewed\n", clusterRotated.Name)
+	return nil
+}
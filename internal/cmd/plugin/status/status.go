@@ -178,11 +178,15 @@ func ExtractPostgresqlStatus(ctx context.Context, cluster apiv1.Cluster) *Postgr
 	return &status
 }
 
-func listFencedInstances(fencedInstances *stringset.Data) string {
+func listFencedInstances(fencedInstances *stringset.Data, mode utils.FencingMode) string {
+	list := strings.Join(fencedInstances.ToList(), ", ")
 	if fencedInstances.Has(utils.FenceAllInstances) {
-		return "All Instances"
+		list = "All Instances"
 	}
-	return strings.Join(fencedInstances.ToList(), ", ")
+	if mode == utils.FencingModeReadOnly {
+		return list + " (read-only)"
+	}
+	return list
 }
 
 func (fullStatus *PostgresqlStatus) printBasicInfo() {
@@ -228,12 +232,12 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 	}
 
 	summary.AddLine("Status:", fullStatus.getStatus(isPrimaryFenced, cluster))
-	if cluster.Spec.Instances == cluster.Status.Instances {
-		summary.AddLine("Instances:", aurora.Green(cluster.Spec.Instances))
+	if cluster.GetInstances() == cluster.Status.Instances {
+		summary.AddLine("Instances:", aurora.Green(cluster.GetInstances()))
 	} else {
-		summary.AddLine("Instances:", aurora.Red(cluster.Spec.Instances))
+		summary.AddLine("Instances:", aurora.Red(cluster.GetInstances()))
 	}
-	if cluster.Spec.Instances == cluster.Status.ReadyInstances {
+	if cluster.GetInstances() == cluster.Status.ReadyInstances {
 		summary.AddLine("Ready instances:", aurora.Green(cluster.Status.ReadyInstances))
 	} else {
 		summary.AddLine("Ready instances:", aurora.Red(cluster.Status.ReadyInstances))
@@ -241,9 +245,27 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 
 	if fencedInstances != nil && fencedInstances.Len() > 0 {
 		if isPrimaryFenced {
-			summary.AddLine("Fenced instances:", aurora.Red(listFencedInstances(fencedInstances)))
+			summary.AddLine("Fenced instances:",
+				aurora.Red(listFencedInstances(fencedInstances, cluster.GetFencingMode())))
 		} else {
-			summary.AddLine("Fenced instances:", aurora.Yellow(listFencedInstances(fencedInstances)))
+			summary.AddLine("Fenced instances:",
+				aurora.Yellow(listFencedInstances(fencedInstances, cluster.GetFencingMode())))
+		}
+	}
+
+	if len(cluster.Status.QuarantinedInstances) > 0 {
+		summary.AddLine("Quarantined instances:",
+			aurora.Red(strings.Join(cluster.Status.QuarantinedInstances, ", ")))
+	}
+
+	if pendingRestart := cluster.Status.PendingRestart; pendingRestart != nil {
+		summary.AddLine("Pending restart:", aurora.Yellow(fmt.Sprintf("%s, deferred by the %s strategy",
+			strings.Join(pendingRestart.Instances, ", "), pendingRestart.Strategy)))
+		if len(pendingRestart.Parameters) > 0 {
+			summary.AddLine("Parameters pending restart:", strings.Join(pendingRestart.Parameters, ", "))
+		}
+		if pendingRestart.NextMaintenanceWindow != nil {
+			summary.AddLine("Next maintenance window:", pendingRestart.NextMaintenanceWindow.Format(time.RFC3339))
 		}
 	}
 
@@ -499,7 +521,7 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 	}
 
 	fmt.Println(aurora.Green("Streaming Replication status"))
-	if fullStatus.Cluster.Spec.Instances == 1 {
+	if fullStatus.Cluster.GetInstances() == 1 {
 		fmt.Println(aurora.Yellow("Not configured").String())
 		fmt.Println()
 		return
@@ -558,7 +580,8 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 
 func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 	//  Column "Replication role"
-	//  If fenced, print "Fenced"
+	//  If fenced in read-only mode, print "Fenced (read-only)"
+	//  else if fenced, print "Fenced"
 	//  else if instance is primary, print "Primary"
 	//  	Otherwise, it is considered a standby
 	//  else if it is not replicating:
@@ -690,6 +713,9 @@ func getCurrentLSN(instance postgres.PostgresqlStatus) postgres.LSN {
 }
 
 func getReplicaRole(instance postgres.PostgresqlStatus, fullStatus *PostgresqlStatus) string {
+	if instance.FencingMode == utils.FencingModeReadOnly {
+		return "Fenced (read-only)"
+	}
 	if instance.MightBeUnavailable {
 		return "Fenced"
 	}
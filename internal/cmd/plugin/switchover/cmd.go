@@ -0,0 +1,68 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package switchover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd create the new "switchover" subcommand
+func NewCmd() *cobra.Command {
+	var cancel bool
+
+	switchoverCmd := &cobra.Command{
+		Use:   "switchover [cluster] [node]",
+		Short: "Request a switchover to the pod named [cluster]-[node] or [node]",
+		Long: "Request the operator to perform a controlled switchover to the pod named [cluster]-[node] " +
+			"or [node]. The switchover is aborted if the chosen instance is not healthy or is lagging behind " +
+			"the primary, and its progress is reported in the switchover section of the cluster status. " +
+			"A requested switchover can be cancelled with --cancel while it is being prepared.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cancel {
+				return plugin.RequiresArguments(1)(cmd, args)
+			}
+			return plugin.RequiresArguments(2)(cmd, args)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			clusterName := args[0]
+			if cancel {
+				return Cancel(context.Background(), clusterName)
+			}
+
+			node := args[1]
+			if _, err := strconv.Atoi(args[1]); err == nil {
+				node = fmt.Sprintf("%s-%s", clusterName, node)
+			}
+			return Switchover(context.Background(), clusterName, node)
+		},
+	}
+
+	switchoverCmd.Flags().BoolVar(
+		&cancel,
+		"cancel",
+		false,
+		"Cancel the requested switchover, if the current primary hasn't been asked to shut down yet",
+	)
+
+	return switchoverCmd
+}
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package switchover implement the kubectl-cnpg switchover command
+package switchover
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Switchover command implementation
+func Switchover(ctx context.Context, clusterName string, serverName string) error {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
+	}
+
+	if cluster.Status.CurrentPrimary == serverName {
+		fmt.Printf("%s is already the primary node in the cluster\n", serverName)
+		return nil
+	}
+
+	// Check if the Pod exist
+	var pod corev1.Pod
+	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: serverName}, &pod)
+	if err != nil {
+		return fmt.Errorf("new primary node %s not found in namespace %s", serverName, plugin.Namespace)
+	}
+
+	origCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[utils.SwitchoverToAnnotationName] = serverName
+	if err := plugin.Client.Patch(ctx, &cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Switchover of cluster %s to node %s requested\n", clusterName, serverName)
+	return nil
+}
+
+// Cancel removes the switchover request from the cluster, if the
+// switchover can still be cancelled
+func Cancel(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+
+	// Get the Cluster object
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
+	}
+
+	if _, requested := cluster.Annotations[utils.SwitchoverToAnnotationName]; !requested {
+		fmt.Printf("No switchover has been requested for cluster %s\n", clusterName)
+		return nil
+	}
+
+	if progress := cluster.Status.Switchover; progress.IsInProgress() &&
+		progress.Phase != apiv1.SwitchoverPhasePreparing {
+		return fmt.Errorf("the switchover to %s can't be cancelled anymore, as it is in the %s phase",
+			progress.TargetPrimary, progress.Phase)
+	}
+
+	// The patch fails if the cluster changed after being read, as the
+	// switchover may have passed the point of no return in the meantime
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.SwitchoverToAnnotationName)
+	err = plugin.Client.Patch(ctx, &cluster,
+		client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+	if apierrs.IsConflict(err) {
+		return fmt.Errorf("cluster %s changed while cancelling the switchover, "+
+			"check the progress of the switchover and retry", clusterName)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Switchover of cluster %s cancelled\n", clusterName)
+	return nil
+}
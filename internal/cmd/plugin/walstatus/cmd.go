@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstatus
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd create the new "wal-status" subcommand
+func NewCmd() *cobra.Command {
+	walStatusCmd := &cobra.Command{
+		Use:   "wal-status [cluster]",
+		Short: "Get the WAL archive and restore status of a PostgreSQL cluster",
+		Long: "Report, for each instance of the cluster, the last archived and restored WAL files, " +
+			"the WAL files still waiting to be archived, the status of the WAL spools " +
+			"and the most recent WAL restore failures.",
+		Args: plugin.RequiresArguments(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return plugin.CompleteClusters(cmd.Context(), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			return WALStatus(cmd.Context(), args[0], plugin.OutputFormat(output))
+		},
+	}
+
+	walStatusCmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json")
+
+	return walStatusCmd
+}
@@ -0,0 +1,321 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstatus
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/logrusorgru/aurora/v4"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// healthOK means that the last attempt succeeded
+	healthOK = "ok"
+
+	// healthFailing means that the last attempt failed
+	healthFailing = "failing"
+
+	// healthUnknown means that there was no attempt yet
+	healthUnknown = "unknown"
+)
+
+// ClusterWALStatus is the WAL archive and restore status of a cluster
+type ClusterWALStatus struct {
+	// The name of the cluster
+	ClusterName string `json:"clusterName"`
+
+	// The first point of recoverability of the cluster, if available
+	FirstRecoverabilityPoint string `json:"firstRecoverabilityPoint,omitempty"`
+
+	// The message of the ContinuousArchiving condition, if available
+	ContinuousArchiving string `json:"continuousArchiving,omitempty"`
+
+	// The status of each instance
+	Instances []InstanceWALStatus `json:"instances"`
+
+	// The errors found while getting the status of the instances
+	Errors []string `json:"errors,omitempty"`
+}
+
+// InstanceWALStatus is the WAL archive and restore status of an instance
+type InstanceWALStatus struct {
+	// The name of the instance
+	Name string `json:"name"`
+
+	// The role of the instance
+	Role string `json:"role"`
+
+	// The WAL file being currently written
+	CurrentWAL string `json:"currentWAL,omitempty"`
+
+	// The health of WAL archiving, as reported by pg_stat_archiver
+	Archiving           string `json:"archiving"`
+	LastArchivedWAL     string `json:"lastArchivedWAL,omitempty"`
+	LastArchivedWALTime string `json:"lastArchivedWALTime,omitempty"`
+	LastFailedWAL       string `json:"lastFailedWAL,omitempty"`
+	LastFailedWALTime   string `json:"lastFailedWALTime,omitempty"`
+
+	// The archive lag, as the number of WAL files waiting to be archived
+	ReadyWALFiles int `json:"readyWalFiles"`
+
+	// The number of WAL files that were archived in parallel and
+	// that PostgreSQL still has to request to archive
+	ArchiveSpoolWALFiles int `json:"archiveSpoolWalFiles"`
+
+	// The health of WAL restore, as reported by the restore_command
+	Restore             string                       `json:"restore"`
+	LastRestoredWAL     string                       `json:"lastRestoredWAL,omitempty"`
+	LastRestoredWALTime string                       `json:"lastRestoredWALTime,omitempty"`
+	RestoreFailures     []postgres.WALRestoreFailure `json:"restoreFailures,omitempty"`
+
+	// The number of WAL files that were prefetched in parallel and
+	// that PostgreSQL still has to request to restore
+	RestoreSpoolWALFiles int `json:"restoreSpoolWalFiles"`
+}
+
+// WALStatus implements the "wal-status" subcommand
+func WALStatus(ctx context.Context, clusterName string, format plugin.OutputFormat) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	managedPods, _, err := resources.GetInstancePods(ctx, cluster.Name)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	instancesStatus, errList := resources.ExtractInstancesStatus(
+		ctx,
+		plugin.Config,
+		managedPods,
+		specs.PostgresContainerName)
+	errs = append(errs, errList...)
+
+	status := newClusterWALStatus(&cluster, instancesStatus, errs)
+	if format != plugin.OutputFormatText {
+		return plugin.Print(status, format, os.Stdout)
+	}
+
+	status.print()
+	return nil
+}
+
+// newClusterWALStatus extracts the WAL archive and restore status of
+// the cluster from the status of its instances
+func newClusterWALStatus(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	errs []error,
+) *ClusterWALStatus {
+	status := &ClusterWALStatus{
+		ClusterName:              cluster.Name,
+		FirstRecoverabilityPoint: cluster.Status.FirstRecoverabilityPoint,
+		Instances:                make([]InstanceWALStatus, 0, len(instancesStatus.Items)),
+	}
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionContinuousArchiving))
+	if condition != nil {
+		status.ContinuousArchiving = condition.Message
+	}
+
+	for _, err := range errs {
+		status.Errors = append(status.Errors, err.Error())
+	}
+
+	for _, instance := range instancesStatus.Items {
+		if instance.Pod == nil || !instance.HasHTTPStatus() {
+			continue
+		}
+
+		status.Instances = append(status.Instances, InstanceWALStatus{
+			Name:                 instance.Pod.Name,
+			Role:                 getRole(cluster, instance),
+			CurrentWAL:           instance.CurrentWAL,
+			Archiving:            getArchivingHealth(instance),
+			LastArchivedWAL:      instance.LastArchivedWAL,
+			LastArchivedWALTime:  instance.LastArchivedWALTime,
+			LastFailedWAL:        instance.LastFailedWAL,
+			LastFailedWALTime:    instance.LastFailedWALTime,
+			ReadyWALFiles:        instance.ReadyWALFiles,
+			ArchiveSpoolWALFiles: instance.ArchiveSpoolWALFiles,
+			Restore:              getRestoreHealth(instance),
+			LastRestoredWAL:      instance.LastRestoredWAL,
+			LastRestoredWALTime:  instance.LastRestoredWALTime,
+			RestoreFailures:      instance.WALRestoreFailures,
+			RestoreSpoolWALFiles: instance.RestoreSpoolWALFiles,
+		})
+	}
+
+	return status
+}
+
+func getRole(cluster *apiv1.Cluster, instance postgres.PostgresqlStatus) string {
+	switch {
+	case instance.IsPrimary:
+		return "primary"
+	case cluster.IsReplica() && instance.Pod.Name == cluster.Status.CurrentPrimary:
+		return "designated primary"
+	default:
+		return "replica"
+	}
+}
+
+// getArchivingHealth gets the health of WAL archiving from pg_stat_archiver
+func getArchivingHealth(instance postgres.PostgresqlStatus) string {
+	switch {
+	case instance.IsArchivingWAL:
+		return healthOK
+	case instance.LastFailedWAL != "":
+		return healthFailing
+	default:
+		return healthUnknown
+	}
+}
+
+// getRestoreHealth gets the health of WAL restore, checking whether the
+// most recent failure of the restore_command happened after the last
+// WAL file has been restored
+func getRestoreHealth(instance postgres.PostgresqlStatus) string {
+	if len(instance.WALRestoreFailures) == 0 {
+		if instance.LastRestoredWAL == "" {
+			return healthUnknown
+		}
+		return healthOK
+	}
+
+	if instance.LastRestoredWALTime == "" {
+		return healthFailing
+	}
+
+	lastFailure := instance.WALRestoreFailures[len(instance.WALRestoreFailures)-1]
+	elapsed, err := utils.DifferenceBetweenTimestamps(lastFailure.Time, instance.LastRestoredWALTime)
+	if err != nil || elapsed > 0 {
+		return healthFailing
+	}
+
+	return healthOK
+}
+
+func (status *ClusterWALStatus) print() {
+	summary := tabby.New()
+	fmt.Println(aurora.Green("WAL archive and restore status"))
+	summary.AddLine("Cluster:", status.ClusterName)
+	summary.AddLine("First Point of Recoverability:", cmp.Or(status.FirstRecoverabilityPoint, "Not Available"))
+	if status.ContinuousArchiving != "" {
+		summary.AddLine("Continuous Archiving:", status.ContinuousArchiving)
+	}
+	summary.Print()
+	fmt.Println()
+
+	fmt.Println(aurora.Green("WAL archiving"))
+	archiving := tabby.New()
+	archiving.AddHeader("Name", "Role", "Status", "Current WAL", "Last Archived WAL", "Last Failed WAL",
+		"WALs Waiting", "Spooled WALs")
+	for _, instance := range status.Instances {
+		archiving.AddLine(
+			instance.Name,
+			instance.Role,
+			colorizeHealth(instance.Archiving),
+			cmp.Or(instance.CurrentWAL, "-"),
+			formatWAL(instance.LastArchivedWAL, instance.LastArchivedWALTime),
+			formatWAL(instance.LastFailedWAL, instance.LastFailedWALTime),
+			instance.ReadyWALFiles,
+			instance.ArchiveSpoolWALFiles,
+		)
+	}
+	archiving.Print()
+	fmt.Println()
+
+	fmt.Println(aurora.Green("WAL restore"))
+	restore := tabby.New()
+	restore.AddHeader("Name", "Role", "Status", "Last Restored WAL", "Prefetched WALs")
+	for _, instance := range status.Instances {
+		restore.AddLine(
+			instance.Name,
+			instance.Role,
+			colorizeHealth(instance.Restore),
+			formatWAL(instance.LastRestoredWAL, instance.LastRestoredWALTime),
+			instance.RestoreSpoolWALFiles,
+		)
+	}
+	restore.Print()
+	fmt.Println()
+
+	status.printRestoreFailures()
+
+	if len(status.Errors) > 0 {
+		fmt.Println(aurora.Red("Error(s) extracting status"))
+		for _, err := range status.Errors {
+			fmt.Println(err)
+		}
+	}
+}
+
+func (status *ClusterWALStatus) printRestoreFailures() {
+	fmt.Println(aurora.Green("Recent WAL restore failures"))
+
+	failures := tabby.New()
+	failures.AddHeader("Name", "WAL", "Time", "Error")
+	found := false
+	for _, instance := range status.Instances {
+		for _, failure := range instance.RestoreFailures {
+			failures.AddLine(instance.Name, failure.WALName, failure.Time, failure.Error)
+			found = true
+		}
+	}
+
+	if !found {
+		fmt.Println("No WAL restore failures")
+	} else {
+		failures.Print()
+	}
+	fmt.Println()
+}
+
+func colorizeHealth(health string) string {
+	switch health {
+	case healthOK:
+		return aurora.Green("OK").String()
+	case healthFailing:
+		return aurora.Red("Failing").String()
+	default:
+		return aurora.Yellow("-").String()
+	}
+}
+
+func formatWAL(walName, walTime string) string {
+	if walName == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s @ %s", walName, walTime)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walstatus
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL status", func() {
+	const (
+		earlier = "2024-05-05T12:00:00.000000Z"
+		later   = "2024-05-05T12:05:00.000000Z"
+	)
+
+	Context("getRestoreHealth", func() {
+		It("is unknown when no WAL file has been restored", func() {
+			Expect(getRestoreHealth(postgres.PostgresqlStatus{})).To(Equal(healthUnknown))
+		})
+
+		It("is ok when the last WAL file has been restored after the last failure", func() {
+			Expect(getRestoreHealth(postgres.PostgresqlStatus{
+				LastRestoredWAL:     "000000010000000000000002",
+				LastRestoredWALTime: later,
+				WALRestoreFailures: []postgres.WALRestoreFailure{
+					{WALName: "000000010000000000000002", Time: earlier, Error: "timeout"},
+				},
+			})).To(Equal(healthOK))
+		})
+
+		It("is failing when the last failure happened after the last WAL restored", func() {
+			Expect(getRestoreHealth(postgres.PostgresqlStatus{
+				LastRestoredWAL:     "000000010000000000000001",
+				LastRestoredWALTime: earlier,
+				WALRestoreFailures: []postgres.WALRestoreFailure{
+					{WALName: "000000010000000000000002", Time: later, Error: "timeout"},
+				},
+			})).To(Equal(healthFailing))
+		})
+
+		It("is failing when no WAL file has ever been restored", func() {
+			Expect(getRestoreHealth(postgres.PostgresqlStatus{
+				WALRestoreFailures: []postgres.WALRestoreFailure{
+					{WALName: "000000010000000000000001", Time: earlier, Error: "timeout"},
+				},
+			})).To(Equal(healthFailing))
+		})
+	})
+
+	Context("newClusterWALStatus", func() {
+		It("aggregates the status of the instances", func() {
+			cluster := &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary:           "cluster-example-1",
+					FirstRecoverabilityPoint: earlier,
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(apiv1.ConditionContinuousArchiving),
+							Status:  metav1.ConditionTrue,
+							Message: "Continuous archiving is working",
+						},
+					},
+				},
+			}
+			instancesStatus := postgres.PostgresqlStatusList{
+				Items: []postgres.PostgresqlStatus{
+					{
+						Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+						IsPrimary:            true,
+						IsArchivingWAL:       true,
+						CurrentWAL:           "000000010000000000000005",
+						LastArchivedWAL:      "000000010000000000000004",
+						ReadyWALFiles:        1,
+						ArchiveSpoolWALFiles: 2,
+					},
+					{
+						Pod:                  &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+						LastRestoredWAL:      "000000010000000000000004",
+						LastRestoredWALTime:  later,
+						RestoreSpoolWALFiles: 3,
+					},
+					{
+						Pod:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+						Error: errors.New("connection refused"),
+					},
+				},
+			}
+
+			status := newClusterWALStatus(cluster, instancesStatus, []error{errors.New("connection refused")})
+			Expect(status.ClusterName).To(Equal("cluster-example"))
+			Expect(status.FirstRecoverabilityPoint).To(Equal(earlier))
+			Expect(status.ContinuousArchiving).To(Equal("Continuous archiving is working"))
+			Expect(status.Errors).To(ConsistOf("connection refused"))
+
+			Expect(status.Instances).To(HaveLen(2))
+			Expect(status.Instances[0].Role).To(Equal("primary"))
+			Expect(status.Instances[0].Archiving).To(Equal(healthOK))
+			Expect(status.Instances[0].ReadyWALFiles).To(Equal(1))
+			Expect(status.Instances[0].ArchiveSpoolWALFiles).To(Equal(2))
+			Expect(status.Instances[0].Restore).To(Equal(healthUnknown))
+
+			Expect(status.Instances[1].Role).To(Equal("replica"))
+			Expect(status.Instances[1].Archiving).To(Equal(healthUnknown))
+			Expect(status.Instances[1].Restore).To(Equal(healthOK))
+			Expect(status.Instances[1].RestoreSpoolWALFiles).To(Equal(3))
+		})
+	})
+})
@@ -106,6 +106,22 @@ type Data struct {
 	// CreateAnyService is true when the user wants the operator to create
 	// the <cluster-name>-any service. Defaults to false.
 	CreateAnyService bool `json:"createAnyService" env:"CREATE_ANY_SERVICE"`
+
+	// NamespaceDefaultsConfigMapName is the name of the ConfigMap that,
+	// when existing in the namespace of a new cluster, contains the
+	// defaults of the clusters of that namespace. Empty to disable
+	// the namespace defaults.
+	NamespaceDefaultsConfigMapName string `json:"namespaceDefaultsConfigMapName" env:"NAMESPACE_DEFAULTS_CONFIGMAP_NAME"`
+
+	// MaxConcurrentBackups is the maximum number of backups running at
+	// the same time across every cluster managed by the operator, the
+	// exceeding ones being queued. Zero means no limit.
+	MaxConcurrentBackups int `json:"maxConcurrentBackups" env:"MAX_CONCURRENT_BACKUPS"`
+
+	// MaxConcurrentBackupsPerNamespace is the maximum number of backups
+	// running at the same time in a namespace, the exceeding ones being
+	// queued. Zero means no limit.
+	MaxConcurrentBackupsPerNamespace int `json:"maxConcurrentBackupsPerNamespace" env:"MAX_CONCURRENT_BACKUPS_PER_NAMESPACE"` //nolint
 }
 
 // Current is the configuration used by the operator
@@ -0,0 +1,253 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// backupQueueCheckInterval is the time after which a queued
+// backup is checked again
+const backupQueueCheckInterval = 10 * time.Second
+
+// archiveTierExportCheckInterval is the time after which the export
+// of a backup to the archive tier is checked again
+const archiveTierExportCheckInterval = time.Minute
+
+// reconcileBackupQueue queues a backup that can't be started because the
+// maximum number of concurrent backups, configured for the operator or for
+// each namespace, has been reached. Queued backups are started in the order
+// they have been created. A nil result means that the backup can be started.
+//
+// The backup controller reconciles one backup at a time, and a backup is
+// flagged as started before the next one is reconciled. The backups are
+// read bypassing the cache, which may not contain the latest status updates,
+// so that a backup that has just been started is always counted
+func (r *BackupReconciler) reconcileBackupQueue(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (*ctrl.Result, error) {
+	maxBackups := configuration.Current.MaxConcurrentBackups
+	maxBackupsPerNamespace := configuration.Current.MaxConcurrentBackupsPerNamespace
+	if maxBackups <= 0 && maxBackupsPerNamespace <= 0 {
+		return nil, nil
+	}
+
+	if !isBackupWaitingToStart(backup) {
+		return nil, nil
+	}
+
+	backups, err := r.listWatchedBackups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !shouldQueueBackup(backup, backups, maxBackups, maxBackupsPerNamespace) {
+		return nil, nil
+	}
+
+	if backup.Status.Phase != apiv1.BackupPhaseQueued {
+		log.FromContext(ctx).Info("Maximum number of concurrent backups reached, queuing the backup",
+			"maxConcurrentBackups", maxBackups,
+			"maxConcurrentBackupsPerNamespace", maxBackupsPerNamespace)
+		r.Recorder.Event(backup, "Normal", "BackupQueued",
+			"Maximum number of concurrent backups reached, the backup has been queued")
+
+		origBackup := backup.DeepCopy()
+		backup.Status.Phase = apiv1.BackupPhaseQueued
+		if err := r.Status().Patch(ctx, backup, client.MergeFrom(origBackup)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ctrl.Result{RequeueAfter: backupQueueCheckInterval}, nil
+}
+
+// listWatchedBackups lists the backups of the namespaces watched by the
+// operator, bypassing the cache. When the operator is restricted to a set
+// of namespaces, it may not have the permission to list the backups of the
+// whole Kubernetes cluster, and the backups are listed namespace by namespace
+func (r *BackupReconciler) listWatchedBackups(ctx context.Context) ([]apiv1.Backup, error) {
+	namespaces := configuration.Current.WatchedNamespaces()
+	if len(namespaces) == 0 {
+		var backups apiv1.BackupList
+		if err := r.APIReader.List(ctx, &backups); err != nil {
+			return nil, err
+		}
+		return backups.Items, nil
+	}
+
+	var result []apiv1.Backup
+	for _, namespace := range namespaces {
+		var backups apiv1.BackupList
+		if err := r.APIReader.List(ctx, &backups, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		result = append(result, backups.Items...)
+	}
+
+	return result, nil
+}
+
+// shouldQueueBackup checks if a backup waiting to be started needs to be
+// queued, given every other backup known to the operator. A limit which is
+// not positive means that the number of concurrent backups is not limited.
+//
+// The backups queued before the passed one are counted only if they can be
+// started themselves: a backup held back by the limit of its namespace
+// doesn't prevent the backups of the other namespaces from being started
+func shouldQueueBackup(
+	backup *apiv1.Backup,
+	backups []apiv1.Backup,
+	maxBackups int,
+	maxBackupsPerNamespace int,
+) bool {
+	var busy int
+	busyInNamespace := make(map[string]int)
+	canStart := func(namespace string) bool {
+		return (maxBackups <= 0 || busy < maxBackups) &&
+			(maxBackupsPerNamespace <= 0 || busyInNamespace[namespace] < maxBackupsPerNamespace)
+	}
+
+	var queuedBefore []*apiv1.Backup
+	for idx := range backups {
+		item := &backups[idx]
+		if item.Namespace == backup.Namespace && item.Name == backup.Name {
+			continue
+		}
+
+		switch {
+		case isBackupRunning(item):
+			busy++
+			busyInNamespace[item.Namespace]++
+		case isBackupQueuedBefore(item, backup):
+			queuedBefore = append(queuedBefore, item)
+		}
+	}
+
+	slices.SortFunc(queuedBefore, func(a, b *apiv1.Backup) int {
+		switch {
+		case isBackupQueuedBefore(a, b):
+			return -1
+		case isBackupQueuedBefore(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+	for _, queued := range queuedBefore {
+		if canStart(queued.Namespace) {
+			busy++
+			busyInNamespace[queued.Namespace]++
+		}
+	}
+
+	return !canStart(backup.Namespace)
+}
+
+// isBackupWaitingToStart checks if a backup has not been started yet
+func isBackupWaitingToStart(backup *apiv1.Backup) bool {
+	switch backup.Status.Phase {
+	case "", apiv1.BackupPhasePending, apiv1.BackupPhaseQueued:
+		return true
+	default:
+		return false
+	}
+}
+
+// isBackupRunning checks if a backup has been started and is not done yet,
+// including the export of a completed backup to the archive tier
+func isBackupRunning(backup *apiv1.Backup) bool {
+	if backup.Status.IsArchiveTierExportRunning() {
+		return true
+	}
+
+	switch backup.Status.Phase {
+	case apiv1.BackupPhaseStarted, apiv1.BackupPhaseRunning, apiv1.BackupPhaseFinalizing:
+		return true
+	default:
+		return false
+	}
+}
+
+// isBackupQueuedBefore checks if a queued backup is going
+// to be started before the passed one
+func isBackupQueuedBefore(queued *apiv1.Backup, backup *apiv1.Backup) bool {
+	if queued.Status.Phase != apiv1.BackupPhaseQueued {
+		return false
+	}
+
+	if !queued.CreationTimestamp.Equal(&backup.CreationTimestamp) {
+		return queued.CreationTimestamp.Before(&backup.CreationTimestamp)
+	}
+
+	if queued.Namespace != backup.Namespace {
+		return queued.Namespace < backup.Namespace
+	}
+
+	return queued.Name < backup.Name
+}
+
+// reconcileArchiveTierExport checks the export of a completed backup to the
+// archive tier, which is run by the instance manager that took the backup.
+// The export is flagged as failed when that instance has been restarted,
+// as it would otherwise be counted among the running backups forever
+func (r *BackupReconciler) reconcileArchiveTierExport(
+	ctx context.Context,
+	backup *apiv1.Backup,
+) (ctrl.Result, error) {
+	if !backup.Status.IsArchiveTierExportRunning() {
+		return ctrl.Result{}, nil
+	}
+
+	if backup.Status.InstanceID != nil {
+		var pod corev1.Pod
+		err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Status.InstanceID.PodName}, &pod)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err == nil && utils.IsPodActive(pod) &&
+			specs.GetPostgresContainerID(pod) == backup.Status.InstanceID.ContainerID {
+			return ctrl.Result{RequeueAfter: archiveTierExportCheckInterval}, nil
+		}
+	}
+
+	const message = "the instance exporting the backup has been restarted"
+	log.FromContext(ctx).Warning("Export to the archive tier interrupted", "reason", message)
+	r.Recorder.Event(backup, "Warning", "ArchiveTierExportFailed", "Export to the archive tier failed: "+message)
+
+	origBackup := backup.DeepCopy()
+	backup.Status.ArchiveTierExport.Phase = apiv1.BackupArchiveTierExportPhaseFailed
+	backup.Status.ArchiveTierExport.StoppedAt = ptr.To(metav1.Now())
+	backup.Status.ArchiveTierExport.Message = message
+	return ctrl.Result{}, r.Status().Patch(ctx, backup, client.MergeFrom(origBackup))
+}
@@ -0,0 +1,259 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup concurrency", func() {
+	now := time.Now()
+
+	newBackup := func(namespace, name string, phase apiv1.BackupPhase, age time.Duration) apiv1.Backup {
+		return apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         namespace,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Status: apiv1.BackupStatus{Phase: phase},
+		}
+	}
+
+	Context("shouldQueueBackup", func() {
+		It("never queues a backup without limits", func() {
+			backup := newBackup("default", "backup", "", 0)
+			backups := []apiv1.Backup{
+				newBackup("default", "running", apiv1.BackupPhaseRunning, time.Hour),
+			}
+			Expect(shouldQueueBackup(&backup, backups, 0, 0)).To(BeFalse())
+		})
+
+		It("queues a backup when the operator limit is reached", func() {
+			backup := newBackup("default", "backup", "", 0)
+			backups := []apiv1.Backup{
+				backup,
+				newBackup("other", "running", apiv1.BackupPhaseRunning, time.Hour),
+				newBackup("default", "completed", apiv1.BackupPhaseCompleted, time.Hour),
+			}
+			Expect(shouldQueueBackup(&backup, backups, 1, 0)).To(BeTrue())
+			Expect(shouldQueueBackup(&backup, backups, 2, 0)).To(BeFalse())
+		})
+
+		It("queues a backup when the namespace limit is reached", func() {
+			backup := newBackup("default", "backup", "", 0)
+			backups := []apiv1.Backup{
+				newBackup("other", "running", apiv1.BackupPhaseStarted, time.Hour),
+			}
+			Expect(shouldQueueBackup(&backup, backups, 0, 1)).To(BeFalse())
+
+			backups = append(backups, newBackup("default", "finalizing", apiv1.BackupPhaseFinalizing, time.Hour))
+			Expect(shouldQueueBackup(&backup, backups, 0, 1)).To(BeTrue())
+		})
+
+		It("starts the queued backups in the order they have been created", func() {
+			older := newBackup("default", "older", apiv1.BackupPhaseQueued, 2*time.Hour)
+			newer := newBackup("default", "newer", apiv1.BackupPhaseQueued, time.Hour)
+			backups := []apiv1.Backup{older, newer}
+
+			Expect(shouldQueueBackup(&older, backups, 1, 0)).To(BeFalse())
+			Expect(shouldQueueBackup(&newer, backups, 1, 0)).To(BeTrue())
+		})
+
+		It("doesn't count the queued backups held back by the limit of their namespace", func() {
+			backup := newBackup("other", "backup", "", 0)
+			backups := []apiv1.Backup{
+				newBackup("default", "running", apiv1.BackupPhaseRunning, 3*time.Hour),
+				newBackup("default", "queued-1", apiv1.BackupPhaseQueued, 2*time.Hour),
+				newBackup("default", "queued-2", apiv1.BackupPhaseQueued, time.Hour),
+			}
+			Expect(shouldQueueBackup(&backup, backups, 2, 1)).To(BeFalse())
+
+			backups = append(backups, newBackup("third", "queued", apiv1.BackupPhaseQueued, time.Hour))
+			Expect(shouldQueueBackup(&backup, backups, 2, 1)).To(BeTrue())
+		})
+
+		It("counts the completed backups being exported to the archive tier", func() {
+			backup := newBackup("default", "backup", "", 0)
+			exporting := newBackup("default", "exporting", apiv1.BackupPhaseCompleted, time.Hour)
+			exporting.Status.ArchiveTierExport = &apiv1.BackupArchiveTierExportStatus{
+				Phase: apiv1.BackupArchiveTierExportPhaseRunning,
+			}
+			Expect(shouldQueueBackup(&backup, []apiv1.Backup{exporting}, 1, 0)).To(BeTrue())
+
+			exporting.Status.ArchiveTierExport.Phase = apiv1.BackupArchiveTierExportPhaseCompleted
+			Expect(shouldQueueBackup(&backup, []apiv1.Backup{exporting}, 1, 0)).To(BeFalse())
+		})
+	})
+
+	Context("reconcileBackupQueue", func() {
+		var r *BackupReconciler
+
+		BeforeEach(func() {
+			configuration.Current = configuration.NewConfiguration()
+			DeferCleanup(func() {
+				configuration.Current = configuration.NewConfiguration()
+			})
+		})
+
+		It("lets the backup start when the limit is not reached", func(ctx SpecContext) {
+			configuration.Current.MaxConcurrentBackups = 2
+			running := newBackup("default", "running", apiv1.BackupPhaseRunning, time.Hour)
+			backup := newBackup("default", "backup", "", 0)
+			r = newFakeBackupReconciler(&running, &backup)
+
+			res, err := r.reconcileBackupQueue(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+			Expect(backup.Status.Phase).To(BeEmpty())
+		})
+
+		It("queues the backup when the limit is reached", func(ctx SpecContext) {
+			configuration.Current.MaxConcurrentBackupsPerNamespace = 1
+			running := newBackup("default", "running", apiv1.BackupPhaseRunning, time.Hour)
+			backup := newBackup("default", "backup", "", 0)
+			r = newFakeBackupReconciler(&running, &backup)
+
+			res, err := r.reconcileBackupQueue(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			Expect(res.RequeueAfter).To(Equal(backupQueueCheckInterval))
+
+			var stored apiv1.Backup
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(&backup), &stored)).To(Succeed())
+			Expect(stored.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseQueued))
+			Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("BackupQueued")))
+		})
+
+		It("lists the backups of the watched namespaces only", func(ctx SpecContext) {
+			configuration.Current.WatchNamespace = "default,other"
+			configuration.Current.MaxConcurrentBackups = 1
+			running := newBackup("other", "running", apiv1.BackupPhaseRunning, time.Hour)
+			backup := newBackup("default", "backup", "", 0)
+			r = newFakeBackupReconciler(&running, &backup)
+			r.APIReader = namespacedReader{Reader: r.Client}
+
+			res, err := r.reconcileBackupQueue(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).ToNot(BeNil())
+			Expect(backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseQueued))
+		})
+
+		It("ignores the backups which have already been started", func(ctx SpecContext) {
+			configuration.Current.MaxConcurrentBackups = 1
+			running := newBackup("default", "running", apiv1.BackupPhaseRunning, time.Hour)
+			backup := newBackup("default", "backup", apiv1.BackupPhaseRunning, 0)
+			r = newFakeBackupReconciler(&running, &backup)
+
+			res, err := r.reconcileBackupQueue(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+	})
+
+	Context("reconcileArchiveTierExport", func() {
+		var (
+			r       *BackupReconciler
+			backup  apiv1.Backup
+			podName = "cluster-example-1"
+		)
+
+		newPod := func(containerID string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: podName},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: specs.PostgresContainerName, ContainerID: containerID},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			backup = newBackup("default", "backup", apiv1.BackupPhaseCompleted, time.Hour)
+			backup.Status.InstanceID = &apiv1.InstanceID{PodName: podName, ContainerID: "containerd://1"}
+			backup.Status.ArchiveTierExport = &apiv1.BackupArchiveTierExportStatus{
+				Phase: apiv1.BackupArchiveTierExportPhaseRunning,
+			}
+		})
+
+		It("waits for the export while the instance is running", func(ctx SpecContext) {
+			r = newFakeBackupReconciler(&backup, newPod("containerd://1"))
+
+			res, err := r.reconcileArchiveTierExport(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(archiveTierExportCheckInterval))
+			Expect(backup.Status.IsArchiveTierExportRunning()).To(BeTrue())
+		})
+
+		It("fails the export when the instance has been restarted", func(ctx SpecContext) {
+			r = newFakeBackupReconciler(&backup, newPod("containerd://2"))
+
+			res, err := r.reconcileArchiveTierExport(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeZero())
+
+			var stored apiv1.Backup
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(&backup), &stored)).To(Succeed())
+			Expect(stored.Status.ArchiveTierExport.Phase).To(Equal(apiv1.BackupArchiveTierExportPhaseFailed))
+			Expect(stored.Status.ArchiveTierExport.StoppedAt).ToNot(BeNil())
+		})
+
+		It("fails the export when the instance doesn't exist anymore", func(ctx SpecContext) {
+			r = newFakeBackupReconciler(&backup)
+
+			_, err := r.reconcileArchiveTierExport(ctx, &backup)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backup.Status.ArchiveTierExport.Phase).To(Equal(apiv1.BackupArchiveTierExportPhaseFailed))
+		})
+	})
+})
+
+// namespacedReader is a client.Reader rejecting the lists which are not
+// restricted to a namespace, as the API server does for an operator
+// without the permission to list the objects of the whole Kubernetes cluster
+type namespacedReader struct {
+	client.Reader
+}
+
+func (r namespacedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var listOptions client.ListOptions
+	listOptions.ApplyOptions(opts)
+	if listOptions.Namespace == "" {
+		return apierrs.NewForbidden(schema.GroupResource{Group: apiv1.GroupVersion.Group, Resource: "backups"},
+			"", fmt.Errorf("cluster-wide list is not allowed"))
+	}
+
+	return r.Reader.List(ctx, list, opts...)
+}
@@ -65,6 +65,10 @@ type BackupReconciler struct {
 	client.Client
 	DiscoveryClient discovery.DiscoveryInterface
 
+	// APIReader reads the objects bypassing the cache
+	// of the client, which may not be up to date
+	APIReader client.Reader
+
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
@@ -76,6 +80,7 @@ func NewBackupReconciler(mgr manager.Manager, discoveryClient *discovery.Discove
 	return &BackupReconciler{
 		Client:               mgr.GetClient(),
 		DiscoveryClient:      discoveryClient,
+		APIReader:            mgr.GetAPIReader(),
 		Scheme:               mgr.GetScheme(),
 		Recorder:             mgr.GetEventRecorderFor("cloudnative-pg-backup"),
 		instanceStatusClient: instance.NewStatusClient(),
@@ -106,7 +111,8 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	switch backup.Status.Phase {
 	case apiv1.BackupPhaseFailed, apiv1.BackupPhaseCompleted:
-		return ctrl.Result{}, nil
+		recordBackupMetrics(&backup)
+		return r.reconcileArchiveTierExport(ctx, &backup)
 	}
 
 	clusterName := backup.Spec.Cluster.Name
@@ -159,6 +165,14 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	res, err := r.reconcileBackupQueue(ctx, &backup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if res != nil {
+		return *res, nil
+	}
+
 	if backup.Spec.Method == apiv1.BackupMethodBarmanObjectStore {
 		if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 			tryFlagBackupAsFailed(ctx, r.Client, &backup,
@@ -298,8 +312,9 @@ func (r *BackupReconciler) isValidBackupRunning(
 		return false, fmt.Errorf("unknown.spec.target received: %s", backup.Spec.Target)
 	}
 
-	containerIsNotRestarted := utils.PodHasContainerStatuses(pod) &&
-		backup.Status.InstanceID.ContainerID == pod.Status.ContainerStatuses[0].ContainerID
+	postgresContainerID := specs.GetPostgresContainerID(pod)
+	containerIsNotRestarted := postgresContainerID != "" &&
+		backup.Status.InstanceID.ContainerID == postgresContainerID
 	isPodActive := utils.IsPodActive(pod)
 	if isCorrectPodElected && containerIsNotRestarted && isPodActive {
 		contextLogger.Info("Backup is already running on",
@@ -378,20 +393,12 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 		return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
-	if !utils.PodHasContainerStatuses(*targetPod) {
-		return nil, fmt.Errorf("target pod lacks container statuses")
+	postgresContainerID := specs.GetPostgresContainerID(*targetPod)
+	if postgresContainerID == "" {
+		return nil, fmt.Errorf("target pod lacks the PostgreSQL container status")
 	}
 
-	if len(backup.Status.Phase) == 0 || backup.Status.Phase == apiv1.BackupPhasePending {
-		backup.Status.SetAsStarted(
-			targetPod.Name,
-			targetPod.Status.ContainerStatuses[0].ContainerID,
-			apiv1.BackupMethodVolumeSnapshot,
-		)
-		// given that we use only kubernetes resources we can use the backup name as ID
-		backup.Status.BackupID = backup.Name
-		backup.Status.BackupName = backup.Name
-		backup.Status.StartedAt = ptr.To(metav1.Now())
+	if setSnapshotBackupAsStarted(backup, targetPod.Name, postgresContainerID) {
 		if err := postgres.PatchBackupStatusAndRetry(ctx, r.Client, backup); err != nil {
 			return nil, err
 		}
@@ -444,6 +451,23 @@ func (r *BackupReconciler) reconcileSnapshotBackup(
 	return nil, nil
 }
 
+// setSnapshotBackupAsStarted flags a volume snapshot backup as started on
+// the passed instance, unless it has already been started. A backup released
+// from the queue is started as well, otherwise it would not be counted among
+// the running ones and could be queued again
+func setSnapshotBackupAsStarted(backup *apiv1.Backup, podName string, containerID string) bool {
+	if !isBackupWaitingToStart(backup) {
+		return false
+	}
+
+	backup.Status.SetAsStarted(podName, containerID, apiv1.BackupMethodVolumeSnapshot)
+	// given that we use only kubernetes resources we can use the backup name as ID
+	backup.Status.BackupID = backup.Name
+	backup.Status.BackupName = backup.Name
+	backup.Status.StartedAt = ptr.To(metav1.Now())
+	return true
+}
+
 func (r *BackupReconciler) getSnapshotTargetPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -582,7 +606,7 @@ func startInstanceManagerBackup(
 ) error {
 	// This backup has been started
 	status := backup.GetStatus()
-	status.SetAsStarted(pod.Name, pod.Status.ContainerStatuses[0].ContainerID, backup.Spec.Method)
+	status.SetAsStarted(pod.Name, specs.GetPostgresContainerID(*pod), backup.Spec.Method)
 
 	if err := postgres.PatchBackupStatusAndRetry(ctx, client, backup); err != nil {
 		return err
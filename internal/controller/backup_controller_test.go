@@ -28,6 +28,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -103,6 +104,7 @@ var _ = Describe("backup_controller barmanObjectStore unit tests", func() {
 				Phase: corev1.PodRunning,
 				ContainerStatuses: []corev1.ContainerStatus{
 					{
+						Name:        specs.PostgresContainerName,
 						ContainerID: containerID,
 					},
 				},
@@ -259,6 +261,38 @@ var _ = Describe("backup_controller volumeSnapshot unit tests", func() {
 	})
 })
 
+var _ = Describe("setSnapshotBackupAsStarted", func() {
+	DescribeTable("starts the backups waiting to be started",
+		func(phase string) {
+			backup := &apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+				Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhase(phase)},
+			}
+
+			Expect(setSnapshotBackupAsStarted(backup, "cluster-example-1", "container-id")).To(BeTrue())
+			Expect(backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseStarted))
+			Expect(backup.Status.BackupID).To(Equal("backup"))
+			Expect(backup.Status.StartedAt).ToNot(BeNil())
+			Expect(backup.Status.InstanceID.PodName).To(Equal("cluster-example-1"))
+			Expect(isBackupRunning(backup)).To(BeTrue())
+		},
+		Entry("new", ""),
+		Entry("pending", apiv1.BackupPhasePending),
+		Entry("released from the queue", apiv1.BackupPhaseQueued),
+	)
+
+	It("doesn't start again a running backup", func() {
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+			Status:     apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning},
+		}
+
+		Expect(setSnapshotBackupAsStarted(backup, "cluster-example-1", "container-id")).To(BeFalse())
+		Expect(backup.Status.Phase).To(BeEquivalentTo(apiv1.BackupPhaseRunning))
+		Expect(backup.Status.StartedAt).To(BeNil())
+	})
+})
+
 var _ = Describe("update snapshot backup metadata", func() {
 	var (
 		env           *testingEnvironment
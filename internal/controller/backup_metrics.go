@@ -0,0 +1,155 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+var (
+	// backupMetricsLabels are the labels of the metrics about the
+	// latest completed base backup of a cluster
+	backupMetricsLabels = []string{"namespace", "cluster", "method"}
+
+	// backupDurationSeconds is the duration of the latest completed
+	// base backup of each cluster
+	backupDurationSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup",
+		Name:      "last_duration_seconds",
+		Help:      "Duration of the latest completed base backup",
+	}, backupMetricsLabels)
+
+	// backupSizeBytes is the size of the latest completed base
+	// backup of each cluster
+	backupSizeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup",
+		Name:      "last_size_bytes",
+		Help:      "Size of the latest completed base backup",
+	}, backupMetricsLabels)
+
+	// backupThroughputBytesPerSecond is the average throughput of the
+	// latest completed base backup of each cluster
+	backupThroughputBytesPerSecond = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup",
+		Name:      "last_throughput_bytes_per_second",
+		Help:      "Average throughput of the latest completed base backup",
+	}, backupMetricsLabels)
+
+	// backupCompletionTimestampSeconds is the time when the latest
+	// base backup of each cluster has been completed
+	backupCompletionTimestampSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cnpg",
+		Subsystem: "backup",
+		Name:      "last_completion_timestamp_seconds",
+		Help:      "Time when the latest base backup has been completed, as seconds since the Unix epoch",
+	}, backupMetricsLabels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		backupDurationSeconds,
+		backupSizeBytes,
+		backupThroughputBytesPerSecond,
+		backupCompletionTimestampSeconds,
+	)
+}
+
+// backupMetricsKey identifies the metrics about the base backups
+// taken with a method for a cluster
+type backupMetricsKey struct {
+	namespace string
+	cluster   string
+	method    apiv1.BackupMethod
+}
+
+// recordedBackups keeps the completion time of the backups whose
+// metrics have been recorded, as the completed backups are reconciled
+// in any order
+var recordedBackups = struct {
+	sync.Mutex
+	completedAt map[backupMetricsKey]time.Time
+}{completedAt: make(map[backupMetricsKey]time.Time)}
+
+// recordBackupMetrics records the metrics of a completed base backup,
+// unless a more recent backup of the same cluster taken with the same
+// method has already been recorded
+func recordBackupMetrics(backup *apiv1.Backup) {
+	if backup.Status.Phase != apiv1.BackupPhaseCompleted ||
+		backup.Status.StartedAt == nil || backup.Status.StoppedAt == nil {
+		return
+	}
+
+	method := backup.Status.Method
+	if method == "" {
+		method = backup.Spec.Method
+	}
+	key := backupMetricsKey{namespace: backup.Namespace, cluster: backup.Spec.Cluster.Name, method: method}
+	completedAt := backup.Status.StoppedAt.Time
+
+	recordedBackups.Lock()
+	defer recordedBackups.Unlock()
+	if lastCompletedAt, ok := recordedBackups.completedAt[key]; ok && !completedAt.After(lastCompletedAt) {
+		return
+	}
+	recordedBackups.completedAt[key] = completedAt
+
+	labels := []string{key.namespace, key.cluster, string(key.method)}
+	duration := max(completedAt.Sub(backup.Status.StartedAt.Time), 0)
+	backupDurationSeconds.WithLabelValues(labels...).Set(duration.Seconds())
+	backupCompletionTimestampSeconds.WithLabelValues(labels...).Set(float64(completedAt.Unix()))
+
+	// A backup whose size is not known must not be compared
+	// with the size of the previous ones
+	if backup.Status.Size <= 0 {
+		backupSizeBytes.DeleteLabelValues(labels...)
+		backupThroughputBytesPerSecond.DeleteLabelValues(labels...)
+		return
+	}
+
+	backupSizeBytes.WithLabelValues(labels...).Set(float64(backup.Status.Size))
+	if duration > 0 {
+		backupThroughputBytesPerSecond.WithLabelValues(labels...).Set(float64(backup.Status.Size) / duration.Seconds())
+	} else {
+		backupThroughputBytesPerSecond.DeleteLabelValues(labels...)
+	}
+}
+
+// forgetBackupMetrics removes the backup metrics of a deleted cluster
+func forgetBackupMetrics(namespace, name string) {
+	clusterLabels := prometheus.Labels{"namespace": namespace, "cluster": name}
+	backupDurationSeconds.DeletePartialMatch(clusterLabels)
+	backupSizeBytes.DeletePartialMatch(clusterLabels)
+	backupThroughputBytesPerSecond.DeletePartialMatch(clusterLabels)
+	backupCompletionTimestampSeconds.DeletePartialMatch(clusterLabels)
+
+	recordedBackups.Lock()
+	defer recordedBackups.Unlock()
+	for key := range recordedBackups.completedAt {
+		if key.namespace == namespace && key.cluster == name {
+			delete(recordedBackups.completedAt, key)
+		}
+	}
+}
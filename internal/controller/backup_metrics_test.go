@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup metrics", func() {
+	const (
+		namespace   = "default"
+		clusterName = "cluster-backup-metrics"
+	)
+
+	startedAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	labels := []string{namespace, clusterName, string(apiv1.BackupMethodBarmanObjectStore)}
+
+	buildBackup := func(duration time.Duration, size int64) *apiv1.Backup {
+		return &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: clusterName},
+				Method:  apiv1.BackupMethodBarmanObjectStore,
+			},
+			Status: apiv1.BackupStatus{
+				Phase:     apiv1.BackupPhaseCompleted,
+				StartedAt: &metav1.Time{Time: startedAt},
+				StoppedAt: &metav1.Time{Time: startedAt.Add(duration)},
+				Size:      size,
+			},
+		}
+	}
+
+	countSeries := func() int {
+		return testutil.CollectAndCount(backupDurationSeconds) +
+			testutil.CollectAndCount(backupSizeBytes) +
+			testutil.CollectAndCount(backupThroughputBytesPerSecond) +
+			testutil.CollectAndCount(backupCompletionTimestampSeconds)
+	}
+
+	BeforeEach(func() {
+		DeferCleanup(forgetBackupMetrics, namespace, clusterName)
+	})
+
+	It("records the duration, the size and the throughput of a completed backup", func() {
+		recordBackupMetrics(buildBackup(100*time.Second, 1000))
+
+		Expect(testutil.ToFloat64(backupDurationSeconds.WithLabelValues(labels...))).To(BeEquivalentTo(100))
+		Expect(testutil.ToFloat64(backupSizeBytes.WithLabelValues(labels...))).To(BeEquivalentTo(1000))
+		Expect(testutil.ToFloat64(backupThroughputBytesPerSecond.WithLabelValues(labels...))).To(BeEquivalentTo(10))
+		Expect(testutil.ToFloat64(backupCompletionTimestampSeconds.WithLabelValues(labels...))).
+			To(BeEquivalentTo(startedAt.Add(100 * time.Second).Unix()))
+	})
+
+	It("ignores the backups which are not completed or older than the recorded one", func() {
+		failed := buildBackup(10*time.Second, 1000)
+		failed.Status.Phase = apiv1.BackupPhaseFailed
+		recordBackupMetrics(failed)
+		Expect(countSeries()).To(BeZero())
+
+		recordBackupMetrics(buildBackup(100*time.Second, 1000))
+		recordBackupMetrics(buildBackup(50*time.Second, 2000))
+		Expect(testutil.ToFloat64(backupDurationSeconds.WithLabelValues(labels...))).To(BeEquivalentTo(100))
+		Expect(testutil.ToFloat64(backupSizeBytes.WithLabelValues(labels...))).To(BeEquivalentTo(1000))
+	})
+
+	It("doesn't report the size and the throughput when the size is unknown", func() {
+		recordBackupMetrics(buildBackup(100*time.Second, 1000))
+		recordBackupMetrics(buildBackup(200*time.Second, 0))
+
+		Expect(testutil.ToFloat64(backupDurationSeconds.WithLabelValues(labels...))).To(BeEquivalentTo(200))
+		Expect(testutil.CollectAndCount(backupSizeBytes)).To(BeZero())
+		Expect(testutil.CollectAndCount(backupThroughputBytesPerSecond)).To(BeZero())
+	})
+
+	It("forgets the metrics of a deleted cluster", func() {
+		recordBackupMetrics(buildBackup(100*time.Second, 1000))
+		Expect(countSeries()).To(Equal(4))
+
+		forgetBackupMetrics(namespace, clusterName)
+		Expect(countSeries()).To(BeZero())
+
+		// A backup completed before the latest one is recorded again
+		// once the metrics have been forgotten
+		recordBackupMetrics(buildBackup(50*time.Second, 1000))
+		Expect(testutil.ToFloat64(backupDurationSeconds.WithLabelValues(labels...))).To(BeEquivalentTo(50))
+	})
+})
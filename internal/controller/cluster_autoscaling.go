@@ -0,0 +1,299 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+const (
+	// replicaAutoscalingCheckInterval is the longest time between two
+	// evaluations of the active connections of the replicas
+	replicaAutoscalingCheckInterval = 30 * time.Second
+
+	// replicaAutoscalingDrainCheckInterval is the time between two checks
+	// of the active connections of the replica being drained
+	replicaAutoscalingDrainCheckInterval = 5 * time.Second
+)
+
+// reconcileReplicaAutoscaling adjusts the number of instances to the
+// active client connections of the replicas, within the configured bounds.
+// The number of instances is written in the status, leaving the
+// specification to the user, and it is then reconciled as usual.
+// Replicas are added by the usual scale up procedure, while they are removed
+// one at a time, after their connections have been drained.
+// It returns the time after which the active connections need to be
+// evaluated again, or zero if the automatic scaling is not enabled
+func (r *ClusterReconciler) reconcileReplicaAutoscaling(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx).WithName("replica_autoscaling")
+
+	if cluster.Spec.ReplicaAutoscaling == nil {
+		if cluster.Status.ReplicaAutoscaling == nil {
+			return 0, nil
+		}
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ReplicaAutoscaling = nil
+		return 0, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	// We don't change the topology of the cluster during a
+	// switchover or a failover
+	if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		return replicaAutoscalingCheckInterval, nil
+	}
+
+	// The instance being removed is still there, we need
+	// to wait for the scale down to complete
+	instances := cluster.GetInstances()
+	if cluster.Status.Instances > instances {
+		return replicaAutoscalingDrainCheckInterval, nil
+	}
+
+	desiredInstances, activeConnections := getReplicaAutoscalingDesiredInstances(cluster, instancesStatus)
+	contextLogger.Debug("Evaluated the active connections of the replicas",
+		"activeConnections", activeConnections,
+		"instances", instances,
+		"desiredInstances", desiredInstances)
+
+	if cluster.Status.ReplicaAutoscaling.GetDrainingInstance() != "" {
+		return r.drainAutoscalingReplica(ctx, cluster, instancesStatus, desiredInstances)
+	}
+
+	now := time.Now()
+	autoscalingStatus := cluster.Status.ReplicaAutoscaling
+	if autoscalingStatus == nil {
+		autoscalingStatus = &apiv1.ReplicaAutoscalingStatus{}
+	}
+
+	switch {
+	case desiredInstances > instances:
+		contextLogger.Info("Scaling up the cluster",
+			"activeConnections", activeConnections,
+			"instances", instances,
+			"desiredInstances", desiredInstances)
+		r.Recorder.Eventf(cluster, "Normal", "ReplicaAutoscaling",
+			"Scaling up from %d to %d instances, with %d active connections on the replicas",
+			instances, desiredInstances, activeConnections)
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{
+			DesiredInstances: desiredInstances,
+			LastScaleTime:    &metav1.Time{Time: now},
+		}
+		return replicaAutoscalingCheckInterval, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+
+	case desiredInstances < instances:
+		scaleDownDelay := cluster.Spec.ReplicaAutoscaling.GetScaleDownDelay()
+		if autoscalingStatus.ScaleDownPendingSince == nil {
+			origCluster := cluster.DeepCopy()
+			autoscalingStatus.DesiredInstances = instances
+			autoscalingStatus.ScaleDownPendingSince = &metav1.Time{Time: now}
+			cluster.Status.ReplicaAutoscaling = autoscalingStatus
+			return min(scaleDownDelay, replicaAutoscalingCheckInterval),
+				r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+		}
+
+		if elapsed := now.Sub(autoscalingStatus.ScaleDownPendingSince.Time); elapsed < scaleDownDelay {
+			return min(scaleDownDelay-elapsed, replicaAutoscalingCheckInterval), nil
+		}
+
+		instanceName := findReplicaAutoscalingDeletableInstance(cluster, instancesStatus)
+		if instanceName == "" {
+			contextLogger.Info("There are no replicas to be removed. Wait for the next sync loop")
+			return replicaAutoscalingCheckInterval, nil
+		}
+
+		contextLogger.Info("Draining the connections of a replica before removing it",
+			"instance", instanceName,
+			"activeConnections", activeConnections,
+			"instances", instances,
+			"desiredInstances", desiredInstances)
+		r.Recorder.Eventf(cluster, "Normal", "ReplicaAutoscaling",
+			"Draining the connections of %s before scaling down, with %d active connections on the replicas",
+			instanceName, activeConnections)
+
+		origCluster := cluster.DeepCopy()
+		autoscalingStatus.DrainingInstance = instanceName
+		autoscalingStatus.DrainStartedAt = &metav1.Time{Time: now}
+		cluster.Status.ReplicaAutoscaling = autoscalingStatus
+		return replicaAutoscalingDrainCheckInterval, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+
+	default:
+		if autoscalingStatus.ScaleDownPendingSince == nil {
+			return replicaAutoscalingCheckInterval, nil
+		}
+
+		origCluster := cluster.DeepCopy()
+		autoscalingStatus.ScaleDownPendingSince = nil
+		cluster.Status.ReplicaAutoscaling = autoscalingStatus
+		return replicaAutoscalingCheckInterval, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+}
+
+// drainAutoscalingReplica waits for the active connections of the replica
+// being drained to complete, or for the drain timeout to expire, and then
+// decreases the number of instances in the status. The replica has already been removed
+// from the `-ro` service, and the scale down will delete it
+func (r *ClusterReconciler) drainAutoscalingReplica(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	desiredInstances int,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx).WithName("replica_autoscaling")
+
+	autoscalingStatus := cluster.Status.ReplicaAutoscaling
+	drainingInstance := autoscalingStatus.DrainingInstance
+	instances := cluster.GetInstances()
+
+	var instanceStatus *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		if instancesStatus.Items[idx].Pod.Name == drainingInstance {
+			instanceStatus = &instancesStatus.Items[idx]
+			break
+		}
+	}
+
+	// The replica has been removed, or it's not a replica anymore:
+	// the drain is over
+	if instanceStatus == nil || drainingInstance == cluster.Status.CurrentPrimary {
+		contextLogger.Info("Replica drain completed", "instance", drainingInstance)
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ReplicaAutoscaling.DrainingInstance = ""
+		cluster.Status.ReplicaAutoscaling.DrainStartedAt = nil
+		return replicaAutoscalingCheckInterval, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	// The load went up again, so we keep the replica
+	if desiredInstances >= instances {
+		contextLogger.Info("Canceling the drain of the replica, the active connections went up",
+			"instance", drainingInstance)
+		r.Recorder.Eventf(cluster, "Normal", "ReplicaAutoscaling",
+			"Canceled the drain of %s, the active connections went up", drainingInstance)
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ReplicaAutoscaling.DrainingInstance = ""
+		cluster.Status.ReplicaAutoscaling.DrainStartedAt = nil
+		cluster.Status.ReplicaAutoscaling.ScaleDownPendingSince = nil
+		return replicaAutoscalingCheckInterval, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	drainTimeout := cluster.Spec.ReplicaAutoscaling.GetDrainTimeout()
+	var elapsed time.Duration
+	if autoscalingStatus.DrainStartedAt != nil {
+		elapsed = time.Since(autoscalingStatus.DrainStartedAt.Time)
+	}
+
+	if instanceStatus.HasHTTPStatus() && instanceStatus.ActiveConnections > 0 && elapsed < drainTimeout {
+		contextLogger.Info("Waiting for the active connections of the replica to complete",
+			"instance", drainingInstance,
+			"activeConnections", instanceStatus.ActiveConnections,
+			"elapsed", elapsed,
+			"drainTimeout", drainTimeout)
+		return min(drainTimeout-elapsed, replicaAutoscalingDrainCheckInterval), nil
+	}
+
+	if instanceStatus.ActiveConnections > 0 {
+		contextLogger.Warning("Drain timeout expired, removing the replica with active connections",
+			"instance", drainingInstance,
+			"activeConnections", instanceStatus.ActiveConnections)
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "ReplicaAutoscaling",
+		"Scaling down from %d to %d instances, removing %s",
+		instances, instances-1, drainingInstance)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ReplicaAutoscaling.DesiredInstances = instances - 1
+	cluster.Status.ReplicaAutoscaling.LastScaleTime = &metav1.Time{Time: time.Now()}
+	cluster.Status.ReplicaAutoscaling.ScaleDownPendingSince = nil
+	return replicaAutoscalingDrainCheckInterval, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getReplicaAutoscalingDesiredInstances gets the number of instances
+// required by the active client connections of the replicas, within the
+// configured bounds, together with the number of those connections.
+// The primary and the delayed replicas don't serve the `-ro` service,
+// so they are not counted as serving replicas
+func getReplicaAutoscalingDesiredInstances(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (int, int) {
+	configuration := cluster.Spec.ReplicaAutoscaling
+
+	activeConnections := 0
+	otherInstances := 0
+	for _, item := range instancesStatus.Items {
+		switch {
+		case item.Pod.Name == cluster.Status.CurrentPrimary || item.IsDelayedReplica:
+			otherInstances++
+		case item.HasHTTPStatus():
+			activeConnections += item.ActiveConnections
+		}
+	}
+
+	targetActiveConnections := int(configuration.TargetActiveConnections)
+	desiredReplicas := (activeConnections + targetActiveConnections - 1) / targetActiveConnections
+	desiredInstances := min(max(desiredReplicas+otherInstances, configuration.MinInstances), configuration.MaxInstances)
+
+	return desiredInstances, activeConnections
+}
+
+// findReplicaAutoscalingDeletableInstance gets the serving replica with the
+// highest serial, which is the one to be removed when scaling down.
+// Delayed replicas are never removed, as they are not serving the
+// active connections
+func findReplicaAutoscalingDeletableInstance(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) string {
+	var result string
+	var lastFoundSerial int
+
+	for _, item := range instancesStatus.Items {
+		if item.Pod.Name == cluster.Status.CurrentPrimary || item.IsDelayedReplica || !item.HasHTTPStatus() {
+			continue
+		}
+
+		podSerial, err := specs.GetNodeSerial(item.Pod.ObjectMeta)
+		if err != nil {
+			continue
+		}
+
+		if podSerial > lastFoundSerial {
+			result = item.Pod.Name
+			lastFoundSerial = podSerial
+		}
+	}
+
+	return result
+}
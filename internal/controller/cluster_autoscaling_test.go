@@ -0,0 +1,319 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replica autoscaling", func() {
+	var (
+		r               ClusterReconciler
+		cluster         *apiv1.Cluster
+		instancesStatus postgres.PostgresqlStatusList
+	)
+
+	buildStatus := func(serial int, activeConnections int) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("cluster-example-%d", serial),
+					Namespace: "default",
+					Annotations: map[string]string{
+						utils.ClusterSerialAnnotationName: strconv.Itoa(serial),
+					},
+				},
+			},
+			IsPrimary:         serial == 1,
+			ActiveConnections: activeConnections,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ReplicaAutoscaling: &apiv1.ReplicaAutoscalingConfiguration{
+					MinInstances:            2,
+					MaxInstances:            5,
+					TargetActiveConnections: 10,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Instances:      3,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus(1, 50),
+				buildStatus(2, 8),
+				buildStatus(3, 7),
+			},
+		}
+	})
+
+	getCluster := func(ctx SpecContext) *apiv1.Cluster {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return &updatedCluster
+	}
+
+	Context("getReplicaAutoscalingDesiredInstances", func() {
+		It("counts only the active connections of the serving replicas", func() {
+			desiredInstances, activeConnections := getReplicaAutoscalingDesiredInstances(cluster, instancesStatus)
+			Expect(activeConnections).To(Equal(15))
+			Expect(desiredInstances).To(Equal(3))
+		})
+
+		It("adds a replica when the average exceeds the target", func() {
+			instancesStatus.Items[2].ActiveConnections = 13
+			desiredInstances, _ := getReplicaAutoscalingDesiredInstances(cluster, instancesStatus)
+			Expect(desiredInstances).To(Equal(4))
+		})
+
+		It("keeps the delayed replicas out of the serving ones", func() {
+			instancesStatus.Items[2].IsDelayedReplica = true
+			instancesStatus.Items[2].ActiveConnections = 30
+			desiredInstances, activeConnections := getReplicaAutoscalingDesiredInstances(cluster, instancesStatus)
+			Expect(activeConnections).To(Equal(8))
+			Expect(desiredInstances).To(Equal(3))
+		})
+
+		It("stays within the configured bounds", func() {
+			instancesStatus.Items[1].ActiveConnections = 0
+			instancesStatus.Items[2].ActiveConnections = 0
+			desiredInstances, _ := getReplicaAutoscalingDesiredInstances(cluster, instancesStatus)
+			Expect(desiredInstances).To(Equal(2))
+
+			instancesStatus.Items[1].ActiveConnections = 100
+			desiredInstances, _ = getReplicaAutoscalingDesiredInstances(cluster, instancesStatus)
+			Expect(desiredInstances).To(Equal(5))
+		})
+	})
+
+	Context("findReplicaAutoscalingDeletableInstance", func() {
+		It("selects the serving replica with the highest serial", func() {
+			Expect(findReplicaAutoscalingDeletableInstance(cluster, instancesStatus)).To(Equal("cluster-example-3"))
+		})
+
+		It("never selects a delayed replica", func() {
+			instancesStatus.Items[2].IsDelayedReplica = true
+			Expect(findReplicaAutoscalingDeletableInstance(cluster, instancesStatus)).To(Equal("cluster-example-2"))
+		})
+	})
+
+	Context("reconcileReplicaAutoscaling", func() {
+		It("scales up the cluster right away", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 25
+			r = newFakeClusterReconciler(cluster)
+
+			checkInterval, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInterval).To(Equal(replicaAutoscalingCheckInterval))
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.Spec.Instances).To(Equal(3))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DesiredInstances).To(Equal(5))
+			Expect(updatedCluster.GetInstances()).To(Equal(5))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.LastScaleTime).ToNot(BeNil())
+		})
+
+		It("waits for the scale down delay before draining a replica", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 0
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.GetInstances()).To(Equal(3))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.ScaleDownPendingSince).ToNot(BeNil())
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainingInstance).To(BeEmpty())
+		})
+
+		It("drains a replica when the scale down delay has expired", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 0
+			cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{
+				ScaleDownPendingSince: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)},
+			}
+			r = newFakeClusterReconciler(cluster)
+
+			checkInterval, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInterval).To(Equal(replicaAutoscalingDrainCheckInterval))
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.GetInstances()).To(Equal(3))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainingInstance).To(Equal("cluster-example-3"))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainStartedAt).ToNot(BeNil())
+		})
+
+		It("forgets the pending scale down when the load goes up again", func(ctx SpecContext) {
+			cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{
+				ScaleDownPendingSince: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+			}
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCluster(ctx).Status.ReplicaAutoscaling.ScaleDownPendingSince).To(BeNil())
+		})
+
+		It("clears the status when the automatic scaling is disabled", func(ctx SpecContext) {
+			cluster.Spec.ReplicaAutoscaling = nil
+			cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{
+				LastScaleTime: &metav1.Time{Time: time.Now()},
+			}
+			r = newFakeClusterReconciler(cluster)
+
+			checkInterval, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInterval).To(BeZero())
+			Expect(getCluster(ctx).Status.ReplicaAutoscaling).To(BeNil())
+		})
+
+		It("doesn't act during a switchover", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 25
+			cluster.Status.TargetPrimary = "cluster-example-2"
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCluster(ctx).GetInstances()).To(Equal(3))
+		})
+
+		It("starts from the desired number of instances in the status", func(ctx SpecContext) {
+			cluster.Spec.Instances = 2
+			cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{DesiredInstances: 3}
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.Spec.Instances).To(Equal(2))
+			Expect(updatedCluster.GetInstances()).To(Equal(3))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.ScaleDownPendingSince).To(BeNil())
+		})
+	})
+
+	Context("drainAutoscalingReplica", func() {
+		BeforeEach(func() {
+			instancesStatus.Items[1].ActiveConnections = 5
+			cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{
+				ScaleDownPendingSince: &metav1.Time{Time: time.Now().Add(-10 * time.Minute)},
+				DrainingInstance:      "cluster-example-3",
+				DrainStartedAt:        &metav1.Time{Time: time.Now()},
+			}
+		})
+
+		It("waits for the active connections of the replica to complete", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 2
+			r = newFakeClusterReconciler(cluster)
+
+			checkInterval, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInterval).To(Equal(replicaAutoscalingDrainCheckInterval))
+			Expect(getCluster(ctx).GetInstances()).To(Equal(3))
+		})
+
+		It("scales down when the replica has been drained", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 0
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.Spec.Instances).To(Equal(3))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DesiredInstances).To(Equal(2))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainingInstance).To(Equal("cluster-example-3"))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.LastScaleTime).ToNot(BeNil())
+		})
+
+		It("scales down when the drain timeout has expired", func(ctx SpecContext) {
+			instancesStatus.Items[2].ActiveConnections = 2
+			cluster.Spec.ReplicaAutoscaling.DrainTimeout = ptr.To(int32(30))
+			cluster.Status.ReplicaAutoscaling.DrainStartedAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getCluster(ctx).GetInstances()).To(Equal(2))
+		})
+
+		It("cancels the drain when the load goes up again", func(ctx SpecContext) {
+			instancesStatus.Items[1].ActiveConnections = 15
+			instancesStatus.Items[2].ActiveConnections = 2
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.GetInstances()).To(Equal(3))
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainingInstance).To(BeEmpty())
+			Expect(updatedCluster.Status.ReplicaAutoscaling.ScaleDownPendingSince).To(BeNil())
+		})
+
+		It("completes the drain when the replica has been removed", func(ctx SpecContext) {
+			cluster.Status.ReplicaAutoscaling.DesiredInstances = 2
+			cluster.Status.Instances = 2
+			instancesStatus.Items = instancesStatus.Items[:2]
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedCluster := getCluster(ctx)
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainingInstance).To(BeEmpty())
+			Expect(updatedCluster.Status.ReplicaAutoscaling.DrainStartedAt).To(BeNil())
+		})
+	})
+
+	Context("getDrainedInstance", func() {
+		It("prefers the drained replica in the scale down", func() {
+			cluster.Status.ReplicaAutoscaling = &apiv1.ReplicaAutoscalingStatus{
+				DrainingInstance: "cluster-example-2",
+			}
+			pods := []corev1.Pod{*instancesStatus.Items[1].Pod, *instancesStatus.Items[2].Pod}
+			Expect(getDrainedInstance(cluster, pods)).To(Equal("cluster-example-2"))
+			Expect(getDrainedInstance(cluster, pods[1:])).To(BeEmpty())
+		})
+	})
+})
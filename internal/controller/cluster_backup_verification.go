@@ -0,0 +1,242 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// errBackupVerificationJobFailed is used when the verification job failed
+// without being able to register the outcome by itself
+var errBackupVerificationJobFailed = fmt.Errorf("backup verification job failed")
+
+// reconcileBackupVerification creates the job that verifies the latest
+// base backup, periodically or as soon as a new backup is completed, and
+// collects its outcome once it is finished
+func (r *ClusterReconciler) reconcileBackupVerification(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	var job batchv1.Job
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetBackupVerificationJobName()},
+		&job)
+	switch {
+	case err == nil:
+		return ctrl.Result{}, r.reconcileBackupVerificationJob(ctx, cluster, &job)
+	case !apierrs.IsNotFound(err):
+		return ctrl.Result{}, err
+	}
+
+	afterBackup := cluster.Spec.Backup.IsBackupVerificationAfterBackupEnabled()
+	if !cluster.Spec.Backup.IsBackupVerificationEnabled() && !afterBackup {
+		return ctrl.Result{}, nil
+	}
+
+	// There's nothing to verify until we have a successful backup, and
+	// we don't want to add load to a cluster that is not healthy
+	lastBackupTime, ok := cluster.Status.LastSuccessfulBackupByMethod[apiv1.BackupMethodBarmanObjectStore]
+	if !ok || cluster.Status.Phase != apiv1.PhaseHealthy {
+		return ctrl.Result{}, nil
+	}
+
+	// A backup completed after the start of the last verification
+	// has not been verified yet
+	if afterBackup && lastBackupTime.After(getLastBackupVerificationTime(cluster)) {
+		return ctrl.Result{}, r.createBackupVerificationJob(ctx, cluster)
+	}
+
+	if !cluster.Spec.Backup.IsBackupVerificationEnabled() {
+		return ctrl.Result{}, nil
+	}
+
+	schedule, err := cron.Parse(cluster.Spec.Backup.GetBackupVerificationSchedule())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("while parsing the backup verification schedule: %w", err)
+	}
+
+	now := time.Now()
+	nextTime := schedule.Next(getLastBackupVerificationTime(cluster))
+	if now.Before(nextTime) {
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	return ctrl.Result{}, r.createBackupVerificationJob(ctx, cluster)
+}
+
+// getLastBackupVerificationTime gets the time used to compute when the
+// next verification is due
+func getLastBackupVerificationTime(cluster *apiv1.Cluster) time.Time {
+	if cluster.Status.LastBackupVerification != nil &&
+		cluster.Status.LastBackupVerification.StartedAt != nil {
+		return cluster.Status.LastBackupVerification.StartedAt.Time
+	}
+
+	return cluster.CreationTimestamp.Time
+}
+
+// createBackupVerificationJob creates the verification job, marking
+// the verification as running in the cluster status
+func (r *ClusterReconciler) createBackupVerificationJob(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	job := specs.CreateBackupVerificationJob(*cluster)
+	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Creating backup verification job", "name", job.Name)
+	if err := r.Create(ctx, job); err != nil {
+		if apierrs.IsAlreadyExists(err) {
+			// This Job was already created, maybe the cache is stale.
+			return nil
+		}
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	now := metav1.Now()
+	cluster.Status.LastBackupVerification = &apiv1.BackupVerificationStatus{
+		Phase:     apiv1.BackupVerificationPhaseRunning,
+		StartedAt: &now,
+	}
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(cluster, "Normal", "BackupVerificationStarted",
+		"Started the verification of the latest base backup")
+	return nil
+}
+
+// reconcileBackupVerificationJob waits for the verification job to be
+// finished, reports its outcome and removes it
+func (r *ClusterReconciler) reconcileBackupVerificationJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if !job.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	jobFailed := job.Status.Failed > 0
+	if !jobFailed && !utils.JobHasOneCompletion(*job) {
+		// The termination of the job will trigger a new reconciliation loop
+		return nil
+	}
+
+	status := cluster.Status.LastBackupVerification
+	if jobFailed && (status == nil || status.Phase == apiv1.BackupVerificationPhaseRunning) {
+		// The instance manager didn't record the result, i.e. because the
+		// Pod was killed. Let's do that on its behalf.
+		origCluster := cluster.DeepCopy()
+		now := metav1.Now()
+		if status == nil {
+			status = &apiv1.BackupVerificationStatus{}
+		}
+		status.Phase = apiv1.BackupVerificationPhaseFailed
+		status.StoppedAt = &now
+		status.Message = errBackupVerificationJobFailed.Error()
+		cluster.Status.LastBackupVerification = status
+		if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return err
+		}
+		if err := conditions.Patch(ctx, r.Client, cluster,
+			apiv1.BuildBackupVerificationFailedCondition(errBackupVerificationJobFailed)); err != nil {
+			return err
+		}
+	}
+
+	if status != nil && status.Phase == apiv1.BackupVerificationPhaseFailed {
+		r.Recorder.Eventf(cluster, "Warning", "BackupVerificationFailed",
+			"Verification of backup %q failed: %s", status.BackupID, status.Message)
+	} else if status != nil {
+		r.Recorder.Eventf(cluster, "Normal", "BackupVerificationSucceeded",
+			"Backup %q has been verified", status.BackupID)
+	}
+
+	if status != nil {
+		if err := r.reportBackupVerificationToBackup(ctx, cluster, status); err != nil {
+			return err
+		}
+	}
+
+	contextLogger.Info("Removing backup verification job", "name", job.Name)
+	background := metav1.DeletePropagationBackground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{
+		PropagationPolicy: &background,
+	}); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// reportBackupVerificationToBackup stores the outcome of the verification
+// in the status of the Backup having the verified backup ID, if any
+func (r *ClusterReconciler) reportBackupVerificationToBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status *apiv1.BackupVerificationStatus,
+) error {
+	if status.BackupID == "" {
+		return nil
+	}
+
+	var backupList apiv1.BackupList
+	if err := r.List(ctx, &backupList,
+		client.MatchingFields{clusterName: cluster.Name},
+		client.InNamespace(cluster.Namespace),
+	); err != nil {
+		return err
+	}
+
+	for idx := range backupList.Items {
+		backup := &backupList.Items[idx]
+		if backup.Status.BackupID != status.BackupID ||
+			backup.Status.Method != apiv1.BackupMethodBarmanObjectStore {
+			continue
+		}
+
+		origBackup := backup.DeepCopy()
+		backup.Status.Verification = status.DeepCopy()
+		if err := r.Status().Patch(ctx, backup, client.MergeFrom(origBackup)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
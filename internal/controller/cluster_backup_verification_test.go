@@ -0,0 +1,230 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup verification", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "cluster-example",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-30 * 24 * time.Hour)),
+			},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket/path",
+					},
+					Verification: &apiv1.BackupVerificationConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				Phase: apiv1.PhaseHealthy,
+				LastSuccessfulBackupByMethod: map[apiv1.BackupMethod]metav1.Time{
+					apiv1.BackupMethodBarmanObjectStore: metav1.Now(),
+				},
+			},
+		}
+	})
+
+	getJob := func(ctx SpecContext) (*batchv1.Job, error) {
+		var job batchv1.Job
+		err := r.Get(ctx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      cluster.GetBackupVerificationJobName(),
+		}, &job)
+		return &job, err
+	}
+
+	It("doesn't create any job when the verification is disabled", func(ctx SpecContext) {
+		cluster.Spec.Backup.Verification.Enabled = false
+		r = newFakeClusterReconciler(cluster)
+
+		res, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.IsZero()).To(BeTrue())
+
+		_, err = getJob(ctx)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("doesn't create any job when there is no backup to verify", func(ctx SpecContext) {
+		cluster.Status.LastSuccessfulBackupByMethod = nil
+		r = newFakeClusterReconciler(cluster)
+
+		_, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = getJob(ctx)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("waits for the next schedule", func(ctx SpecContext) {
+		now := metav1.Now()
+		cluster.Status.LastBackupVerification = &apiv1.BackupVerificationStatus{
+			Phase:     apiv1.BackupVerificationPhaseSucceeded,
+			StartedAt: &now,
+		}
+		r = newFakeClusterReconciler(cluster)
+
+		res, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+
+		_, err = getJob(ctx)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("creates the verification job when it is due", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		_, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		job, err := getJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(specs.IsBackupVerificationJob(*job)).To(BeTrue())
+		Expect(cluster.Status.LastBackupVerification).ToNot(BeNil())
+		Expect(cluster.Status.LastBackupVerification.Phase).To(Equal(apiv1.BackupVerificationPhaseRunning))
+	})
+
+	It("records the failure of a job that couldn't report it", func(ctx SpecContext) {
+		now := metav1.Now()
+		cluster.Status.LastBackupVerification = &apiv1.BackupVerificationStatus{
+			Phase:     apiv1.BackupVerificationPhaseRunning,
+			StartedAt: &now,
+		}
+		job := specs.CreateBackupVerificationJob(*cluster)
+		job.Status.Failed = 1
+		r = newFakeClusterReconciler(cluster, job)
+
+		_, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cluster.Status.LastBackupVerification.Phase).To(Equal(apiv1.BackupVerificationPhaseFailed))
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionBackupVerification))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		_, err = getJob(ctx)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("verifies a backup as soon as it is completed", func(ctx SpecContext) {
+		cluster.Spec.Backup.Verification = &apiv1.BackupVerificationConfiguration{AfterBackup: true}
+		startedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+		cluster.Status.LastBackupVerification = &apiv1.BackupVerificationStatus{
+			Phase:     apiv1.BackupVerificationPhaseSucceeded,
+			StartedAt: &startedAt,
+		}
+		r = newFakeClusterReconciler(cluster)
+
+		_, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = getJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Status.LastBackupVerification.Phase).To(Equal(apiv1.BackupVerificationPhaseRunning))
+	})
+
+	It("doesn't verify again a backup which has already been verified", func(ctx SpecContext) {
+		cluster.Spec.Backup.Verification = &apiv1.BackupVerificationConfiguration{AfterBackup: true}
+		cluster.Status.LastSuccessfulBackupByMethod[apiv1.BackupMethodBarmanObjectStore] =
+			metav1.NewTime(time.Now().Add(-time.Hour))
+		now := metav1.Now()
+		cluster.Status.LastBackupVerification = &apiv1.BackupVerificationStatus{
+			Phase:     apiv1.BackupVerificationPhaseSucceeded,
+			StartedAt: &now,
+		}
+		r = newFakeClusterReconciler(cluster)
+
+		res, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.IsZero()).To(BeTrue())
+
+		_, err = getJob(ctx)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("records the outcome in the verified Backup", func(ctx SpecContext) {
+		now := metav1.Now()
+		cluster.Status.LastBackupVerification = &apiv1.BackupVerificationStatus{
+			BackupID:  "20240101T000000",
+			Phase:     apiv1.BackupVerificationPhaseSucceeded,
+			StartedAt: &now,
+			StoppedAt: &now,
+		}
+		backup := &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-example", Namespace: cluster.Namespace},
+			Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: cluster.Name}},
+			Status: apiv1.BackupStatus{
+				BackupID: "20240101T000000",
+				Method:   apiv1.BackupMethodBarmanObjectStore,
+				Phase:    apiv1.BackupPhaseCompleted,
+			},
+		}
+		job := specs.CreateBackupVerificationJob(*cluster)
+		job.Status.Succeeded = 1
+		r = newFakeClusterReconciler(cluster, job, backup)
+
+		_, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		var updatedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &updatedBackup)).To(Succeed())
+		Expect(updatedBackup.Status.Verification).ToNot(BeNil())
+		Expect(updatedBackup.Status.Verification.Phase).To(Equal(apiv1.BackupVerificationPhaseSucceeded))
+	})
+
+	It("leaves the running job alone", func(ctx SpecContext) {
+		job := specs.CreateBackupVerificationJob(*cluster)
+		r = newFakeClusterReconciler(cluster, job)
+
+		_, err := r.reconcileBackupVerification(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = getJob(ctx)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -97,6 +97,7 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;create;list;watch;delete;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
@@ -108,14 +109,20 @@ var ErrNextLoop = utils.ErrNextLoop
 // +kubebuilder:rbac:groups="",resources=events,verbs=create
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;create;watch;delete;patch
+// +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
-// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;watch;list;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=imagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;watch;list
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=databases/status,verbs=get;patch;update
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=publications/status,verbs=get;patch;update
 
 // Reconcile is the operator reconcile loop
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -132,6 +139,8 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		forgetFailoverMetrics(req.Namespace, req.Name)
+		forgetBackupMetrics(req.Namespace, req.Name)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -162,6 +171,12 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluster) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// The deletion policy is applied even when the reconciliation is
+	// disabled, not to block the deletion of the cluster
+	if deleting, err := r.reconcileDeletionPolicy(ctx, cluster); deleting || err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if utils.IsReconciliationDisabled(&cluster.ObjectMeta) {
 		contextLogger.Warning("Disable reconciliation loop annotation set, skipping the reconciliation.")
 		return ctrl.Result{}, nil
@@ -201,6 +216,15 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot reconcile required plugins: %w", err)
 	}
 
+	// Mount the shared WAL restore cache only when it can be used
+	if err := r.reconcileWalRestoreCache(ctx, cluster); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling the shared WAL restore cache", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the shared WAL restore cache: %w", err)
+	}
+
 	// Ensure we reconcile the orphan resources if present when we reconcile for the first time a cluster
 	if res, err := r.reconcileRestoredCluster(ctx, cluster); res != nil || err != nil {
 		if res != nil {
@@ -252,6 +276,14 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 			"currentPrimary", cluster.Status.CurrentPrimary,
 			"targetPrimary", cluster.Status.TargetPrimary)
 
+		if err := r.reconcileSwitchoverProgress(ctx, cluster, resources); err != nil {
+			if apierrs.IsConflict(err) {
+				contextLogger.Debug("Conflict error while reporting the switchover progress", "error", err)
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("cannot report the switchover progress: %w", err)
+		}
+
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
@@ -266,6 +298,37 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// Upgrade the data directory to the PostgreSQL major version of the image
+	if res, err := r.reconcileMajorUpgrade(ctx, cluster, resources); res != nil || err != nil {
+		if res != nil {
+			return *res, err
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the major upgrade: %w", err)
+	}
+
+	// Fence the instances that keep crashing, if requested
+	if err := r.reconcileQuarantine(ctx, cluster, resources.instances.Items); err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling the quarantine", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the quarantine of the instances: %w", err)
+	}
+
+	// Recreate the former primaries that can't be resynchronized, if requested
+	recloned, err := r.reconcileRewindFailures(ctx, cluster, resources.instances.Items)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling the pg_rewind failures", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the pg_rewind failures: %w", err)
+	}
+	if recloned {
+		// Let's wait for the informer cache to notice the deleted instances
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
 	// Store in the context the TLS configuration required communicating with the Pods
 	ctx, err = certs.NewTLSConfigForContext(
 		ctx,
@@ -386,6 +449,7 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		r.Client,
 		cluster,
 		resources.instances.Items,
+		instancesStatus,
 	); result != nil || err != nil {
 		return *result, err
 	}
@@ -422,9 +486,63 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return res, err
 	}
 
+	// Adjusts the number of instances to the active
+	// connections of the replicas, when requested
+	autoscalingCheckInterval, err := r.reconcileReplicaAutoscaling(ctx, cluster, instancesStatus)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling replica autoscaling", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile replica autoscaling: %w", err)
+	}
+
+	// Removes the temporary cluster used to import a database
+	// from a base backup, if not needed anymore
+	if err := r.deleteImportSourceCluster(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot delete the import source cluster: %w", err)
+	}
+
+	// Verifies the latest base backup can be restored, periodically
+	// or as soon as it is completed
+	verificationResult, err := r.reconcileBackupVerification(ctx, cluster)
+	if err != nil {
+		if apierrs.IsConflict(err) {
+			contextLogger.Debug("Conflict error while reconciling backup verification", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile backup verification: %w", err)
+	}
+
 	// Calls post-reconcile hooks
 	hookResult := postReconcilePluginHooks(ctx, cluster, cluster)
-	return hookResult.Result, hookResult.Err
+	if hookResult.Err != nil || !hookResult.Result.IsZero() {
+		return hookResult.Result, hookResult.Err
+	}
+
+	// Some conditions change without any change to the watched
+	// resources, and need the cluster to be reconciled periodically
+	return withRequeueAfter(verificationResult,
+		getTargetRPORequeueAfter(cluster),
+		getWalRestoreCacheRequeueAfter(cluster),
+		getPendingRestartRequeueAfter(cluster, time.Now()),
+		getTransactionIDAgeRequeueAfter(cluster),
+		getPasswordRotationRequeueAfter(cluster),
+		getSwitchoverProgressRequeueAfter(cluster),
+		autoscalingCheckInterval,
+	), nil
+}
+
+// withRequeueAfter makes sure the cluster is reconciled again within
+// the shortest of the passed intervals, ignoring the zero ones
+func withRequeueAfter(result ctrl.Result, intervals ...time.Duration) ctrl.Result {
+	for _, interval := range intervals {
+		if interval > 0 && (result.RequeueAfter == 0 || result.RequeueAfter > interval) {
+			result.RequeueAfter = interval
+		}
+	}
+
+	return result
 }
 
 func (r *ClusterReconciler) ensureNoFailoverOnFullDisk(
@@ -474,7 +592,7 @@ func (r *ClusterReconciler) handleSwitchover(
 		return nil, nil
 	}
 	if cluster.Status.Phase == apiv1.PhaseInplaceDeletePrimaryRestart {
-		if cluster.Status.ReadyInstances != cluster.Spec.Instances {
+		if cluster.Status.ReadyInstances != cluster.GetInstances() {
 			contextLogger.Info("Waiting for the primary to be restarted without triggering a switchover")
 			return nil, nil
 		}
@@ -495,10 +613,19 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for the failover delay to expire")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, ErrWaitingOnFailoverCooldown) {
+			contextLogger.Info("Waiting for the failover cooldown period to expire")
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
 		if errors.Is(err, ErrWalReceiversRunning) {
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if errors.Is(err, ErrNoFailoverCandidates) {
+			contextLogger.Warning(
+				"Cannot elect a new primary, the only available instances are delayed or fenced replicas")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"error", err)
@@ -520,6 +647,34 @@ func (r *ClusterReconciler) handleSwitchover(
 		}
 	}
 
+	// If a failover has been completed, let's record how long it took
+	if err := r.registerFailoverCompletion(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	// Primary is healthy, let's handle the switchover requested by the user, if any
+	switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+	if err != nil {
+		return nil, err
+	}
+	if switchingOver {
+		contextLogger.Info("Waiting for the requested switchover to progress",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", cluster.Status.TargetPrimary)
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	// Let's proceed with the replacement of an instance requested by the user, if any
+	replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+	if err != nil {
+		return nil, err
+	}
+	if replacing {
+		// Let's wait for the new primary to be promoted or for the
+		// informer cache to notice the deleted instance
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
 	return nil, nil
 }
 
@@ -642,7 +797,7 @@ func (r *ClusterReconciler) reconcileResources(
 	}
 
 	// If we still need more instances, we need to wait before setting healthy status
-	if instancesStatus.InstancesReportingStatus() != cluster.Spec.Instances {
+	if instancesStatus.InstancesReportingStatus() != cluster.GetInstances() {
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
@@ -766,7 +921,7 @@ func (r *ClusterReconciler) reconcilePods(
 	}
 
 	// Are there missing nodes? Let's create one
-	if cluster.Status.Instances < cluster.Spec.Instances &&
+	if cluster.Status.Instances < cluster.GetInstances() &&
 		instancesStatus.InstancesReportingStatus() == cluster.Status.Instances {
 		newNodeSerial, err := r.generateNodeSerial(ctx, cluster)
 		if err != nil {
@@ -776,7 +931,7 @@ func (r *ClusterReconciler) reconcilePods(
 	}
 
 	// Are there nodes to be removed? Remove one of them
-	if cluster.Status.Instances > cluster.Spec.Instances {
+	if cluster.Status.Instances > cluster.GetInstances() {
 		if err := r.scaleDownCluster(ctx, cluster, resources); err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot scale down cluster: %w", err)
 		}
@@ -784,7 +939,7 @@ func (r *ClusterReconciler) reconcilePods(
 
 	// Stop acting here if there are non-ready Pods
 	// In the rest of the function we are sure that
-	// cluster.Status.Instances == cluster.Spec.Instances and
+	// cluster.Status.Instances == cluster.GetInstances() and
 	// we don't need to modify the cluster topology
 	if cluster.Status.ReadyInstances != cluster.Status.Instances ||
 		cluster.Status.ReadyInstances != len(instancesStatus.Items) ||
@@ -835,6 +990,11 @@ func (r *ClusterReconciler) handleRollingUpdate(
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx).WithName("handle_rolling_update")
 
+	// Record the restarts which are deferred by the restart strategy
+	if err := r.reconcilePendingRestart(ctx, cluster, instancesStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// If we need to roll out a restart of any instance, this is the right moment
 	done, err := r.rolloutRequiredInstances(ctx, cluster, &instancesStatus)
 	switch {
@@ -851,7 +1011,7 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		return ctrl.Result{}, ErrNextLoop
 	}
 
-	if instancesStatus.ArePodsWaitingForDecreasedSettings() {
+	if cluster.Status.PendingRestart == nil && instancesStatus.ArePodsWaitingForDecreasedSettings() {
 		// requeue and wait for the pods to be ready to be restarted,
 		// which will be handled by rolloutDueToCondition
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
@@ -906,6 +1066,11 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&apiv1.Pooler{},
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters()),
 		).
+		Watches(
+			&apiv1.Database{},
+			handler.EnqueueRequestsFromMapFunc(r.mapDatabasesToClusters()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Watches(
 			&corev1.Node{},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters()),
@@ -1132,6 +1297,22 @@ func (r *ClusterReconciler) mapPoolersToClusters() handler.MapFunc {
 	}
 }
 
+// mapDatabasesToClusters returns a function mapping database events watched
+// to cluster reconcile requests, as the role of the instances grants access
+// to the secrets used by the databases
+func (r *ClusterReconciler) mapDatabasesToClusters() handler.MapFunc {
+	return func(_ context.Context, obj client.Object) []reconcile.Request {
+		database, ok := obj.(*apiv1.Database)
+		if !ok || database.Spec.ClusterRef.Name == "" {
+			return nil
+		}
+
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: database.Namespace, Name: database.Spec.ClusterRef.Name},
+		}}
+	}
+}
+
 // mapNodeToClusters returns a function mapping cluster events watched to cluster reconcile requests
 func (r *ClusterReconciler) mapConfigMapsToClusters() handler.MapFunc {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
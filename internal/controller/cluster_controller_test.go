@@ -23,6 +23,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -273,3 +274,18 @@ var _ = Describe("Updating target primary", func() {
 		})
 	})
 })
+
+var _ = Describe("withRequeueAfter", func() {
+	It("keeps the result when no interval is set", func() {
+		Expect(withRequeueAfter(ctrl.Result{})).To(Equal(ctrl.Result{}))
+		Expect(withRequeueAfter(ctrl.Result{RequeueAfter: time.Minute}, 0, 0)).
+			To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+	})
+
+	It("requeues within the shortest interval", func() {
+		Expect(withRequeueAfter(ctrl.Result{}, 0, time.Hour, time.Minute)).
+			To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		Expect(withRequeueAfter(ctrl.Result{RequeueAfter: time.Second}, time.Hour, time.Minute)).
+			To(Equal(ctrl.Result{RequeueAfter: time.Second}))
+	})
+})
@@ -61,6 +61,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.completeCertificatesRotation(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.reconcilePostgresServices(ctx, cluster)
 	if err != nil {
 		return err
@@ -98,6 +103,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = createOrPatchPrometheusRule(ctx, r.Client, r.DiscoveryClient, cluster)
+	if err != nil {
+		return err
+	}
+
 	// TODO: only required to cleanup custom monitoring queries configmaps from older versions (v1.10 and v1.11)
 	// 		 that could have been copied with the source configmap name instead of the new default one.
 	// 		 Should be removed in future releases.
@@ -119,7 +129,7 @@ func (r *ClusterReconciler) reconcilePodDisruptionBudget(ctx context.Context, cl
 			return err
 		}
 
-		if cluster.Spec.Instances == 1 {
+		if cluster.GetInstances() == 1 {
 			// If this a single-instance cluster, we need to delete
 			// the PodDisruptionBudget for the primary node too
 			// otherwise the user won't be able to drain the workloads
@@ -144,10 +154,14 @@ func (r *ClusterReconciler) reconcilePodDisruptionBudget(ctx context.Context, cl
 		return err
 	}
 
-	return r.createOrPatchOwnedPodDisruptionBudget(ctx,
-		cluster,
-		specs.BuildReplicasPodDisruptionBudget(cluster),
-	)
+	// The replicas PDB is not needed when there are not enough
+	// replicas, i.e. after a scale down
+	replicasPdb := specs.BuildReplicasPodDisruptionBudget(cluster)
+	if replicasPdb == nil {
+		return r.deleteReplicasPodDisruptionBudget(ctx, cluster)
+	}
+
+	return r.createOrPatchOwnedPodDisruptionBudget(ctx, cluster, replicasPdb)
 }
 
 func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -161,6 +175,11 @@ func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluste
 		return err
 	}
 
+	err = r.reconcilePasswordRotation(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.reconcilePoolerSecrets(ctx, cluster)
 	if err != nil {
 		return err
@@ -171,23 +190,14 @@ func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluste
 
 func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluster *apiv1.Cluster) error {
 	// We need to create a secret for the 'postgres' user when superuser
-	// access is enabled and the user haven't specified his own
-	if cluster.GetEnableSuperuserAccess() &&
-		(cluster.Spec.SuperuserSecret == nil || cluster.Spec.SuperuserSecret.Name == "") {
-		postgresPassword, err := password.Generate(64, 10, 0, false, true)
+	// access is enabled and the user hasn't specified their own
+	if shouldCreateSuperuserSecret(cluster) {
+		postgresPassword, err := generatePassword()
 		if err != nil {
 			return err
 		}
-		postgresSecret := specs.CreateSecret(
-			cluster.GetSuperuserSecretName(),
-			cluster.Namespace,
-			cluster.GetServiceReadWriteName(),
-			"*",
-			"postgres",
-			postgresPassword)
-		cluster.SetInheritedDataAndOwnership(&postgresSecret.ObjectMeta)
 
-		return createOrPatchClusterCredentialSecret(ctx, r.Client, postgresSecret)
+		return createOrPatchClusterCredentialSecret(ctx, r.Client, newSuperuserSecret(cluster, postgresPassword))
 	}
 
 	// If we don't have Superuser enabled we make sure the automatically generated secret doesn't exist
@@ -214,24 +224,60 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 
 func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster *apiv1.Cluster) error {
 	if cluster.ShouldCreateApplicationSecret() {
-		appPassword, err := password.Generate(64, 10, 0, false, true)
+		appPassword, err := generatePassword()
 		if err != nil {
 			return err
 		}
-		appSecret := specs.CreateSecret(
-			cluster.GetApplicationSecretName(),
-			cluster.Namespace,
-			cluster.GetServiceReadWriteName(),
-			cluster.GetApplicationDatabaseName(),
-			cluster.GetApplicationDatabaseOwner(),
-			appPassword)
 
-		cluster.SetInheritedDataAndOwnership(&appSecret.ObjectMeta)
-		return createOrPatchClusterCredentialSecret(ctx, r.Client, appSecret)
+		return createOrPatchClusterCredentialSecret(ctx, r.Client, newAppUserSecret(cluster, appPassword))
 	}
 	return nil
 }
 
+// shouldCreateSuperuserSecret checks if the operator needs to generate
+// the secret of the 'postgres' user, which happens when superuser access
+// is enabled and the user hasn't specified their own
+func shouldCreateSuperuserSecret(cluster *apiv1.Cluster) bool {
+	return cluster.GetEnableSuperuserAccess() &&
+		(cluster.Spec.SuperuserSecret == nil || cluster.Spec.SuperuserSecret.Name == "")
+}
+
+// generatePassword generates a random password for
+// the secrets created by the operator
+func generatePassword() (string, error) {
+	return password.Generate(64, 10, 0, false, true)
+}
+
+// newSuperuserSecret creates the secret of the 'postgres'
+// user with the passed password
+func newSuperuserSecret(cluster *apiv1.Cluster, postgresPassword string) *corev1.Secret {
+	postgresSecret := specs.CreateSecret(
+		cluster.GetSuperuserSecretName(),
+		cluster.Namespace,
+		cluster.GetServiceReadWriteName(),
+		"*",
+		"postgres",
+		postgresPassword)
+	cluster.SetInheritedDataAndOwnership(&postgresSecret.ObjectMeta)
+
+	return postgresSecret
+}
+
+// newAppUserSecret creates the secret of the owner of the
+// application database with the passed password
+func newAppUserSecret(cluster *apiv1.Cluster, appPassword string) *corev1.Secret {
+	appSecret := specs.CreateSecret(
+		cluster.GetApplicationSecretName(),
+		cluster.Namespace,
+		cluster.GetServiceReadWriteName(),
+		cluster.GetApplicationDatabaseName(),
+		cluster.GetApplicationDatabaseOwner(),
+		appPassword)
+	cluster.SetInheritedDataAndOwnership(&appSecret.ObjectMeta)
+
+	return appSecret
+}
+
 func createOrPatchClusterCredentialSecret(
 	ctx context.Context,
 	cli client.Client,
@@ -706,6 +752,11 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		return err
 	}
 
+	databases, err := r.getClusterDatabases(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	var role rbacv1.Role
 	if err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &role); err != nil {
 		if !apierrs.IsNotFound(err) {
@@ -713,10 +764,10 @@ func (r *ClusterReconciler) createOrPatchRole(ctx context.Context, cluster *apiv
 		}
 
 		r.Recorder.Event(cluster, "Normal", "CreatingRole", "Creating Cluster Role")
-		return r.createRole(ctx, cluster, originBackup)
+		return r.createRole(ctx, cluster, originBackup, databases)
 	}
 
-	generatedRole := specs.CreateRole(*cluster, originBackup)
+	generatedRole := specs.CreateRole(*cluster, originBackup, databases)
 	if reflect.DeepEqual(generatedRole.Rules, role.Rules) {
 		// Everything fine, the two config maps are exactly the same
 		return nil
@@ -1005,9 +1056,73 @@ func createOrPatchPodMonitor(
 	}
 }
 
+// createOrPatchPrometheusRule reconciles the PrometheusRule containing
+// the default alerts of the cluster
+func createOrPatchPrometheusRule(
+	ctx context.Context,
+	cli client.Client,
+	discoveryClient discovery.DiscoveryInterface,
+	cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	// Checking for the PrometheusRule Custom Resource Definition in the Kubernetes cluster
+	havePrometheusRuleCRD, err := utils.PrometheusRuleExist(discoveryClient)
+	if err != nil {
+		return err
+	}
+
+	if !havePrometheusRuleCRD {
+		if cluster.IsPrometheusRuleEnabled() {
+			contextLogger.Warning("PrometheusRule CRD not present. Cannot create the PrometheusRule object")
+		}
+		return nil
+	}
+
+	expectedRule := specs.BuildPrometheusRule(cluster)
+	rule := &monitoringv1.PrometheusRule{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(expectedRule), rule); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the prometheusrule: %w", err)
+		}
+		rule = nil
+	}
+
+	switch {
+	case !cluster.IsPrometheusRuleEnabled() && rule == nil:
+		return nil
+	case !cluster.IsPrometheusRuleEnabled() && rule != nil:
+		contextLogger.Info("Deleting PrometheusRule")
+		if err := cli.Delete(ctx, rule); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+	case rule == nil:
+		contextLogger.Debug("Creating PrometheusRule")
+		return cli.Create(ctx, expectedRule)
+	default:
+		origRule := rule.DeepCopy()
+		rule.Spec = expectedRule.Spec
+		// We don't override the current labels/annotations given that there could be data that isn't managed by us
+		utils.MergeObjectsMetadata(rule, expectedRule)
+
+		if reflect.DeepEqual(origRule, rule) {
+			return nil
+		}
+
+		contextLogger.Debug("Patching PrometheusRule")
+		return cli.Patch(ctx, rule, client.MergeFrom(origRule))
+	}
+}
+
 // createRole creates the role
-func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
-	role := specs.CreateRole(*cluster, backupOrigin)
+func (r *ClusterReconciler) createRole(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	databases []apiv1.Database,
+) error {
+	role := specs.CreateRole(*cluster, backupOrigin, databases)
 	cluster.SetInheritedDataAndOwnership(&role.ObjectMeta)
 
 	err := r.Create(ctx, &role)
@@ -1070,6 +1185,14 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, nil
 	}
 
+	// The database to be imported from a base backup must
+	// be recovered before we can create the primary instance
+	if cluster.ShouldImportFromBackup() {
+		if res, err := r.ensureImportSourceIsReady(ctx, cluster); !res.IsZero() || err != nil {
+			return res, err
+		}
+	}
+
 	var (
 		backup           *apiv1.Backup
 		recoverySnapshot *persistentvolumeclaim.StorageSource
@@ -1185,6 +1308,21 @@ func (r *ClusterReconciler) createPrimaryInstance(
 }
 
 // getOriginBackup gets the backup that is used to bootstrap a new PostgreSQL cluster
+// getClusterDatabases gets the Database objects of the cluster
+func (r *ClusterReconciler) getClusterDatabases(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]apiv1.Database, error) {
+	var databases apiv1.DatabaseList
+	if err := r.List(ctx, &databases, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("while getting the databases: %w", err)
+	}
+
+	return slices.DeleteFunc(databases.Items, func(database apiv1.Database) bool {
+		return database.Spec.ClusterRef.Name != cluster.Name
+	}), nil
+}
+
 func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.Cluster) (*apiv1.Backup, error) {
 	if cluster.Spec.Bootstrap == nil ||
 		cluster.Spec.Bootstrap.Recovery == nil ||
@@ -1236,6 +1374,7 @@ func (r *ClusterReconciler) joinReplicaInstance(
 	if storageSource != nil {
 		job = specs.RestoreReplicaInstance(*cluster, nodeSerial)
 	}
+	avoidReplacedInstanceNode(cluster, nodeSerial, &job.Spec.Template.Spec)
 
 	contextLogger.Info("Creating new Job",
 		"job", job.Name,
@@ -1351,6 +1490,10 @@ func (r *ClusterReconciler) ensureInstancesAreCreated(
 		}
 	}
 
+	if nodeSerial, err := specs.GetNodeSerial(instanceToCreate.ObjectMeta); err == nil {
+		avoidReplacedInstanceNode(cluster, nodeSerial, &instanceToCreate.Spec)
+	}
+
 	// If this cluster has been restarted, mark the Pod with the latest restart time
 	if clusterRestart, ok := cluster.Annotations[utils.ClusterRestartAnnotationName]; ok {
 		if instanceToCreate.Annotations == nil {
@@ -1468,6 +1611,15 @@ func (r *ClusterReconciler) checkReadyForRecovery(
 				RequeueAfter: time.Minute,
 			}, nil
 		}
+		if err := cluster.CheckBackupMajorVersion(backup); err != nil {
+			// There's no point in starting a recovery job that is doomed to fail
+			contextLogger.Warning("The source backup is not compatible with the cluster image",
+				"backup", cluster.Spec.Bootstrap.Recovery.Backup,
+				"reason", err.Error())
+			r.Recorder.Event(cluster, "Warning", "IncompatibleBackup", err.Error())
+			return ctrl.Result{RequeueAfter: time.Minute},
+				r.RegisterPhase(ctx, cluster, apiv1.PhaseUnrecoverable, err.Error())
+		}
 	}
 
 	volumeSnapshotsRecovery := cluster.Spec.Bootstrap.Recovery.VolumeSnapshots
@@ -471,6 +471,45 @@ var _ = Describe("check if bootstrap recovery can proceed", func() {
 		Entry("when bootstrapping a backup that is not there",
 			nil, true),
 	)
+
+	It("marks the cluster as unrecoverable when the backup major version differs", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterName,
+				Namespace: namespace,
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.4",
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "1G",
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{
+						Backup: &apiv1.BackupSource{
+							LocalObjectReference: apiv1.LocalObjectReference{
+								Name: name,
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(env.client.Create(ctx, cluster)).To(Succeed())
+
+		backup := &apiv1.Backup{
+			Status: apiv1.BackupStatus{
+				Phase:        apiv1.BackupPhaseCompleted,
+				BackupID:     "20240101T000000",
+				MajorVersion: 15,
+			},
+		}
+
+		res, err := env.clusterReconciler.checkReadyForRecovery(ctx, backup, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(Equal(reconcile.Result{}))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseUnrecoverable))
+		Expect(cluster.Status.PhaseReason).To(ContainSubstring("taken with PostgreSQL 15"))
+	})
 })
 
 var _ = Describe("check if bootstrap recovery can proceed from volume snapshot", func() {
@@ -786,6 +825,91 @@ var _ = Describe("CreateOrPatchPodMonitor", func() {
 	})
 })
 
+var _ = Describe("createOrPatchPrometheusRule", func() {
+	var (
+		fakeCli             k8client.Client
+		fakeDiscoveryClient discovery.DiscoveryInterface
+		cluster             *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					PrometheusRule: &apiv1.PrometheusRuleConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+		}
+
+		fakeCli = fake.NewClientBuilder().WithScheme(schemeBuilder.BuildWithAllKnownScheme()).Build()
+
+		fakeDiscoveryClient = &fakediscovery.FakeDiscovery{
+			Fake: &testing.Fake{
+				Resources: []*metav1.APIResourceList{
+					{
+						GroupVersion: "monitoring.coreos.com/v1",
+						APIResources: []metav1.APIResource{
+							{
+								Name:       "prometheusrules",
+								Kind:       "PrometheusRule",
+								Namespaced: true,
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	getRule := func(ctx SpecContext) (*v1.PrometheusRule, error) {
+		rule := &v1.PrometheusRule{}
+		err := fakeCli.Get(ctx, k8client.ObjectKeyFromObject(cluster), rule)
+		return rule, err
+	}
+
+	It("does nothing when the PrometheusRule CRD is not installed", func(ctx SpecContext) {
+		fakeDiscoveryClient = &fakediscovery.FakeDiscovery{Fake: &testing.Fake{}}
+		Expect(createOrPatchPrometheusRule(ctx, fakeCli, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		_, err := getRule(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("creates and updates the PrometheusRule when it is enabled", func(ctx SpecContext) {
+		Expect(createOrPatchPrometheusRule(ctx, fakeCli, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		rule, err := getRule(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rule.Spec.Groups).To(HaveLen(1))
+		Expect(rule.Spec.Groups[0].Rules).To(HaveLen(3))
+
+		cluster.Spec.Monitoring.PrometheusRule.Alerts = []apiv1.PrometheusAlertConfiguration{
+			{Name: apiv1.PrometheusAlertDiskPressure, Disabled: true},
+		}
+		Expect(createOrPatchPrometheusRule(ctx, fakeCli, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		rule, err = getRule(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rule.Spec.Groups[0].Rules).To(HaveLen(2))
+	})
+
+	It("removes the PrometheusRule when it is disabled", func(ctx SpecContext) {
+		Expect(createOrPatchPrometheusRule(ctx, fakeCli, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		cluster.Spec.Monitoring.PrometheusRule.Enabled = false
+		Expect(createOrPatchPrometheusRule(ctx, fakeCli, fakeDiscoveryClient, cluster)).To(Succeed())
+
+		_, err := getRule(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
+
 var _ = Describe("createOrPatchClusterCredentialSecret", func() {
 	const (
 		secretName = "test-secret"
@@ -909,6 +1033,86 @@ var _ = Describe("createOrPatchClusterCredentialSecret", func() {
 	})
 })
 
+var _ = Describe("externally provided credential secrets", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	newExternalSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("user"),
+				corev1.BasicAuthPasswordKey: []byte("external-password"),
+			},
+		}
+	}
+
+	expectSecretUntouched := func(ctx context.Context, secret *corev1.Secret) {
+		var stored corev1.Secret
+		Expect(r.Get(ctx, k8client.ObjectKeyFromObject(secret), &stored)).To(Succeed())
+		Expect(stored.Data).To(Equal(secret.Data))
+		Expect(stored.OwnerReferences).To(BeEmpty())
+	}
+
+	expectSecretNotFound := func(ctx context.Context, name string) {
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &corev1.Secret{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				EnableSuperuserAccess: ptr.To(true),
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+			},
+		}
+	})
+
+	It("never regenerates the referenced superuser secret", func(ctx SpecContext) {
+		secret := newExternalSecret("external-superuser")
+		cluster.Spec.SuperuserSecret = &apiv1.LocalObjectReference{Name: secret.Name}
+		r = newFakeClusterReconciler(cluster, secret)
+
+		for range 2 {
+			Expect(r.reconcileSuperuserSecret(ctx, cluster)).To(Succeed())
+		}
+		expectSecretUntouched(ctx, secret)
+		expectSecretNotFound(ctx, cluster.Name+apiv1.SuperUserSecretSuffix)
+	})
+
+	It("never regenerates the referenced application secret", func(ctx SpecContext) {
+		secret := newExternalSecret("external-app")
+		cluster.Spec.Bootstrap.InitDB.Secret = &apiv1.LocalObjectReference{Name: secret.Name}
+		r = newFakeClusterReconciler(cluster, secret)
+
+		for range 2 {
+			Expect(r.reconcileAppUserSecret(ctx, cluster)).To(Succeed())
+		}
+		expectSecretUntouched(ctx, secret)
+		expectSecretNotFound(ctx, cluster.Name+apiv1.ApplicationUserSecretSuffix)
+	})
+
+	It("never overwrites a secret with the default name which is not owned by the cluster", func(ctx SpecContext) {
+		superuserSecret := newExternalSecret(cluster.GetSuperuserSecretName())
+		appSecret := newExternalSecret(cluster.GetApplicationSecretName())
+		r = newFakeClusterReconciler(cluster, superuserSecret, appSecret)
+
+		Expect(r.reconcileSuperuserSecret(ctx, cluster)).To(Succeed())
+		Expect(r.reconcileAppUserSecret(ctx, cluster)).To(Succeed())
+		expectSecretUntouched(ctx, superuserSecret)
+		expectSecretUntouched(ctx, appSecret)
+	})
+})
+
 var _ = Describe("createOrPatchOwnedPodDisruptionBudget", func() {
 	var (
 		ctx        context.Context
@@ -18,14 +18,20 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // deleteDanglingMonitoringQueries deletes the default monitoring configMap and/or secret if no cluster in the namespace
@@ -87,3 +93,215 @@ func (r *ClusterReconciler) deleteDanglingMonitoringQueries(ctx context.Context,
 
 	return nil
 }
+
+// reconcileDeletionPolicy makes sure the finalizer applying the deletion
+// policy is set on the cluster. When the cluster is being deleted, it
+// applies the deletion policy and removes the finalizer, letting the
+// Kubernetes garbage collector delete the resources still owned by
+// the cluster.
+// It returns true when the cluster is being deleted.
+func (r *ClusterReconciler) reconcileDeletionPolicy(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
+	if cluster.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(cluster, apiv1.ClusterFinalizerName) {
+			return false, nil
+		}
+
+		origCluster := cluster.DeepCopy()
+		controllerutil.AddFinalizer(cluster, apiv1.ClusterFinalizerName)
+		return false, r.Patch(ctx, cluster, client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+	}
+
+	if !controllerutil.ContainsFinalizer(cluster, apiv1.ClusterFinalizerName) {
+		return true, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	pvcsPolicy := cluster.Spec.DeletionPolicy.GetPVCsReclaimPolicy()
+	backupsPolicy := cluster.Spec.DeletionPolicy.GetBackupsReclaimPolicy()
+
+	// The webhook rejecting the deletions not confirmed is ignored when
+	// it's not reachable, so everything is retained in that case
+	if !cluster.IsDeletionConfirmed() {
+		contextLogger.Warning("The deletion of the cluster has not been confirmed, retaining its resources")
+		r.Recorder.Event(cluster, "Warning", "DeletionNotConfirmed",
+			"The deletion of the cluster has not been confirmed, its PVCs and backups are retained")
+		pvcsPolicy = apiv1.ClusterReclaimRetain
+		backupsPolicy = apiv1.ClusterReclaimRetain
+	}
+
+	contextLogger.Info("Applying the deletion policy of the cluster",
+		"pvcs", pvcsPolicy,
+		"backups", backupsPolicy)
+
+	if pvcsPolicy == apiv1.ClusterReclaimRetain {
+		if err := r.retainPVCs(ctx, cluster); err != nil {
+			return true, err
+		}
+	}
+
+	switch backupsPolicy {
+	case apiv1.ClusterReclaimRetain:
+		if err := r.retainBackups(ctx, cluster); err != nil {
+			return true, err
+		}
+	case apiv1.ClusterReclaimDelete:
+		if err := r.deleteBackups(ctx, cluster); err != nil {
+			return true, err
+		}
+	}
+
+	origCluster := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, apiv1.ClusterFinalizerName)
+	return true, r.Patch(ctx, cluster, client.MergeFromWithOptions(origCluster, client.MergeFromWithOptimisticLock{}))
+}
+
+// retainPVCs removes the ownership of the cluster from its PVCs,
+// so that they are not deleted together with the cluster. Their
+// cluster label is replaced too, so that a new cluster with the
+// same name doesn't consider them as its own
+func (r *ClusterReconciler) retainPVCs(ctx context.Context, cluster *apiv1.Cluster) error {
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return fmt.Errorf("while listing the PVCs to be retained: %w", err)
+	}
+
+	for idx := range pvcs.Items {
+		if err := r.releasePVC(ctx, cluster, &pvcs.Items[idx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releasePVC removes the owner reference to the cluster from the passed
+// PVC, and replaces its cluster label with the retainedFromCluster one.
+// PVCs not owned by the cluster are left untouched
+func (r *ClusterReconciler) releasePVC(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+) error {
+	if !metav1.IsControlledBy(pvc, cluster) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Retaining the PVC after the deletion of the cluster",
+		"name", pvc.Name)
+	origPVC := pvc.DeepCopy()
+	pvc.OwnerReferences = slices.DeleteFunc(pvc.OwnerReferences, func(reference metav1.OwnerReference) bool {
+		return reference.UID == cluster.UID
+	})
+	delete(pvc.Labels, utils.ClusterLabelName)
+	pvc.Labels[utils.RetainedFromClusterLabelName] = cluster.Name
+	return r.Patch(ctx, pvc, client.MergeFrom(origPVC))
+}
+
+// retainBackups removes the ownership of the cluster from its Backup
+// objects and from its volume snapshots, so that they are not deleted
+// together with the cluster
+func (r *ClusterReconciler) retainBackups(ctx context.Context, cluster *apiv1.Cluster) error {
+	backups, err := r.getClusterBackups(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	for idx := range backups {
+		if err := r.removeClusterOwnership(ctx, cluster, &backups[idx]); err != nil {
+			return err
+		}
+	}
+
+	if !utils.HaveVolumeSnapshot() {
+		return nil
+	}
+
+	var snapshots storagesnapshotv1.VolumeSnapshotList
+	if err := r.List(ctx, &snapshots,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return fmt.Errorf("while listing the volume snapshots to be retained: %w", err)
+	}
+	for idx := range snapshots.Items {
+		if err := r.removeClusterOwnership(ctx, cluster, &snapshots.Items[idx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteBackups deletes the Backup objects and the volume snapshots of
+// the cluster. The backups stored in the object store are not touched
+func (r *ClusterReconciler) deleteBackups(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	backups, err := r.getClusterBackups(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	for idx := range backups {
+		contextLogger.Info("Deleting the backup of the deleted cluster", "backupName", backups[idx].Name)
+		if err := r.Delete(ctx, &backups[idx]); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting backup %s: %w", backups[idx].Name, err)
+		}
+	}
+
+	if !utils.HaveVolumeSnapshot() {
+		return nil
+	}
+
+	if err := r.DeleteAllOf(ctx, &storagesnapshotv1.VolumeSnapshot{},
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting the volume snapshots: %w", err)
+	}
+
+	return nil
+}
+
+// getClusterBackups gets the Backup objects of the cluster
+func (r *ClusterReconciler) getClusterBackups(ctx context.Context, cluster *apiv1.Cluster) ([]apiv1.Backup, error) {
+	var backupList apiv1.BackupList
+	if err := r.List(ctx, &backupList, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, fmt.Errorf("while listing the backups: %w", err)
+	}
+
+	backups := make([]apiv1.Backup, 0, len(backupList.Items))
+	for _, backup := range backupList.Items {
+		if backup.Spec.Cluster.Name == cluster.Name {
+			backups = append(backups, backup)
+		}
+	}
+
+	return backups, nil
+}
+
+// removeClusterOwnership removes the owner reference to the
+// cluster from the passed object, if present
+func (r *ClusterReconciler) removeClusterOwnership(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	object client.Object,
+) error {
+	ownerReferences := object.GetOwnerReferences()
+	retainedReferences := make([]metav1.OwnerReference, 0, len(ownerReferences))
+	for _, reference := range ownerReferences {
+		if reference.UID != cluster.UID {
+			retainedReferences = append(retainedReferences, reference)
+		}
+	}
+	if len(retainedReferences) == len(ownerReferences) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Retaining the resource after the deletion of the cluster",
+		"name", object.GetName())
+	origObject := object.DeepCopyObject().(client.Object)
+	object.SetOwnerReferences(retainedReferences)
+	return r.Patch(ctx, object, client.MergeFrom(origObject))
+}
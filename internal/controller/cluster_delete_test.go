@@ -20,11 +20,14 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -132,3 +135,150 @@ var _ = Describe("ensures that deleteDanglingMonitoringQueries works correctly",
 		})
 	})
 })
+
+var _ = Describe("deletion policy", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+		pvc     *corev1.PersistentVolumeClaim
+		backup  *apiv1.Backup
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-uid",
+			},
+		}
+
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1",
+				Namespace: "default",
+				Labels:    map[string]string{utils.ClusterLabelName: cluster.Name},
+			},
+		}
+		cluster.SetInheritedDataAndOwnership(&pvc.ObjectMeta)
+
+		backup = &apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-backup",
+				Namespace: "default",
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+			},
+		}
+		cluster.SetInheritedDataAndOwnership(&backup.ObjectMeta)
+	})
+
+	markAsDeleted := func() {
+		cluster.Finalizers = []string{apiv1.ClusterFinalizerName}
+		cluster.DeletionTimestamp = ptr.To(metav1.Now())
+	}
+
+	expectClusterDeleted := func(ctx context.Context) {
+		err := r.Get(ctx, client.ObjectKeyFromObject(cluster), &apiv1.Cluster{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	}
+
+	It("sets the finalizer on the clusters which are not being deleted", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster, pvc, backup)
+
+		deleting, err := r.reconcileDeletionPolicy(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeFalse())
+
+		var storedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Finalizers).To(ConsistOf(apiv1.ClusterFinalizerName))
+	})
+
+	It("retains the PVCs and the backups by default", func(ctx SpecContext) {
+		markAsDeleted()
+		r = newFakeClusterReconciler(cluster, pvc, backup)
+
+		deleting, err := r.reconcileDeletionPolicy(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		expectClusterDeleted(ctx)
+
+		var storedPVC corev1.PersistentVolumeClaim
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(pvc), &storedPVC)).To(Succeed())
+		Expect(storedPVC.OwnerReferences).To(BeEmpty())
+		Expect(storedPVC.Labels).ToNot(HaveKey(utils.ClusterLabelName))
+		Expect(storedPVC.Labels).To(HaveKeyWithValue(utils.RetainedFromClusterLabelName, cluster.Name))
+
+		var storedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &storedBackup)).To(Succeed())
+		Expect(storedBackup.OwnerReferences).To(BeEmpty())
+	})
+
+	It("deletes the backups and leaves the PVCs to the garbage collector when requested", func(ctx SpecContext) {
+		cluster.Spec.DeletionPolicy = &apiv1.DeletionPolicyConfiguration{
+			PVCs:    apiv1.ClusterReclaimDelete,
+			Backups: apiv1.ClusterReclaimDelete,
+		}
+		markAsDeleted()
+		r = newFakeClusterReconciler(cluster, pvc, backup)
+
+		deleting, err := r.reconcileDeletionPolicy(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		expectClusterDeleted(ctx)
+
+		var storedPVC corev1.PersistentVolumeClaim
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(pvc), &storedPVC)).To(Succeed())
+		Expect(storedPVC.OwnerReferences).To(HaveLen(1))
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(backup), &apiv1.Backup{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("retains the PVCs and the backups when the required confirmation is missing", func(ctx SpecContext) {
+		cluster.Spec.DeletionPolicy = &apiv1.DeletionPolicyConfiguration{
+			PVCs:                apiv1.ClusterReclaimDelete,
+			Backups:             apiv1.ClusterReclaimDelete,
+			RequireConfirmation: true,
+		}
+		markAsDeleted()
+		r = newFakeClusterReconciler(cluster, pvc, backup)
+
+		deleting, err := r.reconcileDeletionPolicy(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		expectClusterDeleted(ctx)
+
+		var storedPVC corev1.PersistentVolumeClaim
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(pvc), &storedPVC)).To(Succeed())
+		Expect(storedPVC.OwnerReferences).To(BeEmpty())
+		Expect(storedPVC.Labels).To(HaveKeyWithValue(utils.RetainedFromClusterLabelName, cluster.Name))
+
+		var storedBackup apiv1.Backup
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(backup), &storedBackup)).To(Succeed())
+		Expect(storedBackup.OwnerReferences).To(BeEmpty())
+	})
+
+	It("applies the deletion policy when the deletion has been confirmed", func(ctx SpecContext) {
+		cluster.Spec.DeletionPolicy = &apiv1.DeletionPolicyConfiguration{
+			Backups:             apiv1.ClusterReclaimDelete,
+			RequireConfirmation: true,
+		}
+		cluster.Annotations = map[string]string{utils.DeletionConfirmationAnnotationName: cluster.Name}
+		markAsDeleted()
+		r = newFakeClusterReconciler(cluster, pvc, backup)
+
+		deleting, err := r.reconcileDeletionPolicy(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(backup), &apiv1.Backup{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
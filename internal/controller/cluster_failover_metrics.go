@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+var (
+	// failoversTotal is the number of failovers started by
+	// the operator, for each cluster
+	failoversTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cnpg",
+		Subsystem: "cluster",
+		Name:      "failovers_total",
+		Help:      "Number of failovers started by the operator",
+	}, []string{"namespace", "cluster"})
+
+	// failoverDurationSeconds is the time between the detection of the
+	// failure of the primary and the promotion of the new one, for each
+	// cluster
+	failoverDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cnpg",
+		Subsystem: "cluster",
+		Name:      "failover_duration_seconds",
+		Help: "Time between the detection of the failure of the primary " +
+			"and the promotion of the new one",
+		Buckets: []float64{5, 10, 15, 30, 45, 60, 90, 120, 180, 300, 600},
+	}, []string{"namespace", "cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(failoversTotal, failoverDurationSeconds)
+}
+
+// forgetFailoverMetrics removes the failover metrics of a deleted cluster
+func forgetFailoverMetrics(namespace, name string) {
+	failoversTotal.DeleteLabelValues(namespace, name)
+	failoverDurationSeconds.DeleteLabelValues(namespace, name)
+}
+
+// registerFailoverStart sets the cluster phase to failover, recording
+// when the failover has been started if it's not already in progress
+func (r *ClusterReconciler) registerFailoverStart(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason string,
+) error {
+	origCluster := cluster.DeepCopy()
+
+	started := cluster.Status.FailoverStartedTimestamp == ""
+	if started {
+		// When the failover has been delayed, the failure of the
+		// primary has been detected earlier
+		cluster.Status.FailoverStartedTimestamp = cluster.Status.CurrentPrimaryFailingSinceTimestamp
+		if cluster.Status.FailoverStartedTimestamp == "" {
+			cluster.Status.FailoverStartedTimestamp = utils.GetCurrentTimestamp()
+		}
+	}
+
+	if err := status.RegisterPhaseWithOrigCluster(
+		ctx, r.Client, cluster, origCluster, apiv1.PhaseFailOver, reason); err != nil {
+		return err
+	}
+
+	if started {
+		failoversTotal.WithLabelValues(cluster.Namespace, cluster.Name).Inc()
+	}
+	return nil
+}
+
+// registerFailoverCompletion records the duration of the failover that
+// has just been completed, if any, and clears its start time
+func (r *ClusterReconciler) registerFailoverCompletion(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Status.FailoverStartedTimestamp == "" ||
+		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return nil
+	}
+
+	duration := getFailoverDuration(cluster, time.Now())
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.FailoverStartedTimestamp = ""
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Failover completed",
+		"newPrimary", cluster.Status.CurrentPrimary,
+		"duration", duration.String())
+	failoverDurationSeconds.WithLabelValues(cluster.Namespace, cluster.Name).Observe(duration.Seconds())
+	return nil
+}
+
+// getFailoverDuration gets the time between the start of the failover and
+// the promotion of the new primary, as recorded by the new primary itself.
+// If the time of the promotion is not usable, the passed current time
+// is used instead.
+func getFailoverDuration(cluster *apiv1.Cluster, now time.Time) time.Duration {
+	startedAt, err := time.Parse(time.RFC3339Nano, cluster.Status.FailoverStartedTimestamp)
+	if err != nil {
+		return 0
+	}
+
+	promotedAt, err := time.Parse(time.RFC3339Nano, cluster.Status.CurrentPrimaryTimestamp)
+	if err != nil || promotedAt.Before(startedAt) {
+		promotedAt = now
+	}
+
+	return max(promotedAt.Sub(startedAt), 0)
+}
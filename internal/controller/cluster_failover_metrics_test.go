@@ -0,0 +1,123 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover metrics", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-failover-metrics",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-failover-metrics-1",
+				TargetPrimary:  "cluster-failover-metrics-1",
+			},
+		}
+
+		r = newFakeClusterReconciler(cluster)
+
+		DeferCleanup(forgetFailoverMetrics, cluster.Namespace, cluster.Name)
+	})
+
+	getFailovers := func() float64 {
+		return testutil.ToFloat64(failoversTotal.WithLabelValues(cluster.Namespace, cluster.Name))
+	}
+
+	It("records the start of the failover only once", func(ctx SpecContext) {
+		Expect(r.registerFailoverStart(ctx, cluster, "Failing over")).To(Succeed())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseFailOver))
+		startedAt := cluster.Status.FailoverStartedTimestamp
+		Expect(startedAt).ToNot(BeEmpty())
+		Expect(getFailovers()).To(BeEquivalentTo(1))
+
+		Expect(r.registerFailoverStart(ctx, cluster, "Failing over again")).To(Succeed())
+		Expect(cluster.Status.FailoverStartedTimestamp).To(Equal(startedAt))
+		Expect(getFailovers()).To(BeEquivalentTo(1))
+
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.FailoverStartedTimestamp).To(Equal(startedAt))
+		Expect(updatedCluster.Status.Phase).To(Equal(apiv1.PhaseFailOver))
+	})
+
+	It("starts the failover when the primary failure has been detected", func(ctx SpecContext) {
+		cluster.Status.CurrentPrimaryFailingSinceTimestamp = "2024-06-01T10:00:00.000000Z"
+		Expect(r.registerFailoverStart(ctx, cluster, "Failing over")).To(Succeed())
+		Expect(cluster.Status.FailoverStartedTimestamp).To(Equal("2024-06-01T10:00:00.000000Z"))
+	})
+
+	It("waits for the new primary to be promoted", func(ctx SpecContext) {
+		cluster.Status.FailoverStartedTimestamp = "2024-06-01T10:00:00.000000Z"
+		cluster.Status.TargetPrimary = "cluster-failover-metrics-2"
+
+		Expect(r.registerFailoverCompletion(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.FailoverStartedTimestamp).ToNot(BeEmpty())
+		Expect(testutil.CollectAndCount(failoverDurationSeconds)).To(BeZero())
+	})
+
+	It("records the duration of the completed failover", func(ctx SpecContext) {
+		cluster.Status.FailoverStartedTimestamp = "2024-06-01T10:00:00.000000Z"
+		cluster.Status.CurrentPrimary = "cluster-failover-metrics-2"
+		cluster.Status.TargetPrimary = "cluster-failover-metrics-2"
+		cluster.Status.CurrentPrimaryTimestamp = "2024-06-01T10:00:42.000000Z"
+
+		Expect(r.registerFailoverCompletion(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.FailoverStartedTimestamp).To(BeEmpty())
+		Expect(testutil.CollectAndCount(failoverDurationSeconds)).To(Equal(1))
+	})
+
+	Context("getFailoverDuration", func() {
+		now := time.Date(2024, 6, 1, 10, 5, 0, 0, time.UTC)
+
+		It("uses the promotion time of the new primary", func() {
+			cluster.Status.FailoverStartedTimestamp = "2024-06-01T10:00:00.000000Z"
+			cluster.Status.CurrentPrimaryTimestamp = "2024-06-01T10:00:42.000000Z"
+			Expect(getFailoverDuration(cluster, now)).To(Equal(42 * time.Second))
+		})
+
+		It("falls back to the current time when the promotion time is not usable", func() {
+			cluster.Status.FailoverStartedTimestamp = "2024-06-01T10:00:00.000000Z"
+			cluster.Status.CurrentPrimaryTimestamp = "2024-06-01T09:00:00.000000Z"
+			Expect(getFailoverDuration(cluster, now)).To(Equal(5 * time.Minute))
+
+			cluster.Status.CurrentPrimaryTimestamp = ""
+			Expect(getFailoverDuration(cluster, now)).To(Equal(5 * time.Minute))
+		})
+	})
+})
@@ -0,0 +1,189 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// importSourceCheckInterval is how often the operator checks whether the
+// temporary cluster, where the base backup to import from is being
+// recovered, is ready
+const importSourceCheckInterval = 10 * time.Second
+
+// ensureImportSourceIsReady creates the temporary cluster recovering the
+// base backup containing the database to be imported, and waits for it
+// to be ready before the primary instance is created
+func (r *ClusterReconciler) ensureImportSourceIsReady(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	var sourceCluster apiv1.Cluster
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetImportSourceClusterName()},
+		&sourceCluster)
+	if apierrs.IsNotFound(err) {
+		return r.createImportSourceCluster(ctx, cluster)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if sourceCluster.Status.ReadyInstances > 0 && sourceCluster.Status.Phase == apiv1.PhaseHealthy {
+		contextLogger.Info("The base backup to import from has been recovered", "name", sourceCluster.Name)
+		return ctrl.Result{}, nil
+	}
+
+	sourcePhase := sourceCluster.Status.Phase
+	if sourcePhase == "" {
+		sourcePhase = "pending"
+	}
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseImportSourceRecovery,
+		fmt.Sprintf("Waiting for cluster %s to recover the base backup: %s", sourceCluster.Name, sourcePhase),
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: importSourceCheckInterval}, nil
+}
+
+// createImportSourceCluster creates the temporary cluster recovering
+// the base backup containing the database to be imported
+func (r *ClusterReconciler) createImportSourceCluster(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	sourceCluster := buildImportSourceCluster(cluster)
+	if err := ctrl.SetControllerReference(cluster, sourceCluster, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	contextLogger.Info("Creating the cluster to recover the base backup to import from", "name", sourceCluster.Name)
+	if err := r.Create(ctx, sourceCluster); err != nil && !apierrs.IsAlreadyExists(err) {
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "CreatingImportSource",
+		"Recovering the base backup of %s into cluster %s",
+		cluster.Spec.Bootstrap.InitDB.Import.Source.ExternalCluster, sourceCluster.Name)
+
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseImportSourceRecovery,
+		fmt.Sprintf("Creating cluster %s to recover the base backup", sourceCluster.Name),
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: importSourceCheckInterval}, nil
+}
+
+// buildImportSourceCluster builds the temporary cluster recovering the
+// base backup containing the database to be imported. It has a single
+// instance and no backup configuration, so that it never writes in the
+// object store of the external cluster
+func buildImportSourceCluster(cluster *apiv1.Cluster) *apiv1.Cluster {
+	source := cluster.Spec.Bootstrap.InitDB.Import.Source
+	fromBackup := source.FromBackup
+
+	var externalClusters []apiv1.ExternalCluster
+	if externalCluster, found := cluster.ExternalCluster(source.ExternalCluster); found {
+		externalClusters = append(externalClusters, *externalCluster.DeepCopy())
+	}
+
+	sourceCluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.GetImportSourceClusterName(),
+			Namespace: cluster.Namespace,
+		},
+		Spec: apiv1.ClusterSpec{
+			Instances:             1,
+			ImageName:             cluster.Spec.ImageName,
+			ImageCatalogRef:       cluster.Spec.ImageCatalogRef.DeepCopy(),
+			ImagePullSecrets:      cluster.Spec.ImagePullSecrets,
+			EnableSuperuserAccess: ptr.To(true),
+			StorageConfiguration:  *cluster.Spec.StorageConfiguration.DeepCopy(),
+			Resources:             *cluster.Spec.Resources.DeepCopy(),
+			Affinity:              *cluster.Spec.Affinity.DeepCopy(),
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				Recovery: &apiv1.BootstrapRecovery{
+					Source:         source.ExternalCluster,
+					RecoveryTarget: fromBackup.RecoveryTarget.DeepCopy(),
+				},
+			},
+			ExternalClusters: externalClusters,
+		},
+	}
+
+	if fromBackup.ImageName != "" {
+		sourceCluster.Spec.ImageName = fromBackup.ImageName
+		sourceCluster.Spec.ImageCatalogRef = nil
+	}
+
+	if fromBackup.Storage != nil {
+		sourceCluster.Spec.StorageConfiguration = *fromBackup.Storage.DeepCopy()
+	}
+
+	return sourceCluster
+}
+
+// deleteImportSourceCluster deletes the temporary cluster used to
+// import a database from a base backup, once the import is completed
+func (r *ClusterReconciler) deleteImportSourceCluster(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.ShouldImportFromBackup() || cluster.Status.ReadyInstances == 0 {
+		return nil
+	}
+
+	var sourceCluster apiv1.Cluster
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetImportSourceClusterName()},
+		&sourceCluster)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !sourceCluster.DeletionTimestamp.IsZero() || !metav1.IsControlledBy(&sourceCluster, cluster) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deleting the cluster used to import from a base backup", "name", sourceCluster.Name)
+	if err := r.Delete(ctx, &sourceCluster); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "DeletedImportSource",
+		"Deleted cluster %s, as the import has been completed", sourceCluster.Name)
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("import from a base backup", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-example-uid",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ImageName: "postgres:16",
+				StorageConfiguration: apiv1.StorageConfiguration{
+					Size: "1Gi",
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &apiv1.Import{
+							Type:      apiv1.MicroserviceSnapshotType,
+							Databases: []string{"shop"},
+							Source: apiv1.ImportSource{
+								ExternalCluster: "origin",
+								FromBackup: &apiv1.ImportFromBackup{
+									RecoveryTarget: &apiv1.RecoveryTarget{
+										TargetTime: "2024-05-01 10:00:00.00000+00",
+									},
+								},
+							},
+						},
+					},
+				},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name: "origin",
+						BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+							DestinationPath: "s3://backups/",
+						},
+					},
+					{
+						Name:                 "other",
+						ConnectionParameters: map[string]string{"host": "other"},
+					},
+				},
+			},
+		}
+	})
+
+	getSourceCluster := func(ctx SpecContext) (*apiv1.Cluster, error) {
+		var sourceCluster apiv1.Cluster
+		err := r.Get(ctx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      cluster.GetImportSourceClusterName(),
+		}, &sourceCluster)
+		return &sourceCluster, err
+	}
+
+	It("builds a single instance cluster recovering the base backup", func() {
+		sourceCluster := buildImportSourceCluster(cluster)
+		Expect(sourceCluster.Name).To(Equal("cluster-example-import-source"))
+		Expect(sourceCluster.Spec.Instances).To(Equal(1))
+		Expect(sourceCluster.Spec.ImageName).To(Equal("postgres:16"))
+		Expect(sourceCluster.Spec.Backup).To(BeNil())
+		Expect(*sourceCluster.Spec.EnableSuperuserAccess).To(BeTrue())
+		Expect(sourceCluster.Spec.Bootstrap.Recovery.Source).To(Equal("origin"))
+		Expect(sourceCluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetTime).
+			To(Equal("2024-05-01 10:00:00.00000+00"))
+		Expect(sourceCluster.Spec.ExternalClusters).To(HaveLen(1))
+		Expect(sourceCluster.Spec.ExternalClusters[0].Name).To(Equal("origin"))
+	})
+
+	It("uses the image and the storage requested for the temporary cluster", func() {
+		cluster.Spec.Bootstrap.InitDB.Import.Source.FromBackup.ImageName = "postgres:15"
+		cluster.Spec.Bootstrap.InitDB.Import.Source.FromBackup.Storage = &apiv1.StorageConfiguration{
+			Size: "100Gi",
+		}
+
+		sourceCluster := buildImportSourceCluster(cluster)
+		Expect(sourceCluster.Spec.ImageName).To(Equal("postgres:15"))
+		Expect(sourceCluster.Spec.StorageConfiguration.Size).To(Equal("100Gi"))
+	})
+
+	It("creates the temporary cluster and waits for it to be ready", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		result, err := r.ensureImportSourceIsReady(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(importSourceCheckInterval))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseImportSourceRecovery))
+
+		sourceCluster, err := getSourceCluster(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metav1.IsControlledBy(sourceCluster, cluster)).To(BeTrue())
+
+		result, err = r.ensureImportSourceIsReady(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(importSourceCheckInterval))
+		Expect(cluster.Status.PhaseReason).To(ContainSubstring("pending"))
+	})
+
+	It("proceeds when the temporary cluster is ready", func(ctx SpecContext) {
+		sourceCluster := buildImportSourceCluster(cluster)
+		sourceCluster.Status.ReadyInstances = 1
+		sourceCluster.Status.Phase = apiv1.PhaseHealthy
+		r = newFakeClusterReconciler(cluster, sourceCluster)
+
+		result, err := r.ensureImportSourceIsReady(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+	})
+
+	It("deletes the temporary cluster once the import is completed", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+		Expect(r.createImportSourceCluster(ctx, cluster)).Error().ToNot(HaveOccurred())
+
+		Expect(r.deleteImportSourceCluster(ctx, cluster)).To(Succeed())
+		_, err := getSourceCluster(ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster.Status.ReadyInstances = 1
+		Expect(r.deleteImportSourceCluster(ctx, cluster)).To(Succeed())
+		_, err = getSourceCluster(ctx)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
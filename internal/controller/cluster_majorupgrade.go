@@ -0,0 +1,351 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileMajorUpgrade upgrades the data directory of the primary instance
+// when the image of the cluster has a newer PostgreSQL major version than the
+// one of the data directory. The returned result is not nil when the
+// reconciliation loop needs to be stopped, as the upgrade is in progress
+func (r *ClusterReconciler) reconcileMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	job, err := r.getMajorUpgradeJob(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if job != nil {
+		return r.reconcileMajorUpgradeJob(ctx, cluster, job)
+	}
+
+	requestedMajor, err := getRequestedMajorVersion(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster.Status.PGDataImageInfo == nil {
+		if err := r.detectPGDataImageInfo(ctx, cluster, resources); err != nil {
+			return nil, err
+		}
+	}
+
+	// A cluster whose primary instance is not running yet has nothing to upgrade
+	if cluster.Status.PGDataImageInfo == nil {
+		return nil, nil
+	}
+
+	if cluster.Status.PGDataImageInfo.MajorVersion == requestedMajor ||
+		cluster.Status.CurrentPrimary == "" {
+		return nil, r.updatePGDataImageInfo(ctx, cluster, requestedMajor)
+	}
+
+	if cluster.Status.PGDataImageInfo.MajorVersion > requestedMajor {
+		return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed,
+			fmt.Sprintf("The data directory has PostgreSQL major version %d, which can't be downgraded to %d",
+				cluster.Status.PGDataImageInfo.MajorVersion, requestedMajor))
+	}
+
+	return r.startMajorUpgrade(ctx, cluster, resources, requestedMajor)
+}
+
+// startMajorUpgrade shuts down every instance of the cluster and creates the
+// job upgrading the data directory of the primary instance
+func (r *ClusterReconciler) startMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	requestedMajor int,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Status.Phase != apiv1.PhaseMajorUpgrade {
+		oldMajor := cluster.Status.PGDataImageInfo.MajorVersion
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+			fmt.Sprintf("Upgrading from PostgreSQL %d to %d", oldMajor, requestedMajor)); err != nil {
+			return nil, err
+		}
+		r.Recorder.Eventf(cluster, "Normal", "MajorUpgradeStarted",
+			"Upgrading the data directory from PostgreSQL %d to %d", oldMajor, requestedMajor)
+	}
+
+	// pg_upgrade needs every instance to be shut down
+	instancesRunning := false
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		instancesRunning = true
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		contextLogger.Info("Shutting down the instance to upgrade the PostgreSQL major version",
+			"instance", pod.Name)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	if instancesRunning {
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	var primaryPVC *corev1.PersistentVolumeClaim
+	for idx := range resources.pvcs.Items {
+		if resources.pvcs.Items[idx].Name == cluster.Status.CurrentPrimary {
+			primaryPVC = &resources.pvcs.Items[idx]
+			break
+		}
+	}
+	if primaryPVC == nil {
+		return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed,
+			fmt.Sprintf("Cannot find the PVC of the primary instance %s", cluster.Status.CurrentPrimary))
+	}
+
+	nodeSerial, err := specs.GetNodeSerial(primaryPVC.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	job := specs.CreateMajorUpgradeJob(*cluster, nodeSerial, cluster.Status.PGDataImageInfo.Image, requestedMajor)
+	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	contextLogger.Info("Creating the major upgrade job", "name", job.Name)
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	// The termination of the job will trigger a new reconciliation loop
+	return &ctrl.Result{}, nil
+}
+
+// reconcileMajorUpgradeJob waits for the upgrade job to be finished. When it
+// succeeds, the replicas are recreated from the upgraded primary instance
+func (r *ClusterReconciler) reconcileMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !job.DeletionTimestamp.IsZero() {
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	switch {
+	case utils.JobHasOneCompletion(*job):
+		upgradedInstance := job.Labels[utils.InstanceNameLabelName]
+		newMajor, err := strconv.Atoi(job.Annotations[utils.MajorVersionAnnotationName])
+		if err != nil {
+			return nil, fmt.Errorf("while parsing the major version of the upgrade job %s: %w", job.Name, err)
+		}
+
+		// The data directories of the replicas still have the old major
+		// version, they will be cloned again from the upgraded primary
+		for _, instanceName := range cluster.Status.InstanceNames {
+			if instanceName == upgradedInstance {
+				continue
+			}
+			contextLogger.Info("Deleting the PVCs of a replica not upgraded", "instance", instanceName)
+			if err := persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+				ctx,
+				r.Client,
+				cluster,
+				instanceName,
+				cluster.Namespace,
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{
+			Image:        job.Spec.Template.Spec.Containers[0].Image,
+			MajorVersion: newMajor,
+		}
+		if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return nil, err
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", "MajorUpgradeCompleted",
+			"The data directory has been upgraded to PostgreSQL %d", newMajor)
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, r.deleteMajorUpgradeJob(ctx, job)
+
+	case job.Status.Failed > 0:
+		requestedMajor, err := getRequestedMajorVersion(cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		var dataMajor int
+		if cluster.Status.PGDataImageInfo != nil {
+			dataMajor = cluster.Status.PGDataImageInfo.MajorVersion
+		}
+
+		// The user restored the previous image, aborting the upgrade
+		if dataMajor == requestedMajor {
+			r.Recorder.Eventf(cluster, "Normal", "MajorUpgradeAborted",
+				"The upgrade has been aborted, restarting with PostgreSQL %d", requestedMajor)
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, r.deleteMajorUpgradeJob(ctx, job)
+		}
+
+		if cluster.Status.Phase == apiv1.PhaseMajorUpgradeFailed {
+			return &ctrl.Result{}, nil
+		}
+
+		r.Recorder.Eventf(cluster, "Warning", "MajorUpgradeFailed",
+			"The major upgrade job %s failed", job.Name)
+		return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed,
+			fmt.Sprintf("The major upgrade job %s failed, check its logs. "+
+				"Restore an image with PostgreSQL %d to abort the upgrade, or delete the job to retry it",
+				job.Name, dataMajor))
+
+	default:
+		// The termination of the job will trigger a new reconciliation loop
+		return &ctrl.Result{}, nil
+	}
+}
+
+// getMajorUpgradeJob gets the job upgrading the data directory
+// of the primary instance, if there's one
+func (r *ClusterReconciler) getMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*batchv1.Job, error) {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{jobOwnerKey: cluster.Name},
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	for idx := range jobs.Items {
+		if specs.IsMajorUpgradeJob(jobs.Items[idx]) {
+			return &jobs.Items[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// deleteMajorUpgradeJob deletes the upgrade job together with its pods
+func (r *ClusterReconciler) deleteMajorUpgradeJob(ctx context.Context, job *batchv1.Job) error {
+	err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// updatePGDataImageInfo records the image and the PostgreSQL major version
+// of the data directory, which are the ones of the cluster when there's no
+// upgrade to be done
+func (r *ClusterReconciler) updatePGDataImageInfo(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	majorVersion int,
+) error {
+	if cluster.Status.Image == "" || cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	imageInfo := apiv1.ImageInfo{
+		Image:        cluster.Status.Image,
+		MajorVersion: majorVersion,
+	}
+	if cluster.Status.PGDataImageInfo != nil && *cluster.Status.PGDataImageInfo == imageInfo {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PGDataImageInfo = &imageInfo
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// detectPGDataImageInfo records the image and the PostgreSQL major version
+// of the data directory when they are unknown, i.e. for a cluster created
+// before they were tracked. They are the ones of the image of the running
+// primary instance, as the image requested in the spec may already have
+// been changed to a newer major version
+func (r *ClusterReconciler) detectPGDataImageInfo(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	if cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		image, err := specs.GetContainerImageName(*pod, specs.PostgresContainerName)
+		if err != nil {
+			return err
+		}
+		version, err := postgres.GetPostgresVersionFromTag(utils.GetImageTag(image))
+		if err != nil {
+			return fmt.Errorf("while detecting the PostgreSQL major version of the primary instance: %w", err)
+		}
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{
+			Image:        image,
+			MajorVersion: version / 10000,
+		}
+		return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	return nil
+}
+
+// getRequestedMajorVersion gets the PostgreSQL major
+// version of the image requested for the cluster
+func getRequestedMajorVersion(cluster *apiv1.Cluster) (int, error) {
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return 0, fmt.Errorf("while detecting the PostgreSQL major version of the cluster: %w", err)
+	}
+
+	return version / 10000, nil
+}
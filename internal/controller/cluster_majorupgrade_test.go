@@ -0,0 +1,227 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major upgrades", func() {
+	var (
+		r         ClusterReconciler
+		cluster   *apiv1.Cluster
+		resources *managedResources
+	)
+
+	makePVC := func(serial string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cluster-example-" + serial,
+				Namespace:   "default",
+				Annotations: map[string]string{utils.ClusterSerialAnnotationName: serial},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 2,
+				ImageName: "postgres:17.2",
+			},
+			Status: apiv1.ClusterStatus{
+				Image:          "postgres:17.2",
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstanceNames:  []string{"cluster-example-1", "cluster-example-2"},
+				PGDataImageInfo: &apiv1.ImageInfo{
+					Image:        "postgres:16.4",
+					MajorVersion: 16,
+				},
+			},
+		}
+
+		resources = &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{
+				Items: []corev1.PersistentVolumeClaim{makePVC("1"), makePVC("2")},
+			},
+		}
+	})
+
+	createUpgradeJob := func(ctx SpecContext) *batchv1.Job {
+		job := specs.CreateMajorUpgradeJob(*cluster, 1, "postgres:16.4", 17)
+		Expect(r.Create(ctx, job)).To(Succeed())
+		return job
+	}
+
+	makePrimaryPod := func(image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: specs.PostgresContainerName, Image: image}},
+			},
+		}
+	}
+
+	It("records the image of a cluster without a major upgrade to be done", func(ctx SpecContext) {
+		cluster.Status.PGDataImageInfo = nil
+		pod := makePrimaryPod("postgres:17.2")
+		resources.instances.Items = []corev1.Pod{*pod}
+		r = newFakeClusterReconciler(cluster, pod)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(cluster.Status.PGDataImageInfo).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:17.2",
+			MajorVersion: 17,
+		}))
+	})
+
+	It("detects the major version of the data directory from the running primary instance", func(ctx SpecContext) {
+		cluster.Status.PGDataImageInfo = nil
+		pod := makePrimaryPod("postgres:16.4")
+		resources.instances.Items = []corev1.Pod{*pod}
+		r = newFakeClusterReconciler(cluster, pod)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.PGDataImageInfo).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:16.4",
+			MajorVersion: 16,
+		}))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgrade))
+	})
+
+	It("waits for the primary instance to run to detect the major version of the data directory",
+		func(ctx SpecContext) {
+			cluster.Status.PGDataImageInfo = nil
+			r = newFakeClusterReconciler(cluster)
+
+			res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(BeNil())
+			Expect(cluster.Status.PGDataImageInfo).To(BeNil())
+		})
+
+	It("shuts down the instances before upgrading the data directory", func(ctx SpecContext) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"}}
+		resources.instances.Items = []corev1.Pod{*pod}
+		r = newFakeClusterReconciler(cluster, pod)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(res.RequeueAfter).ToNot(BeZero())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgrade))
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+		job, err := r.getMajorUpgradeJob(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job).To(BeNil())
+	})
+
+	It("creates the job upgrading the primary instance", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+
+		job, err := r.getMajorUpgradeJob(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(job).ToNot(BeNil())
+		Expect(job.Labels).To(HaveKeyWithValue(utils.InstanceNameLabelName, "cluster-example-1"))
+		Expect(job.Spec.Template.Spec.InitContainers).To(ContainElement(
+			HaveField("Image", "postgres:16.4")))
+	})
+
+	It("recreates the replicas once the upgrade is completed", func(ctx SpecContext) {
+		replicaPVC := makePVC("2")
+		r = newFakeClusterReconciler(cluster, &replicaPVC)
+		job := createUpgradeJob(ctx)
+		job.Status.Succeeded = 1
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		Expect(r.Status().Update(ctx, job)).To(Succeed())
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.PGDataImageInfo).To(Equal(&apiv1.ImageInfo{
+			Image:        "postgres:17.2",
+			MajorVersion: 17,
+		}))
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(&replicaPVC), &corev1.PersistentVolumeClaim{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		err = r.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("waits for the user to intervene when the upgrade fails", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+		job := createUpgradeJob(ctx)
+		job.Status.Failed = 1
+		Expect(r.Status().Update(ctx, job)).To(Succeed())
+
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseMajorUpgradeFailed))
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})).To(Succeed())
+	})
+
+	It("aborts a failed upgrade when the previous image is restored", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+		job := createUpgradeJob(ctx)
+		job.Status.Failed = 1
+		Expect(r.Status().Update(ctx, job)).To(Succeed())
+
+		cluster.Spec.ImageName = "postgres:16.4"
+		cluster.Status.Image = "postgres:16.4"
+		res, err := r.reconcileMajorUpgrade(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).ToNot(BeNil())
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
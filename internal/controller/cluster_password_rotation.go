@@ -0,0 +1,249 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// nextPasswordKey is the key of the secrets where the new password is
+// published during the overlap window preceding a rotation
+const nextPasswordKey = "next-password"
+
+// generatedCredentialSecret is a credential secret generated by the
+// operator, together with the function building it with a given password
+type generatedCredentialSecret struct {
+	name  string
+	build func(cluster *apiv1.Cluster, password string) *corev1.Secret
+}
+
+// reconcilePasswordRotation rotates the passwords stored in the secrets
+// generated by the operator when the interval of the password rotation
+// policy has elapsed since the last rotation. The new passwords are
+// applied to PostgreSQL by the primary instance, which watches the secrets
+func (r *ClusterReconciler) reconcilePasswordRotation(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// The passwords of a replica cluster are the ones of its source, and
+	// a cluster without a primary instance can't apply the new passwords
+	interval := cluster.GetPasswordRotationInterval()
+	if interval == 0 || cluster.IsReplica() || cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	// The schedule starts when the rotation is enabled, as the passwords
+	// have been generated at an unknown time
+	now := time.Now()
+	lastRotation := cluster.Status.LastPasswordRotationTime
+	if lastRotation == nil {
+		return r.setLastPasswordRotationTime(ctx, cluster, now)
+	}
+
+	nextRotation := lastRotation.Add(interval)
+	if now.Before(nextRotation.Add(-cluster.GetPasswordRotationOverlap())) {
+		return nil
+	}
+
+	secrets := getGeneratedCredentialSecrets(cluster)
+	if now.Before(nextRotation) {
+		for _, secret := range secrets {
+			if err := publishNextPassword(ctx, r.Client, cluster, secret, lastRotation.Time); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var rotatedSecrets []string
+	for _, secret := range secrets {
+		rotated, err := rotateClusterCredentialSecret(ctx, r.Client, cluster, secret, lastRotation.Time, now)
+		if err != nil {
+			return err
+		}
+		if rotated {
+			rotatedSecrets = append(rotatedSecrets, secret.name)
+		}
+	}
+
+	if len(rotatedSecrets) > 0 {
+		contextLogger.Info("Rotated the generated passwords", "secrets", rotatedSecrets)
+		r.Recorder.Eventf(cluster, "Normal", "PasswordsRotated",
+			"Rotated the passwords stored in the secrets %s", strings.Join(rotatedSecrets, ", "))
+	}
+
+	return r.setLastPasswordRotationTime(ctx, cluster, now)
+}
+
+// setLastPasswordRotationTime records the time of the
+// last password rotation in the status of the cluster
+func (r *ClusterReconciler) setLastPasswordRotationTime(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	rotationTime time.Time,
+) error {
+	origCluster := cluster.DeepCopy()
+	cluster.Status.LastPasswordRotationTime = &metav1.Time{Time: rotationTime}
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getGeneratedCredentialSecrets gets the credential secrets
+// which are generated by the operator for the passed cluster
+func getGeneratedCredentialSecrets(cluster *apiv1.Cluster) []generatedCredentialSecret {
+	var secrets []generatedCredentialSecret
+	if shouldCreateSuperuserSecret(cluster) {
+		secrets = append(secrets, generatedCredentialSecret{
+			name:  cluster.GetSuperuserSecretName(),
+			build: newSuperuserSecret,
+		})
+	}
+
+	if cluster.ShouldCreateApplicationSecret() {
+		secrets = append(secrets, generatedCredentialSecret{
+			name:  cluster.GetApplicationSecretName(),
+			build: newAppUserSecret,
+		})
+	}
+
+	return secrets
+}
+
+// getRotatableSecret gets the passed credential secret if it can be
+// rotated, which happens when it is owned by the cluster and its password
+// hasn't been rotated since the last rotation recorded in the cluster.
+// The latter happens when the status of the cluster couldn't be updated
+// after rotating the password, which mustn't be rotated again
+func getRotatableSecret(
+	ctx context.Context,
+	cli client.Client,
+	namespace string,
+	name string,
+	lastRotation time.Time,
+) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, owned := IsOwnedByCluster(&secret); !owned {
+		return nil, nil
+	}
+
+	rotationTime, err := time.Parse(time.RFC3339, secret.Annotations[utils.PasswordRotationTimeAnnotationName])
+	if err == nil && rotationTime.After(lastRotation) {
+		return nil, nil
+	}
+
+	return &secret, nil
+}
+
+// publishNextPassword adds the password which will be used after the next
+// rotation to the passed credential secret, so that the applications can
+// get ready for the rotation, while PostgreSQL still accepts the current one
+func publishNextPassword(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	secret generatedCredentialSecret,
+	lastRotation time.Time,
+) error {
+	currentSecret, err := getRotatableSecret(ctx, cli, cluster.Namespace, secret.name, lastRotation)
+	if err != nil || currentSecret == nil {
+		return err
+	}
+	if _, published := currentSecret.Data[nextPasswordKey]; published {
+		return nil
+	}
+
+	nextPassword, err := generatePassword()
+	if err != nil {
+		return err
+	}
+
+	patchedSecret := currentSecret.DeepCopy()
+	if patchedSecret.Data == nil {
+		patchedSecret.Data = make(map[string][]byte)
+	}
+	patchedSecret.Data[nextPasswordKey] = []byte(nextPassword)
+	return cli.Patch(ctx, patchedSecret, client.MergeFrom(currentSecret))
+}
+
+// rotateClusterCredentialSecret replaces the content of an existing
+// credential secret with a new password, which is the one published
+// during the overlap window, if any. Secrets not owned by the cluster
+// are provided by the user and are never changed
+func rotateClusterCredentialSecret(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	secret generatedCredentialSecret,
+	lastRotation time.Time,
+	now time.Time,
+) (bool, error) {
+	currentSecret, err := getRotatableSecret(ctx, cli, cluster.Namespace, secret.name, lastRotation)
+	if err != nil || currentSecret == nil {
+		return false, err
+	}
+
+	newPassword := string(currentSecret.Data[nextPasswordKey])
+	if newPassword == "" {
+		if newPassword, err = generatePassword(); err != nil {
+			return false, err
+		}
+	}
+
+	// The whole content of the secret is replaced, removing the next password
+	proposed := secret.build(cluster, newPassword)
+	patchedSecret := currentSecret.DeepCopy()
+	patchedSecret.Data = make(map[string][]byte, len(proposed.StringData))
+	for key, value := range proposed.StringData {
+		patchedSecret.Data[key] = []byte(value)
+	}
+	if patchedSecret.Annotations == nil {
+		patchedSecret.Annotations = make(map[string]string)
+	}
+	patchedSecret.Annotations[utils.PasswordRotationTimeAnnotationName] = now.Format(time.RFC3339)
+	if err := cli.Patch(ctx, patchedSecret, client.MergeFrom(currentSecret)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getPasswordRotationRequeueAfter gets the time after which the cluster
+// needs to be reconciled again to rotate the generated passwords, or zero
+// if they are not rotated
+func getPasswordRotationRequeueAfter(cluster *apiv1.Cluster) time.Duration {
+	interval := cluster.GetPasswordRotationInterval()
+	if interval == 0 || cluster.IsReplica() || cluster.Status.LastPasswordRotationTime == nil {
+		return 0
+	}
+
+	return max(time.Until(cluster.Status.LastPasswordRotationTime.Add(interval)), time.Second)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("password rotation", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       apiv1.ClusterKind,
+				APIVersion: apiv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:             1,
+				EnableSuperuserAccess: ptr.To(true),
+				PasswordRotation:      &apiv1.PasswordRotationConfiguration{IntervalDays: 30},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+			},
+		}
+	})
+
+	getPassword := func(ctx SpecContext, name string) string {
+		var secret corev1.Secret
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &secret)).To(Succeed())
+		return string(secret.Data["password"])
+	}
+
+	// createSecret creates the passed secret as the API server
+	// stores it, with the content moved to its data
+	createSecret := func(ctx SpecContext, secret *corev1.Secret) {
+		secret.Data = make(map[string][]byte, len(secret.StringData))
+		for key, value := range secret.StringData {
+			secret.Data[key] = []byte(value)
+		}
+		secret.StringData = nil
+		Expect(r.Create(ctx, secret)).To(Succeed())
+	}
+
+	createSecrets := func(ctx SpecContext) {
+		createSecret(ctx, newSuperuserSecret(cluster, "superuser-password"))
+		createSecret(ctx, newAppUserSecret(cluster, "app-password"))
+	}
+
+	It("starts the schedule without rotating the passwords", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+		createSecrets(ctx)
+		password := getPassword(ctx, cluster.GetSuperuserSecretName())
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.LastPasswordRotationTime).ToNot(BeNil())
+		Expect(getPassword(ctx, cluster.GetSuperuserSecretName())).To(Equal(password))
+	})
+
+	It("doesn't rotate the passwords before the interval elapsed", func(ctx SpecContext) {
+		lastRotation := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+		cluster.Status.LastPasswordRotationTime = &lastRotation
+		r = newFakeClusterReconciler(cluster)
+		createSecrets(ctx)
+		password := getPassword(ctx, cluster.GetApplicationSecretName())
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.LastPasswordRotationTime).To(Equal(&lastRotation))
+		Expect(getPassword(ctx, cluster.GetApplicationSecretName())).To(Equal(password))
+	})
+
+	It("rotates the generated passwords when the interval elapsed", func(ctx SpecContext) {
+		lastRotation := metav1.NewTime(time.Now().Add(-31 * 24 * time.Hour))
+		cluster.Status.LastPasswordRotationTime = &lastRotation
+		r = newFakeClusterReconciler(cluster)
+		createSecrets(ctx)
+		superuserPassword := getPassword(ctx, cluster.GetSuperuserSecretName())
+		appPassword := getPassword(ctx, cluster.GetApplicationSecretName())
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.LastPasswordRotationTime.After(lastRotation.Time)).To(BeTrue())
+		Expect(getPassword(ctx, cluster.GetSuperuserSecretName())).ToNot(Equal(superuserPassword))
+		Expect(getPassword(ctx, cluster.GetApplicationSecretName())).ToNot(Equal(appPassword))
+		Expect(r.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("PasswordsRotated")))
+	})
+
+	It("never changes the secrets provided by the user", func(ctx SpecContext) {
+		lastRotation := metav1.NewTime(time.Now().Add(-31 * 24 * time.Hour))
+		cluster.Status.LastPasswordRotationTime = &lastRotation
+		r = newFakeClusterReconciler(cluster)
+		createSecret(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: cluster.GetApplicationSecretName(), Namespace: "default"},
+			StringData: map[string]string{"username": "app", "password": "secret"},
+		})
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(getPassword(ctx, cluster.GetApplicationSecretName())).To(Equal("secret"))
+	})
+
+	It("doesn't rotate again a password rotated after the last recorded rotation", func(ctx SpecContext) {
+		lastRotation := metav1.NewTime(time.Now().Add(-31 * 24 * time.Hour))
+		cluster.Status.LastPasswordRotationTime = &lastRotation
+		r = newFakeClusterReconciler(cluster)
+		appSecret := newAppUserSecret(cluster, "app-password")
+		appSecret.Annotations = map[string]string{
+			utils.PasswordRotationTimeAnnotationName: time.Now().Add(-time.Minute).Format(time.RFC3339),
+		}
+		createSecret(ctx, appSecret)
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.LastPasswordRotationTime.After(lastRotation.Time)).To(BeTrue())
+		Expect(getPassword(ctx, cluster.GetApplicationSecretName())).To(Equal("app-password"))
+	})
+
+	It("publishes the next passwords during the overlap window", func(ctx SpecContext) {
+		cluster.Spec.PasswordRotation.OverlapMinutes = 60
+		lastRotation := metav1.NewTime(time.Now().Add(-30*24*time.Hour + 30*time.Minute))
+		cluster.Status.LastPasswordRotationTime = &lastRotation
+		r = newFakeClusterReconciler(cluster)
+		createSecrets(ctx)
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.LastPasswordRotationTime).To(Equal(&lastRotation))
+		Expect(getPassword(ctx, cluster.GetApplicationSecretName())).To(Equal("app-password"))
+
+		var appSecret corev1.Secret
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "default", Name: cluster.GetApplicationSecretName()},
+			&appSecret)).To(Succeed())
+		nextPassword := string(appSecret.Data[nextPasswordKey])
+		Expect(nextPassword).ToNot(BeEmpty())
+
+		By("applying the published password when the interval elapsed", func() {
+			cluster.Status.LastPasswordRotationTime = ptr.To(metav1.NewTime(lastRotation.Add(-time.Hour)))
+			Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "default", Name: cluster.GetApplicationSecretName()},
+				&appSecret)).To(Succeed())
+			Expect(string(appSecret.Data["password"])).To(Equal(nextPassword))
+			Expect(appSecret.Data).ToNot(HaveKey(nextPasswordKey))
+			Expect(appSecret.Annotations).To(HaveKey(utils.PasswordRotationTimeAnnotationName))
+		})
+	})
+
+	It("doesn't rotate the passwords of a replica cluster", func(ctx SpecContext) {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: ptr.To(true), Source: "origin"}
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.reconcilePasswordRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.LastPasswordRotationTime).To(BeNil())
+	})
+
+	It("requeues the cluster in time for the next rotation", func() {
+		Expect(getPasswordRotationRequeueAfter(cluster)).To(BeZero())
+
+		cluster.Status.LastPasswordRotationTime = ptr.To(metav1.NewTime(time.Now().Add(-30*24*time.Hour + time.Minute)))
+		Expect(getPasswordRotationRequeueAfter(cluster)).To(BeNumerically("<=", time.Minute))
+
+		cluster.Status.LastPasswordRotationTime = ptr.To(metav1.NewTime(time.Now().Add(-31 * 24 * time.Hour)))
+		Expect(getPasswordRotationRequeueAfter(cluster)).To(Equal(time.Second))
+	})
+})
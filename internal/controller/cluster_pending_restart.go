@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcilePendingRestart records in the status of the cluster the restarts
+// required to apply the changes to the PostgreSQL parameters which are
+// deferred by the restart strategy, and removes the approval of the user
+// once every instance has been restarted
+func (r *ClusterReconciler) reconcilePendingRestart(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	pendingRestart := newPendingRestartStatus(cluster, instancesStatus, time.Now())
+	if !reflect.DeepEqual(cluster.Status.PendingRestart, pendingRestart) {
+		if pendingRestart != nil && cluster.Status.PendingRestart == nil {
+			contextLogger.Info("Deferring the restart required by the changes of the PostgreSQL parameters",
+				"strategy", pendingRestart.Strategy,
+				"instances", pendingRestart.Instances,
+				"parameters", pendingRestart.Parameters)
+			r.Recorder.Eventf(cluster, "Normal", "RestartDeferred",
+				"Restart of %v deferred by the %s strategy", pendingRestart.Instances, pendingRestart.Strategy)
+		}
+
+		origCluster := cluster.DeepCopy()
+		cluster.Status.PendingRestart = pendingRestart
+		if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return err
+		}
+	}
+
+	if _, approved := cluster.Annotations[utils.RestartApprovalAnnotationName]; !approved ||
+		len(getInstancesPendingRestart(instancesStatus)) > 0 {
+		return nil
+	}
+
+	contextLogger.Info("The approved restart has been completed")
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.RestartApprovalAnnotationName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// newPendingRestartStatus creates the status of the restarts which are
+// deferred at the passed time, returning nil when there are none
+func newPendingRestartStatus(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	now time.Time,
+) *apiv1.PendingRestartStatus {
+	instances := getInstancesPendingRestart(instancesStatus)
+	if len(instances) == 0 || cluster.IsParameterChangeRestartAllowed(now) {
+		return nil
+	}
+
+	parameters := stringset.New()
+	for _, item := range instancesStatus.Items {
+		for _, parameter := range item.PendingRestartParameters {
+			parameters.Put(parameter)
+		}
+	}
+
+	result := &apiv1.PendingRestartStatus{
+		Strategy:  cluster.GetParameterChangeRestartStrategy(),
+		Instances: instances,
+	}
+	if parameters.Len() > 0 {
+		result.Parameters = parameters.ToSortedList()
+	}
+	if window := cluster.Spec.ParameterChangeRestart.MaintenanceWindow; window != nil &&
+		result.Strategy == apiv1.ParameterChangeRestartStrategyMaintenanceWindow {
+		if nextStart, err := window.GetNextStart(now); err == nil {
+			result.NextMaintenanceWindow = &metav1.Time{Time: nextStart}
+		}
+	}
+
+	return result
+}
+
+// getInstancesPendingRestart gets the sorted names of the instances
+// waiting for a restart to apply the changes to the PostgreSQL parameters
+func getInstancesPendingRestart(instancesStatus postgres.PostgresqlStatusList) []string {
+	var result []string
+	for _, item := range instancesStatus.Items {
+		if item.Pod != nil && (item.PendingRestart || item.PendingRestartForDecrease) {
+			result = append(result, item.Pod.Name)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// getPendingRestartRequeueAfter gets the time after which the cluster
+// needs to be reconciled again, when a restart is deferred until the
+// maintenance window opens, as nothing else may trigger a reconciliation.
+// It is zero when no restart is deferred
+func getPendingRestartRequeueAfter(cluster *apiv1.Cluster, now time.Time) time.Duration {
+	pendingRestart := cluster.Status.PendingRestart
+	if pendingRestart == nil || pendingRestart.NextMaintenanceWindow == nil {
+		return 0
+	}
+
+	return max(pendingRestart.NextMaintenanceWindow.Sub(now), time.Second)
+}
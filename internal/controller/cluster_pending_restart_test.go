@@ -0,0 +1,149 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("deferred restarts", func() {
+	var (
+		r               ClusterReconciler
+		cluster         *apiv1.Cluster
+		instancesStatus postgres.PostgresqlStatusList
+	)
+
+	buildStatus := func(name string, pendingRestart bool, parameters ...string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPodReady:               true,
+			PendingRestart:           pendingRestart,
+			PendingRestartParameters: parameters,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				ParameterChangeRestart: &apiv1.ParameterChangeRestartConfiguration{
+					Strategy: apiv1.ParameterChangeRestartStrategyManual,
+				},
+			},
+		}
+
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1", true, "max_connections", "shared_buffers"),
+				buildStatus("cluster-example-3", true, "max_connections"),
+				buildStatus("cluster-example-2", false),
+			},
+		}
+	})
+
+	It("doesn't defer the restarts with the immediate strategy", func() {
+		cluster.Spec.ParameterChangeRestart = nil
+		Expect(newPendingRestartStatus(cluster, instancesStatus, time.Now())).To(BeNil())
+	})
+
+	It("lists the instances and the parameters waiting for the restart", func() {
+		pendingRestart := newPendingRestartStatus(cluster, instancesStatus, time.Now())
+		Expect(pendingRestart).ToNot(BeNil())
+		Expect(pendingRestart.Strategy).To(Equal(apiv1.ParameterChangeRestartStrategyManual))
+		Expect(pendingRestart.Instances).To(Equal([]string{"cluster-example-1", "cluster-example-3"}))
+		Expect(pendingRestart.Parameters).To(Equal([]string{"max_connections", "shared_buffers"}))
+		Expect(pendingRestart.NextMaintenanceWindow).To(BeNil())
+	})
+
+	It("reports when the next maintenance window opens", func() {
+		cluster.Spec.ParameterChangeRestart = &apiv1.ParameterChangeRestartConfiguration{
+			Strategy: apiv1.ParameterChangeRestartStrategyMaintenanceWindow,
+			MaintenanceWindow: &apiv1.MaintenanceWindowConfiguration{
+				Schedule: "0 0 2 * * *",
+			},
+		}
+		now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+		pendingRestart := newPendingRestartStatus(cluster, instancesStatus, now)
+		Expect(pendingRestart).ToNot(BeNil())
+		Expect(pendingRestart.NextMaintenanceWindow.Time).To(Equal(time.Date(2024, 1, 11, 2, 0, 0, 0, time.Local)))
+	})
+
+	It("doesn't roll out the deferred restarts", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.reconcilePendingRestart(ctx, cluster, instancesStatus)).To(Succeed())
+		Expect(cluster.Status.PendingRestart).ToNot(BeNil())
+
+		podRollout, err := checkPostgresPendingRestart(instancesStatus.Items[0], cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podRollout.required).To(BeFalse())
+	})
+
+	It("rolls out the approved restarts and then removes the approval", func(ctx SpecContext) {
+		cluster.Annotations = map[string]string{utils.RestartApprovalAnnotationName: "true"}
+		cluster.Status.PendingRestart = &apiv1.PendingRestartStatus{
+			Strategy:  apiv1.ParameterChangeRestartStrategyManual,
+			Instances: []string{"cluster-example-1"},
+		}
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.reconcilePendingRestart(ctx, cluster, instancesStatus)).To(Succeed())
+		Expect(cluster.Status.PendingRestart).To(BeNil())
+		Expect(cluster.Annotations).To(HaveKey(utils.RestartApprovalAnnotationName))
+
+		podRollout, err := checkPostgresPendingRestart(instancesStatus.Items[0], cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(podRollout.required).To(BeTrue())
+
+		for idx := range instancesStatus.Items {
+			instancesStatus.Items[idx].PendingRestart = false
+		}
+		Expect(r.reconcilePendingRestart(ctx, cluster, instancesStatus)).To(Succeed())
+
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.RestartApprovalAnnotationName))
+		Expect(updatedCluster.Status.PendingRestart).To(BeNil())
+	})
+
+	It("requeues when the maintenance window opens", func() {
+		now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+		Expect(getPendingRestartRequeueAfter(cluster, now)).To(BeZero())
+
+		cluster.Status.PendingRestart = &apiv1.PendingRestartStatus{
+			Strategy:              apiv1.ParameterChangeRestartStrategyMaintenanceWindow,
+			NextMaintenanceWindow: &metav1.Time{Time: now.Add(14 * time.Hour)},
+		}
+		Expect(getPendingRestartRequeueAfter(cluster, now)).To(Equal(14 * time.Hour))
+		Expect(getPendingRestartRequeueAfter(cluster, now.Add(15*time.Hour))).To(Equal(time.Second))
+	})
+})
@@ -23,6 +23,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -198,7 +199,7 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.GetNamespace(), Name: secretName}, &secret)
 	if err == nil {
 		// Verify the validity of this CA and renew it if needed
-		err = r.renewCASecret(ctx, &secret)
+		err = r.renewCASecret(ctx, &secret, getPendingCertificatesRotation(cluster, &secret))
 		if err != nil {
 			return nil, err
 		}
@@ -214,14 +215,18 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	}
 
 	derivedCaSecret := caPair.GenerateCASecret(cluster.Namespace, secretName)
-	utils.SetAsOwnedBy(&derivedCaSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	cluster.SetInheritedDataAndOwnership(&derivedCaSecret.ObjectMeta)
 	err = r.Create(ctx, derivedCaSecret)
 
 	return derivedCaSecret, err
 }
 
-// renewCASecret check if this CA secret is valid and renew it if needed
-func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret) error {
+// renewCASecret check if this CA secret is valid and renew it if needed,
+// or if the passed rotation request is not empty. When renewed because
+// expiring, the private key is kept, so that the certificates signed by
+// the CA are still valid. When requested, a new private key is generated,
+// and the certificates signed by the CA must be renewed too
+func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret, rotationRequest string) error {
 	pair, err := certs.ParseCASecret(secret)
 	if err != nil {
 		return err
@@ -231,10 +236,18 @@ func (r *ClusterReconciler) renewCASecret(ctx context.Context, secret *v1.Secret
 	if err != nil {
 		return err
 	}
-	if !expiring {
+	if !expiring && rotationRequest == "" {
 		return nil
 	}
 
+	if rotationRequest != "" {
+		if err := pair.RenewPrivateKey(); err != nil {
+			return err
+		}
+		secret.Data[certs.CAPrivateKeyKey] = pair.Private
+		markCertificatesRotation(secret, rotationRequest)
+	}
+
 	privateKey, err := pair.ParseECPrivateKey()
 	if err != nil {
 		return err
@@ -330,7 +343,8 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	var secret v1.Secret
 	err := r.Get(ctx, secretName, &secret)
 	if err == nil {
-		return r.renewAndUpdateCertificate(ctx, caSecret, &secret, altDNSNames)
+		return r.renewAndUpdateCertificate(
+			ctx, caSecret, &secret, altDNSNames, getPendingCertificatesRotation(cluster, &secret))
 	}
 
 	serverSecret, err := generateCertificateFromCA(caSecret, commonName, usage, altDNSNames, secretName)
@@ -338,7 +352,7 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 		return err
 	}
 
-	utils.SetAsOwnedBy(&serverSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	cluster.SetInheritedDataAndOwnership(&serverSecret.ObjectMeta)
 	for k, v := range additionalLabels {
 		if serverSecret.Labels == nil {
 			serverSecret.Labels = make(map[string]string)
@@ -371,14 +385,23 @@ func generateCertificateFromCA(
 }
 
 // renewAndUpdateCertificate renew a certificate giving the certificate that contains the CA that sign it and update
-// the secret
+// the secret. When the passed rotation request is not empty, the certificate is renewed even if it's not expiring
 func (r *ClusterReconciler) renewAndUpdateCertificate(
 	ctx context.Context,
 	caSecret *v1.Secret,
 	secret *v1.Secret,
 	altDNSNames []string,
+	rotationRequest string,
 ) error {
 	origSecret := secret.DeepCopy()
+	if rotationRequest != "" {
+		if err := certs.ForceRenewLeafCertificate(caSecret, secret, altDNSNames); err != nil {
+			return err
+		}
+		markCertificatesRotation(secret, rotationRequest)
+		return r.Patch(ctx, secret, client.MergeFrom(origSecret))
+	}
+
 	hasBeenRenewed, err := certs.RenewLeafCertificate(caSecret, secret, altDNSNames)
 	if err != nil {
 		return err
@@ -389,3 +412,84 @@ func (r *ClusterReconciler) renewAndUpdateCertificate(
 
 	return nil
 }
+
+// isCertificatesRotationRequested checks if the user requested the
+// immediate renewal of the certificates managed by the operator
+func isCertificatesRotationRequested(cluster *apiv1.Cluster) bool {
+	_, ok := cluster.Annotations[utils.CertificatesRotationAnnotationName]
+	return ok
+}
+
+// getPendingCertificatesRotation gets the request of renewal of the
+// certificates of the cluster, if the passed secret has not been renewed
+// for it yet. The same secret can be used for more than one certificate,
+// like the server and the client CA, and must be renewed only once
+func getPendingCertificatesRotation(cluster *apiv1.Cluster, secret *v1.Secret) string {
+	request, ok := cluster.Annotations[utils.CertificatesRotationAnnotationName]
+	if !ok || secret.Annotations[utils.CertificatesRotationAnnotationName] == request {
+		return ""
+	}
+
+	return request
+}
+
+// markCertificatesRotation records in the passed secret
+// that it has been renewed for the passed request
+func markCertificatesRotation(secret *v1.Secret, rotationRequest string) {
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[utils.CertificatesRotationAnnotationName] = rotationRequest
+}
+
+// completeCertificatesRotation records the renewal of the certificates
+// in the cluster status and removes the request. The instances will
+// reload PostgreSQL as soon as they see the new certificates, without
+// interrupting the existing connections
+func (r *ClusterReconciler) completeCertificatesRotation(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !isCertificatesRotationRequested(cluster) {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Info("Renewed the certificates managed by the operator, as requested")
+	r.Recorder.Event(cluster, "Normal", "CertificatesRotated",
+		"Renewed the certificates managed by the operator")
+	if userProvided := getUserProvidedCertificateSecrets(cluster); len(userProvided) > 0 {
+		r.Recorder.Eventf(cluster, "Warning", "CertificatesNotRotated",
+			"The user-provided certificates must be renewed by their issuer: %v", userProvided)
+	}
+
+	origCluster := cluster.DeepCopy()
+	now := metav1.Now()
+	cluster.Status.Certificates.LastRotationTime = &now
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	origCluster = cluster.DeepCopy()
+	delete(cluster.Annotations, utils.CertificatesRotationAnnotationName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getUserProvidedCertificateSecrets gets the names of the secrets containing
+// the certificates which are not managed by the operator
+func getUserProvidedCertificateSecrets(cluster *apiv1.Cluster) []string {
+	if cluster.Spec.Certificates == nil {
+		return nil
+	}
+
+	var result []string
+	for _, secretName := range []string{
+		cluster.Spec.Certificates.ServerCASecret,
+		cluster.Spec.Certificates.ServerTLSSecret,
+		cluster.Spec.Certificates.ClientCASecret,
+		cluster.Spec.Certificates.ReplicationTLSSecret,
+	} {
+		if secretName != "" {
+			result = append(result, secretName)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("requested certificates rotation", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.CertificatesRotationAnnotationName: "2024-01-01T00:00:00Z",
+				},
+			},
+		}
+	})
+
+	It("detects the request", func() {
+		Expect(isCertificatesRotationRequested(cluster)).To(BeTrue())
+		delete(cluster.Annotations, utils.CertificatesRotationAnnotationName)
+		Expect(isCertificatesRotationRequested(cluster)).To(BeFalse())
+	})
+
+	It("does nothing without a request", func(ctx SpecContext) {
+		delete(cluster.Annotations, utils.CertificatesRotationAnnotationName)
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.completeCertificatesRotation(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.Certificates.LastRotationTime).To(BeNil())
+	})
+
+	It("records the rotation and removes the request", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.completeCertificatesRotation(ctx, cluster)).To(Succeed())
+
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.CertificatesRotationAnnotationName))
+		Expect(updatedCluster.Status.Certificates.LastRotationTime).ToNot(BeNil())
+	})
+
+	It("renews the CA with a new private key only when requested", func(ctx SpecContext) {
+		ca, err := certs.CreateRootCA("root", "default")
+		Expect(err).ToNot(HaveOccurred())
+		secret := ca.GenerateCASecret("default", "cluster-example-ca")
+		r = newFakeClusterReconciler(cluster)
+		Expect(r.Create(ctx, secret)).To(Succeed())
+
+		Expect(r.renewCASecret(ctx, secret, "")).To(Succeed())
+		Expect(secret.Data[certs.CACertKey]).To(Equal(ca.Certificate))
+		Expect(secret.Data[certs.CAPrivateKeyKey]).To(Equal(ca.Private))
+
+		Expect(r.renewCASecret(ctx, secret, "2024-01-01T00:00:00Z")).To(Succeed())
+		var renewedSecret corev1.Secret
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(secret), &renewedSecret)).To(Succeed())
+		renewedPair, err := certs.ParseCASecret(&renewedSecret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewedPair.Certificate).ToNot(Equal(ca.Certificate))
+		Expect(renewedPair.Private).ToNot(Equal(ca.Private))
+		Expect(getPendingCertificatesRotation(cluster, &renewedSecret)).To(BeEmpty())
+	})
+
+	It("renews each secret only once for each request", func() {
+		secret := &corev1.Secret{}
+		Expect(getPendingCertificatesRotation(cluster, secret)).To(Equal("2024-01-01T00:00:00Z"))
+
+		markCertificatesRotation(secret, "2024-01-01T00:00:00Z")
+		Expect(getPendingCertificatesRotation(cluster, secret)).To(BeEmpty())
+
+		cluster.Annotations[utils.CertificatesRotationAnnotationName] = "2024-02-01T00:00:00Z"
+		Expect(getPendingCertificatesRotation(cluster, secret)).To(Equal("2024-02-01T00:00:00Z"))
+	})
+
+	It("lists the user-provided certificates which won't be rotated", func() {
+		Expect(getUserProvidedCertificateSecrets(cluster)).To(BeEmpty())
+		cluster.Spec.Certificates = &apiv1.CertificatesConfiguration{
+			ServerTLSSecret: "my-server-tls",
+			ServerCASecret:  "my-server-ca",
+		}
+		Expect(getUserProvidedCertificateSecrets(cluster)).To(ConsistOf("my-server-tls", "my-server-ca"))
+	})
+})
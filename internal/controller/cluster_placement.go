@@ -0,0 +1,173 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+)
+
+// nodeNameField is the only field of the nodes which
+// can be used in the node selector terms
+const nodeNameField = "metadata.name"
+
+// isPlacementCompatibleWithStorage checks whether the pod of the passed
+// instance, once recreated with the passed spec, can be scheduled on a node
+// where its persistent volumes can be attached. This is not the case, for
+// example, when the volumes are local or bound to an availability zone
+// not allowed by the node selector or the node affinity of the pod
+func (r *ClusterReconciler) isPlacementCompatibleWithStorage(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instanceName string,
+	podSpec corev1.PodSpec,
+) (bool, error) {
+	pvcs, err := persistentvolumeclaim.GetInstancePVCs(ctx, r.Client, instanceName, cluster.Namespace)
+	if err != nil {
+		return false, err
+	}
+
+	var volumeTerms [][]corev1.NodeSelectorTerm
+	for _, pvc := range pvcs {
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+
+		var pv corev1.PersistentVolume
+		if err := r.Get(ctx, client.ObjectKey{Name: pvc.Spec.VolumeName}, &pv); err != nil {
+			return false, fmt.Errorf("while getting the volume of PVC %s: %w", pvc.Name, err)
+		}
+
+		if pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil {
+			volumeTerms = append(volumeTerms, pv.Spec.NodeAffinity.Required.NodeSelectorTerms)
+		}
+	}
+
+	if len(volumeTerms) == 0 {
+		return true, nil
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		return false, err
+	}
+
+	for i := range nodes.Items {
+		if isNodeCompatible(&nodes.Items[i], podSpec, volumeTerms) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// isNodeCompatible checks whether the passed node matches the node selector
+// and the required node affinity of the pod, and the node affinity of its
+// volumes. The taints of the node are not considered
+func isNodeCompatible(node *corev1.Node, podSpec corev1.PodSpec, volumeTerms [][]corev1.NodeSelectorTerm) bool {
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+
+	if podSpec.Affinity != nil && podSpec.Affinity.NodeAffinity != nil &&
+		podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		!matchesNodeSelectorTerms(
+			node,
+			podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+		return false
+	}
+
+	for _, terms := range volumeTerms {
+		if !matchesNodeSelectorTerms(node, terms) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesNodeSelectorTerms checks whether the passed node matches
+// at least one of the passed node selector terms
+func matchesNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if matchesNodeSelectorTerm(node, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesNodeSelectorTerm checks whether the passed node matches every
+// requirement of the passed node selector term. An empty term matches
+// no node, as it happens in Kubernetes
+func matchesNodeSelectorTerm(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	fields := labels.Set{nodeNameField: node.Name}
+	for _, requirement := range term.MatchExpressions {
+		if !matchesNodeSelectorRequirement(labels.Set(node.Labels), requirement) {
+			return false
+		}
+	}
+	for _, requirement := range term.MatchFields {
+		if requirement.Key != nodeNameField || !matchesNodeSelectorRequirement(fields, requirement) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesNodeSelectorRequirement checks whether the passed
+// set of labels matches the node selector requirement
+func matchesNodeSelectorRequirement(set labels.Set, requirement corev1.NodeSelectorRequirement) bool {
+	var operator selection.Operator
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		operator = selection.In
+	case corev1.NodeSelectorOpNotIn:
+		operator = selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		operator = selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		operator = selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		operator = selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		operator = selection.LessThan
+	default:
+		return false
+	}
+
+	labelRequirement, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+	if err != nil {
+		return false
+	}
+
+	return labelRequirement.Matches(set)
+}
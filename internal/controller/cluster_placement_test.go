@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("placement compatible with the storage", func() {
+	const zoneLabel = "topology.kubernetes.io/zone"
+
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	newNode := func(name, zone string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{zoneLabel: zone, "pool": "pool-" + zone},
+			},
+		}
+	}
+
+	zoneSelector := func(zone string) *corev1.VolumeNodeAffinity {
+		return &corev1.VolumeNodeAffinity{
+			Required: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      zoneLabel,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{zone},
+					}},
+				}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-2",
+				Namespace: "default",
+				Labels: map[string]string{
+					utils.InstanceNameLabelName: "cluster-example-2",
+					utils.PvcRoleLabelName:      string(utils.PVCRolePgData),
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-a"},
+		}
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-a"},
+			Spec:       corev1.PersistentVolumeSpec{NodeAffinity: zoneSelector("a")},
+		}
+
+		r = newFakeClusterReconciler(cluster, pvc, pv, newNode("node-a", "a"), newNode("node-b", "b"))
+	})
+
+	It("accepts a node selector matching a node which can attach the volumes", func(ctx SpecContext) {
+		podSpec := corev1.PodSpec{NodeSelector: map[string]string{"pool": "pool-a"}}
+		Expect(r.isPlacementCompatibleWithStorage(ctx, cluster, "cluster-example-2", podSpec)).To(BeTrue())
+	})
+
+	It("rejects a node selector matching only nodes which can't attach the volumes", func(ctx SpecContext) {
+		podSpec := corev1.PodSpec{NodeSelector: map[string]string{"pool": "pool-b"}}
+		Expect(r.isPlacementCompatibleWithStorage(ctx, cluster, "cluster-example-2", podSpec)).To(BeFalse())
+	})
+
+	It("considers the required node affinity of the pod", func(ctx SpecContext) {
+		podSpec := corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: zoneSelector("b").Required,
+				},
+			},
+		}
+		Expect(r.isPlacementCompatibleWithStorage(ctx, cluster, "cluster-example-2", podSpec)).To(BeFalse())
+	})
+
+	It("accepts any placement for the volumes without node affinity", func(ctx SpecContext) {
+		podSpec := corev1.PodSpec{NodeSelector: map[string]string{"pool": "pool-b"}}
+		Expect(r.isPlacementCompatibleWithStorage(ctx, cluster, "cluster-example-3", podSpec)).To(BeTrue())
+	})
+
+	DescribeTable("matches the node selector terms",
+		func(term corev1.NodeSelectorTerm, expected bool) {
+			Expect(matchesNodeSelectorTerm(newNode("node-a", "a"), term)).To(Equal(expected))
+		},
+		Entry("with an empty term", corev1.NodeSelectorTerm{}, false),
+		Entry("with a matching expression", corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: zoneLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"b"}},
+			},
+		}, true),
+		Entry("with a matching node name", corev1.NodeSelectorTerm{
+			MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}},
+			},
+		}, true),
+		Entry("with a different node name", corev1.NodeSelectorTerm{
+			MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-b"}},
+			},
+		}, false),
+	)
+})
@@ -0,0 +1,235 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileQuarantine fences the instances that keep crashing, when the
+// quarantine is enabled, and keeps the list of the quarantined instances
+// in the cluster status up to date
+func (r *ClusterReconciler) reconcileQuarantine(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var toBeQuarantined []*corev1.Pod
+	now := time.Now()
+	for idx := range instances {
+		pod := &instances[idx]
+		if !isQuarantineCandidate(cluster, pod) {
+			continue
+		}
+
+		window, changed := getRestartWindow(cluster, pod, now)
+		if changed {
+			if err := r.setRestartWindow(ctx, pod, window); err != nil {
+				return err
+			}
+		}
+
+		if shouldQuarantineInstance(cluster, pod, window) {
+			toBeQuarantined = append(toBeQuarantined, pod)
+		}
+	}
+
+	if len(toBeQuarantined) > 0 {
+		origCluster := cluster.DeepCopy()
+		for _, pod := range toBeQuarantined {
+			if _, err := utils.AddFencedInstance(pod.Name, cluster); err != nil {
+				return err
+			}
+		}
+		if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return err
+		}
+
+		for _, pod := range toBeQuarantined {
+			// A new restart window will start when the instance is unfenced
+			if err := r.resetRestartWindow(ctx, pod); err != nil {
+				return err
+			}
+
+			restartCount := getPostgresContainerRestartCount(pod)
+			contextLogger.Warning("Quarantining an instance that keeps crashing",
+				"instance", pod.Name,
+				"restartCount", restartCount,
+				"restartWindow", cluster.Spec.Quarantine.GetRestartWindow())
+			r.Recorder.Eventf(cluster, "Warning", "QuarantinedInstance",
+				"Fenced instance %s as it has been restarted at least %d times in %s and it is still crashing",
+				pod.Name, cluster.Spec.Quarantine.MaxRestarts, cluster.Spec.Quarantine.GetRestartWindow())
+		}
+	}
+
+	quarantinedInstances := getQuarantinedInstances(cluster, instances, toBeQuarantined)
+	if slices.Equal(quarantinedInstances, cluster.Status.QuarantinedInstances) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.QuarantinedInstances = quarantinedInstances
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// isQuarantineCandidate checks whether an instance can be quarantined.
+// The primary instance is never quarantined, as doing so would prevent
+// the failover.
+func isQuarantineCandidate(cluster *apiv1.Cluster, pod *corev1.Pod) bool {
+	if cluster.Spec.Quarantine == nil {
+		return false
+	}
+
+	if pod.Name == cluster.Status.CurrentPrimary || pod.Name == cluster.Status.TargetPrimary {
+		return false
+	}
+
+	if !utils.IsPodActive(*pod) || cluster.IsInstanceFenced(pod.Name) {
+		return false
+	}
+
+	// An instance without enough space for the WALs is not broken,
+	// and it has its own handling
+	return isWALSpaceAvailableOnPod(pod)
+}
+
+// shouldQuarantineInstance checks whether an instance has been restarted
+// more than allowed in the current restart window and is still crashing
+func shouldQuarantineInstance(cluster *apiv1.Cluster, pod *corev1.Pod, window restartWindow) bool {
+	if utils.IsPodAlive(*pod) {
+		return false
+	}
+
+	return getPostgresContainerRestartCount(pod)-window.RestartCount >= cluster.Spec.Quarantine.MaxRestarts
+}
+
+// restartWindow is the time window in which the restarts of an instance
+// are counted. It is stored in an annotation of the Pod, so that it is
+// discarded together with the restart count when the Pod is recreated.
+type restartWindow struct {
+	// When the window started
+	Start metav1.Time `json:"start"`
+
+	// The restart count of the PostgreSQL container when the window started
+	RestartCount int32 `json:"restartCount"`
+}
+
+// getRestartWindow gets the current restart window of the passed instance,
+// starting a new one when the previous one is expired or missing. It also
+// returns whether the window has changed and needs to be stored.
+func getRestartWindow(cluster *apiv1.Cluster, pod *corev1.Pod, now time.Time) (restartWindow, bool) {
+	restartCount := getPostgresContainerRestartCount(pod)
+	newWindow := restartWindow{
+		Start:        metav1.NewTime(now),
+		RestartCount: restartCount,
+	}
+
+	var window restartWindow
+	content, found := pod.Annotations[utils.QuarantineRestartWindowAnnotationName]
+	if !found || json.Unmarshal([]byte(content), &window) != nil {
+		return newWindow, true
+	}
+
+	if now.Sub(window.Start.Time) > cluster.Spec.Quarantine.GetRestartWindow() ||
+		restartCount < window.RestartCount {
+		return newWindow, true
+	}
+
+	return window, false
+}
+
+// setRestartWindow stores the passed restart window in the Pod
+func (r *ClusterReconciler) setRestartWindow(ctx context.Context, pod *corev1.Pod, window restartWindow) error {
+	content, err := json.Marshal(window)
+	if err != nil {
+		return err
+	}
+
+	origPod := pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[utils.QuarantineRestartWindowAnnotationName] = string(content)
+	return r.Patch(ctx, pod, client.MergeFrom(origPod))
+}
+
+// resetRestartWindow removes the restart window from the Pod
+func (r *ClusterReconciler) resetRestartWindow(ctx context.Context, pod *corev1.Pod) error {
+	if _, found := pod.Annotations[utils.QuarantineRestartWindowAnnotationName]; !found {
+		return nil
+	}
+
+	origPod := pod.DeepCopy()
+	delete(pod.Annotations, utils.QuarantineRestartWindowAnnotationName)
+	return client.IgnoreNotFound(r.Patch(ctx, pod, client.MergeFrom(origPod)))
+}
+
+// getQuarantinedInstances gets the sorted list of the instances which are
+// quarantined, discarding the ones that have been unfenced or removed
+func getQuarantinedInstances(
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+	newlyQuarantined []*corev1.Pod,
+) []string {
+	candidates := slices.Clone(cluster.Status.QuarantinedInstances)
+	for _, pod := range newlyQuarantined {
+		candidates = append(candidates, pod.Name)
+	}
+
+	result := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if slices.Contains(result, name) || !cluster.IsInstanceFenced(name) {
+			continue
+		}
+		if !slices.ContainsFunc(instances, func(pod corev1.Pod) bool { return pod.Name == name }) {
+			continue
+		}
+		result = append(result, name)
+	}
+	slices.Sort(result)
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// getPostgresContainerRestartCount gets the number of times the PostgreSQL
+// container of an instance has been restarted
+func getPostgresContainerRestartCount(pod *corev1.Pod) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == specs.PostgresContainerName {
+			return status.RestartCount
+		}
+	}
+
+	return 0
+}
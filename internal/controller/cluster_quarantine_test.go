@@ -0,0 +1,212 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance quarantine", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+		pods    []corev1.Pod
+	)
+
+	buildPod := func(name string, restartCount int32, crashing bool) corev1.Pod {
+		status := corev1.ContainerStatus{
+			Name:         specs.PostgresContainerName,
+			RestartCount: restartCount,
+		}
+		if crashing {
+			status.State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{status},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:  3,
+				Quarantine: &apiv1.QuarantineConfiguration{MaxRestarts: 5},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+
+		pods = []corev1.Pod{
+			buildPod("cluster-example-1", 0, false),
+			buildPod("cluster-example-2", 5, true),
+			buildPod("cluster-example-3", 2, true),
+		}
+	})
+
+	withRestartWindow := func(pod *corev1.Pod, start time.Time, restartCount int32) {
+		content, err := json.Marshal(restartWindow{Start: metav1.NewTime(start), RestartCount: restartCount})
+		Expect(err).ToNot(HaveOccurred())
+		pod.Annotations = map[string]string{utils.QuarantineRestartWindowAnnotationName: string(content)}
+	}
+
+	Context("isQuarantineCandidate", func() {
+		It("accepts a replica", func() {
+			Expect(isQuarantineCandidate(cluster, &pods[1])).To(BeTrue())
+		})
+
+		It("doesn't accept the primary", func() {
+			cluster.Status.CurrentPrimary = "cluster-example-2"
+			cluster.Status.TargetPrimary = "cluster-example-2"
+			Expect(isQuarantineCandidate(cluster, &pods[1])).To(BeFalse())
+		})
+
+		It("doesn't accept an instance that is already fenced", func() {
+			cluster.Annotations = map[string]string{utils.FencedInstanceAnnotation: `["cluster-example-2"]`}
+			Expect(isQuarantineCandidate(cluster, &pods[1])).To(BeFalse())
+		})
+
+		It("doesn't accept any instance when the quarantine is disabled", func() {
+			cluster.Spec.Quarantine = nil
+			Expect(isQuarantineCandidate(cluster, &pods[1])).To(BeFalse())
+		})
+	})
+
+	Context("shouldQuarantineInstance", func() {
+		It("quarantines an instance that keeps crashing in the restart window", func() {
+			Expect(shouldQuarantineInstance(cluster, &pods[1], restartWindow{RestartCount: 0})).To(BeTrue())
+		})
+
+		It("doesn't quarantine an instance restarted less than the limit in the restart window", func() {
+			Expect(shouldQuarantineInstance(cluster, &pods[1], restartWindow{RestartCount: 1})).To(BeFalse())
+			Expect(shouldQuarantineInstance(cluster, &pods[2], restartWindow{RestartCount: 0})).To(BeFalse())
+		})
+
+		It("doesn't quarantine an instance that is not crashing anymore", func() {
+			pods[1].Status.ContainerStatuses[0].State.Waiting = nil
+			Expect(shouldQuarantineInstance(cluster, &pods[1], restartWindow{RestartCount: 0})).To(BeFalse())
+		})
+	})
+
+	Context("getRestartWindow", func() {
+		now := time.Now()
+
+		It("starts a new window when there's none", func() {
+			window, changed := getRestartWindow(cluster, &pods[1], now)
+			Expect(changed).To(BeTrue())
+			Expect(window.RestartCount).To(BeEquivalentTo(5))
+		})
+
+		It("keeps the current window until it expires", func() {
+			withRestartWindow(&pods[1], now.Add(-30*time.Minute), 1)
+			window, changed := getRestartWindow(cluster, &pods[1], now)
+			Expect(changed).To(BeFalse())
+			Expect(window.RestartCount).To(BeEquivalentTo(1))
+		})
+
+		It("starts a new window when the current one is expired", func() {
+			withRestartWindow(&pods[1], now.Add(-2*time.Hour), 1)
+			window, changed := getRestartWindow(cluster, &pods[1], now)
+			Expect(changed).To(BeTrue())
+			Expect(window.RestartCount).To(BeEquivalentTo(5))
+		})
+
+		It("honors the configured window", func() {
+			cluster.Spec.Quarantine.RestartWindow = 600
+			withRestartWindow(&pods[1], now.Add(-30*time.Minute), 1)
+			_, changed := getRestartWindow(cluster, &pods[1], now)
+			Expect(changed).To(BeTrue())
+		})
+	})
+
+	Context("reconcileQuarantine", func() {
+		It("doesn't count the restarts before the first restart window", func(ctx SpecContext) {
+			r = newFakeClusterReconciler(cluster)
+			for idx := range pods {
+				Expect(r.Create(ctx, &pods[idx])).To(Succeed())
+			}
+
+			Expect(r.reconcileQuarantine(ctx, cluster, pods)).To(Succeed())
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.IsInstanceFenced("cluster-example-2")).To(BeFalse())
+
+			var updatedPod corev1.Pod
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(&pods[1]), &updatedPod)).To(Succeed())
+			Expect(updatedPod.Annotations).To(HaveKey(utils.QuarantineRestartWindowAnnotationName))
+		})
+
+		It("fences the instances that keep crashing and reports them", func(ctx SpecContext) {
+			for idx := range pods {
+				withRestartWindow(&pods[idx], time.Now().Add(-time.Minute), 0)
+			}
+			r = newFakeClusterReconciler(cluster)
+			for idx := range pods {
+				Expect(r.Create(ctx, &pods[idx])).To(Succeed())
+			}
+
+			Expect(r.reconcileQuarantine(ctx, cluster, pods)).To(Succeed())
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.IsInstanceFenced("cluster-example-2")).To(BeTrue())
+
+			var updatedPod corev1.Pod
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(&pods[1]), &updatedPod)).To(Succeed())
+			Expect(updatedPod.Annotations).ToNot(HaveKey(utils.QuarantineRestartWindowAnnotationName))
+			Expect(updatedCluster.IsInstanceFenced("cluster-example-3")).To(BeFalse())
+			Expect(updatedCluster.Status.QuarantinedInstances).To(Equal([]string{"cluster-example-2"}))
+		})
+
+		It("forgets the instances that have been unfenced", func(ctx SpecContext) {
+			cluster.Status.QuarantinedInstances = []string{"cluster-example-2"}
+			pods[1].Status.ContainerStatuses[0].State.Waiting = nil
+			for idx := range pods {
+				withRestartWindow(&pods[idx], time.Now().Add(-time.Minute), 0)
+			}
+			r = newFakeClusterReconciler(cluster)
+
+			Expect(r.reconcileQuarantine(ctx, cluster, pods)).To(Succeed())
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.QuarantinedInstances).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,399 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// errNoReplacementPrimary is raised when none of the replicas can become
+// the new primary instead of the instance being replaced
+var errNoReplacementPrimary = errors.New("no replica can become the new primary")
+
+// reconcileInstanceReplacement handles the replacement of an instance
+// requested via the replaceInstance annotation. The instance is switched
+// over if it is the primary, then evicted and deleted together with its
+// PVCs once all the other instances are ready, and the operator creates a
+// new one in its place, on another node. It must be invoked only when the current primary is healthy
+// and no switchover or failover is in progress.
+// It returns true when a switchover has been started or the instance has
+// been deleted.
+func (r *ClusterReconciler) reconcileInstanceReplacement(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	instanceName, requested := cluster.Annotations[utils.ReplaceInstanceAnnotationName]
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionInstanceReplacement))
+	inProgress := condition != nil && condition.Reason == string(apiv1.ConditionReasonInstanceReplacementInProgress)
+
+	if !requested {
+		if inProgress {
+			return false, r.abortInstanceReplacement(ctx, cluster,
+				fmt.Errorf("instance replacement withdrawn while in progress"))
+		}
+		return false, nil
+	}
+
+	if !inProgress {
+		if err := checkInstanceReplacement(cluster, instancesStatus, instanceName); err != nil {
+			return false, r.abortInstanceReplacement(ctx, cluster, err)
+		}
+
+		contextLogger.Info("Starting the requested instance replacement", "instance", instanceName)
+		r.Recorder.Eventf(cluster, "Normal", "ReplacingInstance",
+			"Replacing instance %v as requested", instanceName)
+	}
+
+	instance := findInstanceStatus(instancesStatus, instanceName)
+	switch {
+	case instance == nil:
+		// The instance has been deleted, let's wait for the new one
+		if cluster.Status.ReadyInstances < cluster.GetInstances() {
+			return false, conditions.Patch(ctx, r.Client, cluster,
+				apiv1.BuildInstanceReplacementInProgressCondition(fmt.Sprintf(
+					"Waiting for the instance replacing %s to be ready", instanceName)))
+		}
+
+		contextLogger.Info("Requested instance replacement completed", "instance", instanceName)
+		r.Recorder.Eventf(cluster, "Normal", "InstanceReplaced", "Instance %v has been replaced", instanceName)
+		if err := r.setReplacedInstanceNode(ctx, cluster, ""); err != nil {
+			return false, err
+		}
+		if err := conditions.Patch(ctx, r.Client, cluster,
+			apiv1.BuildInstanceReplacementSucceededCondition(instanceName)); err != nil {
+			return false, err
+		}
+		return false, r.removeInstanceReplacementRequest(ctx, cluster)
+
+	case !instance.Pod.DeletionTimestamp.IsZero():
+		return false, conditions.Patch(ctx, r.Client, cluster,
+			apiv1.BuildInstanceReplacementInProgressCondition(fmt.Sprintf(
+				"Waiting for instance %s to be deleted", instanceName)))
+
+	case instanceName == cluster.Status.CurrentPrimary:
+		return r.switchoverReplacedPrimary(ctx, cluster, instancesStatus, instanceName)
+	}
+
+	if pending := getPendingInstances(cluster, instancesStatus, instanceName); len(pending) > 0 {
+		contextLogger.Info("Waiting for the other instances to be ready before replacing an instance",
+			"instance", instanceName,
+			"pendingInstances", pending)
+		return false, conditions.Patch(ctx, r.Client, cluster,
+			apiv1.BuildInstanceReplacementInProgressCondition(fmt.Sprintf(
+				"Waiting for the other instances to be ready before deleting %s", instanceName)))
+	}
+
+	// The node is recorded before evicting the pod, so that
+	// the new instance is not scheduled on it
+	if err := r.setReplacedInstanceNode(ctx, cluster, instance.Pod.Spec.NodeName); err != nil {
+		return false, err
+	}
+
+	contextLogger.Info("Evicting the instance to be replaced", "instance", instanceName)
+	if err := r.evictInstance(ctx, instance.Pod); err != nil {
+		if apierrs.IsTooManyRequests(err) {
+			contextLogger.Info("The eviction of the instance to be replaced is not allowed yet",
+				"instance", instanceName,
+				"reason", err.Error())
+			return false, conditions.Patch(ctx, r.Client, cluster,
+				apiv1.BuildInstanceReplacementInProgressCondition(fmt.Sprintf(
+					"Waiting for the disruption budget to allow evicting %s", instanceName)))
+		}
+		return false, err
+	}
+	if err := persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+		ctx,
+		r.Client,
+		cluster,
+		instanceName,
+		cluster.Namespace,
+	); err != nil {
+		return false, err
+	}
+	r.Recorder.Eventf(cluster, "Normal", "ReplacingInstance",
+		"Evicted instance %v and deleted its PVCs, a new instance will be created", instanceName)
+
+	return true, conditions.Patch(ctx, r.Client, cluster,
+		apiv1.BuildInstanceReplacementInProgressCondition(fmt.Sprintf(
+			"Evicted instance %s, waiting for the new instance to be created", instanceName)))
+}
+
+// evictInstance evicts the pod of an instance through the eviction API,
+// so that the disruption budgets of the cluster are honored
+func (r *ClusterReconciler) evictInstance(ctx context.Context, pod *corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	if err := r.SubResource("eviction").Create(ctx, pod, eviction); err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// setReplacedInstanceNode records the node where the instance being
+// replaced was running, or forgets it when empty
+func (r *ClusterReconciler) setReplacedInstanceNode(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeName string,
+) error {
+	if cluster.Status.ReplacedInstanceNode == nodeName {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ReplacedInstanceNode = nodeName
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// avoidReplacedInstanceNode prevents the pods of the instance created in
+// place of the replaced one, which is the last generated instance, from
+// being scheduled on the node where the replaced instance was running.
+// The requirement is added to every node selector term, as they are ORed
+func avoidReplacedInstanceNode(cluster *apiv1.Cluster, nodeSerial int, podSpec *corev1.PodSpec) {
+	nodeName := cluster.Status.ReplacedInstanceNode
+	if nodeName == "" || nodeSerial != cluster.Status.LatestGeneratedNode {
+		return
+	}
+
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      nodeNameField,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{nodeName},
+	}
+
+	// The affinity may be shared with the cluster specification
+	affinity := podSpec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	nodeSelector := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for idx := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[idx].MatchFields = append(
+			nodeSelector.NodeSelectorTerms[idx].MatchFields, requirement)
+	}
+
+	podSpec.Affinity = affinity
+}
+
+// switchoverReplacedPrimary starts a switchover from the primary instance
+// being replaced to the first replica that would be promoted on failover
+// and can safely become the new primary.
+// It returns true when the switchover has been started.
+func (r *ClusterReconciler) switchoverReplacedPrimary(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	instanceName string,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	targetPrimary, err := selectReplacementPrimary(cluster, instancesStatus, instanceName)
+	if err != nil {
+		contextLogger.Info("Waiting for a replica which can become the primary before replacing an instance",
+			"instance", instanceName,
+			"reason", err.Error())
+		return false, conditions.Patch(ctx, r.Client, cluster,
+			apiv1.BuildInstanceReplacementInProgressCondition(fmt.Sprintf(
+				"Waiting to switch over from %s: %v", instanceName, err)))
+	}
+
+	contextLogger.Info("Switching over from the instance to be replaced",
+		"currentPrimary", instanceName,
+		"targetPrimary", targetPrimary)
+	instancesStatus.LogStatus(ctx)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
+		"Switching over from %v to %v to replace the primary instance", instanceName, targetPrimary)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.TargetPrimary = targetPrimary
+	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	meta.SetStatusCondition(&cluster.Status.Conditions, *apiv1.BuildInstanceReplacementInProgressCondition(
+		fmt.Sprintf("Switching over from %s to %s", instanceName, targetPrimary)))
+	if err := status.RegisterPhaseWithOrigCluster(
+		ctx,
+		r.Client,
+		cluster,
+		origCluster,
+		apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v to replace %v", targetPrimary, instanceName),
+	); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// selectReplacementPrimary gets the first replica, in the order in which
+// they would be promoted on failover, which can safely become the primary
+// instead of the instance being replaced
+func selectReplacementPrimary(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	instanceName string,
+) (string, error) {
+	configuration := cluster.Spec.FailoverPriority
+	err := errNoReplacementPrimary
+	for _, candidate := range instancesStatus.RankFailoverCandidates(
+		configuration.GetPriority, cluster.GetFailoverLSNTolerance()) {
+		candidateName := candidate.Status.Pod.Name
+		if !candidate.Eligible || candidateName == instanceName {
+			continue
+		}
+
+		if err = checkSwitchoverTarget(cluster, instancesStatus, candidateName); err == nil {
+			return candidateName, nil
+		}
+	}
+
+	return "", err
+}
+
+// checkInstanceReplacement checks whether the named instance can be
+// replaced while keeping the cluster available, returning the reason why
+// that's not possible
+func checkInstanceReplacement(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	instanceName string,
+) error {
+	if findInstanceStatus(instancesStatus, instanceName) == nil {
+		return fmt.Errorf("instance %s is not part of the cluster", instanceName)
+	}
+
+	if cluster.GetInstances() < 2 {
+		return fmt.Errorf("instance %s can't be replaced without downtime in a single instance cluster",
+			instanceName)
+	}
+
+	// While the instance is being replaced, one standby less is available
+	// for the synchronous replication, and the writes would be blocked
+	availableStandbys := cluster.GetInstances() - 2
+	if cluster.Spec.MinSyncReplicas > availableStandbys &&
+		cluster.GetSyncReplicasUnavailablePolicy() == apiv1.SyncReplicasUnavailablePolicyBlock {
+		return fmt.Errorf(
+			"replacing instance %s would leave %d standbys, fewer than the %d synchronous ones required",
+			instanceName, availableStandbys, cluster.Spec.MinSyncReplicas)
+	}
+
+	return nil
+}
+
+// getPendingInstances gets the instances, other than the one being
+// replaced, which are not ready or are missing
+func getPendingInstances(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	instanceName string,
+) []string {
+	var pending []string
+	readyInstances := 0
+	for _, item := range instancesStatus.Items {
+		if item.Pod == nil || item.Pod.Name == instanceName {
+			continue
+		}
+		if !item.IsPodReady || !item.HasHTTPStatus() {
+			pending = append(pending, item.Pod.Name)
+			continue
+		}
+		readyInstances++
+	}
+
+	if missing := cluster.GetInstances() - 1 - readyInstances - len(pending); missing > 0 {
+		pending = append(pending, fmt.Sprintf("%d missing", missing))
+	}
+
+	return pending
+}
+
+// findInstanceStatus gets the status of the named instance, if it exists
+func findInstanceStatus(instancesStatus postgres.PostgresqlStatusList, instanceName string) *postgres.PostgresqlStatus {
+	for idx := range instancesStatus.Items {
+		if instancesStatus.Items[idx].Pod != nil && instancesStatus.Items[idx].Pod.Name == instanceName {
+			return &instancesStatus.Items[idx]
+		}
+	}
+
+	return nil
+}
+
+// abortInstanceReplacement reports why the requested replacement can't
+// be completed and removes the request
+func (r *ClusterReconciler) abortInstanceReplacement(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason error,
+) error {
+	log.FromContext(ctx).Warning("Requested instance replacement aborted", "reason", reason.Error())
+	r.Recorder.Eventf(cluster, "Warning", "InstanceReplacementAborted", "Instance replacement aborted: %v", reason)
+	if err := r.setReplacedInstanceNode(ctx, cluster, ""); err != nil {
+		return err
+	}
+	condition := apiv1.BuildInstanceReplacementAbortedCondition(reason)
+	if err := conditions.Patch(ctx, r.Client, cluster, condition); err != nil {
+		return err
+	}
+
+	return r.removeInstanceReplacementRequest(ctx, cluster)
+}
+
+// removeInstanceReplacementRequest removes the replaceInstance annotation
+// from the cluster
+func (r *ClusterReconciler) removeInstanceReplacementRequest(ctx context.Context, cluster *apiv1.Cluster) error {
+	if _, ok := cluster.Annotations[utils.ReplaceInstanceAnnotationName]; !ok {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.ReplaceInstanceAnnotationName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
@@ -0,0 +1,301 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("requested instance replacement", func() {
+	var (
+		r               ClusterReconciler
+		cluster         *apiv1.Cluster
+		instancesStatus postgres.PostgresqlStatusList
+	)
+
+	buildStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+			IsPrimary:           isPrimary,
+			IsPodReady:          true,
+			IsWalReceiverActive: !isPrimary,
+			CurrentLsn:          "0/6000000",
+			ReceivedLsn:         "0/6000000",
+			ReplayLsn:           "0/6000000",
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.ReplaceInstanceAnnotationName: "cluster-example-2",
+				},
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				ReadyInstances: 3,
+			},
+		}
+
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1", true),
+				buildStatus("cluster-example-2", false),
+				buildStatus("cluster-example-3", false),
+			},
+		}
+		instancesStatus.Items[0].ReplicationInfo = postgres.PgStatReplicationList{
+			{ApplicationName: "cluster-example-2", SyncState: "async"},
+			{ApplicationName: "cluster-example-3", SyncState: "async"},
+		}
+	})
+
+	buildReconciler := func() {
+		objects := []client.Object{cluster}
+		for idx := range instancesStatus.Items {
+			objects = append(objects, instancesStatus.Items[idx].Pod)
+		}
+		r = newFakeClusterReconciler(objects...)
+	}
+
+	getReplacementCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionInstanceReplacement))
+	}
+
+	Context("checkInstanceReplacement", func() {
+		It("accepts a replica of a cluster with more instances", func() {
+			Expect(checkInstanceReplacement(cluster, instancesStatus, "cluster-example-2")).To(Succeed())
+		})
+
+		It("rejects an unknown instance", func() {
+			Expect(checkInstanceReplacement(cluster, instancesStatus, "cluster-example-4")).
+				To(MatchError(ContainSubstring("not part of the cluster")))
+		})
+
+		It("rejects the instance of a single instance cluster", func() {
+			cluster.Spec.Instances = 1
+			Expect(checkInstanceReplacement(cluster, instancesStatus, "cluster-example-1")).
+				To(MatchError(ContainSubstring("single instance cluster")))
+		})
+
+		It("rejects a replacement blocking the synchronous replication", func() {
+			cluster.Spec.MinSyncReplicas = 2
+			cluster.Spec.MaxSyncReplicas = 2
+			Expect(checkInstanceReplacement(cluster, instancesStatus, "cluster-example-2")).
+				To(MatchError(ContainSubstring("synchronous")))
+
+			cluster.Spec.SyncReplicasUnavailablePolicy = apiv1.SyncReplicasUnavailablePolicyRelax
+			Expect(checkInstanceReplacement(cluster, instancesStatus, "cluster-example-2")).To(Succeed())
+		})
+	})
+
+	Context("getPendingInstances", func() {
+		It("counts the instances without a pod as missing", func() {
+			instancesStatus.Items[2].Pod = nil
+			Expect(getPendingInstances(cluster, instancesStatus, "cluster-example-2")).
+				To(Equal([]string{"1 missing"}))
+		})
+	})
+
+	Context("avoidReplacedInstanceNode", func() {
+		BeforeEach(func() {
+			cluster.Status.ReplacedInstanceNode = "node-2"
+			cluster.Status.LatestGeneratedNode = 4
+		})
+
+		It("keeps the new instance away from the node of the replaced one", func() {
+			var podSpec corev1.PodSpec
+			avoidReplacedInstanceNode(cluster, 4, &podSpec)
+
+			terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(1))
+			Expect(terms[0].MatchFields).To(ConsistOf(corev1.NodeSelectorRequirement{
+				Key:      "metadata.name",
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{"node-2"},
+			}))
+		})
+
+		It("adds the requirement to every term without changing the cluster", func() {
+			cluster.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+						}},
+						{MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}},
+						}},
+					},
+				},
+			}
+			podSpec := corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: cluster.Spec.Affinity.NodeAffinity}}
+			avoidReplacedInstanceNode(cluster, 4, &podSpec)
+
+			for _, term := range podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.
+				NodeSelectorTerms {
+				Expect(term.MatchExpressions).To(HaveLen(1))
+				Expect(term.MatchFields).To(HaveLen(1))
+			}
+			for _, term := range cluster.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.
+				NodeSelectorTerms {
+				Expect(term.MatchFields).To(BeEmpty())
+			}
+		})
+
+		It("doesn't change the other instances", func() {
+			var podSpec corev1.PodSpec
+			avoidReplacedInstanceNode(cluster, 3, &podSpec)
+			Expect(podSpec.Affinity).To(BeNil())
+
+			cluster.Status.ReplacedInstanceNode = ""
+			avoidReplacedInstanceNode(cluster, 4, &podSpec)
+			Expect(podSpec.Affinity).To(BeNil())
+		})
+	})
+
+	Context("reconcileInstanceReplacement", func() {
+		It("does nothing without a request", func(ctx SpecContext) {
+			delete(cluster.Annotations, utils.ReplaceInstanceAnnotationName)
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeFalse())
+			Expect(getReplacementCondition()).To(BeNil())
+		})
+
+		It("evicts a replica when the other instances are ready", func(ctx SpecContext) {
+			instancesStatus.Items[1].Pod.Spec.NodeName = "node-2"
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeTrue())
+			Expect(getReplacementCondition().Reason).
+				To(Equal(string(apiv1.ConditionReasonInstanceReplacementInProgress)))
+			Expect(cluster.Status.ReplacedInstanceNode).To(Equal("node-2"))
+
+			var pod corev1.Pod
+			err = r.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &pod)
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("waits for the other instances to be ready", func(ctx SpecContext) {
+			instancesStatus.Items[2].IsPodReady = false
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeFalse())
+			Expect(getReplacementCondition().Message).To(ContainSubstring("Waiting for the other instances"))
+
+			var pod corev1.Pod
+			Expect(r.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-2"}, &pod)).
+				To(Succeed())
+		})
+
+		It("switches over from the primary before replacing it", func(ctx SpecContext) {
+			cluster.Annotations[utils.ReplaceInstanceAnnotationName] = "cluster-example-1"
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeTrue())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+			Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+			Expect(getReplacementCondition().Message).To(ContainSubstring("Switching over"))
+		})
+
+		It("waits for a replica which can become the primary", func(ctx SpecContext) {
+			cluster.Annotations[utils.ReplaceInstanceAnnotationName] = "cluster-example-1"
+			instancesStatus.Items[1].IsWalReceiverActive = false
+			instancesStatus.Items[2].IsWalReceiverActive = false
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeFalse())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+			Expect(getReplacementCondition().Message).To(ContainSubstring("not streaming"))
+		})
+
+		It("rejects the replacement of an unknown instance", func(ctx SpecContext) {
+			cluster.Annotations[utils.ReplaceInstanceAnnotationName] = "cluster-example-4"
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeFalse())
+			Expect(getReplacementCondition().Reason).
+				To(Equal(string(apiv1.ConditionReasonInstanceReplacementAborted)))
+			Expect(cluster.Annotations).ToNot(HaveKey(utils.ReplaceInstanceAnnotationName))
+		})
+
+		It("reports the completion once the new instance is ready", func(ctx SpecContext) {
+			meta.SetStatusCondition(&cluster.Status.Conditions,
+				*apiv1.BuildInstanceReplacementInProgressCondition("Evicted instance cluster-example-2"))
+			instancesStatus.Items[1] = buildStatus("cluster-example-4", false)
+			cluster.Status.ReadyInstances = 2
+			cluster.Status.ReplacedInstanceNode = "node-2"
+			buildReconciler()
+
+			replacing, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeFalse())
+			Expect(getReplacementCondition().Message).To(ContainSubstring("Waiting for the instance replacing"))
+
+			cluster.Status.ReadyInstances = 3
+			replacing, err = r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(replacing).To(BeFalse())
+			Expect(getReplacementCondition().Status).To(Equal(metav1.ConditionTrue))
+			Expect(cluster.Annotations).ToNot(HaveKey(utils.ReplaceInstanceAnnotationName))
+			Expect(cluster.Status.ReplacedInstanceNode).To(BeEmpty())
+		})
+
+		It("reports a replacement withdrawn while in progress", func(ctx SpecContext) {
+			delete(cluster.Annotations, utils.ReplaceInstanceAnnotationName)
+			meta.SetStatusCondition(&cluster.Status.Conditions,
+				*apiv1.BuildInstanceReplacementInProgressCondition("Evicted instance cluster-example-2"))
+			buildReconciler()
+
+			_, err := r.reconcileInstanceReplacement(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getReplacementCondition().Reason).
+				To(Equal(string(apiv1.ConditionReasonInstanceReplacementAborted)))
+		})
+	})
+})
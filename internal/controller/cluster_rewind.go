@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+)
+
+// reconcileRewindFailures recreates the former primaries that can't be
+// resynchronized with the current primary through pg_rewind, when the
+// rewind failure policy allows it, and keeps the outcome of pg_rewind in
+// the cluster status limited to the existing instances. It returns true
+// when at least one instance has been deleted.
+func (r *ClusterReconciler) reconcileRewindFailures(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instances []corev1.Pod,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if len(cluster.Status.RewindStatus) == 0 {
+		return false, nil
+	}
+
+	rewindStatus := make(map[apiv1.PodName]apiv1.InstanceRewindStatus, len(cluster.Status.RewindStatus))
+	recloned := false
+	for idx := range instances {
+		pod := &instances[idx]
+		status, ok := cluster.Status.RewindStatus[apiv1.PodName(pod.Name)]
+		if !ok {
+			continue
+		}
+
+		if !shouldRecloneInstance(cluster, pod, status) {
+			rewindStatus[apiv1.PodName(pod.Name)] = status
+			continue
+		}
+
+		contextLogger.Warning("Recreating a former primary that can't be resynchronized with pg_rewind",
+			"instance", pod.Name,
+			"rewindError", status.Error)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return false, err
+		}
+		if err := persistentvolumeclaim.EnsureInstancePVCGroupIsDeleted(
+			ctx,
+			r.Client,
+			cluster,
+			pod.Name,
+			pod.Namespace,
+		); err != nil {
+			return false, err
+		}
+		r.Recorder.Eventf(cluster, "Warning", "RecloneFormerPrimary",
+			"Deleted the former primary %s and its PVCs, as pg_rewind can't be used: %s",
+			pod.Name, status.Error)
+		recloned = true
+	}
+
+	if len(rewindStatus) == 0 {
+		rewindStatus = nil
+	}
+	if maps.Equal(rewindStatus, cluster.Status.RewindStatus) {
+		return recloned, nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.RewindStatus = rewindStatus
+	return recloned, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// shouldRecloneInstance checks whether an instance needs to be recreated
+// because pg_rewind can't be used with it, as a WAL file it needs is not
+// available anymore. The primary instance is never recreated.
+func shouldRecloneInstance(cluster *apiv1.Cluster, pod *corev1.Pod, status apiv1.InstanceRewindStatus) bool {
+	if cluster.Spec.RewindFailurePolicy != apiv1.RewindFailurePolicyReclone {
+		return false
+	}
+
+	if pod.Name == cluster.Status.CurrentPrimary || pod.Name == cluster.Status.TargetPrimary {
+		return false
+	}
+
+	return !status.Succeeded && status.WALUnavailable
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_rewind failures", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+		pods    []corev1.Pod
+		pvc     *corev1.PersistentVolumeClaim
+	)
+
+	walUnavailable := apiv1.InstanceRewindStatus{
+		Succeeded:      false,
+		Error:          "error executing pg_rewind: a WAL file required by pg_rewind is not available",
+		WALUnavailable: true,
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:           3,
+				RewindFailurePolicy: apiv1.RewindFailurePolicyReclone,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				RewindStatus: map[apiv1.PodName]apiv1.InstanceRewindStatus{
+					"cluster-example-1": walUnavailable,
+					"cluster-example-4": {Succeeded: true},
+				},
+			},
+		}
+
+		pods = []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2", Namespace: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3", Namespace: "default"}},
+		}
+
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1", Namespace: "default"},
+		}
+	})
+
+	Context("shouldRecloneInstance", func() {
+		It("reclones a former primary when a WAL file is not available", func() {
+			Expect(shouldRecloneInstance(cluster, &pods[0], walUnavailable)).To(BeTrue())
+		})
+
+		It("doesn't reclone an instance when pg_rewind fails for other reasons", func() {
+			Expect(shouldRecloneInstance(cluster, &pods[0],
+				apiv1.InstanceRewindStatus{Error: "connection refused"})).To(BeFalse())
+		})
+
+		It("doesn't reclone the primary", func() {
+			Expect(shouldRecloneInstance(cluster, &pods[1], walUnavailable)).To(BeFalse())
+		})
+
+		It("doesn't reclone an instance with the wait policy", func() {
+			cluster.Spec.RewindFailurePolicy = apiv1.RewindFailurePolicyWait
+			Expect(shouldRecloneInstance(cluster, &pods[0], walUnavailable)).To(BeFalse())
+		})
+	})
+
+	Context("reconcileRewindFailures", func() {
+		It("deletes the instance and its storage", func(ctx SpecContext) {
+			r = newFakeClusterReconciler(cluster, &pods[0], &pods[1], &pods[2], pvc)
+
+			recloned, err := r.reconcileRewindFailures(ctx, cluster, pods)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recloned).To(BeTrue())
+
+			err = r.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &corev1.Pod{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			err = r.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.RewindStatus).To(BeEmpty())
+		})
+
+		It("keeps the instance with the wait policy, forgetting the removed ones", func(ctx SpecContext) {
+			cluster.Spec.RewindFailurePolicy = apiv1.RewindFailurePolicyWait
+			r = newFakeClusterReconciler(cluster, &pods[0], &pods[1], &pods[2], pvc)
+
+			recloned, err := r.reconcileRewindFailures(ctx, cluster, pods)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recloned).To(BeFalse())
+
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &corev1.Pod{})).To(Succeed())
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.RewindStatus).To(Equal(map[apiv1.PodName]apiv1.InstanceRewindStatus{
+				"cluster-example-1": walUnavailable,
+			}))
+		})
+	})
+})
@@ -41,7 +41,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
-	if cluster.Spec.MaxSyncReplicas > 0 && cluster.Spec.Instances < (cluster.Spec.MaxSyncReplicas+1) {
+	if cluster.Spec.MaxSyncReplicas > 0 && cluster.GetInstances() < (cluster.Spec.MaxSyncReplicas+1) {
 		cluster.Spec.Instances = cluster.Status.Instances
 		if err := r.Update(ctx, cluster); err != nil {
 			return err
@@ -54,8 +54,12 @@ func (r *ClusterReconciler) scaleDownCluster(
 		return nil
 	}
 
-	// Is there is an instance to be deleted?
-	instanceName := findDeletableInstance(cluster, resources.instances.Items)
+	// Is there is an instance to be deleted? The replica drained by the
+	// automatic scaling takes precedence over the other ones
+	instanceName := getDrainedInstance(cluster, resources.instances.Items)
+	if instanceName == "" {
+		instanceName = findDeletableInstance(cluster, resources.instances.Items)
+	}
 	if instanceName == "" {
 		contextLogger.Info("There are no instances to be sacrificed. Wait for the next sync loop")
 		return nil
@@ -68,6 +72,23 @@ func (r *ClusterReconciler) scaleDownCluster(
 	return r.ensureInstanceIsDeleted(ctx, cluster, instanceName)
 }
 
+// getDrainedInstance gets the replica whose connections have been drained
+// by the automatic scaling, if its Pod is still there
+func getDrainedInstance(cluster *apiv1.Cluster, instances []corev1.Pod) string {
+	drainingInstance := cluster.Status.ReplicaAutoscaling.GetDrainingInstance()
+	if drainingInstance == "" || drainingInstance == cluster.Status.CurrentPrimary {
+		return ""
+	}
+
+	for _, pod := range instances {
+		if pod.Name == drainingInstance {
+			return drainingInstance
+		}
+	}
+
+	return ""
+}
+
 func (r *ClusterReconciler) ensureInstanceIsDeleted(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -22,11 +22,14 @@ import (
 	"reflect"
 	"runtime"
 	"sort"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -197,6 +200,16 @@ func (r *ClusterReconciler) getManagedJobs(
 		return batchv1.JobList{}, err
 	}
 
+	// Backup verification and major upgrade jobs are not creating
+	// any instance and have a lifecycle of their own
+	instanceJobs := make([]batchv1.Job, 0, len(childJobs.Items))
+	for _, job := range childJobs.Items {
+		if !specs.IsBackupVerificationJob(job) && !specs.IsMajorUpgradeJob(job) {
+			instanceJobs = append(instanceJobs, job)
+		}
+	}
+	childJobs.Items = instanceJobs
+
 	sort.Slice(childJobs.Items, func(i, j int) bool {
 		return childJobs.Items[i].Name < childJobs.Items[j].Name
 	})
@@ -260,6 +273,12 @@ func (r *ClusterReconciler) updateResourceStatus(
 		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint,
 	)
 
+	// The selector of the instance pods, used by the scale subresource
+	cluster.Status.Selector = labels.SelectorFromSet(labels.Set{
+		utils.ClusterLabelName: cluster.Name,
+		utils.PodRoleLabelName: string(utils.PodRoleInstance),
+	}).String()
+
 	// Services
 	cluster.Status.WriteService = cluster.GetServiceReadWriteName()
 	cluster.Status.ReadService = cluster.GetServiceReadName()
@@ -748,12 +767,331 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		}
 	}
 
+	cluster.Status.LogicalReplicationSlotsStatus = getLogicalReplicationSlotsStatus(cluster, statuses)
+
+	setTargetRPOCondition(cluster, statuses, time.Now())
+	setWALArchiveBacklogCondition(cluster, statuses)
+	setTransactionIDAgeCondition(cluster, statuses)
+	setSlowQueryLoggingCondition(cluster, statuses)
+	setDiskTimeToFullCondition(cluster, statuses)
+	r.setSyncReplicationCondition(cluster)
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
 	return nil
 }
 
+// setTargetRPOCondition sets the condition reporting whether the archive
+// lag of the primary is within the target recovery point objective.
+// The condition is left unchanged if the primary is not reporting its
+// archive lag, and removed if the target is not set.
+func setTargetRPOCondition(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+	now time.Time,
+) {
+	targetRPO := cluster.Spec.Backup.GetTargetRPO()
+	if targetRPO == 0 || cluster.IsReplica() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionTargetRPO))
+		return
+	}
+
+	for _, item := range statuses.Items {
+		if !item.IsPrimary {
+			continue
+		}
+
+		archiveLag, ok := item.GetArchiveLag(now)
+		if !ok {
+			return
+		}
+
+		condition := apiv1.BuildTargetRPOMetCondition(targetRPO)
+		if archiveLag > targetRPO {
+			condition = apiv1.BuildTargetRPOExceededCondition(targetRPO)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+		return
+	}
+}
+
+// setWALArchiveBacklogCondition sets the condition reporting whether the
+// number of WAL files waiting to be archived by the primary is within the
+// threshold. The condition is left unchanged if the primary is not
+// reporting its status, and removed if the monitoring is not enabled.
+func setWALArchiveBacklogCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	configuration := cluster.Spec.Backup.GetWalArchiveBacklog()
+	if configuration == nil || cluster.IsReplica() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionWALArchiveBacklog))
+		return
+	}
+
+	for _, item := range statuses.Items {
+		if !item.IsPrimary {
+			continue
+		}
+
+		if !item.HasHTTPStatus() {
+			return
+		}
+
+		condition := apiv1.BuildWALArchiveBacklogWithinThresholdCondition(
+			item.ReadyWALFiles, configuration.MaxReadyWALFiles)
+		if item.ReadyWALFiles > int(configuration.MaxReadyWALFiles) {
+			condition = apiv1.BuildWALArchiveBacklogExceededCondition(
+				item.ReadyWALFiles, configuration.MaxReadyWALFiles, configuration.Action)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+		return
+	}
+}
+
+// setTransactionIDAgeCondition sets the condition reporting whether the
+// age of the oldest unfrozen transaction or multixact ID of the databases
+// is within the thresholds protecting from the wraparound.
+// The condition is left unchanged if the primary is not reporting its
+// status, and removed in replica clusters, where the age depends on
+// the source cluster.
+func setTransactionIDAgeCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	if cluster.IsReplica() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionTransactionIDAge))
+		return
+	}
+
+	for _, item := range statuses.Items {
+		if !item.IsPrimary {
+			continue
+		}
+
+		if !item.HasHTTPStatus() || item.OldestXIDAge == 0 {
+			return
+		}
+
+		warningAge := cluster.Spec.Monitoring.GetTransactionIDWarningAge()
+		criticalAge := cluster.Spec.Monitoring.GetTransactionIDCriticalAge()
+
+		var condition *metav1.Condition
+		switch {
+		case item.OldestXIDAge > criticalAge:
+			condition = apiv1.BuildTransactionIDAgeExceededCondition(
+				apiv1.ConditionReasonTransactionIDAgeCritical,
+				item.OldestXIDDatabase, item.OldestXIDAge, criticalAge)
+		case item.OldestXIDAge > warningAge:
+			condition = apiv1.BuildTransactionIDAgeExceededCondition(
+				apiv1.ConditionReasonTransactionIDAgeWarning,
+				item.OldestXIDDatabase, item.OldestXIDAge, warningAge)
+		default:
+			condition = apiv1.BuildTransactionIDAgeWithinThresholdCondition(item.OldestXIDAge, warningAge)
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+		return
+	}
+}
+
+// setSyncReplicationCondition sets the condition reporting whether the
+// primary is requiring `minSyncReplicas` synchronous standbys, raising
+// a warning event when the synchronous replication is relaxed because
+// of the standbys not being ready, and a normal one when it is restored.
+// The condition is removed unless the `relax` policy is in use.
+func (r *ClusterReconciler) setSyncReplicationCondition(cluster *apiv1.Cluster) {
+	if cluster.Spec.MinSyncReplicas == 0 ||
+		cluster.GetSyncReplicasUnavailablePolicy() != apiv1.SyncReplicasUnavailablePolicyRelax {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionSyncReplication))
+		return
+	}
+
+	syncReplicas, _ := cluster.GetSyncReplicasData()
+	condition := apiv1.BuildSyncReplicationEnforcedCondition(syncReplicas)
+	if syncReplicas < cluster.Spec.MinSyncReplicas {
+		condition = apiv1.BuildSyncReplicationRelaxedCondition(syncReplicas, cluster.Spec.MinSyncReplicas)
+	}
+
+	previousCondition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionSyncReplication))
+	wasRelaxed := previousCondition != nil && previousCondition.Status == metav1.ConditionFalse
+	isRelaxed := condition.Status == metav1.ConditionFalse
+	switch {
+	case isRelaxed && !wasRelaxed:
+		r.Recorder.Eventf(cluster, "Warning", "SynchronousReplicationRelaxed",
+			"Synchronous replication relaxed to %d standbys instead of %d, as not enough standbys are ready",
+			syncReplicas, cluster.Spec.MinSyncReplicas)
+	case !isRelaxed && wasRelaxed:
+		r.Recorder.Eventf(cluster, "Normal", "SynchronousReplicationRestored",
+			"Synchronous replication restored to %d standbys", syncReplicas)
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+}
+
+// setSlowQueryLoggingCondition sets the condition reporting whether every
+// instance is running with the slow query logging settings of the cluster,
+// comparing them with the values in effect reported by the instances.
+// The instances not reporting their status are not considered, and the
+// condition is removed if the slow query logging is not configured.
+func setSlowQueryLoggingCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	expectedSettings := cluster.Spec.PostgresConfiguration.SlowQueryLogging.GetParameters()
+	if len(expectedSettings) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionSlowQueryLogging))
+		return
+	}
+
+	var pendingInstances []string
+	reportingInstances := 0
+	for _, item := range statuses.Items {
+		if !item.HasHTTPStatus() || item.Pod == nil {
+			continue
+		}
+
+		reportingInstances++
+		for name, value := range expectedSettings {
+			if item.SlowQueryLoggingSettings[name] != value {
+				pendingInstances = append(pendingInstances, item.Pod.Name)
+				break
+			}
+		}
+	}
+
+	if reportingInstances == 0 {
+		return
+	}
+
+	condition := apiv1.BuildSlowQueryLoggingAppliedCondition()
+	if len(pendingInstances) > 0 {
+		sort.Strings(pendingInstances)
+		condition = apiv1.BuildSlowQueryLoggingPendingCondition(pendingInstances)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+}
+
+// setDiskTimeToFullCondition sets the condition reporting whether a volume
+// is projected to be full within the threshold set in the cluster, given
+// the growth rate of its used space reported by the instances. The
+// condition is kept unchanged when no instance is reporting the disk usage.
+func setDiskTimeToFullCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	var (
+		reporting      bool
+		minTimeToFull  time.Duration
+		fillingPod     string
+		fillingVolume  string
+		fillingVolumes bool
+	)
+	for _, item := range statuses.Items {
+		if !item.HasHTTPStatus() || item.Pod == nil || len(item.DiskUsage) == 0 {
+			continue
+		}
+
+		reporting = true
+		for _, usage := range item.DiskUsage {
+			timeToFull, ok := usage.GetTimeToFull()
+			if !ok || (fillingVolumes && timeToFull >= minTimeToFull) {
+				continue
+			}
+
+			fillingVolumes = true
+			minTimeToFull = timeToFull
+			fillingPod = item.Pod.Name
+			fillingVolume = usage.Volume
+		}
+	}
+
+	if !reporting {
+		return
+	}
+
+	threshold := cluster.Spec.Monitoring.GetDiskTimeToFullThreshold()
+	condition := apiv1.BuildDiskTimeToFullAboveThresholdCondition(threshold)
+	if fillingVolumes && minTimeToFull < threshold {
+		condition = apiv1.BuildDiskTimeToFullBelowThresholdCondition(
+			fillingPod, fillingVolume, minTimeToFull.Round(time.Second), threshold)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+}
+
+// targetRPOCheckInterval is the longest time between two checks
+// of the archive lag, when a target RPO is set
+const targetRPOCheckInterval = 30 * time.Second
+
+// getTargetRPORequeueAfter gets the time after which the cluster needs
+// to be reconciled again to check the archive lag, when a target RPO is
+// set or the WAL archive backlog is monitored, or zero otherwise
+func getTargetRPORequeueAfter(cluster *apiv1.Cluster) time.Duration {
+	if (cluster.Spec.Backup.GetTargetRPO() == 0 && cluster.Spec.Backup.GetWalArchiveBacklog() == nil) ||
+		cluster.IsReplica() {
+		return 0
+	}
+
+	return targetRPOCheckInterval
+}
+
+// transactionIDAgeCheckInterval is the longest time between two checks
+// of the age of the oldest unfrozen transaction ID
+const transactionIDAgeCheckInterval = 5 * time.Minute
+
+// getTransactionIDAgeRequeueAfter gets the time after which the cluster
+// needs to be reconciled again to check the age of the oldest unfrozen
+// transaction ID, which grows without any change to the cluster
+func getTransactionIDAgeRequeueAfter(cluster *apiv1.Cluster) time.Duration {
+	if cluster.IsReplica() {
+		return 0
+	}
+
+	return transactionIDAgeCheckInterval
+}
+
+// getLogicalReplicationSlotsStatus extracts the status of the managed
+// logical replication slots from the one reported by the primary instance.
+// The status is left unchanged if the primary instance is not reporting it.
+func getLogicalReplicationSlotsStatus(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) []apiv1.LogicalReplicationSlotStatus {
+	slots := cluster.Spec.ReplicationSlots.GetLogicalSlots()
+	if len(slots) == 0 {
+		return nil
+	}
+
+	var primary *postgres.PostgresqlStatus
+	for idx := range statuses.Items {
+		if statuses.Items[idx].IsPrimary && statuses.Items[idx].Error == nil {
+			primary = &statuses.Items[idx]
+			break
+		}
+	}
+	if primary == nil {
+		return cluster.Status.LogicalReplicationSlotsStatus
+	}
+
+	reportedSlots := make(map[string]postgres.PgReplicationSlot, len(primary.ReplicationSlotsInfo))
+	for _, reported := range primary.ReplicationSlotsInfo {
+		if reported.SlotType == "logical" {
+			reportedSlots[reported.SlotName] = reported
+		}
+	}
+
+	var result []apiv1.LogicalReplicationSlotStatus
+	for _, slot := range slots {
+		if slot.GetEnsure() != apiv1.EnsurePresent {
+			continue
+		}
+
+		reported, ok := reportedSlots[slot.Name]
+		if !ok {
+			continue
+		}
+
+		result = append(result, apiv1.LogicalReplicationSlotStatus{
+			Name:              reported.SlotName,
+			Database:          reported.Database,
+			Active:            reported.Active,
+			Failover:          reported.Failover,
+			ConfirmedFlushLSN: reported.ConfirmedFlushLsn,
+			LagBytes:          reported.LagBytes,
+		})
+	}
+
+	return result
+}
+
 // getPodsTopology returns a map with all the information about the pods topology
 func getPodsTopology(
 	ctx context.Context,
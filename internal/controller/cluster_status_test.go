@@ -18,15 +18,22 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -171,3 +178,458 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("logical replication slots status", func() {
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				ReplicationSlots: &v1.ReplicationSlotsConfiguration{
+					Logical: []v1.LogicalReplicationSlotConfiguration{
+						{Name: "cdc", Database: "app"},
+						{Name: "missing", Database: "app"},
+						{Name: "legacy", Database: "app", Ensure: v1.EnsureAbsent},
+					},
+				},
+			},
+		}
+	})
+
+	statuses := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{IsPrimary: false},
+			{
+				IsPrimary: true,
+				ReplicationSlotsInfo: postgres.PgReplicationSlotList{
+					{SlotName: "_cnpg_cluster_example_2", SlotType: "physical", Active: true},
+					{
+						SlotName:          "cdc",
+						SlotType:          "logical",
+						Database:          "app",
+						Active:            true,
+						Failover:          true,
+						ConfirmedFlushLsn: "0/3000060",
+						LagBytes:          1024,
+					},
+					{SlotName: "legacy", SlotType: "logical", Database: "app"},
+				},
+			},
+		},
+	}
+
+	It("reports the managed slots existing in the primary instance", func() {
+		Expect(getLogicalReplicationSlotsStatus(cluster, statuses)).To(Equal([]v1.LogicalReplicationSlotStatus{
+			{
+				Name:              "cdc",
+				Database:          "app",
+				Active:            true,
+				Failover:          true,
+				ConfirmedFlushLSN: "0/3000060",
+				LagBytes:          1024,
+			},
+		}))
+	})
+
+	It("keeps the previous status when the primary instance is not reporting it", func() {
+		cluster.Status.LogicalReplicationSlotsStatus = []v1.LogicalReplicationSlotStatus{{Name: "cdc"}}
+		Expect(getLogicalReplicationSlotsStatus(cluster, postgres.PostgresqlStatusList{})).
+			To(Equal(cluster.Status.LogicalReplicationSlotsStatus))
+	})
+
+	It("reports nothing without managed slots", func() {
+		cluster.Spec.ReplicationSlots = nil
+		Expect(getLogicalReplicationSlotsStatus(cluster, statuses)).To(BeNil())
+	})
+})
+
+var _ = Describe("target RPO condition", func() {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Backup: &v1.BackupConfiguration{TargetRPO: 300},
+			},
+		}
+	})
+
+	buildStatuses := func(lastArchived time.Duration) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{IsPrimary: false},
+				{
+					IsPrimary:           true,
+					ReadyWALFiles:       2,
+					LastArchivedWALTime: now.Add(-lastArchived).Format(time.RFC3339Nano),
+				},
+			},
+		}
+	}
+
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionTargetRPO))
+	}
+
+	It("reports an archive lag within the target RPO", func() {
+		setTargetRPOCondition(cluster, buildStatuses(time.Minute), now)
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports an archive lag exceeding the target RPO", func() {
+		setTargetRPOCondition(cluster, buildStatuses(10*time.Minute), now)
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonTargetRPOExceeded)))
+	})
+
+	It("keeps the condition when the primary is not reporting the archive lag", func() {
+		setTargetRPOCondition(cluster, buildStatuses(10*time.Minute), now)
+		setTargetRPOCondition(cluster, postgres.PostgresqlStatusList{}, now)
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("removes the condition when the target RPO is not set", func() {
+		setTargetRPOCondition(cluster, buildStatuses(10*time.Minute), now)
+		cluster.Spec.Backup.TargetRPO = 0
+		setTargetRPOCondition(cluster, buildStatuses(10*time.Minute), now)
+		Expect(getCondition()).To(BeNil())
+	})
+
+	It("requeues the cluster to check the archive lag", func() {
+		Expect(getTargetRPORequeueAfter(cluster)).To(Equal(targetRPOCheckInterval))
+
+		cluster.Spec.Backup = nil
+		Expect(getTargetRPORequeueAfter(cluster)).To(BeZero())
+	})
+})
+
+var _ = Describe("WAL archive backlog condition", func() {
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Backup: &v1.BackupConfiguration{
+					WalArchiveBacklog: &v1.WalArchiveBacklogConfiguration{
+						MaxReadyWALFiles: 10,
+						Action:           v1.WalArchiveBacklogActionThrottle,
+					},
+				},
+			},
+		}
+	})
+
+	buildStatuses := func(readyWALFiles int) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{IsPrimary: false},
+				{IsPrimary: true, ReadyWALFiles: readyWALFiles},
+			},
+		}
+	}
+
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionWALArchiveBacklog))
+	}
+
+	It("reports a backlog within the threshold", func() {
+		setWALArchiveBacklogCondition(cluster, buildStatuses(10))
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports a backlog exceeding the threshold", func() {
+		setWALArchiveBacklogCondition(cluster, buildStatuses(11))
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonWALArchiveBacklogExceeded)))
+		Expect(getCondition().Message).To(ContainSubstring("throttled"))
+	})
+
+	It("keeps the condition when the primary is not reporting its status", func() {
+		setWALArchiveBacklogCondition(cluster, buildStatuses(11))
+		setWALArchiveBacklogCondition(cluster, postgres.PostgresqlStatusList{})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("removes the condition when the monitoring is not enabled", func() {
+		setWALArchiveBacklogCondition(cluster, buildStatuses(11))
+		cluster.Spec.Backup.WalArchiveBacklog = nil
+		setWALArchiveBacklogCondition(cluster, buildStatuses(11))
+		Expect(getCondition()).To(BeNil())
+	})
+
+	It("requeues the cluster to check the backlog", func() {
+		Expect(getTargetRPORequeueAfter(cluster)).To(Equal(targetRPOCheckInterval))
+	})
+})
+
+var _ = Describe("transaction ID age condition", func() {
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Monitoring: &v1.MonitoringConfiguration{
+					TransactionIDWraparound: &v1.TransactionIDWraparoundConfiguration{
+						WarningAge:  1000,
+						CriticalAge: 2000,
+					},
+				},
+			},
+		}
+	})
+
+	buildStatuses := func(age int64) postgres.PostgresqlStatusList {
+		return postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{IsPrimary: false},
+				{IsPrimary: true, OldestXIDAge: age, OldestXIDDatabase: "app"},
+			},
+		}
+	}
+
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionTransactionIDAge))
+	}
+
+	It("reports an age within the thresholds", func() {
+		setTransactionIDAgeCondition(cluster, buildStatuses(1000))
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports an age exceeding the warning threshold", func() {
+		setTransactionIDAgeCondition(cluster, buildStatuses(1001))
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonTransactionIDAgeWarning)))
+		Expect(getCondition().Message).To(ContainSubstring(`"app"`))
+	})
+
+	It("reports an age exceeding the critical threshold", func() {
+		setTransactionIDAgeCondition(cluster, buildStatuses(2001))
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonTransactionIDAgeCritical)))
+	})
+
+	It("uses the default thresholds when they are not set", func() {
+		cluster.Spec.Monitoring = nil
+		setTransactionIDAgeCondition(cluster, buildStatuses(v1.DefaultTransactionIDWarningAge))
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+		setTransactionIDAgeCondition(cluster, buildStatuses(v1.DefaultTransactionIDCriticalAge+1))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonTransactionIDAgeCritical)))
+	})
+
+	It("keeps the condition when the primary is not reporting its status", func() {
+		setTransactionIDAgeCondition(cluster, buildStatuses(2001))
+		setTransactionIDAgeCondition(cluster, postgres.PostgresqlStatusList{})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("requeues the cluster to check the age periodically", func() {
+		Expect(getTransactionIDAgeRequeueAfter(cluster)).To(Equal(transactionIDAgeCheckInterval))
+	})
+})
+
+var _ = Describe("slow query logging condition", func() {
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				PostgresConfiguration: v1.PostgresConfiguration{
+					SlowQueryLogging: &v1.SlowQueryLoggingConfiguration{
+						MinDuration: "1s",
+						LockWaits:   ptr.To(true),
+					},
+				},
+			},
+		}
+	})
+
+	buildStatus := func(name string, minDuration string) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			SlowQueryLoggingSettings: map[string]string{
+				"log_min_duration_statement": minDuration,
+				"log_lock_waits":             "on",
+			},
+		}
+	}
+
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionSlowQueryLogging))
+	}
+
+	It("reports the settings in effect on every instance", func() {
+		setSlowQueryLoggingCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1", "1000"),
+				buildStatus("cluster-example-2", "1000"),
+			},
+		})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports the instances where the settings are not in effect yet", func() {
+		setSlowQueryLoggingCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-2", "-1"),
+				buildStatus("cluster-example-1", "1000"),
+			},
+		})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonSlowQueryLoggingPending)))
+		Expect(getCondition().Message).To(HaveSuffix(": cluster-example-2"))
+	})
+
+	It("keeps the condition when no instance is reporting its status", func() {
+		setSlowQueryLoggingCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{buildStatus("cluster-example-1", "-1")},
+		})
+		setSlowQueryLoggingCondition(cluster, postgres.PostgresqlStatusList{})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("removes the condition when the slow query logging is not configured", func() {
+		setSlowQueryLoggingCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{buildStatus("cluster-example-1", "1000")},
+		})
+		cluster.Spec.PostgresConfiguration.SlowQueryLogging = nil
+		setSlowQueryLoggingCondition(cluster, postgres.PostgresqlStatusList{})
+		Expect(getCondition()).To(BeNil())
+	})
+})
+
+var _ = Describe("disk time to full condition", func() {
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Monitoring: &v1.MonitoringConfiguration{
+					DiskUsage: &v1.DiskUsageConfiguration{TimeToFullThreshold: 3600},
+				},
+			},
+		}
+	})
+
+	buildStatus := func(name string, usage ...postgres.VolumeUsage) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			DiskUsage: usage,
+		}
+	}
+
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionDiskTimeToFull))
+	}
+
+	It("reports the volumes that are not going to be full soon", func() {
+		setDiskTimeToFullCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1",
+					postgres.VolumeUsage{Volume: "data", AvailableBytes: 7200, GrowthRate: 1},
+					postgres.VolumeUsage{Volume: "wal", AvailableBytes: 100}),
+			},
+		})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports the volume that is going to be full first", func() {
+		setDiskTimeToFullCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1",
+					postgres.VolumeUsage{Volume: "data", AvailableBytes: 1800, GrowthRate: 1}),
+				buildStatus("cluster-example-2",
+					postgres.VolumeUsage{Volume: "data", AvailableBytes: 1800, GrowthRate: 1},
+					postgres.VolumeUsage{Volume: "wal", AvailableBytes: 600, GrowthRate: 1}),
+			},
+		})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonDiskTimeToFullBelowThreshold)))
+		Expect(getCondition().Message).To(HavePrefix("The wal volume of instance cluster-example-2 " +
+			"is projected to be full in 10m0s"))
+	})
+
+	It("keeps the condition when no instance is reporting the disk usage", func() {
+		setDiskTimeToFullCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1",
+					postgres.VolumeUsage{Volume: "data", AvailableBytes: 60, GrowthRate: 1}),
+			},
+		})
+		setDiskTimeToFullCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{buildStatus("cluster-example-1")},
+		})
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+})
+
+var _ = Describe("synchronous replication condition", func() {
+	var (
+		cluster  *v1.Cluster
+		recorder *record.FakeRecorder
+		r        *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Instances:                     3,
+				MinSyncReplicas:               1,
+				MaxSyncReplicas:               2,
+				SyncReplicasUnavailablePolicy: v1.SyncReplicasUnavailablePolicyRelax,
+			},
+			Status: v1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				InstanceNames:  []string{"cluster-example-1", "cluster-example-2", "cluster-example-3"},
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		r = &ClusterReconciler{Recorder: recorder}
+	})
+
+	setHealthyInstances := func(instances ...string) {
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: instances,
+		}
+	}
+
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionSyncReplication))
+	}
+
+	It("reports the synchronous replication as enforced when the standbys are ready", func() {
+		setHealthyInstances("cluster-example-1", "cluster-example-2", "cluster-example-3")
+		r.setSyncReplicationCondition(cluster)
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("raises a warning when relaxing the synchronous replication and restores it", func() {
+		setHealthyInstances("cluster-example-1")
+		r.setSyncReplicationCondition(cluster)
+		Expect(getCondition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(getCondition().Reason).To(Equal(string(v1.ConditionReasonSyncReplicationRelaxed)))
+		Expect(<-recorder.Events).To(HavePrefix("Warning SynchronousReplicationRelaxed"))
+
+		r.setSyncReplicationCondition(cluster)
+		Expect(recorder.Events).To(BeEmpty())
+
+		setHealthyInstances("cluster-example-1", "cluster-example-2")
+		r.setSyncReplicationCondition(cluster)
+		Expect(getCondition().Status).To(Equal(metav1.ConditionTrue))
+		Expect(<-recorder.Events).To(HavePrefix("Normal SynchronousReplicationRestored"))
+	})
+
+	It("removes the condition with the default policy", func() {
+		setHealthyInstances("cluster-example-1")
+		r.setSyncReplicationCondition(cluster)
+		Expect(getCondition()).ToNot(BeNil())
+		<-recorder.Events
+
+		cluster.Spec.SyncReplicasUnavailablePolicy = ""
+		r.setSyncReplicationCondition(cluster)
+		Expect(getCondition()).To(BeNil())
+	})
+})
@@ -0,0 +1,542 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// maxSwitchoverReplayLag is the maximum amount of WAL, in bytes, that the
+// target of a requested switchover can still need to replay. This is the
+// size of a WAL segment with the default PostgreSQL settings.
+const maxSwitchoverReplayLag = 16 * 1024 * 1024
+
+const (
+	// switchoverPreparationPeriod is the minimum time a requested
+	// switchover is prepared for, during which it can be cancelled
+	switchoverPreparationPeriod = 10 * time.Second
+
+	// switchoverPreparationTimeout is the maximum time the target of a
+	// requested switchover can take to catch up with the current primary
+	switchoverPreparationTimeout = 5 * time.Minute
+
+	// switchoverReconfigurationTimeout is the maximum time the replicas
+	// can take to follow the new primary before the switchover is
+	// reported as completed anyway
+	switchoverReconfigurationTimeout = 5 * time.Minute
+)
+
+// reconcileSwitchoverRequest handles the switchover requested via the
+// switchoverTo annotation. It must be invoked only when the current primary
+// is healthy and no switchover or failover is in progress.
+// A requested switchover is prepared first, waiting for the target to catch
+// up with the current primary, and can be cancelled by removing the
+// annotation until the current primary is asked to shut down.
+// It returns true when the reconciliation needs to wait for the switchover
+// to progress.
+func (r *ClusterReconciler) reconcileSwitchoverRequest(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	requestedPrimary, requested := cluster.Annotations[utils.SwitchoverToAnnotationName]
+	progress := cluster.Status.Switchover
+
+	// The switchover we started is finished, either because the target
+	// is the new primary or because it was reverted. Let's report it.
+	if progress.IsInProgress() && progress.Phase != apiv1.SwitchoverPhasePreparing {
+		if cluster.Status.CurrentPrimary != progress.TargetPrimary {
+			return false, r.abortSwitchoverRequest(ctx, cluster, fmt.Errorf(
+				"switchover to %s aborted while in progress, %s is still the primary instance",
+				progress.TargetPrimary, cluster.Status.CurrentPrimary))
+		}
+
+		message := fmt.Sprintf("Switched over to %v", cluster.Status.CurrentPrimary)
+		if notFollowing := getReplicasNotFollowingPrimary(cluster, instancesStatus); len(notFollowing) > 0 {
+			if !isSwitchoverPhaseExpired(progress, apiv1.SwitchoverPhaseReconfiguringReplicas,
+				switchoverReconfigurationTimeout, time.Now()) {
+				return false, r.setSwitchoverPhase(ctx, cluster, apiv1.SwitchoverPhaseReconfiguringReplicas,
+					"Waiting for the replicas to follow the new primary")
+			}
+
+			contextLogger.Warning("Replicas not following the new primary after the switchover",
+				"currentPrimary", cluster.Status.CurrentPrimary,
+				"replicas", notFollowing)
+			message = fmt.Sprintf("%s, %s not following it yet", message, strings.Join(notFollowing, ", "))
+		}
+
+		contextLogger.Info("Requested switchover completed", "currentPrimary", cluster.Status.CurrentPrimary)
+		r.Recorder.Event(cluster, "Normal", "SwitchoverCompleted", message)
+		if err := conditions.Patch(ctx, r.Client, cluster,
+			apiv1.BuildSwitchoverSucceededCondition(cluster.Status.CurrentPrimary)); err != nil {
+			return false, err
+		}
+		if err := r.setSwitchoverPhase(ctx, cluster, apiv1.SwitchoverPhaseCompleted, message); err != nil {
+			return false, err
+		}
+		if requested && requestedPrimary != cluster.Status.CurrentPrimary {
+			// A different switchover has been requested in the meantime
+			return false, nil
+		}
+		return false, r.removeSwitchoverRequest(ctx, cluster)
+	}
+
+	// The switchover being prepared is cancelled when the request
+	// is removed or points to a different instance
+	if progress.IsInProgress() && (!requested || requestedPrimary != progress.TargetPrimary) {
+		contextLogger.Info("Requested switchover cancelled", "targetPrimary", progress.TargetPrimary)
+		r.Recorder.Eventf(cluster, "Normal", "SwitchoverCancelled",
+			"Switchover to %v cancelled", progress.TargetPrimary)
+		if err := conditions.Patch(ctx, r.Client, cluster, apiv1.BuildSwitchoverAbortedCondition(
+			fmt.Errorf("switchover to %s cancelled", progress.TargetPrimary))); err != nil {
+			return false, err
+		}
+		if err := r.setSwitchoverPhase(ctx, cluster, apiv1.SwitchoverPhaseCancelled,
+			"The request has been removed before the current primary was asked to shut down"); err != nil {
+			return false, err
+		}
+		return requested, nil
+	}
+
+	if !requested {
+		return false, nil
+	}
+
+	if requestedPrimary == cluster.Status.CurrentPrimary {
+		contextLogger.Info("Requested switchover target is already the primary instance",
+			"currentPrimary", cluster.Status.CurrentPrimary)
+		return false, r.removeSwitchoverRequest(ctx, cluster)
+	}
+
+	if err := checkSwitchoverTarget(cluster, instancesStatus, requestedPrimary); err != nil {
+		return false, r.abortSwitchoverRequest(ctx, cluster, err)
+	}
+
+	if !progress.IsInProgress() {
+		contextLogger.Info("Preparing the requested switchover",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", requestedPrimary)
+		r.Recorder.Eventf(cluster, "Normal", "SwitchoverPreparing",
+			"Preparing the switchover from %v to %v as requested", cluster.Status.CurrentPrimary, requestedPrimary)
+
+		now := metav1.Now()
+		origCluster := cluster.DeepCopy()
+		cluster.Status.Switchover = &apiv1.SwitchoverStatus{
+			SourcePrimary:  cluster.Status.CurrentPrimary,
+			TargetPrimary:  requestedPrimary,
+			Phase:          apiv1.SwitchoverPhasePreparing,
+			PhaseStartedAt: &now,
+			StartedAt:      &now,
+			Message:        "The target instance is eligible, the switchover can still be cancelled",
+		}
+		return true, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	prepared, message, err := checkSwitchoverPreparation(cluster, instancesStatus, time.Now())
+	if err != nil {
+		return false, r.abortSwitchoverRequest(ctx, cluster, err)
+	}
+	if !prepared {
+		return true, r.setSwitchoverPhase(ctx, cluster, apiv1.SwitchoverPhasePreparing, message)
+	}
+
+	// This is the point of no return: the current primary
+	// is going to shut down as soon as it notices the new target
+	contextLogger.Info("Starting the requested switchover",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", requestedPrimary)
+	instancesStatus.LogStatus(ctx)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
+		"Switching over from %v to %v as requested", cluster.Status.CurrentPrimary, requestedPrimary)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.TargetPrimary = requestedPrimary
+	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	meta.SetStatusCondition(&cluster.Status.Conditions, *apiv1.BuildSwitchoverStartedCondition(requestedPrimary))
+	updateSwitchoverPhase(cluster.Status.Switchover, apiv1.SwitchoverPhaseDemotingPrimary,
+		fmt.Sprintf("Waiting for %v to shut down", cluster.Status.CurrentPrimary))
+	if err := status.RegisterPhaseWithOrigCluster(
+		ctx,
+		r.Client,
+		cluster,
+		origCluster,
+		apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v", requestedPrimary),
+	); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// reconcileSwitchoverProgress reports the progress of the requested
+// switchover while the primary instance is changing, that is from when the
+// current primary is asked to shut down to when the target is promoted
+func (r *ClusterReconciler) reconcileSwitchoverProgress(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	progress := cluster.Status.Switchover
+	if !progress.IsInProgress() || progress.TargetPrimary != cluster.Status.TargetPrimary {
+		return nil
+	}
+
+	ctx, err := certs.NewTLSConfigForContext(ctx, r.Client, cluster.GetServerCASecretObjectKey())
+	if err != nil {
+		return err
+	}
+	instancesStatus := r.InstanceClient.GetStatusFromInstances(ctx, resources.instances)
+
+	phase, message := getSwitchoverProgress(cluster, instancesStatus)
+	if slices.Index(switchoverPhasesOrder, phase) <= slices.Index(switchoverPhasesOrder, progress.Phase) {
+		return nil
+	}
+
+	return r.setSwitchoverPhase(ctx, cluster, phase, message)
+}
+
+// switchoverPhasesOrder is the order in which the phases
+// of a switchover that is not terminated are entered
+var switchoverPhasesOrder = []apiv1.SwitchoverPhase{
+	apiv1.SwitchoverPhasePreparing,
+	apiv1.SwitchoverPhaseDemotingPrimary,
+	apiv1.SwitchoverPhaseWaitingForSync,
+	apiv1.SwitchoverPhasePromotingTarget,
+	apiv1.SwitchoverPhaseReconfiguringReplicas,
+}
+
+// getSwitchoverProgress detects the phase of a switchover while the
+// primary instance is changing, from the status of the former primary
+// and of the target instance
+func getSwitchoverProgress(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (apiv1.SwitchoverPhase, string) {
+	var formerPrimary, target *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		switch instancesStatus.Items[idx].Pod.Name {
+		case cluster.Status.CurrentPrimary:
+			formerPrimary = &instancesStatus.Items[idx]
+		case cluster.Status.TargetPrimary:
+			target = &instancesStatus.Items[idx]
+		}
+	}
+
+	switch {
+	case target != nil && target.HasHTTPStatus() && target.IsPrimary:
+		return apiv1.SwitchoverPhasePromotingTarget,
+			fmt.Sprintf("%v has been promoted and is taking over the primary role", cluster.Status.TargetPrimary)
+	case formerPrimary != nil && formerPrimary.HasHTTPStatus() && formerPrimary.IsPrimary:
+		return apiv1.SwitchoverPhaseDemotingPrimary,
+			fmt.Sprintf("Waiting for %v to shut down", cluster.Status.CurrentPrimary)
+	default:
+		return apiv1.SwitchoverPhaseWaitingForSync,
+			fmt.Sprintf("Waiting for %v to replay the WAL received from %v",
+				cluster.Status.TargetPrimary, cluster.Status.CurrentPrimary)
+	}
+}
+
+// getReplicasNotFollowingPrimary gets the instances that are not streaming
+// the WAL from the current primary, according to the replication status of
+// the latter. The fenced instances and the ones whose pod is not running,
+// for example because it is being replaced, are not waited for
+func getReplicasNotFollowingPrimary(cluster *apiv1.Cluster, instancesStatus postgres.PostgresqlStatusList) []string {
+	var primary *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		if instancesStatus.Items[idx].Pod.Name == cluster.Status.CurrentPrimary {
+			primary = &instancesStatus.Items[idx]
+		}
+	}
+
+	var notFollowing []string
+	for _, item := range instancesStatus.Items {
+		if item.Pod.Name == cluster.Status.CurrentPrimary || cluster.IsInstanceFenced(item.Pod.Name) ||
+			!utils.IsPodActive(*item.Pod) || item.Pod.Status.Phase == corev1.PodPending {
+			continue
+		}
+
+		following := primary != nil && primary.HasHTTPStatus() &&
+			slices.ContainsFunc(primary.ReplicationInfo, func(replication postgres.PgStatReplication) bool {
+				return replication.ApplicationName == item.Pod.Name
+			})
+		if !following {
+			notFollowing = append(notFollowing, item.Pod.Name)
+		}
+	}
+
+	return notFollowing
+}
+
+// isSwitchoverPhaseExpired checks whether the switchover has
+// been in the passed phase for longer than the passed timeout
+func isSwitchoverPhaseExpired(
+	progress *apiv1.SwitchoverStatus,
+	phase apiv1.SwitchoverPhase,
+	timeout time.Duration,
+	now time.Time,
+) bool {
+	return progress.Phase == phase && progress.PhaseStartedAt != nil &&
+		now.Sub(progress.PhaseStartedAt.Time) >= timeout
+}
+
+// checkSwitchoverPreparation checks whether the requested switchover has
+// been prepared, which happens when the target instance has caught up with
+// the current primary and the switchover has been prepared for at least
+// switchoverPreparationPeriod, leaving time to cancel it. It returns what the
+// preparation is waiting for, or an error when the target doesn't catch up
+// within switchoverPreparationTimeout
+func checkSwitchoverPreparation(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	now time.Time,
+) (bool, string, error) {
+	progress := cluster.Status.Switchover
+	lag, err := getSwitchoverTargetLag(cluster, instancesStatus, progress.TargetPrimary)
+	if err != nil {
+		return false, "", err
+	}
+
+	var preparationTime time.Duration
+	if progress.StartedAt != nil {
+		preparationTime = now.Sub(progress.StartedAt.Time)
+	}
+
+	if lag > maxSwitchoverReplayLag {
+		if preparationTime >= switchoverPreparationTimeout {
+			return false, "", fmt.Errorf("instance %s is lagging behind the primary by %d bytes",
+				progress.TargetPrimary, lag)
+		}
+		return false, fmt.Sprintf("Waiting for %v to catch up with the primary, the switchover can still be cancelled",
+			progress.TargetPrimary), nil
+	}
+
+	if preparationTime < switchoverPreparationPeriod {
+		return false, "The target instance is eligible, the switchover can still be cancelled", nil
+	}
+
+	return true, "", nil
+}
+
+// switchoverProgressCheckInterval is the time between two checks
+// of the replicas following the new primary after a switchover
+const switchoverProgressCheckInterval = 5 * time.Second
+
+// getSwitchoverProgressRequeueAfter gets the time after which the cluster
+// needs to be reconciled again to check if the replicas are following the
+// new primary, as that doesn't change any watched resource. It is zero
+// when no replica is being reconfigured
+func getSwitchoverProgressRequeueAfter(cluster *apiv1.Cluster) time.Duration {
+	progress := cluster.Status.Switchover
+	if !progress.IsInProgress() || progress.Phase != apiv1.SwitchoverPhaseReconfiguringReplicas {
+		return 0
+	}
+
+	return switchoverProgressCheckInterval
+}
+
+// setSwitchoverPhase stores the phase of the requested switchover
+// in the cluster status, if it changed
+func (r *ClusterReconciler) setSwitchoverPhase(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	phase apiv1.SwitchoverPhase,
+	message string,
+) error {
+	progress := cluster.Status.Switchover
+	if progress == nil || (progress.Phase == phase && progress.Message == message) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Switchover progress",
+		"targetPrimary", progress.TargetPrimary,
+		"phase", phase,
+		"message", message)
+
+	if progress.Phase != phase && slices.Contains(switchoverPhasesOrder, phase) {
+		r.Recorder.Eventf(cluster, "Normal", "SwitchoverProgress",
+			"Switchover to %v: %s", progress.TargetPrimary, message)
+	}
+
+	origCluster := cluster.DeepCopy()
+	updateSwitchoverPhase(cluster.Status.Switchover, phase, message)
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// updateSwitchoverPhase moves the switchover to the passed phase,
+// recording when it has been terminated
+func updateSwitchoverPhase(progress *apiv1.SwitchoverStatus, phase apiv1.SwitchoverPhase, message string) {
+	now := metav1.Now()
+	if progress.Phase != phase {
+		progress.PhaseStartedAt = &now
+	}
+	progress.Phase = phase
+	progress.Message = message
+	if !progress.IsInProgress() {
+		progress.StoppedAt = &now
+	}
+}
+
+// abortSwitchoverRequest reports why the requested switchover can't be
+// completed and removes the request
+func (r *ClusterReconciler) abortSwitchoverRequest(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason error,
+) error {
+	log.FromContext(ctx).Warning("Requested switchover aborted", "reason", reason.Error())
+	r.Recorder.Eventf(cluster, "Warning", "SwitchoverAborted", "Switchover aborted: %v", reason)
+	if err := conditions.Patch(ctx, r.Client, cluster, apiv1.BuildSwitchoverAbortedCondition(reason)); err != nil {
+		return err
+	}
+
+	if cluster.Status.Switchover.IsInProgress() {
+		if err := r.setSwitchoverPhase(ctx, cluster, apiv1.SwitchoverPhaseAborted, reason.Error()); err != nil {
+			return err
+		}
+	}
+
+	return r.removeSwitchoverRequest(ctx, cluster)
+}
+
+// removeSwitchoverRequest removes the switchoverTo annotation from the cluster
+func (r *ClusterReconciler) removeSwitchoverRequest(ctx context.Context, cluster *apiv1.Cluster) error {
+	if _, ok := cluster.Annotations[utils.SwitchoverToAnnotationName]; !ok {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.SwitchoverToAnnotationName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// checkSwitchoverTarget checks whether the named instance can safely become
+// the new primary of the cluster with a switchover, returning the reason
+// why that's not possible
+func checkSwitchoverTarget(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	targetName string,
+) error {
+	var primary, target *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		switch instancesStatus.Items[idx].Pod.Name {
+		case cluster.Status.CurrentPrimary:
+			primary = &instancesStatus.Items[idx]
+		case targetName:
+			target = &instancesStatus.Items[idx]
+		}
+	}
+
+	if primary == nil || !primary.HasHTTPStatus() {
+		return fmt.Errorf("the current primary %s is not reporting its status", cluster.Status.CurrentPrimary)
+	}
+
+	switch {
+	case target == nil:
+		return fmt.Errorf("instance %s is not part of the cluster", targetName)
+	case cluster.IsInstanceFenced(targetName):
+		return fmt.Errorf("instance %s is fenced", targetName)
+	case !target.IsPodReady || !target.HasHTTPStatus():
+		return fmt.Errorf("instance %s is not ready", targetName)
+	case !target.IsWalReceiverActive:
+		return fmt.Errorf("instance %s is not streaming from the primary", targetName)
+	case target.ReplayPaused:
+		return fmt.Errorf("instance %s has WAL replay paused", targetName)
+	}
+
+	// In a replica cluster the designated primary is a standby too,
+	// and there's no transaction being committed locally
+	if cluster.IsReplica() {
+		return nil
+	}
+
+	if cluster.Spec.MinSyncReplicas > 0 || cluster.Spec.MaxSyncReplicas > 0 {
+		// With synchronous replication, the target must be one of the
+		// standbys which are confirming the transactions committed on
+		// the primary
+		idx := slices.IndexFunc(primary.ReplicationInfo, func(replication postgres.PgStatReplication) bool {
+			return replication.ApplicationName == targetName
+		})
+		if idx == -1 {
+			return fmt.Errorf("instance %s is not streaming from the primary", targetName)
+		}
+		if syncState := primary.ReplicationInfo[idx].SyncState; syncState != "sync" && syncState != "quorum" {
+			return fmt.Errorf("instance %s is not a synchronous standby (sync state: %s)", targetName, syncState)
+		}
+	}
+
+	return nil
+}
+
+// getSwitchoverTargetLag gets the amount of WAL, in bytes, that the target
+// of a switchover still needs to replay to catch up with the current
+// primary. The designated primary of a replica cluster is a standby too,
+// and is never considered lagging behind
+func getSwitchoverTargetLag(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	targetName string,
+) (int64, error) {
+	if cluster.IsReplica() {
+		return 0, nil
+	}
+
+	var primary, target *postgres.PostgresqlStatus
+	for idx := range instancesStatus.Items {
+		switch instancesStatus.Items[idx].Pod.Name {
+		case cluster.Status.CurrentPrimary:
+			primary = &instancesStatus.Items[idx]
+		case targetName:
+			target = &instancesStatus.Items[idx]
+		}
+	}
+	if primary == nil || target == nil {
+		return 0, fmt.Errorf("instance %s or the current primary %s is not reporting its status",
+			targetName, cluster.Status.CurrentPrimary)
+	}
+
+	primaryLSN, err := primary.CurrentLsn.Parse()
+	if err != nil {
+		return 0, fmt.Errorf("while parsing the LSN of the current primary: %w", err)
+	}
+	targetLSN, err := target.ReplayLsn.Parse()
+	if err != nil {
+		return 0, fmt.Errorf("while parsing the replay LSN of instance %s: %w", targetName, err)
+	}
+
+	return primaryLSN - targetLSN, nil
+}
@@ -0,0 +1,392 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("requested switchover", func() {
+	var (
+		r               ClusterReconciler
+		cluster         *apiv1.Cluster
+		instancesStatus postgres.PostgresqlStatusList
+	)
+
+	buildStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:           isPrimary,
+			IsPodReady:          true,
+			IsWalReceiverActive: !isPrimary,
+			CurrentLsn:          "0/6000000",
+			ReplayLsn:           "0/6000000",
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.SwitchoverToAnnotationName: "cluster-example-2",
+				},
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				buildStatus("cluster-example-1", true),
+				buildStatus("cluster-example-2", false),
+				buildStatus("cluster-example-3", false),
+			},
+		}
+		instancesStatus.Items[0].ReplicationInfo = postgres.PgStatReplicationList{
+			{ApplicationName: "cluster-example-2", SyncState: "async"},
+			{ApplicationName: "cluster-example-3", SyncState: "async"},
+		}
+	})
+
+	getSwitchoverCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionSwitchover))
+	}
+
+	Context("checkSwitchoverTarget", func() {
+		It("accepts a healthy standby", func() {
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).To(Succeed())
+		})
+
+		It("rejects an unknown instance", func() {
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-4")).
+				To(MatchError(ContainSubstring("not part of the cluster")))
+		})
+
+		It("rejects an instance which is not ready", func() {
+			instancesStatus.Items[1].IsPodReady = false
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).
+				To(MatchError(ContainSubstring("not ready")))
+		})
+
+		It("rejects an instance which is not reporting its status", func() {
+			instancesStatus.Items[1].Error = fmt.Errorf("connection refused")
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).
+				To(MatchError(ContainSubstring("not ready")))
+		})
+
+		It("rejects an instance which is not streaming", func() {
+			instancesStatus.Items[1].IsWalReceiverActive = false
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).
+				To(MatchError(ContainSubstring("not streaming")))
+		})
+
+		It("rejects a fenced instance", func() {
+			cluster.Annotations[utils.FencedInstanceAnnotation] = `["cluster-example-2"]`
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).
+				To(MatchError(ContainSubstring("fenced")))
+		})
+
+		It("requires a synchronous standby when synchronous replication is enabled", func() {
+			cluster.Spec.MinSyncReplicas = 1
+			cluster.Spec.MaxSyncReplicas = 1
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).
+				To(MatchError(ContainSubstring("not a synchronous standby")))
+
+			instancesStatus.Items[0].ReplicationInfo[0].SyncState = "sync"
+			Expect(checkSwitchoverTarget(cluster, instancesStatus, "cluster-example-2")).To(Succeed())
+		})
+	})
+
+	Context("reconcileSwitchoverRequest", func() {
+		It("does nothing without a request", func(ctx SpecContext) {
+			delete(cluster.Annotations, utils.SwitchoverToAnnotationName)
+			r = newFakeClusterReconciler(cluster)
+
+			started, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(started).To(BeFalse())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+		})
+
+		It("prepares the switchover to the requested instance", func(ctx SpecContext) {
+			r = newFakeClusterReconciler(cluster)
+
+			switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(switchingOver).To(BeTrue())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+			Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhasePreparing))
+			Expect(cluster.Status.Switchover.SourcePrimary).To(Equal("cluster-example-1"))
+			Expect(cluster.Status.Switchover.TargetPrimary).To(Equal("cluster-example-2"))
+		})
+
+		It("keeps preparing the switchover during the preparation period", func(ctx SpecContext) {
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(switchingOver).To(BeTrue())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+			Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhasePreparing))
+		})
+
+		It("starts the prepared switchover to the requested instance", func(ctx SpecContext) {
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			cluster.Status.Switchover.StartedAt = ptr.To(metav1.NewTime(time.Now().Add(-switchoverPreparationPeriod)))
+			switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(switchingOver).To(BeTrue())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+			Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+			Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseDemotingPrimary))
+			Expect(getSwitchoverCondition().Reason).To(Equal(string(apiv1.ConditionReasonSwitchoverStarted)))
+			Expect(cluster.Annotations).To(HaveKey(utils.SwitchoverToAnnotationName))
+		})
+
+		It("cancels the prepared switchover when the request is removed", func(ctx SpecContext) {
+			r = newFakeClusterReconciler(cluster)
+
+			_, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+
+			delete(cluster.Annotations, utils.SwitchoverToAnnotationName)
+			switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(switchingOver).To(BeFalse())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+			Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseCancelled))
+			Expect(cluster.Status.Switchover.StoppedAt).ToNot(BeNil())
+			Expect(getSwitchoverCondition().Reason).To(Equal(string(apiv1.ConditionReasonSwitchoverAborted)))
+		})
+
+		It("rejects the switchover to an ineligible instance", func(ctx SpecContext) {
+			instancesStatus.Items[1].IsWalReceiverActive = false
+			r = newFakeClusterReconciler(cluster)
+
+			switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(switchingOver).To(BeFalse())
+			Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+			condition := getSwitchoverCondition()
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSwitchoverAborted)))
+			Expect(condition.Message).To(ContainSubstring("not streaming"))
+
+			var updatedCluster apiv1.Cluster
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Annotations).ToNot(HaveKey(utils.SwitchoverToAnnotationName))
+		})
+
+		When("the current primary has been asked to shut down", func() {
+			BeforeEach(func() {
+				meta.SetStatusCondition(&cluster.Status.Conditions,
+					*apiv1.BuildSwitchoverStartedCondition("cluster-example-2"))
+				cluster.Status.Switchover = &apiv1.SwitchoverStatus{
+					SourcePrimary: "cluster-example-1",
+					TargetPrimary: "cluster-example-2",
+					Phase:         apiv1.SwitchoverPhaseDemotingPrimary,
+				}
+			})
+
+			It("waits for the replicas to follow the new primary", func(ctx SpecContext) {
+				cluster.Status.CurrentPrimary = "cluster-example-2"
+				cluster.Status.TargetPrimary = "cluster-example-2"
+				instancesStatus.Items[0].IsPrimary = false
+				instancesStatus.Items[1].IsPrimary = true
+				r = newFakeClusterReconciler(cluster)
+
+				switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(switchingOver).To(BeFalse())
+				Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseReconfiguringReplicas))
+				Expect(cluster.Annotations).To(HaveKey(utils.SwitchoverToAnnotationName))
+				Expect(getSwitchoverProgressRequeueAfter(cluster)).To(Equal(switchoverProgressCheckInterval))
+			})
+
+			It("completes the switchover when the replicas don't follow the new primary in time",
+				func(ctx SpecContext) {
+					cluster.Status.CurrentPrimary = "cluster-example-2"
+					cluster.Status.TargetPrimary = "cluster-example-2"
+					cluster.Status.Switchover.Phase = apiv1.SwitchoverPhaseReconfiguringReplicas
+					cluster.Status.Switchover.PhaseStartedAt = ptr.To(metav1.NewTime(
+						time.Now().Add(-switchoverReconfigurationTimeout)))
+					instancesStatus.Items[0].IsPrimary = false
+					instancesStatus.Items[1].IsPrimary = true
+					r = newFakeClusterReconciler(cluster)
+
+					_, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseCompleted))
+					Expect(cluster.Status.Switchover.Message).
+						To(ContainSubstring("cluster-example-1, cluster-example-3 not following it yet"))
+					Expect(cluster.Annotations).ToNot(HaveKey(utils.SwitchoverToAnnotationName))
+				})
+
+			It("reports the completion of the switchover", func(ctx SpecContext) {
+				cluster.Status.CurrentPrimary = "cluster-example-2"
+				cluster.Status.TargetPrimary = "cluster-example-2"
+				instancesStatus.Items[0].IsPrimary = false
+				instancesStatus.Items[0].IsWalReceiverActive = true
+				instancesStatus.Items[1].IsPrimary = true
+				instancesStatus.Items[1].ReplicationInfo = postgres.PgStatReplicationList{
+					{ApplicationName: "cluster-example-1", SyncState: "async"},
+					{ApplicationName: "cluster-example-3", SyncState: "async"},
+				}
+				r = newFakeClusterReconciler(cluster)
+
+				switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(switchingOver).To(BeFalse())
+				Expect(getSwitchoverCondition().Status).To(Equal(metav1.ConditionTrue))
+				Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseCompleted))
+				Expect(cluster.Annotations).ToNot(HaveKey(utils.SwitchoverToAnnotationName))
+				Expect(getSwitchoverProgressRequeueAfter(cluster)).To(BeZero())
+			})
+
+			It("reports a switchover that has been aborted while in progress", func(ctx SpecContext) {
+				r = newFakeClusterReconciler(cluster)
+
+				switchingOver, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(switchingOver).To(BeFalse())
+				Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+				Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseAborted))
+				Expect(getSwitchoverCondition().Reason).To(Equal(string(apiv1.ConditionReasonSwitchoverAborted)))
+				Expect(cluster.Annotations).ToNot(HaveKey(utils.SwitchoverToAnnotationName))
+			})
+
+			It("can't be cancelled anymore", func(ctx SpecContext) {
+				cluster.Status.TargetPrimary = "cluster-example-2"
+				delete(cluster.Annotations, utils.SwitchoverToAnnotationName)
+				instancesStatus.Items[0].IsPrimary = false
+				instancesStatus.Items[1].IsPrimary = true
+				cluster.Status.CurrentPrimary = "cluster-example-2"
+				r = newFakeClusterReconciler(cluster)
+
+				_, err := r.reconcileSwitchoverRequest(ctx, cluster, instancesStatus)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cluster.Status.Switchover.Phase).To(Equal(apiv1.SwitchoverPhaseReconfiguringReplicas))
+			})
+		})
+	})
+
+	Context("checkSwitchoverPreparation", func() {
+		BeforeEach(func() {
+			cluster.Status.Switchover = &apiv1.SwitchoverStatus{
+				SourcePrimary: "cluster-example-1",
+				TargetPrimary: "cluster-example-2",
+				Phase:         apiv1.SwitchoverPhasePreparing,
+				StartedAt:     ptr.To(metav1.Now()),
+			}
+		})
+
+		It("waits for the preparation period", func() {
+			prepared, message, err := checkSwitchoverPreparation(cluster, instancesStatus, time.Now())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prepared).To(BeFalse())
+			Expect(message).To(ContainSubstring("can still be cancelled"))
+
+			prepared, _, err = checkSwitchoverPreparation(cluster, instancesStatus,
+				time.Now().Add(switchoverPreparationPeriod))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prepared).To(BeTrue())
+		})
+
+		It("waits for the target to catch up with the primary", func() {
+			instancesStatus.Items[1].ReplayLsn = "0/1000000"
+			prepared, message, err := checkSwitchoverPreparation(cluster, instancesStatus,
+				time.Now().Add(switchoverPreparationPeriod))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prepared).To(BeFalse())
+			Expect(message).To(ContainSubstring("catch up"))
+		})
+
+		It("fails when the target doesn't catch up in time", func() {
+			instancesStatus.Items[1].ReplayLsn = "0/1000000"
+			_, _, err := checkSwitchoverPreparation(cluster, instancesStatus,
+				time.Now().Add(switchoverPreparationTimeout))
+			Expect(err).To(MatchError(ContainSubstring("lagging behind")))
+		})
+	})
+
+	Context("getReplicasNotFollowingPrimary", func() {
+		It("checks the replicas streaming from the primary", func() {
+			Expect(getReplicasNotFollowingPrimary(cluster, instancesStatus)).To(BeEmpty())
+
+			instancesStatus.Items[0].ReplicationInfo = instancesStatus.Items[0].ReplicationInfo[:1]
+			Expect(getReplicasNotFollowingPrimary(cluster, instancesStatus)).To(ConsistOf("cluster-example-3"))
+		})
+
+		It("doesn't wait for the fenced instances and the ones not running", func() {
+			instancesStatus.Items[0].ReplicationInfo = nil
+			cluster.Annotations[utils.FencedInstanceAnnotation] = `["cluster-example-2"]`
+			instancesStatus.Items[2].Pod.Status.Phase = corev1.PodPending
+			Expect(getReplicasNotFollowingPrimary(cluster, instancesStatus)).To(BeEmpty())
+		})
+	})
+
+	Context("getSwitchoverProgress", func() {
+		BeforeEach(func() {
+			cluster.Status.TargetPrimary = "cluster-example-2"
+		})
+
+		It("waits for the current primary to shut down", func() {
+			phase, _ := getSwitchoverProgress(cluster, instancesStatus)
+			Expect(phase).To(Equal(apiv1.SwitchoverPhaseDemotingPrimary))
+		})
+
+		It("waits for the target to replay the WAL once the former primary is down", func() {
+			instancesStatus.Items[0].Error = fmt.Errorf("connection refused")
+			instancesStatus.Items[1].IsWalReceiverActive = false
+			phase, message := getSwitchoverProgress(cluster, instancesStatus)
+			Expect(phase).To(Equal(apiv1.SwitchoverPhaseWaitingForSync))
+			Expect(message).To(ContainSubstring("cluster-example-2"))
+		})
+
+		It("detects the promotion of the target", func() {
+			instancesStatus.Items[0].Error = fmt.Errorf("connection refused")
+			instancesStatus.Items[1].IsPrimary = true
+			phase, _ := getSwitchoverProgress(cluster, instancesStatus)
+			Expect(phase).To(Equal(apiv1.SwitchoverPhasePromotingTarget))
+		})
+	})
+})
@@ -25,14 +25,17 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/instance"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources/status"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -72,9 +75,19 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 			continue
 		}
 
+		canBeScheduled, err := r.canRecreatedPodBeScheduled(ctx, cluster, postgresqlStatus.Pod, podRollout)
+		if err != nil {
+			return false, err
+		}
+		if !canBeScheduled {
+			continue
+		}
+
 		restartMessage := fmt.Sprintf("Restarting instance %s, because: %s",
 			postgresqlStatus.Pod.Name, podRollout.reason)
-		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade, restartMessage); err != nil {
+		if err := r.registerRollingUpdatePhase(
+			ctx, cluster, apiv1.PhaseUpgrade, restartMessage, apiv1.ConditionReasonRollingUpdateReplicas,
+		); err != nil {
 			return false, fmt.Errorf("postgresqlStatus pod name: %s, %w", postgresqlStatus.Pod.Name, err)
 		}
 
@@ -96,17 +109,30 @@ func (r *ClusterReconciler) rolloutRequiredInstances(
 	// we first check whether a restart is needed given the provided condition
 	podRollout := isPodNeedingRollout(ctx, *primaryPostgresqlStatus, cluster)
 	if !podRollout.required {
-		return false, nil
+		return false, r.reportRollingUpdateCompleted(ctx, cluster)
 	}
 
 	// if the primary instance is marked for restart due to hot standby sensitive parameter decrease,
-	// it should be restarted by the instance manager itself
-	if primaryPostgresqlStatus.PendingRestartForDecrease {
+	// it should be restarted by the instance manager itself, unless the restart
+	// has been deferred by the restart strategy. In that case, we restart it
+	// in place, as the replicas can't be promoted before the primary is restarted
+	if primaryPostgresqlStatus.PendingRestartForDecrease &&
+		cluster.GetParameterChangeRestartStrategy() == apiv1.ParameterChangeRestartStrategyImmediate {
+		return false, nil
+	}
+
+	canBeScheduled, err := r.canRecreatedPodBeScheduled(ctx, cluster, primaryPostgresqlStatus.Pod, podRollout)
+	if err != nil {
+		return false, err
+	}
+	if !canBeScheduled {
 		return false, nil
 	}
 
 	return r.updatePrimaryPod(ctx, cluster, podList, *primaryPostgresqlStatus.Pod,
-		podRollout.canBeInPlace, podRollout.primaryForceRecreate, podRollout.reason)
+		podRollout.canBeInPlace,
+		podRollout.primaryForceRecreate || primaryPostgresqlStatus.PendingRestartForDecrease,
+		podRollout.reason)
 }
 
 func (r *ClusterReconciler) updatePrimaryPod(
@@ -125,7 +151,8 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised {
 		contextLogger.Info("Waiting for the user to request a switchover to complete the rolling update",
 			"reason", reason)
-		err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser, "User must issue a supervised switchover")
+		err := r.registerRollingUpdatePhase(ctx, cluster, apiv1.PhaseWaitingForUser,
+			"User must issue a supervised switchover", apiv1.ConditionReasonRollingUpdateWaitingForUser)
 		if err != nil {
 			return false, err
 		}
@@ -141,13 +168,15 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			}
 			contextLogger.Info("Restarting primary instance in-place",
 				"reason", reason)
-			err := r.RegisterPhase(ctx, cluster, apiv1.PhaseInplacePrimaryRestart, reason)
+			err := r.registerRollingUpdatePhase(ctx, cluster, apiv1.PhaseInplacePrimaryRestart, reason,
+				apiv1.ConditionReasonRollingUpdatePrimary)
 			return err == nil, err
 		}
 		// The pod needs to be deleted and recreated for the change to be applied
 		contextLogger.Info("Restarting primary instance without a switchover first",
 			"reason", reason)
-		err := r.RegisterPhase(ctx, cluster, apiv1.PhaseInplaceDeletePrimaryRestart, reason)
+		err := r.registerRollingUpdatePhase(ctx, cluster, apiv1.PhaseInplaceDeletePrimaryRestart, reason,
+			apiv1.ConditionReasonRollingUpdatePrimary)
 		if err != nil {
 			return false, err
 		}
@@ -155,21 +184,8 @@ func (r *ClusterReconciler) updatePrimaryPod(
 		return err == nil, err
 	}
 
-	// if the cluster has more than one instance, we should trigger a switchover before upgrading
-	if cluster.Status.Instances > 1 && len(podList.Items) > 1 {
-		// If this is not a replica cluster, podList.Items[1] is the first replica,
-		// as the pod list is sorted in the same order we use for switchover / failover.
-		// This may not be true for replica clusters, where every instance is a replica
-		// from the PostgreSQL point-of-view.
-		targetInstance := podList.Items[1]
-
-		// If this is a replica cluster, the target primary we chose may be
-		// the one we're trying to upgrade, as the list isn't sorted. In
-		// this case, we promote the first instance of the list
-		if targetInstance.Pod.Name == primaryPod.Name {
-			targetInstance = podList.Items[0]
-		}
-
+	// if the cluster has an instance that can be promoted, we should trigger a switchover before upgrading
+	if targetInstance := getSwitchoverTarget(cluster, podList, primaryPod.Name); targetInstance != nil {
 		// Before promoting a replica, the instance manager will wait for the WAL receiver
 		// process to be down. We're doing that to avoid losing data written on the primary.
 		// This protection can work only when the streaming connection is active.
@@ -193,13 +209,18 @@ func (r *ClusterReconciler) updatePrimaryPod(
 		podList.LogStatus(ctx)
 		r.Recorder.Eventf(cluster, "Normal", "Switchover",
 			"Initiating switchover to %s to upgrade %s", targetInstance.Pod.Name, primaryPod.Name)
+		meta.SetStatusCondition(&cluster.Status.Conditions, *apiv1.BuildRollingUpdateInProgressCondition(
+			apiv1.ConditionReasonRollingUpdateSwitchover,
+			fmt.Sprintf("Switching over to %s to update %s", targetInstance.Pod.Name, primaryPod.Name),
+		))
 		return true, r.setPrimaryInstance(ctx, cluster, targetInstance.Pod.Name)
 	}
 
-	// if there is only one instance in the cluster, we should upgrade it even if it's a primary
-	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade,
+	// if there is no other instance that can be promoted, we should upgrade it even if it's a primary
+	if err := r.registerRollingUpdatePhase(ctx, cluster, apiv1.PhaseUpgrade,
 		fmt.Sprintf("The primary instance needs to be restarted: %s, reason: %s",
 			primaryPod.Name, reason),
+		apiv1.ConditionReasonRollingUpdatePrimary,
 	); err != nil {
 		return false, fmt.Errorf("postgresqlStatus for pod %s: %w", primaryPod.Name, err)
 	}
@@ -207,6 +228,67 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	return true, r.upgradePod(ctx, cluster, &primaryPod, reason)
 }
 
+// getSwitchoverTarget gets the instance to be promoted before upgrading the
+// primary, or nil when there is no such instance, as it happens in clusters
+// made of a single instance or when the only replicas are delayed ones
+func getSwitchoverTarget(
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+	primaryName string,
+) *postgres.PostgresqlStatus {
+	if cluster.Status.Instances <= 1 || len(podList.Items) <= 1 {
+		return nil
+	}
+
+	// If this is not a replica cluster, podList.Items[1] is the first replica,
+	// as the pod list is sorted in the same order we use for switchover / failover.
+	// This may not be true for replica clusters, where every instance is a replica
+	// from the PostgreSQL point-of-view.
+	targetInstance := podList.Items[1]
+
+	// If this is a replica cluster, the target primary we chose may be
+	// the one we're trying to upgrade, as the list isn't sorted. In
+	// this case, we promote the first instance of the list
+	if targetInstance.Pod.Name == primaryName {
+		targetInstance = podList.Items[0]
+	}
+
+	// Delayed replicas are sorted after the other ones and are
+	// never promoted automatically
+	if targetInstance.IsDelayedReplica {
+		return nil
+	}
+
+	return &targetInstance
+}
+
+// registerRollingUpdatePhase sets the phase of the cluster together with
+// the ConditionRollingUpdate condition, reporting the current step of the
+// rolling update, using a single patch
+func (r *ClusterReconciler) registerRollingUpdatePhase(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	phase string,
+	phaseReason string,
+	conditionReason apiv1.ConditionReason,
+) error {
+	origCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&cluster.Status.Conditions,
+		*apiv1.BuildRollingUpdateInProgressCondition(conditionReason, phaseReason))
+	return status.RegisterPhaseWithOrigCluster(ctx, r.Client, cluster, origCluster, phase, phaseReason)
+}
+
+// reportRollingUpdateCompleted marks the rolling update as completed,
+// if one was in progress
+func (r *ClusterReconciler) reportRollingUpdateCompleted(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionRollingUpdate)) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Rolling update completed")
+	return conditions.Patch(ctx, r.Client, cluster, apiv1.RollingUpdateCompletedCondition)
+}
+
 func (r *ClusterReconciler) updateRestartAnnotation(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -291,6 +373,7 @@ func isPodNeedingRollout(
 		"pod image is outdated":                checkPodImageIsOutdated,
 		"postgres restart required":            checkPostgresPendingRestart,
 		"cluster has newer restart annotation": checkClusterHasNewerRestartAnnotation,
+		"pod placement doesn't match its role": checkPodPlacementIsOutdated,
 	}
 
 	podRollout := applyCheckers(checkers)
@@ -532,8 +615,13 @@ func checkClusterHasNewerRestartAnnotation(
 
 func checkPostgresPendingRestart(
 	status postgres.PostgresqlStatus,
-	_ *apiv1.Cluster,
+	cluster *apiv1.Cluster,
 ) (rollout, error) {
+	// The restart has been deferred by the restart strategy
+	if cluster.Status.PendingRestart != nil {
+		return rollout{}, nil
+	}
+
 	if status.PendingRestart {
 		return rollout{
 			required:     true,
@@ -607,10 +695,13 @@ func checkPodSpecIsOutdated(
 	if err != nil {
 		return rollout{}, fmt.Errorf("while unmarshaling the pod resources annotation: %w", err)
 	}
-	envConfig := specs.CreatePodEnvConfig(*cluster, status.Pod.Name)
-	gracePeriod := int64(cluster.GetMaxStopDelay())
-	tlsEnabled := instance.GetStatusSchemeFromPod(status.Pod).IsHTTPS()
-	targetPodSpec := specs.CreateClusterPodSpec(status.Pod.Name, *cluster, envConfig, gracePeriod, tlsEnabled)
+	targetPodSpec := getTargetPodSpec(status.Pod, cluster)
+
+	// the placement of the primary is applied the next time
+	// its pod is recreated, c.f. checkPodPlacementIsOutdated
+	if isPrimaryPlacementDeferred(status.Pod, cluster) {
+		copyPodPlacement(&targetPodSpec, storedPodSpec)
+	}
 
 	// the bootstrap init-container could change image after an operator upgrade.
 	// If in-place upgrades of the instance manager are enabled, we don't need rollout.
@@ -642,6 +733,107 @@ func checkPodSpecIsOutdated(
 	return rollout{}, nil
 }
 
+// canRecreatedPodBeScheduled checks whether the passed pod, when it needs
+// to be recreated with the placement rules of its role, can be scheduled on
+// a node where its persistent volumes can be attached. When it can't, the
+// pod is not recreated, as it would stay pending, and a warning is raised
+func (r *ClusterReconciler) canRecreatedPodBeScheduled(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pod *corev1.Pod,
+	podRollout rollout,
+) (bool, error) {
+	if cluster.Spec.RolePlacement == nil || podRollout.canBeInPlace {
+		return true, nil
+	}
+
+	compatible, err := r.isPlacementCompatibleWithStorage(ctx, cluster, pod.Name, getTargetPodSpec(pod, cluster))
+	if err != nil {
+		return false, fmt.Errorf("while checking the placement of pod %s: %w", pod.Name, err)
+	}
+	if compatible {
+		return true, nil
+	}
+
+	log.FromContext(ctx).Info(
+		"The pod can't be recreated, as no node matching its placement rules can attach its volumes",
+		"pod", pod.Name,
+		"reason", podRollout.reason)
+	r.Recorder.Eventf(cluster, "Warning", "PlacementNotCompatibleWithStorage",
+		"Pod %s can't be recreated: no node matching its placement rules can attach its volumes", pod.Name)
+	return false, nil
+}
+
+// checkPodPlacementIsOutdated checks whether the placement rules of the pod
+// match the ones defined for the role of its instance, as it's not the case
+// when a replica has been promoted. The primary is never switched over
+// because of its placement, as the promoted replica would be placed with
+// the rules of the replicas too: it's only recreated in place when the
+// primary update method is restart
+func checkPodPlacementIsOutdated(
+	status postgres.PostgresqlStatus,
+	cluster *apiv1.Cluster,
+) (rollout, error) {
+	if cluster.Spec.RolePlacement == nil || isPrimaryPlacementDeferred(status.Pod, cluster) {
+		return rollout{}, nil
+	}
+
+	podSpecAnnotation, ok := status.Pod.ObjectMeta.Annotations[utils.PodSpecAnnotationName]
+	if !ok {
+		return rollout{}, nil
+	}
+
+	var storedPodSpec corev1.PodSpec
+	if err := json.Unmarshal([]byte(podSpecAnnotation), &storedPodSpec); err != nil {
+		return rollout{}, fmt.Errorf("while unmarshaling the pod resources annotation: %w", err)
+	}
+	targetPodSpec := getTargetPodSpec(status.Pod, cluster)
+
+	if isPodPlacementEqual(storedPodSpec, targetPodSpec) {
+		return rollout{}, nil
+	}
+
+	return rollout{
+		required: true,
+		reason: fmt.Sprintf("the placement of pod '%s' doesn't match the rules for its role",
+			status.Pod.Name),
+	}, nil
+}
+
+// isPrimaryPlacementDeferred checks whether the passed pod is the one of the
+// current primary, and its placement rules must not cause a rollout, as the
+// primary would be switched over
+func isPrimaryPlacementDeferred(pod *corev1.Pod, cluster *apiv1.Cluster) bool {
+	return cluster.Spec.RolePlacement != nil &&
+		pod.Name == cluster.Status.CurrentPrimary &&
+		cluster.GetPrimaryUpdateMethod() != apiv1.PrimaryUpdateMethodRestart
+}
+
+// getTargetPodSpec gets the spec the pod of the passed instance
+// would be recreated with
+func getTargetPodSpec(pod *corev1.Pod, cluster *apiv1.Cluster) corev1.PodSpec {
+	envConfig := specs.CreatePodEnvConfig(*cluster, pod.Name)
+	gracePeriod := int64(cluster.GetMaxStopDelay())
+	tlsEnabled := instance.GetStatusSchemeFromPod(pod).IsHTTPS()
+	return specs.CreateClusterPodSpec(pod.Name, *cluster, envConfig, gracePeriod, tlsEnabled)
+}
+
+// isPodPlacementEqual checks whether the passed pod specs have the same placement rules
+func isPodPlacementEqual(currentPodSpec, targetPodSpec corev1.PodSpec) bool {
+	return reflect.DeepEqual(currentPodSpec.Affinity, targetPodSpec.Affinity) &&
+		reflect.DeepEqual(currentPodSpec.Tolerations, targetPodSpec.Tolerations) &&
+		reflect.DeepEqual(currentPodSpec.NodeSelector, targetPodSpec.NodeSelector) &&
+		reflect.DeepEqual(currentPodSpec.TopologySpreadConstraints, targetPodSpec.TopologySpreadConstraints)
+}
+
+// copyPodPlacement copies the placement rules of the passed pod spec
+func copyPodPlacement(podSpec *corev1.PodSpec, sourcePodSpec corev1.PodSpec) {
+	podSpec.Affinity = sourcePodSpec.Affinity
+	podSpec.Tolerations = sourcePodSpec.Tolerations
+	podSpec.NodeSelector = sourcePodSpec.NodeSelector
+	podSpec.TopologySpreadConstraints = sourcePodSpec.TopologySpreadConstraints
+}
+
 // upgradePod deletes a Pod to let the operator recreate it using an
 // updated definition
 func (r *ClusterReconciler) upgradePod(
@@ -17,11 +17,14 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"encoding/json"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -666,3 +669,202 @@ var _ = Describe("hasValidPodSpec", func() {
 		})
 	})
 })
+
+var _ = Describe("rolling update condition", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+		podList postgres.PostgresqlStatusList
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:             2,
+				PrimaryUpdateStrategy: apiv1.PrimaryUpdateStrategyUnsupervised,
+				PrimaryUpdateMethod:   apiv1.PrimaryUpdateMethodSwitchover,
+			},
+			Status: apiv1.ClusterStatus{
+				Instances:      2,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+
+		podList = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary: true,
+				},
+				{
+					Pod:                 &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					IsWalReceiverActive: true,
+				},
+			},
+		}
+	})
+
+	getRollingUpdateCondition := func(ctx context.Context) *metav1.Condition {
+		var updatedCluster apiv1.Cluster
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return meta.FindStatusCondition(updatedCluster.Status.Conditions, string(apiv1.ConditionRollingUpdate))
+	}
+
+	It("reports the switchover of the primary instance", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		done, err := r.updatePrimaryPod(ctx, cluster, &podList, *podList.Items[0].Pod,
+			true, false, "the instance is using a different image")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		condition := getRollingUpdateCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRollingUpdateSwitchover)))
+		Expect(condition.Message).To(Equal("Switching over to cluster-example-2 to update cluster-example-1"))
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+	})
+
+	It("doesn't switch over to a delayed replica", func() {
+		Expect(getSwitchoverTarget(cluster, &podList, "cluster-example-1").Pod.Name).To(Equal("cluster-example-2"))
+
+		podList.Items[1].IsDelayedReplica = true
+		Expect(getSwitchoverTarget(cluster, &podList, "cluster-example-1")).To(BeNil())
+	})
+
+	It("reports that the user must issue a switchover", func(ctx SpecContext) {
+		cluster.Spec.PrimaryUpdateStrategy = apiv1.PrimaryUpdateStrategySupervised
+		r = newFakeClusterReconciler(cluster)
+
+		done, err := r.updatePrimaryPod(ctx, cluster, &podList, *podList.Items[0].Pod,
+			true, false, "the instance is using a different image")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		condition := getRollingUpdateCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRollingUpdateWaitingForUser)))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseWaitingForUser))
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("reports the restart of the primary instance", func(ctx SpecContext) {
+		cluster.Spec.PrimaryUpdateMethod = apiv1.PrimaryUpdateMethodRestart
+		r = newFakeClusterReconciler(cluster)
+
+		done, err := r.updatePrimaryPod(ctx, cluster, &podList, *podList.Items[0].Pod,
+			true, false, "Postgres needs a restart to apply some configuration changes")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		condition := getRollingUpdateCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRollingUpdatePrimary)))
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseInplacePrimaryRestart))
+	})
+
+	It("marks the rolling update as completed", func(ctx SpecContext) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, *apiv1.BuildRollingUpdateInProgressCondition(
+			apiv1.ConditionReasonRollingUpdateReplicas, "Restarting instance cluster-example-2"))
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.reportRollingUpdateCompleted(ctx, cluster)).To(Succeed())
+		condition := getRollingUpdateCondition(ctx)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonRollingUpdateCompleted)))
+	})
+
+	It("doesn't add the condition when no rolling update happened", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+
+		Expect(r.reportRollingUpdateCompleted(ctx, cluster)).To(Succeed())
+		Expect(getRollingUpdateCondition(ctx)).To(BeNil())
+	})
+})
+
+var _ = Describe("Role placement rollout", func() {
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:13.11",
+				RolePlacement: &apiv1.RolePlacementConfiguration{
+					Primary: &apiv1.InstancePlacement{
+						NodeSelector: map[string]string{"pool": "primary"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "test-1",
+				TargetPrimary:  "test-1",
+			},
+		}
+		configuration.Current = configuration.NewConfiguration()
+	})
+
+	It("doesn't require a rollout when the pods match the rules of their role", func(ctx SpecContext) {
+		for _, serial := range []int{1, 2} {
+			status := postgres.PostgresqlStatus{
+				Pod:            specs.PodWithExistingStorage(cluster, serial),
+				IsPodReady:     true,
+				ExecutableHash: "test_hash",
+			}
+			Expect(isPodNeedingRollout(ctx, status, &cluster).required).To(BeFalse())
+		}
+	})
+
+	It("recreates a promoted replica in place with the rules of the primary", func() {
+		pod := specs.PodWithExistingStorage(cluster, 2)
+		cluster.Status.CurrentPrimary = "test-2"
+		cluster.Status.TargetPrimary = "test-2"
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		rollout, err := checkPodPlacementIsOutdated(status, &cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollout.required).To(BeTrue())
+		Expect(rollout.primaryForceRecreate).To(BeFalse())
+		Expect(rollout.reason).To(ContainSubstring("doesn't match the rules for its role"))
+	})
+
+	It("never switches over a promoted replica because of its placement", func(ctx SpecContext) {
+		cluster.Spec.PrimaryUpdateMethod = apiv1.PrimaryUpdateMethodSwitchover
+		pod := specs.PodWithExistingStorage(cluster, 2)
+		cluster.Status.CurrentPrimary = "test-2"
+		cluster.Status.TargetPrimary = "test-2"
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		Expect(isPodNeedingRollout(ctx, status, &cluster).required).To(BeFalse())
+	})
+
+	It("moves the former primary with the rules of the replicas", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		cluster.Status.CurrentPrimary = "test-2"
+		cluster.Status.TargetPrimary = "test-2"
+
+		status := postgres.PostgresqlStatus{
+			Pod:            pod,
+			IsPodReady:     true,
+			ExecutableHash: "test_hash",
+		}
+		rollout, err := checkPodPlacementIsOutdated(status, &cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollout.required).To(BeTrue())
+	})
+})
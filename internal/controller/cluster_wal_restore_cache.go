@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// walRestoreCacheCheckInterval is how often the operator checks
+// if the claim of the shared WAL restore cache became usable
+const walRestoreCacheCheckInterval = 5 * time.Minute
+
+// reconcileWalRestoreCache checks if the PersistentVolumeClaim hosting the
+// shared WAL restore cache can be mounted by the instances, recording it in
+// the status. The claim is mounted only once verified, so that a missing
+// claim never prevents the instances from starting, as they fall back to
+// their own spool
+func (r *ClusterReconciler) reconcileWalRestoreCache(ctx context.Context, cluster *apiv1.Cluster) error {
+	claimName, err := r.getWalRestoreCacheClaim(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if claimName == cluster.Status.WalRestoreCacheClaim {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.WalRestoreCacheClaim = claimName
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getWalRestoreCacheClaim gets the name of the claim hosting the shared
+// WAL restore cache, if it can be mounted by the instances, or an empty
+// string otherwise
+func (r *ClusterReconciler) getWalRestoreCacheClaim(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
+	if cluster.Spec.WalRestoreCache == nil {
+		return "", nil
+	}
+
+	claimName := cluster.Spec.WalRestoreCache.ClaimName
+	var pvc corev1.PersistentVolumeClaim
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: claimName}, &pvc)
+
+	var reason string
+	switch {
+	case apierrs.IsNotFound(err):
+		reason = "the PersistentVolumeClaim doesn't exist"
+	case err != nil:
+		return cluster.Status.WalRestoreCacheClaim, err
+	case !pvc.DeletionTimestamp.IsZero():
+		reason = "the PersistentVolumeClaim is being deleted"
+	case !slices.Contains(pvc.Spec.AccessModes, corev1.ReadWriteMany):
+		reason = "the PersistentVolumeClaim doesn't have the ReadWriteMany access mode"
+	default:
+		return claimName, nil
+	}
+
+	log.FromContext(ctx).Info("The shared WAL restore cache can't be used, "+
+		"the instances will use their own spool",
+		"claimName", claimName,
+		"reason", reason)
+	r.Recorder.Eventf(cluster, "Warning", "WalRestoreCacheUnavailable",
+		"The shared WAL restore cache %s can't be used: %s", claimName, reason)
+	return "", nil
+}
+
+// getWalRestoreCacheRequeueAfter gets the time after which the cluster
+// needs to be reconciled again to check if the claim of the shared WAL
+// restore cache became usable, as the operator is not notified about the
+// changes of the claims it doesn't own. It is zero when it's not needed
+func getWalRestoreCacheRequeueAfter(cluster *apiv1.Cluster) time.Duration {
+	if cluster.Spec.WalRestoreCache == nil || cluster.ShouldMountWalRestoreCache() {
+		return 0
+	}
+
+	return walRestoreCacheCheckInterval
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shared WAL restore cache", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				WalRestoreCache: &apiv1.WalRestoreCacheConfiguration{ClaimName: "wal-cache"},
+			},
+		}
+	})
+
+	newClaim := func(accessMode corev1.PersistentVolumeAccessMode) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "wal-cache", Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{accessMode},
+			},
+		}
+	}
+
+	It("mounts a usable claim", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster, newClaim(corev1.ReadWriteMany))
+		Expect(r.reconcileWalRestoreCache(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.WalRestoreCacheClaim).To(Equal("wal-cache"))
+		Expect(cluster.ShouldMountWalRestoreCache()).To(BeTrue())
+		Expect(getWalRestoreCacheRequeueAfter(cluster)).To(BeZero())
+	})
+
+	It("doesn't mount a missing claim, and checks it again later", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster)
+		Expect(r.reconcileWalRestoreCache(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.WalRestoreCacheClaim).To(BeEmpty())
+		Expect(cluster.ShouldMountWalRestoreCache()).To(BeFalse())
+		Expect(getWalRestoreCacheRequeueAfter(cluster)).To(Equal(walRestoreCacheCheckInterval))
+	})
+
+	It("doesn't mount a claim which can't be shared by the instances", func(ctx SpecContext) {
+		r = newFakeClusterReconciler(cluster, newClaim(corev1.ReadWriteOnce))
+		Expect(r.reconcileWalRestoreCache(ctx, cluster)).To(Succeed())
+		Expect(cluster.ShouldMountWalRestoreCache()).To(BeFalse())
+	})
+
+	It("stops mounting the claim when the cache is disabled", func(ctx SpecContext) {
+		cluster.Status.WalRestoreCacheClaim = "wal-cache"
+		cluster.Spec.WalRestoreCache = nil
+		r = newFakeClusterReconciler(cluster, newClaim(corev1.ReadWriteMany))
+		Expect(r.reconcileWalRestoreCache(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.WalRestoreCacheClaim).To(BeEmpty())
+	})
+})
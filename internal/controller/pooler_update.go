@@ -174,9 +174,10 @@ func (r *PoolerReconciler) updateRBAC(
 			return err
 		}
 		resources.Role = role
-	} else if !reflect.DeepEqual(role.Rules, resources.Role.Rules) {
+	} else if !reflect.DeepEqual(role.Rules, resources.Role.Rules) || !isMetadataInherited(resources.Role, role) {
 		contextLog.Info("Updating role")
 		resources.Role.Rules = role.Rules
+		utils.MergeObjectsMetadata(resources.Role, role)
 		if err := r.Update(ctx, resources.Role); err != nil {
 			return err
 		}
@@ -194,9 +195,11 @@ func (r *PoolerReconciler) updateRBAC(
 		}
 		resources.RoleBinding = &roleBinding
 	} else if !reflect.DeepEqual(roleBinding.Subjects, resources.RoleBinding.Subjects) ||
-		!reflect.DeepEqual(roleBinding.RoleRef, resources.RoleBinding.RoleRef) {
+		!reflect.DeepEqual(roleBinding.RoleRef, resources.RoleBinding.RoleRef) ||
+		!isMetadataInherited(resources.RoleBinding, &roleBinding) {
 		resources.RoleBinding.RoleRef = roleBinding.RoleRef
 		resources.RoleBinding.Subjects = roleBinding.Subjects
+		utils.MergeObjectsMetadata(resources.RoleBinding, &roleBinding)
 		if err := r.Update(ctx, resources.RoleBinding); err != nil {
 			return err
 		}
@@ -240,6 +243,10 @@ func (r *PoolerReconciler) updateServiceAccount(
 
 	origServiceAccount := resources.ServiceAccount.DeepCopy()
 	ensureServiceAccountHaveImagePullSecret(resources.ServiceAccount, pullSecretName)
+	if expectedServiceAccount := pgbouncer.ServiceAccount(pooler); !isMetadataInherited(
+		resources.ServiceAccount, expectedServiceAccount) {
+		utils.MergeObjectsMetadata(resources.ServiceAccount, expectedServiceAccount)
+	}
 	if !reflect.DeepEqual(origServiceAccount, resources.ServiceAccount) {
 		contextLog.Info("Updating service account")
 		if err := r.Patch(ctx, resources.ServiceAccount, client.MergeFrom(origServiceAccount)); err != nil {
@@ -340,3 +347,10 @@ func ensureServiceAccountHaveImagePullSecret(serviceAccount *corev1.ServiceAccou
 		Name: pullSecretName,
 	})
 }
+
+// isMetadataInherited checks if the labels and the annotations of
+// the expected object are already set in the current one
+func isMetadataInherited(current, expected client.Object) bool {
+	return utils.IsMapSubset(current.GetLabels(), expected.GetLabels()) &&
+		utils.IsMapSubset(current.GetAnnotations(), expected.GetAnnotations())
+}
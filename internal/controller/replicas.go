@@ -40,6 +40,16 @@ var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 // elapsed yet
 var ErrWaitingOnFailOverDelay = fmt.Errorf("current primary isn't healthy, waiting for the delay before triggering a failover") //nolint: lll
 
+// ErrWaitingOnFailoverCooldown is raised when the primary server can't be elected because the
+// .spec.failoverCooldown period, started with the last promotion, hasn't elapsed yet
+var ErrWaitingOnFailoverCooldown = fmt.Errorf("current primary isn't healthy, waiting for the failover cooldown period to expire") //nolint: lll
+
+// ErrNoFailoverCandidates is raised when the primary server can't be elected
+// because the only available instances are delayed replicas or fenced
+// instances, which are never promoted automatically
+var ErrNoFailoverCandidates = fmt.Errorf(
+	"no instance can be promoted, the only available ones are delayed or fenced replicas")
+
 // reconcileTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion.
 // Returns the name of the primary if any changes was made and any error encountered.
@@ -93,7 +103,8 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 	contextLogger := log.FromContext(ctx)
 
 	mostAdvancedInstance := status.Items[0]
-	if cluster.Status.TargetPrimary == mostAdvancedInstance.Pod.Name {
+	failoverCandidate := selectFailoverCandidate(cluster, status)
+	if cluster.Status.TargetPrimary == failoverCandidate.Pod.Name {
 		return "", nil
 	}
 
@@ -102,6 +113,21 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 		return "", nil
 	}
 
+	// Delayed replicas and fenced instances are sorted after the other ones,
+	// and when the most advanced instance is one of them there is nothing
+	// that can be promoted
+	if !mostAdvancedInstance.IsElectable() {
+		return "", ErrNoFailoverCandidates
+	}
+
+	// The cooldown period only prevents new failovers from being initiated,
+	// it doesn't stop the ones in progress
+	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if err := enforceFailoverCooldown(ctx, cluster, status); err != nil {
+			return "", err
+		}
+	}
+
 	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
 		return "", err
 	}
@@ -117,7 +143,7 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 			"Current primary isn't healthy, initiating a failover from %v", cluster.Status.CurrentPrimary)
-		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
+		if err := r.registerFailoverStart(ctx, cluster,
 			fmt.Sprintf("Initiating a failover from %v", cluster.Status.CurrentPrimary)); err != nil {
 			return "", err
 		}
@@ -136,33 +162,43 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForNonReplicaCluster(
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
-		contextLogger.Info("Failing over", "newPrimary", mostAdvancedInstance.Pod.Name)
+		contextLogger.Info("Failing over", "newPrimary", failoverCandidate.Pod.Name)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
 			"Failing over from %v to %v",
-			cluster.Status.CurrentPrimary, mostAdvancedInstance.Pod.Name)
+			cluster.Status.CurrentPrimary, failoverCandidate.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
-			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, mostAdvancedInstance.Pod.Name),
+			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, failoverCandidate.Pod.Name),
 		); err != nil {
 			return "", err
 		}
 	} else {
 		contextLogger.Info("Target primary isn't healthy, switching target",
-			"newPrimary", mostAdvancedInstance.Pod.Name)
+			"newPrimary", failoverCandidate.Pod.Name)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 			"Target primary isn't healthy, switching target from %v to %v",
-			cluster.Status.TargetPrimary, mostAdvancedInstance.Pod.Name)
+			cluster.Status.TargetPrimary, failoverCandidate.Pod.Name)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
-			fmt.Sprintf("Switching over to %v", mostAdvancedInstance.Pod.Name)); err != nil {
+			fmt.Sprintf("Switching over to %v", failoverCandidate.Pod.Name)); err != nil {
 			return "", err
 		}
 	}
 
-	// Set the first pod in the sorted list as the new targetPrimary
-	return mostAdvancedInstance.Pod.Name, r.setPrimaryInstance(ctx, cluster, mostAdvancedInstance.Pod.Name)
+	// Set the failover candidate as the new targetPrimary
+	return failoverCandidate.Pod.Name, r.setPrimaryInstance(ctx, cluster, failoverCandidate.Pod.Name)
+}
+
+// selectFailoverCandidate gets the instance to be promoted from the sorted
+// status list. It's the most advanced replica, unless a replica with a higher
+// failover priority has received WAL within the LSN tolerance from it
+func selectFailoverCandidate(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) postgres.PostgresqlStatus {
+	return status.SelectFailoverCandidate(cluster.Spec.FailoverPriority.GetPriority, cluster.GetFailoverLSNTolerance())
 }
 
 // isNodeUnschedulable checks whether a node is set to unschedulable
@@ -195,13 +231,13 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 	// schedulable instance, wait, because something is in progress
 	if !hasFailedPods &&
 		// e.g an instance is being joined
-		(cluster.Spec.Instances != cluster.Status.ReadyInstances ||
+		(cluster.GetInstances() != cluster.Status.ReadyInstances ||
 			// e.g. we want all instances to be moved to a schedulable node before triggering the switchover
-			len(podsOnOtherNodes.Items) < cluster.Spec.Instances-1) {
+			len(podsOnOtherNodes.Items) < cluster.GetInstances()-1) {
 		contextLogger.Info("Current primary is running on unschedulable node and something is already in progress",
 			"currentPrimary", primaryPod.Pod.Name,
 			"podsOnOtherNodes", len(podsOnOtherNodes.Items),
-			"instances", cluster.Spec.Instances,
+			"instances", cluster.GetInstances(),
 			"readyInstances", cluster.Status.ReadyInstances,
 			"primaryNode", primaryPod.Node)
 		return "", nil
@@ -224,6 +260,11 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 			continue
 		}
 
+		// Delayed replicas are never promoted automatically
+		if candidate.IsDelayedReplica {
+			continue
+		}
+
 		// If the candidate has not established a connection to the current primary, skip it
 		if !candidate.IsWalReceiverActive {
 			continue
@@ -277,6 +318,14 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForReplicaCluster(
 		}
 	}
 
+	if !status.Items[0].IsElectable() {
+		return "", ErrNoFailoverCandidates
+	}
+
+	if err := enforceFailoverCooldown(ctx, cluster, status); err != nil {
+		return "", err
+	}
+
 	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
 		return "", err
 	}
@@ -296,7 +345,7 @@ func (r *ClusterReconciler) reconcileTargetPrimaryForReplicaCluster(
 	r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 		"Current target primary isn't healthy, failing over from %v to %v",
 		cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
-	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
+	if err := r.registerFailoverStart(ctx, cluster,
 		fmt.Sprintf("Failing over to %v", status.Items[0].Pod.Name)); err != nil {
 		return "", err
 	}
@@ -340,6 +389,54 @@ func (r *ClusterReconciler) enforceFailoverDelay(ctx context.Context, cluster *a
 	return r.evaluateFailoverDelay(ctx, cluster, cluster.Spec.FailoverDelay)
 }
 
+// enforceFailoverCooldown prevents a failover from being initiated during the
+// cooldown period following the last promotion, unless the current primary
+// has hard-failed and this is allowed by the configuration
+func enforceFailoverCooldown(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) error {
+	cooldown := cluster.Spec.FailoverCooldown
+	if cooldown == nil || cooldown.Period == 0 || cluster.Status.CurrentPrimaryTimestamp == "" {
+		return nil
+	}
+
+	sincePromotion, err := utils.DifferenceBetweenTimestamps(
+		utils.GetCurrentTimestamp(),
+		cluster.Status.CurrentPrimaryTimestamp,
+	)
+	if err != nil {
+		return err
+	}
+	if sincePromotion >= time.Duration(cooldown.Period)*time.Second {
+		return nil
+	}
+
+	if cooldown.GetAllowOnHardFailure() && isPrimaryHardFailed(cluster, status) {
+		log.FromContext(ctx).Info("Current primary has hard-failed, ignoring the failover cooldown period",
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"sincePromotion", sincePromotion)
+		return nil
+	}
+
+	return ErrWaitingOnFailoverCooldown
+}
+
+// isPrimaryHardFailed checks whether the Pod of the current primary is not
+// running anymore, or its instance manager is not reachable
+func isPrimaryHardFailed(cluster *apiv1.Cluster, status postgres.PostgresqlStatusList) bool {
+	for _, item := range status.Items {
+		if item.Pod == nil || item.Pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		return !utils.IsPodActive(*item.Pod) || !item.HasHTTPStatus()
+	}
+
+	return true
+}
+
 func (r *ClusterReconciler) evaluateFailoverDelay(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
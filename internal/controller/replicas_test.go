@@ -17,8 +17,13 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -151,3 +156,202 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("failover cooldown", func() {
+	var (
+		cluster *apiv1.Cluster
+		status  postgres.PostgresqlStatusList
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				FailoverCooldown: &apiv1.FailoverCooldownConfiguration{
+					Period: 300,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:          "cluster-example-2",
+				TargetPrimary:           "cluster-example-2",
+				CurrentPrimaryTimestamp: utils.GetCurrentTimestamp(),
+			},
+		}
+		status = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPodReady: true,
+				},
+				{
+					Pod:        &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					IsPrimary:  true,
+					IsPodReady: false,
+				},
+			},
+		}
+	})
+
+	It("doesn't interfere when the cooldown is not configured", func(ctx SpecContext) {
+		cluster.Spec.FailoverCooldown = nil
+		Expect(enforceFailoverCooldown(ctx, cluster, status)).To(Succeed())
+	})
+
+	It("prevents the failover during the cooldown period", func(ctx SpecContext) {
+		Expect(enforceFailoverCooldown(ctx, cluster, status)).To(MatchError(ErrWaitingOnFailoverCooldown))
+	})
+
+	It("allows the failover after the cooldown period", func(ctx SpecContext) {
+		cluster.Status.CurrentPrimaryTimestamp = time.Now().Add(-10 * time.Minute).Format(metav1.RFC3339Micro)
+		Expect(enforceFailoverCooldown(ctx, cluster, status)).To(Succeed())
+	})
+
+	It("allows the failover when the primary hard-fails", func(ctx SpecContext) {
+		status.Items[1].Error = fmt.Errorf("connection refused")
+		Expect(enforceFailoverCooldown(ctx, cluster, status)).To(Succeed())
+
+		status.Items = status.Items[:1]
+		Expect(enforceFailoverCooldown(ctx, cluster, status)).To(Succeed())
+	})
+
+	It("waits for the cooldown period even on hard failures when requested", func(ctx SpecContext) {
+		cluster.Spec.FailoverCooldown.AllowOnHardFailure = ptr.To(false)
+		status.Items = status.Items[:1]
+		Expect(enforceFailoverCooldown(ctx, cluster, status)).To(MatchError(ErrWaitingOnFailoverCooldown))
+	})
+})
+
+var _ = Describe("failover with delayed replicas", func() {
+	var (
+		r       ClusterReconciler
+		cluster *apiv1.Cluster
+		status  postgres.PostgresqlStatusList
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		status = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+					IsDelayedReplica: true,
+				},
+				{
+					Pod:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					Error: fmt.Errorf("connection refused"),
+				},
+			},
+		}
+	})
+
+	It("never promotes a delayed replica", func(ctx SpecContext) {
+		selectedPrimary, err := r.reconcileTargetPrimaryForNonReplicaCluster(ctx, cluster, status, nil)
+		Expect(err).To(MatchError(ErrNoFailoverCandidates))
+		Expect(selectedPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("never promotes a replica fenced in read-only mode", func(ctx SpecContext) {
+		status.Items[0].IsDelayedReplica = false
+		status.Items[0].FencingMode = utils.FencingModeReadOnly
+		selectedPrimary, err := r.reconcileTargetPrimaryForNonReplicaCluster(ctx, cluster, status, nil)
+		Expect(err).To(MatchError(ErrNoFailoverCandidates))
+		Expect(selectedPrimary).To(BeEmpty())
+	})
+
+	It("never promotes a delayed replica in a replica cluster", func(ctx SpecContext) {
+		// the designated primary is not reporting its status
+		status.Items = status.Items[:1]
+		selectedPrimary, err := r.reconcileTargetPrimaryForReplicaCluster(ctx, cluster, status, nil)
+		Expect(err).To(MatchError(ErrNoFailoverCandidates))
+		Expect(selectedPrimary).To(BeEmpty())
+	})
+})
+
+var _ = Describe("failover priority", func() {
+	var (
+		cluster *apiv1.Cluster
+		status  postgres.PostgresqlStatusList
+	)
+
+	replica := func(name string, receivedLSN postgres.LSN) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			ReceivedLsn: receivedLSN,
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				FailoverPriority: &apiv1.FailoverPriorityConfiguration{
+					Instances: []apiv1.InstanceFailoverPriority{
+						{Name: "cluster-example-3", Priority: 10},
+						{Name: "cluster-example-4", Priority: 20},
+					},
+				},
+			},
+		}
+		status = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				replica("cluster-example-2", "0/5000000"),
+				replica("cluster-example-3", "0/4800000"),
+				replica("cluster-example-4", "0/3000000"),
+				{
+					Pod:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					Error: fmt.Errorf("connection refused"),
+				},
+			},
+		}
+	})
+
+	It("promotes the most advanced replica when no priority is configured", func() {
+		cluster.Spec.FailoverPriority = nil
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("prefers the replica with the highest priority within the LSN tolerance", func() {
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("never prefers a replica which is behind by more than the LSN tolerance", func() {
+		tolerance := resource.MustParse("1Mi")
+		cluster.Spec.FailoverPriority.LSNTolerance = &tolerance
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-2"))
+
+		tolerance = resource.MustParse("64Mi")
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-4"))
+	})
+
+	It("keeps the most advanced replica when the priorities are the same", func() {
+		cluster.Spec.FailoverPriority.Instances = []apiv1.InstanceFailoverPriority{
+			{Name: "cluster-example-2", Priority: 10},
+			{Name: "cluster-example-3", Priority: 10},
+		}
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("doesn't change the primary when it is healthy", func() {
+		status.Items[0].IsPrimary = true
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("never prefers a delayed replica", func() {
+		status.Items[1].IsDelayedReplica = true
+		Expect(selectFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("doesn't switch the target while the preferred replica is promoted", func(ctx SpecContext) {
+		var r ClusterReconciler
+		cluster.Status.CurrentPrimary = "cluster-example-1"
+		cluster.Status.TargetPrimary = "cluster-example-3"
+		selectedPrimary, err := r.reconcileTargetPrimaryForNonReplicaCluster(ctx, cluster, status, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectedPrimary).To(BeEmpty())
+	})
+})
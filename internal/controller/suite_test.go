@@ -110,9 +110,10 @@ func buildTestEnvironment() *testingEnvironment {
 	}
 
 	backupReconciler := &BackupReconciler{
-		Client:   k8sClient,
-		Scheme:   scheme,
-		Recorder: record.NewFakeRecorder(120),
+		Client:    k8sClient,
+		APIReader: k8sClient,
+		Scheme:    scheme,
+		Recorder:  record.NewFakeRecorder(120),
 	}
 
 	return &testingEnvironment{
@@ -125,6 +126,37 @@ func buildTestEnvironment() *testingEnvironment {
 	}
 }
 
+// newFakeClusterReconciler creates a ClusterReconciler with a fake
+// client containing the passed objects. The client handles the status
+// subresource of the clusters, the backups and the jobs as the API
+// server does, and ignores the field selectors
+func newFakeClusterReconciler(objects ...client.Object) ClusterReconciler {
+	scheme := schemeBuilder.BuildWithAllKnownScheme()
+	return ClusterReconciler{
+		Scheme: scheme,
+		Client: fakeClientWithIndexAdapter{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&apiv1.Cluster{}, &apiv1.Backup{}, &batchv1.Job{}).
+				Build(),
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+// newFakeBackupReconciler creates a BackupReconciler sharing
+// the fake client of newFakeClusterReconciler
+func newFakeBackupReconciler(objects ...client.Object) *BackupReconciler {
+	clusterReconciler := newFakeClusterReconciler(objects...)
+	return &BackupReconciler{
+		Scheme:    clusterReconciler.Scheme,
+		Client:    clusterReconciler.Client,
+		APIReader: clusterReconciler.Client,
+		Recorder:  clusterReconciler.Recorder,
+	}
+}
+
 func newFakePooler(k8sClient client.Client, cluster *apiv1.Cluster) *apiv1.Pooler {
 	pooler := &apiv1.Pooler{
 		ObjectMeta: metav1.ObjectMeta{
@@ -19,6 +19,7 @@ limitations under the License.
 package cache
 
 import (
+	"strings"
 	"sync"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -35,6 +36,18 @@ const (
 
 var cache sync.Map
 
+// WALArchiveDestinationKey is the key to be used to access the cached envs
+// for wal-archive on the passed additional WAL destination
+func WALArchiveDestinationKey(name string) string {
+	return WALArchiveKey + "-" + name
+}
+
+// IsWALArchiveKey returns true if the passed key is the one of the cached envs
+// for wal-archive, either on the main or on an additional WAL destination
+func IsWALArchiveKey(key string) bool {
+	return key == WALArchiveKey || strings.HasPrefix(key, WALArchiveKey+"-")
+}
+
 // Store write an object into the local cache
 func Store(c string, v interface{}) {
 	cache.Store(c, v)
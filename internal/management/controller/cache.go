@@ -104,5 +104,27 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	}
 
 	cache.Store(cache.WALArchiveKey, envArchive)
+
+	for idx := range cluster.Spec.Backup.AdditionalWalDestinations {
+		destination := &cluster.Spec.Backup.AdditionalWalDestinations[idx]
+		envDestination, err := barmanCredentials.EnvSetBackupCloudCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			&destination.BarmanObjectStoreConfiguration,
+			os.Environ())
+		if apierrors.IsForbidden(err) {
+			log.Info("WAL destination credentials don't yet have access permissions. "+
+				"Will retry reconciliation loop", "destination", destination.Name)
+			return true
+		}
+		if err != nil {
+			log.Error(err, "while getting WAL destination credentials", "destination", destination.Name)
+			continue
+		}
+
+		cache.Store(cache.WALArchiveDestinationKey(destination.Name), envDestination)
+	}
+
 	return false
 }
@@ -0,0 +1,289 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// databaseReconciliationInterval is the time between two reconciliations
+// of a Database that is not ready, or that can't be reconciled by
+// this instance right now
+const databaseReconciliationInterval = 30 * time.Second
+
+// databaseDriftCheckInterval is the time between two reconciliations of
+// a ready Database, correcting the changes made outside of the operator
+const databaseDriftCheckInterval = 5 * time.Minute
+
+// DatabaseReconciler reconciles a Database object
+type DatabaseReconciler struct {
+	client.Client
+	instance *postgres.Instance
+}
+
+// NewDatabaseReconciler creates a new database reconciler
+func NewDatabaseReconciler(instance *postgres.Instance, client client.Client) *DatabaseReconciler {
+	return &DatabaseReconciler{
+		Client:   client,
+		instance: instance,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Database{}).
+		Complete(r)
+}
+
+// Reconcile is the database reconciliation loop
+func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("database_reconciler").
+		WithValues("database", req.NamespacedName)
+
+	var database apiv1.Database
+	if err := r.Client.Get(ctx, req.NamespacedName, &database); err != nil {
+		// The database has been deleted and, if needed, the finalizer
+		// has already been executed
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This database is not for the cluster managed by this instance
+	if database.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := r.getCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted, together with its databases.
+			// We remove the finalizer, as nobody would be left to do it,
+			// and wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return ctrl.Result{}, r.removeFinalizer(ctx, &database)
+		}
+		return ctrl.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	// Databases are managed only by the primary instance of a primary
+	// cluster. We requeue to handle switchovers and promotions.
+	if cluster.IsReplica() ||
+		cluster.Status.CurrentPrimary != r.instance.PodName ||
+		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		contextLogger.Trace("skipping the database reconciler on a non-primary instance")
+		return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+
+	if !database.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDeletion(ctx, &database)
+	}
+
+	if err := r.reconcileFinalizer(ctx, &database); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping database reconciling")
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	extensionsStatus, serversStatus, reconcileErr := r.reconcileDatabase(ctx, &database)
+
+	origDatabase := database.DeepCopy()
+	database.Status.ObservedGeneration = database.Generation
+	database.Status.Ready = reconcileErr == nil
+	database.Status.Error = ""
+	if reconcileErr != nil {
+		database.Status.Error = reconcileErr.Error()
+	}
+	database.Status.Extensions = extensionsStatus
+	database.Status.Servers = serversStatus
+	if err := r.Client.Status().Patch(ctx, &database, client.MergeFrom(origDatabase)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while setting the database status: %w", err)
+	}
+
+	if reconcileErr != nil {
+		contextLogger.Info("Error while reconciling database", "err", reconcileErr)
+		return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+
+	for _, extension := range extensionsStatus {
+		if !extension.Ready {
+			return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, nil
+		}
+	}
+
+	for _, server := range serversStatus {
+		if !server.Ready {
+			return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, nil
+		}
+	}
+
+	// The secrets are not watched, we periodically apply
+	// their content to follow the rotation of the credentials
+	if len(database.GetCredentialsSecretNames()) > 0 {
+		return ctrl.Result{RequeueAfter: databaseReconciliationInterval}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: databaseDriftCheckInterval}, nil
+}
+
+// removeFinalizer removes the finalizer from a database
+// whose cluster doesn't exist anymore
+func (r *DatabaseReconciler) removeFinalizer(ctx context.Context, database *apiv1.Database) error {
+	if !controllerutil.RemoveFinalizer(database, apiv1.DatabaseFinalizerName) {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Client.Update(ctx, database))
+}
+
+// reconcileFinalizer ensures the finalizer is set only when the database
+// needs to be dropped on deletion
+func (r *DatabaseReconciler) reconcileFinalizer(ctx context.Context, database *apiv1.Database) error {
+	var changed bool
+	if database.Spec.ReclaimPolicy == apiv1.DatabaseReclaimDelete {
+		changed = controllerutil.AddFinalizer(database, apiv1.DatabaseFinalizerName)
+	} else {
+		changed = controllerutil.RemoveFinalizer(database, apiv1.DatabaseFinalizerName)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return r.Client.Update(ctx, database)
+}
+
+// reconcileDeletion drops the database, if required by the reclaim
+// policy, and removes the finalizer
+func (r *DatabaseReconciler) reconcileDeletion(ctx context.Context, database *apiv1.Database) error {
+	if !controllerutil.ContainsFinalizer(database, apiv1.DatabaseFinalizerName) {
+		return nil
+	}
+
+	if database.Spec.ReclaimPolicy == apiv1.DatabaseReclaimDelete {
+		db, err := r.instance.GetSuperUserDB()
+		if err != nil {
+			return fmt.Errorf("while getting the superuser connection: %w", err)
+		}
+
+		if err := dropDatabase(ctx, db, database); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(database, apiv1.DatabaseFinalizerName)
+	return r.Client.Update(ctx, database)
+}
+
+// reconcileDatabase applies the specification of the database, and then
+// of its extensions and foreign servers, returning the status of every
+// managed extension and foreign server
+func (r *DatabaseReconciler) reconcileDatabase(
+	ctx context.Context,
+	database *apiv1.Database,
+) ([]apiv1.ExtensionStatus, []apiv1.ServerStatus, error) {
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("while getting the superuser connection: %w", err)
+	}
+
+	if database.GetEnsure() == apiv1.EnsureAbsent {
+		return nil, nil, dropDatabase(ctx, db, database)
+	}
+
+	installed, err := getInstalledDatabase(ctx, db, database)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if installed != nil {
+		err = updateDatabase(ctx, db, database, installed)
+	} else {
+		err = createDatabase(ctx, db, database)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(database.Spec.Extensions) == 0 && len(database.Spec.Servers) == 0 {
+		return nil, nil, nil
+	}
+
+	// Extensions and foreign servers live inside the database, so we need
+	// to connect to it. Pooled connections are not kept idle, so they
+	// won't prevent the database from being dropped later.
+	targetDB, err := r.instance.ConnectionPool().Connection(database.Spec.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("while connecting to database %q: %w", database.Spec.Name, err)
+	}
+
+	var extensionsStatus []apiv1.ExtensionStatus
+	if len(database.Spec.Extensions) > 0 {
+		extensionsStatus = reconcileDatabaseExtensions(ctx, targetDB, database.Spec.Extensions)
+	}
+
+	var serversStatus []apiv1.ServerStatus
+	if len(database.Spec.Servers) > 0 {
+		serversStatus = reconcileDatabaseServers(ctx, targetDB, database.Spec.Servers, r.getCredentials)
+	}
+
+	return extensionsStatus, serversStatus, nil
+}
+
+// getCredentials gets the username and the password stored in a secret
+// in the namespace of the cluster, to be used in a user mapping
+func (r *DatabaseReconciler) getCredentials(ctx context.Context, secretName string) (string, string, error) {
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx,
+		types.NamespacedName{Namespace: r.instance.Namespace, Name: secretName},
+		&secret); err != nil {
+		return "", "", fmt.Errorf("while getting secret %q: %w", secretName, err)
+	}
+
+	return getUserMappingCredentials(&secret)
+}
+
+// getCluster gets the managed cluster through the client
+func (r *DatabaseReconciler) getCluster(ctx context.Context) (*apiv1.Cluster, error) {
+	var cluster apiv1.Cluster
+	err := r.Client.Get(ctx,
+		types.NamespacedName{
+			Namespace: r.instance.Namespace,
+			Name:      r.instance.ClusterName,
+		},
+		&cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cluster, nil
+}
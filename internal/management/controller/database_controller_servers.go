@@ -0,0 +1,347 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// credentialsGetter gets the username and the password
+// stored in the secret with the passed name
+type credentialsGetter func(ctx context.Context, secretName string) (string, string, error)
+
+// getUserMappingCredentials gets the username and the password stored in
+// a secret referred to by a user mapping. The credentials become readable
+// by the mapped role, and whoever can create a Database could otherwise
+// expose any secret of the namespace: the secret needs to be explicitly
+// labeled to be used in a user mapping
+func getUserMappingCredentials(secret *corev1.Secret) (string, string, error) {
+	if secret.Labels[pkgUtils.UserMappingSecretLabelName] != "true" {
+		return "", "", fmt.Errorf(
+			"secret %q can't be used in a user mapping without the %s=true label",
+			secret.Name, pkgUtils.UserMappingSecretLabelName)
+	}
+
+	return utils.GetUserPasswordFromSecret(secret)
+}
+
+// installedServer is a foreign server which is already present in a database
+type installedServer struct {
+	foreignDataWrapper string
+	options            map[string]string
+}
+
+// reconcileDatabaseServers applies the desired state of each foreign server,
+// and of its user mappings, through a connection to the target database,
+// reporting the outcome of every one of them. A failure on a single foreign
+// server doesn't prevent the others from being reconciled.
+func reconcileDatabaseServers(
+	ctx context.Context,
+	db *sql.DB,
+	servers []apiv1.ServerSpec,
+	getCredentials credentialsGetter,
+) []apiv1.ServerStatus {
+	contextLogger := log.FromContext(ctx)
+
+	result := make([]apiv1.ServerStatus, len(servers))
+	for idx := range servers {
+		server := &servers[idx]
+		result[idx].Name = server.Name
+		if err := reconcileDatabaseServer(ctx, db, server, getCredentials); err != nil {
+			contextLogger.Info("Error while reconciling foreign server", "server", server.Name, "err", err)
+			result[idx].Error = err.Error()
+			continue
+		}
+		result[idx].Ready = true
+	}
+
+	return result
+}
+
+// reconcileDatabaseServer applies the desired state of a single
+// foreign server and of its user mappings
+func reconcileDatabaseServer(
+	ctx context.Context,
+	db *sql.DB,
+	server *apiv1.ServerSpec,
+	getCredentials credentialsGetter,
+) error {
+	name := pgx.Identifier{server.Name}.Sanitize()
+
+	if server.GetEnsure() == apiv1.EnsureAbsent {
+		// The user mappings we manage would prevent the foreign server from
+		// being dropped. Every other dependent object needs to be removed
+		// by the user, as we never cascade the drop
+		for idx := range server.UserMappings {
+			if err := dropUserMapping(ctx, db, server.Name, server.UserMappings[idx].User); err != nil {
+				return err
+			}
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP SERVER IF EXISTS %s", name)); err != nil {
+			return fmt.Errorf("while dropping foreign server: %w", err)
+		}
+		return nil
+	}
+
+	installed, err := getInstalledServer(ctx, db, server.Name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case installed == nil:
+		if err := createServer(ctx, db, server); err != nil {
+			return err
+		}
+
+	case installed.foreignDataWrapper != server.ForeignDataWrapper:
+		return fmt.Errorf("foreign server uses the foreign-data wrapper %q instead of %q "+
+			"and needs to be dropped to be recreated", installed.foreignDataWrapper, server.ForeignDataWrapper)
+
+	default:
+		if changes := buildAlterOptions(installed.options, server.Options); len(changes) > 0 {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER SERVER %s OPTIONS (%s)",
+				name, strings.Join(changes, ", "))); err != nil {
+				return fmt.Errorf("while altering foreign server: %w", err)
+			}
+		}
+	}
+
+	for idx := range server.UserMappings {
+		if err := reconcileUserMapping(ctx, db, server.Name, &server.UserMappings[idx], getCredentials); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getInstalledServer gets the foreign-data wrapper and the options of a
+// foreign server, returning nil if the foreign server doesn't exist
+func getInstalledServer(ctx context.Context, db *sql.DB, name string) (*installedServer, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT w.fdwname, coalesce(s.srvoptions, '{}')
+		FROM pg_foreign_server s
+		JOIN pg_foreign_data_wrapper w ON s.srvfdw = w.oid
+		WHERE s.srvname = $1`,
+		name)
+
+	var options pq.StringArray
+	var result installedServer
+	if err := row.Scan(&result.foreignDataWrapper, &options); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while detecting foreign server: %w", err)
+	}
+	result.options = parseOptions(options)
+
+	return &result, nil
+}
+
+// createServer creates a foreign server in the database
+func createServer(ctx context.Context, db *sql.DB, server *apiv1.ServerSpec) error {
+	var sqlCreateServer strings.Builder
+	sqlCreateServer.WriteString(fmt.Sprintf("CREATE SERVER %s FOREIGN DATA WRAPPER %s",
+		pgx.Identifier{server.Name}.Sanitize(), pgx.Identifier{server.ForeignDataWrapper}.Sanitize()))
+	if len(server.Options) > 0 {
+		sqlCreateServer.WriteString(fmt.Sprintf(" OPTIONS (%s)", buildCreateOptions(server.Options)))
+	}
+
+	if _, err := db.ExecContext(ctx, sqlCreateServer.String()); err != nil {
+		return fmt.Errorf("while creating foreign server: %w", err)
+	}
+
+	return nil
+}
+
+// reconcileUserMapping applies the desired state of a user mapping. The
+// credentials are read from the secret every time, and a change of the
+// secret is applied to the user mapping
+func reconcileUserMapping(
+	ctx context.Context,
+	db *sql.DB,
+	serverName string,
+	mapping *apiv1.UserMappingSpec,
+	getCredentials credentialsGetter,
+) error {
+	if mapping.GetEnsure() == apiv1.EnsureAbsent {
+		return dropUserMapping(ctx, db, serverName, mapping.User)
+	}
+
+	options := slices.Clone(mapping.Options)
+	if mapping.CredentialsSecret != nil && mapping.CredentialsSecret.Name != "" {
+		username, password, err := getCredentials(ctx, mapping.CredentialsSecret.Name)
+		if err != nil {
+			return fmt.Errorf("while getting the credentials of the user mapping for %q: %w", mapping.User, err)
+		}
+		options = append(options,
+			apiv1.OptionSpec{Name: "user", Value: username},
+			apiv1.OptionSpec{Name: "password", Value: password})
+	}
+
+	installedOptions, err := getInstalledUserMapping(ctx, db, serverName, mapping.User)
+	if err != nil {
+		return err
+	}
+
+	mappingTarget := fmt.Sprintf("FOR %s SERVER %s",
+		getUserMappingRole(mapping.User), pgx.Identifier{serverName}.Sanitize())
+
+	var query string
+	if installedOptions == nil {
+		query = fmt.Sprintf("CREATE USER MAPPING %s", mappingTarget)
+		if len(options) > 0 {
+			query += fmt.Sprintf(" OPTIONS (%s)", buildCreateOptions(options))
+		}
+	} else if changes := buildAlterOptions(installedOptions, options); len(changes) > 0 {
+		query = fmt.Sprintf("ALTER USER MAPPING %s OPTIONS (%s)", mappingTarget, strings.Join(changes, ", "))
+	}
+
+	if query == "" {
+		return nil
+	}
+
+	// The query may contain the password, which can't be part of the error
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("while reconciling the user mapping for %q: %w", mapping.User, err)
+	}
+
+	return nil
+}
+
+// getInstalledUserMapping gets the options of a user mapping,
+// returning nil if the user mapping doesn't exist
+func getInstalledUserMapping(
+	ctx context.Context,
+	db *sql.DB,
+	serverName string,
+	user string,
+) (map[string]string, error) {
+	userName := user
+	if strings.EqualFold(user, "public") {
+		userName = "public"
+	}
+
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT coalesce(umoptions, '{}')
+		FROM pg_user_mappings
+		WHERE srvname = $1 AND usename = $2`,
+		serverName, userName)
+
+	var options pq.StringArray
+	if err := row.Scan(&options); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while detecting the user mapping for %q: %w", user, err)
+	}
+
+	return parseOptions(options), nil
+}
+
+// dropUserMapping drops a user mapping, if it exists
+func dropUserMapping(ctx context.Context, db *sql.DB, serverName string, user string) error {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP USER MAPPING IF EXISTS FOR %s SERVER %s",
+		getUserMappingRole(user), pgx.Identifier{serverName}.Sanitize())); err != nil {
+		return fmt.Errorf("while dropping the user mapping for %q: %w", user, err)
+	}
+
+	return nil
+}
+
+// getUserMappingRole gets the role of a user mapping as used in
+// the SQL commands, where PUBLIC is a keyword
+func getUserMappingRole(user string) string {
+	if strings.EqualFold(user, "public") {
+		return "PUBLIC"
+	}
+
+	return pgx.Identifier{user}.Sanitize()
+}
+
+// parseOptions parses the options of a foreign object,
+// stored by PostgreSQL as a list of `name=value` strings
+func parseOptions(options []string) map[string]string {
+	result := make(map[string]string, len(options))
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		result[name] = value
+	}
+
+	return result
+}
+
+// buildCreateOptions builds the list of options of a new foreign object
+func buildCreateOptions(options []apiv1.OptionSpec) string {
+	clauses := make([]string, len(options))
+	for idx, option := range options {
+		clauses[idx] = fmt.Sprintf("%s %s", pgx.Identifier{option.Name}.Sanitize(), pq.QuoteLiteral(option.Value))
+	}
+
+	return strings.Join(clauses, ", ")
+}
+
+// buildAlterOptions builds the changes aligning the options of an existing
+// foreign object to the desired ones, dropping the options not requested
+func buildAlterOptions(installed map[string]string, desired []apiv1.OptionSpec) []string {
+	var changes []string
+	desiredNames := make([]string, 0, len(desired))
+	for _, option := range desired {
+		desiredNames = append(desiredNames, option.Name)
+
+		name := pgx.Identifier{option.Name}.Sanitize()
+		currentValue, found := installed[option.Name]
+		switch {
+		case !found:
+			changes = append(changes, fmt.Sprintf("ADD %s %s", name, pq.QuoteLiteral(option.Value)))
+		case currentValue != option.Value:
+			changes = append(changes, fmt.Sprintf("SET %s %s", name, pq.QuoteLiteral(option.Value)))
+		}
+	}
+
+	installedNames := make([]string, 0, len(installed))
+	for name := range installed {
+		installedNames = append(installedNames, name)
+	}
+	slices.Sort(installedNames)
+
+	for _, name := range installedNames {
+		if !slices.Contains(desiredNames, name) {
+			changes = append(changes, fmt.Sprintf("DROP %s", pgx.Identifier{name}.Sanitize()))
+		}
+	}
+
+	return changes
+}
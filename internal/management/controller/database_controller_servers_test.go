@@ -0,0 +1,200 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed foreign servers SQL", func() {
+	const (
+		detectServerQuery = `SELECT w.fdwname, coalesce(s.srvoptions, '{}')
+		FROM pg_foreign_server s
+		JOIN pg_foreign_data_wrapper w ON s.srvfdw = w.oid
+		WHERE s.srvname = $1`
+		detectUserMappingQuery = `SELECT coalesce(umoptions, '{}')
+		FROM pg_user_mappings
+		WHERE srvname = $1 AND usename = $2`
+	)
+
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+		err    error
+	)
+
+	getCredentials := func(_ context.Context, secretName string) (string, string, error) {
+		if secretName != "remote-app" {
+			return "", "", fmt.Errorf("secret %q not found", secretName)
+		}
+		return "remote_user", "secret", nil
+	}
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("creates a missing foreign server with its user mapping", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectServerQuery).WithArgs("remote").
+			WillReturnRows(sqlmock.NewRows([]string{"fdwname", "srvoptions"}))
+		dbMock.ExpectExec(`CREATE SERVER "remote" FOREIGN DATA WRAPPER "postgres_fdw" ` +
+			`OPTIONS ("host" 'cluster-remote-rw', "dbname" 'app')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(detectUserMappingQuery).WithArgs("remote", "app").
+			WillReturnRows(sqlmock.NewRows([]string{"umoptions"}))
+		dbMock.ExpectExec(`CREATE USER MAPPING FOR "app" SERVER "remote" ` +
+			`OPTIONS ("user" 'remote_user', "password" 'secret')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status := reconcileDatabaseServers(ctx, db, []apiv1.ServerSpec{
+			{
+				Name:               "remote",
+				ForeignDataWrapper: "postgres_fdw",
+				Options: []apiv1.OptionSpec{
+					{Name: "host", Value: "cluster-remote-rw"},
+					{Name: "dbname", Value: "app"},
+				},
+				UserMappings: []apiv1.UserMappingSpec{
+					{User: "app", CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-app"}},
+				},
+			},
+		}, getCredentials)
+		Expect(status).To(ConsistOf(apiv1.ServerStatus{Name: "remote", Ready: true}))
+	})
+
+	It("aligns the options of an existing foreign server and user mapping", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectServerQuery).WithArgs("remote").
+			WillReturnRows(sqlmock.NewRows([]string{"fdwname", "srvoptions"}).
+				AddRow("postgres_fdw", "{host=old-host,dbname=app,port=5432}"))
+		dbMock.ExpectExec(`ALTER SERVER "remote" OPTIONS (SET "host" 'cluster-remote-rw', DROP "port")`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(detectUserMappingQuery).WithArgs("remote", "public").
+			WillReturnRows(sqlmock.NewRows([]string{"umoptions"}).AddRow("{user=remote_user,password=old}"))
+		dbMock.ExpectExec(`ALTER USER MAPPING FOR PUBLIC SERVER "remote" OPTIONS (SET "password" 'secret')`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status := reconcileDatabaseServers(ctx, db, []apiv1.ServerSpec{
+			{
+				Name:               "remote",
+				ForeignDataWrapper: "postgres_fdw",
+				Options: []apiv1.OptionSpec{
+					{Name: "host", Value: "cluster-remote-rw"},
+					{Name: "dbname", Value: "app"},
+				},
+				UserMappings: []apiv1.UserMappingSpec{
+					{User: "PUBLIC", CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-app"}},
+				},
+			},
+		}, getCredentials)
+		Expect(status).To(ConsistOf(apiv1.ServerStatus{Name: "remote", Ready: true}))
+	})
+
+	It("refuses to change the foreign-data wrapper of a foreign server", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectServerQuery).WithArgs("remote").
+			WillReturnRows(sqlmock.NewRows([]string{"fdwname", "srvoptions"}).AddRow("file_fdw", "{}"))
+
+		status := reconcileDatabaseServers(ctx, db, []apiv1.ServerSpec{
+			{Name: "remote", ForeignDataWrapper: "postgres_fdw"},
+		}, getCredentials)
+		Expect(status).To(HaveLen(1))
+		Expect(status[0].Ready).To(BeFalse())
+		Expect(status[0].Error).To(ContainSubstring("file_fdw"))
+	})
+
+	It("reports a user mapping whose secret is missing", func(ctx SpecContext) {
+		dbMock.ExpectQuery(detectServerQuery).WithArgs("remote").
+			WillReturnRows(sqlmock.NewRows([]string{"fdwname", "srvoptions"}).AddRow("postgres_fdw", "{}"))
+
+		status := reconcileDatabaseServers(ctx, db, []apiv1.ServerSpec{
+			{
+				Name:               "remote",
+				ForeignDataWrapper: "postgres_fdw",
+				UserMappings: []apiv1.UserMappingSpec{
+					{User: "app", CredentialsSecret: &apiv1.LocalObjectReference{Name: "missing"}},
+				},
+			},
+		}, getCredentials)
+		Expect(status).To(HaveLen(1))
+		Expect(status[0].Ready).To(BeFalse())
+		Expect(status[0].Error).To(ContainSubstring("missing"))
+	})
+
+	It("drops a foreign server that should be absent, without cascading", func(ctx SpecContext) {
+		dbMock.ExpectExec(`DROP USER MAPPING IF EXISTS FOR "app" SERVER "remote"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(`DROP SERVER IF EXISTS "remote"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		status := reconcileDatabaseServers(ctx, db, []apiv1.ServerSpec{
+			{
+				Name:               "remote",
+				Ensure:             apiv1.EnsureAbsent,
+				ForeignDataWrapper: "postgres_fdw",
+				UserMappings:       []apiv1.UserMappingSpec{{User: "app"}},
+			},
+		}, getCredentials)
+		Expect(status).To(ConsistOf(apiv1.ServerStatus{Name: "remote", Ready: true}))
+	})
+})
+
+var _ = Describe("getUserMappingCredentials", func() {
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote-app", Namespace: "default"},
+			Data: map[string][]byte{
+				"username": []byte("remote_user"),
+				"password": []byte("secret"),
+			},
+		}
+	})
+
+	It("refuses the secrets without the user mapping label", func() {
+		_, _, err := getUserMappingCredentials(secret)
+		Expect(err).To(MatchError(ContainSubstring(utils.UserMappingSecretLabelName)))
+
+		secret.Labels = map[string]string{utils.UserMappingSecretLabelName: "false"}
+		_, _, err = getUserMappingCredentials(secret)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("gets the credentials of the labeled secrets", func() {
+		secret.Labels = map[string]string{utils.UserMappingSecretLabelName: "true"}
+		username, password, err := getUserMappingCredentials(secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(username).To(Equal("remote_user"))
+		Expect(password).To(Equal("secret"))
+	})
+})
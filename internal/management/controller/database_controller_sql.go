@@ -0,0 +1,306 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// installedDatabase contains the mutable properties of
+// a database which is already present in PostgreSQL
+type installedDatabase struct {
+	allowConnections bool
+	connectionLimit  int
+	isTemplate       bool
+	owner            string
+	tablespace       string
+}
+
+// getInstalledDatabase gets the mutable properties of the database,
+// returning nil if the database doesn't exist
+func getInstalledDatabase(ctx context.Context, db *sql.DB, obj *apiv1.Database) (*installedDatabase, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT d.datallowconn, d.datconnlimit, d.datistemplate, pg_get_userbyid(d.datdba), t.spcname
+		FROM pg_database d
+		JOIN pg_tablespace t ON d.dattablespace = t.oid
+		WHERE d.datname = $1`,
+		obj.Spec.Name)
+
+	var result installedDatabase
+	if err := row.Scan(
+		&result.allowConnections,
+		&result.connectionLimit,
+		&result.isTemplate,
+		&result.owner,
+		&result.tablespace,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while detecting database %q: %w", obj.Spec.Name, err)
+	}
+
+	return &result, nil
+}
+
+// createDatabase creates the database as required by the specification
+func createDatabase(ctx context.Context, db *sql.DB, obj *apiv1.Database) error {
+	var sqlCreateDatabase strings.Builder
+	sqlCreateDatabase.WriteString(fmt.Sprintf("CREATE DATABASE %s", pgx.Identifier{obj.Spec.Name}.Sanitize()))
+	if len(obj.Spec.Owner) > 0 {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" OWNER %s", pgx.Identifier{obj.Spec.Owner}.Sanitize()))
+	}
+	if len(obj.Spec.Template) > 0 {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" TEMPLATE %s", pgx.Identifier{obj.Spec.Template}.Sanitize()))
+	}
+	if len(obj.Spec.Tablespace) > 0 {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" TABLESPACE %s", pgx.Identifier{obj.Spec.Tablespace}.Sanitize()))
+	}
+	if obj.Spec.AllowConnections != nil {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" ALLOW_CONNECTIONS %v", *obj.Spec.AllowConnections))
+	}
+	if obj.Spec.ConnectionLimit != nil {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" CONNECTION LIMIT %v", *obj.Spec.ConnectionLimit))
+	}
+	if obj.Spec.IsTemplate != nil {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" IS_TEMPLATE %v", *obj.Spec.IsTemplate))
+	}
+	if len(obj.Spec.Encoding) > 0 {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" ENCODING %s", pgx.Identifier{obj.Spec.Encoding}.Sanitize()))
+	}
+	if len(obj.Spec.Locale) > 0 {
+		sqlCreateDatabase.WriteString(fmt.Sprintf(" LOCALE %s", pgx.Identifier{obj.Spec.Locale}.Sanitize()))
+	}
+
+	_, err := db.ExecContext(ctx, sqlCreateDatabase.String())
+	if err != nil {
+		return fmt.Errorf("while creating database %q: %w", obj.Spec.Name, err)
+	}
+
+	return nil
+}
+
+// updateDatabase aligns the mutable properties of an existing database
+// to the specification, altering only the ones which differ
+func updateDatabase(ctx context.Context, db *sql.DB, obj *apiv1.Database, installed *installedDatabase) error {
+	name := pgx.Identifier{obj.Spec.Name}.Sanitize()
+
+	var changes []string
+	if obj.Spec.AllowConnections != nil && *obj.Spec.AllowConnections != installed.allowConnections {
+		changes = append(changes,
+			fmt.Sprintf("ALTER DATABASE %s WITH ALLOW_CONNECTIONS %v", name, *obj.Spec.AllowConnections))
+	}
+	if obj.Spec.ConnectionLimit != nil && *obj.Spec.ConnectionLimit != installed.connectionLimit {
+		changes = append(changes,
+			fmt.Sprintf("ALTER DATABASE %s WITH CONNECTION LIMIT %v", name, *obj.Spec.ConnectionLimit))
+	}
+	if obj.Spec.IsTemplate != nil && *obj.Spec.IsTemplate != installed.isTemplate {
+		changes = append(changes,
+			fmt.Sprintf("ALTER DATABASE %s WITH IS_TEMPLATE %v", name, *obj.Spec.IsTemplate))
+	}
+	if len(obj.Spec.Owner) > 0 && obj.Spec.Owner != installed.owner {
+		changes = append(changes,
+			fmt.Sprintf("ALTER DATABASE %s OWNER TO %s", name, pgx.Identifier{obj.Spec.Owner}.Sanitize()))
+	}
+	if len(obj.Spec.Tablespace) > 0 && obj.Spec.Tablespace != installed.tablespace {
+		changes = append(changes,
+			fmt.Sprintf("ALTER DATABASE %s SET TABLESPACE %s", name, pgx.Identifier{obj.Spec.Tablespace}.Sanitize()))
+	}
+
+	for _, change := range changes {
+		if _, err := db.ExecContext(ctx, change); err != nil {
+			return fmt.Errorf("while altering database %q: %w", obj.Spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dropDatabase drops the database, if it exists
+func dropDatabase(ctx context.Context, db *sql.DB, obj *apiv1.Database) error {
+	_, err := db.ExecContext(
+		ctx,
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", pgx.Identifier{obj.Spec.Name}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("while dropping database %q: %w", obj.Spec.Name, err)
+	}
+
+	return nil
+}
+
+// installedExtension is an extension which is already present in a database
+type installedExtension struct {
+	version string
+	schema  string
+}
+
+// reconcileDatabaseExtensions applies the desired state of each extension
+// through a connection to the target database, reporting the outcome of
+// every one of them. A failure on a single extension doesn't prevent the
+// others from being reconciled.
+func reconcileDatabaseExtensions(
+	ctx context.Context,
+	db *sql.DB,
+	extensions []apiv1.ExtensionSpec,
+) []apiv1.ExtensionStatus {
+	contextLogger := log.FromContext(ctx)
+
+	result := make([]apiv1.ExtensionStatus, len(extensions))
+	for idx := range extensions {
+		extension := &extensions[idx]
+		result[idx].Name = extension.Name
+		if err := reconcileDatabaseExtension(ctx, db, extension); err != nil {
+			contextLogger.Info("Error while reconciling extension", "extension", extension.Name, "err", err)
+			result[idx].Error = err.Error()
+			continue
+		}
+		result[idx].Ready = true
+	}
+
+	return result
+}
+
+// reconcileDatabaseExtension applies the desired state of a single extension
+func reconcileDatabaseExtension(ctx context.Context, db *sql.DB, extension *apiv1.ExtensionSpec) error {
+	name := pgx.Identifier{extension.Name}.Sanitize()
+
+	if extension.GetEnsure() == apiv1.EnsureAbsent {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP EXTENSION IF EXISTS %s", name)); err != nil {
+			return fmt.Errorf("while dropping extension: %w", err)
+		}
+		return nil
+	}
+
+	installed, err := getInstalledExtension(ctx, db, extension.Name)
+	if err != nil {
+		return err
+	}
+
+	if installed == nil {
+		if err := checkExtensionSharedPreloadLibraries(ctx, db, extension.Name); err != nil {
+			return err
+		}
+		return createExtension(ctx, db, extension)
+	}
+
+	if len(extension.Version) > 0 && extension.Version != installed.version {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s",
+			name, pgx.Identifier{extension.Version}.Sanitize())); err != nil {
+			return fmt.Errorf("while updating extension: %w", err)
+		}
+	}
+
+	if len(extension.Schema) > 0 && extension.Schema != installed.schema {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s",
+			name, pgx.Identifier{extension.Schema}.Sanitize())); err != nil {
+			return fmt.Errorf("while changing the extension schema: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getInstalledExtension gets the version and the schema of an extension,
+// returning nil if the extension is not installed
+func getInstalledExtension(ctx context.Context, db *sql.DB, name string) (*installedExtension, error) {
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT e.extversion, n.nspname
+		FROM pg_extension e
+		JOIN pg_namespace n ON e.extnamespace = n.oid
+		WHERE e.extname = $1`,
+		name)
+
+	var result installedExtension
+	if err := row.Scan(&result.version, &result.schema); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("while detecting extension: %w", err)
+	}
+
+	return &result, nil
+}
+
+// createExtension installs an extension in the database
+func createExtension(ctx context.Context, db *sql.DB, extension *apiv1.ExtensionSpec) error {
+	var sqlCreateExtension strings.Builder
+	sqlCreateExtension.WriteString(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s",
+		pgx.Identifier{extension.Name}.Sanitize()))
+	if len(extension.Schema) > 0 {
+		sqlCreateExtension.WriteString(fmt.Sprintf(" SCHEMA %s", pgx.Identifier{extension.Schema}.Sanitize()))
+	}
+	if len(extension.Version) > 0 {
+		sqlCreateExtension.WriteString(fmt.Sprintf(" VERSION %s", pgx.Identifier{extension.Version}.Sanitize()))
+	}
+
+	if _, err := db.ExecContext(ctx, sqlCreateExtension.String()); err != nil {
+		return fmt.Errorf("while creating extension: %w", err)
+	}
+
+	return nil
+}
+
+// checkExtensionSharedPreloadLibraries verifies that the libraries required
+// by the extensions managed by the operator are loaded by PostgreSQL, as
+// installing them otherwise would produce a non-working extension
+func checkExtensionSharedPreloadLibraries(ctx context.Context, db *sql.DB, name string) error {
+	var requiredLibraries []string
+	for _, managedExtension := range postgres.ManagedExtensions {
+		if managedExtension.Name == name {
+			requiredLibraries = managedExtension.SharedPreloadLibraries
+			break
+		}
+	}
+
+	if len(requiredLibraries) == 0 {
+		return nil
+	}
+
+	var sharedPreloadLibraries string
+	row := db.QueryRowContext(ctx, "SELECT current_setting('shared_preload_libraries')")
+	if err := row.Scan(&sharedPreloadLibraries); err != nil {
+		return fmt.Errorf("while reading shared_preload_libraries: %w", err)
+	}
+
+	loadedLibraries := strings.Split(sharedPreloadLibraries, ",")
+	for idx := range loadedLibraries {
+		loadedLibraries[idx] = strings.TrimSpace(loadedLibraries[idx])
+	}
+
+	for _, library := range requiredLibraries {
+		if !slices.Contains(loadedLibraries, library) {
+			return fmt.Errorf(
+				"extension requires %q in shared_preload_libraries: "+
+					"set the corresponding parameters in the cluster to have it loaded", library)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed Database SQL", func() {
+	var (
+		dbMock   sqlmock.Sqlmock
+		db       *sql.DB
+		database *apiv1.Database
+		err      error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		database = &apiv1.Database{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-one",
+				Namespace: "default",
+			},
+			Spec: apiv1.DatabaseSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-example"},
+				Name:       "db-one",
+				Owner:      "app",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("getInstalledDatabase", func() {
+		const detectDatabaseQuery = `SELECT d.datallowconn, d.datconnlimit, d.datistemplate,
+			pg_get_userbyid(d.datdba), t.spcname
+			FROM pg_database d
+			JOIN pg_tablespace t ON d.dattablespace = t.oid
+			WHERE d.datname = $1`
+
+		It("returns the properties of an existing Database", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectDatabaseQuery).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows(
+					[]string{"datallowconn", "datconnlimit", "datistemplate", "owner", "spcname"}).
+					AddRow(true, -1, false, "app", "pg_default"))
+
+			installed, err := getInstalledDatabase(ctx, db, database)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(installed).To(Equal(&installedDatabase{
+				allowConnections: true,
+				connectionLimit:  -1,
+				owner:            "app",
+				tablespace:       "pg_default",
+			}))
+		})
+
+		It("returns nil when a Database is missing", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectDatabaseQuery).WithArgs(database.Spec.Name).
+				WillReturnRows(sqlmock.NewRows(
+					[]string{"datallowconn", "datconnlimit", "datistemplate", "owner", "spcname"}))
+
+			installed, err := getInstalledDatabase(ctx, db, database)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(installed).To(BeNil())
+		})
+	})
+
+	Context("createDatabase", func() {
+		It("should create a new Database", func(ctx SpecContext) {
+			database.Spec.IsTemplate = ptr.To(true)
+			database.Spec.Tablespace = "myTablespace"
+			database.Spec.AllowConnections = ptr.To(true)
+			database.Spec.ConnectionLimit = ptr.To(-1)
+
+			expectedQuery := fmt.Sprintf(
+				"CREATE DATABASE %s OWNER %s TABLESPACE %s "+
+					"ALLOW_CONNECTIONS %t CONNECTION LIMIT %d IS_TEMPLATE %t",
+				`"db-one"`, `"app"`, `"myTablespace"`,
+				*database.Spec.AllowConnections, *database.Spec.ConnectionLimit, *database.Spec.IsTemplate,
+			)
+			dbMock.ExpectExec(expectedQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(createDatabase(ctx, db, database)).To(Succeed())
+		})
+
+		It("should create a new Database with template, encoding and locale", func(ctx SpecContext) {
+			database.Spec.Template = "myTemplate"
+			database.Spec.Encoding = "myEncoding"
+			database.Spec.Locale = "myLocale"
+
+			expectedQuery := `CREATE DATABASE "db-one" OWNER "app" TEMPLATE "myTemplate" ` +
+				`ENCODING "myEncoding" LOCALE "myLocale"`
+			dbMock.ExpectExec(expectedQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(createDatabase(ctx, db, database)).To(Succeed())
+		})
+	})
+
+	Context("updateDatabase", func() {
+		It("should alter the mutable properties of the Database", func(ctx SpecContext) {
+			database.Spec.ConnectionLimit = ptr.To(10)
+			database.Spec.Tablespace = "myTablespace"
+
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" WITH CONNECTION LIMIT 10`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" OWNER TO "app"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" SET TABLESPACE "myTablespace"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(updateDatabase(ctx, db, database, &installedDatabase{
+				connectionLimit: -1,
+				owner:           "postgres",
+				tablespace:      "pg_default",
+			})).To(Succeed())
+		})
+
+		It("should not alter the properties which are already aligned", func(ctx SpecContext) {
+			database.Spec.AllowConnections = ptr.To(true)
+			database.Spec.ConnectionLimit = ptr.To(10)
+			database.Spec.Tablespace = "myTablespace"
+
+			Expect(updateDatabase(ctx, db, database, &installedDatabase{
+				allowConnections: true,
+				connectionLimit:  10,
+				owner:            "app",
+				tablespace:       "myTablespace",
+			})).To(Succeed())
+		})
+
+		It("should stop at the first error", func(ctx SpecContext) {
+			database.Spec.AllowConnections = ptr.To(false)
+
+			dbMock.ExpectExec(`ALTER DATABASE "db-one" WITH ALLOW_CONNECTIONS false`).
+				WillReturnError(fmt.Errorf("boom"))
+
+			Expect(updateDatabase(ctx, db, database, &installedDatabase{allowConnections: true})).ToNot(Succeed())
+		})
+	})
+
+	Context("dropDatabase", func() {
+		It("should drop an existing Database", func(ctx SpecContext) {
+			dbMock.ExpectExec(`DROP DATABASE IF EXISTS "db-one"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(dropDatabase(ctx, db, database)).To(Succeed())
+		})
+	})
+
+	Context("reconcileDatabaseExtensions", func() {
+		const detectExtensionQuery = `SELECT e.extversion, n.nspname
+			FROM pg_extension e
+			JOIN pg_namespace n ON e.extnamespace = n.oid
+			WHERE e.extname = $1`
+
+		It("creates a missing extension", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectExtensionQuery).WithArgs("hstore").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+			dbMock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS "hstore" SCHEMA "ext" VERSION "1.8"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			status := reconcileDatabaseExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "hstore", Schema: "ext", Version: "1.8"},
+			})
+			Expect(status).To(ConsistOf(apiv1.ExtensionStatus{Name: "hstore", Ready: true}))
+		})
+
+		It("updates an extension to the requested version", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectExtensionQuery).WithArgs("hstore").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}).AddRow("1.7", "public"))
+			dbMock.ExpectExec(`ALTER EXTENSION "hstore" UPDATE TO "1.8"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			status := reconcileDatabaseExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "hstore", Version: "1.8"},
+			})
+			Expect(status).To(ConsistOf(apiv1.ExtensionStatus{Name: "hstore", Ready: true}))
+		})
+
+		It("drops an extension that should be absent", func(ctx SpecContext) {
+			dbMock.ExpectExec(`DROP EXTENSION IF EXISTS "hstore"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			status := reconcileDatabaseExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "hstore", Ensure: apiv1.EnsureAbsent},
+			})
+			Expect(status).To(ConsistOf(apiv1.ExtensionStatus{Name: "hstore", Ready: true}))
+		})
+
+		It("refuses to create an extension whose library is not preloaded", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectExtensionQuery).WithArgs("pg_stat_statements").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+			dbMock.ExpectQuery(`SELECT current_setting('shared_preload_libraries')`).
+				WillReturnRows(sqlmock.NewRows([]string{""}).AddRow("pgaudit"))
+			dbMock.ExpectQuery(detectExtensionQuery).WithArgs("hstore").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}).AddRow("1.8", "public"))
+
+			status := reconcileDatabaseExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "pg_stat_statements"},
+				{Name: "hstore"},
+			})
+			Expect(status).To(HaveLen(2))
+			Expect(status[0].Ready).To(BeFalse())
+			Expect(status[0].Error).To(ContainSubstring("shared_preload_libraries"))
+			Expect(status[1].Ready).To(BeTrue())
+		})
+
+		It("creates an extension whose library is preloaded", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectExtensionQuery).WithArgs("pg_stat_statements").
+				WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+			dbMock.ExpectQuery(`SELECT current_setting('shared_preload_libraries')`).
+				WillReturnRows(sqlmock.NewRows([]string{""}).AddRow("pgaudit, pg_stat_statements"))
+			dbMock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS "pg_stat_statements"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			status := reconcileDatabaseExtensions(ctx, db, []apiv1.ExtensionSpec{
+				{Name: "pg_stat_statements"},
+			})
+			Expect(status).To(ConsistOf(apiv1.ExtensionStatus{Name: "pg_stat_statements", Ready: true}))
+		})
+	})
+})
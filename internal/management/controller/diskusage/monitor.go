@@ -0,0 +1,180 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	pgpostgres "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// sampleInterval is how often the Monitor samples
+// the usage of the volumes
+const sampleInterval = 30 * time.Second
+
+// usageSample is the space used on a volume,
+// as measured at a certain time
+type usageSample struct {
+	usedBytes uint64
+	time      time.Time
+}
+
+// volume is a volume of the instance, mounted in path
+type volume struct {
+	name string
+	path string
+}
+
+// A Monitor is a Kubernetes manager.Runnable that periodically samples the
+// usage of the volumes of the instance, and computes the rate at which the
+// used space grows over the projection window configured in the cluster.
+// The instance reports them in its status and metrics, so that volumes
+// can be enlarged before they are full.
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Monitor struct {
+	instance *postgres.Instance
+
+	// The samples of each volume within the projection window,
+	// from the oldest to the newest
+	samples map[string][]usageSample
+}
+
+// NewMonitor creates a new disk usage Monitor
+func NewMonitor(instance *postgres.Instance) *Monitor {
+	return &Monitor{
+		instance: instance,
+	}
+}
+
+// Start starts running the disk usage Monitor
+func (m *Monitor) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("disk_usage_monitor")
+	ticker := time.NewTicker(sampleInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated disk usage monitor loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := m.reconcile(); err != nil {
+			contextLog.Error(err, "while sampling the disk usage")
+		}
+	}
+}
+
+func (m *Monitor) reconcile() error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	window := cluster.Spec.Monitoring.GetDiskUsageProjectionWindow()
+	volumes := getVolumes(cluster, m.instance.PgData)
+
+	samples := make(map[string][]usageSample, len(volumes))
+	usage := make([]pgpostgres.VolumeUsage, 0, len(volumes))
+	for _, volume := range volumes {
+		total, used, available, err := compatibility.GetFilesystemUsage(volume.path)
+		if err != nil {
+			return fmt.Errorf("while getting the usage of the %s volume: %w", volume.name, err)
+		}
+
+		volumeSamples := addSample(m.samples[volume.name], usageSample{usedBytes: used, time: now}, window)
+		samples[volume.name] = volumeSamples
+		usage = append(usage, pgpostgres.VolumeUsage{
+			Volume:         volume.name,
+			TotalBytes:     total,
+			UsedBytes:      used,
+			AvailableBytes: available,
+			GrowthRate:     getGrowthRate(volumeSamples),
+		})
+	}
+
+	m.samples = samples
+	m.instance.SetDiskUsage(usage)
+	return nil
+}
+
+// getVolumes gets the volumes of the instance: the one containing
+// PGDATA, and the WAL and tablespace ones when present
+func getVolumes(cluster *apiv1.Cluster, pgData string) []volume {
+	volumes := []volume{{name: "data", path: pgData}}
+	if cluster.ShouldCreateWalArchiveVolume() {
+		volumes = append(volumes, volume{name: "wal", path: specs.PgWalVolumePath})
+	}
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		volumes = append(volumes, volume{
+			name: "tbs-" + tablespace.Name,
+			path: specs.MountForTablespace(tablespace.Name),
+		})
+	}
+
+	return volumes
+}
+
+// addSample adds a sample to the ones of a volume, removing
+// the samples older than the projection window
+func addSample(samples []usageSample, sample usageSample, window time.Duration) []usageSample {
+	result := make([]usageSample, 0, len(samples)+1)
+	for _, previous := range samples {
+		if sample.time.Sub(previous.time) <= window {
+			result = append(result, previous)
+		}
+	}
+
+	return append(result, sample)
+}
+
+// getGrowthRate gets the number of bytes the used space grew every
+// second between the oldest and the newest sample, which is negative
+// when the used space is shrinking. It is zero until there are at
+// least two samples
+func getGrowthRate(samples []usageSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	oldest := samples[0]
+	newest := samples[len(samples)-1]
+	elapsed := newest.time.Sub(oldest.time).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return (float64(newest.usedBytes) - float64(oldest.usedBytes)) / elapsed
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskusage
+
+import (
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("disk usage sampling", func() {
+	now := time.Now()
+
+	Context("addSample", func() {
+		It("removes the samples older than the projection window", func() {
+			samples := []usageSample{
+				{usedBytes: 100, time: now.Add(-2 * time.Hour)},
+				{usedBytes: 200, time: now.Add(-30 * time.Minute)},
+			}
+			Expect(addSample(samples, usageSample{usedBytes: 300, time: now}, time.Hour)).To(Equal([]usageSample{
+				{usedBytes: 200, time: now.Add(-30 * time.Minute)},
+				{usedBytes: 300, time: now},
+			}))
+		})
+	})
+
+	Context("getGrowthRate", func() {
+		It("needs at least two samples", func() {
+			Expect(getGrowthRate(nil)).To(BeZero())
+			Expect(getGrowthRate([]usageSample{{usedBytes: 100, time: now}})).To(BeZero())
+		})
+
+		It("computes the number of bytes the used space grew every second", func() {
+			Expect(getGrowthRate([]usageSample{
+				{usedBytes: 1000, time: now.Add(-time.Minute)},
+				{usedBytes: 1500, time: now.Add(-30 * time.Second)},
+				{usedBytes: 7000, time: now},
+			})).To(BeNumerically("==", 100))
+		})
+
+		It("is negative when the used space is shrinking", func() {
+			Expect(getGrowthRate([]usageSample{
+				{usedBytes: 7000, time: now.Add(-time.Minute)},
+				{usedBytes: 1000, time: now},
+			})).To(BeNumerically("==", -100))
+		})
+	})
+
+	Context("getVolumes", func() {
+		It("samples the data volume of a cluster without other volumes", func() {
+			Expect(getVolumes(&apiv1.Cluster{}, "/pgdata")).To(Equal([]volume{
+				{name: "data", path: "/pgdata"},
+			}))
+		})
+
+		It("samples the WAL and the tablespace volumes", func() {
+			cluster := &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					WalStorage: &apiv1.StorageConfiguration{},
+					Tablespaces: []apiv1.TablespaceConfiguration{
+						{Name: "atablespace"},
+					},
+				},
+			}
+			Expect(getVolumes(cluster, "/pgdata")).To(Equal([]volume{
+				{name: "data", path: "/pgdata"},
+				{name: "wal", path: "/var/lib/postgresql/wal"},
+				{name: "tbs-atablespace", path: "/var/lib/postgresql/tablespaces/atablespace"},
+			}))
+		})
+	})
+})
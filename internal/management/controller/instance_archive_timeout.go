@@ -0,0 +1,155 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+const (
+	// walRateSampleInterval is how often the WAL generation
+	// rate is measured on the primary instance
+	walRateSampleInterval = time.Minute
+
+	// minWALRateChangeRatio is the minimum relative change of the WAL
+	// generation rate that is applied, to avoid reloading the configuration
+	// for small fluctuations
+	minWALRateChangeRatio = 0.2
+)
+
+// walSample is the amount of WAL generated by the
+// instance, as measured at a certain time
+type walSample struct {
+	walBytes int64
+	time     time.Time
+}
+
+// reconcileWALGenerationRate measures the WAL generation rate of the
+// primary instance, from which archive_timeout is computed when its
+// automatic tuning is enabled. The configuration files are then
+// refreshed by the reconciliation loop itself
+func (r *InstanceReconciler) reconcileWALGenerationRate(ctx context.Context, cluster *apiv1.Cluster) {
+	contextLogger := log.FromContext(ctx)
+
+	if !r.shouldMeasureWALGenerationRate(cluster) {
+		r.lastWALSample = nil
+		r.instance.SetWALGenerationRate(nil)
+		return
+	}
+	if isPrimary, err := r.instance.IsPrimary(); err != nil || !isPrimary {
+		r.lastWALSample = nil
+		r.instance.SetWALGenerationRate(nil)
+		return
+	}
+
+	if r.lastWALSample != nil && time.Since(r.lastWALSample.time) < walRateSampleInterval {
+		return
+	}
+
+	sample, segmentSize, err := r.getWALSample(ctx)
+	if err != nil {
+		contextLogger.Debug("Cannot measure the WAL generation rate", "err", err)
+		return
+	}
+
+	previousSample := r.lastWALSample
+	r.lastWALSample = sample
+	bytesPerSecond, ok := getWALRate(previousSample, sample)
+	if !ok {
+		return
+	}
+
+	rate := &postgres.WALGenerationRate{BytesPerSecond: bytesPerSecond, SegmentSize: segmentSize}
+	if !shouldUpdateWALGenerationRate(r.instance.GetWALGenerationRate(), rate) {
+		return
+	}
+
+	contextLogger.Info("WAL generation rate changed, archive_timeout will be tuned",
+		"bytesPerSecond", int64(bytesPerSecond))
+	r.instance.SetWALGenerationRate(rate)
+}
+
+// shouldMeasureWALGenerationRate checks if the WAL generation rate
+// is needed, which happens on the primary instance when the
+// automatic tuning of archive_timeout is enabled
+func (r *InstanceReconciler) shouldMeasureWALGenerationRate(cluster *apiv1.Cluster) bool {
+	return cluster.Spec.PostgresConfiguration.AdaptiveArchiveTimeout.IsEnabled() &&
+		cluster.Status.CurrentPrimary == r.instance.PodName &&
+		!r.instance.IsFenced()
+}
+
+// getWALSample gets the amount of WAL generated by the instance, together
+// with the size of the WAL segments. We use pg_stat_wal as, unlike the
+// current WAL location, it doesn't account for the unused space in the
+// segments switched because of archive_timeout.
+func (r *InstanceReconciler) getWALSample(ctx context.Context) (*walSample, int64, error) {
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sample walSample
+	var segmentSize int64
+	row := db.QueryRowContext(ctx,
+		"SELECT wal_bytes::bigint, "+
+			"(SELECT setting::bigint FROM pg_catalog.pg_settings WHERE name = 'wal_segment_size') "+
+			"FROM pg_catalog.pg_stat_wal")
+	if err := row.Scan(&sample.walBytes, &segmentSize); err != nil {
+		return nil, 0, fmt.Errorf("while measuring the WAL generation rate: %w", err)
+	}
+	sample.time = time.Now()
+
+	return &sample, segmentSize, nil
+}
+
+// getWALRate gets the number of bytes of WAL generated every second
+// between two samples, returning false if it cannot be computed
+// because the statistics have been reset in the meantime
+func getWALRate(previous, current *walSample) (float64, bool) {
+	if previous == nil {
+		return 0, false
+	}
+
+	elapsed := current.time.Sub(previous.time).Seconds()
+	generated := current.walBytes - previous.walBytes
+	if elapsed <= 0 || generated < 0 {
+		return 0, false
+	}
+
+	return float64(generated) / elapsed, true
+}
+
+// shouldUpdateWALGenerationRate checks if the measured WAL generation
+// rate is different enough from the current one to be applied
+func shouldUpdateWALGenerationRate(current, measured *postgres.WALGenerationRate) bool {
+	if current == nil || current.SegmentSize != measured.SegmentSize {
+		return true
+	}
+	if current.BytesPerSecond == measured.BytesPerSecond {
+		return false
+	}
+
+	return math.Abs(measured.BytesPerSecond-current.BytesPerSecond) >=
+		current.BytesPerSecond*minWALRateChangeRatio
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL generation rate", func() {
+	const segmentSize = 16 * 1024 * 1024
+
+	Context("getWALRate", func() {
+		now := time.Now()
+
+		It("needs a previous sample", func() {
+			_, ok := getWALRate(nil, &walSample{walBytes: 1000, time: now})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("computes the number of bytes generated every second", func() {
+			rate, ok := getWALRate(
+				&walSample{walBytes: 1000, time: now},
+				&walSample{walBytes: 7000, time: now.Add(time.Minute)})
+			Expect(ok).To(BeTrue())
+			Expect(rate).To(BeNumerically("==", 100))
+		})
+
+		It("ignores the statistics being reset", func() {
+			_, ok := getWALRate(
+				&walSample{walBytes: 7000, time: now},
+				&walSample{walBytes: 1000, time: now.Add(time.Minute)})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("shouldUpdateWALGenerationRate", func() {
+		current := &postgres.WALGenerationRate{BytesPerSecond: 1000, SegmentSize: segmentSize}
+
+		It("applies the first measure", func() {
+			Expect(shouldUpdateWALGenerationRate(nil, current)).To(BeTrue())
+		})
+
+		It("ignores small changes", func() {
+			Expect(shouldUpdateWALGenerationRate(current,
+				&postgres.WALGenerationRate{BytesPerSecond: 1000, SegmentSize: segmentSize})).To(BeFalse())
+			Expect(shouldUpdateWALGenerationRate(current,
+				&postgres.WALGenerationRate{BytesPerSecond: 1100, SegmentSize: segmentSize})).To(BeFalse())
+		})
+
+		It("applies big changes", func() {
+			Expect(shouldUpdateWALGenerationRate(current,
+				&postgres.WALGenerationRate{BytesPerSecond: 1500, SegmentSize: segmentSize})).To(BeTrue())
+			Expect(shouldUpdateWALGenerationRate(current,
+				&postgres.WALGenerationRate{BytesPerSecond: 0, SegmentSize: segmentSize})).To(BeTrue())
+		})
+
+		It("applies a change of the WAL segment size", func() {
+			Expect(shouldUpdateWALGenerationRate(current,
+				&postgres.WALGenerationRate{BytesPerSecond: 1000, SegmentSize: 2 * segmentSize})).To(BeTrue())
+		})
+
+		It("leaves an idle cluster alone", func() {
+			idle := &postgres.WALGenerationRate{SegmentSize: segmentSize}
+			Expect(shouldUpdateWALGenerationRate(idle, idle)).To(BeFalse())
+		})
+	})
+})
@@ -153,6 +153,14 @@ func (r *InstanceReconciler) Reconcile(
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadNeeded := r.RefreshSecrets(ctx, cluster)
 
+	// archive_timeout is computed from the WAL generation rate
+	// while generating the configuration files
+	r.reconcileWALGenerationRate(ctx, cluster)
+
+	// The write transactions are made read-only while generating
+	// the configuration files when the WAL archive backlog is too big
+	r.reconcileWALArchiveBacklog(ctx, cluster)
+
 	reloadConfigNeeded, err := r.refreshConfigurationFiles(ctx, cluster)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -239,6 +247,14 @@ func (r *InstanceReconciler) Reconcile(
 	// From now on, the database can be assumed as running. Every operation
 	// needing the database to be up should be put below this line.
 
+	if err := r.reconcilePreviousMajorVersionData(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot remove the data of the previous major version: %w", err)
+	}
+
+	if err := r.reconcileWALArchiveThrottling(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot throttle the WAL archive backlog: %w", err)
+	}
+
 	r.configureSlotReplicator(cluster)
 
 	if result, err := reconciler.ReconcileReplicationSlots(
@@ -257,6 +273,14 @@ func (r *InstanceReconciler) Reconcile(
 		}
 	}
 
+	if err := r.reconcileTimeoutExemptions(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the exemptions from the default timeouts: %w", err)
+	}
+
+	if err := r.reconcileLogicalReplicationSlots(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the logical replication slots: %w", err)
+	}
+
 	if err = r.refreshCredentialsFromSecret(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
@@ -284,6 +308,16 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// The WAL archive backlog needs to be checked periodically
+	if r.shouldCheckWALArchiveBacklog(cluster) {
+		return reconcile.Result{RequeueAfter: walArchiveBacklogCheckInterval}, nil
+	}
+
+	// The WAL generation rate needs to be measured periodically
+	if r.shouldMeasureWALGenerationRate(cluster) {
+		return reconcile.Result{RequeueAfter: walRateSampleInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -335,7 +369,9 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
-	reloadIdent, err := r.instance.RefreshPGIdent(cluster.Spec.PostgresConfiguration.PgIdent)
+	reloadIdent, err := r.instance.RefreshPGIdent(
+		cluster.Spec.PostgresConfiguration.PgIdent,
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.GetIdentMappings())
 	if err != nil {
 		return false, err
 	}
@@ -358,7 +394,18 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 }
 
 func (r *InstanceReconciler) reconcileFencing(ctx context.Context, cluster *apiv1.Cluster) *reconcile.Result {
-	fencingRequired := cluster.IsInstanceFenced(r.instance.PodName)
+	contextLogger := log.FromContext(ctx)
+
+	// In read-only mode PostgreSQL keeps running, with the read-only
+	// transactions already set in the configuration files
+	fencingMode := cluster.GetInstanceFencingMode(r.instance.PodName)
+	readOnlyFencingRequired := fencingMode == pkgUtils.FencingModeReadOnly
+	if readOnlyFencingRequired != r.instance.IsReadOnlyFenced() {
+		contextLogger.Info("Read-only fencing status changed", "readOnlyFenced", readOnlyFencingRequired)
+		r.instance.SetReadOnlyFencing(readOnlyFencingRequired)
+	}
+
+	fencingRequired := fencingMode == pkgUtils.FencingModeStop
 	isFenced := r.instance.IsFenced()
 	switch {
 	case !isFenced && fencingRequired:
@@ -403,7 +450,9 @@ func (r *InstanceReconciler) initialize(ctx context.Context, cluster *apiv1.Clus
 		return err
 	}
 
-	r.instance.SetFencing(cluster.IsInstanceFenced(r.instance.PodName))
+	fencingMode := cluster.GetInstanceFencingMode(r.instance.PodName)
+	r.instance.SetFencing(fencingMode == pkgUtils.FencingModeStop)
+	r.instance.SetReadOnlyFencing(fencingMode == pkgUtils.FencingModeReadOnly)
 
 	return nil
 }
@@ -483,12 +532,15 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		return false, err
 	}
 
-	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
-
 	db, err := r.instance.GetSuperUserDB()
 	if err != nil {
 		contextLogger.Error(err, "Cannot connect to primary server")
 	} else {
+		if cluster.IsDrainingPrimaryConnections() {
+			drainPrimaryConnections(ctx, r.client, db, cluster, r.instance.PodName)
+		}
+
+		contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
 		_, err = db.Exec("CHECKPOINT")
 		if err != nil {
 			contextLogger.Error(err, "Error while requesting a checkpoint")
@@ -542,9 +594,11 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 		return fmt.Errorf("getting the superuserdb: %w", err)
 	}
 
+	declaredExtensions := cluster.Spec.PostgresConfiguration.GetDeclaredExtensions()
+	managedExtensions := cluster.Spec.PostgresConfiguration.GetManagedExtensions()
 	extensionStatusChanged := false
-	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+	for _, extension := range managedExtensions {
+		extensionIsUsed := extension.IsEnabled(declaredExtensions, cluster.Spec.PostgresConfiguration.Parameters)
 		if lastStatus, ok := r.extensionStatus[extension.Name]; !ok || lastStatus != extensionIsUsed {
 			extensionStatusChanged = true
 			break
@@ -560,7 +614,9 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 			continue
 		}
 		if extensionStatusChanged {
-			if err = r.reconcileExtensions(ctx, db, cluster.Spec.PostgresConfiguration.Parameters); err != nil {
+			if err = r.reconcileExtensions(
+				ctx, db, managedExtensions, declaredExtensions, cluster.Spec.PostgresConfiguration.Parameters,
+			); err != nil {
 				errors = append(errors,
 					fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
 			}
@@ -574,8 +630,8 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 		return fmt.Errorf("got errors while reconciling databases: %v", errors)
 	}
 
-	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+	for _, extension := range managedExtensions {
+		extensionIsUsed := extension.IsEnabled(declaredExtensions, cluster.Spec.PostgresConfiguration.Parameters)
 		r.extensionStatus[extension.Name] = extensionIsUsed
 	}
 
@@ -608,7 +664,11 @@ func (r *InstanceReconciler) getAllAccessibleDatabases(
 // ReconcileExtensions reconciles the expected extensions for this
 // PostgreSQL instance
 func (r *InstanceReconciler) reconcileExtensions(
-	ctx context.Context, db *sql.DB, userSettings map[string]string,
+	ctx context.Context,
+	db *sql.DB,
+	managedExtensions []postgres.ManagedExtension,
+	declaredExtensions []string,
+	userSettings map[string]string,
 ) (err error) {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -619,8 +679,8 @@ func (r *InstanceReconciler) reconcileExtensions(
 		_ = tx.Rollback()
 	}()
 
-	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(userSettings)
+	for _, extension := range managedExtensions {
+		extensionIsUsed := extension.IsEnabled(declaredExtensions, userSettings)
 
 		row := tx.QueryRow("SELECT COUNT(*) > 0 FROM pg_extension WHERE extname = $1", extension.Name)
 		err = row.Err()
@@ -773,6 +833,12 @@ func (r *InstanceReconciler) reconcileMetrics(
 	syncReplicas, _ := cluster.GetSyncReplicasData()
 	exporter.Metrics.SyncReplicas.WithLabelValues("expected").Set(float64(syncReplicas))
 
+	if cluster.IsSyncReplicationRelaxed() {
+		exporter.Metrics.SyncReplicationRelaxed.Set(1)
+	} else {
+		exporter.Metrics.SyncReplicationRelaxed.Set(0)
+	}
+
 	if cluster.IsReplica() {
 		exporter.Metrics.ReplicaCluster.Set(1)
 	} else {
@@ -951,9 +1017,21 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
 	r.instance.SmartStopDelay = cluster.GetSmartShutdownTimeout()
+	r.instance.SSLMinProtocolVersion = cluster.GetClientSSLMinProtocolVersion()
 	r.instance.RequiresDesignatedPrimaryTransition = detectRequiresDesignatedPrimaryTransition()
 }
 
+// reconcilePreviousMajorVersionData removes the data of the previous major
+// version kept after an in-place major upgrade, now that PostgreSQL is
+// running on the upgraded data directory
+func (r *InstanceReconciler) reconcilePreviousMajorVersionData(ctx context.Context) error {
+	removed, err := postgresManagement.RemovePreviousMajorVersionData(r.instance.PgData)
+	if removed {
+		log.FromContext(ctx).Info("Removed the data directory of the previous major version")
+	}
+	return err
+}
+
 // reconcileAutoConf reconciles the permission of `postgresql.auto.conf`
 // given the relative setting in `.spec.postgresql.enableAlterSystem`
 func (r *InstanceReconciler) reconcileAutoConf(ctx context.Context, cluster *apiv1.Cluster) {
@@ -1007,6 +1085,13 @@ func (r *InstanceReconciler) processConfigReloadAndManageRestart(ctx context.Con
 	phase := apiv1.PhaseApplyingConfiguration
 	phaseReason := "PostgreSQL configuration changed"
 	if status.IsPrimary && status.PendingRestartForDecrease {
+		if cluster.GetParameterChangeRestartStrategy() != apiv1.ParameterChangeRestartStrategyImmediate {
+			// The operator will restart the primary instance when allowed
+			// by the restart strategy
+			contextLogger.Info("Deferring the restart of the primary instance due to hot standby " +
+				"sensible parameters decrease, according to the restart strategy")
+			return nil
+		}
 		if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategyUnsupervised {
 			contextLogger.Info("Restarting primary in-place due to hot standby sensible parameters decrease")
 			restartTimeout := time.Duration(cluster.GetRestartTimeout()) * time.Second
@@ -1021,7 +1106,7 @@ func (r *InstanceReconciler) processConfigReloadAndManageRestart(ctx context.Con
 	}
 	if phase == apiv1.PhaseApplyingConfiguration &&
 		(cluster.Status.Phase == apiv1.PhaseApplyingConfiguration ||
-			(status.IsPrimary && cluster.Spec.Instances > 1)) {
+			(status.IsPrimary && cluster.GetInstances() > 1)) {
 		// I'm not the first instance spotting the configuration
 		// change, everything is fine and there is no need to signal
 		// the operator again.
@@ -1129,6 +1214,40 @@ func (r *InstanceReconciler) refreshCAFromSecret(
 	return changed, nil
 }
 
+// refreshCABundleFromSecrets receive a list of secrets and rewrite the provided
+// location with the concatenation of their ca.crt entries. This allows PostgreSQL
+// to trust more than one CA at the same time, like while rotating them
+func (r *InstanceReconciler) refreshCABundleFromSecrets(
+	ctx context.Context,
+	secrets []corev1.Secret,
+	destLocation string,
+) (bool, error) {
+	var bundle []byte
+	for idx := range secrets {
+		caCertificate, ok := secrets[idx].Data[certs.CACertKey]
+		if !ok {
+			return false, fmt.Errorf("missing %s entry in Secret %s", certs.CACertKey, secrets[idx].Name)
+		}
+		bundle = append(bundle, caCertificate...)
+		if len(caCertificate) > 0 && caCertificate[len(caCertificate)-1] != '\n' {
+			bundle = append(bundle, '\n')
+		}
+	}
+
+	changed, err := fileutils.WriteFileAtomic(destLocation, bundle, 0o600)
+	if err != nil {
+		return false, fmt.Errorf("while writing CA bundle: %w", err)
+	}
+
+	if changed {
+		log.FromContext(ctx).Info("Refreshed configuration file",
+			"filename", destLocation,
+			"secrets", len(secrets))
+	}
+
+	return changed, nil
+}
+
 // refreshFileFromSecret receive a secret and rewrite the file corresponding to the key to the provided location
 func (r *InstanceReconciler) refreshFileFromSecret(
 	ctx context.Context,
@@ -1402,7 +1521,7 @@ func (r *InstanceReconciler) shouldRequeueForMissingTopology(cluster *apiv1.Clus
 	}
 
 	topologyStatus := cluster.Status.Topology
-	if !topologyStatus.SuccessfullyExtracted || len(topologyStatus.Instances) != cluster.Spec.Instances {
+	if !topologyStatus.SuccessfullyExtracted || len(topologyStatus.Instances) != cluster.GetInstances() {
 		log.Info("missing topology information while syncReplicaElectionConstraint are enabled, " +
 			"will requeue to calculate correctly the synchronous names")
 		return true
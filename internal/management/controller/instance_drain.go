@@ -0,0 +1,155 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// activeTransactionsQuery counts the transactions still running on the
+// instance, excluding the one used to run this query
+const activeTransactionsQuery = `SELECT count(*)
+	FROM pg_catalog.pg_stat_activity
+	WHERE backend_type = 'client backend'
+	AND xact_start IS NOT NULL
+	AND pid <> pg_catalog.pg_backend_pid()`
+
+// drainPollInterval is the interval between two checks of the transactions
+// still running on the former primary
+var drainPollInterval = time.Second
+
+// drainPrimaryConnections waits, for at most the switchover drain timeout,
+// for this instance to be removed from the -rw service and then for the
+// running transactions to complete. The transactions are not waited for
+// when the instance is still receiving new connections, as the drain would
+// be pointless.
+func drainPrimaryConnections(
+	ctx context.Context,
+	cli client.Client,
+	db *sql.DB,
+	cluster *apiv1.Cluster,
+	podName string,
+) {
+	contextLogger := log.FromContext(ctx)
+
+	timeout := time.Duration(cluster.Spec.SwitchoverDrainTimeout) * time.Second
+	start := time.Now()
+
+	if err := waitForReadWriteServiceRemoval(ctx, cli, cluster, podName, timeout); err != nil {
+		contextLogger.Warning("The instance is still part of the -rw service, skipping the drain",
+			"err", err)
+		return
+	}
+
+	waitForTransactionsToComplete(ctx, db, timeout-time.Since(start))
+}
+
+// waitForReadWriteServiceRemoval waits, for at most the passed timeout, for
+// the endpoints of the -rw service to stop including the passed Pod
+func waitForReadWriteServiceRemoval(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	podName string,
+	timeout time.Duration,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	drainCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	contextLogger.Info("Waiting for the instance to be removed from the -rw service")
+	for {
+		var endpoints corev1.Endpoints
+		err := cli.Get(drainCtx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      cluster.GetServiceReadWriteName(),
+		}, &endpoints)
+		if err != nil {
+			return fmt.Errorf("while getting the -rw service endpoints: %w", err)
+		}
+
+		if !endpointsIncludePod(&endpoints, podName) {
+			return nil
+		}
+
+		select {
+		case <-drainCtx.Done():
+			return fmt.Errorf("timeout expired while waiting for the removal from the -rw service")
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// endpointsIncludePod checks whether the passed Pod is one of the
+// addresses, ready or not, of the passed endpoints
+func endpointsIncludePod(endpoints *corev1.Endpoints, podName string) bool {
+	for _, subset := range endpoints.Subsets {
+		for _, addresses := range [][]corev1.EndpointAddress{subset.Addresses, subset.NotReadyAddresses} {
+			for _, address := range addresses {
+				if address.TargetRef != nil && address.TargetRef.Name == podName {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// waitForTransactionsToComplete waits, for at most the passed timeout, for
+// the transactions running on the instance to complete. It never fails: when
+// the timeout expires, or the running transactions can't be detected, the
+// shutdown of the instance will take care to terminate them.
+func waitForTransactionsToComplete(ctx context.Context, db *sql.DB, timeout time.Duration) {
+	contextLogger := log.FromContext(ctx).WithValues("timeout", timeout)
+
+	drainCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	contextLogger.Info("Waiting for the running transactions to complete before the shutdown")
+	for {
+		var activeTransactions int
+		if err := db.QueryRowContext(drainCtx, activeTransactionsQuery).Scan(&activeTransactions); err != nil {
+			contextLogger.Warning("Error while detecting the running transactions, skipping the drain",
+				"err", err)
+			return
+		}
+
+		if activeTransactions == 0 {
+			contextLogger.Info("No transaction is running, proceeding with the shutdown")
+			return
+		}
+
+		select {
+		case <-drainCtx.Done():
+			contextLogger.Info("Timeout expired while waiting for the running transactions to complete",
+				"activeTransactions", activeTransactions)
+			return
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
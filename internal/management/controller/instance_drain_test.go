@@ -0,0 +1,147 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("waitForTransactionsToComplete", func() {
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		originalPollInterval := drainPollInterval
+		drainPollInterval = 10 * time.Millisecond
+		DeferCleanup(func() {
+			drainPollInterval = originalPollInterval
+		})
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("waits until the running transactions are completed", func(ctx SpecContext) {
+		dbMock.ExpectQuery(activeTransactionsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		dbMock.ExpectQuery(activeTransactionsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		dbMock.ExpectQuery(activeTransactionsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		waitForTransactionsToComplete(ctx, db, time.Minute)
+	})
+
+	It("stops waiting when the timeout expires", func(ctx SpecContext) {
+		drainPollInterval = time.Hour
+		dbMock.ExpectQuery(activeTransactionsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		start := time.Now()
+		waitForTransactionsToComplete(ctx, db, 50*time.Millisecond)
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("doesn't wait when the running transactions can't be detected", func(ctx SpecContext) {
+		dbMock.ExpectQuery(activeTransactionsQuery).WillReturnError(fmt.Errorf("connection refused"))
+
+		waitForTransactionsToComplete(ctx, db, time.Minute)
+	})
+})
+
+var _ = Describe("waitForReadWriteServiceRemoval", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+	}
+
+	endpointsWith := func(podNames ...string) *corev1.Endpoints {
+		addresses := make([]corev1.EndpointAddress, 0, len(podNames))
+		for _, podName := range podNames {
+			addresses = append(addresses, corev1.EndpointAddress{
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: podName},
+			})
+		}
+
+		return &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.GetServiceReadWriteName(),
+				Namespace: cluster.Namespace,
+			},
+			Subsets: []corev1.EndpointSubset{{Addresses: addresses}},
+		}
+	}
+
+	BeforeEach(func() {
+		originalPollInterval := drainPollInterval
+		drainPollInterval = 10 * time.Millisecond
+		DeferCleanup(func() {
+			drainPollInterval = originalPollInterval
+		})
+	})
+
+	It("succeeds when the instance is not part of the -rw service", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(endpointsWith("cluster-example-2")).
+			Build()
+
+		Expect(waitForReadWriteServiceRemoval(ctx, cli, cluster, "cluster-example-1", time.Minute)).
+			To(Succeed())
+	})
+
+	It("fails when the instance is still part of the -rw service at the timeout", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			WithObjects(endpointsWith("cluster-example-1")).
+			Build()
+
+		Expect(waitForReadWriteServiceRemoval(ctx, cli, cluster, "cluster-example-1", 50*time.Millisecond)).
+			ToNot(Succeed())
+	})
+
+	It("fails when the -rw service endpoints can't be read", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().
+			WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+			Build()
+
+		Expect(waitForReadWriteServiceRemoval(ctx, cli, cluster, "cluster-example-1", time.Minute)).
+			ToNot(Succeed())
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// reconcileLogicalReplicationSlots creates the managed logical replication
+// slots in the primary instance, and drops the ones requested to be absent.
+// The slots that have been removed from the spec are left untouched.
+func (r *InstanceReconciler) reconcileLogicalReplicationSlots(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.IsReplica() || r.instance.PodName != cluster.Status.CurrentPrimary {
+		return nil
+	}
+
+	slots := cluster.Spec.ReplicationSlots.GetLogicalSlots()
+	if len(slots) == 0 {
+		return nil
+	}
+
+	ver, err := r.instance.GetPgVersion()
+	if err != nil {
+		return fmt.Errorf("getting the PostgreSQL version: %w", err)
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	var enableFailover enableSlotFailoverFunc
+	if ver.Major >= 17 {
+		enableFailover = r.enableSlotFailover
+	}
+
+	return reconcileLogicalReplicationSlots(
+		ctx,
+		db,
+		r.instance.ConnectionPool().Connection,
+		slots,
+		enableFailover,
+	)
+}
+
+// enableSlotFailover sets the failover flag of an existing logical
+// replication slot. This is only possible with the replication protocol,
+// connecting to the database of the slot
+func (r *InstanceReconciler) enableSlotFailover(
+	ctx context.Context,
+	slot apiv1.LogicalReplicationSlotConfiguration,
+) error {
+	conn, err := pgconn.Connect(ctx, r.instance.ConnectionPool().GetDsn(slot.Database)+" replication=database")
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", slot.Database, err)
+	}
+	defer func() {
+		_ = conn.Close(ctx)
+	}()
+
+	query := fmt.Sprintf("ALTER_REPLICATION_SLOT %s ( FAILOVER true )", pgx.Identifier{slot.Name}.Sanitize())
+	_, err = conn.Exec(ctx, query).ReadAll()
+	return err
+}
+
+// enableSlotFailoverFunc sets the failover flag of
+// an existing logical replication slot
+type enableSlotFailoverFunc func(ctx context.Context, slot apiv1.LogicalReplicationSlotConfiguration) error
+
+// reconcileLogicalReplicationSlots makes sure that the passed logical
+// replication slots exist, or don't exist, as requested. Logical replication
+// slots can only be created while connected to their database, which is
+// why we need a way to get a connection to it. The failover flag of the
+// slots, available from PostgreSQL 17, makes the standbys synchronize them:
+// it is set on creation and, for the slots created before, with the passed
+// function, which is nil when the flag is not supported.
+func reconcileLogicalReplicationSlots(
+	ctx context.Context,
+	db *sql.DB,
+	getConnection func(dbname string) (*sql.DB, error),
+	slots []apiv1.LogicalReplicationSlotConfiguration,
+	enableFailover enableSlotFailoverFunc,
+) error {
+	contextLogger := log.FromContext(ctx)
+	withFailover := enableFailover != nil
+
+	existingSlots, err := getLogicalReplicationSlots(ctx, db, withFailover)
+	if err != nil {
+		return err
+	}
+
+	for _, slot := range slots {
+		existingSlot, exists := existingSlots[slot.Name]
+
+		switch slot.GetEnsure() {
+		case apiv1.EnsurePresent:
+			if exists && (!withFailover || existingSlot.failover) {
+				continue
+			}
+
+			if exists {
+				// A slot can only be altered while nobody is using it
+				if existingSlot.active {
+					contextLogger.Info("Logical replication slot in use, "+
+						"postponing the synchronization on the standbys",
+						"slot", slot.Name)
+					continue
+				}
+
+				contextLogger.Info("Enabling the synchronization of the logical replication slot on the standbys",
+					"slot", slot.Name)
+				if err := enableFailover(ctx, slot); err != nil {
+					return fmt.Errorf("while enabling the failover of logical replication slot %s: %w",
+						slot.Name, err)
+				}
+				continue
+			}
+
+			contextLogger.Info("Creating logical replication slot",
+				"slot", slot.Name, "database", slot.Database, "plugin", slot.GetPlugin())
+			targetDB, err := getConnection(slot.Database)
+			if err != nil {
+				return fmt.Errorf("while connecting to database %s: %w", slot.Database, err)
+			}
+
+			query := "SELECT pg_catalog.pg_create_logical_replication_slot($1, $2)"
+			if withFailover {
+				query = "SELECT pg_catalog.pg_create_logical_replication_slot($1, $2, false, false, true)"
+			}
+			if _, err := targetDB.ExecContext(ctx, query, slot.Name, slot.GetPlugin()); err != nil {
+				return fmt.Errorf("while creating logical replication slot %s: %w", slot.Name, err)
+			}
+
+		case apiv1.EnsureAbsent:
+			if !exists {
+				continue
+			}
+
+			if existingSlot.active {
+				contextLogger.Info("Logical replication slot still in use, postponing its removal",
+					"slot", slot.Name)
+				continue
+			}
+
+			contextLogger.Info("Dropping logical replication slot", "slot", slot.Name)
+			if _, err := db.ExecContext(
+				ctx,
+				"SELECT pg_catalog.pg_drop_replication_slot($1)",
+				slot.Name,
+			); err != nil {
+				return fmt.Errorf("while dropping logical replication slot %s: %w", slot.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// logicalSlotState is the state of an existing logical replication slot
+type logicalSlotState struct {
+	// Whether the slot is in use
+	active bool
+
+	// Whether the slot is synchronized on the standbys
+	failover bool
+}
+
+// getLogicalReplicationSlots gets the logical replication slots existing in
+// the instance, together with their state. The failover flag is only read
+// when supported
+func getLogicalReplicationSlots(
+	ctx context.Context,
+	db *sql.DB,
+	withFailover bool,
+) (map[string]logicalSlotState, error) {
+	failoverColumn := "false"
+	if withFailover {
+		failoverColumn = "failover"
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		fmt.Sprintf(`SELECT slot_name, active, %s FROM pg_catalog.pg_replication_slots
+		WHERE slot_type = 'logical' AND NOT temporary`, failoverColumn))
+	if err != nil {
+		return nil, fmt.Errorf("while getting the list of logical replication slots: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]logicalSlotState)
+	for rows.Next() {
+		var name string
+		var state logicalSlotState
+		if err := rows.Scan(&name, &state.active, &state.failover); err != nil {
+			return nil, err
+		}
+		result[name] = state
+	}
+
+	return result, rows.Err()
+}
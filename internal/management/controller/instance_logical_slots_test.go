@@ -0,0 +1,136 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managed logical replication slots", func() {
+	const (
+		slotsQuery = `SELECT slot_name, active, false FROM pg_catalog.pg_replication_slots
+		WHERE slot_type = 'logical' AND NOT temporary`
+		slotsWithFailoverQuery = `SELECT slot_name, active, failover FROM pg_catalog.pg_replication_slots
+		WHERE slot_type = 'logical' AND NOT temporary`
+	)
+
+	var (
+		dbMock       sqlmock.Sqlmock
+		db           *sql.DB
+		targetDBMock sqlmock.Sqlmock
+		targetDB     *sql.DB
+		connectedTo  []string
+		altered      []string
+	)
+
+	getConnection := func(dbname string) (*sql.DB, error) {
+		connectedTo = append(connectedTo, dbname)
+		return targetDB, nil
+	}
+
+	enableFailover := func(_ context.Context, slot apiv1.LogicalReplicationSlotConfiguration) error {
+		altered = append(altered, slot.Name)
+		return nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		targetDB, targetDBMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		connectedTo = nil
+		altered = nil
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+		Expect(targetDBMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	slots := []apiv1.LogicalReplicationSlotConfiguration{
+		{Name: "cdc", Database: "app"},
+		{Name: "debezium", Database: "app", Plugin: "wal2json"},
+		{Name: "legacy", Database: "app", Ensure: apiv1.EnsureAbsent},
+	}
+
+	It("creates the missing slots in their database", func(ctx SpecContext) {
+		dbMock.ExpectQuery(slotsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"slot_name", "active", "failover"}).AddRow("cdc", true, false))
+		targetDBMock.ExpectExec("SELECT pg_catalog.pg_create_logical_replication_slot($1, $2)").
+			WithArgs("debezium", "wal2json").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(reconcileLogicalReplicationSlots(ctx, db, getConnection, slots, nil)).To(Succeed())
+		Expect(connectedTo).To(ConsistOf("app"))
+	})
+
+	It("enables the synchronization of the slots from PostgreSQL 17", func(ctx SpecContext) {
+		dbMock.ExpectQuery(slotsWithFailoverQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"slot_name", "active", "failover"}).AddRow("debezium", false, true))
+		targetDBMock.ExpectExec("SELECT pg_catalog.pg_create_logical_replication_slot($1, $2, false, false, true)").
+			WithArgs("cdc", "pgoutput").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(reconcileLogicalReplicationSlots(ctx, db, getConnection, slots, enableFailover)).To(Succeed())
+		Expect(altered).To(BeEmpty())
+	})
+
+	It("enables the synchronization of the existing slots when not in use", func(ctx SpecContext) {
+		dbMock.ExpectQuery(slotsWithFailoverQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"slot_name", "active", "failover"}).
+				AddRow("cdc", false, false).
+				AddRow("debezium", true, false))
+
+		Expect(reconcileLogicalReplicationSlots(ctx, db, getConnection, slots, enableFailover)).To(Succeed())
+		Expect(altered).To(ConsistOf("cdc"))
+		Expect(connectedTo).To(BeEmpty())
+	})
+
+	It("drops the slots requested to be absent when they are not in use", func(ctx SpecContext) {
+		dbMock.ExpectQuery(slotsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"slot_name", "active", "failover"}).
+				AddRow("cdc", true, false).
+				AddRow("debezium", true, false).
+				AddRow("legacy", false, false).
+				AddRow("unmanaged", false, false))
+		dbMock.ExpectExec("SELECT pg_catalog.pg_drop_replication_slot($1)").
+			WithArgs("legacy").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(reconcileLogicalReplicationSlots(ctx, db, getConnection, slots, nil)).To(Succeed())
+		Expect(connectedTo).To(BeEmpty())
+	})
+
+	It("postpones the removal of the slots in use", func(ctx SpecContext) {
+		dbMock.ExpectQuery(slotsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"slot_name", "active", "failover"}).
+				AddRow("cdc", true, false).
+				AddRow("debezium", true, false).
+				AddRow("legacy", true, false))
+
+		Expect(reconcileLogicalReplicationSlots(ctx, db, getConnection, slots, nil)).To(Succeed())
+	})
+})
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -31,6 +32,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -99,7 +101,8 @@ func (r *InstanceReconciler) refreshReplicationUserCertificate(
 		postgresSpec.StreamingReplicaKeyLocation)
 }
 
-// refreshClientCA gets the latest client CA certificates from the secrets.
+// refreshClientCA gets the latest client CA certificates from the secrets,
+// together with the additional ones trusted to sign the client certificates.
 // It returns true if configuration has been changed
 func (r *InstanceReconciler) refreshClientCA(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
 	var secret corev1.Secret
@@ -111,7 +114,26 @@ func (r *InstanceReconciler) refreshClientCA(ctx context.Context, cluster *apiv1
 		return false, err
 	}
 
-	return r.refreshCAFromSecret(ctx, &secret, postgresSpec.ClientCACertificateLocation)
+	additionalSecretNames := cluster.Spec.PostgresConfiguration.CertificateAuthentication.
+		GetAdditionalClientCASecretNames()
+	if len(additionalSecretNames) == 0 {
+		return r.refreshCAFromSecret(ctx, &secret, postgresSpec.ClientCACertificateLocation)
+	}
+
+	secrets := []corev1.Secret{secret}
+	for _, secretName := range additionalSecretNames {
+		var additionalSecret corev1.Secret
+		if err := r.GetClient().Get(
+			ctx,
+			client.ObjectKey{Namespace: r.instance.Namespace, Name: secretName},
+			&additionalSecret,
+		); err != nil {
+			return false, fmt.Errorf("while getting the additional client CA secret %s: %w", secretName, err)
+		}
+		secrets = append(secrets, additionalSecret)
+	}
+
+	return r.refreshCABundleFromSecrets(ctx, secrets, postgresSpec.ClientCACertificateLocation)
 }
 
 // refreshServerCA gets the latest server CA certificates from the secrets.
@@ -256,31 +278,10 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(ctx context.Context
 				err, "Error while changing mode of the postgresql.auto.conf file before pg_rewind, skipped")
 		}
 
-		// pg_rewind could require a clean shutdown of the old primary to
-		// work. Unfortunately, if the old primary is already clean starting
-		// it up may make it advance in respect to the new one.
-		// The only way to check if we really need to start it up before
-		// invoking pg_rewind is to try using pg_rewind and, on failures,
-		// retrying after having started up the instance.
-		err = r.instance.Rewind(ctx, pgMajorVersion)
+		err = r.rewindFormerPrimary(ctx, pgMajorVersion)
+		r.reportRewindStatus(ctx, cluster, err)
 		if err != nil {
-			contextLogger.Info(
-				"pg_rewind failed, starting the server to complete the crash recovery",
-				"err", err)
-
-			// pg_rewind requires a clean shutdown of the old primary to work.
-			// The only way to do that is to start the server again
-			// and wait for it to be available again.
-			err = r.instance.CompleteCrashRecovery(ctx)
-			if err != nil {
-				return err
-			}
-
-			// Then let's go back to the point of the new primary
-			err = r.instance.Rewind(ctx, pgMajorVersion)
-			if err != nil {
-				return err
-			}
+			return err
 		}
 
 		// Now I can demote myself
@@ -288,6 +289,62 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(ctx context.Context
 	}
 }
 
+// rewindFormerPrimary aligns the data directory of a former primary
+// to the new one using pg_rewind
+func (r *InstanceReconciler) rewindFormerPrimary(ctx context.Context, pgMajorVersion int) error {
+	contextLogger := log.FromContext(ctx)
+
+	// pg_rewind could require a clean shutdown of the old primary to
+	// work. Unfortunately, if the old primary is already clean starting
+	// it up may make it advance in respect to the new one.
+	// The only way to check if we really need to start it up before
+	// invoking pg_rewind is to try using pg_rewind and, on failures,
+	// retrying after having started up the instance.
+	err := r.instance.Rewind(ctx, pgMajorVersion)
+	if err == nil {
+		return nil
+	}
+
+	contextLogger.Info(
+		"pg_rewind failed, starting the server to complete the crash recovery",
+		"err", err)
+
+	// pg_rewind requires a clean shutdown of the old primary to work.
+	// The only way to do that is to start the server again
+	// and wait for it to be available again.
+	if err := r.instance.CompleteCrashRecovery(ctx); err != nil {
+		return err
+	}
+
+	// Then let's go back to the point of the new primary
+	return r.instance.Rewind(ctx, pgMajorVersion)
+}
+
+// reportRewindStatus stores the outcome of pg_rewind in the status of the
+// cluster, so that the operator can recreate the instance when pg_rewind
+// can't be used and the rewind failure policy allows it
+func (r *InstanceReconciler) reportRewindStatus(ctx context.Context, cluster *apiv1.Cluster, rewindErr error) {
+	contextLogger := log.FromContext(ctx)
+
+	status := apiv1.InstanceRewindStatus{
+		Succeeded: rewindErr == nil,
+		Time:      pkgUtils.GetCurrentTimestamp(),
+	}
+	if rewindErr != nil {
+		status.Error = rewindErr.Error()
+		status.WALUnavailable = errors.Is(rewindErr, postgres.ErrPgRewindWALUnavailable)
+	}
+
+	oldCluster := cluster.DeepCopy()
+	if cluster.Status.RewindStatus == nil {
+		cluster.Status.RewindStatus = make(map[apiv1.PodName]apiv1.InstanceRewindStatus)
+	}
+	cluster.Status.RewindStatus[apiv1.PodName(r.instance.PodName)] = status
+	if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+		contextLogger.Error(err, "while reporting the outcome of pg_rewind")
+	}
+}
+
 // ReconcileWalStorage moves the files from PGDATA/pg_wal to the volume attached, if exists, and
 // creates a symlink for it
 func (r *InstanceReconciler) ReconcileWalStorage(ctx context.Context) error {
@@ -0,0 +1,213 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// timeoutParameters are the default timeouts
+// which are disabled for the exempted roles
+var timeoutParameters = []string{
+	"idle_in_transaction_session_timeout",
+	"statement_timeout",
+}
+
+// operatorRoles are the roles used by the operator, like for the maintenance
+// operations and the replication, which are never subject to the default
+// timeouts
+var operatorRoles = []string{
+	"postgres",
+	apiv1.StreamingReplicationUser,
+}
+
+// reconcileTimeoutExemptions disables the default timeouts of the cluster
+// for the exempted roles and for the ones used by the operator, with
+// `ALTER ROLE ... SET`, and removes the exemptions it created from the
+// roles which are not exempted anymore. The exempted roles are recorded
+// in the cluster status, so that the settings created by the users are
+// never reset
+func (r *InstanceReconciler) reconcileTimeoutExemptions(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.IsReplica() || r.instance.PodName != cluster.Status.CurrentPrimary {
+		return nil
+	}
+
+	parameters := cluster.Spec.PostgresConfiguration.Timeouts.GetParameters()
+	if len(parameters) == 0 && len(cluster.Status.TimeoutExemptRoles) == 0 {
+		return nil
+	}
+
+	var exemptRoles []string
+	if len(parameters) > 0 {
+		exemptRoles = append(slices.Clone(operatorRoles), cluster.Spec.PostgresConfiguration.Timeouts.ExemptRoles...)
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	exemptedRoles, err := reconcileTimeoutExemptions(
+		ctx,
+		db,
+		parameters,
+		exemptRoles,
+		cluster.Status.TimeoutExemptRoles)
+	if err != nil {
+		return err
+	}
+
+	if slices.Equal(exemptedRoles, cluster.Status.TimeoutExemptRoles) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.TimeoutExemptRoles = exemptedRoles
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// reconcileTimeoutExemptions makes sure that the passed timeouts are
+// disabled for the exempted roles existing in the database, and resets
+// the exemptions of the managed roles, which are the ones previously
+// exempted by the operator, when they are not needed anymore. The
+// exemptions of the other roles are left untouched. It returns the
+// roles which are now exempted by the operator
+func reconcileTimeoutExemptions(
+	ctx context.Context,
+	db *sql.DB,
+	parameters map[string]string,
+	exemptRoles []string,
+	managedRoles []string,
+) ([]string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	exemptions, err := getTimeoutExemptions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	existingRoles, err := getRoleNames(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var exemptedRoles []string
+	for _, role := range exemptRoles {
+		if !slices.Contains(existingRoles, role) {
+			contextLogger.Debug("Exempted role not found, skipping", "role", role)
+			continue
+		}
+		exemptedRoles = append(exemptedRoles, role)
+	}
+	slices.Sort(exemptedRoles)
+	exemptedRoles = slices.Compact(exemptedRoles)
+
+	for _, name := range timeoutParameters {
+		_, enabled := parameters[name]
+
+		for _, role := range exemptedRoles {
+			if !enabled || slices.Contains(exemptions[name], role) {
+				continue
+			}
+
+			contextLogger.Info("Exempting role from the default timeout", "role", role, "parameter", name)
+			query := fmt.Sprintf("ALTER ROLE %s SET %s TO 0", pgx.Identifier{role}.Sanitize(), name)
+			if _, err := db.ExecContext(ctx, query); err != nil {
+				return nil, fmt.Errorf("while exempting role %s from %s: %w", role, name, err)
+			}
+		}
+
+		for _, role := range exemptions[name] {
+			if !slices.Contains(managedRoles, role) {
+				continue
+			}
+			if enabled && slices.Contains(exemptedRoles, role) {
+				continue
+			}
+
+			contextLogger.Info("Removing the exemption from the default timeout", "role", role, "parameter", name)
+			query := fmt.Sprintf("ALTER ROLE %s RESET %s", pgx.Identifier{role}.Sanitize(), name)
+			if _, err := db.ExecContext(ctx, query); err != nil {
+				return nil, fmt.Errorf("while removing the exemption of role %s from %s: %w", role, name, err)
+			}
+		}
+	}
+
+	return exemptedRoles, nil
+}
+
+// getTimeoutExemptions gets, for each timeout, the roles
+// which have it disabled with `ALTER ROLE ... SET`
+func getTimeoutExemptions(ctx context.Context, db *sql.DB) (map[string][]string, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT r.rolname, split_part(c.setting, '=', 1)
+		FROM pg_catalog.pg_db_role_setting s
+		JOIN pg_catalog.pg_roles r ON r.oid = s.setrole
+		CROSS JOIN LATERAL unnest(s.setconfig) AS c(setting)
+		WHERE s.setdatabase = 0
+		AND c.setting IN ('statement_timeout=0', 'idle_in_transaction_session_timeout=0')
+		ORDER BY 1`)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the exemptions from the default timeouts: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var role, name string
+		if err := rows.Scan(&role, &name); err != nil {
+			return nil, err
+		}
+		result[name] = append(result[name], role)
+	}
+
+	return result, rows.Err()
+}
+
+// getRoleNames gets the names of the roles existing in the database
+func getRoleNames(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT rolname FROM pg_catalog.pg_roles")
+	if err != nil {
+		return nil, fmt.Errorf("while getting the list of roles: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		result = append(result, role)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("exemptions from the default timeouts", func() {
+	const (
+		exemptionsQuery = `SELECT r.rolname, split_part(c.setting, '=', 1)
+		FROM pg_catalog.pg_db_role_setting s
+		JOIN pg_catalog.pg_roles r ON r.oid = s.setrole
+		CROSS JOIN LATERAL unnest(s.setconfig) AS c(setting)
+		WHERE s.setdatabase = 0
+		AND c.setting IN ('statement_timeout=0', 'idle_in_transaction_session_timeout=0')
+		ORDER BY 1`
+		rolesQuery = "SELECT rolname FROM pg_catalog.pg_roles"
+	)
+
+	var (
+		dbMock sqlmock.Sqlmock
+		db     *sql.DB
+	)
+
+	parameters := map[string]string{
+		"statement_timeout":                   "30000ms",
+		"idle_in_transaction_session_timeout": "60000ms",
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("exempts the existing roles which are not exempted yet", func(ctx SpecContext) {
+		dbMock.ExpectQuery(exemptionsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "name"}).AddRow("batch", "statement_timeout"))
+		dbMock.ExpectQuery(rolesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname"}).AddRow("postgres").AddRow("app").AddRow("batch"))
+		dbMock.ExpectExec(`ALTER ROLE "batch" SET idle_in_transaction_session_timeout TO 0`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		exemptedRoles, err := reconcileTimeoutExemptions(ctx, db, parameters, []string{"batch", "missing"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exemptedRoles).To(Equal([]string{"batch"}))
+	})
+
+	It("removes the exemptions which are not requested anymore", func(ctx SpecContext) {
+		dbMock.ExpectQuery(exemptionsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "name"}).
+				AddRow("app", "statement_timeout").
+				AddRow("batch", "idle_in_transaction_session_timeout").
+				AddRow("batch", "statement_timeout"))
+		dbMock.ExpectQuery(rolesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname"}).AddRow("postgres").AddRow("app").AddRow("batch"))
+		dbMock.ExpectExec(`ALTER ROLE "app" RESET statement_timeout`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		exemptedRoles, err := reconcileTimeoutExemptions(
+			ctx, db, parameters, []string{"batch"}, []string{"app", "batch"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exemptedRoles).To(Equal([]string{"batch"}))
+	})
+
+	It("doesn't reset the settings created by the users", func(ctx SpecContext) {
+		dbMock.ExpectQuery(exemptionsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "name"}).AddRow("dba", "statement_timeout"))
+		dbMock.ExpectQuery(rolesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname"}).AddRow("postgres").AddRow("dba"))
+
+		exemptedRoles, err := reconcileTimeoutExemptions(ctx, db, parameters, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exemptedRoles).To(BeEmpty())
+	})
+
+	It("resets the exemptions from the timeouts which are not set anymore", func(ctx SpecContext) {
+		dbMock.ExpectQuery(exemptionsQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname", "name"}).
+				AddRow("postgres", "idle_in_transaction_session_timeout").
+				AddRow("postgres", "statement_timeout"))
+		dbMock.ExpectQuery(rolesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"rolname"}).AddRow("postgres"))
+		dbMock.ExpectExec(`ALTER ROLE "postgres" RESET idle_in_transaction_session_timeout`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		exemptedRoles, err := reconcileTimeoutExemptions(
+			ctx, db, map[string]string{"statement_timeout": "30000ms"}, operatorRoles, []string{"postgres"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exemptedRoles).To(Equal([]string{"postgres"}))
+	})
+})
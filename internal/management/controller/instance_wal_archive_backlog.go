@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+const (
+	// walArchiveBacklogCheckInterval is how often the primary instance
+	// counts the WAL files waiting to be archived, when they are throttled
+	walArchiveBacklogCheckInterval = 10 * time.Second
+
+	// walArchiveBacklogResumeRatio is the fraction of the threshold the
+	// backlog needs to go back to before the throttling is stopped, to
+	// avoid reloading the configuration at every check when the backlog
+	// is around the threshold
+	walArchiveBacklogResumeRatio = 0.8
+)
+
+// reconcileWALArchiveBacklog counts the WAL files waiting to be archived
+// on the primary instance and, when they exceed the threshold configured
+// in the cluster, throttles the write transactions until the archiver
+// catches up. This way the WAL files don't fill the volume, crashing
+// PostgreSQL. The configuration files are then refreshed by the
+// reconciliation loop itself
+func (r *InstanceReconciler) reconcileWALArchiveBacklog(ctx context.Context, cluster *apiv1.Cluster) {
+	contextLogger := log.FromContext(ctx)
+
+	if !r.shouldCheckWALArchiveBacklog(cluster) {
+		r.instance.SetWALArchiveThrottled(false)
+		return
+	}
+	if isPrimary, err := r.instance.IsPrimary(); err != nil || !isPrimary {
+		r.instance.SetWALArchiveThrottled(false)
+		return
+	}
+
+	readyWALFiles, _, err := postgres.GetWALArchiveCounters()
+	if err != nil {
+		contextLogger.Error(err, "while counting the WAL files waiting to be archived")
+		return
+	}
+
+	configuration := cluster.Spec.Backup.GetWalArchiveBacklog()
+	throttled := r.instance.IsWALArchiveThrottled()
+	shouldThrottle := isThrottlingNeeded(configuration, readyWALFiles, throttled)
+	if shouldThrottle == throttled {
+		return
+	}
+
+	if shouldThrottle {
+		contextLogger.Warning("Too many WAL files waiting to be archived, stopping the write transactions",
+			"readyWALFiles", readyWALFiles,
+			"maxReadyWALFiles", configuration.MaxReadyWALFiles)
+	} else {
+		contextLogger.Info("WAL archive backlog is back within the threshold, resuming the write transactions",
+			"readyWALFiles", readyWALFiles,
+			"maxReadyWALFiles", configuration.MaxReadyWALFiles)
+	}
+	r.instance.SetWALArchiveThrottled(shouldThrottle)
+}
+
+// shouldCheckWALArchiveBacklog checks if the WAL archive backlog needs
+// to be checked, which happens on the primary instance of a primary
+// cluster when the throttling is enabled
+func (r *InstanceReconciler) shouldCheckWALArchiveBacklog(cluster *apiv1.Cluster) bool {
+	return cluster.Spec.Backup.GetWalArchiveBacklog().ShouldThrottle() &&
+		!cluster.IsReplica() &&
+		cluster.Status.CurrentPrimary == r.instance.PodName &&
+		!r.instance.IsFenced()
+}
+
+// isThrottlingNeeded checks if the write transactions need to be throttled
+// given the number of WAL files waiting to be archived. Once started, the
+// throttling lasts until the backlog goes below walArchiveBacklogResumeRatio
+// of the threshold
+func isThrottlingNeeded(
+	configuration *apiv1.WalArchiveBacklogConfiguration,
+	readyWALFiles int,
+	throttled bool,
+) bool {
+	threshold := float64(configuration.MaxReadyWALFiles)
+	if throttled {
+		threshold *= walArchiveBacklogResumeRatio
+	}
+
+	return float64(readyWALFiles) > threshold
+}
+
+// reconcileWALArchiveThrottling terminates the write transactions in
+// progress while the WAL archive backlog is being throttled. The new
+// transactions are read-only by default, but they can still be
+// switched to read-write: these ones are terminated as well
+func (r *InstanceReconciler) reconcileWALArchiveThrottling(ctx context.Context) error {
+	if !r.instance.IsWALArchiveThrottled() {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	terminated, err := terminateWriteTransactions(ctx, db)
+	if err != nil {
+		return err
+	}
+	if terminated > 0 {
+		log.FromContext(ctx).Warning(
+			"Terminated the write transactions while the WAL archive backlog is throttled",
+			"terminated", terminated)
+	}
+
+	return nil
+}
+
+// terminateWriteTransactions terminates the client sessions running a
+// transaction which wrote something, and therefore has a transaction ID,
+// returning how many they were. The sessions of the superusers, including
+// the ones of the operator, are not terminated
+func terminateWriteTransactions(ctx context.Context, db *sql.DB) (int, error) {
+	var terminated int
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT count(pg_catalog.pg_terminate_backend(a.pid))
+		FROM pg_catalog.pg_stat_activity a
+		JOIN pg_catalog.pg_roles r ON r.oid = a.usesysid
+		WHERE a.backend_type = 'client backend'
+		AND a.backend_xid IS NOT NULL
+		AND a.pid <> pg_catalog.pg_backend_pid()
+		AND NOT r.rolsuper`)
+	if err := row.Scan(&terminated); err != nil {
+		return 0, fmt.Errorf("while terminating the write transactions: %w", err)
+	}
+
+	return terminated, nil
+}
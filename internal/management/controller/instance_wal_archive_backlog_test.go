@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archive backlog throttling", func() {
+	configuration := &apiv1.WalArchiveBacklogConfiguration{
+		MaxReadyWALFiles: 100,
+		Action:           apiv1.WalArchiveBacklogActionThrottle,
+	}
+
+	It("throttles when the backlog exceeds the threshold", func() {
+		Expect(isThrottlingNeeded(configuration, 100, false)).To(BeFalse())
+		Expect(isThrottlingNeeded(configuration, 101, false)).To(BeTrue())
+	})
+
+	It("keeps throttling until the backlog is well below the threshold", func() {
+		Expect(isThrottlingNeeded(configuration, 90, true)).To(BeTrue())
+		Expect(isThrottlingNeeded(configuration, 80, true)).To(BeFalse())
+	})
+
+	It("terminates the write transactions", func(ctx SpecContext) {
+		db, dbMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		dbMock.ExpectQuery(`SELECT count(pg_catalog.pg_terminate_backend(a.pid))
+		FROM pg_catalog.pg_stat_activity a
+		JOIN pg_catalog.pg_roles r ON r.oid = a.usesysid
+		WHERE a.backend_type = 'client backend'
+		AND a.backend_xid IS NOT NULL
+		AND a.pid <> pg_catalog.pg_backend_pid()
+		AND NOT r.rolsuper`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		Expect(terminateWriteTransactions(ctx, db)).To(Equal(2))
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+})
@@ -0,0 +1,273 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// pollInterval is how often the Scheduler looks whether a new
+// maintenance is due
+const pollInterval = 30 * time.Second
+
+// A Scheduler is a Kubernetes manager.Runnable that runs the scheduled
+// maintenance of the databases, like the periodic rebuild of the indexes.
+// It only works on the primary instance, as the maintenance statements
+// can't be executed on a hot standby. Since the maintenance is run inside
+// the loop of the Scheduler, two runs never overlap.
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Scheduler struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// The time of the last run on this instance, used to avoid
+	// running the maintenance again when the status couldn't be
+	// updated
+	lastRun time.Time
+}
+
+// NewScheduler creates a new maintenance Scheduler
+func NewScheduler(instance *postgres.Instance, client client.Client) *Scheduler {
+	return &Scheduler{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts running the maintenance Scheduler
+func (s *Scheduler) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("maintenance_scheduler")
+	ticker := time.NewTicker(pollInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated maintenance scheduler loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := s.reconcile(ctx); err != nil {
+			contextLog.Error(err, "while running the scheduled maintenance")
+		}
+	}
+}
+
+func (s *Scheduler) reconcile(ctx context.Context) error {
+	contextLog := log.FromContext(ctx)
+
+	cachedCluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due, err := isMaintenanceDue(cachedCluster, s.lastRun, now)
+	if err != nil || !due {
+		return err
+	}
+
+	if cachedCluster.Status.CurrentPrimary != s.instance.PodName || s.instance.IsFenced() {
+		return nil
+	}
+	if isPrimary, err := s.instance.IsPrimary(); err != nil || !isPrimary {
+		return err
+	}
+
+	s.lastRun = now
+
+	var cluster apiv1.Cluster
+	if err := s.client.Get(
+		ctx,
+		client.ObjectKey{Namespace: s.instance.Namespace, Name: s.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	startedAt := metav1.NewTime(now)
+	if err := patchStatus(ctx, s.client, &cluster, &apiv1.ScheduledMaintenanceStatus{
+		Phase:     apiv1.ScheduledMaintenancePhaseRunning,
+		Instance:  s.instance.PodName,
+		StartedAt: &startedAt,
+	}); err != nil {
+		return err
+	}
+
+	db, err := s.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	contextLog.Info("Starting the scheduled maintenance")
+	maintenanceErr := runMaintenance(
+		ctx,
+		db,
+		s.instance.ConnectionPool().Connection,
+		cluster.Spec.ScheduledMaintenance.Databases,
+		cluster.Spec.ScheduledMaintenance.SQL,
+	)
+
+	stoppedAt := metav1.Now()
+	status := &apiv1.ScheduledMaintenanceStatus{
+		Phase:     apiv1.ScheduledMaintenancePhaseSucceeded,
+		Instance:  s.instance.PodName,
+		StartedAt: &startedAt,
+		StoppedAt: &stoppedAt,
+	}
+	if maintenanceErr != nil {
+		contextLog.Error(maintenanceErr, "Scheduled maintenance failed")
+		status.Phase = apiv1.ScheduledMaintenancePhaseFailed
+		status.Message = maintenanceErr.Error()
+	} else {
+		contextLog.Info("Scheduled maintenance completed")
+	}
+
+	return patchStatus(ctx, s.client, &cluster, status)
+}
+
+// isMaintenanceDue checks if the scheduled maintenance should be started.
+// The runs that have been missed, i.e. because the instance wasn't the
+// primary, are not recovered: we just wait for the next one.
+func isMaintenanceDue(cluster *apiv1.Cluster, lastRun time.Time, now time.Time) (bool, error) {
+	if !cluster.Spec.ScheduledMaintenance.IsEnabled() {
+		return false, nil
+	}
+
+	schedule, err := cron.Parse(cluster.Spec.ScheduledMaintenance.GetSchedule())
+	if err != nil {
+		return false, fmt.Errorf("while parsing the schedule of the maintenance: %w", err)
+	}
+
+	reference := cluster.CreationTimestamp.Time
+	if status := cluster.Status.ScheduledMaintenance; status != nil {
+		switch {
+		case status.StoppedAt != nil:
+			reference = status.StoppedAt.Time
+		case status.StartedAt != nil:
+			reference = status.StartedAt.Time
+		}
+	}
+	if lastRun.After(reference) {
+		reference = lastRun
+	}
+
+	return !schedule.Next(reference).After(now), nil
+}
+
+// runMaintenance runs the maintenance statements in the passed databases,
+// or in every database accepting connections if none is passed. When no
+// statement is passed, the indexes of each database are rebuilt. It stops
+// at the first failure.
+func runMaintenance(
+	ctx context.Context,
+	db *sql.DB,
+	getConnection func(dbname string) (*sql.DB, error),
+	databases []string,
+	statements []string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if len(databases) == 0 {
+		var err error
+		if databases, err = getDatabases(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	for _, database := range databases {
+		targetDB, err := getConnection(database)
+		if err != nil {
+			return fmt.Errorf("while connecting to database %s: %w", database, err)
+		}
+
+		databaseStatements := statements
+		if len(databaseStatements) == 0 {
+			databaseStatements = []string{
+				fmt.Sprintf("REINDEX DATABASE CONCURRENTLY %s", pgx.Identifier{database}.Sanitize()),
+			}
+		}
+
+		for _, statement := range databaseStatements {
+			contextLogger.Info("Running maintenance statement", "database", database, "statement", statement)
+			if _, err := targetDB.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("while running %s on database %s: %w", statement, database, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getDatabases gets the databases accepting connections, except the
+// template ones
+func getDatabases(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		"SELECT datname FROM pg_catalog.pg_database WHERE datallowconn AND NOT datistemplate ORDER BY datname")
+	if err != nil {
+		return nil, fmt.Errorf("while getting the list of databases: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+
+	return databases, rows.Err()
+}
+
+// patchStatus stores the passed status of the maintenance in the cluster
+func patchStatus(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	status *apiv1.ScheduledMaintenanceStatus,
+) error {
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ScheduledMaintenance = status
+	return cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("isMaintenanceDue", func() {
+	var cluster *apiv1.Cluster
+	creationTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(creationTime),
+			},
+			Spec: apiv1.ClusterSpec{
+				ScheduledMaintenance: &apiv1.ScheduledMaintenanceConfiguration{
+					Enabled:  true,
+					Schedule: "0 0 2 * * *",
+				},
+			},
+		}
+	})
+
+	It("is not due when the maintenance is disabled", func() {
+		cluster.Spec.ScheduledMaintenance.Enabled = false
+		Expect(isMaintenanceDue(cluster, time.Time{}, creationTime.Add(48*time.Hour))).To(BeFalse())
+	})
+
+	It("is due once the first scheduled time has been reached", func() {
+		Expect(isMaintenanceDue(cluster, time.Time{}, creationTime.Add(time.Hour))).To(BeFalse())
+		Expect(isMaintenanceDue(cluster, time.Time{}, creationTime.Add(14*time.Hour))).To(BeTrue())
+	})
+
+	It("waits for the next scheduled time after the last run", func() {
+		stoppedAt := metav1.NewTime(creationTime.Add(14*time.Hour + 10*time.Minute))
+		cluster.Status.ScheduledMaintenance = &apiv1.ScheduledMaintenanceStatus{
+			Phase:     apiv1.ScheduledMaintenancePhaseSucceeded,
+			StoppedAt: &stoppedAt,
+		}
+		Expect(isMaintenanceDue(cluster, time.Time{}, creationTime.Add(20*time.Hour))).To(BeFalse())
+		Expect(isMaintenanceDue(cluster, time.Time{}, creationTime.Add(38*time.Hour))).To(BeTrue())
+	})
+
+	It("doesn't run again when the status couldn't be updated", func() {
+		lastRun := creationTime.Add(14 * time.Hour)
+		Expect(isMaintenanceDue(cluster, lastRun, creationTime.Add(20*time.Hour))).To(BeFalse())
+	})
+
+	It("reports an invalid schedule", func() {
+		cluster.Spec.ScheduledMaintenance.Schedule = "invalid"
+		_, err := isMaintenanceDue(cluster, time.Time{}, creationTime)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("runMaintenance", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	getConnection := func(string) (*sql.DB, error) {
+		return db, nil
+	}
+
+	It("rebuilds the indexes of every database by default", func(ctx SpecContext) {
+		mock.ExpectQuery(
+			"SELECT datname FROM pg_catalog.pg_database WHERE datallowconn AND NOT datistemplate ORDER BY datname").
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("postgres"))
+		mock.ExpectExec(`REINDEX DATABASE CONCURRENTLY "app"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`REINDEX DATABASE CONCURRENTLY "postgres"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(runMaintenance(ctx, db, getConnection, nil, nil)).To(Succeed())
+	})
+
+	It("runs the requested statements in the requested databases", func(ctx SpecContext) {
+		mock.ExpectExec("REINDEX TABLE CONCURRENTLY orders").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("VACUUM (ANALYZE) orders").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(runMaintenance(ctx, db, getConnection, []string{"app"},
+			[]string{"REINDEX TABLE CONCURRENTLY orders", "VACUUM (ANALYZE) orders"})).To(Succeed())
+	})
+
+	It("stops at the first failure", func(ctx SpecContext) {
+		mock.ExpectExec(`REINDEX DATABASE CONCURRENTLY "app"`).WillReturnError(errors.New("deadlock detected"))
+
+		err := runMaintenance(ctx, db, getConnection, []string{"app", "postgres"}, nil)
+		Expect(err).To(MatchError(ContainSubstring("deadlock detected")))
+	})
+})
@@ -43,6 +43,10 @@ type InstanceReconciler struct {
 	secretVersions  map[string]string
 	extensionStatus map[string]bool
 
+	// The previous measure of the WAL generated by the instance,
+	// used to compute the WAL generation rate
+	lastWALSample *walSample
+
 	systemInitialization  *concurrency.Executed
 	firstReconcileDone    atomic.Bool
 	metricsServerExporter *metricserver.Exporter
@@ -0,0 +1,227 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// publicationReconciliationInterval is the time between two
+// reconciliations of a Publication, to fix the drift of the
+// publication in PostgreSQL from its specification
+const publicationReconciliationInterval = 30 * time.Second
+
+// PublicationReconciler reconciles a Publication object
+type PublicationReconciler struct {
+	client.Client
+	instance *postgres.Instance
+}
+
+// NewPublicationReconciler creates a new publication reconciler
+func NewPublicationReconciler(instance *postgres.Instance, client client.Client) *PublicationReconciler {
+	return &PublicationReconciler{
+		Client:   client,
+		instance: instance,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PublicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.Publication{}).
+		Complete(r)
+}
+
+// Reconcile is the publication reconciliation loop
+func (r *PublicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx).WithName("publication_reconciler").
+		WithValues("publication", req.NamespacedName)
+
+	var publication apiv1.Publication
+	if err := r.Client.Get(ctx, req.NamespacedName, &publication); err != nil {
+		// The publication has been deleted and, if needed, the finalizer
+		// has already been executed
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// This publication is not for the cluster managed by this instance
+	if publication.Spec.ClusterRef.Name != r.instance.ClusterName {
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := r.getCluster(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The cluster has been deleted, together with its publications.
+			// We remove the finalizer, as nobody would be left to do it,
+			// and wait for this instance manager to be terminated
+			contextLogger.Debug("Could not find Cluster")
+			return ctrl.Result{}, r.removeFinalizer(ctx, &publication)
+		}
+		return ctrl.Result{}, fmt.Errorf("could not fetch Cluster: %w", err)
+	}
+
+	// Publications are managed only by the primary instance of a primary
+	// cluster. We requeue to handle switchovers and promotions.
+	if cluster.IsReplica() ||
+		cluster.Status.CurrentPrimary != r.instance.PodName ||
+		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		contextLogger.Trace("skipping the publication reconciler on a non-primary instance")
+		return ctrl.Result{RequeueAfter: publicationReconciliationInterval}, nil
+	}
+
+	if !publication.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDeletion(ctx, &publication)
+	}
+
+	if err := r.reconcileFinalizer(ctx, &publication); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if r.instance.IsServerReady() != nil {
+		contextLogger.Debug("database not ready, skipping publication reconciling")
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	reconcileErr := r.reconcilePublication(ctx, &publication)
+
+	origPublication := publication.DeepCopy()
+	publication.Status.ObservedGeneration = publication.Generation
+	publication.Status.Ready = reconcileErr == nil
+	publication.Status.Error = ""
+	if reconcileErr != nil {
+		publication.Status.Error = reconcileErr.Error()
+	}
+	if err := r.Client.Status().Patch(ctx, &publication, client.MergeFrom(origPublication)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("while setting the publication status: %w", err)
+	}
+
+	if reconcileErr != nil {
+		contextLogger.Info("Error while reconciling publication", "err", reconcileErr)
+	}
+
+	// The publication can be changed inside PostgreSQL too: we
+	// requeue to bring it back to the specification
+	return ctrl.Result{RequeueAfter: publicationReconciliationInterval}, nil
+}
+
+// removeFinalizer removes the finalizer from the publication, if present
+func (r *PublicationReconciler) removeFinalizer(ctx context.Context, publication *apiv1.Publication) error {
+	if !controllerutil.RemoveFinalizer(publication, apiv1.PublicationFinalizerName) {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Client.Update(ctx, publication))
+}
+
+// reconcileFinalizer ensures the finalizer is set only when the publication
+// needs to be dropped on deletion
+func (r *PublicationReconciler) reconcileFinalizer(ctx context.Context, publication *apiv1.Publication) error {
+	var changed bool
+	if publication.Spec.ReclaimPolicy == apiv1.PublicationReclaimDelete {
+		changed = controllerutil.AddFinalizer(publication, apiv1.PublicationFinalizerName)
+	} else {
+		changed = controllerutil.RemoveFinalizer(publication, apiv1.PublicationFinalizerName)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return r.Client.Update(ctx, publication)
+}
+
+// reconcileDeletion drops the publication, if required by the reclaim
+// policy, and removes the finalizer
+func (r *PublicationReconciler) reconcileDeletion(ctx context.Context, publication *apiv1.Publication) error {
+	if !controllerutil.ContainsFinalizer(publication, apiv1.PublicationFinalizerName) {
+		return nil
+	}
+
+	if publication.Spec.ReclaimPolicy == apiv1.PublicationReclaimDelete {
+		db, err := r.instance.ConnectionPool().Connection(publication.Spec.DBName)
+		if err != nil {
+			return fmt.Errorf("while connecting to database %q: %w", publication.Spec.DBName, err)
+		}
+
+		if err := dropPublication(ctx, db, publication); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(publication, apiv1.PublicationFinalizerName)
+	return r.Client.Update(ctx, publication)
+}
+
+// reconcilePublication creates the publication, or aligns the existing
+// one to the specification
+func (r *PublicationReconciler) reconcilePublication(ctx context.Context, publication *apiv1.Publication) error {
+	ver, err := r.instance.GetPgVersion()
+	if err != nil {
+		return err
+	}
+
+	withSchemas := ver.Major >= 15
+	if !withSchemas && hasTablesInSchema(&publication.Spec.Target) {
+		return errPublicationTablesInSchemaNotSupported
+	}
+
+	// Publications live inside the database, so we need to connect to it
+	db, err := r.instance.ConnectionPool().Connection(publication.Spec.DBName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %q: %w", publication.Spec.DBName, err)
+	}
+
+	state, err := detectPublication(ctx, db, publication, withSchemas)
+	if err != nil {
+		return err
+	}
+
+	if state != nil {
+		return updatePublication(ctx, db, publication, state)
+	}
+
+	return createPublication(ctx, db, publication)
+}
+
+// getCluster gets the managed cluster through the client
+func (r *PublicationReconciler) getCluster(ctx context.Context) (*apiv1.Cluster, error) {
+	var cluster apiv1.Cluster
+	err := r.Client.Get(ctx,
+		types.NamespacedName{
+			Namespace: r.instance.Namespace,
+			Name:      r.instance.ClusterName,
+		},
+		&cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cluster, nil
+}
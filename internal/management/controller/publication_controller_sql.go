@@ -0,0 +1,345 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// errPublicationAllTablesChanged is raised when the target of an existing
+// publication is switched from or to FOR ALL TABLES, which PostgreSQL
+// doesn't allow with ALTER PUBLICATION
+var errPublicationAllTablesChanged = errors.New(
+	"cannot switch an existing publication from or to FOR ALL TABLES, it needs to be recreated")
+
+// errPublicationTablesInSchemaNotSupported is raised when the publication
+// is requested to publish every table of a schema, which is not supported
+// before PostgreSQL 15
+var errPublicationTablesInSchemaNotSupported = errors.New(
+	"publishing the tables in a schema requires PostgreSQL 15 or later")
+
+// allPublicationOperations is the list of the operations published by default
+var allPublicationOperations = []apiv1.PublicationOperation{
+	apiv1.PublicationOperationInsert,
+	apiv1.PublicationOperationUpdate,
+	apiv1.PublicationOperationDelete,
+	apiv1.PublicationOperationTruncate,
+}
+
+// publicationState is the state of an existing publication
+type publicationState struct {
+	// Whether the publication is for all the tables
+	allTables bool
+
+	// The published operations
+	operations []apiv1.PublicationOperation
+
+	// The sorted and quoted qualified names of the published tables
+	tables []string
+
+	// The sorted and quoted names of the published schemas
+	schemas []string
+}
+
+// detectPublication checks if the publication exists in the database,
+// returning its state or nil when it doesn't exist. The published
+// schemas are only read when supported, from PostgreSQL 15
+func detectPublication(
+	ctx context.Context,
+	db *sql.DB,
+	obj *apiv1.Publication,
+	withSchemas bool,
+) (*publicationState, error) {
+	var (
+		state                                        publicationState
+		pubInsert, pubUpdate, pubDelete, pubTruncate bool
+	)
+	row := db.QueryRowContext(
+		ctx,
+		`SELECT puballtables, pubinsert, pubupdate, pubdelete, pubtruncate
+		FROM pg_catalog.pg_publication WHERE pubname = $1`,
+		obj.Spec.Name)
+	err := row.Scan(&state.allTables, &pubInsert, &pubUpdate, &pubDelete, &pubTruncate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while detecting publication %q: %w", obj.Spec.Name, err)
+	}
+
+	for idx, published := range []bool{pubInsert, pubUpdate, pubDelete, pubTruncate} {
+		if published {
+			state.operations = append(state.operations, allPublicationOperations[idx])
+		}
+	}
+
+	if state.allTables {
+		return &state, nil
+	}
+
+	state.tables, err = queryPublicationNames(
+		ctx,
+		db,
+		`SELECT n.nspname, c.relname
+		FROM pg_catalog.pg_publication_rel pr
+		JOIN pg_catalog.pg_publication p ON p.oid = pr.prpubid
+		JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE p.pubname = $1`,
+		obj.Spec.Name)
+	if err != nil {
+		return nil, fmt.Errorf("while detecting the tables of publication %q: %w", obj.Spec.Name, err)
+	}
+
+	if !withSchemas {
+		return &state, nil
+	}
+
+	state.schemas, err = queryPublicationNames(
+		ctx,
+		db,
+		`SELECT n.nspname
+		FROM pg_catalog.pg_publication_namespace pn
+		JOIN pg_catalog.pg_publication p ON p.oid = pn.pnpubid
+		JOIN pg_catalog.pg_namespace n ON n.oid = pn.pnnspid
+		WHERE p.pubname = $1`,
+		obj.Spec.Name)
+	if err != nil {
+		return nil, fmt.Errorf("while detecting the schemas of publication %q: %w", obj.Spec.Name, err)
+	}
+
+	return &state, nil
+}
+
+// queryPublicationNames runs the passed query, returning the sorted list
+// of the quoted identifiers made of the columns of each row
+func queryPublicationNames(ctx context.Context, db *sql.DB, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for rows.Next() {
+		identifier := make(pgx.Identifier, len(columns))
+		pointers := make([]any, len(columns))
+		for idx := range identifier {
+			pointers[idx] = &identifier[idx]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		result = append(result, identifier.Sanitize())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(result)
+	return result, nil
+}
+
+// createPublication creates the publication as required by the specification
+func createPublication(ctx context.Context, db *sql.DB, obj *apiv1.Publication) error {
+	var sqlCreatePublication strings.Builder
+	sqlCreatePublication.WriteString(
+		fmt.Sprintf("CREATE PUBLICATION %s FOR %s", pgx.Identifier{obj.Spec.Name}.Sanitize(),
+			toPublicationTargetSQL(&obj.Spec.Target)))
+	if len(obj.Spec.Publish) > 0 {
+		sqlCreatePublication.WriteString(
+			fmt.Sprintf(" WITH (publish = %s)", toPublishOperationsSQL(obj.Spec.Publish)))
+	}
+
+	if _, err := db.ExecContext(ctx, sqlCreatePublication.String()); err != nil {
+		return fmt.Errorf("while creating publication %q: %w", obj.Spec.Name, err)
+	}
+
+	return nil
+}
+
+// updatePublication aligns the target and the published operations of an
+// existing publication to the specification, when they differ
+func updatePublication(ctx context.Context, db *sql.DB, obj *apiv1.Publication, state *publicationState) error {
+	if state.allTables != obj.Spec.Target.AllTables {
+		return errPublicationAllTablesChanged
+	}
+
+	name := pgx.Identifier{obj.Spec.Name}.Sanitize()
+
+	var changes []string
+	if !state.allTables {
+		tables, schemas, err := getPublicationTargetNames(ctx, db, &obj.Spec.Target)
+		if err != nil {
+			return fmt.Errorf("while resolving the target of publication %q: %w", obj.Spec.Name, err)
+		}
+
+		if !slices.Equal(tables, state.tables) || !slices.Equal(schemas, state.schemas) {
+			changes = append(changes,
+				fmt.Sprintf("ALTER PUBLICATION %s SET %s", name, toPublicationTargetSQL(&obj.Spec.Target)))
+		}
+	}
+
+	publish := obj.Spec.Publish
+	if len(publish) == 0 {
+		publish = allPublicationOperations
+	}
+	if !isSamePublicationOperations(publish, state.operations) {
+		changes = append(changes,
+			fmt.Sprintf("ALTER PUBLICATION %s SET (publish = %s)", name, toPublishOperationsSQL(publish)))
+	}
+
+	for _, change := range changes {
+		if _, err := db.ExecContext(ctx, change); err != nil {
+			return fmt.Errorf("while altering publication %q: %w", obj.Spec.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getPublicationTargetNames gets the sorted and quoted qualified names of
+// the tables, and the sorted and quoted names of the schemas, in the passed
+// target. The schema of the tables which don't have one is found through
+// the search path, and the tables which don't exist are left unqualified
+func getPublicationTargetNames(
+	ctx context.Context,
+	db *sql.DB,
+	target *apiv1.PublicationTarget,
+) (tables []string, schemas []string, err error) {
+	for _, object := range target.Objects {
+		switch {
+		case object.Table != nil && object.Table.Schema != "":
+			tables = append(tables, pgx.Identifier{object.Table.Schema, object.Table.Name}.Sanitize())
+
+		case object.Table != nil:
+			var schema string
+			err := db.QueryRowContext(
+				ctx,
+				`SELECT n.nspname FROM pg_catalog.pg_class c
+				JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+				WHERE c.oid = pg_catalog.to_regclass($1)`,
+				pgx.Identifier{object.Table.Name}.Sanitize()).Scan(&schema)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				tables = append(tables, pgx.Identifier{object.Table.Name}.Sanitize())
+			case err != nil:
+				return nil, nil, err
+			default:
+				tables = append(tables, pgx.Identifier{schema, object.Table.Name}.Sanitize())
+			}
+
+		default:
+			schemas = append(schemas, pgx.Identifier{object.TablesInSchema}.Sanitize())
+		}
+	}
+
+	slices.Sort(tables)
+	slices.Sort(schemas)
+	return tables, schemas, nil
+}
+
+// isSamePublicationOperations checks whether the passed
+// lists contain the same operations, in any order
+func isSamePublicationOperations(operations, otherOperations []apiv1.PublicationOperation) bool {
+	sortedOperations := slices.Clone(operations)
+	slices.Sort(sortedOperations)
+	sortedOperations = slices.Compact(sortedOperations)
+
+	sortedOtherOperations := slices.Clone(otherOperations)
+	slices.Sort(sortedOtherOperations)
+	sortedOtherOperations = slices.Compact(sortedOtherOperations)
+
+	return slices.Equal(sortedOperations, sortedOtherOperations)
+}
+
+// hasTablesInSchema checks whether the passed target publishes
+// every table of a schema, which requires PostgreSQL 15
+func hasTablesInSchema(target *apiv1.PublicationTarget) bool {
+	for _, object := range target.Objects {
+		if object.TablesInSchema != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dropPublication drops the publication, if it exists
+func dropPublication(ctx context.Context, db *sql.DB, obj *apiv1.Publication) error {
+	_, err := db.ExecContext(
+		ctx,
+		fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pgx.Identifier{obj.Spec.Name}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("while dropping publication %q: %w", obj.Spec.Name, err)
+	}
+
+	return nil
+}
+
+// toPublicationTargetSQL renders the target of the publication, as expected
+// after the FOR keyword of CREATE PUBLICATION and the SET keyword
+// of ALTER PUBLICATION
+func toPublicationTargetSQL(target *apiv1.PublicationTarget) string {
+	if target.AllTables {
+		return "ALL TABLES"
+	}
+
+	objects := make([]string, 0, len(target.Objects))
+	for _, object := range target.Objects {
+		switch {
+		case object.Table != nil && object.Table.Schema != "":
+			objects = append(objects, fmt.Sprintf("TABLE %s",
+				pgx.Identifier{object.Table.Schema, object.Table.Name}.Sanitize()))
+		case object.Table != nil:
+			objects = append(objects, fmt.Sprintf("TABLE %s",
+				pgx.Identifier{object.Table.Name}.Sanitize()))
+		default:
+			objects = append(objects, fmt.Sprintf("TABLES IN SCHEMA %s",
+				pgx.Identifier{object.TablesInSchema}.Sanitize()))
+		}
+	}
+
+	return strings.Join(objects, ", ")
+}
+
+// toPublishOperationsSQL renders the value of the publish option
+func toPublishOperationsSQL(operations []apiv1.PublicationOperation) string {
+	values := make([]string, len(operations))
+	for idx, operation := range operations {
+		values[idx] = string(operation)
+	}
+
+	return pq.QuoteLiteral(strings.Join(values, ", "))
+}
@@ -0,0 +1,266 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Managed Publication SQL", func() {
+	const (
+		detectPublicationQuery = `SELECT puballtables, pubinsert, pubupdate, pubdelete, pubtruncate ` +
+			`FROM pg_catalog.pg_publication WHERE pubname = $1`
+		detectPublicationTablesQuery = `SELECT n.nspname, c.relname ` +
+			`FROM pg_catalog.pg_publication_rel pr ` +
+			`JOIN pg_catalog.pg_publication p ON p.oid = pr.prpubid ` +
+			`JOIN pg_catalog.pg_class c ON c.oid = pr.prrelid ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+			`WHERE p.pubname = $1`
+		detectPublicationSchemasQuery = `SELECT n.nspname ` +
+			`FROM pg_catalog.pg_publication_namespace pn ` +
+			`JOIN pg_catalog.pg_publication p ON p.oid = pn.pnpubid ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = pn.pnnspid ` +
+			`WHERE p.pubname = $1`
+		resolveTableSchemaQuery = `SELECT n.nspname FROM pg_catalog.pg_class c ` +
+			`JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace ` +
+			`WHERE c.oid = pg_catalog.to_regclass($1)`
+	)
+
+	publicationColumns := []string{"puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate"}
+
+	var (
+		dbMock      sqlmock.Sqlmock
+		db          *sql.DB
+		publication *apiv1.Publication
+		err         error
+	)
+
+	BeforeEach(func() {
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		publication = &apiv1.Publication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pub-one",
+				Namespace: "default",
+			},
+			Spec: apiv1.PublicationSpec{
+				ClusterRef: corev1.LocalObjectReference{Name: "cluster-example"},
+				Name:       "pub_one",
+				DBName:     "app",
+				Target: apiv1.PublicationTarget{
+					Objects: []apiv1.PublicationTargetObject{
+						{Table: &apiv1.PublicationTargetTable{Name: "orders"}},
+						{Table: &apiv1.PublicationTargetTable{Schema: "sales", Name: "customers"}},
+						{TablesInSchema: "inventory"},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	Context("detectPublication", func() {
+		It("detects an existing publication for all the tables", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectPublicationQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(true, true, true, false, false))
+
+			state, err := detectPublication(ctx, db, publication, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state).To(Equal(&publicationState{
+				allTables: true,
+				operations: []apiv1.PublicationOperation{
+					apiv1.PublicationOperationInsert,
+					apiv1.PublicationOperationUpdate,
+				},
+			}))
+		})
+
+		It("detects the tables and the schemas of an existing publication", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectPublicationQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(false, true, true, true, true))
+			dbMock.ExpectQuery(detectPublicationTablesQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname"}).
+					AddRow("sales", "customers").
+					AddRow("public", "orders"))
+			dbMock.ExpectQuery(detectPublicationSchemasQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("inventory"))
+
+			state, err := detectPublication(ctx, db, publication, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.allTables).To(BeFalse())
+			Expect(state.operations).To(Equal(allPublicationOperations))
+			Expect(state.tables).To(Equal([]string{`"public"."orders"`, `"sales"."customers"`}))
+			Expect(state.schemas).To(Equal([]string{`"inventory"`}))
+		})
+
+		It("doesn't detect the schemas when they are not supported", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectPublicationQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows(publicationColumns).AddRow(false, true, true, true, true))
+			dbMock.ExpectQuery(detectPublicationTablesQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows([]string{"nspname", "relname"}).AddRow("public", "orders"))
+
+			state, err := detectPublication(ctx, db, publication, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state.tables).To(Equal([]string{`"public"."orders"`}))
+			Expect(state.schemas).To(BeEmpty())
+		})
+
+		It("detects a missing publication", func(ctx SpecContext) {
+			dbMock.ExpectQuery(detectPublicationQuery).WithArgs("pub_one").
+				WillReturnRows(sqlmock.NewRows(publicationColumns))
+
+			state, err := detectPublication(ctx, db, publication, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(state).To(BeNil())
+		})
+	})
+
+	Context("createPublication", func() {
+		It("creates a publication for a list of objects", func(ctx SpecContext) {
+			dbMock.ExpectExec(`CREATE PUBLICATION "pub_one" FOR TABLE "orders", ` +
+				`TABLE "sales"."customers", TABLES IN SCHEMA "inventory"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(createPublication(ctx, db, publication)).To(Succeed())
+		})
+
+		It("creates a publication for all the tables, publishing only some operations", func(ctx SpecContext) {
+			publication.Spec.Target = apiv1.PublicationTarget{AllTables: true}
+			publication.Spec.Publish = []apiv1.PublicationOperation{
+				apiv1.PublicationOperationInsert,
+				apiv1.PublicationOperationUpdate,
+			}
+
+			dbMock.ExpectExec(`CREATE PUBLICATION "pub_one" FOR ALL TABLES WITH (publish = 'insert, update')`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(createPublication(ctx, db, publication)).To(Succeed())
+		})
+	})
+
+	Context("updatePublication", func() {
+		alignedState := func() *publicationState {
+			return &publicationState{
+				operations: allPublicationOperations,
+				tables:     []string{`"public"."orders"`, `"sales"."customers"`},
+				schemas:    []string{`"inventory"`},
+			}
+		}
+
+		It("doesn't alter a publication matching the specification", func(ctx SpecContext) {
+			dbMock.ExpectQuery(resolveTableSchemaQuery).WithArgs(`"orders"`).
+				WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+
+			Expect(updatePublication(ctx, db, publication, alignedState())).To(Succeed())
+		})
+
+		It("aligns the objects and the operations of the publication", func(ctx SpecContext) {
+			state := alignedState()
+			state.tables = []string{`"public"."orders"`}
+			state.operations = []apiv1.PublicationOperation{apiv1.PublicationOperationInsert}
+
+			dbMock.ExpectQuery(resolveTableSchemaQuery).WithArgs(`"orders"`).
+				WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+			dbMock.ExpectExec(`ALTER PUBLICATION "pub_one" SET TABLE "orders", ` +
+				`TABLE "sales"."customers", TABLES IN SCHEMA "inventory"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			dbMock.ExpectExec(`ALTER PUBLICATION "pub_one" SET (publish = 'insert, update, delete, truncate')`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(updatePublication(ctx, db, publication, state)).To(Succeed())
+		})
+
+		It("only aligns the operations of a publication for all the tables", func(ctx SpecContext) {
+			publication.Spec.Target = apiv1.PublicationTarget{AllTables: true}
+			publication.Spec.Publish = []apiv1.PublicationOperation{apiv1.PublicationOperationDelete}
+
+			dbMock.ExpectExec(`ALTER PUBLICATION "pub_one" SET (publish = 'delete')`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(updatePublication(ctx, db, publication, &publicationState{
+				allTables:  true,
+				operations: allPublicationOperations,
+			})).To(Succeed())
+		})
+
+		It("ignores the order of the published operations", func(ctx SpecContext) {
+			publication.Spec.Target = apiv1.PublicationTarget{AllTables: true}
+			publication.Spec.Publish = []apiv1.PublicationOperation{
+				apiv1.PublicationOperationUpdate,
+				apiv1.PublicationOperationInsert,
+			}
+
+			Expect(updatePublication(ctx, db, publication, &publicationState{
+				allTables: true,
+				operations: []apiv1.PublicationOperation{
+					apiv1.PublicationOperationInsert,
+					apiv1.PublicationOperationUpdate,
+				},
+			})).To(Succeed())
+		})
+
+		It("refuses to switch a publication to all the tables", func(ctx SpecContext) {
+			publication.Spec.Target = apiv1.PublicationTarget{AllTables: true}
+
+			Expect(updatePublication(ctx, db, publication, alignedState())).
+				To(MatchError(errPublicationAllTablesChanged))
+		})
+
+		It("should stop at the first error", func(ctx SpecContext) {
+			state := alignedState()
+			state.schemas = nil
+
+			dbMock.ExpectQuery(resolveTableSchemaQuery).WithArgs(`"orders"`).
+				WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+			dbMock.ExpectExec(`ALTER PUBLICATION "pub_one" SET TABLE "orders", ` +
+				`TABLE "sales"."customers", TABLES IN SCHEMA "inventory"`).
+				WillReturnError(fmt.Errorf("boom"))
+
+			Expect(updatePublication(ctx, db, publication, state)).ToNot(Succeed())
+		})
+	})
+
+	Context("hasTablesInSchema", func() {
+		It("detects the schemas in the target", func() {
+			Expect(hasTablesInSchema(&publication.Spec.Target)).To(BeTrue())
+			Expect(hasTablesInSchema(&apiv1.PublicationTarget{AllTables: true})).To(BeFalse())
+		})
+	})
+
+	Context("dropPublication", func() {
+		It("drops an existing publication", func(ctx SpecContext) {
+			dbMock.ExpectExec(`DROP PUBLICATION IF EXISTS "pub_one"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			Expect(dropPublication(ctx, db, publication)).To(Succeed())
+		})
+	})
+})
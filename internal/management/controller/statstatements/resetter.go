@@ -0,0 +1,195 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statstatements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// pollInterval is how often the Resetter looks whether a new
+// reset of the statistics is due
+const pollInterval = 30 * time.Second
+
+// A Resetter is a Kubernetes manager.Runnable that periodically resets the
+// pg_stat_statements statistics, following the schedule configured in the
+// cluster, so that the statements executed in fresh windows can be observed.
+// It only works on the primary instance, which records the result of each
+// reset in the status of the cluster.
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Resetter struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// The time of the last reset on this instance, used to avoid
+	// resetting the statistics again when the status couldn't be
+	// updated
+	lastRun time.Time
+}
+
+// NewResetter creates a new pg_stat_statements Resetter
+func NewResetter(instance *postgres.Instance, client client.Client) *Resetter {
+	return &Resetter{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts running the pg_stat_statements Resetter
+func (r *Resetter) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("pg_stat_statements_resetter")
+	ticker := time.NewTicker(pollInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated pg_stat_statements resetter loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := r.reconcile(log.IntoContext(ctx, contextLog)); err != nil {
+			contextLog.Error(err, "while resetting the pg_stat_statements statistics")
+		}
+	}
+}
+
+func (r *Resetter) reconcile(ctx context.Context) error {
+	contextLog := log.FromContext(ctx)
+
+	cachedCluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due, err := isResetDue(cachedCluster, r.lastRun, now)
+	if err != nil || !due {
+		return err
+	}
+
+	if cachedCluster.Status.CurrentPrimary != r.instance.PodName || r.instance.IsFenced() {
+		return nil
+	}
+	if isPrimary, err := r.instance.IsPrimary(); err != nil || !isPrimary {
+		return err
+	}
+
+	r.lastRun = now
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	status := &apiv1.StatStatementsResetStatus{
+		Phase:    apiv1.StatStatementsResetPhaseSucceeded,
+		Instance: r.instance.PodName,
+	}
+	statements, resetErr := resetStatistics(ctx, db)
+	resetAt := metav1.Now()
+	status.ResetAt = &resetAt
+	if resetErr != nil {
+		contextLog.Error(resetErr, "Scheduled reset of the pg_stat_statements statistics failed")
+		status.Phase = apiv1.StatStatementsResetPhaseFailed
+		status.Message = resetErr.Error()
+	} else {
+		contextLog.Info("Reset the pg_stat_statements statistics as scheduled", "statements", statements)
+		status.Statements = statements
+	}
+
+	var cluster apiv1.Cluster
+	if err := r.client.Get(
+		ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: r.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.StatStatementsReset = status
+	return r.client.Status().Patch(ctx, &cluster, client.MergeFrom(origCluster))
+}
+
+// isResetDue checks if the pg_stat_statements statistics should be reset.
+// The resets that have been missed, i.e. because the instance wasn't the
+// primary, are not recovered: we just wait for the next one.
+func isResetDue(cluster *apiv1.Cluster, lastRun time.Time, now time.Time) (bool, error) {
+	configuration := cluster.Spec.PostgresConfiguration.StatStatements
+	if !configuration.IsResetScheduled() {
+		return false, nil
+	}
+
+	schedule, err := cron.Parse(configuration.ResetSchedule)
+	if err != nil {
+		return false, fmt.Errorf("while parsing the reset schedule of pg_stat_statements: %w", err)
+	}
+
+	reference := cluster.CreationTimestamp.Time
+	if status := cluster.Status.StatStatementsReset; status != nil && status.ResetAt != nil {
+		reference = status.ResetAt.Time
+	}
+	if lastRun.After(reference) {
+		reference = lastRun
+	}
+
+	return !schedule.Next(reference).After(now), nil
+}
+
+// resetStatistics resets the pg_stat_statements statistics, returning
+// the number of statements that were tracked
+func resetStatistics(ctx context.Context, db *sql.DB) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var statements int64
+	if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM pg_stat_statements").Scan(&statements); err != nil {
+		return 0, fmt.Errorf("while counting the tracked statements: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_stat_statements_reset()"); err != nil {
+		return 0, fmt.Errorf("while resetting the statistics: %w", err)
+	}
+
+	return statements, tx.Commit()
+}
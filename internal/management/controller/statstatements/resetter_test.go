@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statstatements
+
+import (
+	"errors"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("isResetDue", func() {
+	var cluster *apiv1.Cluster
+	creationTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(creationTime),
+			},
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					StatStatements: &apiv1.StatStatementsConfiguration{
+						ResetSchedule: "0 0 * * * *",
+					},
+				},
+			},
+		}
+	})
+
+	It("is not due without a reset schedule", func() {
+		cluster.Spec.PostgresConfiguration.StatStatements.ResetSchedule = ""
+		Expect(isResetDue(cluster, time.Time{}, creationTime.Add(48*time.Hour))).To(BeFalse())
+	})
+
+	It("is due once the first scheduled time has been reached", func() {
+		Expect(isResetDue(cluster, time.Time{}, creationTime.Add(30*time.Minute))).To(BeFalse())
+		Expect(isResetDue(cluster, time.Time{}, creationTime.Add(time.Hour))).To(BeTrue())
+	})
+
+	It("waits for the next scheduled time after the last reset", func() {
+		resetAt := metav1.NewTime(creationTime.Add(time.Hour + time.Second))
+		cluster.Status.StatStatementsReset = &apiv1.StatStatementsResetStatus{
+			Phase:   apiv1.StatStatementsResetPhaseSucceeded,
+			ResetAt: &resetAt,
+		}
+		Expect(isResetDue(cluster, time.Time{}, creationTime.Add(90*time.Minute))).To(BeFalse())
+		Expect(isResetDue(cluster, time.Time{}, creationTime.Add(2*time.Hour))).To(BeTrue())
+	})
+
+	It("doesn't reset again when the status couldn't be updated", func() {
+		lastRun := creationTime.Add(time.Hour)
+		Expect(isResetDue(cluster, lastRun, creationTime.Add(90*time.Minute))).To(BeFalse())
+	})
+
+	It("reports an invalid schedule", func() {
+		cluster.Spec.PostgresConfiguration.StatStatements.ResetSchedule = "invalid"
+		_, err := isResetDue(cluster, time.Time{}, creationTime)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("resetStatistics", func() {
+	It("resets the statistics and reports the number of tracked statements", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count(*) FROM pg_stat_statements").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+		mock.ExpectExec("SELECT pg_stat_statements_reset()").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		Expect(resetStatistics(ctx, db)).To(BeEquivalentTo(42))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports the failure of the reset", func(ctx SpecContext) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT count(*) FROM pg_stat_statements").
+			WillReturnError(errors.New(`relation "pg_stat_statements" does not exist`))
+		mock.ExpectRollback()
+
+		_, err = resetStatistics(ctx, db)
+		Expect(err).To(MatchError(ContainSubstring("does not exist")))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
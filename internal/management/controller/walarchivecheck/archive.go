@@ -0,0 +1,175 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchivecheck
+
+import (
+	"context"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// walArchive is a WAL archive whose content can be checked
+type walArchive interface {
+	// contains checks which of the passed WAL files are in the archive,
+	// returning the outcome in the same order
+	contains(ctx context.Context, walNames []string) ([]bool, error)
+}
+
+// listedArchive is a WAL archive whose content has been listed
+// in advance, without downloading the WAL files
+type listedArchive struct {
+	walNames *stringset.Data
+}
+
+// newListedArchive creates a WAL archive containing the passed WAL files
+func newListedArchive(walNames []string) *listedArchive {
+	return &listedArchive{
+		walNames: stringset.From(walNames),
+	}
+}
+
+// contains implements the walArchive interface
+func (archive *listedArchive) contains(_ context.Context, walNames []string) ([]bool, error) {
+	found := make([]bool, len(walNames))
+	for idx, walName := range walNames {
+		found[idx] = archive.walNames.Has(walName)
+	}
+
+	return found, nil
+}
+
+// checkResult is the outcome of a check of the WAL archive
+type checkResult struct {
+	// The last WAL file found in the archive with no gaps before
+	// it, empty if no WAL file has been checked
+	lastCheckedWAL string
+
+	// The first WAL file missing from the archive, if any
+	missingWAL string
+}
+
+// findGap looks for the first WAL file missing from the archive between
+// the from and to segments, both included, checking batchSize WAL files
+// at a time. When a WAL file is missing in the timeline being followed,
+// the same segment is searched in the following timelines, up to the one
+// of the to segment, as it happens after a promotion.
+func findGap(
+	ctx context.Context,
+	archive walArchive,
+	from, to postgres.Segment,
+	batchSize int,
+	segmentSize int64,
+) (checkResult, error) {
+	var result checkResult
+
+	current := from
+NextBatch:
+	for !isAfter(current, to) {
+		batch := current.NextSegments(batchSize, nil, &segmentSize)
+		for idx := range batch {
+			if isAfter(batch[idx], to) {
+				batch = batch[:idx]
+				break
+			}
+		}
+
+		walNames := make([]string, len(batch))
+		for idx := range batch {
+			walNames[idx] = batch[idx].Name()
+		}
+
+		found, err := archive.contains(ctx, walNames)
+		if err != nil {
+			return result, err
+		}
+
+		for idx, segment := range batch {
+			if found[idx] {
+				result.lastCheckedWAL = walNames[idx]
+				continue
+			}
+
+			newerSegment, err := findInNewerTimelines(ctx, archive, segment, to.Tli)
+			if err != nil {
+				return result, err
+			}
+			if newerSegment == nil {
+				result.missingWAL = walNames[idx]
+				return result, nil
+			}
+
+			// The following segments are searched in the new timeline
+			result.lastCheckedWAL = newerSegment.Name()
+			current = nextSegment(*newerSegment, segmentSize)
+			continue NextBatch
+		}
+
+		current = nextSegment(batch[len(batch)-1], segmentSize)
+	}
+
+	return result, nil
+}
+
+// findInNewerTimelines looks for the passed segment in the timelines
+// following its own one, up to maxTimeline, returning nil if not found
+func findInNewerTimelines(
+	ctx context.Context,
+	archive walArchive,
+	segment postgres.Segment,
+	maxTimeline int32,
+) (*postgres.Segment, error) {
+	for tli := segment.Tli + 1; tli <= maxTimeline; tli++ {
+		candidate := postgres.Segment{Tli: tli, Log: segment.Log, Seg: segment.Seg}
+		found, err := archive.contains(ctx, []string{candidate.Name()})
+		if err != nil {
+			return nil, err
+		}
+		if found[0] {
+			return &candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// limitCheckRange limits the range of the segments to be checked to the
+// first maxSegments ones, keeping the timeline of the last segment so that
+// the timeline changes are still followed
+func limitCheckRange(from, to postgres.Segment, maxSegments int, segmentSize int64) postgres.Segment {
+	segments := from.NextSegments(maxSegments, nil, &segmentSize)
+	last := segments[len(segments)-1]
+	if !isAfter(to, last) {
+		return to
+	}
+
+	return postgres.Segment{Tli: to.Tli, Log: last.Log, Seg: last.Seg}
+}
+
+// nextSegment gets the segment following the passed one in the same timeline
+func nextSegment(segment postgres.Segment, segmentSize int64) postgres.Segment {
+	return segment.NextSegments(2, nil, &segmentSize)[1]
+}
+
+// isAfter checks if the position of the segment a in the WAL stream
+// follows the one of b, without considering the timeline
+func isAfter(a, b postgres.Segment) bool {
+	if a.Log != b.Log {
+		return a.Log > b.Log
+	}
+	return a.Seg > b.Seg
+}
@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchivecheck
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeArchive is a WAL archive containing a fixed set of WAL files
+type fakeArchive struct {
+	walFiles map[string]bool
+	requests int
+	err      error
+}
+
+func newFakeArchive(walNames ...string) *fakeArchive {
+	archive := &fakeArchive{walFiles: make(map[string]bool)}
+	for _, walName := range walNames {
+		archive.walFiles[walName] = true
+	}
+	return archive
+}
+
+func (archive *fakeArchive) contains(_ context.Context, walNames []string) ([]bool, error) {
+	archive.requests++
+	if archive.err != nil {
+		return nil, archive.err
+	}
+
+	result := make([]bool, len(walNames))
+	for idx, walName := range walNames {
+		result[idx] = archive.walFiles[walName]
+	}
+	return result, nil
+}
+
+var _ = Describe("findGap", func() {
+	segmentSize := postgres.DefaultWALSegmentSize
+
+	It("accepts a contiguous archive", func(ctx SpecContext) {
+		archive := newFakeArchive(
+			"0000000100000000000000FE",
+			"0000000100000000000000FF",
+			"000000010000000100000000",
+			"000000010000000100000001",
+		)
+		result, err := findGap(ctx, archive,
+			postgres.MustSegmentFromName("0000000100000000000000FE"),
+			postgres.MustSegmentFromName("000000010000000100000001"),
+			3, segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.missingWAL).To(BeEmpty())
+		Expect(result.lastCheckedWAL).To(Equal("000000010000000100000001"))
+		Expect(archive.requests).To(Equal(2))
+	})
+
+	It("detects the first missing WAL file", func(ctx SpecContext) {
+		archive := newFakeArchive(
+			"000000010000000000000001",
+			"000000010000000000000002",
+			"000000010000000000000004",
+		)
+		result, err := findGap(ctx, archive,
+			postgres.MustSegmentFromName("000000010000000000000001"),
+			postgres.MustSegmentFromName("000000010000000000000004"),
+			10, segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.missingWAL).To(Equal("000000010000000000000003"))
+		Expect(result.lastCheckedWAL).To(Equal("000000010000000000000002"))
+	})
+
+	It("follows the timeline changes", func(ctx SpecContext) {
+		archive := newFakeArchive(
+			"000000010000000000000001",
+			"000000010000000000000002",
+			"000000030000000000000003",
+			"000000030000000000000004",
+		)
+		result, err := findGap(ctx, archive,
+			postgres.MustSegmentFromName("000000010000000000000001"),
+			postgres.MustSegmentFromName("000000030000000000000004"),
+			2, segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.missingWAL).To(BeEmpty())
+		Expect(result.lastCheckedWAL).To(Equal("000000030000000000000004"))
+	})
+
+	It("does nothing when there are no WAL files to check", func(ctx SpecContext) {
+		archive := newFakeArchive()
+		result, err := findGap(ctx, archive,
+			postgres.MustSegmentFromName("000000010000000000000005"),
+			postgres.MustSegmentFromName("000000010000000000000004"),
+			1, segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(checkResult{}))
+		Expect(archive.requests).To(BeZero())
+	})
+
+	It("fails when the archive can't be accessed", func(ctx SpecContext) {
+		archive := newFakeArchive()
+		archive.err = errors.New("connection refused")
+		_, err := findGap(ctx, archive,
+			postgres.MustSegmentFromName("000000010000000000000001"),
+			postgres.MustSegmentFromName("000000010000000000000004"),
+			1, segmentSize)
+		Expect(err).To(MatchError("connection refused"))
+	})
+})
+
+var _ = Describe("listedArchive", func() {
+	It("checks the presence of the WAL files in the list", func(ctx SpecContext) {
+		archive := newListedArchive([]string{
+			"000000010000000000000001",
+			"000000010000000000000003",
+		})
+		found, err := archive.contains(ctx, []string{
+			"000000010000000000000001",
+			"000000010000000000000002",
+			"000000010000000000000003",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal([]bool{true, false, true}))
+	})
+})
+
+var _ = Describe("limitCheckRange", func() {
+	segmentSize := postgres.DefaultWALSegmentSize
+
+	It("keeps a range shorter than the limit", func() {
+		to := postgres.MustSegmentFromName("000000010000000000000004")
+		Expect(limitCheckRange(
+			postgres.MustSegmentFromName("000000010000000000000001"),
+			to, 10, segmentSize)).To(Equal(to))
+	})
+
+	It("limits a longer range keeping the last timeline", func() {
+		Expect(limitCheckRange(
+			postgres.MustSegmentFromName("0000000100000000000000FE"),
+			postgres.MustSegmentFromName("000000030000000200000000"),
+			4, segmentSize).Name()).To(Equal("000000030000000100000001"))
+	})
+})
@@ -0,0 +1,308 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchivecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// maxCheckedWALs is the maximum number of WAL files checked every
+	// time, so that a long WAL archive is checked in several runs
+	maxCheckedWALs = 4096
+
+	// checkBatchSize is the number of WAL files looked up together
+	// in the list of the archived ones
+	checkBatchSize = 64
+
+	// pollInterval is how often the Checker looks whether a new
+	// check of the WAL archive is due
+	pollInterval = time.Minute
+)
+
+// A Checker is a Kubernetes manager.Runnable that periodically looks for
+// gaps in the WAL archive of the cluster, between the beginning of the
+// oldest base backup and the last archived WAL file. It only works on the
+// primary instance.
+//
+// c.f. https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Checker struct {
+	instance *postgres.Instance
+	client   client.Client
+
+	// The time of the last attempt, used to avoid retrying
+	// a failing check more often than requested
+	lastAttempt time.Time
+}
+
+// NewChecker creates a new WAL archive Checker
+func NewChecker(instance *postgres.Instance, client client.Client) *Checker {
+	return &Checker{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts running the WAL archive Checker
+func (c *Checker) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("wal_archive_checker")
+	ticker := time.NewTicker(pollInterval)
+
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated WAL archive checker loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := c.reconcile(ctx); err != nil {
+			contextLog.Error(err, "while checking the WAL archive")
+		}
+	}
+}
+
+func (c *Checker) reconcile(ctx context.Context) error {
+	cachedCluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !isCheckDue(cachedCluster, c.lastAttempt, now) {
+		return nil
+	}
+
+	if cachedCluster.Status.CurrentPrimary != c.instance.PodName || c.instance.IsFenced() {
+		return nil
+	}
+	if isPrimary, err := c.instance.IsPrimary(); err != nil || !isPrimary {
+		return err
+	}
+
+	c.lastAttempt = now
+
+	var cluster apiv1.Cluster
+	if err := c.client.Get(
+		ctx,
+		client.ObjectKey{Namespace: c.instance.Namespace, Name: c.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	result, err := c.check(ctx, &cluster)
+	if err != nil {
+		return err
+	}
+
+	return registerCheckResult(ctx, c.client, &cluster, result)
+}
+
+// check looks for gaps in the WAL archive, starting after the
+// last checked WAL file
+func (c *Checker) check(ctx context.Context, cluster *apiv1.Cluster) (*checkResult, error) {
+	contextLog := log.FromContext(ctx)
+
+	env, err := cache.LoadEnv(cache.WALArchiveKey)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the credentials of the WAL archive: %w", err)
+	}
+
+	objectStore := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.Name
+	if objectStore.ServerName != "" {
+		serverName = objectStore.ServerName
+	}
+
+	backupCatalog, err := barman.GetBackupList(ctx, objectStore, serverName, env)
+	if err != nil {
+		return nil, err
+	}
+	firstBackup := backupCatalog.FirstBackupInfo()
+	if firstBackup == nil {
+		contextLog.Info("No base backup found, skipping the WAL archive check")
+		return nil, nil
+	}
+
+	lastArchivedWAL, segmentSize, err := c.getArchiverStatus()
+	if err != nil {
+		return nil, err
+	}
+	if !postgresSpec.IsWALFile(lastArchivedWAL) {
+		// This happens when no WAL file has been archived yet, or when the
+		// last archived file is a history or a partial one, i.e. just after
+		// a promotion. We'll try again the next time.
+		contextLog.Info("The last archived file is not a WAL file, skipping the WAL archive check",
+			"lastArchivedWAL", lastArchivedWAL)
+		return nil, nil
+	}
+
+	from, to, err := getCheckRange(cluster.Status.WalArchiveCheck, firstBackup.BeginWal, lastArchivedWAL, segmentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	to = limitCheckRange(from, to, maxCheckedWALs, segmentSize)
+
+	walNames, err := barman.GetWALList(ctx, objectStore, serverName, env)
+	if err != nil {
+		return nil, err
+	}
+
+	contextLog.Info("Checking the WAL archive",
+		"from", from.Name(),
+		"to", to.Name(),
+		"archivedWALs", len(walNames))
+	result, err := findGap(ctx, newListedArchive(walNames), from, to, checkBatchSize, segmentSize)
+	if err != nil {
+		return nil, err
+	}
+	if result.missingWAL != "" {
+		contextLog.Warning("Gap detected in the WAL archive", "missingWAL", result.missingWAL)
+	}
+
+	return &result, nil
+}
+
+// getArchiverStatus gets the name of the last archived WAL file,
+// together with the size of the WAL segments
+func (c *Checker) getArchiverStatus() (string, int64, error) {
+	db, err := c.instance.GetSuperUserDB()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var lastArchivedWAL string
+	var segmentSize int64
+	row := db.QueryRow(
+		"SELECT COALESCE(last_archived_wal, ''), " +
+			"(SELECT setting::bigint FROM pg_catalog.pg_settings WHERE name = 'wal_segment_size') " +
+			"FROM pg_catalog.pg_stat_archiver")
+	if err := row.Scan(&lastArchivedWAL, &segmentSize); err != nil {
+		return "", 0, fmt.Errorf("while reading the status of the WAL archiver: %w", err)
+	}
+
+	return lastArchivedWAL, segmentSize, nil
+}
+
+// isCheckDue checks if a new check of the WAL archive is required
+func isCheckDue(cluster *apiv1.Cluster, lastAttempt time.Time, now time.Time) bool {
+	if !cluster.Spec.Backup.IsWalArchiveCheckEnabled() {
+		return false
+	}
+
+	lastCheck := lastAttempt
+	if status := cluster.Status.WalArchiveCheck; status != nil &&
+		status.LastCheckTime != nil && status.LastCheckTime.Time.After(lastCheck) {
+		lastCheck = status.LastCheckTime.Time
+	}
+
+	return !now.Before(lastCheck.Add(cluster.Spec.Backup.GetWalArchiveCheckInterval()))
+}
+
+// getCheckRange gets the segments to be checked, starting from the one
+// following the last checked WAL file, or from the beginning of the oldest
+// base backup if it is more recent, up to the last archived WAL file
+func getCheckRange(
+	status *apiv1.WalArchiveCheckStatus,
+	firstBackupWAL string,
+	lastArchivedWAL string,
+	segmentSize int64,
+) (from, to postgresSpec.Segment, err error) {
+	if from, err = postgresSpec.SegmentFromName(firstBackupWAL); err != nil {
+		return from, to, fmt.Errorf("while parsing the first WAL of the oldest base backup: %w", err)
+	}
+
+	if to, err = postgresSpec.SegmentFromName(lastArchivedWAL); err != nil {
+		return from, to, fmt.Errorf("while parsing the last archived WAL: %w", err)
+	}
+
+	if status == nil || status.LastCheckedWAL == "" {
+		return from, to, nil
+	}
+
+	lastChecked, err := postgresSpec.SegmentFromName(status.LastCheckedWAL)
+	if err != nil {
+		// We'll just check everything again
+		return from, to, nil
+	}
+
+	if next := nextSegment(lastChecked, segmentSize); isAfter(next, from) {
+		from = next
+	}
+
+	return from, to, nil
+}
+
+// registerCheckResult stores the outcome of the check in the cluster
+// status, together with the corresponding condition
+func registerCheckResult(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	result *checkResult,
+) error {
+	if result == nil {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+
+	status := &apiv1.WalArchiveCheckStatus{}
+	if cluster.Status.WalArchiveCheck != nil {
+		status = cluster.Status.WalArchiveCheck.DeepCopy()
+	}
+	if result.lastCheckedWAL != "" {
+		status.LastCheckedWAL = result.lastCheckedWAL
+	}
+	status.MissingWAL = result.missingWAL
+	now := metav1.Now()
+	status.LastCheckTime = &now
+	cluster.Status.WalArchiveCheck = status
+
+	if err := cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	condition := apiv1.WALArchiveContiguousCondition
+	if result.missingWAL != "" {
+		condition = apiv1.BuildWALArchiveGapDetectedCondition(result.missingWAL)
+	}
+	return conditions.Patch(ctx, cli, cluster, condition)
+}
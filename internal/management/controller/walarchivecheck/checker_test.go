@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchivecheck
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("getCheckRange", func() {
+	segmentSize := postgres.DefaultWALSegmentSize
+
+	It("starts from the oldest base backup on the first check", func() {
+		from, to, err := getCheckRange(nil, "000000010000000000000002", "000000020000000000000008", segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(from.Name()).To(Equal("000000010000000000000002"))
+		Expect(to.Name()).To(Equal("000000020000000000000008"))
+	})
+
+	It("starts after the last checked WAL file", func() {
+		status := &apiv1.WalArchiveCheckStatus{LastCheckedWAL: "000000010000000000000005"}
+		from, _, err := getCheckRange(status, "000000010000000000000002", "000000010000000000000008", segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(from.Name()).To(Equal("000000010000000000000006"))
+	})
+
+	It("starts from the oldest base backup when the older ones have been removed", func() {
+		status := &apiv1.WalArchiveCheckStatus{LastCheckedWAL: "000000010000000000000005"}
+		from, _, err := getCheckRange(status, "000000010000000000000007", "000000010000000000000008", segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(from.Name()).To(Equal("000000010000000000000007"))
+	})
+})
+
+var _ = Describe("isCheckDue", func() {
+	var cluster *apiv1.Cluster
+	now := time.Now()
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						BarmanCredentials: apiv1.BarmanCredentials{
+							AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+						},
+					},
+					WalArchiveCheck: &apiv1.WalArchiveCheckConfiguration{
+						Enabled:  true,
+						Interval: 600,
+					},
+				},
+			},
+		}
+	})
+
+	It("is not due when the check is disabled", func() {
+		cluster.Spec.Backup.WalArchiveCheck.Enabled = false
+		Expect(isCheckDue(cluster, time.Time{}, now)).To(BeFalse())
+	})
+
+	It("is due when the archive has never been checked", func() {
+		Expect(isCheckDue(cluster, time.Time{}, now)).To(BeTrue())
+	})
+
+	It("waits for the interval since the last check", func() {
+		cluster.Status.WalArchiveCheck = &apiv1.WalArchiveCheckStatus{
+			LastCheckTime: &metav1.Time{Time: now.Add(-5 * time.Minute)},
+		}
+		Expect(isCheckDue(cluster, time.Time{}, now)).To(BeFalse())
+		Expect(isCheckDue(cluster, time.Time{}, now.Add(5*time.Minute))).To(BeTrue())
+	})
+
+	It("waits for the interval since the last failed attempt", func() {
+		Expect(isCheckDue(cluster, now.Add(-time.Minute), now)).To(BeFalse())
+	})
+})
+
+var _ = Describe("registerCheckResult", func() {
+	var (
+		cli     client.Client
+		cluster *apiv1.Cluster
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Status: apiv1.ClusterStatus{
+				WalArchiveCheck: &apiv1.WalArchiveCheckStatus{
+					LastCheckedWAL: "000000010000000000000002",
+				},
+			},
+		}
+		scheme := schemeBuilder.BuildWithAllKnownScheme()
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+	})
+
+	It("reports a contiguous archive", func(ctx SpecContext) {
+		Expect(registerCheckResult(ctx, cli, cluster, &checkResult{
+			lastCheckedWAL: "000000010000000000000005",
+		})).To(Succeed())
+
+		var updatedCluster apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.WalArchiveCheck.LastCheckedWAL).To(Equal("000000010000000000000005"))
+		Expect(updatedCluster.Status.WalArchiveCheck.MissingWAL).To(BeEmpty())
+		Expect(updatedCluster.Status.WalArchiveCheck.LastCheckTime).ToNot(BeNil())
+		Expect(meta.IsStatusConditionTrue(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionWALArchiveContiguous))).To(BeTrue())
+	})
+
+	It("reports a gap in the archive", func(ctx SpecContext) {
+		Expect(registerCheckResult(ctx, cli, cluster, &checkResult{
+			missingWAL: "000000010000000000000003",
+		})).To(Succeed())
+
+		var updatedCluster apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		Expect(updatedCluster.Status.WalArchiveCheck.LastCheckedWAL).To(Equal("000000010000000000000002"))
+		Expect(updatedCluster.Status.WalArchiveCheck.MissingWAL).To(Equal("000000010000000000000003"))
+		condition := meta.FindStatusCondition(updatedCluster.Status.Conditions,
+			string(apiv1.ConditionWALArchiveContiguous))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonWALArchiveGapDetected)))
+	})
+})
@@ -35,6 +35,7 @@ type PgBouncerInstanceInterface interface {
 	Pause() error
 	Resume() error
 	Reload() error
+	Reconnect() error
 }
 
 // NewPgBouncerInstance initializes a new pgBouncerInstance
@@ -143,3 +144,19 @@ func (p *pgBouncerInstance) Reload() error {
 
 	return nil
 }
+
+// Reconnect issues a RECONNECT command to the PgBouncer instance, closing
+// every server connection as soon as it is released, returning any error
+func (p *pgBouncerInstance) Reconnect() error {
+	db, err := p.pool.Connection("pgbouncer")
+	if err != nil {
+		return fmt.Errorf("while connecting to pgbouncer database locally: %w", err)
+	}
+
+	_, err = db.Exec("RECONNECT")
+	if err != nil {
+		return fmt.Errorf("while reconnecting server connections: %w", err)
+	}
+
+	return nil
+}
@@ -88,6 +88,21 @@ var _ = Describe("PgBouncerInstance", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("when the server connections are reconnected", func() {
+		It("should not return an error", func() {
+			mock.ExpectExec("RECONNECT").WillReturnResult(sqlmock.NewResult(1, 1))
+
+			pgBouncerInstance := &pgBouncerInstance{
+				mu:     &sync.RWMutex{},
+				paused: false,
+				pool:   &fakePooler{DB: db},
+			}
+
+			err := pgBouncerInstance.Reconnect()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
 
 type fakePooler struct {
@@ -45,6 +45,10 @@ type PgBouncerReconciler struct {
 	poolerWatch          watch.Interface
 	instance             PgBouncerInstanceInterface
 	poolerNamespacedName types.NamespacedName
+
+	// The version of the auth query secret used by the
+	// latest configuration that has been written
+	authQuerySecretVersion *apiv1.SecretVersion
 }
 
 // NewPgBouncerReconciler creates a new pgbouncer reconciler
@@ -166,6 +170,7 @@ func (r *PgBouncerReconciler) synchronizeConfig(ctx context.Context, pooler *api
 	if configurationChanged, err = r.writePgBouncerConfig(ctx, pooler); err != nil {
 		return fmt.Errorf("while writing PgBouncer configuration: %w", err)
 	}
+	authQuerySecretRotated := r.trackAuthQuerySecretVersion(pooler)
 
 	if !configurationChanged {
 		return nil
@@ -175,9 +180,33 @@ func (r *PgBouncerReconciler) synchronizeConfig(ctx context.Context, pooler *api
 		return fmt.Errorf("while reloading configuration due to change: %w", err)
 	}
 
+	// The server connections opened with the former credentials are
+	// closed as soon as they are released, to be opened again
+	// with the rotated ones
+	if authQuerySecretRotated {
+		if err = r.instance.Reconnect(); err != nil {
+			return fmt.Errorf("while reconnecting after the rotation of the auth query secret: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// trackAuthQuerySecretVersion stores the version of the auth query secret
+// used by the configuration, returning true when it has been changed
+// since the previous configuration
+func (r *PgBouncerReconciler) trackAuthQuerySecretVersion(pooler *apiv1.Pooler) bool {
+	if pooler.Status.Secrets == nil || pooler.Status.Secrets.PgBouncerSecrets == nil {
+		return false
+	}
+
+	version := pooler.Status.Secrets.PgBouncerSecrets.AuthQuery
+	previousVersion := r.authQuerySecretVersion
+	r.authQuerySecretVersion = &version
+
+	return previousVersion != nil && *previousVersion != version
+}
+
 // writePgBouncerConfig writes the PgBouncer configuration files given the Pooler
 // specification, returning a boolean flag indicating if the configuration has
 // changed or not
@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("auth query secret rotation", func() {
+	buildPooler := func(version string) *apiv1.Pooler {
+		return &apiv1.Pooler{
+			Status: apiv1.PoolerStatus{
+				Secrets: &apiv1.PoolerSecrets{
+					PgBouncerSecrets: &apiv1.PgBouncerSecrets{
+						AuthQuery: apiv1.SecretVersion{Name: "auth", Version: version},
+					},
+				},
+			},
+		}
+	}
+
+	It("detects when the auth query secret changes", func() {
+		r := &PgBouncerReconciler{}
+
+		Expect(r.trackAuthQuerySecretVersion(buildPooler("1"))).To(BeFalse())
+		Expect(r.trackAuthQuerySecretVersion(buildPooler("1"))).To(BeFalse())
+		Expect(r.trackAuthQuerySecretVersion(buildPooler("2"))).To(BeTrue())
+		Expect(r.trackAuthQuerySecretVersion(buildPooler("2"))).To(BeFalse())
+	})
+
+	It("ignores poolers whose status is not populated yet", func() {
+		r := &PgBouncerReconciler{}
+		Expect(r.trackAuthQuerySecretVersion(&apiv1.Pooler{})).To(BeFalse())
+		Expect(r.authQuerySecretVersion).To(BeNil())
+	})
+})
@@ -91,6 +91,23 @@ func (pair KeyPair) ParseECPrivateKey() (*ecdsa.PrivateKey, error) {
 	return x509.ParseECPrivateKey(block.Bytes)
 }
 
+// RenewPrivateKey replaces the private key stored in the pair with a new
+// one. The certificate must be renewed afterwards, as it doesn't match it
+func (pair *KeyPair) RenewPrivateKey() error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	privateKeyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	pair.Private = encodePrivateKey(privateKeyBytes)
+	return nil
+}
+
 // ParseCertificate parse certificate stored in the pair
 func (pair KeyPair) ParseCertificate() (*x509.Certificate, error) {
 	block, _ := pem.Decode(pair.Certificate)
@@ -289,15 +306,17 @@ func (pair *KeyPair) RenewCertificate(
 	newCertificate.SerialNumber = serialNumber
 	newCertificate.DNSNames = altDNSNames
 
-	if parentCertificate == nil {
-		parentCertificate = &newCertificate
-	}
-
 	tlsPrivateKey, err := pair.ParseECPrivateKey()
 	if err != nil {
 		return err
 	}
 
+	if parentCertificate == nil {
+		// The private key may have been renewed too
+		newCertificate.PublicKey = &tlsPrivateKey.PublicKey
+		parentCertificate = &newCertificate
+	}
+
 	certificateBytes, err := x509.CreateCertificate(
 		rand.Reader,
 		&newCertificate,
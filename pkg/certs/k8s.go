@@ -107,30 +107,62 @@ func RenewLeafCertificate(caSecret *v1.Secret, secret *v1.Secret, altDNSNames []
 		return false, nil
 	}
 
+	if err := renewLeafKeyPair(caSecret, secret, pair, altDNSNames); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ForceRenewLeafCertificate renew a secret containing a leaf
+// certificate given the secret containing the CA that will sign it,
+// even if the certificate is not expiring. A new private key is
+// generated, so that the previous one can't be used anymore
+func ForceRenewLeafCertificate(caSecret *v1.Secret, secret *v1.Secret, altDNSNames []string) error {
+	pair, err := ParseServerSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	if err := pair.RenewPrivateKey(); err != nil {
+		return err
+	}
+
+	if err := renewLeafKeyPair(caSecret, secret, pair, altDNSNames); err != nil {
+		return err
+	}
+
+	secret.Data[TLSPrivateKeyKey] = pair.Private
+	return nil
+}
+
+// renewLeafKeyPair renews the certificate of the passed pair, signing it
+// with the passed CA, and stores it in the secret
+func renewLeafKeyPair(caSecret *v1.Secret, secret *v1.Secret, pair *KeyPair, altDNSNames []string) error {
 	// Parse the CA secret to get the private key
 	caPair, err := ParseCASecret(caSecret)
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	caPrivateKey, err := caPair.ParseECPrivateKey()
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	caCertificate, err := caPair.ParseCertificate()
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	err = pair.RenewCertificate(caPrivateKey, caCertificate, altDNSNames)
 	if err != nil {
-		return false, err
+		return err
 	}
 
 	secret.Data["tls.crt"] = pair.Certificate
 
-	return true, nil
+	return nil
 }
 
 // Setup ensures that we have the required PKI infrastructure to make the operator and the clusters working
@@ -371,3 +371,31 @@ var _ = Describe("Webhook environment creation", func() {
 		Expect(updatedValidatingWebhook.Webhooks[0].ClientConfig.CABundle).To(Equal(webhookSecret.Data["tls.crt"]))
 	})
 })
+
+var _ = Describe("Leaf certificate renewal", func() {
+	It("renews a valid certificate only when forced", func() {
+		ca, err := CreateRootCA("root", "namespace")
+		Expect(err).ToNot(HaveOccurred())
+		caSecret := ca.GenerateCASecret("namespace", "ca")
+
+		altDNSNames := []string{"this.host.name.com"}
+		pair, err := ca.CreateAndSignPair("this.host.name.com", CertTypeServer, altDNSNames)
+		Expect(err).ToNot(HaveOccurred())
+		secret := pair.GenerateCertificateSecret("namespace", "server")
+		originalCert, err := pair.ParseCertificate()
+		Expect(err).ToNot(HaveOccurred())
+
+		renewed, err := RenewLeafCertificate(caSecret, secret, altDNSNames)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewed).To(BeFalse())
+
+		Expect(ForceRenewLeafCertificate(caSecret, secret, altDNSNames)).To(Succeed())
+		renewedPair, err := ParseServerSecret(secret)
+		Expect(err).ToNot(HaveOccurred())
+		renewedCert, err := renewedPair.ParseCertificate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renewedCert.SerialNumber).ToNot(Equal(originalCert.SerialNumber))
+		Expect(renewedCert.Subject).To(Equal(originalCert.Subject))
+		Expect(renewedPair.Private).ToNot(Equal(pair.Private))
+	})
+})
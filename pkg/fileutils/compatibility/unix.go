@@ -21,6 +21,7 @@ limitations under the License.
 package compatibility
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"syscall"
@@ -47,3 +48,31 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 func Umask(mask int) int {
 	return unix.Umask(mask)
 }
+
+// GetFilesystemUsage invokes the Unix system call Statfs, returning the size
+// of the filesystem containing the given path, the space used and the space
+// available to unprivileged users, in bytes
+func GetFilesystemUsage(path string) (total, used, available uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize) //nolint:gosec
+	total = stat.Blocks * blockSize
+	used = (stat.Blocks - stat.Bfree) * blockSize
+	available = stat.Bavail * blockSize
+	return total, used, available, nil
+}
+
+// TryLockFile takes an exclusive advisory lock on the passed file, without
+// waiting, returning false if another open file holds it. The lock is
+// released when the file is closed, even if the process crashes
+func TryLockFile(file *os.File) (bool, error) {
+	err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB) //nolint:gosec
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -22,6 +22,7 @@ package compatibility
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 )
 
@@ -39,3 +40,13 @@ func AddInstanceRunCommands(cmd *exec.Cmd) {
 func Umask(mask int) int {
 	return mask
 }
+
+// GetFilesystemUsage fakes function for cross-compiling compatibility
+func GetFilesystemUsage(path string) (total, used, available uint64, err error) {
+	return 0, 0, 0, fmt.Errorf("function GetFilesystemUsage() is not supported in Windows")
+}
+
+// TryLockFile fakes function for cross-compiling compatibility
+func TryLockFile(file *os.File) (bool, error) {
+	return false, fmt.Errorf("function TryLockFile() is not supported in Windows")
+}
@@ -519,6 +519,48 @@ func MoveDirectoryContent(sourceDirectory, destinationDirectory string) error {
 	return RemoveDirectoryContent(sourceDirectory)
 }
 
+// CopyDirectory copies a directory recursively into the destination,
+// which is created if needed, preserving the permissions of the files
+// and the symbolic links
+func CopyDirectory(sourceDirectory, destinationDirectory string) error {
+	return filepath.WalkDir(sourceDirectory, func(sourcePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDirectory, sourcePath)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destinationDirectory, relativePath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			// The owner needs to be able to write the content
+			// of the directory while copying it
+			return os.MkdirAll(destinationPath, info.Mode().Perm()|0o700)
+
+		case entry.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(sourcePath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, destinationPath)
+
+		default:
+			if err := CopyFile(sourcePath, destinationPath); err != nil {
+				return err
+			}
+			return os.Chmod(destinationPath, info.Mode().Perm())
+		}
+	})
+}
+
 // GetFileSize returns the size of a file or an error
 func GetFileSize(fileName string) (int64, error) {
 	stat, err := os.Stat(fileName)
@@ -237,6 +237,30 @@ var _ = Describe("function MoveDirectoryContent", func() {
 	})
 })
 
+var _ = Describe("function CopyDirectory", func() {
+	It("copies a directory recursively, preserving permissions and symlinks", func() {
+		sourceDir := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(sourceDir, "bin"), 0o750)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sourceDir, "bin", "postgres"), []byte("binary"), 0o750)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(sourceDir, "bin", "notes"), []byte("text"), 0o600)).To(Succeed())
+		Expect(os.Symlink("postgres", filepath.Join(sourceDir, "bin", "postmaster"))).To(Succeed())
+
+		destinationDir := filepath.Join(GinkgoT().TempDir(), "copy")
+		Expect(CopyDirectory(sourceDir, destinationDir)).To(Succeed())
+
+		info, err := os.Stat(filepath.Join(destinationDir, "bin", "postgres"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o750)))
+		Expect(os.ReadFile(filepath.Join(destinationDir, "bin", "notes"))).To(BeEquivalentTo("text"))
+		Expect(os.Readlink(filepath.Join(destinationDir, "bin", "postmaster"))).To(Equal("postgres"))
+	})
+
+	It("returns error if the source directory doesn't exist", func() {
+		Expect(CopyDirectory(filepath.Join(GinkgoT().TempDir(), "not-exists"), GinkgoT().TempDir())).
+			ToNot(Succeed())
+	})
+})
+
 var _ = Describe("RemoveFiles", func() {
 	var tempDir string
 
@@ -271,11 +271,9 @@ func (archiver *WALArchiver) CheckWalArchiveDestination(ctx context.Context, opt
 // BarmanCloudCheckWalArchiveOptions create the options needed for the `barman-cloud-check-wal-archive`
 // command.
 func (archiver *WALArchiver) BarmanCloudCheckWalArchiveOptions(
-	cluster *apiv1.Cluster,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
-	configuration := cluster.Spec.Backup.BarmanObjectStore
-
 	var options []string
 	if len(configuration.EndpointURL) > 0 {
 		options = append(
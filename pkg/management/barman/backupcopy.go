@@ -0,0 +1,238 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// backupCopyReadScript writes to the standard output the objects of a base
+// backup, together with the WAL files needed to restore it, which are the
+// ones between its first and last WAL file and the history files of its
+// timeline. The backup.info file is written last, so that the copy doesn't
+// appear in the catalog of the destination until it is complete.
+//
+// Each object is written as its path relative to the server directory,
+// prefixed by its length, followed by its content in chunks prefixed
+// by their length, and by an empty chunk.
+const backupCopyReadScript = `
+import os
+import struct
+import sys
+from contextlib import closing
+
+from barman.clients.cloud_backup_list import parse_arguments
+from barman.cloud import CloudBackupCatalog
+from barman.cloud_providers import get_cloud_interface
+
+CHUNK_SIZE = 1024 * 1024
+
+backup_id = sys.argv[1]
+config = parse_arguments(sys.argv[2:])
+cloud_interface = get_cloud_interface(config)
+output = sys.stdout.buffer
+
+
+def is_needed(wal_name, backup_info):
+    if wal_name.endswith(".history"):
+        return int(wal_name[:8], 16) <= backup_info.timeline
+    return backup_info.begin_wal <= wal_name[:24] <= backup_info.end_wal
+
+
+def send(key, prefix):
+    stream = cloud_interface.remote_open(key)
+    if stream is None:
+        raise SystemExit("object %s not found" % key)
+    name = os.path.relpath(key, prefix).encode()
+    output.write(struct.pack(">I", len(name)) + name)
+    while True:
+        chunk = stream.read(CHUNK_SIZE)
+        if not chunk:
+            break
+        output.write(struct.pack(">Q", len(chunk)) + chunk)
+    output.write(struct.pack(">Q", 0))
+
+
+with closing(cloud_interface):
+    catalog = CloudBackupCatalog(cloud_interface=cloud_interface, server_name=config.server_name)
+    backup_info = catalog.get_backup_info(backup_id)
+    if backup_info is None:
+        raise SystemExit("backup %s not found" % backup_id)
+
+    prefix = os.path.join(cloud_interface.path, config.server_name)
+    for wal_name, wal_path in sorted(catalog.get_wal_paths().items()):
+        if is_needed(wal_name, backup_info):
+            send(wal_path, prefix)
+
+    backup_prefix = os.path.join(prefix, "base", backup_id)
+    info_path = os.path.join(backup_prefix, "backup.info")
+    for key in sorted(cloud_interface.list_bucket(backup_prefix + "/", delimiter="")):
+        if key != info_path:
+            send(key, prefix)
+    send(info_path, prefix)
+    output.flush()
+`
+
+// backupCopyWriteScript uploads to the object store the objects written
+// to the standard input by backupCopyReadScript, streaming their content
+const backupCopyWriteScript = `
+import io
+import os
+import struct
+import sys
+from contextlib import closing
+
+from barman.clients.cloud_backup_list import parse_arguments
+from barman.cloud_providers import get_cloud_interface
+
+config = parse_arguments(sys.argv[1:])
+cloud_interface = get_cloud_interface(config)
+source = sys.stdin.buffer
+
+
+def read_exactly(size):
+    data = source.read(size)
+    if len(data) != size:
+        raise SystemExit("truncated stream")
+    return data
+
+
+class ObjectReader(io.RawIOBase):
+    def __init__(self):
+        self.remaining = 0
+        self.position = 0
+        self.done = False
+
+    def readable(self):
+        return True
+
+    def tell(self):
+        return self.position
+
+    def readinto(self, buffer):
+        while self.remaining == 0:
+            if self.done:
+                return 0
+            (self.remaining,) = struct.unpack(">Q", read_exactly(8))
+            self.done = self.remaining == 0
+        data = read_exactly(min(len(buffer), self.remaining))
+        buffer[: len(data)] = data
+        self.remaining -= len(data)
+        self.position += len(data)
+        return len(data)
+
+
+with closing(cloud_interface):
+    prefix = os.path.join(cloud_interface.path, config.server_name)
+    while True:
+        header = source.read(4)
+        if not header:
+            break
+        if len(header) != 4:
+            raise SystemExit("truncated stream")
+        (size,) = struct.unpack(">I", header)
+        name = read_exactly(size).decode()
+        reader = ObjectReader()
+        cloud_interface.upload_fileobj(io.BufferedReader(reader), os.path.join(prefix, name))
+        reader.read()
+`
+
+// CopyBackup copies a base backup, together with the WAL files needed to
+// restore it, from an object store to another one. The objects are streamed
+// from a process reading the source to a process writing the destination,
+// each one with the credentials of its own object store
+func CopyBackup(
+	ctx context.Context,
+	backupID string,
+	sourceConfiguration *v1.BarmanObjectStoreConfiguration,
+	sourceServerName string,
+	sourceEnv []string,
+	destinationConfiguration *v1.BarmanObjectStoreConfiguration,
+	destinationServerName string,
+	destinationEnv []string,
+) error {
+	contextLogger := log.FromContext(ctx).WithName("barman")
+
+	sourceOptions, err := getCatalogOptions(sourceConfiguration, sourceServerName)
+	if err != nil {
+		return err
+	}
+	destinationOptions, err := getCatalogOptions(destinationConfiguration, destinationServerName)
+	if err != nil {
+		return err
+	}
+
+	pipeReader, pipeWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	var readStderr, writeStderr bytes.Buffer
+	interpreter := getPythonInterpreter()
+	readCmd := exec.CommandContext(ctx, interpreter, // #nosec G204
+		append([]string{"-c", backupCopyReadScript, backupID}, sourceOptions...)...)
+	readCmd.Env = sourceEnv
+	readCmd.Stdout = pipeWriter
+	readCmd.Stderr = &readStderr
+	writeCmd := exec.CommandContext(ctx, interpreter, // #nosec G204
+		append([]string{"-c", backupCopyWriteScript}, destinationOptions...)...)
+	writeCmd.Env = destinationEnv
+	writeCmd.Stdin = pipeReader
+	writeCmd.Stderr = &writeStderr
+
+	readErr := readCmd.Start()
+	var writeErr error
+	if readErr == nil {
+		writeErr = writeCmd.Start()
+	}
+
+	// The pipe is now owned by the processes: closing our copies of its
+	// ends lets each process detect when the other one exits
+	_ = pipeWriter.Close()
+	_ = pipeReader.Close()
+
+	if readErr == nil {
+		readErr = readCmd.Wait()
+	}
+	if writeErr == nil && writeCmd.Process != nil {
+		writeErr = writeCmd.Wait()
+	}
+
+	if err := errors.Join(readErr, writeErr); err != nil {
+		contextLogger.Error(err,
+			"Can't copy the backup",
+			"backupID", backupID,
+			"readStderr", readStderr.String(),
+			"writeStderr", writeStderr.String())
+	}
+	if readErr != nil {
+		return fmt.Errorf("while reading backup %s: %w", backupID, readErr)
+	}
+	if writeErr != nil {
+		return fmt.Errorf("while writing backup %s: %w", backupID, writeErr)
+	}
+
+	return nil
+}
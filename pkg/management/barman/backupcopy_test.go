@@ -0,0 +1,90 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"os"
+	"path/filepath"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeInterpreter replaces the Python interpreter: the reading process
+// writes the backup ID and the side it belongs to, and the writing
+// process appends its side to what it reads
+const fakeInterpreter = `#!/bin/sh
+case "$2" in
+*sys.stdin.buffer*)
+	{ cat; printf ' %s' "$CNPG_TEST_SIDE"; } > "$CNPG_TEST_OUTPUT"
+	;;
+*)
+	[ "$3" = "failing" ] && exit 1
+	printf '%s %s' "$3" "$CNPG_TEST_SIDE"
+	;;
+esac
+`
+
+var _ = Describe("CopyBackup", func() {
+	var (
+		outputFile string
+		env        func(side string) []string
+	)
+
+	configuration := &v1.BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"}
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		interpreter := filepath.Join(tempDir, "python-fake")
+		Expect(os.WriteFile(interpreter, []byte(fakeInterpreter), 0o700)).To(Succeed()) // #nosec G306
+		Expect(os.WriteFile(
+			filepath.Join(tempDir, barmanCapabilities.BarmanCloudBackupList),
+			[]byte("#!"+interpreter+"\n"),
+			0o700)).To(Succeed()) // #nosec G306
+		GinkgoT().Setenv("PATH", tempDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		outputFile = filepath.Join(tempDir, "output")
+		env = func(side string) []string {
+			return []string{
+				"PATH=" + os.Getenv("PATH"),
+				"CNPG_TEST_SIDE=" + side,
+				"CNPG_TEST_OUTPUT=" + outputFile,
+			}
+		}
+	})
+
+	It("streams the backup between the processes, each one with its environment", func(ctx SpecContext) {
+		Expect(CopyBackup(ctx, "20240101T000000",
+			configuration, "source", env("source"),
+			configuration, "destination", env("destination"),
+		)).To(Succeed())
+
+		output, err := os.ReadFile(outputFile) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output)).To(Equal("20240101T000000 source destination"))
+	})
+
+	It("fails when the backup can't be read", func(ctx SpecContext) {
+		Expect(CopyBackup(ctx, "failing",
+			configuration, "source", env("source"),
+			configuration, "destination", env("destination"),
+		)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ErrBucketNotFound is returned when the bucket of the object store doesn't exist
+var ErrBucketNotFound = errors.New("bucket not found")
+
+// bucketNotFoundExitCode is the exit code of bucketCheckScript when the
+// bucket doesn't exist, which is not used by Python for its own failures
+const bucketNotFoundExitCode = 3
+
+// bucketCheckScript checks whether the bucket of the object store exists,
+// like barman-cloud-wal-restore does before looking for the WAL file.
+// That command returns the same exit code when the bucket doesn't exist
+// and when the WAL file is not in the archive
+const bucketCheckScript = `
+import sys
+from contextlib import closing
+
+from barman.clients.cloud_backup_list import parse_arguments
+from barman.cloud_providers import get_cloud_interface
+
+config = parse_arguments(sys.argv[1:])
+cloud_interface = get_cloud_interface(config)
+with closing(cloud_interface):
+    if not cloud_interface.test_connectivity():
+        raise SystemExit("cannot connect to the object store")
+    if not cloud_interface.bucket_exists:
+        sys.exit(3)
+`
+
+// CheckBucketExists returns ErrBucketNotFound when the bucket of the
+// object store doesn't exist, and an error when its existence can't
+// be checked
+func CheckBucketExists(
+	ctx context.Context,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	serverName string,
+	env []string,
+) error {
+	contextLogger := log.FromContext(ctx).WithName("barman")
+
+	options, err := getCatalogOptions(barmanConfiguration, serverName)
+	if err != nil {
+		return err
+	}
+
+	var stderrBuffer bytes.Buffer
+	cmd := exec.CommandContext(ctx, getPythonInterpreter(), // #nosec G204
+		append([]string{"-c", bucketCheckScript}, options...)...)
+	cmd.Env = env
+	cmd.Stderr = &stderrBuffer
+	err = cmd.Run()
+	if err == nil {
+		return nil
+	}
+
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ExitCode() == bucketNotFoundExitCode {
+		return fmt.Errorf("%w: %s", ErrBucketNotFound, barmanConfiguration.DestinationPath)
+	}
+
+	contextLogger.Error(err,
+		"Can't check the existence of the bucket",
+		"options", options,
+		"stderr", stderrBuffer.String())
+	return fmt.Errorf("while checking the existence of the bucket: %w", err)
+}
@@ -41,6 +41,18 @@ func detect(version *semver.Version) *Capabilities {
 	newCapabilities.Version = version
 
 	switch {
+	case version.GE(semver.Version{Major: 3, Minor: 12}):
+		// The compression level of WAL files, added in Barman >= 3.12
+		newCapabilities.HasCompressionLevel = true
+		fallthrough
+	case version.GE(semver.Version{Major: 3, Minor: 10}):
+		// zstd, lz4 and xz compression of WAL files, added in Barman >= 3.10
+		newCapabilities.HasZstd = true
+		newCapabilities.HasLz4 = true
+		newCapabilities.HasXz = true
+		// The upload bandwidth limit of backups, added in Barman >= 3.10
+		newCapabilities.HasMaxBandwidth = true
+		fallthrough
 	case version.GE(semver.Version{Major: 3, Minor: 4}):
 		// The --name flag was added to Barman in version 3.3 but we also require the
 		// barman-cloud-backup-show command which was not added until Barman version 3.4
@@ -19,11 +19,43 @@ package capabilities
 import (
 	"github.com/blang/semver"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("detect capabilities", func() {
+	It("ensures that the compression level is supported since the 3.12 version", func() {
+		version, err := semver.ParseTolerant("3.12.0")
+		Expect(err).ToNot(HaveOccurred())
+		capabilities := detect(&version)
+		Expect(capabilities.HasCompressionLevel).To(BeTrue())
+		Expect(capabilities.HasZstd).To(BeTrue())
+	})
+
+	It("ensures that zstd, lz4 and xz are supported since the 3.10 version", func() {
+		version, err := semver.ParseTolerant("3.10.0")
+		Expect(err).ToNot(HaveOccurred())
+		capabilities := detect(&version)
+		Expect(capabilities.HasZstd).To(BeTrue())
+		Expect(capabilities.HasLz4).To(BeTrue())
+		Expect(capabilities.HasXz).To(BeTrue())
+		Expect(capabilities.HasMaxBandwidth).To(BeTrue())
+		Expect(capabilities.HasCompressionLevel).To(BeFalse())
+		Expect(capabilities.hasName).To(BeTrue())
+	})
+
+	It("checks the support of the compression algorithms", func() {
+		version, err := semver.ParseTolerant("3.4.0")
+		Expect(err).ToNot(HaveOccurred())
+		capabilities := detect(&version)
+		Expect(capabilities.SupportsCompression(apiv1.CompressionTypeNone)).To(BeTrue())
+		Expect(capabilities.SupportsCompression(apiv1.CompressionTypeGzip)).To(BeTrue())
+		Expect(capabilities.SupportsCompression(apiv1.CompressionTypeSnappy)).To(BeTrue())
+		Expect(capabilities.SupportsCompression(apiv1.CompressionTypeZstd)).To(BeFalse())
+	})
+
 	It("ensures that all capabilities are true for the 3.4 version", func() {
 		version, err := semver.ParseTolerant("3.4.0")
 		Expect(err).ToNot(HaveOccurred())
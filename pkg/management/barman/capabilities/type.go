@@ -38,6 +38,28 @@ type Capabilities struct {
 	HasErrorCodesForWALRestore bool
 	HasErrorCodesForRestore    bool
 	HasAzureManagedIdentity    bool
+	HasZstd                    bool
+	HasLz4                     bool
+	HasXz                      bool
+	HasCompressionLevel        bool
+	HasMaxBandwidth            bool
+}
+
+// SupportsCompression returns true if the passed compression
+// algorithm can be used
+func (c *Capabilities) SupportsCompression(compression apiv1.CompressionType) bool {
+	switch compression {
+	case apiv1.CompressionTypeSnappy:
+		return c.HasSnappy
+	case apiv1.CompressionTypeZstd:
+		return c.HasZstd
+	case apiv1.CompressionTypeLz4:
+		return c.HasLz4
+	case apiv1.CompressionTypeXz:
+		return c.HasXz
+	default:
+		return true
+	}
 }
 
 // ShouldExecuteBackupWithName returns true if the new backup logic should be executed
@@ -29,6 +29,16 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
+const (
+	// awsConfigFileLocation is where the configuration file of the
+	// AWS SDK used by barman-cloud is written
+	awsConfigFileLocation = "/controller/.aws_config"
+
+	// awsPathStyleConfig is the configuration of the AWS SDK
+	// addressing the buckets with path-style URLs
+	awsPathStyleConfig = "[default]\ns3 =\n    addressing_style = path\n"
+)
+
 // EnvSetBackupCloudCredentials sets the AWS environment variables needed for backups
 // given the configuration inside the cluster
 func EnvSetBackupCloudCredentials(
@@ -38,10 +48,9 @@ func EnvSetBackupCloudCredentials(
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	env []string,
 ) ([]string, error) {
-	if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
-		env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
-	} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
-		env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
+	env, err := EnvSetEndpointOptions(configuration, postgres.BarmanBackupEndpointCACertificateLocation, env)
+	if err != nil {
+		return nil, err
 	}
 
 	return envSetCloudCredentials(ctx, c, namespace, configuration, env)
@@ -55,13 +64,39 @@ func EnvSetRestoreCloudCredentials(
 	namespace string,
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	env []string,
+) ([]string, error) {
+	env, err := EnvSetEndpointOptions(configuration, postgres.BarmanRestoreEndpointCACertificateLocation, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return envSetCloudCredentials(ctx, c, namespace, configuration, env)
+}
+
+// EnvSetEndpointOptions sets the environment variables needed to connect
+// to the endpoint of the object store, given the location where its CA
+// bundle has been written
+func EnvSetEndpointOptions(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	endpointCALocation string,
+	env []string,
 ) ([]string, error) {
 	if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
-		env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
+		env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", endpointCALocation))
 	} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
-		env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
+		env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", endpointCALocation))
 	}
-	return envSetCloudCredentials(ctx, c, namespace, configuration, env)
+
+	if configuration.ForcePathStyle && configuration.BarmanCredentials.AWS != nil {
+		// The addressing style of the buckets can only be set
+		// in the configuration file of the AWS SDK
+		if _, err := fileutils.WriteFileAtomic(awsConfigFileLocation, []byte(awsPathStyleConfig), 0o600); err != nil {
+			return nil, fmt.Errorf("while writing the AWS configuration file: %w", err)
+		}
+		env = append(env, fmt.Sprintf("AWS_CONFIG_FILE=%s", awsConfigFileLocation))
+	}
+
+	return env, nil
 }
 
 // envSetCloudCredentials sets the AWS environment variables given the configuration
@@ -191,8 +191,12 @@ func (restorer *WALRestorer) RestoreList(
 				//
 				// The implemented prefetch is speculative and this WAL may just
 				// not exist, this means that this may not be a real error.
+				//
+				// A file which is not in the archive is reported by the
+				// caller, as it usually means that the end of the archive
+				// has been reached
 				if errors.Is(result.Err, ErrWALNotFound) {
-					contextLog.Info(
+					contextLog.Debug(
 						"WAL file not found in the recovery object store",
 						"walName", result.WalName,
 						"options", options,
@@ -218,15 +222,11 @@ func (restorer *WALRestorer) RestoreList(
 	return resultList
 }
 
-// Restore restores a WAL file from the object store
+// Restore restores a WAL file from the object store. When the file is not
+// in the archive the returned error wraps ErrWALNotFound: this is expected
+// when PostgreSQL reaches the end of the archive, so the output of
+// barman-cloud-wal-restore is only logged at the debug level
 func (restorer *WALRestorer) Restore(walName, destinationPath string, baseOptions []string) error {
-	const (
-		exitCodeBucketOrWalNotFound = 1
-		exitCodeConnectivityError   = 2
-		exitCodeInvalidWalName      = 3
-		exitCodeGeneric             = 4
-	)
-
 	currentCapabilities, capabilitiesError := barmanCapabilities.CurrentCapabilities()
 	if capabilitiesError != nil {
 		return capabilitiesError
@@ -245,20 +245,54 @@ func (restorer *WALRestorer) Restore(walName, destinationPath string, baseOption
 		options...) // #nosec G204
 	barmanCloudWalRestoreCmd.Env = restorer.env
 
-	err := execlog.RunStreaming(barmanCloudWalRestoreCmd, barmanCapabilities.BarmanCloudWalRestore)
+	logger := log.WithName(barmanCapabilities.BarmanCloudWalRestore)
+	stdoutWriter := &execlog.BufferedLogWriter{}
+	stderrWriter := &execlog.BufferedLogWriter{}
+	streamingCmd, err := execlog.RunStreamingNoWaitWithWriter(
+		barmanCloudWalRestoreCmd,
+		barmanCapabilities.BarmanCloudWalRestore,
+		stdoutWriter,
+		stderrWriter)
 	if err == nil {
-		return nil
+		err = streamingCmd.Wait()
+	}
+
+	if err != nil {
+		var exitError *exec.ExitError
+		if !currentCapabilities.HasErrorCodesForWALRestore || !errors.As(err, &exitError) {
+			err = fmt.Errorf("unexpected failure retrieving %q with %s: %w",
+				walName, barmanCapabilities.BarmanCloudWalRestore, err)
+		} else {
+			err = newRestoreError(walName, exitError.ExitCode())
+		}
 	}
 
-	var exitError *exec.ExitError
-	if !currentCapabilities.HasErrorCodesForWALRestore || !errors.As(err, &exitError) {
-		return fmt.Errorf("unexpected failure retrieving %q with %s: %w",
-			walName, barmanCapabilities.BarmanCloudWalRestore, err)
+	stdoutLogger := logger.WithValues(execlog.PipeKey, execlog.StdOut)
+	stderrLogger := logger.WithValues(execlog.PipeKey, execlog.StdErr)
+	if errors.Is(err, ErrWALNotFound) {
+		stdoutWriter.Flush(stdoutLogger.Debug)
+		stderrWriter.Flush(stderrLogger.Debug)
+	} else {
+		stdoutWriter.Flush(stdoutLogger.Info)
+		stderrWriter.Flush(stderrLogger.Info)
 	}
 
+	return err
+}
+
+// newRestoreError translates the exit code of barman-cloud-wal-restore
+// into an error, distinguishing a file which is not in the archive
+// from a failure while fetching it
+func newRestoreError(walName string, exitCode int) error {
+	const (
+		exitCodeBucketOrWalNotFound = 1
+		exitCodeConnectivityError   = 2
+		exitCodeInvalidWalName      = 3
+		exitCodeGeneric             = 4
+	)
+
 	// nolint: lll
 	// source: https://github.com/EnterpriseDB/barman/blob/26ed480cd0268dfd3f8546cc97660d4bd827772a/tests/test_barman_cloud_wal_restore.py
-	exitCode := exitError.ExitCode()
 	switch exitCode {
 	case exitCodeBucketOrWalNotFound:
 		return fmt.Errorf("object storage or file not found %s: %w", walName, ErrWALNotFound)
@@ -0,0 +1,44 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman-cloud-wal-restore exit codes", func() {
+	const walName = "000000010000000000000003"
+
+	It("recognizes a file which is not in the archive", func() {
+		err := newRestoreError(walName, 1)
+		Expect(err).To(MatchError(ErrWALNotFound))
+		Expect(err.Error()).To(ContainSubstring(walName))
+	})
+
+	DescribeTable("distinguishes the failures while fetching the file",
+		func(exitCode int) {
+			err := newRestoreError(walName, exitCode)
+			Expect(err).To(HaveOccurred())
+			Expect(err).ToNot(MatchError(ErrWALNotFound))
+		},
+		Entry("connectivity failure", 2),
+		Entry("invalid WAL name", 3),
+		Entry("generic failure", 4),
+		Entry("unknown exit code", 42),
+	)
+})
@@ -0,0 +1,349 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+)
+
+const (
+	// sharedCacheLockSuffix is the suffix of the files locked to tell the
+	// other instances that a WAL file is being downloaded
+	sharedCacheLockSuffix = ".lock"
+
+	// sharedCacheChecksumSuffix is the suffix of the files containing
+	// the SHA256 checksum of the WAL files in the shared cache
+	sharedCacheChecksumSuffix = ".sha256"
+
+	// sharedCacheTemporarySuffix is the suffix of the files being
+	// copied into the shared cache
+	sharedCacheTemporarySuffix = ".tmp"
+
+	// sharedCacheTemporaryFileTimeout is the time after which a file being
+	// copied into the shared cache is considered the leftover of a failed
+	// copy, e.g. because the instance doing it crashed
+	sharedCacheTemporaryFileTimeout = 2 * time.Minute
+
+	// sharedCacheLockAttempts is how many times we try to lock a WAL file
+	// whose lock file is being removed at the same time
+	sharedCacheLockAttempts = 3
+)
+
+// SharedCache is a directory, shared by the instances of a cluster, where
+// the WAL files downloaded from an object store are kept for a while, to be
+// read by the other instances instead of downloading them again
+type SharedCache struct {
+	// The directory containing the WAL files
+	directory string
+
+	// How long the WAL files are kept
+	retentionPeriod time.Duration
+}
+
+// NewSharedCache creates a new shared cache in a subdirectory of the passed
+// one, as the instances may be restoring WAL files from different object
+// stores. It fails if the cache can't be used, i.e. when the directory is
+// not mounted or it's not writable
+func NewSharedCache(baseDirectory, name string, retentionPeriod time.Duration) (*SharedCache, error) {
+	stat, err := os.Stat(baseDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("while checking the shared WAL restore cache: %w", err)
+	}
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("the shared WAL restore cache %s is not a directory", baseDirectory)
+	}
+
+	directory := path.Join(baseDirectory, name)
+	if err := fileutils.EnsureDirectoryExists(directory); err != nil {
+		return nil, fmt.Errorf("while creating the shared WAL restore cache directory: %w", err)
+	}
+
+	return &SharedCache{
+		directory:       directory,
+		retentionPeriod: retentionPeriod,
+	}, nil
+}
+
+func (cache *SharedCache) fileName(walName string) string {
+	return path.Join(cache.directory, walName)
+}
+
+// Fetch copies the passed WAL file from the cache into the destination
+// path, returning a boolean flag indicating if the file was in the cache.
+// The content of the file is verified against the checksum computed when
+// it was stored, and a corrupted file is removed from the cache
+func (cache *SharedCache) Fetch(walName, destinationPath string) (bool, error) {
+	expectedChecksum, err := os.ReadFile(cache.fileName(walName) + sharedCacheChecksumSuffix) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("while reading the checksum of %s from the shared WAL restore cache: %w",
+			walName, err)
+	}
+
+	checksum, err := copyFileWithChecksum(cache.fileName(walName), destinationPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("while copying %s from the shared WAL restore cache: %w", walName, err)
+	}
+
+	if checksum != strings.TrimSpace(string(expectedChecksum)) {
+		_ = os.Remove(destinationPath)
+		_ = os.Remove(cache.fileName(walName))
+		_ = os.Remove(cache.fileName(walName) + sharedCacheChecksumSuffix)
+		return false, fmt.Errorf("the checksum of %s in the shared WAL restore cache doesn't match, "+
+			"the file has been removed", walName)
+	}
+
+	return true, nil
+}
+
+// Store copies the WAL file in the source path into the cache, together
+// with its checksum. The files are renamed into their final place once
+// completely written, so the other instances never read a partial file
+func (cache *SharedCache) Store(walName, sourcePath string) error {
+	temporaryFileName, err := cache.createTemporaryFile(walName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(temporaryFileName)
+	}()
+
+	checksum, err := copyFileWithChecksum(sourcePath, temporaryFileName)
+	if err != nil {
+		return fmt.Errorf("while copying %s into the shared WAL restore cache: %w", walName, err)
+	}
+
+	temporaryChecksumFileName, err := cache.createTemporaryFile(walName + sharedCacheChecksumSuffix)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(temporaryChecksumFileName)
+	}()
+
+	if err := os.WriteFile(temporaryChecksumFileName, []byte(checksum), 0o600); err != nil {
+		return fmt.Errorf("while writing the checksum of %s into the shared WAL restore cache: %w", walName, err)
+	}
+	if err := os.Rename(temporaryChecksumFileName, cache.fileName(walName)+sharedCacheChecksumSuffix); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFileName, cache.fileName(walName))
+}
+
+// createTemporaryFile creates a new empty file in the cache, with a unique
+// name, where the passed file can be written before being renamed. This
+// way more instances can store the same file at the same time
+func (cache *SharedCache) createTemporaryFile(name string) (string, error) {
+	temporaryFile, err := os.CreateTemp(cache.directory, name+".*"+sharedCacheTemporarySuffix)
+	if err != nil {
+		return "", fmt.Errorf("while creating a temporary file in the shared WAL restore cache: %w", err)
+	}
+
+	return temporaryFile.Name(), temporaryFile.Close()
+}
+
+// Lock tells the other instances that the passed WAL file is being
+// downloaded, taking an advisory lock on the corresponding lock file. It
+// returns false if another instance holds the lock, and a function
+// releasing it otherwise. The lock is released by the kernel if the
+// process holding it terminates, so it can never be left behind
+func (cache *SharedCache) Lock(walName string) (acquired bool, unlock func(), err error) {
+	lockFileName := cache.fileName(walName) + sharedCacheLockSuffix
+
+	for attempt := 0; attempt < sharedCacheLockAttempts; attempt++ {
+		lockFile, locked, err := tryLockFile(lockFileName)
+		if err != nil || !locked {
+			return false, nil, err
+		}
+
+		// The lock file may have been removed by the expiration of the cache
+		// after we opened it, and we need to lock the one replacing it
+		if isSameFile(lockFile, lockFileName) {
+			return true, func() { _ = lockFile.Close() }, nil
+		}
+		_ = lockFile.Close()
+	}
+
+	return false, nil, nil
+}
+
+// RemoveExpired removes the WAL files which have been in the cache for
+// longer than the retention period, together with their lock files, when
+// not locked, and the leftovers of the failed copies
+func (cache *SharedCache) RemoveExpired() error {
+	entries, err := os.ReadDir(cache.directory)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		fileName := path.Join(cache.directory, entry.Name())
+		switch {
+		case strings.HasSuffix(entry.Name(), sharedCacheTemporarySuffix):
+			if time.Since(info.ModTime()) < sharedCacheTemporaryFileTimeout {
+				continue
+			}
+			err = os.Remove(fileName)
+
+		case time.Since(info.ModTime()) < cache.retentionPeriod:
+			continue
+
+		case strings.HasSuffix(entry.Name(), sharedCacheLockSuffix):
+			err = removeLockFile(fileName)
+
+		default:
+			err = os.Remove(fileName)
+		}
+
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveExpiredFromSharedCaches removes the expired WAL files from
+// every shared cache in the passed base directory
+func RemoveExpiredFromSharedCaches(baseDirectory string, retentionPeriod time.Duration) error {
+	entries, err := os.ReadDir(baseDirectory)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		cache := &SharedCache{
+			directory:       path.Join(baseDirectory, entry.Name()),
+			retentionPeriod: retentionPeriod,
+		}
+		if err := cache.RemoveExpired(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeLockFile removes the passed lock file if nobody is holding it
+func removeLockFile(lockFileName string) error {
+	lockFile, locked, err := tryLockFile(lockFileName)
+	if err != nil || !locked {
+		return err
+	}
+	defer func() {
+		_ = lockFile.Close()
+	}()
+
+	return os.Remove(lockFileName)
+}
+
+// tryLockFile opens the passed lock file, creating it if needed, and
+// tries to lock it. The returned file is open only if the lock is held
+func tryLockFile(lockFileName string) (*os.File, bool, error) {
+	lockFile, err := os.OpenFile(lockFileName, os.O_CREATE|os.O_RDWR, 0o600) // #nosec G304
+	if err != nil {
+		return nil, false, err
+	}
+
+	locked, err := compatibility.TryLockFile(lockFile)
+	if err != nil || !locked {
+		_ = lockFile.Close()
+		return nil, false, err
+	}
+
+	return lockFile, true, nil
+}
+
+// isSameFile checks if the passed open file is still
+// the one having the passed name
+func isSameFile(file *os.File, fileName string) bool {
+	openStat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	currentStat, err := os.Stat(fileName)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(openStat, currentStat)
+}
+
+// copyFileWithChecksum copies the source file into the destination one,
+// returning the hex encoded SHA256 checksum of its content
+func copyFileWithChecksum(source, destination string) (checksum string, err error) {
+	in, err := os.Open(source) // #nosec G304
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(destination, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600) // #nosec G304
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		closeError := out.Close()
+		if err == nil && closeError != nil {
+			err = closeError
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), in); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorer
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shared WAL restore cache", func() {
+	const walName = "000000010000000000000001"
+
+	var (
+		baseDirectory string
+		cache         *SharedCache
+		sourcePath    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		baseDirectory = GinkgoT().TempDir()
+		cache, err = NewSharedCache(baseDirectory, "store", time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		sourcePath = path.Join(GinkgoT().TempDir(), walName)
+		Expect(os.WriteFile(sourcePath, []byte("wal content"), 0o600)).To(Succeed())
+	})
+
+	It("can't be used when the base directory is not mounted", func() {
+		_, err := NewSharedCache(path.Join(baseDirectory, "missing"), "store", time.Hour)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("restores the WAL files stored by the other instances", func() {
+		destinationPath := path.Join(GinkgoT().TempDir(), "RECOVERYXLOG")
+
+		restored, err := cache.Fetch(walName, destinationPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored).To(BeFalse())
+
+		Expect(cache.Store(walName, sourcePath)).To(Succeed())
+		restored, err = cache.Fetch(walName, destinationPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored).To(BeTrue())
+
+		content, err := fileutils.ReadFile(destinationPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("wal content"))
+
+		// The file is kept for the other instances
+		restored, err = cache.Fetch(walName, destinationPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored).To(BeTrue())
+	})
+
+	It("doesn't restore the corrupted WAL files", func() {
+		destinationPath := path.Join(GinkgoT().TempDir(), "RECOVERYXLOG")
+
+		Expect(cache.Store(walName, sourcePath)).To(Succeed())
+		Expect(os.WriteFile(cache.fileName(walName), []byte("corrupted"), 0o600)).To(Succeed())
+
+		restored, err := cache.Fetch(walName, destinationPath)
+		Expect(err).To(HaveOccurred())
+		Expect(restored).To(BeFalse())
+		Expect(fileutils.FileExists(destinationPath)).To(BeFalse())
+		Expect(fileutils.FileExists(cache.fileName(walName))).To(BeFalse())
+		Expect(fileutils.FileExists(cache.fileName(walName) + sharedCacheChecksumSuffix)).To(BeFalse())
+	})
+
+	It("ignores the WAL files without a checksum", func() {
+		destinationPath := path.Join(GinkgoT().TempDir(), "RECOVERYXLOG")
+		Expect(os.WriteFile(cache.fileName(walName), []byte("wal content"), 0o600)).To(Succeed())
+
+		restored, err := cache.Fetch(walName, destinationPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored).To(BeFalse())
+	})
+
+	It("lets only one instance download a WAL file", func() {
+		acquired, unlock, err := cache.Lock(walName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+
+		acquired, _, err = cache.Lock(walName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeFalse())
+
+		unlock()
+		acquired, unlock, err = cache.Lock(walName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+		unlock()
+	})
+
+	It("removes the expired WAL files", func() {
+		const expiredWALName = "000000010000000000000002"
+		Expect(cache.Store(walName, sourcePath)).To(Succeed())
+		Expect(cache.Store(expiredWALName, sourcePath)).To(Succeed())
+
+		expiredTime := time.Now().Add(-2 * time.Hour)
+		for _, suffix := range []string{"", sharedCacheChecksumSuffix} {
+			Expect(os.Chtimes(cache.fileName(expiredWALName)+suffix, expiredTime, expiredTime)).To(Succeed())
+		}
+
+		Expect(RemoveExpiredFromSharedCaches(baseDirectory, time.Hour)).To(Succeed())
+		Expect(fileutils.FileExists(cache.fileName(walName))).To(BeTrue())
+		Expect(fileutils.FileExists(cache.fileName(walName) + sharedCacheChecksumSuffix)).To(BeTrue())
+		Expect(fileutils.FileExists(cache.fileName(expiredWALName))).To(BeFalse())
+		Expect(fileutils.FileExists(cache.fileName(expiredWALName) + sharedCacheChecksumSuffix)).To(BeFalse())
+	})
+
+	It("removes only the expired lock files which are not locked", func() {
+		const expiredWALName = "000000010000000000000002"
+
+		acquired, unlock, err := cache.Lock(walName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+		defer unlock()
+
+		acquired, expiredUnlock, err := cache.Lock(expiredWALName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(acquired).To(BeTrue())
+		expiredUnlock()
+
+		expiredTime := time.Now().Add(-2 * time.Hour)
+		for _, name := range []string{walName, expiredWALName} {
+			Expect(os.Chtimes(cache.fileName(name)+sharedCacheLockSuffix, expiredTime, expiredTime)).To(Succeed())
+		}
+
+		Expect(cache.RemoveExpired()).To(Succeed())
+		Expect(fileutils.FileExists(cache.fileName(walName) + sharedCacheLockSuffix)).To(BeTrue())
+		Expect(fileutils.FileExists(cache.fileName(expiredWALName) + sharedCacheLockSuffix)).To(BeFalse())
+	})
+
+	It("removes the leftovers of the failed copies", func() {
+		temporaryFileName, err := cache.createTemporaryFile(walName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cache.RemoveExpired()).To(Succeed())
+		Expect(fileutils.FileExists(temporaryFileName)).To(BeTrue())
+
+		expiredTime := time.Now().Add(-2 * sharedCacheTemporaryFileTimeout)
+		Expect(os.Chtimes(temporaryFileName, expiredTime, expiredTime)).To(Succeed())
+		Expect(cache.RemoveExpired()).To(Succeed())
+		Expect(fileutils.FileExists(temporaryFileName)).To(BeFalse())
+	})
+})
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// maxRecentFailures is the number of failures of the restore_command
+// that are kept in the status file
+const maxRecentFailures = 5
+
+// Status is the outcome of the most recent executions of the
+// restore_command, as persisted in the status file
+type Status struct {
+	// The name of the last WAL file that has been restored
+	LastRestoredWAL string `json:"lastRestoredWAL,omitempty"`
+
+	// The time when the last WAL file has been restored
+	LastRestoredWALTime string `json:"lastRestoredWALTime,omitempty"`
+
+	// The most recent failures, the oldest one first
+	RecentFailures []postgres.WALRestoreFailure `json:"recentFailures,omitempty"`
+}
+
+// ReadStatus reads the status file, returning an empty status
+// if it doesn't exist
+func ReadStatus(fileName string) (*Status, error) {
+	var status Status
+
+	content, err := fileutils.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return &status, nil
+	}
+
+	if err := json.Unmarshal(content, &status); err != nil {
+		return nil, fmt.Errorf("while decoding the WAL restore status file: %w", err)
+	}
+
+	return &status, nil
+}
+
+// RecordSuccess stores in the status file that the passed WAL file
+// has been restored
+func RecordSuccess(fileName string, walName string) error {
+	return updateStatus(fileName, func(status *Status) {
+		status.LastRestoredWAL = walName
+		status.LastRestoredWALTime = utils.GetCurrentTimestamp()
+	})
+}
+
+// RecordFailure stores in the status file that the passed WAL file
+// couldn't be restored, discarding the oldest failures
+func RecordFailure(fileName string, walName string, restoreErr error) error {
+	return updateStatus(fileName, func(status *Status) {
+		status.RecentFailures = append(status.RecentFailures, postgres.WALRestoreFailure{
+			WALName: walName,
+			Time:    utils.GetCurrentTimestamp(),
+			Error:   restoreErr.Error(),
+		})
+		if len(status.RecentFailures) > maxRecentFailures {
+			status.RecentFailures = status.RecentFailures[len(status.RecentFailures)-maxRecentFailures:]
+		}
+	})
+}
+
+func updateStatus(fileName string, update func(status *Status)) error {
+	status, err := ReadStatus(fileName)
+	if err != nil {
+		// A corrupted status file is not worth failing the restore_command,
+		// we'll just start from scratch
+		status = &Status{}
+	}
+
+	update(status)
+
+	content, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteFileAtomic(fileName, content, 0o600)
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restorer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL restore status", func() {
+	var statusFile string
+
+	BeforeEach(func() {
+		statusFile = path.Join(GinkgoT().TempDir(), "wal-restore-status.json")
+	})
+
+	It("is empty when the status file doesn't exist", func() {
+		status, err := ReadStatus(statusFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*status).To(BeZero())
+	})
+
+	It("records the last restored WAL file", func() {
+		Expect(RecordSuccess(statusFile, "000000010000000000000001")).To(Succeed())
+		Expect(RecordSuccess(statusFile, "000000010000000000000002")).To(Succeed())
+
+		status, err := ReadStatus(statusFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.LastRestoredWAL).To(Equal("000000010000000000000002"))
+		Expect(status.LastRestoredWALTime).ToNot(BeEmpty())
+		Expect(status.RecentFailures).To(BeEmpty())
+	})
+
+	It("keeps only the most recent failures", func() {
+		for idx := 1; idx <= maxRecentFailures+2; idx++ {
+			walName := fmt.Sprintf("0000000100000000000000%02d", idx)
+			Expect(RecordFailure(statusFile, walName, errors.New("access denied"))).To(Succeed())
+		}
+
+		status, err := ReadStatus(statusFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.RecentFailures).To(HaveLen(maxRecentFailures))
+		Expect(status.RecentFailures[0].WALName).To(Equal("000000010000000000000003"))
+		Expect(status.RecentFailures[maxRecentFailures-1].WALName).To(Equal("000000010000000000000007"))
+		Expect(status.RecentFailures[0].Error).To(Equal("access denied"))
+	})
+
+	It("starts from scratch when the status file is corrupted", func() {
+		Expect(os.WriteFile(statusFile, []byte("{"), 0o600)).To(Succeed())
+		_, err := ReadStatus(statusFile)
+		Expect(err).To(HaveOccurred())
+
+		Expect(RecordSuccess(statusFile, "000000010000000000000001")).To(Succeed())
+		status, err := ReadStatus(statusFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.LastRestoredWAL).To(Equal("000000010000000000000001"))
+	})
+})
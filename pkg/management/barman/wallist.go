@@ -0,0 +1,165 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// walListScript lists the names of the WAL files in the archive, one per line.
+// The barman-cloud tools have no command doing that, so we use the barman
+// library, parsing the options with the parser of barman-cloud-backup-list.
+// The same options can then be used for both commands.
+const walListScript = `
+import sys
+from contextlib import closing
+
+from barman.clients.cloud_backup_list import parse_arguments
+from barman.cloud import CloudBackupCatalog
+from barman.cloud_providers import get_cloud_interface
+
+config = parse_arguments(sys.argv[1:])
+cloud_interface = get_cloud_interface(config)
+with closing(cloud_interface):
+    catalog = CloudBackupCatalog(cloud_interface=cloud_interface, server_name=config.server_name)
+    for wal_name in catalog.get_wal_paths():
+        print(wal_name)
+`
+
+// defaultPythonInterpreter is the interpreter used to list the WAL files
+// when the one of the barman-cloud tools can't be detected
+const defaultPythonInterpreter = "python3"
+
+// GetWALList returns the names of the WAL files in the archive, listing
+// the objects in the object store without downloading them
+func GetWALList(
+	ctx context.Context,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	serverName string,
+	env []string,
+) ([]string, error) {
+	contextLogger := log.FromContext(ctx).WithName("barman")
+
+	options, err := getCatalogOptions(barmanConfiguration, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	cmd := exec.CommandContext(ctx, getPythonInterpreter(), // #nosec G204
+		append([]string{"-c", walListScript}, options...)...)
+	cmd.Env = env
+	cmd.Stdout = &stdoutBuffer
+	cmd.Stderr = &stderrBuffer
+	if err := cmd.Run(); err != nil {
+		contextLogger.Error(err,
+			"Can't list the WAL files",
+			"options", options,
+			"stderr", stderrBuffer.String())
+		return nil, fmt.Errorf("while listing the WAL files in the archive: %w", err)
+	}
+
+	var walNames []string
+	scanner := bufio.NewScanner(&stdoutBuffer)
+	for scanner.Scan() {
+		if walName := strings.TrimSpace(scanner.Text()); walName != "" {
+			walNames = append(walNames, walName)
+		}
+	}
+
+	return walNames, scanner.Err()
+}
+
+// getCatalogOptions gets the options of barman-cloud-backup-list
+// pointing to the catalog of the passed server
+func getCatalogOptions(
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	serverName string,
+) ([]string, error) {
+	var options []string
+	if barmanConfiguration.EndpointURL != "" {
+		options = append(options, "--endpoint-url", barmanConfiguration.EndpointURL)
+	}
+
+	options, err := AppendCloudProviderOptionsFromConfiguration(options, barmanConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(options, barmanConfiguration.DestinationPath, serverName), nil
+}
+
+// getPythonInterpreter gets the interpreter of the barman-cloud tools,
+// reading the shebang of barman-cloud-backup-list, as the barman library
+// may not be available to the default one
+func getPythonInterpreter() string {
+	commandPath, err := exec.LookPath(barmanCapabilities.BarmanCloudBackupList)
+	if err != nil {
+		return defaultPythonInterpreter
+	}
+
+	file, err := os.Open(commandPath) // #nosec G304
+	if err != nil {
+		return defaultPythonInterpreter
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	firstLine, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil {
+		return defaultPythonInterpreter
+	}
+
+	return parseShebang(firstLine)
+}
+
+// parseShebang gets the interpreter from the passed shebang line,
+// returning the default interpreter if it is not a Python one
+func parseShebang(line string) string {
+	interpreter, found := strings.CutPrefix(strings.TrimSpace(line), "#!")
+	if !found {
+		return defaultPythonInterpreter
+	}
+
+	fields := strings.Fields(interpreter)
+	if len(fields) == 0 {
+		return defaultPythonInterpreter
+	}
+
+	// e.g. "#!/usr/bin/env python3"
+	if len(fields) > 1 && strings.HasSuffix(fields[0], "/env") {
+		fields = fields[1:]
+	}
+
+	if !strings.Contains(fields[0], "python") {
+		return defaultPythonInterpreter
+	}
+
+	return fields[0]
+}
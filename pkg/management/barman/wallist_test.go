@@ -0,0 +1,34 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("parseShebang",
+	func(line, expected string) {
+		Expect(parseShebang(line)).To(Equal(expected))
+	},
+	Entry("with the path of the interpreter", "#!/usr/bin/python3\n", "/usr/bin/python3"),
+	Entry("with a virtual environment", "#!/opt/barman/bin/python", "/opt/barman/bin/python"),
+	Entry("with env", "#!/usr/bin/env python3", "python3"),
+	Entry("with arguments", "#!/usr/bin/python3 -s", "/usr/bin/python3"),
+	Entry("without a shebang", "import sys", defaultPythonInterpreter),
+	Entry("with another interpreter", "#!/bin/sh", defaultPythonInterpreter),
+)
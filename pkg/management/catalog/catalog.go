@@ -56,7 +56,7 @@ func NewCatalogFromBarmanCloudBackupList(rawJSON string) (*Catalog, error) {
 	return result, nil
 }
 
-var currentTLIRegex = regexp.MustCompile("^(|latest)$")
+var currentTLIRegex = regexp.MustCompile("^(|latest|current)$")
 
 // LatestBackupInfo gets the information about the latest successful backup
 func (catalog *Catalog) LatestBackupInfo() *BarmanBackup {
@@ -77,6 +77,25 @@ func (catalog *Catalog) LatestBackupInfo() *BarmanBackup {
 	return nil
 }
 
+// FirstBackupInfo gets the information about the oldest successful backup
+func (catalog *Catalog) FirstBackupInfo() *BarmanBackup {
+	if catalog.Len() == 0 {
+		return nil
+	}
+
+	// the code below assumes the catalog to be sorted, therefore, we enforce it first
+	sort.Sort(catalog)
+
+	// Skip errored backups and return the first valid one
+	for i := 0; i < len(catalog.List); i++ {
+		if catalog.List[i].isBackupDone() {
+			return &catalog.List[i]
+		}
+	}
+
+	return nil
+}
+
 // FirstRecoverabilityPoint gets the start time of the first backup in
 // the catalog
 func (catalog *Catalog) FirstRecoverabilityPoint() *time.Time {
@@ -100,8 +119,13 @@ func (catalog *Catalog) FirstRecoverabilityPoint() *time.Time {
 }
 
 // FindBackupInfo finds the backup info that should be used to file
-// a PITR request via target parameters specified within `RecoveryTarget`
-func (catalog *Catalog) FindBackupInfo(recoveryTarget *v1.RecoveryTarget) (*BarmanBackup, error) {
+// a PITR request via target parameters specified within `RecoveryTarget`.
+// When the target timeline is a number, the history of that timeline, if
+// passed, allows choosing the backups taken on its parents too
+func (catalog *Catalog) FindBackupInfo(
+	recoveryTarget *v1.RecoveryTarget,
+	history *TimelineHistory,
+) (*BarmanBackup, error) {
 	// Check that BackupID is not empty. In such case, always use the
 	// backup ID provided by the user.
 	if recoveryTarget.BackupID != "" {
@@ -120,21 +144,22 @@ func (catalog *Catalog) FindBackupInfo(recoveryTarget *v1.RecoveryTarget) (*Barm
 
 	// The first step is to check any time based research
 	if t := recoveryTarget.TargetTime; t != "" {
-		return catalog.findClosestBackupFromTargetTime(t, targetTLI)
+		return catalog.findClosestBackupFromTargetTime(t, targetTLI, history)
 	}
 
 	// The second step is to check any LSN based research
 	if t := recoveryTarget.TargetLSN; t != "" {
-		return catalog.findClosestBackupFromTargetLSN(t, targetTLI)
+		return catalog.findClosestBackupFromTargetLSN(t, targetTLI, history)
 	}
 
 	// The fallback is to use the latest available backup in chronological order
-	return catalog.findlatestBackupFromTimeline(targetTLI), nil
+	return catalog.findlatestBackupFromTimeline(targetTLI, history), nil
 }
 
 func (catalog *Catalog) findClosestBackupFromTargetLSN(
 	targetLSNString string,
 	targetTLI string,
+	history *TimelineHistory,
 ) (*BarmanBackup, error) {
 	targetLSN := postgres.LSN(targetLSNString)
 	if _, err := targetLSN.Parse(); err != nil {
@@ -145,9 +170,7 @@ func (catalog *Catalog) findClosestBackupFromTargetLSN(
 		if !barmanBackup.isBackupDone() {
 			continue
 		}
-		if (strconv.Itoa(barmanBackup.TimeLine) == targetTLI ||
-			// if targetTLI is not an integer, it will be ignored actually
-			currentTLIRegex.MatchString(targetTLI)) &&
+		if barmanBackup.isOnTargetTimeline(targetTLI, history) &&
 			postgres.LSN(barmanBackup.BeginLSN).Less(targetLSN) {
 			return &catalog.List[i], nil
 		}
@@ -158,6 +181,7 @@ func (catalog *Catalog) findClosestBackupFromTargetLSN(
 func (catalog *Catalog) findClosestBackupFromTargetTime(
 	targetTimeString string,
 	targetTLI string,
+	history *TimelineHistory,
 ) (*BarmanBackup, error) {
 	targetTime, err := utils.ParseTargetTime(nil, targetTimeString)
 	if err != nil {
@@ -168,9 +192,7 @@ func (catalog *Catalog) findClosestBackupFromTargetTime(
 		if !barmanBackup.isBackupDone() {
 			continue
 		}
-		if (strconv.Itoa(barmanBackup.TimeLine) == targetTLI ||
-			// if targetTLI is not an integer, it will be ignored actually
-			currentTLIRegex.MatchString(targetTLI)) &&
+		if barmanBackup.isOnTargetTimeline(targetTLI, history) &&
 			!barmanBackup.EndTime.After(targetTime) {
 			return &catalog.List[i], nil
 		}
@@ -178,15 +200,13 @@ func (catalog *Catalog) findClosestBackupFromTargetTime(
 	return nil, nil
 }
 
-func (catalog *Catalog) findlatestBackupFromTimeline(targetTLI string) *BarmanBackup {
+func (catalog *Catalog) findlatestBackupFromTimeline(targetTLI string, history *TimelineHistory) *BarmanBackup {
 	for i := len(catalog.List) - 1; i >= 0; i-- {
 		barmanBackup := catalog.List[i]
 		if !barmanBackup.isBackupDone() {
 			continue
 		}
-		if strconv.Itoa(barmanBackup.TimeLine) == targetTLI ||
-			// if targetTLI is not an integer, it will be ignored actually
-			currentTLIRegex.MatchString(targetTLI) {
+		if barmanBackup.isOnTargetTimeline(targetTLI, history) {
 			return &catalog.List[i]
 		}
 	}
@@ -194,6 +214,19 @@ func (catalog *Catalog) findlatestBackupFromTimeline(targetTLI string) *BarmanBa
 	return nil
 }
 
+// isOnTargetTimeline checks if the backup can be used to recover along the
+// target timeline, because it was taken on that timeline or, given its
+// history, on one of its parents before the fork
+func (b *BarmanBackup) isOnTargetTimeline(targetTLI string, history *TimelineHistory) bool {
+	// if targetTLI is not an integer, it will be ignored actually
+	if currentTLIRegex.MatchString(targetTLI) || strconv.Itoa(b.TimeLine) == targetTLI {
+		return true
+	}
+
+	return history != nil && strconv.Itoa(history.TimeLine) == targetTLI &&
+		history.Includes(b.TimeLine, postgres.LSN(b.EndLSN))
+}
+
 func (catalog *Catalog) findBackupFromID(backupID string) (*BarmanBackup, error) {
 	if backupID == "" {
 		return nil, fmt.Errorf("no backupID provided")
@@ -254,6 +287,13 @@ type BarmanBackup struct {
 
 	// The TimeLine
 	TimeLine int `json:"timeline"`
+
+	// The version of the PostgreSQL server where the backup was
+	// taken, in the server_version_num format (i.e. 160002)
+	Version int `json:"version"`
+
+	// The size of the backup, in bytes
+	Size int64 `json:"size"`
 }
 
 type barmanBackupShow struct {
@@ -300,6 +340,19 @@ func (b *BarmanBackup) deserializeBackupTimeStrings() error {
 	return nil
 }
 
+// MajorVersion returns the major version of the PostgreSQL server where
+// the backup was taken, in the same format used by the PG_VERSION file.
+// Zero is returned when the version is not known.
+func (b *BarmanBackup) MajorVersion() int {
+	// Before PostgreSQL 10 the major version included the minor part
+	// too, and those versions are not supported anyway
+	if b.Version < 100000 {
+		return 0
+	}
+
+	return b.Version / 10000
+}
+
 func (b *BarmanBackup) isBackupDone() bool {
 	return !b.BeginTime.IsZero() && !b.EndTime.IsZero()
 }
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -63,15 +64,38 @@ var _ = Describe("Backup catalog", func() {
 		Expect(catalog.LatestBackupInfo().ID).To(Equal("202101031200"))
 	})
 
+	It("can be summarized", func() {
+		retrievedAt := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)
+		summary := catalog.Summary("cluster-example", retrievedAt)
+		Expect(summary.ServerName).To(Equal("cluster-example"))
+		Expect(summary.RetrievedAt).To(Equal(retrievedAt))
+		Expect(*summary.FirstRecoverabilityPoint).To(Equal(time.Date(2021, 1, 1, 12, 30, 0, 0, time.UTC)))
+		Expect(*summary.LastSuccessfulBackup).To(Equal(time.Date(2021, 1, 3, 12, 30, 0, 0, time.UTC)))
+		Expect(summary.Backups).To(HaveLen(3))
+		Expect(summary.Backups[0].ID).To(Equal("202101011200"))
+		Expect(*summary.Backups[0].BeginTime).To(Equal(time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)))
+		Expect(summary.Backups[0].TimeLine).To(Equal(1))
+
+		emptySummary := NewCatalog(nil).Summary("cluster-example", retrievedAt)
+		Expect(emptySummary.Backups).To(BeEmpty())
+		Expect(emptySummary.FirstRecoverabilityPoint).To(BeNil())
+		Expect(emptySummary.LastSuccessfulBackup).To(BeNil())
+	})
+
+	It("can get the first backupinfo", func() {
+		Expect(catalog.FirstBackupInfo().ID).To(Equal("202101011200"))
+		Expect(NewCatalog(nil).FirstBackupInfo()).To(BeNil())
+	})
+
 	It("can find the closest backup info when there is one", func() {
 		recoveryTarget := &v1.RecoveryTarget{TargetTime: time.Now().Format("2006-01-02 15:04:04")}
-		closestBackupInfo, err := catalog.FindBackupInfo(recoveryTarget)
+		closestBackupInfo, err := catalog.FindBackupInfo(recoveryTarget, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(closestBackupInfo.ID).To(Equal("202101031200"))
 
 		recoveryTarget = &v1.RecoveryTarget{TargetTime: time.Date(2021, 1, 2, 12, 30, 0,
 			0, time.UTC).Format("2006-01-02 15:04:04")}
-		closestBackupInfo, err = catalog.FindBackupInfo(recoveryTarget)
+		closestBackupInfo, err = catalog.FindBackupInfo(recoveryTarget, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(closestBackupInfo.ID).To(Equal("202101021200"))
 	})
@@ -79,25 +103,95 @@ var _ = Describe("Backup catalog", func() {
 	It("will return an empty result when the closest backup cannot be found", func() {
 		recoveryTarget := &v1.RecoveryTarget{TargetTime: time.Date(2019, 1, 2, 12, 30,
 			0, 0, time.UTC).Format("2006-01-02 15:04:04")}
-		closestBackupInfo, err := catalog.FindBackupInfo(recoveryTarget)
+		closestBackupInfo, err := catalog.FindBackupInfo(recoveryTarget, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(closestBackupInfo).To(BeNil())
 	})
 
 	It("can find the backup info when BackupID is provided", func() {
 		recoveryTarget := &v1.RecoveryTarget{TargetName: "recovery_point_1", BackupID: "202101021200"}
-		BackupInfo, err := catalog.FindBackupInfo(recoveryTarget)
+		BackupInfo, err := catalog.FindBackupInfo(recoveryTarget, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(BackupInfo.ID).To(Equal("202101021200"))
 
 		trueVal := true
 		recoveryTarget = &v1.RecoveryTarget{TargetImmediate: &trueVal, BackupID: "202101011200"}
-		BackupInfo, err = catalog.FindBackupInfo(recoveryTarget)
+		BackupInfo, err = catalog.FindBackupInfo(recoveryTarget, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(BackupInfo.ID).To(Equal("202101011200"))
 	})
 })
 
+var _ = Describe("Backup catalog with branched histories", func() {
+	catalog := NewCatalog([]BarmanBackup{
+		{
+			ID:        "202101011200",
+			BeginLSN:  "0/2000028",
+			EndLSN:    "0/2000100",
+			BeginTime: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2021, 1, 1, 12, 30, 0, 0, time.UTC),
+			TimeLine:  1,
+		},
+		{
+			ID:        "202101021200",
+			BeginLSN:  "0/6000028",
+			EndLSN:    "0/6000100",
+			BeginTime: time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2021, 1, 2, 12, 30, 0, 0, time.UTC),
+			TimeLine:  1,
+		},
+		{
+			ID:        "202101031200",
+			BeginLSN:  "0/8000028",
+			EndLSN:    "0/8000100",
+			BeginTime: time.Date(2021, 1, 3, 12, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2021, 1, 3, 12, 30, 0, 0, time.UTC),
+			TimeLine:  3,
+		},
+	})
+
+	// Timeline 2 has been forked from timeline 1 between the two backups
+	history := &TimelineHistory{
+		TimeLine:     2,
+		SwitchPoints: map[int]postgres.LSN{1: "0/5000000"},
+	}
+
+	It("only uses the backups of the target timeline without its history", func() {
+		backup, err := catalog.FindBackupInfo(&v1.RecoveryTarget{TargetTLI: "2"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup).To(BeNil())
+	})
+
+	It("uses the backups taken on the parents before the fork", func() {
+		backup, err := catalog.FindBackupInfo(&v1.RecoveryTarget{TargetTLI: "2"}, history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101011200"))
+
+		backup, err = catalog.FindBackupInfo(&v1.RecoveryTarget{
+			TargetTLI:  "2",
+			TargetTime: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC).Format("2006-01-02 15:04:04"),
+		}, history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101011200"))
+
+		backup, err = catalog.FindBackupInfo(&v1.RecoveryTarget{TargetTLI: "2", TargetLSN: "0/9000000"}, history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101011200"))
+	})
+
+	It("ignores the history of a different timeline", func() {
+		backup, err := catalog.FindBackupInfo(&v1.RecoveryTarget{TargetTLI: "3"}, history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101031200"))
+	})
+
+	It("uses every backup when following the current timeline", func() {
+		backup, err := catalog.FindBackupInfo(&v1.RecoveryTarget{TargetTLI: "current"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backup.ID).To(Equal("202101031200"))
+	})
+})
+
 var _ = Describe("barman-cloud-backup-list parsing", func() {
 	const barmanCloudListOutput = `{
   "backups_list": [
@@ -161,6 +255,9 @@ var _ = Describe("barman-cloud-backup-list parsing", func() {
 		Expect(result.List[0].SystemID).To(Equal("6885668674852188181"))
 		Expect(result.List[0].BeginTimeString).To(Equal("Tue Oct 20 11:52:31 2020"))
 		Expect(result.List[0].EndTimeString).To(Equal("Tue Oct 20 11:52:34 2020"))
+		Expect(result.List[0].Version).To(Equal(120004))
+		Expect(result.List[0].MajorVersion()).To(Equal(12))
+		Expect(result.List[1].MajorVersion()).To(BeZero())
 	})
 
 	It("must extract the latest backup id", func() {
@@ -245,5 +342,20 @@ var _ = Describe("barman-cloud-backup-show parsing", func() {
 		Expect(result.SystemID).To(Equal("6885668674852188181"))
 		Expect(result.BeginTimeString).To(Equal("Tue Jan 19 03:14:08 2038"))
 		Expect(result.EndTimeString).To(Equal("Tue Jan 19 04:14:08 2038"))
+		Expect(result.MajorVersion()).To(Equal(15))
+		Expect(result.Size).To(BeZero())
+	})
+
+	It("must parse the size of the backup", func() {
+		result, err := NewBackupFromBarmanCloudBackupShow(`{
+			"cloud": {
+				"begin_time": "Tue Jan 19 03:14:08 2038",
+				"end_time": "Tue Jan 19 04:14:08 2038",
+				"size": 31464718,
+				"backup_id": "20201020T115231"
+			}
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Size).To(BeEquivalentTo(31464718))
 	})
 })
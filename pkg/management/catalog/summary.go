@@ -0,0 +1,132 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"sort"
+	"time"
+)
+
+// Summary is the content of the backup catalog of a server, as exposed
+// by the instance manager to the tools that need to list the base backups
+// and the interval of time they can be used to recover to
+type Summary struct {
+	// The name of the server in the object store
+	ServerName string `json:"serverName"`
+
+	// The base backups, the oldest one first
+	Backups []BackupSummary `json:"backups"`
+
+	// The earliest point in time the cluster can be recovered to,
+	// that is the end of the oldest successful base backup
+	FirstRecoverabilityPoint *time.Time `json:"firstRecoverabilityPoint,omitempty"`
+
+	// The end of the most recent successful base backup
+	LastSuccessfulBackup *time.Time `json:"lastSuccessfulBackup,omitempty"`
+
+	// The first WAL file needed to recover from the oldest
+	// successful base backup
+	FirstWAL string `json:"firstWAL,omitempty"`
+
+	// The last WAL file that has been archived, when the
+	// catalog is exposed by the primary instance
+	LastArchivedWAL string `json:"lastArchivedWAL,omitempty"`
+
+	// The time when the catalog has been read from the object store
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// BackupSummary is the information about a base backup
+// contained in the catalog
+type BackupSummary struct {
+	// The ID of the backup
+	ID string `json:"id"`
+
+	// The name of the backup, if available
+	Name string `json:"name,omitempty"`
+
+	// The moment when the backup started
+	BeginTime *time.Time `json:"beginTime,omitempty"`
+
+	// The moment when the backup ended
+	EndTime *time.Time `json:"endTime,omitempty"`
+
+	// The WAL files where the backup started and ended
+	BeginWAL string `json:"beginWAL,omitempty"`
+	EndWAL   string `json:"endWAL,omitempty"`
+
+	// The LSNs where the backup started and ended
+	BeginLSN string `json:"beginLSN,omitempty"`
+	EndLSN   string `json:"endLSN,omitempty"`
+
+	// The timeline of the backup
+	TimeLine int `json:"timeline,omitempty"`
+
+	// The version of the PostgreSQL server where the backup was
+	// taken, in the server_version_num format (i.e. 160002)
+	Version int `json:"version,omitempty"`
+
+	// The error that caused the backup to fail, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Summary creates the summary of the backup catalog
+func (catalog *Catalog) Summary(serverName string, retrievedAt time.Time) *Summary {
+	// the code below assumes the catalog to be sorted, therefore, we enforce it first
+	sort.Sort(catalog)
+
+	result := &Summary{
+		ServerName:               serverName,
+		Backups:                  make([]BackupSummary, 0, catalog.Len()),
+		FirstRecoverabilityPoint: catalog.FirstRecoverabilityPoint(),
+		RetrievedAt:              retrievedAt,
+	}
+
+	if firstBackup := catalog.FirstBackupInfo(); firstBackup != nil {
+		result.FirstWAL = firstBackup.BeginWal
+	}
+	if latestBackup := catalog.LatestBackupInfo(); latestBackup != nil {
+		result.LastSuccessfulBackup = &latestBackup.EndTime
+	}
+
+	for idx := range catalog.List {
+		backup := &catalog.List[idx]
+		result.Backups = append(result.Backups, BackupSummary{
+			ID:        backup.ID,
+			Name:      backup.BackupName,
+			BeginTime: timeOrNil(backup.BeginTime),
+			EndTime:   timeOrNil(backup.EndTime),
+			BeginWAL:  backup.BeginWal,
+			EndWAL:    backup.EndWal,
+			BeginLSN:  backup.BeginLSN,
+			EndLSN:    backup.EndLSN,
+			TimeLine:  backup.TimeLine,
+			Version:   backup.Version,
+			Error:     backup.Error,
+		})
+	}
+
+	return result
+}
+
+func timeOrNil(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// TimelineHistory is the content of the history file of a timeline,
+// listing the timelines it has been forked from
+type TimelineHistory struct {
+	// The timeline the history file refers to
+	TimeLine int
+
+	// The LSN where each of the parent timelines has been left,
+	// indexed by timeline
+	SwitchPoints map[int]postgres.LSN
+}
+
+// TimelineHistoryFileName gets the name of the history file of a timeline,
+// as it is archived by PostgreSQL
+func TimelineHistoryFileName(timeline int) string {
+	return fmt.Sprintf("%08X.history", timeline)
+}
+
+// NewTimelineHistory parses the content of the history file of a timeline
+func NewTimelineHistory(timeline int, content string) (*TimelineHistory, error) {
+	history := &TimelineHistory{
+		TimeLine:     timeline,
+		SwitchPoints: make(map[int]postgres.LSN),
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid line in the history of timeline %d: %q", timeline, line)
+		}
+
+		parent, err := strconv.Atoi(fields[0])
+		if err != nil || parent < 1 || parent >= timeline {
+			return nil, fmt.Errorf("invalid parent timeline in the history of timeline %d: %q", timeline, line)
+		}
+
+		switchPoint := postgres.LSN(fields[1])
+		if _, err := switchPoint.Parse(); err != nil {
+			return nil, fmt.Errorf("invalid switch point in the history of timeline %d: %w", timeline, err)
+		}
+
+		history.SwitchPoints[parent] = switchPoint
+	}
+
+	return history, nil
+}
+
+// Includes checks if the WAL generated in the passed timeline, up to the
+// passed LSN, is part of the history of this timeline. This happens when
+// the passed timeline is this one, or one of its parents which was left
+// after that LSN
+func (history *TimelineHistory) Includes(timeline int, lsn postgres.LSN) bool {
+	if timeline == history.TimeLine {
+		return true
+	}
+
+	switchPoint, ok := history.SwitchPoints[timeline]
+	if !ok {
+		return false
+	}
+
+	return !switchPoint.Less(lsn)
+}
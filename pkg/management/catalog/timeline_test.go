@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeline history", func() {
+	const historyContent = `1	0/5000000	no recovery target specified
+
+2	0/7000000	before 2021-01-02 12:00:00+00
+`
+
+	It("gets the name of the history file", func() {
+		Expect(TimelineHistoryFileName(3)).To(Equal("00000003.history"))
+		Expect(TimelineHistoryFileName(26)).To(Equal("0000001A.history"))
+	})
+
+	It("parses the history file", func() {
+		history, err := NewTimelineHistory(3, historyContent)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history.TimeLine).To(Equal(3))
+		Expect(history.SwitchPoints).To(Equal(map[int]postgres.LSN{
+			1: "0/5000000",
+			2: "0/7000000",
+		}))
+	})
+
+	It("rejects the parents which are not older than the timeline", func() {
+		_, err := NewTimelineHistory(2, historyContent)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects invalid switch points", func() {
+		_, err := NewTimelineHistory(2, "1\tinvalid\n")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("checks which WAL is part of the history", func() {
+		history, err := NewTimelineHistory(3, historyContent)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history.Includes(3, "1/0")).To(BeTrue())
+		Expect(history.Includes(2, "0/6000000")).To(BeTrue())
+		Expect(history.Includes(2, "0/8000000")).To(BeFalse())
+		Expect(history.Includes(1, "0/5000000")).To(BeTrue())
+		Expect(history.Includes(1, "0/6000000")).To(BeFalse())
+		Expect(history.Includes(4, "0/1000000")).To(BeFalse())
+	})
+})
@@ -34,3 +34,28 @@ func (w *LogWriter) Write(p []byte) (int, error) {
 
 	return len(p), nil
 }
+
+// BufferedLogWriter implements the `Writer` interface keeping the lines
+// in memory, so that they can be logged once the outcome of the command
+// is known
+type BufferedLogWriter struct {
+	lines []string
+}
+
+// Write stores the given slice of bytes as a line
+func (w *BufferedLogWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		w.lines = append(w.lines, string(p))
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the stored lines with the passed logging function,
+// i.e. the Info or the Debug method of a logger
+func (w *BufferedLogWriter) Flush(logFunc func(msg string, keysAndValues ...interface{})) {
+	for _, line := range w.lines {
+		logFunc(line)
+	}
+	w.lines = nil
+}
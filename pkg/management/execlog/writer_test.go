@@ -33,3 +33,26 @@ var _ = Describe("Writing to a LogWriter", func() {
 		})
 	})
 })
+
+var _ = Describe("Writing to a BufferedLogWriter", func() {
+	It("logs the lines only when flushed", func() {
+		var logged []string
+		logFunc := func(msg string, _ ...interface{}) {
+			logged = append(logged, msg)
+		}
+
+		var w BufferedLogWriter
+		n, err := w.Write([]byte("first line"))
+		Expect(n).To(Equal(10))
+		Expect(err).ToNot(HaveOccurred())
+		_, _ = w.Write(nil)
+		_, _ = w.Write([]byte("second line"))
+		Expect(logged).To(BeEmpty())
+
+		w.Flush(logFunc)
+		Expect(logged).To(Equal([]string{"first line", "second line"}))
+
+		w.Flush(logFunc)
+		Expect(logged).To(HaveLen(2))
+	})
+})
@@ -87,8 +87,8 @@ local pgbouncer pgbouncer peer
 {{ $rule -}}
 {{ end }}
 
-host all all 0.0.0.0/0 md5
-host all all ::/0 md5
+{{ .ClientAuthRecordType }} all all 0.0.0.0/0 {{ .ClientAuthMethod }}
+{{ .ClientAuthRecordType }} all all ::/0 {{ .ClientAuthMethod }}
 `
 
 	pgBouncerUserListTemplateString = `
@@ -182,6 +182,17 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		parameters["auth_file"] = authFilePath
 	}
 
+	if pooler.Spec.PgBouncer.AuthDBName != "" {
+		parameters["auth_dbname"] = pooler.Spec.PgBouncer.AuthDBName
+	}
+
+	// The cert authentication method can only be used with TLS connections
+	clientAuthMethod := pooler.Spec.PgBouncer.GetClientAuthMethod()
+	clientAuthRecordType := "host"
+	if clientAuthMethod == apiv1.PgBouncerClientAuthMethodCert {
+		clientAuthRecordType = "hostssl"
+	}
+
 	templateData := struct {
 		Pooler            *apiv1.Pooler
 		AuthQuery         string
@@ -189,6 +200,9 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		AuthQueryPassword string
 		Parameters        string
 		PgHba             []string
+
+		ClientAuthMethod     apiv1.PgBouncerClientAuthMethod
+		ClientAuthRecordType string
 	}{
 		Pooler:            pooler,
 		AuthQuery:         pooler.GetAuthQuery(),
@@ -203,6 +217,9 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		// to be stable.
 		Parameters: stringifyPgBouncerParameters(parameters),
 		PgHba:      pooler.Spec.PgBouncer.PgHBA,
+
+		ClientAuthMethod:     clientAuthMethod,
+		ClientAuthRecordType: clientAuthRecordType,
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
@@ -0,0 +1,104 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PgBouncer configuration files", func() {
+	var (
+		pooler  *apiv1.Pooler
+		secrets *Secrets
+	)
+
+	BeforeEach(func() {
+		pooler = &apiv1.Pooler{
+			ObjectMeta: metav1.ObjectMeta{Name: "pooler-example", Namespace: "default"},
+			Spec: apiv1.PoolerSpec{
+				Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+				Type:    apiv1.PoolerTypeRW,
+				PgBouncer: &apiv1.PgBouncerSpec{
+					PoolMode: apiv1.PgBouncerPoolModeSession,
+				},
+			},
+		}
+
+		secrets = &Secrets{
+			AuthQuery: &corev1.Secret{
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{
+					corev1.BasicAuthUsernameKey: []byte("service"),
+					corev1.BasicAuthPasswordKey: []byte("secret"),
+				},
+			},
+			ServerCA: &corev1.Secret{},
+			Client:   &corev1.Secret{},
+			ClientCA: &corev1.Secret{},
+		}
+	})
+
+	getFile := func(files ConfigurationFiles, name string) string {
+		return string(files[filepath.Join(ConfigsDir, name)])
+	}
+
+	It("authenticates the clients with md5 by default", func() {
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+
+		hba := getFile(files, PgBouncerHBAConfFileName)
+		Expect(hba).To(ContainSubstring("host all all 0.0.0.0/0 md5\n"))
+		Expect(hba).To(ContainSubstring("host all all ::/0 md5\n"))
+		Expect(getFile(files, PgBouncerIniFileName)).ToNot(ContainSubstring("auth_dbname"))
+		Expect(getFile(files, PgBouncerUserListFileName)).To(ContainSubstring(`"service" "secret"`))
+	})
+
+	It("authenticates the clients with SCRAM-SHA-256 when requested", func() {
+		pooler.Spec.PgBouncer.ClientAuthMethod = apiv1.PgBouncerClientAuthMethodScram
+
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+
+		hba := getFile(files, PgBouncerHBAConfFileName)
+		Expect(hba).To(ContainSubstring("host all all 0.0.0.0/0 scram-sha-256\n"))
+		Expect(hba).To(ContainSubstring("local pgbouncer pgbouncer peer"))
+	})
+
+	It("requires TLS with the cert authentication method", func() {
+		pooler.Spec.PgBouncer.ClientAuthMethod = apiv1.PgBouncerClientAuthMethodCert
+
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getFile(files, PgBouncerHBAConfFileName)).To(ContainSubstring("hostssl all all 0.0.0.0/0 cert\n"))
+	})
+
+	It("runs the authentication query in the requested database", func() {
+		pooler.Spec.PgBouncer.AuthDBName = "auth"
+
+		files, err := BuildConfigurationFiles(pooler, secrets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getFile(files, PgBouncerIniFileName)).To(ContainSubstring("auth_dbname = auth\n"))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"math"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// WALGenerationRate is the rate at which the primary
+// instance is generating WAL
+type WALGenerationRate struct {
+	// BytesPerSecond is the amount of WAL generated every second
+	BytesPerSecond float64
+
+	// SegmentSize is the size of the WAL segments, in bytes
+	SegmentSize int64
+}
+
+// computeArchiveTimeout gets the archive_timeout, in seconds, to be used
+// at the passed WAL generation rate, within the configured range.
+//
+// The WAL segments are archived when they are full or when archive_timeout
+// expires, whichever comes first. Using half the time needed to fill a
+// segment, archive_timeout always expires first, so that the RPO is
+// bounded by archive_timeout itself, while every archived segment still
+// contains about half a segment of WAL. When the rate is unknown, or
+// the cluster is idle, the highest value is used.
+func computeArchiveTimeout(
+	rate *WALGenerationRate,
+	configuration *apiv1.AdaptiveArchiveTimeoutConfiguration,
+) int32 {
+	minTimeout := configuration.GetMinTimeout().Seconds()
+	maxTimeout := configuration.GetMaxTimeout().Seconds()
+
+	timeout := maxTimeout
+	if rate != nil && rate.BytesPerSecond > 0 && rate.SegmentSize > 0 {
+		fillTime := float64(rate.SegmentSize) / rate.BytesPerSecond
+		timeout = math.Min(maxTimeout, math.Max(minTimeout, fillTime/2))
+	}
+
+	return int32(math.Round(timeout))
+}
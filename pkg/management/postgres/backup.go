@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -106,8 +107,9 @@ func getDataConfiguration(
 		return options, nil
 	}
 
-	if configuration.Data.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
-		return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
+	if !capabilities.SupportsCompression(configuration.Data.Compression) {
+		return nil, fmt.Errorf("%v compression is not supported in Barman %v",
+			configuration.Data.Compression, capabilities.Version)
 	}
 
 	if len(configuration.Data.Compression) != 0 {
@@ -136,9 +138,71 @@ func getDataConfiguration(
 			strconv.Itoa(int(*configuration.Data.Jobs)))
 	}
 
+	if maxBandwidth := getEffectiveMaxBandwidth(configuration, capabilities); maxBandwidth != nil {
+		options = append(
+			options,
+			"--max-bandwidth",
+			strconv.FormatInt(maxBandwidth.Value(), 10))
+	}
+
 	return configuration.Data.AppendAdditionalCommandArgs(options), nil
 }
 
+// getEffectiveMaxBandwidth gets the upload bandwidth limit that will be
+// applied to the backup, or nil if the backup is not throttled
+func getEffectiveMaxBandwidth(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	capabilities *barmanCapabilities.Capabilities,
+) *resource.Quantity {
+	if configuration.Data == nil || configuration.Data.MaxBandwidth == nil || !capabilities.HasMaxBandwidth {
+		return nil
+	}
+
+	return configuration.Data.MaxBandwidth
+}
+
+// reportMaxBandwidth records the upload bandwidth limit that is applied
+// to the backup, warning the user when it can't be honored
+func (b *BackupCommand) reportMaxBandwidth(configuration *apiv1.BarmanObjectStoreConfiguration) {
+	if configuration.Data == nil || configuration.Data.MaxBandwidth == nil {
+		return
+	}
+
+	maxBandwidth := getEffectiveMaxBandwidth(configuration, b.Capabilities)
+	if maxBandwidth == nil {
+		b.Log.Warning("The backup bandwidth limit is not supported by this Barman version, ignoring it",
+			"maxBandwidth", configuration.Data.MaxBandwidth.String(),
+			"barmanVersion", b.Capabilities.Version)
+		b.Recorder.Eventf(b.Backup, "Warning", "MaxBandwidthIgnored",
+			"Backup bandwidth limit of %s/s ignored, Barman %v doesn't support it",
+			configuration.Data.MaxBandwidth.String(), b.Capabilities.Version)
+		return
+	}
+
+	b.Log.Info("Limiting the backup upload bandwidth", "maxBandwidth", maxBandwidth.String())
+	b.Recorder.Eventf(b.Backup, "Normal", "MaxBandwidth",
+		"Backup upload bandwidth limited to %s/s", maxBandwidth.String())
+}
+
+// withBackupImmediateCheckpoint returns the Barman configuration to be used
+// for the passed backup, applying the immediate checkpoint setting of the
+// backup, when specified, over the one of the cluster
+func withBackupImmediateCheckpoint(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	backup *apiv1.Backup,
+) *apiv1.BarmanObjectStoreConfiguration {
+	if backup == nil || backup.Spec.ImmediateCheckpoint == nil {
+		return configuration
+	}
+
+	result := configuration.DeepCopy()
+	if result.Data == nil {
+		result.Data = &apiv1.DataBackupConfiguration{}
+	}
+	result.Data.ImmediateCheckpoint = *backup.Spec.ImmediateCheckpoint
+	return result
+}
+
 // getBarmanCloudBackupOptions extract the list of command line options to be used with
 // barman-cloud-backup
 func (b *BackupCommand) getBarmanCloudBackupOptions(
@@ -153,7 +217,11 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 		options = append(options, "--name", b.Backup.Status.BackupName)
 	}
 
-	options, err := getDataConfiguration(options, configuration, b.Capabilities)
+	options, err := getDataConfiguration(
+		options,
+		withBackupImmediateCheckpoint(configuration, b.Backup),
+		b.Capabilities,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +326,7 @@ func (b *BackupCommand) retryWithRefreshedCluster(
 // This method will take long time and is supposed to run inside a dedicated
 // goroutine.
 func (b *BackupCommand) run(ctx context.Context) {
-	if err := b.takeBackup(ctx); err != nil {
+	if err := b.takeBackupWithHooks(ctx); err != nil {
 		backupStatus := b.Backup.GetStatus()
 
 		// record the failure
@@ -304,6 +372,7 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	// record the backup beginning
 	b.Log.Info("Starting barman-cloud-backup", "options", options)
 	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+	b.reportMaxBandwidth(barmanConfiguration)
 
 	// Update backup status in cluster conditions on startup
 	if err := b.retryWithRefreshedCluster(ctx, func() error {
@@ -349,8 +418,15 @@ func (b *BackupCommand) takeBackup(ctx context.Context) error {
 	b.Log.Debug("extracted barman backup", "backup", barmanBackup)
 	assignBarmanBackupToBackup(b.Backup, barmanBackup)
 
+	// Flag the backup to be exported to the archive tier, if needed
+	b.prepareArchiveTierExport(ctx)
+
 	if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
 		b.Log.Error(err, "Can't set backup status as completed")
+
+		// An export which hasn't been recorded wouldn't be counted
+		// among the running backups, bypassing their limit
+		b.Backup.Status.ArchiveTierExport = nil
 	}
 
 	// Update backup status in cluster conditions on backup completion
@@ -397,6 +473,10 @@ func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 		}
 	}
 
+	// Export the backup to the archive tier, if needed, and
+	// delete the backups there per policy
+	b.archiveTierMaintenance(ctx)
+
 	// Extracting the latest backup using barman-cloud-backup-list
 	backupList, err := barman.GetBackupList(
 		ctx,
@@ -428,8 +508,8 @@ func (b *BackupCommand) backupMaintenance(ctx context.Context) {
 	}
 }
 
-// updateClusterStatusWithBackupTimes updates the last successful backup time and first
-// recoverability point for the cluster
+// updateClusterStatusWithBackupTimes updates the last successful backup time, the first
+// recoverability point and the first WAL file required to recover the cluster
 func updateClusterStatusWithBackupTimes(cluster *apiv1.Cluster, backupList *catalog.Catalog) {
 	firstRecoverabilityPoint := backupList.FirstRecoverabilityPoint()
 	var lastSuccessfulBackup *time.Time
@@ -438,6 +518,15 @@ func updateClusterStatusWithBackupTimes(cluster *apiv1.Cluster, backupList *cata
 	}
 
 	cluster.UpdateBackupTimes(apiv1.BackupMethodBarmanObjectStore, firstRecoverabilityPoint, lastSuccessfulBackup)
+
+	cluster.Status.FirstRequiredWAL = ""
+	cluster.Status.FirstRequiredWALTime = ""
+	if firstBackupInfo := backupList.FirstBackupInfo(); firstBackupInfo != nil && firstBackupInfo.BeginWal != "" {
+		cluster.Status.FirstRequiredWAL = firstBackupInfo.BeginWal
+		if !firstBackupInfo.BeginTime.IsZero() {
+			cluster.Status.FirstRequiredWALTime = firstBackupInfo.BeginTime.Format(time.RFC3339)
+		}
+	}
 }
 
 // PatchBackupStatusAndRetry updates a certain backup's status in the k8s database,
@@ -475,6 +564,7 @@ func (b *BackupCommand) setupBackupStatus() {
 	backupStatus.BarmanCredentials = barmanConfiguration.BarmanCredentials
 	backupStatus.EndpointCA = barmanConfiguration.EndpointCA
 	backupStatus.EndpointURL = barmanConfiguration.EndpointURL
+	backupStatus.ForcePathStyle = barmanConfiguration.ForcePathStyle
 	backupStatus.DestinationPath = barmanConfiguration.DestinationPath
 	if barmanConfiguration.Data != nil {
 		backupStatus.Encryption = string(barmanConfiguration.Data.Encryption)
@@ -499,4 +589,6 @@ func assignBarmanBackupToBackup(backup *apiv1.Backup, barmanBackup *catalog.Barm
 	backupStatus.EndWal = barmanBackup.EndWal
 	backupStatus.BeginLSN = barmanBackup.BeginLSN
 	backupStatus.EndLSN = barmanBackup.EndLSN
+	backupStatus.MajorVersion = barmanBackup.MajorVersion()
+	backupStatus.Size = barmanBackup.Size
 }
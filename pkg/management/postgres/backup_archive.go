@@ -0,0 +1,213 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// prepareArchiveTierExport flags the completed backup to be exported to
+// the archive tier, when the latest backup there is outdated. The export
+// is flagged in the same status update completing the backup, so that the
+// backup keeps being counted among the running ones until it is exported
+func (b *BackupCommand) prepareArchiveTierExport(ctx context.Context) {
+	destination := b.Cluster.Spec.Backup.GetArchiveDestination()
+	if destination == nil {
+		return
+	}
+
+	outdated, err := b.isArchiveTierOutdated(ctx, destination)
+	if err != nil {
+		b.Log.Error(err, "Cannot check the archive tier", "destination", destination.Name)
+		b.Recorder.Eventf(b.Cluster, "Warning", "ArchiveTierExportFailed",
+			"Cannot check if backup %s needs to be exported to the archive tier: %v", b.Backup.Name, err)
+		return
+	}
+	if !outdated {
+		b.Log.Debug("The archive tier is up to date, skipping the export", "destination", destination.Name)
+		return
+	}
+
+	b.Backup.Status.ArchiveTierExport = &apiv1.BackupArchiveTierExportStatus{
+		DestinationName: destination.Name,
+		Phase:           apiv1.BackupArchiveTierExportPhaseRunning,
+		StartedAt:       ptr.To(metav1.Now()),
+	}
+}
+
+// archiveTierMaintenance exports the backup to the archive tier, when
+// it has been flagged to, and applies the retention policy of the
+// archive tier
+func (b *BackupCommand) archiveTierMaintenance(ctx context.Context) {
+	destination := b.Cluster.Spec.Backup.GetArchiveDestination()
+
+	if b.Backup.Status.IsArchiveTierExportRunning() {
+		b.exportToArchiveTier(ctx, destination)
+	}
+
+	if destination == nil || b.Cluster.Spec.Backup.Archive.RetentionPolicy == "" {
+		return
+	}
+
+	env, err := b.getArchiveTierEnv(ctx, destination)
+	if err != nil {
+		b.Log.Error(err, "Cannot recover the credentials of the archive tier")
+		return
+	}
+
+	b.Log.Info("Applying backup retention policy to the archive tier",
+		"retentionPolicy", b.Cluster.Spec.Backup.Archive.RetentionPolicy)
+	archiveConfiguration := &apiv1.BackupConfiguration{
+		BarmanObjectStore: &destination.BarmanObjectStoreConfiguration,
+		RetentionPolicy:   b.Cluster.Spec.Backup.Archive.RetentionPolicy,
+	}
+	if err := barman.DeleteBackupsByPolicy(
+		ctx,
+		archiveConfiguration,
+		getArchiveTierServerName(b.Cluster, destination),
+		env,
+	); err != nil {
+		// Proper logging already happened inside DeleteBackupsByPolicy
+		b.Recorder.Event(b.Cluster, "Warning", "RetentionPolicyFailed",
+			"Retention policy of the archive tier failed")
+	}
+}
+
+// exportToArchiveTier copies the completed backup, together with the WAL
+// files needed to restore it, to the archive tier, recording the outcome
+// in the status of the backup. The WAL files are copied from the primary
+// object store, as the archive tier may have missed some of them
+func (b *BackupCommand) exportToArchiveTier(ctx context.Context, destination *apiv1.WalArchiveDestination) {
+	export := b.Backup.Status.ArchiveTierExport
+
+	err := b.copyToArchiveTier(ctx, destination)
+	export.StoppedAt = ptr.To(metav1.Now())
+	if err != nil {
+		b.Log.Error(err, "Cannot export the backup to the archive tier", "destination", export.DestinationName)
+		b.Recorder.Eventf(b.Cluster, "Warning", "ArchiveTierExportFailed",
+			"Export of backup %s to the archive tier failed: %v", b.Backup.Name, err)
+		export.Phase = apiv1.BackupArchiveTierExportPhaseFailed
+		export.Message = err.Error()
+	} else {
+		b.Recorder.Eventf(b.Cluster, "Normal", "ArchiveTierExported",
+			"Backup %s exported to the archive tier %s", b.Backup.Name, export.DestinationName)
+		export.Phase = apiv1.BackupArchiveTierExportPhaseCompleted
+	}
+
+	if err := PatchBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set the outcome of the export to the archive tier")
+	}
+}
+
+// copyToArchiveTier copies the completed backup to the archive tier
+func (b *BackupCommand) copyToArchiveTier(ctx context.Context, destination *apiv1.WalArchiveDestination) error {
+	if destination == nil || destination.Name != b.Backup.Status.ArchiveTierExport.DestinationName {
+		return fmt.Errorf("the archive tier %s is not configured anymore",
+			b.Backup.Status.ArchiveTierExport.DestinationName)
+	}
+
+	env, err := b.getArchiveTierEnv(ctx, destination)
+	if err != nil {
+		return fmt.Errorf("while getting the credentials of the archive tier: %w", err)
+	}
+
+	b.Log.Info("Exporting the backup to the archive tier", "destination", destination.Name)
+	return barman.CopyBackup(
+		ctx,
+		b.Backup.Status.BackupID,
+		b.Cluster.Spec.Backup.BarmanObjectStore,
+		b.Backup.Status.ServerName,
+		b.Env,
+		&destination.BarmanObjectStoreConfiguration,
+		getArchiveTierServerName(b.Cluster, destination),
+		env,
+	)
+}
+
+// isArchiveTierOutdated checks if the latest base backup in the
+// archive tier is older than archiveAfter
+func (b *BackupCommand) isArchiveTierOutdated(
+	ctx context.Context,
+	destination *apiv1.WalArchiveDestination,
+) (bool, error) {
+	archiveAfter, err := utils.ParsePolicyDuration(b.Cluster.Spec.Backup.Archive.ArchiveAfter)
+	if err != nil {
+		return false, err
+	}
+
+	env, err := b.getArchiveTierEnv(ctx, destination)
+	if err != nil {
+		return false, err
+	}
+
+	backupList, err := barman.GetBackupList(
+		ctx,
+		&destination.BarmanObjectStoreConfiguration,
+		getArchiveTierServerName(b.Cluster, destination),
+		env)
+	if err != nil {
+		return false, err
+	}
+
+	return isCatalogOutdated(backupList, archiveAfter, time.Now()), nil
+}
+
+// getArchiveTierEnv gets the environment to access the archive tier
+func (b *BackupCommand) getArchiveTierEnv(
+	ctx context.Context,
+	destination *apiv1.WalArchiveDestination,
+) ([]string, error) {
+	return barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		b.Client,
+		b.Cluster.Namespace,
+		&destination.BarmanObjectStoreConfiguration,
+		os.Environ())
+}
+
+// getArchiveTierServerName gets the name of the server in the archive
+// tier, which defaults to the name of the cluster
+func getArchiveTierServerName(cluster *apiv1.Cluster, destination *apiv1.WalArchiveDestination) string {
+	if destination.ServerName != "" {
+		return destination.ServerName
+	}
+
+	return cluster.Name
+}
+
+// isCatalogOutdated checks if the latest base backup in the
+// catalog is older than archiveAfter, or if there is none
+func isCatalogOutdated(backupList *catalog.Catalog, archiveAfter time.Duration, now time.Time) bool {
+	latestBackup := backupList.LatestBackupInfo()
+	if latestBackup == nil {
+		return true
+	}
+
+	return now.Sub(latestBackup.EndTime) >= archiveAfter
+}
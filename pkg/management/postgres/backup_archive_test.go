@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("archive tier", func() {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	archiveAfter := 30 * 24 * time.Hour
+
+	It("is outdated when it contains no base backup", func() {
+		Expect(isCatalogOutdated(catalog.NewCatalog(nil), archiveAfter, now)).To(BeTrue())
+	})
+
+	It("is outdated when the latest base backup is older than archiveAfter", func() {
+		backupList := catalog.NewCatalog([]catalog.BarmanBackup{
+			{
+				ID:        "202405010000",
+				BeginTime: now.Add(-40 * 24 * time.Hour),
+				EndTime:   now.Add(-40*24*time.Hour + time.Hour),
+			},
+		})
+		Expect(isCatalogOutdated(backupList, archiveAfter, now)).To(BeTrue())
+	})
+
+	It("is up to date when the latest base backup is younger than archiveAfter", func() {
+		backupList := catalog.NewCatalog([]catalog.BarmanBackup{
+			{
+				ID:        "202405010000",
+				BeginTime: now.Add(-40 * 24 * time.Hour),
+				EndTime:   now.Add(-40*24*time.Hour + time.Hour),
+			},
+			{
+				ID:        "202406200000",
+				BeginTime: now.Add(-10 * 24 * time.Hour),
+				EndTime:   now.Add(-10*24*time.Hour + time.Hour),
+			},
+		})
+		Expect(isCatalogOutdated(backupList, archiveAfter, now)).To(BeFalse())
+	})
+
+	It("ignores the base backups that are not completed", func() {
+		backupList := catalog.NewCatalog([]catalog.BarmanBackup{
+			{
+				ID:        "202406200000",
+				BeginTime: now.Add(-10 * 24 * time.Hour),
+			},
+		})
+		Expect(isCatalogOutdated(backupList, archiveAfter, now)).To(BeTrue())
+	})
+})
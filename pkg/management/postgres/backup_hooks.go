@@ -0,0 +1,100 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+)
+
+// takeBackupWithHooks takes the backup, executing the pre-backup hooks
+// before it and the post-backup hooks after it, whatever its outcome
+func (b *BackupCommand) takeBackupWithHooks(ctx context.Context) error {
+	var backupErr error
+	for _, hook := range b.Cluster.Spec.Backup.GetPreBackupHooks() {
+		if backupErr = b.runBackupHook(ctx, hook, b.getBackupHookEnv(nil)); backupErr != nil {
+			backupErr = fmt.Errorf("pre-backup hook %s failed: %w", hook.Name, backupErr)
+			break
+		}
+	}
+
+	if backupErr == nil {
+		backupErr = b.takeBackup(ctx)
+	}
+
+	for _, hook := range b.Cluster.Spec.Backup.GetPostBackupHooks() {
+		if err := b.runBackupHook(ctx, hook, b.getBackupHookEnv(backupErr)); err != nil {
+			b.Log.Error(err, "Post-backup hook failed", "hook", hook.Name)
+			b.Recorder.Eventf(b.Backup, "Warning", "PostBackupHookFailed",
+				"Post-backup hook %s failed: %v", hook.Name, err)
+		}
+	}
+
+	return backupErr
+}
+
+// runBackupHook executes a backup hook, terminating it when
+// its timeout expires
+func (b *BackupCommand) runBackupHook(ctx context.Context, hook apiv1.BackupHook, env []string) error {
+	b.Log.Info("Running backup hook", "hook", hook.Name, "command", hook.Command)
+
+	hookCtx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	cmd := exec.CommandContext(hookCtx, hook.Command[0], hook.Command[1:]...) // #nosec G204
+	cmd.Env = env
+	err := execlog.RunStreaming(cmd, "backup-hook-"+hook.Name)
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %v", hook.GetTimeout())
+	}
+
+	return err
+}
+
+// getBackupHookEnv gets the environment of the backup hooks, containing
+// the context of the backup. The outcome of the backup is only known by
+// the post-backup hooks.
+func (b *BackupCommand) getBackupHookEnv(backupErr error) []string {
+	backupStatus := b.Backup.GetStatus()
+
+	env := append(os.Environ(),
+		"CNPG_CLUSTER_NAME="+b.Cluster.Name,
+		"CNPG_NAMESPACE="+b.Cluster.Namespace,
+		"CNPG_BACKUP_NAME="+b.Backup.Name,
+	)
+
+	switch {
+	case backupErr != nil:
+		env = append(env,
+			"CNPG_BACKUP_PHASE="+string(apiv1.BackupPhaseFailed),
+			"CNPG_BACKUP_ERROR="+backupErr.Error())
+	case backupStatus.Phase == apiv1.BackupPhaseCompleted:
+		env = append(env,
+			"CNPG_BACKUP_PHASE="+string(apiv1.BackupPhaseCompleted),
+			"CNPG_BACKUP_ID="+backupStatus.BackupID)
+	default:
+		env = append(env, "CNPG_BACKUP_PHASE="+string(backupStatus.Phase))
+	}
+
+	return env
+}
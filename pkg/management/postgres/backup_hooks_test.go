@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup hooks", func() {
+	var (
+		backupCommand *BackupCommand
+		recorder      *record.FakeRecorder
+		outputFile    string
+	)
+
+	BeforeEach(func() {
+		outputFile = path.Join(GinkgoT().TempDir(), "output")
+		recorder = record.NewFakeRecorder(10)
+		backupCommand = &BackupCommand{
+			Cluster: &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test"},
+				Spec: apiv1.ClusterSpec{
+					Backup: &apiv1.BackupConfiguration{
+						Hooks: &apiv1.BackupHooksConfiguration{
+							PostBackup: []apiv1.BackupHook{
+								{
+									Name: "notify",
+									Command: []string{
+										"sh", "-c",
+										`echo "$CNPG_BACKUP_NAME $CNPG_BACKUP_PHASE $CNPG_BACKUP_ERROR" > ` + outputFile,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Backup: &apiv1.Backup{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: "test"},
+			},
+			Recorder: recorder,
+			Log:      log.FromContext(context.Background()),
+		}
+	})
+
+	readOutput := func() string {
+		content, err := os.ReadFile(outputFile) // #nosec G304
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("fails the backup without taking it when a pre-backup hook fails", func(ctx SpecContext) {
+		backupCommand.Cluster.Spec.Backup.Hooks.PreBackup = []apiv1.BackupHook{
+			{Name: "quiesce", Command: []string{"false"}},
+			{Name: "never", Command: []string{"touch", outputFile + "-never"}},
+		}
+
+		err := backupCommand.takeBackupWithHooks(ctx)
+		Expect(err).To(MatchError(ContainSubstring("pre-backup hook quiesce failed")))
+		Expect(outputFile + "-never").ToNot(BeAnExistingFile())
+		Expect(readOutput()).To(HavePrefix("test-backup failed pre-backup hook quiesce failed"))
+	})
+
+	It("terminates the hooks that don't complete within their timeout", func(ctx SpecContext) {
+		hook := apiv1.BackupHook{Name: "slow", Command: []string{"sleep", "30"}, Timeout: 1}
+
+		err := backupCommand.runBackupHook(ctx, hook, backupCommand.getBackupHookEnv(nil))
+		Expect(err).To(MatchError("timed out after 1s"))
+	})
+
+	It("reports the failure of a post-backup hook as an event", func(ctx SpecContext) {
+		backupCommand.Cluster.Spec.Backup.Hooks.PreBackup = []apiv1.BackupHook{
+			{Name: "quiesce", Command: []string{"false"}},
+		}
+		backupCommand.Cluster.Spec.Backup.Hooks.PostBackup = []apiv1.BackupHook{
+			{Name: "resume", Command: []string{"false"}},
+		}
+
+		Expect(backupCommand.takeBackupWithHooks(ctx)).ToNot(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("PostBackupHookFailed")))
+	})
+
+	It("passes the outcome of a completed backup to the post-backup hooks", func() {
+		backupCommand.Backup.Status.Phase = apiv1.BackupPhaseCompleted
+		backupCommand.Backup.Status.BackupID = "20240601T100000"
+
+		env := backupCommand.getBackupHookEnv(nil)
+		Expect(env).To(ContainElements(
+			"CNPG_CLUSTER_NAME=test-cluster",
+			"CNPG_NAMESPACE=test",
+			"CNPG_BACKUP_NAME=test-backup",
+			"CNPG_BACKUP_PHASE=completed",
+			"CNPG_BACKUP_ID=20240601T100000",
+		))
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
@@ -175,6 +176,7 @@ var _ = Describe("update barman backup metadata", func() {
 					BackupName: "twoHoursAgo",
 					BeginTime:  threeHoursAgo.Time,
 					EndTime:    twoHoursAgo.Time,
+					BeginWal:   "000000010000000000000002",
 				},
 				{
 					BackupName: "youngest",
@@ -203,6 +205,8 @@ var _ = Describe("update barman backup metadata", func() {
 			To(Equal(oneHourAgo))
 		Expect(cluster.Status.LastSuccessfulBackupByMethod).
 			ToNot(HaveKey(apiv1.BackupMethodVolumeSnapshot))
+		Expect(cluster.Status.FirstRequiredWAL).To(Equal("000000010000000000000002"))
+		Expect(cluster.Status.FirstRequiredWALTime).To(Equal(threeHoursAgo.Format(time.RFC3339)))
 	})
 
 	It("will update the metadata if they are outdated", func() {
@@ -325,4 +329,46 @@ var _ = Describe("generate backup options", func() {
 						"--min-chunk-size=5MB --read-timeout=60 -vv",
 				))
 	})
+
+	It("should honor the immediate checkpoint setting of the backup", func() {
+		cluster.Spec.Backup.BarmanObjectStore.Data.AdditionalCommandArgs = nil
+		backup := &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				ImmediateCheckpoint: ptr.To(false),
+			},
+		}
+		configuration := withBackupImmediateCheckpoint(cluster.Spec.Backup.BarmanObjectStore, backup)
+		options, err := getDataConfiguration([]string{}, configuration, &capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Join(options, " ")).To(Equal("--gzip --encryption aes256 --jobs 2"))
+		Expect(cluster.Spec.Backup.BarmanObjectStore.Data.ImmediateCheckpoint).To(BeTrue())
+
+		configuration = withBackupImmediateCheckpoint(&apiv1.BarmanObjectStoreConfiguration{}, &apiv1.Backup{
+			Spec: apiv1.BackupSpec{
+				ImmediateCheckpoint: ptr.To(true),
+			},
+		})
+		options, err = getDataConfiguration([]string{}, configuration, &capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--immediate-checkpoint"}))
+	})
+
+	It("should limit the bandwidth only when supported by Barman", func() {
+		configuration := &apiv1.BarmanObjectStoreConfiguration{
+			Data: &apiv1.DataBackupConfiguration{
+				MaxBandwidth: ptr.To(resource.MustParse("50Mi")),
+			},
+		}
+		options, err := getDataConfiguration([]string{}, configuration, &capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(BeEmpty())
+		Expect(getEffectiveMaxBandwidth(configuration, &capabilities)).To(BeNil())
+
+		supportedCapabilities := capabilities
+		supportedCapabilities.HasMaxBandwidth = true
+		options, err = getDataConfiguration([]string{}, configuration, &supportedCapabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--max-bandwidth", "52428800"}))
+		Expect(getEffectiveMaxBandwidth(configuration, &supportedCapabilities).String()).To(Equal("50Mi"))
+	})
 })
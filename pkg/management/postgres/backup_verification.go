@@ -0,0 +1,184 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// VerifyBackup restores the latest base backup available in the object
+// store of the cluster into the (empty) data directory, replays the
+// archived WALs and checks that PostgreSQL can exit recovery mode.
+// The outcome is recorded in the status of the cluster.
+func (info InitInfo) VerifyBackup(ctx context.Context, cli client.Client) error {
+	contextLogger := log.FromContext(ctx)
+
+	cluster, err := info.loadCluster(ctx, cli)
+	if err != nil {
+		return err
+	}
+
+	backupID, verifyErr := info.verifyLatestBackup(ctx, cli, cluster)
+	if verifyErr != nil {
+		contextLogger.Error(verifyErr, "Backup verification failed", "backupID", backupID)
+	} else {
+		contextLogger.Info("Backup verification succeeded", "backupID", backupID)
+	}
+
+	if err := registerBackupVerificationResult(ctx, cli, cluster, backupID, verifyErr); err != nil {
+		return fmt.Errorf("while registering the backup verification result: %w", err)
+	}
+
+	return verifyErr
+}
+
+// verifyLatestBackup does the actual verification, returning
+// the ID of the verified backup
+func (info InitInfo) verifyLatestBackup(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) (string, error) {
+	if !cluster.Spec.Backup.IsBarmanBackupConfigured() {
+		return "", fmt.Errorf("no object store configured for cluster %v", cluster.Name)
+	}
+
+	objectStore := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.Name
+	if objectStore.ServerName != "" {
+		serverName = objectStore.ServerName
+	}
+
+	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
+		ctx,
+		cli,
+		cluster.Namespace,
+		objectStore,
+		os.Environ())
+	if err != nil {
+		return "", err
+	}
+
+	backupCatalog, err := barman.GetBackupList(ctx, objectStore, serverName, env)
+	if err != nil {
+		return "", err
+	}
+
+	targetBackup := backupCatalog.LatestBackupInfo()
+	if targetBackup == nil {
+		return "", fmt.Errorf("no backup found in the object store")
+	}
+	backup := newBackupFromCatalog(serverName, objectStore, targetBackup)
+
+	if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
+		return targetBackup.ID, err
+	}
+
+	if err := info.restoreDataDir(backup, env); err != nil {
+		return targetBackup.ID, err
+	}
+
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return targetBackup.ID, err
+	}
+
+	if err := info.WriteInitialPostgresqlConf(cluster); err != nil {
+		return targetBackup.ID, err
+	}
+
+	if _, err := info.GetInstance().migratePostgresAutoConfFile(ctx); err != nil {
+		return targetBackup.ID, err
+	}
+
+	if err := info.WriteRestoreHbaConf(); err != nil {
+		return targetBackup.ID, err
+	}
+
+	// We replay every available WAL file, as the goal is to check the
+	// whole recovery chain and not only the base backup
+	if err := info.writeRestoreWalConfig(backup, cluster, nil); err != nil {
+		return targetBackup.ID, err
+	}
+
+	instance := info.GetInstance()
+	instance.Env = env
+
+	if err := instance.VerifyPgDataCoherence(ctx); err != nil {
+		return targetBackup.ID, err
+	}
+
+	return targetBackup.ID, instance.WithActiveInstance(func() error {
+		db, err := instance.GetSuperUserDB()
+		if err != nil {
+			return err
+		}
+
+		if err := waitUntilRecoveryFinishes(db); err != nil {
+			return fmt.Errorf("while waiting for PostgreSQL to stop recovery mode: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// registerBackupVerificationResult stores the outcome of the verification
+// in the cluster status, together with the corresponding condition
+func registerBackupVerificationResult(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	backupID string,
+	verifyErr error,
+) error {
+	origCluster := cluster.DeepCopy()
+
+	status := &apiv1.BackupVerificationStatus{}
+	if cluster.Status.LastBackupVerification != nil {
+		status = cluster.Status.LastBackupVerification.DeepCopy()
+	}
+	status.BackupID = backupID
+	now := metav1.Now()
+	status.StoppedAt = &now
+	status.Phase = apiv1.BackupVerificationPhaseSucceeded
+	status.Message = ""
+	if verifyErr != nil {
+		status.Phase = apiv1.BackupVerificationPhaseFailed
+		status.Message = verifyErr.Error()
+	}
+	cluster.Status.LastBackupVerification = status
+
+	if err := cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	condition := apiv1.BackupVerificationSucceededCondition
+	if verifyErr != nil {
+		condition = apiv1.BuildBackupVerificationFailedCondition(verifyErr)
+	}
+	return conditions.Patch(ctx, cli, cluster, condition)
+}
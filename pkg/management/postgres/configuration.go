@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -61,7 +62,12 @@ func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	cluster *apiv1.Cluster,
 	preserveUserSettings bool,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, preserveUserSettings)
+	postgresConfiguration, sha256, err := createPostgresqlConfiguration(
+		cluster,
+		preserveUserSettings,
+		instance.PodName,
+		instance.GetWALGenerationRate(),
+		instance.IsWALArchiveThrottled())
 	if err != nil {
 		return false, err
 	}
@@ -80,6 +86,16 @@ func (instance *Instance) RefreshConfigurationFilesFromCluster(
 		instance.ConfigSha256 = sha256
 	}
 
+	if postgresConfigurationChanged && utils.IsUnsafeDurabilityAllowed(&cluster.ObjectMeta) {
+		if disabled := postgres.GetDisabledUnsafeDurabilityParameters(
+			cluster.Spec.PostgresConfiguration.Parameters); len(disabled) > 0 {
+			log.Warning("UNSAFE CONFIGURATION: PostgreSQL durability settings are disabled, "+
+				"a crash may lead to unrecoverable data corruption. Never use this configuration in production",
+				"parameters", disabled,
+				"annotation", utils.UnsafeDurabilityAnnotationName)
+		}
+	}
+
 	return postgresConfigurationChanged, nil
 }
 
@@ -104,6 +120,8 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 
 	return postgres.CreateHBARules(
 		cluster.Spec.PostgresConfiguration.PgHBA,
+		cluster.Spec.PostgresConfiguration.PgHBAPrepend,
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.GetHBARules(),
 		defaultAuthenticationMethod,
 		buildLDAPConfigString(cluster, ldapBindPassword))
 }
@@ -199,21 +217,24 @@ func quoteHbaLiteral(literal string) string {
 }
 
 // generatePostgresqlIdent generates the pg_ident.conf content given
-// a set of additional pg_ident lines that is usually taken from the
-// Cluster configuration
-func (instance *Instance) generatePostgresqlIdent(additionalLines []string) (string, error) {
+// a set of additional pg_ident lines and the mappings of the client
+// certificates, that are usually taken from the Cluster configuration
+func (instance *Instance) generatePostgresqlIdent(additionalLines, certificateMappings []string) (string, error) {
 	return postgres.CreateIdentRules(
 		additionalLines,
+		certificateMappings,
 		getCurrentUserOrDefaultToInsecureMapping(),
 	)
 }
 
 // RefreshPGIdent generates and writes down the pg_ident.conf file given
-// a set of additional pg_ident lines that is usually taken from the
-// Cluster configuration
-func (instance *Instance) RefreshPGIdent(additionalLines []string) (postgresIdentChanged bool, err error) {
+// a set of additional pg_ident lines and the mappings of the client
+// certificates, that are usually taken from the Cluster configuration
+func (instance *Instance) RefreshPGIdent(
+	additionalLines, certificateMappings []string,
+) (postgresIdentChanged bool, err error) {
 	// Generate pg_ident.conf file
-	pgIdentContent, err := instance.generatePostgresqlIdent(additionalLines)
+	pgIdentContent, err := instance.generatePostgresqlIdent(additionalLines, certificateMappings)
 	if err != nil {
 		return false, nil
 	}
@@ -418,21 +439,37 @@ func (instance *Instance) migratePostgresAutoConfFile(ctx context.Context) (bool
 
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
 // used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings bool) (string, string, error) {
+func createPostgresqlConfiguration(
+	cluster *apiv1.Cluster,
+	preserveUserSettings bool,
+	instanceName string,
+	walGenerationRate *WALGenerationRate,
+	walArchiveThrottled bool,
+) (string, string, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
 		return "", "", err
 	}
 
+	userSettings := getInstanceUserSettings(cluster, instanceName, walGenerationRate, walArchiveThrottled)
 	info := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     fromVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     userSettings,
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
 		IsWalArchivingDisabled:           utils.IsWalArchivingDisabled(&cluster.ObjectMeta),
+		IsUnsafeDurabilityAllowed:        utils.IsUnsafeDurabilityAllowed(&cluster.ObjectMeta),
+		LogDestination:                   string(cluster.Spec.PostgresConfiguration.LogDestination),
+		DeclaredExtensions:               cluster.Spec.PostgresConfiguration.GetDeclaredExtensions(),
+		ManagedExtensions:                cluster.Spec.PostgresConfiguration.GetManagedExtensions(),
+	}
+
+	delayedReplicas := cluster.Spec.PostgresConfiguration.DelayedReplicas
+	if delayedReplicas.IsDelayedReplica(instanceName) {
+		info.RecoveryMinApplyDelay = delayedReplicas.GetMinApplyDelay()
 	}
 
 	if preserveUserSettings {
@@ -464,6 +501,137 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster, preserveUserSettings
 	return conf, sha256, nil
 }
 
+// getInstanceUserSettings gets the PostgreSQL parameters of the passed
+// instance, merging the ones requested by the user with the ones
+// computed by the operator
+func getInstanceUserSettings(
+	cluster *apiv1.Cluster,
+	instanceName string,
+	walGenerationRate *WALGenerationRate,
+	walArchiveThrottled bool,
+) map[string]string {
+	version, _ := cluster.GetPostgresqlVersion()
+	parameters := cluster.Spec.PostgresConfiguration.Parameters
+	if defaultParameters := getDefaultUserSettings(cluster, version); len(defaultParameters) > 0 {
+		// The parameters requested by the user take precedence
+		// over the ones of the profile and of the extensions
+		for key, value := range parameters {
+			defaultParameters[key] = value
+		}
+		parameters = defaultParameters
+	}
+
+	overrides := getClusterOverrides(cluster)
+	for key, value := range getInstanceOverrides(cluster, instanceName, version, walGenerationRate,
+		walArchiveThrottled) {
+		overrides[key] = value
+	}
+	if len(overrides) == 0 {
+		return parameters
+	}
+
+	result := make(map[string]string, len(parameters)+len(overrides))
+	for key, value := range parameters {
+		result[key] = value
+	}
+	for key, value := range overrides {
+		result[key] = value
+	}
+	return result
+}
+
+// getDefaultUserSettings gets the parameters set by the autovacuum and
+// checkpoint tuning profiles, and by the default configuration of the
+// declared managed extensions. They can be overridden by the user
+func getDefaultUserSettings(cluster *apiv1.Cluster, version int) map[string]string {
+	defaultParameters := cluster.Spec.PostgresConfiguration.Autovacuum.GetParameters(version)
+	for key, value := range cluster.Spec.PostgresConfiguration.Checkpoint.GetParameters() {
+		defaultParameters[key] = value
+	}
+	declaredExtensions := cluster.Spec.PostgresConfiguration.GetDeclaredExtensions()
+	for _, extension := range cluster.Spec.PostgresConfiguration.GetManagedExtensions() {
+		if !slices.Contains(declaredExtensions, extension.Name) {
+			continue
+		}
+		for key, value := range extension.DefaultParameters {
+			defaultParameters[key] = value
+		}
+	}
+
+	return defaultParameters
+}
+
+// getClusterOverrides gets the parameters set through the dedicated
+// stanzas of the cluster, i.e. the default timeouts, the TLS settings,
+// the slow query logging, pg_stat_statements, and the memory settings
+// computed from the memory limit of the container. They override the
+// ones requested by the user
+func getClusterOverrides(cluster *apiv1.Cluster) map[string]string {
+	overrides := cluster.Spec.PostgresConfiguration.Timeouts.GetParameters()
+	for key, value := range cluster.Spec.PostgresConfiguration.TLS.GetParameters() {
+		overrides[key] = value
+	}
+	for key, value := range cluster.Spec.PostgresConfiguration.SlowQueryLogging.GetParameters() {
+		overrides[key] = value
+	}
+	for key, value := range cluster.Spec.PostgresConfiguration.StatStatements.GetParameters() {
+		overrides[key] = value
+	}
+	memoryLimit := cluster.Spec.Resources.Limits.Memory()
+	for key, value := range cluster.Spec.PostgresConfiguration.Memory.GetParameters(memoryLimit) {
+		overrides[key] = value
+	}
+
+	return overrides
+}
+
+// getInstanceOverrides gets the parameters depending on the state of the
+// passed instance: hot_standby_feedback when the instance has been selected
+// for it, archive_timeout computed from the WAL generation rate when the
+// automatic tuning is enabled, the synchronization of the managed logical
+// replication slots on the standbys, where supported, and read-only
+// transactions when the instance is fenced in read-only mode or while the
+// WAL archive backlog is being throttled
+func getInstanceOverrides(
+	cluster *apiv1.Cluster,
+	instanceName string,
+	version int,
+	walGenerationRate *WALGenerationRate,
+	walArchiveThrottled bool,
+) map[string]string {
+	overrides := make(map[string]string)
+	if slices.Contains(cluster.Spec.PostgresConfiguration.HotStandbyFeedbackInstances, instanceName) {
+		overrides["hot_standby_feedback"] = "on"
+	}
+	if configuration := cluster.Spec.PostgresConfiguration.AdaptiveArchiveTimeout; configuration.IsEnabled() {
+		overrides["archive_timeout"] = fmt.Sprintf("%ds", computeArchiveTimeout(walGenerationRate, configuration))
+	}
+	if len(cluster.Spec.ReplicationSlots.GetLogicalSlots()) > 0 && version >= 170000 {
+		// The slot synchronization worker requires the feedback of the
+		// standbys, to prevent the removal of the rows that are still
+		// needed by the logical replication slots
+		if slices.Contains(getSlotSyncInstances(cluster), instanceName) {
+			overrides["sync_replication_slots"] = "on"
+			overrides["hot_standby_feedback"] = "on"
+		}
+		// The logical subscribers never get ahead of the
+		// standbys synchronizing the replication slots
+		if standbySlots := getSynchronizedStandbySlots(cluster, instanceName); standbySlots != "" {
+			overrides["synchronized_standby_slots"] = standbySlots
+		}
+	}
+	if cluster.GetInstanceFencingMode(instanceName) == utils.FencingModeReadOnly {
+		overrides["default_transaction_read_only"] = "on"
+	}
+	if walArchiveThrottled && cluster.Spec.Backup.GetWalArchiveBacklog().ShouldThrottle() {
+		// The write transactions which bypass this setting
+		// are terminated by the instance reconciler
+		overrides["default_transaction_read_only"] = "on"
+	}
+
+	return overrides
+}
+
 // configurePostgresForImport configures Postgres to be optimized for the firt import
 // process, by writing dedicated options the override.conf file just for this phase
 func configurePostgresForImport(ctx context.Context, pgData string) (changed bool, err error) {
@@ -492,3 +660,38 @@ func configurePostgresForImport(ctx context.Context, pgData string) (changed boo
 
 	return changed, nil
 }
+
+// getSlotSyncInstances gets the instances synchronizing the managed logical
+// replication slots, which need hot_standby_feedback. When some instances
+// are selected for hot_standby_feedback, only them are used, and every
+// instance otherwise
+func getSlotSyncInstances(cluster *apiv1.Cluster) []string {
+	if instances := cluster.Spec.PostgresConfiguration.HotStandbyFeedbackInstances; len(instances) > 0 {
+		return instances
+	}
+
+	return cluster.Status.InstanceNames
+}
+
+// getSynchronizedStandbySlots gets the value of synchronized_standby_slots
+// for the passed instance, listing the high availability replication slots
+// of the other instances synchronizing the managed logical replication
+// slots. This way, when the instance is the primary, the logical changes are
+// sent to the subscribers only after being received by those standbys, which
+// can then be promoted without losing them. It is empty when the high
+// availability replication slots are disabled. The instances which don't
+// exist are ignored, as the primary would wait for them forever
+func getSynchronizedStandbySlots(cluster *apiv1.Cluster, instanceName string) string {
+	slotNames := make([]string, 0, len(cluster.Status.InstanceNames))
+	for _, name := range getSlotSyncInstances(cluster) {
+		if name == instanceName || !slices.Contains(cluster.Status.InstanceNames, name) {
+			continue
+		}
+		if slotName := cluster.GetSlotNameFromInstanceName(name); slotName != "" {
+			slotNames = append(slotNames, slotName)
+		}
+	}
+	slices.Sort(slotNames)
+
+	return strings.Join(slotNames, ",")
+}
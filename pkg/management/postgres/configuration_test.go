@@ -21,9 +21,12 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -165,20 +168,538 @@ var _ = Describe("Test building of the list of temporary tablespaces", func() {
 	}
 
 	It("doesn't set temp_tablespaces if there are no declared tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithoutTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithoutTablespaces, true, "", nil, false)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("temp_tablespaces"))
 	})
 
 	It("doesn't set temp_tablespaces if there are no temporary tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithoutTemporaryTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithoutTemporaryTablespaces, true, "", nil, false)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).ToNot(ContainSubstring("temp_tablespaces"))
 	})
 
 	It("sets temp_tablespaces when there are temporary tablespaces", func() {
-		config, _, err := createPostgresqlConfiguration(&clusterWithTemporaryTablespaces, true)
+		config, _, err := createPostgresqlConfiguration(&clusterWithTemporaryTablespaces, true, "", nil, false)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(config).To(ContainSubstring("temp_tablespaces = 'other_temporary_tablespace,temporary_tablespace'"))
 	})
 })
+
+var _ = Describe("hot_standby_feedback on selected instances", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Parameters: map[string]string{
+					"work_mem": "8MB",
+				},
+				HotStandbyFeedbackInstances: []string{"configurationTest-3"},
+			},
+		},
+	}
+
+	It("enables hot_standby_feedback on the selected instances", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-3", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("hot_standby_feedback = 'on'"))
+		Expect(config).To(ContainSubstring("work_mem = '8MB'"))
+	})
+
+	It("doesn't enable hot_standby_feedback on the other instances", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).ToNot(ContainSubstring("hot_standby_feedback"))
+		Expect(config).To(ContainSubstring("work_mem = '8MB'"))
+	})
+
+	It("doesn't change the parameters of the cluster", func() {
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-3", nil, false)).To(HaveKey("hot_standby_feedback"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).ToNot(HaveKey("hot_standby_feedback"))
+	})
+})
+
+var _ = Describe("delayed replicas", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				DelayedReplicas: &apiv1.DelayedReplicasConfiguration{
+					Instances:     []string{"configurationTest-3"},
+					MinApplyDelay: "1h",
+				},
+			},
+		},
+	}
+
+	It("delays the application of the changes on the delayed replicas", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, "configurationTest-3", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("recovery_min_apply_delay = '3600000ms'"))
+	})
+
+	It("doesn't delay the application of the changes on the other instances", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, "configurationTest-2", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).ToNot(ContainSubstring("recovery_min_apply_delay"))
+	})
+})
+
+var _ = Describe("extensions declared with a recipe", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Extensions: []apiv1.ManagedExtensionConfiguration{
+					{
+						Name: "pg_cron",
+						Recipe: &apiv1.ManagedExtensionRecipe{
+							SharedPreloadLibraries: []string{"pg_cron"},
+							Parameters: map[string]string{
+								"cron.database_name": "postgres",
+								"cron.log_run":       "off",
+							},
+						},
+					},
+				},
+				Parameters: map[string]string{
+					"cron.database_name": "app",
+				},
+			},
+		},
+	}
+
+	It("loads the libraries of the extension and applies its default configuration", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, false, "configurationTest-1", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("shared_preload_libraries = 'pg_cron'"))
+		Expect(config).To(ContainSubstring("cron.log_run = 'off'"))
+		Expect(config).To(ContainSubstring("cron.database_name = 'app'"))
+	})
+})
+
+var _ = Describe("synchronization of the managed logical replication slots", func() {
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:17.0",
+				ReplicationSlots: &apiv1.ReplicationSlotsConfiguration{
+					HighAvailability: &apiv1.ReplicationSlotsHAConfiguration{
+						Enabled: ptr.To(true),
+					},
+					Logical: []apiv1.LogicalReplicationSlotConfiguration{
+						{Name: "cdc", Database: "app"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				InstanceNames: []string{"configurationTest-1", "configurationTest-2", "configurationTest-3"},
+			},
+		}
+	})
+
+	It("enables the slot synchronization from PostgreSQL 17", func() {
+		settings := getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("sync_replication_slots", "on"))
+		Expect(settings).To(HaveKeyWithValue("hot_standby_feedback", "on"))
+		Expect(settings).To(HaveKeyWithValue("synchronized_standby_slots",
+			"_cnpg_configurationtest_2,_cnpg_configurationtest_3"))
+	})
+
+	It("synchronizes the slots only on the instances selected for hot_standby_feedback", func() {
+		cluster.Spec.PostgresConfiguration.HotStandbyFeedbackInstances = []string{
+			"configurationTest-2", "configurationTest-4",
+		}
+
+		settings := getInstanceUserSettings(&cluster, "configurationTest-3", nil, false)
+		Expect(settings).ToNot(HaveKey("sync_replication_slots"))
+		Expect(settings).ToNot(HaveKey("hot_standby_feedback"))
+		Expect(settings).To(HaveKeyWithValue("synchronized_standby_slots", "_cnpg_configurationtest_2"))
+
+		settings = getInstanceUserSettings(&cluster, "configurationTest-2", nil, false)
+		Expect(settings).To(HaveKeyWithValue("sync_replication_slots", "on"))
+		Expect(settings).To(HaveKeyWithValue("hot_standby_feedback", "on"))
+		Expect(settings).ToNot(HaveKey("synchronized_standby_slots"))
+	})
+
+	It("doesn't wait for the standbys without the high availability replication slots", func() {
+		cluster.Spec.ReplicationSlots.HighAvailability = nil
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)).
+			ToNot(HaveKey("synchronized_standby_slots"))
+	})
+
+	It("doesn't enable the slot synchronization on the previous versions", func() {
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.4"
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)).ToNot(HaveKey("sync_replication_slots"))
+	})
+
+	It("doesn't enable the slot synchronization without managed slots", func() {
+		cluster.Spec.ReplicationSlots.Logical = nil
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)).ToNot(HaveKey("sync_replication_slots"))
+	})
+})
+
+var _ = Describe("adaptive archive_timeout", func() {
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					AdaptiveArchiveTimeout: &apiv1.AdaptiveArchiveTimeoutConfiguration{Enabled: true},
+				},
+			},
+		}
+	})
+
+	const segmentSize = 16 * 1024 * 1024
+
+	It("computes archive_timeout from the WAL generation rate", func() {
+		rate := &WALGenerationRate{BytesPerSecond: segmentSize / 240, SegmentSize: segmentSize}
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", rate, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("archive_timeout = '120s'"))
+	})
+
+	It("uses the highest archive_timeout until the rate is measured", func() {
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("archive_timeout = '1800s'"))
+	})
+
+	It("ignores the WAL generation rate when the automatic tuning is disabled", func() {
+		cluster.Spec.PostgresConfiguration.AdaptiveArchiveTimeout.Enabled = false
+		rate := &WALGenerationRate{BytesPerSecond: segmentSize / 240, SegmentSize: segmentSize}
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", rate, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("archive_timeout = '5min'"))
+	})
+
+	Context("computeArchiveTimeout", func() {
+		configuration := &apiv1.AdaptiveArchiveTimeoutConfiguration{
+			Enabled:    true,
+			MinTimeout: 60,
+			MaxTimeout: 1800,
+		}
+
+		It("uses the highest value on idle clusters", func() {
+			Expect(computeArchiveTimeout(nil, configuration)).To(BeEquivalentTo(1800))
+			Expect(computeArchiveTimeout(
+				&WALGenerationRate{SegmentSize: segmentSize}, configuration)).To(BeEquivalentTo(1800))
+			Expect(computeArchiveTimeout(
+				&WALGenerationRate{BytesPerSecond: 10, SegmentSize: segmentSize}, configuration)).To(BeEquivalentTo(1800))
+		})
+
+		It("uses the lowest value on busy clusters", func() {
+			Expect(computeArchiveTimeout(
+				&WALGenerationRate{BytesPerSecond: segmentSize, SegmentSize: segmentSize},
+				configuration)).To(BeEquivalentTo(60))
+		})
+
+		It("uses half the time needed to fill a WAL segment", func() {
+			Expect(computeArchiveTimeout(
+				&WALGenerationRate{BytesPerSecond: segmentSize / 600, SegmentSize: segmentSize},
+				configuration)).To(BeEquivalentTo(300))
+		})
+
+		It("uses the default range", func() {
+			defaultConfiguration := &apiv1.AdaptiveArchiveTimeoutConfiguration{Enabled: true}
+			Expect(computeArchiveTimeout(nil, defaultConfiguration)).
+				To(BeEquivalentTo(apiv1.DefaultAdaptiveArchiveTimeoutMax))
+			Expect(computeArchiveTimeout(
+				&WALGenerationRate{BytesPerSecond: segmentSize, SegmentSize: segmentSize},
+				defaultConfiguration)).To(BeEquivalentTo(apiv1.DefaultAdaptiveArchiveTimeoutMin))
+		})
+	})
+})
+
+var _ = Describe("WAL archive backlog throttling", func() {
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					WalArchiveBacklog: &apiv1.WalArchiveBacklogConfiguration{
+						MaxReadyWALFiles: 100,
+						Action:           apiv1.WalArchiveBacklogActionThrottle,
+					},
+				},
+			},
+		}
+	})
+
+	It("makes the transactions read-only while throttling", func() {
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, true)).
+			To(HaveKeyWithValue("default_transaction_read_only", "on"))
+	})
+
+	It("doesn't make the transactions read-only when not throttling", func() {
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)).
+			ToNot(HaveKey("default_transaction_read_only"))
+	})
+
+	It("doesn't make the transactions read-only when the backlog is only reported", func() {
+		cluster.Spec.Backup.WalArchiveBacklog.Action = apiv1.WalArchiveBacklogActionAlert
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, true)).
+			ToNot(HaveKey("default_transaction_read_only"))
+	})
+})
+
+var _ = Describe("read-only fencing", func() {
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation:  `["configurationTest-2"]`,
+					utils.FencingModeAnnotationName: string(utils.FencingModeReadOnly),
+				},
+			},
+		}
+	})
+
+	It("makes the transactions read-only on the fenced instances", func() {
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-2", nil, false)).
+			To(HaveKeyWithValue("default_transaction_read_only", "on"))
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)).
+			ToNot(HaveKey("default_transaction_read_only"))
+	})
+
+	It("doesn't change the transactions when the fenced instances are stopped", func() {
+		delete(cluster.Annotations, utils.FencingModeAnnotationName)
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-2", nil, false)).
+			ToNot(HaveKey("default_transaction_read_only"))
+	})
+})
+
+var _ = Describe("default timeouts", func() {
+	It("writes the default timeouts in the configuration", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Timeouts: &apiv1.TimeoutsConfiguration{
+						StatementTimeout:                "30s",
+						IdleInTransactionSessionTimeout: "5m",
+					},
+				},
+			},
+		}
+
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("statement_timeout = '30000ms'"))
+		Expect(config).To(ContainSubstring("idle_in_transaction_session_timeout = '300000ms'"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(BeEmpty())
+	})
+})
+
+var _ = Describe("slow query logging", func() {
+	It("writes the slow query logging settings in the configuration", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					SlowQueryLogging: &apiv1.SlowQueryLoggingConfiguration{
+						MinDuration: "2s",
+						LockWaits:   ptr.To(true),
+					},
+				},
+			},
+		}
+
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("log_min_duration_statement = '2000'"))
+		Expect(config).To(ContainSubstring("log_lock_waits = 'on'"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pg_stat_statements settings", func() {
+	It("loads the extension with the requested maximum number of statements", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					StatStatements: &apiv1.StatStatementsConfiguration{
+						Max: 5000,
+					},
+				},
+			},
+		}
+
+		config, _, err := createPostgresqlConfiguration(&cluster, true, "configurationTest-1", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("pg_stat_statements.max = '5000'"))
+		Expect(config).To(ContainSubstring("pg_stat_statements.track = 'top'"))
+		Expect(config).To(MatchRegexp("shared_preload_libraries = '.*pg_stat_statements.*'"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(BeEmpty())
+	})
+})
+
+var _ = Describe("autovacuum tuning profiles", func() {
+	var cluster apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.3",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"autovacuum_naptime": "30s"},
+					Autovacuum: &apiv1.AutovacuumConfiguration{
+						Profile: apiv1.AutovacuumProfileAggressive,
+					},
+				},
+			},
+		}
+	})
+
+	It("adds the parameters of the profile to the user ones", func() {
+		settings := getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("autovacuum_vacuum_scale_factor", "0.05"))
+		Expect(settings).To(HaveKeyWithValue("autovacuum_vacuum_insert_scale_factor", "0.05"))
+		Expect(settings).To(HaveKeyWithValue("autovacuum_naptime", "30s"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+	})
+
+	It("doesn't add any parameter with the default profile", func() {
+		cluster.Spec.PostgresConfiguration.Autovacuum.Profile = apiv1.AutovacuumProfileDefault
+		Expect(getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)).To(Equal(
+			map[string]string{"autovacuum_naptime": "30s"}))
+	})
+})
+
+var _ = Describe("checkpoint tuning profiles", func() {
+	It("adds the parameters of the profile to the user ones", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.3",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"max_wal_size": "8GB"},
+					Checkpoint: &apiv1.CheckpointConfiguration{
+						Profile: apiv1.CheckpointProfileWriteIntensive,
+					},
+				},
+			},
+		}
+
+		settings := getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("min_wal_size", "1GB"))
+		Expect(settings).To(HaveKeyWithValue("checkpoint_timeout", "15min"))
+		Expect(settings).To(HaveKeyWithValue("max_wal_size", "8GB"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("memory settings", func() {
+	It("computes the memory settings from the memory limit of the container", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.3",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+					Memory: &apiv1.MemoryConfiguration{
+						SharedBuffersPercentage:      25,
+						EffectiveCacheSizePercentage: 75,
+					},
+				},
+			},
+		}
+
+		settings := getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("work_mem", "8MB"))
+		Expect(settings).To(HaveKeyWithValue("shared_buffers", "524288kB"))
+		Expect(settings).To(HaveKeyWithValue("effective_cache_size", "1572864kB"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+
+		cluster.Spec.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("4Gi")
+		settings = getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("shared_buffers", "1048576kB"))
+	})
+})
+
+var _ = Describe("declared managed extensions", func() {
+	It("adds the default parameters of the extensions to the user ones", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:16.3",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"pg_stat_statements.track": "all"},
+					Extensions: []apiv1.ManagedExtensionConfiguration{
+						{Name: "pg_stat_statements"},
+						{Name: "pgaudit"},
+					},
+				},
+			},
+		}
+
+		settings := getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("pg_stat_statements.track", "all"))
+		Expect(settings).To(HaveKeyWithValue("pg_stat_statements.max", "10000"))
+		Expect(settings).To(HaveKeyWithValue("pgaudit.log_catalog", "off"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("TLS settings", func() {
+	It("adds the TLS settings to the user ones", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+					TLS: &apiv1.PostgresTLSConfiguration{
+						MinProtocolVersion: "TLSv1.2",
+						Ciphers:            []string{"HIGH", "!aNULL"},
+					},
+				},
+			},
+		}
+
+		settings := getInstanceUserSettings(&cluster, "configurationTest-1", nil, false)
+		Expect(settings).To(HaveKeyWithValue("work_mem", "8MB"))
+		Expect(settings).To(HaveKeyWithValue("ssl_min_protocol_version", "TLSv1.2"))
+		Expect(settings).To(HaveKeyWithValue("ssl_ciphers", "HIGH:!aNULL"))
+	})
+})
@@ -23,8 +23,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
-// buildPrimaryConnInfo builds the connection string to connect to primaryHostname
-func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
+// buildPrimaryConnInfo builds the connection string to connect to primaryHostname,
+// requiring the passed minimum TLS protocol version, if any
+func buildPrimaryConnInfo(primaryHostname, applicationName, sslMinProtocolVersion string) string {
 	// We should have been using configfile.CreateConnectionString
 	// but doing that we would cause an unnecessary restart of
 	// existing PostgreSQL 12 clusters.
@@ -36,5 +37,10 @@ func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
 		fmt.Sprintf("sslrootcert=%v ", postgres.ServerCACertificateLocation) +
 		fmt.Sprintf("application_name=%v ", applicationName) +
 		"sslmode=verify-ca"
+	if sslMinProtocolVersion != "" {
+		// Only added when requested, to not change the connection string
+		// of the existing clusters
+		primaryConnInfo += fmt.Sprintf(" ssl_min_protocol_version=%v", sslMinProtocolVersion)
+	}
 	return primaryConnInfo
 }
@@ -0,0 +1,34 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("buildPrimaryConnInfo", func() {
+	It("doesn't require a TLS protocol version by default", func() {
+		Expect(buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2", "")).
+			ToNot(ContainSubstring("ssl_min_protocol_version"))
+	})
+
+	It("requires the passed minimum TLS protocol version", func() {
+		Expect(buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2", "TLSv1.3")).
+			To(HaveSuffix("sslmode=verify-ca ssl_min_protocol_version=TLSv1.3"))
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -95,11 +96,30 @@ type InitInfo struct {
 	// of SQL files to be executed just after having configured a new instance
 	PostInitApplicationSQLRefsFolder string
 
+	// PostInitSQLRefsFolder is the folder which contains a bunch of SQL files
+	// to be executed inside the postgres database just after having
+	// configured a new instance
+	PostInitSQLRefsFolder string
+
+	// PostInitTemplateSQLRefsFolder is the folder which contains a bunch of
+	// SQL files to be executed inside the template1 database just after
+	// having configured a new instance
+	PostInitTemplateSQLRefsFolder string
+
 	// BackupLabelFile holds the content returned by pg_stop_backup. Needed for a hot backup restore
 	BackupLabelFile []byte
 
 	// TablespaceMapFile holds the content returned by pg_stop_backup. Needed for a hot backup restore
 	TablespaceMapFile []byte
+
+	// SSLMinProtocolVersion is the minimum TLS protocol version required
+	// when connecting to the primary, if any
+	SSLMinProtocolVersion string
+
+	// Timezone is the default time zone of the new instance, written
+	// by initdb in the configuration. When empty, the one of the
+	// environment is used
+	Timezone string
 }
 
 // VerifyPGData verifies if the passed configuration is OK, otherwise it returns an error
@@ -148,6 +168,10 @@ func (info InitInfo) CreateDataDirectory() error {
 	_ = compatibility.Umask(0o077)
 
 	initdbCmd := exec.Command(constants.InitdbName, options...) // #nosec
+	if info.Timezone != "" {
+		// initdb gets the default time zone from the environment
+		initdbCmd.Env = append(os.Environ(), "TZ="+info.Timezone)
+	}
 	err := execlog.RunBuffering(initdbCmd, constants.InitdbName)
 	if err != nil {
 		return fmt.Errorf("error while creating the PostgreSQL instance: %w", err)
@@ -224,6 +248,10 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return err
 	}
 
+	if err = info.executeSQLRefs(dbSuperUser, info.PostInitSQLRefsFolder); err != nil {
+		return fmt.Errorf("could not execute post init SQL refs: %w", err)
+	}
+
 	dbTemplate, err := instance.GetTemplateDB()
 	if err != nil {
 		return fmt.Errorf("while getting template database: %w", err)
@@ -234,6 +262,10 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return fmt.Errorf("could not execute init Template queries: %w", err)
 	}
 
+	if err = info.executeSQLRefs(dbTemplate, info.PostInitTemplateSQLRefsFolder); err != nil {
+		return fmt.Errorf("could not execute post init template SQL refs: %w", err)
+	}
+
 	if info.ApplicationDatabase == "" {
 		return nil
 	}
@@ -264,7 +296,7 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return fmt.Errorf("could not execute init Application queries: %w", err)
 	}
 
-	if err = info.executePostInitApplicationSQLRefs(appDB); err != nil {
+	if err = info.executeSQLRefs(appDB, info.PostInitApplicationSQLRefsFolder); err != nil {
 		return fmt.Errorf("could not execute post init application SQL refs: %w", err)
 	}
 
@@ -278,19 +310,21 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 	return nil
 }
 
-func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
-	if info.PostInitApplicationSQLRefsFolder == "" {
+// executeSQLRefs executes, in alphabetical order, the SQL files contained
+// in the passed directory. Nothing is done when the directory is empty.
+func (info InitInfo) executeSQLRefs(sqlUser *sql.DB, directory string) error {
+	if directory == "" {
 		return nil
 	}
 
-	if err := fileutils.EnsureDirectoryExists(info.PostInitApplicationSQLRefsFolder); err != nil {
-		return fmt.Errorf("could not find directory: %s, err: %w", info.PostInitApplicationSQLRefsFolder, err)
+	if err := fileutils.EnsureDirectoryExists(directory); err != nil {
+		return fmt.Errorf("could not find directory: %s, err: %w", directory, err)
 	}
 
-	files, err := fileutils.GetDirectoryContent(info.PostInitApplicationSQLRefsFolder)
+	files, err := fileutils.GetDirectoryContent(directory)
 	if err != nil {
 		return fmt.Errorf("could not get directory content from: %s, err: %w",
-			info.PostInitApplicationSQLRefsFolder, err)
+			directory, err)
 	}
 
 	// Sorting ensures that we execute the files in the correct order.
@@ -298,14 +332,15 @@ func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
 	sort.Strings(files)
 
 	for _, file := range files {
-		sql, ioErr := fileutils.ReadFile(path.Join(info.PostInitApplicationSQLRefsFolder, file))
+		sql, ioErr := fileutils.ReadFile(path.Join(directory, file))
 		if ioErr != nil {
-			return fmt.Errorf("could not read file: %s, err; %w", file, err)
+			return fmt.Errorf("could not read file: %s, err; %w", file, ioErr)
 		}
 
 		if err = info.executeQueries(sqlUser, []string{string(sql)}); err != nil {
-			return fmt.Errorf("could not execute queries: %w", err)
+			return fmt.Errorf("could not execute queries in file %s: %w", path.Join(directory, file), err)
 		}
+		log.Info("Executed SQL file", "file", path.Join(directory, file))
 	}
 
 	return nil
@@ -365,6 +400,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	}
 
 	// Prepare the managed configuration file (override.conf)
+	info.SSLMinProtocolVersion = cluster.GetClientSSLMinProtocolVersion()
 	primaryConnInfo := info.GetPrimaryConnInfo()
 	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 
@@ -430,15 +466,21 @@ func executeLogicalImport(
 	}
 	defer originPool.ShutdownConnections()
 
+	progress := logicalimport.NewProgressReporter(client, cluster)
+	progress.Start(ctx)
+
 	cloneType := cluster.Spec.Bootstrap.InitDB.Import.Type
 	switch cloneType {
 	case apiv1.MicroserviceSnapshotType:
-		return logicalimport.Microservice(ctx, cluster, destinationPool, originPool)
+		err = logicalimport.Microservice(ctx, cluster, destinationPool, originPool, progress)
 	case apiv1.MonolithSnapshotType:
-		return logicalimport.Monolith(ctx, cluster, destinationPool, originPool)
+		err = logicalimport.Monolith(ctx, cluster, destinationPool, originPool, progress)
 	default:
-		return fmt.Errorf("unrecognized clone type %s", cloneType)
+		err = fmt.Errorf("unrecognized clone type %s", cloneType)
 	}
+
+	progress.Stop(ctx, err)
+	return err
 }
 
 func getConnectionPoolerForExternalCluster(
@@ -448,6 +490,11 @@ func getConnectionPoolerForExternalCluster(
 	namespaceOfNewCluster string,
 ) (*pool.ConnectionPool, error) {
 	externalCluster, ok := cluster.ExternalCluster(cluster.Spec.Bootstrap.InitDB.Import.Source.ExternalCluster)
+	if cluster.ShouldImportFromBackup() {
+		// The base backup of the external cluster has been recovered
+		// by the operator into a temporary cluster
+		externalCluster, ok = cluster.GetImportSourceExternalCluster(), true
+	}
 	if !ok {
 		return nil, fmt.Errorf("missing external cluster")
 	}
@@ -0,0 +1,70 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"os"
+	"path"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("executeSQLRefs", func() {
+	var (
+		info      InitInfo
+		directory string
+	)
+
+	BeforeEach(func() {
+		directory = GinkgoT().TempDir()
+		Expect(os.WriteFile(path.Join(directory, "1.sql"), []byte("SELECT 2"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(path.Join(directory, "0.sql"), []byte("SELECT 1"), 0o600)).To(Succeed())
+	})
+
+	It("does nothing without a directory", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(info.executeSQLRefs(db, "")).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("executes the SQL files in order", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectExec("SELECT 1").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("SELECT 2").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		Expect(info.executeSQLRefs(db, directory)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("stops at the first failing SQL file, reporting it", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		mock.ExpectExec("SELECT 1").WillReturnError(errors.New("syntax error"))
+
+		err = info.executeSQLRefs(db, directory)
+		Expect(err).To(MatchError(ContainSubstring(path.Join(directory, "0.sql"))))
+		Expect(err).To(MatchError(ContainSubstring("syntax error")))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
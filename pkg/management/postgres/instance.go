@@ -29,6 +29,7 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
@@ -193,6 +194,23 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// readOnlyFenced specifies whether the instance is fenced in read-only
+	// mode, keeping PostgreSQL running. It never entails mightBeUnavailable
+	readOnlyFenced atomic.Bool
+
+	// walGenerationRate is the WAL generation rate measured by the
+	// instance reconciler, used to tune archive_timeout. It is nil
+	// until it is measured
+	walGenerationRate atomic.Pointer[WALGenerationRate]
+
+	// walArchiveThrottled is true when the write transactions are being
+	// delayed because too many WAL files are waiting to be archived
+	walArchiveThrottled atomic.Bool
+
+	// diskUsage is the usage of the volumes of the instance, as
+	// sampled by the disk usage monitor
+	diskUsage atomic.Pointer[[]postgres.VolumeUsage]
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
 
@@ -207,6 +225,10 @@ type Instance struct {
 
 	// ServerCertificate is the certificate we use to serve https connections
 	ServerCertificate *tls.Certificate
+
+	// The minimum TLS protocol version required when connecting
+	// to the primary, if any
+	SSLMinProtocolVersion string
 }
 
 // SetAlterSystemEnabled allows or deny the usage of the
@@ -232,6 +254,69 @@ func (instance *Instance) IsFenced() bool {
 	return instance.fenced.Load()
 }
 
+// IsReadOnlyFenced checks whether the instance is fenced in read-only
+// mode, with PostgreSQL still running
+func (instance *Instance) IsReadOnlyFenced() bool {
+	return instance.readOnlyFenced.Load()
+}
+
+// SetReadOnlyFencing marks whether the instance is fenced in read-only mode
+func (instance *Instance) SetReadOnlyFencing(enabled bool) {
+	instance.readOnlyFenced.Store(enabled)
+}
+
+// GetFencingMode gets how the instance is fenced, which
+// is empty when the instance is not fenced
+func (instance *Instance) GetFencingMode() utils.FencingMode {
+	switch {
+	case instance.IsFenced():
+		return utils.FencingModeStop
+	case instance.IsReadOnlyFenced():
+		return utils.FencingModeReadOnly
+	default:
+		return ""
+	}
+}
+
+// GetWALGenerationRate gets the measured WAL generation rate,
+// or nil if it has not been measured yet
+func (instance *Instance) GetWALGenerationRate() *WALGenerationRate {
+	return instance.walGenerationRate.Load()
+}
+
+// SetWALGenerationRate sets the measured WAL generation rate, used
+// to tune archive_timeout. Passing nil discards the measure
+func (instance *Instance) SetWALGenerationRate(rate *WALGenerationRate) {
+	instance.walGenerationRate.Store(rate)
+}
+
+// IsWALArchiveThrottled checks whether the write transactions are being
+// delayed because too many WAL files are waiting to be archived
+func (instance *Instance) IsWALArchiveThrottled() bool {
+	return instance.walArchiveThrottled.Load()
+}
+
+// SetWALArchiveThrottled sets whether the write transactions need to be
+// delayed because too many WAL files are waiting to be archived
+func (instance *Instance) SetWALArchiveThrottled(throttled bool) {
+	instance.walArchiveThrottled.Store(throttled)
+}
+
+// GetDiskUsage gets the usage of the volumes of the instance, which
+// is empty until the disk usage monitor samples them
+func (instance *Instance) GetDiskUsage() []postgres.VolumeUsage {
+	usage := instance.diskUsage.Load()
+	if usage == nil {
+		return nil
+	}
+	return *usage
+}
+
+// SetDiskUsage sets the usage of the volumes of the instance
+func (instance *Instance) SetDiskUsage(usage []postgres.VolumeUsage) {
+	instance.diskUsage.Store(&usage)
+}
+
 // CanCheckReadiness checks whether the instance should be checked for readiness
 func (instance *Instance) CanCheckReadiness() bool {
 	return instance.canCheckReadiness.Load()
@@ -264,20 +349,13 @@ func (instance *Instance) CheckHasDiskSpaceForWAL(ctx context.Context) (bool, er
 	}
 
 	pgControlData := utils.ParsePgControldataOutput(pgControlDataString)
-	walSegmentSizeString, ok := pgControlData["Bytes per WAL segment"]
-	if !ok {
-		return false, fmt.Errorf("no 'Bytes per WAL segment' section into pg_controldata output")
-	}
-
-	walSegmentSize, err := strconv.Atoi(walSegmentSizeString)
+	walSegmentSize, err := utils.GetWALSegmentSizeFromPgControldata(pgControlData)
 	if err != nil {
-		return false, fmt.Errorf(
-			"wrong 'Bytes per WAL segment' pg_controldata value (not an integer): '%s' %w",
-			walSegmentSizeString, err)
+		return false, err
 	}
 
 	walDirectory := path.Join(instance.PgData, pgWalDirectory)
-	return fileutils.NewDiskProbe(walDirectory).HasStorageAvailable(ctx, walSegmentSize)
+	return fileutils.NewDiskProbe(walDirectory).HasStorageAvailable(ctx, int(walSegmentSize))
 }
 
 // SetMightBeUnavailable marks whether the instance being down should be tolerated
@@ -333,7 +411,7 @@ func (instance *Instance) VerifyPgDataCoherence(ctx context.Context) error {
 	}
 
 	// creates a bare pg_ident.conf that only grants local access
-	_, err := instance.RefreshPGIdent(nil)
+	_, err := instance.RefreshPGIdent(nil, nil)
 	return err
 }
 
@@ -663,19 +741,27 @@ func (instance *Instance) Run() (*execlog.StreamingCmd, error) {
 // WithActiveInstance execute the internal function while this
 // PostgreSQL instance is running
 func (instance *Instance) WithActiveInstance(inner func() error) error {
-	// Start the CSV logpipe to redirect log to stdout
+	// Start the CSV and JSON logpipes to redirect log to stdout,
+	// depending on the log destination chosen by the user
 	ctx, ctxCancel := context.WithCancel(context.Background())
 	csvPipe := logpipe.NewLogPipe()
+	jsonPipe := logpipe.NewJSONLineLogPipe(filepath.Join(postgres.LogPath, postgres.LogFileName+".json"))
 
 	go func() {
 		if err := csvPipe.Start(ctx); err != nil {
 			log.Info("csv pipeline encountered an error", "err", err)
 		}
 	}()
+	go func() {
+		if err := jsonPipe.Start(ctx); err != nil {
+			log.Info("json pipeline encountered an error", "err", err)
+		}
+	}()
 
 	defer func() {
 		ctxCancel()
 		csvPipe.GetExitedCondition().Wait()
+		jsonPipe.GetExitedCondition().Wait()
 	}()
 
 	err := instance.Startup()
@@ -1019,11 +1105,25 @@ func (instance *Instance) Rewind(ctx context.Context, postgresMajorVersion int)
 		"pgdata", instance.PgData,
 		"options", options)
 
+	// The restore_command records the WAL files it can't find, telling
+	// us if pg_rewind failed because of them
+	if err := os.Remove(pgRewindMissingWALFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	pgRewindCmd := exec.Command(pgRewindName, options...) // #nosec
-	pgRewindCmd.Env = instance.Env
+	pgRewindCmd.Env = getPgRewindEnv(instance.Env, pgRewindMissingWALFile)
 	err = execlog.RunStreaming(pgRewindCmd, pgRewindName)
 	if err != nil {
 		contextLogger.Error(err, "Failed to execute pg_rewind", "options", options)
+		missingWALs, missingErr := getPgRewindMissingWALs(pgRewindMissingWALFile)
+		if missingErr != nil {
+			contextLogger.Warning("Cannot read the WAL files not found by pg_rewind", "err", missingErr.Error())
+		}
+		if len(missingWALs) > 0 {
+			return fmt.Errorf("error executing pg_rewind: %w (%s): %w",
+				ErrPgRewindWALUnavailable, strings.Join(missingWALs, ", "), err)
+		}
 		return fmt.Errorf("error executing pg_rewind: %w", err)
 	}
 
@@ -1237,7 +1337,7 @@ func (instance *Instance) DropConnections() error {
 
 // GetPrimaryConnInfo returns the DSN to reach the primary
 func (instance *Instance) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
+	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName, instance.SSLMinProtocolVersion)
 }
 
 // HandleInstanceCommandRequests execute a command requested by the reconciliation
@@ -70,7 +70,9 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 
 // Join creates a new instance joined to an existing PostgreSQL cluster
 func (info InitInfo) Join(cluster *apiv1.Cluster) error {
-	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
+	info.SSLMinProtocolVersion = cluster.GetClientSSLMinProtocolVersion()
+	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName, info.SSLMinProtocolVersion) +
+		" dbname=postgres connect_timeout=5"
 
 	pgVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
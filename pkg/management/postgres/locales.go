@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// initDBLocaleOptions are the initdb options accepting a locale name
+var initDBLocaleOptions = []string{"--locale", "--lc-collate", "--lc-ctype"}
+
+// VerifyLocalesAndTimezone checks that the locales and the time zone
+// requested for the new instance are available in the image, so that
+// the bootstrap fails before running initdb if they are not
+func (info InitInfo) VerifyLocalesAndTimezone() error {
+	if info.Timezone != "" {
+		if _, err := time.LoadLocation(info.Timezone); err != nil {
+			return fmt.Errorf("time zone %q is not available in the image: %w", info.Timezone, err)
+		}
+	}
+
+	locales := getInitDBLocales(info.InitDBOptions)
+	if len(locales) == 0 {
+		return nil
+	}
+
+	output, err := exec.Command("locale", "-a").Output() // #nosec G204
+	if err != nil {
+		log.Warning("Cannot list the locales available in the image, skipping their verification",
+			"error", err.Error())
+		return nil
+	}
+	availableLocales := strings.Fields(string(output))
+
+	for _, locale := range locales {
+		if !isLocaleAvailable(locale, availableLocales) {
+			return fmt.Errorf("locale %q is not available in the image", locale)
+		}
+	}
+
+	return nil
+}
+
+// getInitDBLocales gets the locale names passed to initdb, supporting
+// both the `--option=value` and the `--option value` forms
+func getInitDBLocales(options []string) []string {
+	var result []string
+	for idx, option := range options {
+		name, value, found := strings.Cut(option, "=")
+		if !slices.Contains(initDBLocaleOptions, name) {
+			continue
+		}
+
+		if !found {
+			if idx+1 >= len(options) {
+				continue
+			}
+			value = options[idx+1]
+		}
+
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// isLocaleAvailable checks if a locale is in the list reported by
+// `locale -a`, which uses the normalized form of the codeset
+func isLocaleAvailable(locale string, availableLocales []string) bool {
+	if locale == "C" || locale == "POSIX" {
+		return true
+	}
+
+	normalizedLocale := normalizeLocaleName(locale)
+	for _, availableLocale := range availableLocales {
+		if normalizeLocaleName(availableLocale) == normalizedLocale {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeLocaleName normalizes the codeset of a locale name as the C
+// library does, so that `en_US.UTF-8` and `en_US.utf8` are the same locale
+func normalizeLocaleName(locale string) string {
+	name, modifier, hasModifier := strings.Cut(locale, "@")
+	language, codeset, hasCodeset := strings.Cut(name, ".")
+
+	result := language
+	if hasCodeset {
+		var normalizedCodeset strings.Builder
+		onlyDigits := true
+		for _, r := range codeset {
+			switch {
+			case unicode.IsLetter(r):
+				onlyDigits = false
+				normalizedCodeset.WriteRune(unicode.ToLower(r))
+			case unicode.IsDigit(r):
+				normalizedCodeset.WriteRune(r)
+			}
+		}
+
+		result += "."
+		if onlyDigits {
+			result += "iso"
+		}
+		result += normalizedCodeset.String()
+	}
+
+	if hasModifier {
+		result += "@" + modifier
+	}
+
+	return result
+}
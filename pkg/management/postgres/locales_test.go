@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("initdb locales", func() {
+	It("gets the locales passed to initdb", func() {
+		Expect(getInitDBLocales([]string{
+			"-k",
+			"--encoding=UTF8",
+			"--locale=en_US.UTF-8",
+			"--lc-collate", "de_DE.UTF-8",
+			"--lc-ctype=en_US.UTF-8",
+		})).To(Equal([]string{"en_US.UTF-8", "de_DE.UTF-8"}))
+		Expect(getInitDBLocales([]string{"--lc-ctype"})).To(BeEmpty())
+	})
+
+	It("normalizes the codeset of the locale names", func() {
+		Expect(normalizeLocaleName("en_US.UTF-8")).To(Equal("en_US.utf8"))
+		Expect(normalizeLocaleName("de_DE.ISO-8859-1@euro")).To(Equal("de_DE.iso88591@euro"))
+		Expect(normalizeLocaleName("ru_RU.1251")).To(Equal("ru_RU.iso1251"))
+		Expect(normalizeLocaleName("it_IT")).To(Equal("it_IT"))
+	})
+
+	It("checks if a locale is available", func() {
+		availableLocales := []string{"C.utf8", "en_US.utf8", "POSIX"}
+		Expect(isLocaleAvailable("C", availableLocales)).To(BeTrue())
+		Expect(isLocaleAvailable("en_US.UTF-8", availableLocales)).To(BeTrue())
+		Expect(isLocaleAvailable("C.UTF-8", availableLocales)).To(BeTrue())
+		Expect(isLocaleAvailable("it_IT.UTF-8", availableLocales)).To(BeFalse())
+	})
+
+	It("rejects a time zone which is not available", func() {
+		Expect(InitInfo{Timezone: "Not/AZone"}.VerifyLocalesAndTimezone()).ToNot(Succeed())
+		Expect(InitInfo{Timezone: "UTC"}.VerifyLocalesAndTimezone()).To(Succeed())
+	})
+})
@@ -32,6 +32,13 @@ import (
 
 type databaseSnapshotter struct {
 	cluster *apiv1.Cluster
+
+	// The reporter of the progress of the import in the cluster status
+	progress *ProgressReporter
+
+	// When set, the content of the databases is streamed from this
+	// origin directly into pg_restore, without writing a dump file
+	streamingOrigin pool.Pooler
 }
 
 func (ds *databaseSnapshotter) getDatabaseList(ctx context.Context, target pool.Pooler) ([]string, error) {
@@ -98,6 +105,7 @@ func (ds *databaseSnapshotter) exportDatabases(
 
 	for _, database := range databases {
 		contextLogger.Info("exporting database", "databaseName", database)
+		ds.progress.setStep(ctx, fmt.Sprintf("exporting database %s", database))
 		dsn := target.GetDsn(database)
 		options := []string{
 			"-Fc",
@@ -134,6 +142,7 @@ func (ds *databaseSnapshotter) importDatabases(
 				"databaseName", database,
 				"section", section,
 			)
+			ds.progress.setStep(ctx, fmt.Sprintf("importing section %s of database %s", section, database))
 
 			exists, err := ds.databaseExists(target, database)
 			if err != nil {
@@ -153,10 +162,11 @@ func (ds *databaseSnapshotter) importDatabases(
 				"-U", "postgres",
 				"-d", targetDatabase,
 				"--section", section,
-				generateFileNameForDatabase(database),
 			}
 
 			options = append(options, alwaysPresentOptions...)
+			options = append(options, ds.getRestoreJobsOptions()...)
+			options = append(options, generateFileNameForDatabase(database))
 
 			contextLogger.Info("Running pg_restore",
 				"cmd", pgRestore,
@@ -168,6 +178,8 @@ func (ds *databaseSnapshotter) importDatabases(
 				return fmt.Errorf("error while executing pg_restore, section:%s, %w", section, err)
 			}
 		}
+
+		ds.progress.databaseImported(ctx, database)
 	}
 
 	return nil
@@ -203,6 +215,7 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 			"databaseName", database,
 			"section", section,
 		)
+		ds.progress.setStep(ctx, fmt.Sprintf("importing section %s of database %s", section, database))
 
 		options := []string{
 			"-U", "postgres",
@@ -211,9 +224,17 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 			fmt.Sprintf("--role=%s", owner),
 			"-d", targetDatabase,
 			"--section", section,
-			generateFileNameForDatabase(database),
 		}
 
+		if ds.streamingOrigin != nil {
+			if err := ds.streamSection(ctx, database, section, options); err != nil {
+				return err
+			}
+			continue
+		}
+
+		options = append(options, ds.getRestoreJobsOptions()...)
+		options = append(options, generateFileNameForDatabase(database))
 		contextLogger.Info("Running pg_restore",
 			"cmd", pgRestore,
 			"options", options)
@@ -266,6 +287,7 @@ func (ds *databaseSnapshotter) executePostImportQueries(
 
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("executing post import user defined queries")
+	ds.progress.setStep(ctx, "executing the post-import queries")
 
 	db, err := target.Connection(database)
 	if err != nil {
@@ -291,6 +313,7 @@ func (ds *databaseSnapshotter) analyze(
 
 	for _, database := range databases {
 		contextLogger.Info(fmt.Sprintf("running analyze for database: %s", database))
+		ds.progress.setStep(ctx, fmt.Sprintf("analyzing database %s", database))
 		db, err := target.Connection(database)
 		if err != nil {
 			return err
@@ -364,3 +387,14 @@ func (ds *databaseSnapshotter) getSectionsToExecute() []string {
 
 	return []string{preData, data, postData}
 }
+
+// getRestoreJobsOptions gets the options needed to run pg_restore
+// with the requested number of concurrent jobs
+func (ds *databaseSnapshotter) getRestoreJobsOptions() []string {
+	jobs := ds.cluster.Spec.Bootstrap.InitDB.Import.RestoreJobs
+	if jobs <= 1 {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("--jobs=%d", jobs)}
+}
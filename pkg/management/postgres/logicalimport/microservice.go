@@ -30,18 +30,28 @@ func Microservice(
 	cluster *apiv1.Cluster,
 	destination pool.Pooler,
 	origin pool.Pooler,
+	progress *ProgressReporter,
 ) error {
 	contextLogger := log.FromContext(ctx)
-	ds := databaseSnapshotter{cluster: cluster}
+	ds := databaseSnapshotter{cluster: cluster, progress: progress}
 	databases := cluster.Spec.Bootstrap.InitDB.Import.Databases
 	contextLogger.Info("starting microservice clone process")
+	progress.setDatabases(ctx, databases)
 
-	if err := createDumpsDirectory(); err != nil {
-		return nil
-	}
+	// The temporary cluster where the base backup has been recovered can't
+	// be changed by the users, so we can stream every section of the dump
+	// separately without needing the disk space to store it
+	streaming := cluster.ShouldImportFromBackup()
+	if streaming {
+		ds.streamingOrigin = origin
+	} else {
+		if err := createDumpsDirectory(); err != nil {
+			return nil
+		}
 
-	if err := ds.exportDatabases(ctx, origin, databases); err != nil {
-		return err
+		if err := ds.exportDatabases(ctx, origin, databases); err != nil {
+			return err
+		}
 	}
 
 	if err := ds.dropExtensionsFromDatabase(ctx, destination, cluster.Spec.Bootstrap.InitDB.Database); err != nil {
@@ -58,10 +68,14 @@ func Microservice(
 		return err
 	}
 
-	if err := cleanDumpDirectory(); err != nil {
-		return err
+	if !streaming {
+		if err := cleanDumpDirectory(); err != nil {
+			return err
+		}
 	}
 
+	progress.databaseImported(ctx, databases[0])
+
 	if err := ds.executePostImportQueries(ctx, destination, cluster.Spec.Bootstrap.InitDB.Database); err != nil {
 		return err
 	}
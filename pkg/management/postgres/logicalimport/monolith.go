@@ -30,10 +30,12 @@ func Monolith(
 	cluster *apiv1.Cluster,
 	destination pool.Pooler,
 	origin pool.Pooler,
+	progress *ProgressReporter,
 ) error {
 	contextLogger := log.FromContext(ctx)
 	contextLogger.Info("starting monolith clone process")
 
+	progress.setStep(ctx, "importing roles")
 	if err := cloneRoles(ctx, cluster, destination, origin); err != nil {
 		return err
 	}
@@ -42,11 +44,12 @@ func Monolith(
 		return err
 	}
 
-	ds := databaseSnapshotter{cluster: cluster}
+	ds := databaseSnapshotter{cluster: cluster, progress: progress}
 	databases, err := ds.getDatabaseList(ctx, origin)
 	if err != nil {
 		return err
 	}
+	progress.setDatabases(ctx, databases)
 
 	if err := createDumpsDirectory(); err != nil {
 		return err
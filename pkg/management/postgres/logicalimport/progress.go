@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ProgressReporter reports the progress of the import in the status of
+// the cluster. The import is not stopped when the status can't be
+// updated, as the progress is only informative. A nil reporter
+// doesn't report anything.
+type ProgressReporter struct {
+	cli     client.Client
+	cluster *apiv1.Cluster
+}
+
+// NewProgressReporter creates a new ProgressReporter for the passed cluster
+func NewProgressReporter(cli client.Client, cluster *apiv1.Cluster) *ProgressReporter {
+	return &ProgressReporter{
+		cli:     cli,
+		cluster: cluster,
+	}
+}
+
+// Start reports that the import has been started, discarding the
+// progress of any previous attempt
+func (p *ProgressReporter) Start(ctx context.Context) {
+	p.update(ctx, func(status *apiv1.LogicalImportStatus) {
+		*status = apiv1.LogicalImportStatus{
+			Phase:     apiv1.LogicalImportPhaseRunning,
+			StartedAt: ptr.To(metav1.Now()),
+		}
+	})
+}
+
+// Stop reports that the import has been terminated, with the passed error
+func (p *ProgressReporter) Stop(ctx context.Context, importErr error) {
+	p.update(ctx, func(status *apiv1.LogicalImportStatus) {
+		status.Phase = apiv1.LogicalImportPhaseCompleted
+		if importErr != nil {
+			status.Phase = apiv1.LogicalImportPhaseFailed
+			status.Message = importErr.Error()
+		} else {
+			status.Step = ""
+			status.StepStartedAt = nil
+		}
+		status.StoppedAt = ptr.To(metav1.Now())
+	})
+}
+
+// setDatabases reports the databases to be imported
+func (p *ProgressReporter) setDatabases(ctx context.Context, databases []string) {
+	p.update(ctx, func(status *apiv1.LogicalImportStatus) {
+		status.Databases = slices.Clone(databases)
+	})
+}
+
+// setStep reports the step being executed
+func (p *ProgressReporter) setStep(ctx context.Context, step string) {
+	p.update(ctx, func(status *apiv1.LogicalImportStatus) {
+		status.Step = step
+		status.StepStartedAt = ptr.To(metav1.Now())
+	})
+}
+
+// databaseImported reports that a database has been imported
+func (p *ProgressReporter) databaseImported(ctx context.Context, database string) {
+	p.update(ctx, func(status *apiv1.LogicalImportStatus) {
+		if !slices.Contains(status.ImportedDatabases, database) {
+			status.ImportedDatabases = append(status.ImportedDatabases, database)
+		}
+	})
+}
+
+func (p *ProgressReporter) update(ctx context.Context, cb func(status *apiv1.LogicalImportStatus)) {
+	if p == nil {
+		return
+	}
+
+	origCluster := p.cluster.DeepCopy()
+	if p.cluster.Status.LogicalImport == nil {
+		p.cluster.Status.LogicalImport = &apiv1.LogicalImportStatus{}
+	}
+	cb(p.cluster.Status.LogicalImport)
+
+	if err := p.cli.Status().Patch(ctx, p.cluster, client.MergeFrom(origCluster)); err != nil {
+		log.FromContext(ctx).Warning("Cannot report the progress of the import in the cluster status",
+			"error", err.Error())
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("import progress reporting", func() {
+	var (
+		cli      client.Client
+		cluster  *apiv1.Cluster
+		progress *ProgressReporter
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-namespace",
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		progress = NewProgressReporter(cli, cluster)
+	})
+
+	getStatus := func(ctx SpecContext) *apiv1.LogicalImportStatus {
+		var updatedCluster apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		return updatedCluster.Status.LogicalImport
+	}
+
+	It("reports the progress of a completed import", func(ctx SpecContext) {
+		progress.Start(ctx)
+		Expect(getStatus(ctx).Phase).To(Equal(apiv1.LogicalImportPhaseRunning))
+		Expect(getStatus(ctx).StartedAt).ToNot(BeNil())
+
+		progress.setDatabases(ctx, []string{"accounting", "banking"})
+		progress.setStep(ctx, "importing section data of database accounting")
+		status := getStatus(ctx)
+		Expect(status.Databases).To(Equal([]string{"accounting", "banking"}))
+		Expect(status.Step).To(Equal("importing section data of database accounting"))
+		Expect(status.StepStartedAt).ToNot(BeNil())
+
+		progress.databaseImported(ctx, "accounting")
+		progress.databaseImported(ctx, "accounting")
+		Expect(getStatus(ctx).ImportedDatabases).To(Equal([]string{"accounting"}))
+
+		progress.Stop(ctx, nil)
+		status = getStatus(ctx)
+		Expect(status.Phase).To(Equal(apiv1.LogicalImportPhaseCompleted))
+		Expect(status.Step).To(BeEmpty())
+		Expect(status.StoppedAt).ToNot(BeNil())
+	})
+
+	It("reports the step where the import failed", func(ctx SpecContext) {
+		progress.Start(ctx)
+		progress.setStep(ctx, "exporting database accounting")
+		progress.Stop(ctx, errors.New("error in pg_dump"))
+
+		status := getStatus(ctx)
+		Expect(status.Phase).To(Equal(apiv1.LogicalImportPhaseFailed))
+		Expect(status.Step).To(Equal("exporting database accounting"))
+		Expect(status.Message).To(Equal("error in pg_dump"))
+	})
+
+	It("discards the progress of the previous attempts", func(ctx SpecContext) {
+		progress.Start(ctx)
+		progress.databaseImported(ctx, "accounting")
+		progress.Stop(ctx, errors.New("error in pg_dump"))
+
+		progress.Start(ctx)
+		status := getStatus(ctx)
+		Expect(status.Phase).To(Equal(apiv1.LogicalImportPhaseRunning))
+		Expect(status.ImportedDatabases).To(BeEmpty())
+		Expect(status.Message).To(BeEmpty())
+	})
+
+	It("doesn't report anything without a reporter", func(ctx SpecContext) {
+		var nilProgress *ProgressReporter
+		nilProgress.setStep(ctx, "exporting database accounting")
+	})
+})
+
+var _ = Describe("concurrent restore jobs", func() {
+	It("passes the number of jobs to pg_restore only when needed", func() {
+		ds := databaseSnapshotter{
+			cluster: &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					Bootstrap: &apiv1.BootstrapConfiguration{
+						InitDB: &apiv1.BootstrapInitDB{
+							Import: &apiv1.Import{},
+						},
+					},
+				},
+			},
+		}
+		Expect(ds.getRestoreJobsOptions()).To(BeEmpty())
+
+		ds.cluster.Spec.Bootstrap.InitDB.Import.RestoreJobs = 1
+		Expect(ds.getRestoreJobsOptions()).To(BeEmpty())
+
+		ds.cluster.Spec.Bootstrap.InitDB.Import.RestoreJobs = 4
+		Expect(ds.getRestoreJobsOptions()).To(Equal([]string{"--jobs=4"}))
+	})
+})
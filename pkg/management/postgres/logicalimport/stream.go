@@ -0,0 +1,115 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// streamingProgressInterval is how often the progress of the
+// streaming of a database is logged
+const streamingProgressInterval = 30 * time.Second
+
+// progressReader counts the bytes read from the wrapped reader
+type progressReader struct {
+	reader io.Reader
+	bytes  atomic.Int64
+}
+
+// Read implements the io.Reader interface
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.bytes.Add(int64(n))
+	return n, err
+}
+
+// streamSection pipes the output of pg_dump, for the passed section of
+// the database, into pg_restore, which is invoked with the passed options
+func (ds *databaseSnapshotter) streamSection(
+	ctx context.Context,
+	database string,
+	section string,
+	restoreOptions []string,
+) error {
+	contextLogger := log.FromContext(ctx).WithValues("databaseName", database, "section", section)
+
+	dumpOptions := []string{
+		"-Fc",
+		"-d", ds.streamingOrigin.GetDsn(database),
+		"--section", section,
+	}
+
+	contextLogger.Info("Streaming database section",
+		"dumpOptions", dumpOptions,
+		"restoreOptions", restoreOptions)
+
+	pgDumpCommand := exec.Command(pgDump, dumpOptions...) // #nosec
+	pgDumpCommand.Stderr = &execlog.LogWriter{
+		Logger: log.WithName(pgDump).WithValues(execlog.PipeKey, execlog.StdErr),
+	}
+	dumpOutput, err := pgDumpCommand.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	progress := &progressReader{reader: dumpOutput}
+	pgRestoreCommand := exec.Command(pgRestore, restoreOptions...) // #nosec
+	pgRestoreCommand.Stdin = progress
+
+	if err := pgDumpCommand.Start(); err != nil {
+		return fmt.Errorf("error while starting pg_dump, section:%s, %w", section, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(streamingProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				contextLogger.Info("Streaming database section in progress",
+					"streamedBytes", progress.bytes.Load())
+			}
+		}
+	}()
+
+	restoreErr := execlog.RunStreaming(pgRestoreCommand, pgRestore)
+	close(done)
+	if restoreErr != nil {
+		// pg_dump would be blocked forever on a pipe nobody is reading
+		_ = pgDumpCommand.Process.Kill()
+		_ = pgDumpCommand.Wait()
+		return fmt.Errorf("error while executing pg_restore, section:%s, %w", section, restoreErr)
+	}
+
+	if err := pgDumpCommand.Wait(); err != nil {
+		return fmt.Errorf("error in pg_dump, section:%s, %w", section, err)
+	}
+
+	contextLogger.Info("Streamed database section", "streamedBytes", progress.bytes.Load())
+	return nil
+}
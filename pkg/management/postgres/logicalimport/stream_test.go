@@ -0,0 +1,36 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("progressReader", func() {
+	It("counts the bytes that have been read", func() {
+		reader := &progressReader{reader: strings.NewReader("dump content")}
+
+		content, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("dump content"))
+		Expect(reader.bytes.Load()).To(BeEquivalentTo(len("dump content")))
+	})
+})
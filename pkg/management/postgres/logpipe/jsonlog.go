@@ -0,0 +1,107 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// parseJSONLogRecord parses a line of the log written by PostgreSQL when
+// log_destination is jsonlog, returning nil if the line is not one of them,
+// as it happens for the lines written by the instance manager subcommands
+// in the same file.
+//
+// See https://www.postgresql.org/docs/current/runtime-config-logging.html
+// section "Using JSON-Format Log Output".
+func parseJSONLogRecord(line []byte) map[string]string {
+	if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("{")) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var content map[string]interface{}
+	if err := decoder.Decode(&content); err != nil {
+		return nil
+	}
+
+	// Every record written by PostgreSQL has these fields,
+	// which are not used by the instance manager
+	if _, ok := content["timestamp"]; !ok {
+		return nil
+	}
+	if _, ok := content["error_severity"]; !ok {
+		return nil
+	}
+
+	result := make(map[string]string, len(content))
+	for key, value := range content {
+		switch v := value.(type) {
+		case string:
+			result[key] = v
+		case json.Number:
+			result[key] = v.String()
+		case nil:
+		default:
+			result[key] = fmt.Sprint(v)
+		}
+	}
+
+	return result
+}
+
+// FromJSONLog stores inside the record structure the fields of a record
+// of the log written by PostgreSQL when log_destination is jsonlog, using
+// the same representation of the CSV format
+func (r *LoggingRecord) FromJSONLog(content map[string]string) NamedRecord {
+	r.LogTime = content["timestamp"]
+	r.Username = content["user"]
+	r.DatabaseName = content["dbname"]
+	r.ProcessID = content["pid"]
+	r.ConnectionFrom = content["remote_host"]
+	if port := content["remote_port"]; port != "" {
+		r.ConnectionFrom = fmt.Sprintf("%s:%s", r.ConnectionFrom, port)
+	}
+	r.SessionID = content["session_id"]
+	r.SessionLineNum = content["line_num"]
+	r.CommandTag = content["ps"]
+	r.SessionStartTime = content["session_start"]
+	r.VirtualTransactionID = content["vxid"]
+	r.TransactionID = content["txid"]
+	r.ErrorSeverity = content["error_severity"]
+	r.SQLStateCode = content["state_code"]
+	r.Message = content["message"]
+	r.Detail = content["detail"]
+	r.Hint = content["hint"]
+	r.InternalQuery = content["internal_query"]
+	r.InternalQueryPos = content["internal_position"]
+	r.Context = content["context"]
+	r.Query = content["statement"]
+	r.QueryPos = content["cursor_position"]
+	r.Location = ""
+	if funcName := content["func_name"]; funcName != "" {
+		r.Location = fmt.Sprintf("%s, %s:%s", funcName, content["file_name"], content["file_line_num"])
+	}
+	r.ApplicationName = content["application_name"]
+	r.BackendType = content["backend_type"]
+	r.LeaderPid = content["leader_pid"]
+	r.QueryID = content["query_id"]
+	return r
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logpipe
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL JSON log record", func() {
+	It("recognizes the records written by PostgreSQL", func() {
+		content := parseJSONLogRecord([]byte(`{"timestamp":"2024-01-01 10:00:00.000 UTC",` +
+			`"pid":42,"error_severity":"LOG","message":"hello"}`))
+		Expect(content).To(Equal(map[string]string{
+			"timestamp":      "2024-01-01 10:00:00.000 UTC",
+			"pid":            "42",
+			"error_severity": "LOG",
+			"message":        "hello",
+		}))
+	})
+
+	It("ignores lines that are not written by PostgreSQL", func() {
+		Expect(parseJSONLogRecord([]byte(`{"level":"info","ts":1,"msg":"hello"}`))).To(BeNil())
+		Expect(parseJSONLogRecord([]byte(`not a JSON line`))).To(BeNil())
+		Expect(parseJSONLogRecord([]byte(`{"timestamp":`))).To(BeNil())
+	})
+
+	It("fills the fields as in the CSV format", func() {
+		var r LoggingRecord
+		r.FromJSONLog(map[string]string{
+			"timestamp":         "0",
+			"user":              "1",
+			"dbname":            "2",
+			"pid":               "3",
+			"remote_host":       "10.0.0.1",
+			"remote_port":       "5432",
+			"session_id":        "5",
+			"line_num":          "6",
+			"ps":                "7",
+			"session_start":     "8",
+			"vxid":              "9",
+			"txid":              "10",
+			"error_severity":    "11",
+			"state_code":        "12",
+			"message":           "13",
+			"detail":            "14",
+			"hint":              "15",
+			"internal_query":    "16",
+			"internal_position": "17",
+			"context":           "18",
+			"statement":         "19",
+			"cursor_position":   "20",
+			"func_name":         "exec_simple_query",
+			"file_name":         "postgres.c",
+			"file_line_num":     "1200",
+			"application_name":  "22",
+			"backend_type":      "23",
+			"leader_pid":        "24",
+			"query_id":          "25",
+		})
+		Expect(r).To(Equal(LoggingRecord{
+			LogTime:              "0",
+			Username:             "1",
+			DatabaseName:         "2",
+			ProcessID:            "3",
+			ConnectionFrom:       "10.0.0.1:5432",
+			SessionID:            "5",
+			SessionLineNum:       "6",
+			CommandTag:           "7",
+			SessionStartTime:     "8",
+			VirtualTransactionID: "9",
+			TransactionID:        "10",
+			ErrorSeverity:        "11",
+			SQLStateCode:         "12",
+			Message:              "13",
+			Detail:               "14",
+			Hint:                 "15",
+			InternalQuery:        "16",
+			InternalQueryPos:     "17",
+			Context:              "18",
+			Query:                "19",
+			QueryPos:             "20",
+			Location:             "exec_simple_query, postgres.c:1200",
+			ApplicationName:      "22",
+			BackendType:          "23",
+			LeaderPid:            "24",
+			QueryID:              "25",
+		}))
+	})
+
+	It("extracts the pgaudit records", func() {
+		r := NewPgAuditLoggingDecorator()
+		result := r.FromJSONLog(map[string]string{
+			"timestamp":      "2024-01-01 10:00:00.000 UTC",
+			"error_severity": "LOG",
+			"message":        "AUDIT: SESSION,1,1,READ,SELECT,,,SELECT 1,<not logged>",
+		})
+		Expect(result.GetName()).To(Equal(PgAuditRecordName))
+		Expect(r.Audit.StatementID).To(Equal("1"))
+		Expect(r.Audit.Command).To(Equal("SELECT"))
+		Expect(r.Message).To(BeEmpty())
+	})
+})
@@ -57,11 +57,20 @@ func (p *LineLogPipe) GetExitedCondition() *concurrency.Executed {
 	return p.exited
 }
 
-// NewJSONLineLogPipe returns a logPipe for json format
+// NewJSONLineLogPipe returns a logPipe for json format. The records written
+// by PostgreSQL when log_destination is jsonlog are written in the same way
+// as the ones in CSV format, while the other lines are written as they are
 func NewJSONLineLogPipe(fileName string) *LineLogPipe {
+	record := NewPgAuditLoggingDecorator()
+	writer := &LogRecordWriter{}
+
 	return &LineLogPipe{
 		fileName: fileName,
 		handler: func(line []byte) {
+			if content := parseJSONLogRecord(line); content != nil {
+				writer.Write(record.FromJSONLog(content))
+				return
+			}
 			fmt.Println(string(line))
 		},
 		initialized: concurrency.NewExecuted(),
@@ -58,7 +58,20 @@ func getTagAndContent(record *LoggingRecord) (string, string) {
 // FromCSV implements the CSVRecordParser interface, parsing a LoggingRecord and then
 func (r *PgAuditLoggingDecorator) FromCSV(content []string) NamedRecord {
 	r.LoggingRecord.FromCSV(content)
+	return r.decorate()
+}
+
+// FromJSONLog parses a LoggingRecord from a record of the log written
+// by PostgreSQL when log_destination is jsonlog, and then extracts
+// the pgaudit record if present
+func (r *PgAuditLoggingDecorator) FromJSONLog(content map[string]string) NamedRecord {
+	r.LoggingRecord.FromJSONLog(content)
+	return r.decorate()
+}
 
+// decorate extracts the pgaudit record from the message of
+// the parsed LoggingRecord, if present
+func (r *PgAuditLoggingDecorator) decorate() NamedRecord {
 	tag, record := getTagAndContent(r.LoggingRecord)
 	if tag != "AUDIT" || record == "" {
 		return r.LoggingRecord
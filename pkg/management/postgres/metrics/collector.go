@@ -23,7 +23,10 @@ import (
 	"database/sql"
 	"fmt"
 	"path"
+	"reflect"
 	"regexp"
+	"sync"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/prometheus/client_golang/prometheus"
@@ -47,6 +50,61 @@ type QueriesCollector struct {
 
 	errorUserQueries      *prometheus.CounterVec
 	errorUserQueriesGauge prometheus.Gauge
+
+	// cache keeps the metrics of the queries with `cache_seconds`
+	cache *queriesCache
+}
+
+// queriesCacheKey identifies the metrics collected by a user
+// query from a database
+type queriesCacheKey struct {
+	query    string
+	database string
+}
+
+// cachedMetrics are the metrics collected by a user query from a
+// database, together with the time they must be collected again
+type cachedMetrics struct {
+	metrics   []prometheus.Metric
+	expiresAt time.Time
+}
+
+// queriesCache keeps the metrics collected by the user queries having
+// `cache_seconds` set, so that they are run only once in that interval
+type queriesCache struct {
+	lock    sync.Mutex
+	entries map[queriesCacheKey]cachedMetrics
+}
+
+// replay sends the cached metrics to the channel, returning false
+// if they are missing or expired
+func (c *queriesCache) replay(key queriesCacheKey, now time.Time, ch chan<- prometheus.Metric) bool {
+	c.lock.Lock()
+	entry, found := c.entries[key]
+	c.lock.Unlock()
+
+	if !found || !now.Before(entry.expiresAt) {
+		return false
+	}
+
+	for _, metric := range entry.metrics {
+		ch <- metric
+	}
+	return true
+}
+
+// store keeps the metrics collected by a query for `cacheSeconds`
+func (c *queriesCache) store(key queriesCacheKey, metrics []prometheus.Metric, now time.Time, cacheSeconds uint64) {
+	if cacheSeconds == 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries[key] = cachedMetrics{
+		metrics:   metrics,
+		expiresAt: now.Add(time.Duration(cacheSeconds) * time.Second),
+	}
 }
 
 // Name returns the name of this collector, as supplied by the user in the configMap
@@ -120,19 +178,31 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 
 		allTargetDatabases := q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache)
 		for targetDatabase := range allTargetDatabases {
+			cacheKey := queriesCacheKey{query: name, database: targetDatabase}
+			if q.cache.replay(cacheKey, time.Now(), ch) {
+				queryLogger.Debug("Using cached metrics", "targetDatabase", targetDatabase)
+				continue
+			}
+
 			conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
 			if err != nil {
 				q.reportUserQueryErrorMetric(name + ": " + err.Error())
 				continue
 			}
 
-			err = collector.collect(conn, ch)
+			metrics, err := collector.collectIsolated(conn)
+			for _, metric := range metrics {
+				ch <- metric
+			}
 			if err != nil {
 				queryLogger.Error(err, "Error collecting user query",
 					"targetDatabase", targetDatabase)
 				// Increment metrics counters.
 				q.reportUserQueryErrorMetric(name + " on db " + targetDatabase + ": " + err.Error())
+				continue
 			}
+
+			q.cache.store(cacheKey, metrics, time.Now(), userQuery.CacheSeconds)
 		}
 	}
 	return nil
@@ -258,6 +328,7 @@ func NewQueriesCollector(
 		variableLabels: make(map[string]VariableSet),
 		userQueries:    make(UserQueries),
 		defaultDBName:  defaultDBName,
+		cache:          &queriesCache{entries: make(map[queriesCacheKey]cachedMetrics)},
 		errorUserQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: name,
 			Name:      "errors_total",
@@ -300,6 +371,25 @@ func (q *QueriesCollector) ParseQueries(customQueries []byte) error {
 	return nil
 }
 
+// InheritCache reuses the metrics cached by a previous collector for
+// the queries which didn't change, as the collector is created again
+// every time the custom queries are reconciled
+func (q *QueriesCollector) InheritCache(previous *QueriesCollector) {
+	if q == nil || previous == nil {
+		return
+	}
+
+	previous.cache.lock.Lock()
+	defer previous.cache.lock.Unlock()
+
+	for key, entry := range previous.cache.entries {
+		query, found := q.userQueries[key.query]
+		if found && reflect.DeepEqual(query, previous.userQueries[key.query]) {
+			q.cache.entries[key] = entry
+		}
+	}
+}
+
 // InjectUserQueries injects the passed queries
 func (q *QueriesCollector) InjectUserQueries(defaultQueries UserQueries) {
 	if q == nil {
@@ -322,6 +412,33 @@ type QueryCollector struct {
 	variableLabels VariableSet
 }
 
+// collectIsolated retrieves metrics from query, returning them. A panic
+// while processing the results is reported as an error, so that a faulty
+// query cannot stop the collection of the other ones
+func (c QueryCollector) collectIsolated(conn *sql.DB) (metrics []prometheus.Metric, err error) {
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+
+	var collected []prometheus.Metric
+	go func() {
+		defer close(done)
+		for metric := range ch {
+			collected = append(collected, metric)
+		}
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while collecting the metrics: %v", r)
+		}
+		close(ch)
+		<-done
+		metrics = collected
+	}()
+
+	return nil, c.collect(conn, ch)
+}
+
 // collect retrieves metrics from query and exposes them to prometheus
 func (c QueryCollector) collect(conn *sql.DB, ch chan<- prometheus.Metric) error {
 	tx, err := createMonitoringTx(conn)
@@ -335,6 +452,12 @@ func (c QueryCollector) collect(conn *sql.DB, ch chan<- prometheus.Metric) error
 		}
 	}()
 
+	if timeout := c.userQuery.TimeoutSeconds; timeout > 0 {
+		if _, err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout TO '%ds'", timeout)); err != nil {
+			return err
+		}
+	}
+
 	shouldBeCollected, err := c.userQuery.isCollectable(tx)
 	if err != nil {
 		return err
@@ -17,6 +17,9 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/prometheus/client_golang/prometheus"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -147,3 +150,140 @@ var _ = Describe("QueryCollector tests", func() {
 		})
 	})
 })
+
+var _ = Describe("user queries isolation", func() {
+	userQuery := UserQuery{
+		Query:          "SELECT 42 AS answer",
+		TimeoutSeconds: 5,
+		Metrics: []Mapping{
+			{
+				"answer": ColumnMapping{
+					Usage:       GAUGE,
+					Description: "The answer",
+				},
+			},
+		},
+	}
+
+	expectMonitoringTx := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SET application_name TO cnpg_metrics_exporter").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET standard_conforming_strings TO on").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET ROLE TO pg_monitor").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SET LOCAL statement_timeout TO '5s'").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	It("collects the metrics within the statement timeout of the query", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		expectMonitoringTx(mock)
+		mock.ExpectQuery("SELECT 42 AS answer").
+			WillReturnRows(sqlmock.NewRows([]string{"answer"}).AddRow(42))
+		mock.ExpectCommit()
+
+		columnMapping, variableLabels := userQuery.ToMetricMap("test_answer")
+		collector := QueryCollector{
+			namespace:      "answer",
+			userQuery:      userQuery,
+			columnMapping:  columnMapping,
+			variableLabels: variableLabels,
+		}
+
+		metrics, err := collector.collectIsolated(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics).To(HaveLen(1))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reports a panic while processing the results as an error", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		expectMonitoringTx(mock)
+		mock.ExpectQuery("SELECT 42 AS answer").
+			WillReturnRows(sqlmock.NewRows([]string{"answer"}).AddRow(42))
+		mock.ExpectCommit()
+
+		collector := QueryCollector{
+			namespace: "answer",
+			userQuery: userQuery,
+			columnMapping: MetricMapSet{
+				"answer": MetricMap{
+					Name: "answer",
+					Conversion: func(interface{}) (float64, bool) {
+						panic("faulty conversion")
+					},
+				},
+			},
+		}
+
+		metrics, err := collector.collectIsolated(db)
+		Expect(err).To(MatchError(ContainSubstring("faulty conversion")))
+		Expect(metrics).To(BeEmpty())
+	})
+})
+
+var _ = Describe("user queries cache", func() {
+	now := time.Now()
+	key := queriesCacheKey{query: "answer", database: "app"}
+	metric := prometheus.MustNewConstMetric(
+		prometheus.NewDesc("test_answer", "The answer", nil, nil),
+		prometheus.GaugeValue,
+		42)
+
+	var cache *queriesCache
+	BeforeEach(func() {
+		cache = &queriesCache{entries: make(map[queriesCacheKey]cachedMetrics)}
+	})
+
+	It("doesn't keep the metrics of the queries without cache_seconds", func() {
+		cache.store(key, []prometheus.Metric{metric}, now, 0)
+		Expect(cache.replay(key, now, make(chan prometheus.Metric, 1))).To(BeFalse())
+	})
+
+	It("replays the metrics until they expire", func() {
+		cache.store(key, []prometheus.Metric{metric}, now, 60)
+
+		ch := make(chan prometheus.Metric, 1)
+		Expect(cache.replay(key, now.Add(30*time.Second), ch)).To(BeTrue())
+		Expect(ch).To(Receive(Equal(metric)))
+
+		Expect(cache.replay(key, now.Add(time.Minute), ch)).To(BeFalse())
+		Expect(cache.replay(queriesCacheKey{query: "answer", database: "postgres"}, now, ch)).To(BeFalse())
+	})
+})
+
+var _ = Describe("user queries cache inheritance", func() {
+	metric := prometheus.MustNewConstMetric(
+		prometheus.NewDesc("test_answer", "The answer", nil, nil),
+		prometheus.GaugeValue,
+		42)
+
+	It("keeps the cached metrics of the queries which didn't change", func() {
+		previous := NewQueriesCollector("test", nil, "app")
+		previous.InjectUserQueries(UserQueries{
+			"answer":  UserQuery{Query: "SELECT 42 AS answer", CacheSeconds: 60},
+			"changed": UserQuery{Query: "SELECT 1 AS changed", CacheSeconds: 60},
+		})
+		for _, name := range []string{"answer", "changed"} {
+			previous.cache.store(
+				queriesCacheKey{query: name, database: "app"},
+				[]prometheus.Metric{metric},
+				time.Now(),
+				60)
+		}
+
+		current := NewQueriesCollector("test", nil, "app")
+		current.InjectUserQueries(UserQueries{
+			"answer":  UserQuery{Query: "SELECT 42 AS answer", CacheSeconds: 60},
+			"changed": UserQuery{Query: "SELECT 2 AS changed", CacheSeconds: 60},
+		})
+		current.InheritCache(previous)
+
+		Expect(current.cache.entries).To(HaveKey(queriesCacheKey{query: "answer", database: "app"}))
+		Expect(current.cache.entries).ToNot(HaveKey(queriesCacheKey{query: "changed", database: "app"}))
+	})
+})
@@ -36,6 +36,7 @@ type UserQuery struct {
 	Master          bool      `yaml:"master"` // wokeignore:rule=master
 	Primary         bool      `yaml:"primary"`
 	CacheSeconds    uint64    `yaml:"cache_seconds"`
+	TimeoutSeconds  uint64    `yaml:"timeout_seconds"`
 	RunOnServer     string    `yaml:"runonserver"`
 	TargetDatabases []string  `yaml:"target_databases"`
 	// Name allows overriding the key name in the metric namespace
@@ -110,6 +110,8 @@ func (pool *ConnectionPool) newConnection(dbname string) (*sql.DB, error) {
 	// that need a PostgreSQL connection:
 	//
 	// * Declarative Role Management
+	// * Declarative Database Management
+	// * Declarative Publication Management
 	// * Probes
 	// * Replication slots reconciler
 	// * Online VolumeSnapshot backup connection
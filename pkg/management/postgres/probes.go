@@ -20,16 +20,22 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -63,12 +69,68 @@ func (instance *Instance) IsServerReady() error {
 	return superUserDB.Ping()
 }
 
+// replicationLagQuery gets whether the instance is in recovery and, if so,
+// whether it is streaming from its source and the time elapsed since the
+// last replayed transaction, as long as the instance has still some WAL
+// to replay
+const replicationLagQuery = `SELECT pg_catalog.pg_is_in_recovery(),
+	EXISTS (SELECT 1 FROM pg_catalog.pg_stat_wal_receiver WHERE status = 'streaming'),
+	CASE WHEN pg_catalog.pg_last_wal_receive_lsn() = pg_catalog.pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM pg_catalog.now() - pg_catalog.pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// UnlimitedReplicationLag can be passed to IsReplicationLagAcceptable
+// to only check that a replica is streaming from its source
+const UnlimitedReplicationLag = time.Duration(math.MaxInt64)
+
+// IsReplicationLagAcceptable checks if the instance, when a replica, is not
+// lagging behind the primary more than the passed maximum lag
+func (instance *Instance) IsReplicationLagAcceptable(maximumLag time.Duration) error {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	return checkReplicationLag(superUserDB, maximumLag)
+}
+
+func checkReplicationLag(db *sql.DB, maximumLag time.Duration) error {
+	var (
+		inRecovery bool
+		streaming  bool
+		lagSeconds float64
+	)
+	if err := db.QueryRow(replicationLagQuery).Scan(&inRecovery, &streaming, &lagSeconds); err != nil {
+		return fmt.Errorf("while getting the replication lag: %w", err)
+	}
+
+	if !inRecovery {
+		return nil
+	}
+
+	// Without a WAL receiver, the replica can't know how much WAL
+	// it still has to replay, and its lag can't be trusted
+	if !streaming {
+		return fmt.Errorf("the replica is not streaming from its source")
+	}
+
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	if lag > maximumLag {
+		return fmt.Errorf("replication lag of %v is higher than the maximum allowed of %v",
+			lag.Round(time.Second), maximumLag)
+	}
+
+	return nil
+}
+
 // GetStatus Extract the status of this PostgreSQL database
 func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err error) {
 	result = &postgres.PostgresqlStatus{
 		Pod:                    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: instance.PodName}},
 		InstanceManagerVersion: versions.Version,
 		MightBeUnavailable:     instance.MightBeUnavailable(),
+		FencingMode:            instance.GetFencingMode(),
+		DiskUsage:              instance.GetDiskUsage(),
 	}
 
 	// this deferred function may override the error returned. Take extra care.
@@ -107,19 +169,38 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 			-- True if at least one column requires a restart
 			EXISTS(SELECT 1 FROM pg_settings WHERE pending_restart),
 			-- The size of database in human readable format
-			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database)`)
-	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.TotalInstanceSize)
+			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database),
+			-- The number of client connections running a query
+			(SELECT count(*) FROM pg_catalog.pg_stat_activity
+				WHERE backend_type = 'client backend' AND state = 'active'
+				AND application_name NOT IN ('cnpg-instance-manager', 'cnpg_metrics_exporter'))`)
+	err = row.Scan(
+		&result.SystemID,
+		&result.IsPrimary,
+		&result.PendingRestart,
+		&result.TotalInstanceSize,
+		&result.ActiveConnections)
 	if err != nil {
 		return result, err
 	}
 
 	if result.PendingRestart {
+		result.PendingRestartParameters, err = getPendingRestartSettings(superUserDB)
+		if err != nil {
+			return result, err
+		}
+
 		err = updateResultForDecrease(instance, superUserDB, result)
 		if err != nil {
 			return result, err
 		}
 	}
 
+	result.SlowQueryLoggingSettings, err = getSettings(superUserDB, v1.SlowQueryLoggingParameters)
+	if err != nil {
+		return result, err
+	}
+
 	err = instance.fillStatus(result)
 	if err != nil {
 		return result, err
@@ -246,6 +327,58 @@ WHERE pending_settings.name IN (
 	return decreasedSensibleValues, nil
 }
 
+// getPendingRestartSettings gets the names of the parameters whose
+// changes will be applied only after a restart
+func getPendingRestartSettings(superUserDB *sql.DB) ([]string, error) {
+	rows, err := superUserDB.Query(`SELECT name FROM pg_catalog.pg_settings WHERE pending_restart ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getSettings gets the values in effect of the passed parameters,
+// in the unit used by pg_settings
+func getSettings(superUserDB *sql.DB, names []string) (map[string]string, error) {
+	rows, err := superUserDB.Query(
+		`SELECT name, setting FROM pg_catalog.pg_settings WHERE name = ANY(string_to_array($1, ','))`,
+		strings.Join(names, ","))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := make(map[string]string, len(names))
+	for rows.Next() {
+		var name, setting string
+		if err = rows.Scan(&name, &setting); err != nil {
+			return nil, err
+		}
+		result[name] = setting
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // fillStatus extract the current instance information into the PostgresqlStatus
 // structure
 func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
@@ -276,9 +409,60 @@ func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
 		return err
 	}
 
+	fillWALRestoreStatus(walrestore.StatusFile, result)
+	if err := fillWALSpoolStatus(walarchive.SpoolDirectory, walrestore.SpoolDirectory, result); err != nil {
+		return err
+	}
+
 	return instance.fillWalStatus(result)
 }
 
+// fillWALRestoreStatus reads the outcome of the most recent executions of
+// the restore_command. This information is only reported, so a corrupted
+// status file is not considered an error
+func fillWALRestoreStatus(statusFile string, result *postgres.PostgresqlStatus) {
+	restoreStatus, err := restorer.ReadStatus(statusFile)
+	if err != nil {
+		log.Warning("Cannot read the WAL restore status", "statusFile", statusFile, "error", err.Error())
+		return
+	}
+
+	result.LastRestoredWAL = restoreStatus.LastRestoredWAL
+	result.LastRestoredWALTime = restoreStatus.LastRestoredWALTime
+	result.WALRestoreFailures = restoreStatus.RecentFailures
+}
+
+// fillWALSpoolStatus counts the WAL files that were archived or
+// prefetched in parallel, and that PostgreSQL still has to request
+func fillWALSpoolStatus(archiveSpool, restoreSpool string, result *postgres.PostgresqlStatus) (err error) {
+	if result.ArchiveSpoolWALFiles, err = countSpooledWALFiles(archiveSpool); err != nil {
+		return err
+	}
+
+	result.RestoreSpoolWALFiles, err = countSpooledWALFiles(restoreSpool)
+	return err
+}
+
+// countSpooledWALFiles returns the number of WAL files in a spool directory,
+// ignoring the flags the restorer may have stored there
+func countSpooledWALFiles(spoolDirectory string) (int, error) {
+	files, err := fileutils.GetDirectoryContent(spoolDirectory)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, fileName := range files {
+		if postgres.IsWALFile(fileName) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (instance *Instance) fillBasebackupStats(
 	superUserDB *sql.DB,
 	result *postgres.PostgresqlStatus,
@@ -352,12 +536,25 @@ func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatu
 			(SELECT COALESCE(last_archived_wal, '') FROM pg_catalog.pg_stat_archiver),
 			pg_walfile_name(pg_current_wal_lsn()) as current_wal,
 			pg_current_wal_lsn(),
-			(SELECT timeline_id FROM pg_control_checkpoint()) as timeline_id
+			(SELECT timeline_id FROM pg_control_checkpoint()) as timeline_id,
+			-- The database with the oldest unfrozen transaction or multixact ID,
+			-- as both of them need to be frozen before the wraparound
+			oldest.datname,
+			oldest.xid_age
+		FROM (
+			SELECT datname::text,
+				GREATEST(pg_catalog.age(datfrozenxid), pg_catalog.mxid_age(datminmxid))::bigint AS xid_age
+			FROM pg_catalog.pg_database
+			ORDER BY 2 DESC
+			LIMIT 1
+		) AS oldest
 		`)
 	err = row.Scan(&result.LastArchivedWAL,
 		&result.CurrentWAL,
 		&result.CurrentLsn,
 		&result.TimeLineID,
+		&result.OldestXIDDatabase,
+		&result.OldestXIDAge,
 	)
 
 	return err
@@ -389,10 +586,18 @@ func (instance *Instance) fillReplicationSlotsStatus(result *postgres.Postgresql
 	if !result.IsPrimary {
 		return nil
 	}
-	if ver, _ := instance.GetPgVersion(); ver.Major < 13 {
+	ver, _ := instance.GetPgVersion()
+	if ver.Major < 13 {
 		return nil
 	}
 
+	// The failover flag of the logical replication slots has been
+	// introduced in PostgreSQL 17
+	failoverColumn := "false"
+	if ver.Major >= 17 {
+		failoverColumn = "failover"
+	}
+
 	var err error
 	var slots postgres.PgReplicationSlotList
 	superUserDB, err := instance.GetSuperUserDB()
@@ -412,7 +617,10 @@ func (instance *Instance) fillReplicationSlotsStatus(result *postgres.Postgresql
 	coalesce(catalog_xmin::text, ''),	
 	coalesce(restart_lsn::text, ''),
 	coalesce(wal_status::text, ''),
-	safe_wal_size
+	safe_wal_size,
+	coalesce(confirmed_flush_lsn::text, ''),
+	coalesce(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), confirmed_flush_lsn), 0)::bigint,
+	` + failoverColumn + `
     FROM pg_replication_slots`)
 	if err != nil {
 		return err
@@ -436,6 +644,9 @@ func (instance *Instance) fillReplicationSlotsStatus(result *postgres.Postgresql
 			&slot.RestartLsn,
 			&slot.WalStatus,
 			&slot.SafeWalSize,
+			&slot.ConfirmedFlushLsn,
+			&slot.LagBytes,
+			&slot.Failover,
 		); err != nil {
 			return err
 		}
@@ -543,8 +754,15 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 			"(SELECT timeline_id FROM pg_control_checkpoint()), " +
 			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), " +
 			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), " +
-			"pg_is_wal_replay_paused()")
-	if err := row.Scan(&result.TimeLineID, &result.ReceivedLsn, &result.ReplayLsn, &result.ReplayPaused); err != nil {
+			"pg_is_wal_replay_paused(), " +
+			"(SELECT setting::bigint > 0 FROM pg_catalog.pg_settings WHERE name = 'recovery_min_apply_delay')")
+	if err := row.Scan(
+		&result.TimeLineID,
+		&result.ReceivedLsn,
+		&result.ReplayLsn,
+		&result.ReplayPaused,
+		&result.IsDelayedReplica,
+	); err != nil {
 		return err
 	}
 
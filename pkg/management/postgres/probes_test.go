@@ -17,12 +17,18 @@ limitations under the License.
 package postgres
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
+	"path"
 	"regexp"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/blang/semver"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -148,4 +154,139 @@ var _ = Describe("probes", func() {
 			Expect(status.PgStatBasebackupsInfo[0].TablespacesStreamed).To(Equal(int64(1)))
 		})
 	})
+
+	Context("WAL restore and spool status", func() {
+		It("reports the outcome of the restore_command", func() {
+			statusFile := path.Join(GinkgoT().TempDir(), "wal-restore-status.json")
+			Expect(restorer.RecordSuccess(statusFile, "000000010000000000000001")).To(Succeed())
+			Expect(restorer.RecordFailure(statusFile, "000000010000000000000002",
+				errors.New("access denied"))).To(Succeed())
+
+			status := &postgres.PostgresqlStatus{}
+			fillWALRestoreStatus(statusFile, status)
+			Expect(status.LastRestoredWAL).To(Equal("000000010000000000000001"))
+			Expect(status.WALRestoreFailures).To(HaveLen(1))
+			Expect(status.WALRestoreFailures[0].WALName).To(Equal("000000010000000000000002"))
+		})
+
+		It("doesn't fail with a corrupted restore status file", func() {
+			statusFile := path.Join(GinkgoT().TempDir(), "wal-restore-status.json")
+			Expect(os.WriteFile(statusFile, []byte("{"), 0o600)).To(Succeed())
+
+			status := &postgres.PostgresqlStatus{}
+			fillWALRestoreStatus(statusFile, status)
+			Expect(status.LastRestoredWAL).To(BeEmpty())
+		})
+
+		It("counts the WAL files in the spool directories", func() {
+			archiveSpool := path.Join(GinkgoT().TempDir(), "wal-archive-spool")
+			restoreSpool := path.Join(GinkgoT().TempDir(), "wal-restore-spool")
+			Expect(os.MkdirAll(restoreSpool, 0o700)).To(Succeed())
+			for _, fileName := range []string{
+				"000000010000000000000001",
+				"000000010000000000000002",
+				"end-of-wal-stream",
+			} {
+				Expect(os.WriteFile(path.Join(restoreSpool, fileName), nil, 0o600)).To(Succeed())
+			}
+
+			status := &postgres.PostgresqlStatus{}
+			Expect(fillWALSpoolStatus(archiveSpool, restoreSpool, status)).To(Succeed())
+			Expect(status.ArchiveSpoolWALFiles).To(BeZero())
+			Expect(status.RestoreSpoolWALFiles).To(Equal(2))
+		})
+	})
+})
+
+var _ = Describe("replication lag readiness", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	expectLag := func(inRecovery, streaming bool, lagSeconds float64) {
+		mock.ExpectQuery(replicationLagQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"in_recovery", "streaming", "lag"}).AddRow(inRecovery, streaming, lagSeconds))
+	}
+
+	It("never considers the lag of a primary", func() {
+		expectLag(false, false, 0)
+		Expect(checkReplicationLag(db, 30*time.Second)).To(Succeed())
+	})
+
+	It("accepts a replica lagging less than the threshold", func() {
+		expectLag(true, true, 12.5)
+		Expect(checkReplicationLag(db, 30*time.Second)).To(Succeed())
+	})
+
+	It("rejects a replica lagging more than the threshold", func() {
+		expectLag(true, true, 3600)
+		Expect(checkReplicationLag(db, 30*time.Second)).
+			To(MatchError("replication lag of 1h0m0s is higher than the maximum allowed of 30s"))
+	})
+
+	It("rejects a replica which is not streaming", func() {
+		expectLag(true, false, 0)
+		Expect(checkReplicationLag(db, 30*time.Second)).
+			To(MatchError("the replica is not streaming from its source"))
+	})
+
+	It("only checks the streaming with an unlimited lag", func() {
+		expectLag(true, true, 86400)
+		Expect(checkReplicationLag(db, UnlimitedReplicationLag)).To(Succeed())
+
+		expectLag(true, false, 0)
+		Expect(checkReplicationLag(db, UnlimitedReplicationLag)).
+			To(MatchError("the replica is not streaming from its source"))
+	})
+})
+
+var _ = Describe("pending restart settings", func() {
+	It("lists the parameters waiting for a restart", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT name FROM pg_catalog.pg_settings WHERE pending_restart ORDER BY name`)).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).
+				AddRow("max_connections").
+				AddRow("shared_buffers"))
+
+		settings, err := getPendingRestartSettings(db)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal([]string{"max_connections", "shared_buffers"}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+})
+
+var _ = Describe("current settings", func() {
+	It("gets the values in effect of the passed parameters", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT name, setting FROM pg_catalog.pg_settings WHERE name = ANY(string_to_array($1, ','))`)).
+			WithArgs("log_lock_waits,log_min_duration_statement").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "setting"}).
+				AddRow("log_lock_waits", "on").
+				AddRow("log_min_duration_statement", "2000"))
+
+		settings, err := getSettings(db, []string{"log_lock_waits", "log_min_duration_statement"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(map[string]string{
+			"log_lock_waits":             "on",
+			"log_min_duration_statement": "2000",
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
 })
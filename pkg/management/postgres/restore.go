@@ -135,6 +135,10 @@ func (info InitInfo) RestoreSnapshot(ctx context.Context, cli client.Client, imm
 		return err
 	}
 
+	if err := info.reconcileRecoveredAlterSystemSettings(ctx, cli, cluster); err != nil {
+		return err
+	}
+
 	if cluster.IsReplica() {
 		server, ok := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
 		if !ok {
@@ -156,7 +160,7 @@ func (info InitInfo) RestoreSnapshot(ctx context.Context, cli client.Client, imm
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+	if err := info.writeRestoreWalConfig(backup, cluster, cluster.Spec.Bootstrap.Recovery.RecoveryTarget); err != nil {
 		return err
 	}
 
@@ -246,10 +250,20 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
+	// A physical backup can only be restored by the same major version
+	// of PostgreSQL, let's fail before downloading it
+	if err := cluster.CheckBackupMajorVersion(backup); err != nil {
+		return err
+	}
+
 	if err := info.ensureArchiveContainsLastCheckpointRedoWAL(ctx, cluster, env, backup); err != nil {
 		return err
 	}
 
+	if err := info.ensureTargetTimelineIsReachable(ctx, cluster, env, backup); err != nil {
+		return err
+	}
+
 	if err := info.restoreDataDir(backup, env); err != nil {
 		return err
 	}
@@ -268,6 +282,9 @@ func (info InitInfo) Restore(ctx context.Context) error {
 	if _, err := info.GetInstance().migratePostgresAutoConfFile(ctx); err != nil {
 		return err
 	}
+	if err := info.reconcileRecoveredAlterSystemSettings(ctx, typedClient, cluster); err != nil {
+		return err
+	}
 	if cluster.IsReplica() {
 		server, ok := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
 		if !ok {
@@ -289,7 +306,7 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+	if err := info.writeRestoreWalConfig(backup, cluster, cluster.Spec.Bootstrap.Recovery.RecoveryTarget); err != nil {
 		return err
 	}
 
@@ -325,6 +342,7 @@ func (info InitInfo) ensureArchiveContainsLastCheckpointRedoWAL(
 		BarmanCredentials: backup.Status.BarmanCredentials,
 		EndpointCA:        backup.Status.EndpointCA,
 		EndpointURL:       backup.Status.EndpointURL,
+		ForcePathStyle:    backup.Status.ForcePathStyle,
 		DestinationPath:   backup.Status.DestinationPath,
 		ServerName:        backup.Status.ServerName,
 	}, cluster.Name)
@@ -339,6 +357,104 @@ func (info InitInfo) ensureArchiveContainsLastCheckpointRedoWAL(
 	return nil
 }
 
+// ensureTargetTimelineIsReachable checks, when the recovery target timeline
+// has been explicitly chosen, that it is in the archive and that the
+// backup has been taken on it or on one of its parents before the fork.
+// PostgreSQL would otherwise only notice it after the data has been restored
+func (info InitInfo) ensureTargetTimelineIsReachable(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	env []string,
+	backup *apiv1.Backup,
+) error {
+	targetTimeline := cluster.Spec.Bootstrap.Recovery.RecoveryTarget.GetTargetTimeline()
+	if targetTimeline == 0 {
+		return nil
+	}
+
+	beginSegment, err := postgresSpec.SegmentFromName(backup.Status.BeginWal)
+	if err != nil {
+		return fmt.Errorf("while detecting the timeline of the backup: %w", err)
+	}
+	backupTimeline := int(beginSegment.Tli)
+
+	if targetTimeline < backupTimeline {
+		return fmt.Errorf("cannot recover to timeline %d from a backup taken on timeline %d",
+			targetTimeline, backupTimeline)
+	}
+	if targetTimeline == backupTimeline {
+		return nil
+	}
+
+	opts, err := barman.CloudWalRestoreOptions(&apiv1.BarmanObjectStoreConfiguration{
+		BarmanCredentials: backup.Status.BarmanCredentials,
+		EndpointCA:        backup.Status.EndpointCA,
+		EndpointURL:       backup.Status.EndpointURL,
+		ForcePathStyle:    backup.Status.ForcePathStyle,
+		DestinationPath:   backup.Status.DestinationPath,
+		ServerName:        backup.Status.ServerName,
+	}, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	history, err := downloadTimelineHistory(ctx, cluster, env, opts, targetTimeline)
+	if err != nil {
+		return err
+	}
+
+	if !history.Includes(backupTimeline, postgresSpec.LSN(backup.Status.EndLSN)) {
+		return fmt.Errorf("timeline %d has not been forked from timeline %d after the end of backup %s",
+			targetTimeline, backupTimeline, backup.Status.BackupID)
+	}
+
+	return nil
+}
+
+// downloadTimelineHistory downloads and parses the history file of
+// a timeline from the WAL archive
+func downloadTimelineHistory(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	env []string,
+	walRestoreOptions []string,
+	timeline int,
+) (*catalog.TimelineHistory, error) {
+	contextLogger := log.FromContext(ctx)
+	historyFileName := catalog.TimelineHistoryFileName(timeline)
+	historyFilePath := path.Join(postgresSpec.RecoveryTemporaryDirectory, historyFileName)
+
+	defer func() {
+		if err := fileutils.RemoveFile(historyFilePath); err != nil {
+			contextLogger.Error(err, "while deleting the temporary history file")
+		}
+	}()
+
+	if err := fileutils.EnsureParentDirectoryExists(historyFilePath); err != nil {
+		return nil, err
+	}
+
+	rest, err := restorer.New(ctx, cluster, env, walarchive.SpoolDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rest.Restore(historyFileName, historyFilePath, walRestoreOptions)
+	if errors.Is(err, restorer.ErrWALNotFound) {
+		return nil, fmt.Errorf("timeline %d not found in the archive: %w", timeline, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while downloading the history of timeline %d: %w", timeline, err)
+	}
+
+	content, err := fileutils.ReadFile(historyFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.NewTimelineHistory(timeline, string(content))
+}
+
 // restoreCustomWalDir moves the current pg_wal data to the specified custom wal dir and applies the symlink
 // returns indicating if any changes were made and any error encountered in the process
 func (info InitInfo) restoreCustomWalDir(ctx context.Context) (bool, error) {
@@ -459,38 +575,89 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 	}
 	serverName := server.GetServerName()
 
+	objectStore := server.BarmanObjectStore
+	targetBackup, env, err := findTargetBackupInObjectStore(ctx, typedClient, cluster, objectStore, serverName)
+	if targetBackup == nil && server.ArchiveObjectStore != nil {
+		log.Info("Target backup not found, looking for it in the archive tier",
+			"sourceName", sourceName, "err", err)
+		objectStore = server.ArchiveObjectStore
+		if objectStore.ServerName != "" {
+			serverName = objectStore.ServerName
+		}
+		targetBackup, env, err = findTargetBackupInObjectStore(ctx, typedClient, cluster, objectStore, serverName)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if targetBackup == nil {
+		return nil, nil, fmt.Errorf("no target backup found")
+	}
+
+	log.Info("Target backup found", "backup", targetBackup)
+
+	return newBackupFromCatalog(serverName, objectStore, targetBackup), env, nil
+}
+
+// findTargetBackupInObjectStore chooses the backup to restore among the
+// ones in the catalog of the passed object store, returning it together
+// with the environment needed to access the object store
+func findTargetBackupInObjectStore(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	objectStore *apiv1.BarmanObjectStoreConfiguration,
+	serverName string,
+) (*catalog.BarmanBackup, []string, error) {
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
 		cluster.Namespace,
-		server.BarmanObjectStore,
+		objectStore,
 		os.Environ())
 	if err != nil {
 		return nil, nil, err
 	}
 
-	backupCatalog, err := barman.GetBackupList(ctx, server.BarmanObjectStore, serverName, env)
+	backupCatalog, err := barman.GetBackupList(ctx, objectStore, serverName, env)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// We are now choosing the right backup to restore
-	var targetBackup *catalog.BarmanBackup
 	if cluster.Spec.Bootstrap.Recovery != nil &&
 		cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
-		targetBackup, err = backupCatalog.FindBackupInfo(cluster.Spec.Bootstrap.Recovery.RecoveryTarget)
+		recoveryTarget := cluster.Spec.Bootstrap.Recovery.RecoveryTarget
+
+		// The backups taken on the parents of the target timeline can be
+		// used too, and we need its history to find them
+		var history *catalog.TimelineHistory
+		if timeline := recoveryTarget.GetTargetTimeline(); timeline > 1 {
+			opts, err := barman.CloudWalRestoreOptions(objectStore, serverName)
+			if err != nil {
+				return nil, nil, err
+			}
+			history, err = downloadTimelineHistory(ctx, cluster, env, opts, timeline)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		targetBackup, err := backupCatalog.FindBackupInfo(recoveryTarget, history)
 		if err != nil {
 			return nil, nil, err
 		}
-	} else {
-		targetBackup = backupCatalog.LatestBackupInfo()
-	}
-	if targetBackup == nil {
-		return nil, nil, fmt.Errorf("no target backup found")
+		return targetBackup, env, nil
 	}
 
-	log.Info("Target backup found", "backup", targetBackup)
+	return backupCatalog.LatestBackupInfo(), env, nil
+}
 
+// newBackupFromCatalog generates an in-memory Backup structure given a
+// backup found in the catalog of the passed object store
+func newBackupFromCatalog(
+	serverName string,
+	objectStore *apiv1.BarmanObjectStoreConfiguration,
+	targetBackup *catalog.BarmanBackup,
+) *apiv1.Backup {
 	return &apiv1.Backup{
 		Spec: apiv1.BackupSpec{
 			Cluster: apiv1.LocalObjectReference{
@@ -498,10 +665,10 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 			},
 		},
 		Status: apiv1.BackupStatus{
-			BarmanCredentials: server.BarmanObjectStore.BarmanCredentials,
-			EndpointCA:        server.BarmanObjectStore.EndpointCA,
-			EndpointURL:       server.BarmanObjectStore.EndpointURL,
-			DestinationPath:   server.BarmanObjectStore.DestinationPath,
+			BarmanCredentials: objectStore.BarmanCredentials,
+			EndpointCA:        objectStore.EndpointCA,
+			EndpointURL:       objectStore.EndpointURL,
+			DestinationPath:   objectStore.DestinationPath,
 			ServerName:        serverName,
 			BackupID:          targetBackup.ID,
 			Phase:             apiv1.BackupPhaseCompleted,
@@ -511,11 +678,12 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 			EndWal:            targetBackup.EndWal,
 			BeginLSN:          targetBackup.BeginLSN,
 			EndLSN:            targetBackup.EndLSN,
+			MajorVersion:      targetBackup.MajorVersion(),
 			Error:             targetBackup.Error,
 			CommandOutput:     "",
 			CommandError:      "",
 		},
-	}, env, nil
+	}
 }
 
 // loadBackupFromReference loads a backup object and the required credentials given the backup object resource
@@ -541,6 +709,7 @@ func (info InitInfo) loadBackupFromReference(
 			BarmanCredentials: backup.Status.BarmanCredentials,
 			EndpointCA:        backup.Status.EndpointCA,
 			EndpointURL:       backup.Status.EndpointURL,
+			ForcePathStyle:    backup.Status.ForcePathStyle,
 			DestinationPath:   backup.Status.DestinationPath,
 			ServerName:        backup.Status.ServerName,
 		},
@@ -554,9 +723,13 @@ func (info InitInfo) loadBackupFromReference(
 }
 
 // writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
-// to complete the WAL recovery from the object storage and then start
-// as a new primary
-func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
+// to complete the WAL recovery from the object storage, up to the
+// passed recovery target, and then start as a new primary
+func (info InitInfo) writeRestoreWalConfig(
+	backup *apiv1.Backup,
+	cluster *apiv1.Cluster,
+	recoveryTarget *apiv1.RecoveryTarget,
+) error {
 	var err error
 
 	cmd := []string{barmanCapabilities.BarmanCloudWalRestore}
@@ -578,7 +751,7 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 			"restore_command = '%s'\n"+
 			"%s",
 		strings.Join(cmd, " "),
-		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
+		recoveryTarget.BuildPostgresOptions())
 
 	return info.writeRecoveryConfiguration(cluster, recoveryFileContents)
 }
@@ -776,7 +949,9 @@ func (info InitInfo) WriteInitialPostgresqlConf(cluster *apiv1.Cluster) error {
 	if err != nil {
 		return fmt.Errorf("while generating pg_hba.conf: %w", err)
 	}
-	_, err = temporaryInstance.RefreshPGIdent(cluster.Spec.PostgresConfiguration.PgIdent)
+	_, err = temporaryInstance.RefreshPGIdent(
+		cluster.Spec.PostgresConfiguration.PgIdent,
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.GetIdentMappings())
 	if err != nil {
 		return fmt.Errorf("while generating pg_ident.conf: %w", err)
 	}
@@ -830,7 +1005,7 @@ func (info InitInfo) WriteRestoreHbaConf() error {
 	}
 
 	// Create only the local map referred in the HBA configuration
-	_, err = info.GetInstance().RefreshPGIdent(nil)
+	_, err = info.GetInstance().RefreshPGIdent(nil, nil)
 	return err
 }
 
@@ -863,11 +1038,22 @@ func (info InitInfo) ConfigureInstanceAfterRestore(ctx context.Context, cluster
 			return fmt.Errorf("while waiting for PostgreSQL to stop recovery mode: %w", err)
 		}
 
-		return nil
+		// The maintenance operations are only available when
+		// bootstrapping with the recovery method
+		if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+			return nil
+		}
+		command := cluster.Spec.Bootstrap.Recovery.PostRecovery.GetMaintenanceCommand()
+		if command == "" {
+			return nil
+		}
+
+		return runPostRecoveryMaintenance(ctx, db, instance.ConnectionPool().Connection, command)
 	}); err != nil {
 		return err
 	}
 
+	info.SSLMinProtocolVersion = cluster.GetClientSSLMinProtocolVersion()
 	primaryConnInfo := info.GetPrimaryConnInfo()
 	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 	if _, err := configurePostgresOverrideConfFile(info.PgData, primaryConnInfo, slotName); err != nil {
@@ -889,9 +1075,56 @@ func (info InitInfo) ConfigureInstanceAfterRestore(ctx context.Context, cluster
 	})
 }
 
+// runPostRecoveryMaintenance runs the passed maintenance command on every
+// database accepting connections, refreshing the statistics that are
+// stale after a recovery
+func runPostRecoveryMaintenance(
+	ctx context.Context,
+	db *sql.DB,
+	getConnection func(dbname string) (*sql.DB, error),
+	command string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	rows, err := db.QueryContext(
+		ctx,
+		"SELECT datname FROM pg_catalog.pg_database WHERE datallowconn ORDER BY datname")
+	if err != nil {
+		return fmt.Errorf("while getting the list of databases: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return err
+		}
+		databases = append(databases, database)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, database := range databases {
+		contextLogger.Info("Running post-recovery maintenance", "database", database, "command", command)
+		targetDB, err := getConnection(database)
+		if err != nil {
+			return fmt.Errorf("while connecting to database %s: %w", database, err)
+		}
+		if _, err := targetDB.ExecContext(ctx, command); err != nil {
+			return fmt.Errorf("while running %s on database %s: %w", command, database, err)
+		}
+	}
+
+	return nil
+}
+
 // GetPrimaryConnInfo returns the DSN to reach the primary
 func (info InitInfo) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(info.ClusterName+"-rw", info.PodName)
+	return buildPrimaryConnInfo(info.ClusterName+"-rw", info.PodName, info.SSLMinProtocolVersion)
 }
 
 func (info *InitInfo) checkBackupDestination(
@@ -923,7 +1156,7 @@ func (info *InitInfo) checkBackupDestination(
 	}
 
 	// Get WAL archive options
-	checkWalOptions, err := walArchiver.BarmanCloudCheckWalArchiveOptions(cluster, cluster.Name)
+	checkWalOptions, err := walArchiver.BarmanCloudCheckWalArchiveOptions(cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
 	if err != nil {
 		log.Error(err, "while getting barman-cloud-wal-archive options")
 		return err
@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// reconcileRecoveredAlterSystemSettings handles the parameters set with
+// ALTER SYSTEM in the origin cluster, which are stored in the
+// postgresql.auto.conf file of the recovered backup. Unless the user asked
+// to reapply them, they are removed, as they would take precedence over
+// the configuration managed by the operator. In both cases, they are
+// reported in the status of the cluster.
+func (info InitInfo) reconcileRecoveredAlterSystemSettings(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	autoConfFile := filepath.Join(info.PgData, "postgresql.auto.conf")
+	lines, err := fileutils.ReadFileLines(autoConfFile)
+	if err != nil {
+		return fmt.Errorf("while reading postgresql.auto.conf file: %w", err)
+	}
+
+	parameters := parseAutoConfParameters(lines)
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	status := newRecoveredAlterSystemSettingsStatus(cluster, parameters)
+	if status.Reapplied {
+		contextLogger.Info("Keeping the ALTER SYSTEM settings of the recovered backup",
+			"parameters", parameters,
+			"divergingParameters", status.DivergingParameters)
+	} else {
+		contextLogger.Warning("Removing the ALTER SYSTEM settings of the recovered backup",
+			"parameters", parameters,
+			"divergingParameters", status.DivergingParameters)
+		if _, err := fileutils.WriteLinesToFile(autoConfFile, removeAutoConfParameters(lines)); err != nil {
+			return fmt.Errorf("while cleaning up postgresql.auto.conf file: %w", err)
+		}
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.RecoveredAlterSystemSettings = status
+	return cli.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// newRecoveredAlterSystemSettingsStatus creates the status reporting the
+// passed ALTER SYSTEM settings, detecting the ones diverging from the
+// parameters declared in the cluster
+func newRecoveredAlterSystemSettingsStatus(
+	cluster *apiv1.Cluster,
+	parameters map[string]string,
+) *apiv1.RecoveredAlterSystemSettingsStatus {
+	status := &apiv1.RecoveredAlterSystemSettingsStatus{
+		Reapplied:  cluster.Spec.Bootstrap.Recovery.ReapplyAlterSystemSettings,
+		Parameters: parameters,
+	}
+
+	for name, value := range parameters {
+		if declaredValue, ok := cluster.Spec.PostgresConfiguration.Parameters[name]; !ok || declaredValue != value {
+			status.DivergingParameters = append(status.DivergingParameters, name)
+		}
+	}
+	slices.Sort(status.DivergingParameters)
+
+	return status
+}
+
+// parseAutoConfParameters extracts the parameters from the content of
+// a postgresql.auto.conf file, skipping the ones managed by the operator
+func parseAutoConfParameters(lines []string) map[string]string {
+	managedOptions := stringset.From(cleanupAutoConfOptions)
+	result := make(map[string]string)
+	for _, line := range lines {
+		name, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" || strings.HasPrefix(name, "#") || managedOptions.Has(name) {
+			continue
+		}
+
+		result[name] = unquoteConfigurationValue(strings.TrimSpace(value))
+	}
+
+	return result
+}
+
+// unquoteConfigurationValue removes the quotes from a value, as written
+// by PostgreSQL in the postgresql.auto.conf file
+func unquoteConfigurationValue(value string) string {
+	if len(value) < 2 || !strings.HasPrefix(value, "'") || !strings.HasSuffix(value, "'") {
+		return value
+	}
+
+	return strings.NewReplacer("''", "'", `\\`, `\`).Replace(value[1 : len(value)-1])
+}
+
+// removeAutoConfParameters removes every parameter from the content of
+// a postgresql.auto.conf file, keeping only the comments
+func removeAutoConfParameters(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
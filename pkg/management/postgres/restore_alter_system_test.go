@@ -0,0 +1,140 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ALTER SYSTEM settings of a recovered backup", func() {
+	const autoConfContent = `# Do not edit this file manually!
+# It will be overwritten by the ALTER SYSTEM command.
+work_mem = '64MB'
+shared_buffers = '1GB'
+log_line_prefix = '%m [%p] ''%a'' '
+primary_conninfo = 'host=origin'
+`
+
+	var (
+		cluster  *apiv1.Cluster
+		cli      client.Client
+		initInfo InitInfo
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Recovery: &apiv1.BootstrapRecovery{Source: "origin"},
+				},
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					Parameters: map[string]string{
+						"work_mem":       "64MB",
+						"shared_buffers": "512MB",
+					},
+				},
+			},
+		}
+		cli = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			WithStatusSubresource(&apiv1.Cluster{}).
+			Build()
+		initInfo = InitInfo{PgData: GinkgoT().TempDir()}
+	})
+
+	writeAutoConf := func(content string) string {
+		fileName := filepath.Join(initInfo.PgData, "postgresql.auto.conf")
+		Expect(os.WriteFile(fileName, []byte(content), 0o600)).To(Succeed())
+		return fileName
+	}
+
+	It("parses the parameters, skipping the ones managed by the operator", func() {
+		lines, err := fileutils.ReadFileLines(writeAutoConf(autoConfContent))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(parseAutoConfParameters(lines)).To(Equal(map[string]string{
+			"work_mem":        "64MB",
+			"shared_buffers":  "1GB",
+			"log_line_prefix": "%m [%p] '%a' ",
+		}))
+	})
+
+	It("detects the parameters diverging from the declared ones", func() {
+		status := newRecoveredAlterSystemSettingsStatus(cluster, map[string]string{
+			"work_mem":        "64MB",
+			"shared_buffers":  "1GB",
+			"log_line_prefix": "%m ",
+		})
+		Expect(status.Reapplied).To(BeFalse())
+		Expect(status.DivergingParameters).To(Equal([]string{"log_line_prefix", "shared_buffers"}))
+	})
+
+	It("removes the parameters by default, reporting them", func(ctx SpecContext) {
+		fileName := writeAutoConf(autoConfContent)
+
+		Expect(initInfo.reconcileRecoveredAlterSystemSettings(ctx, cli, cluster)).To(Succeed())
+
+		lines, err := fileutils.ReadFileLines(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(HavePrefix("#"))
+
+		var updatedCluster apiv1.Cluster
+		Expect(cli.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+		status := updatedCluster.Status.RecoveredAlterSystemSettings
+		Expect(status).ToNot(BeNil())
+		Expect(status.Reapplied).To(BeFalse())
+		Expect(status.Parameters).To(HaveKeyWithValue("shared_buffers", "1GB"))
+		Expect(status.DivergingParameters).To(ConsistOf("shared_buffers", "log_line_prefix"))
+	})
+
+	It("keeps the parameters when requested", func(ctx SpecContext) {
+		cluster.Spec.Bootstrap.Recovery.ReapplyAlterSystemSettings = true
+		fileName := writeAutoConf(autoConfContent)
+
+		Expect(initInfo.reconcileRecoveredAlterSystemSettings(ctx, cli, cluster)).To(Succeed())
+
+		content, err := fileutils.ReadFile(fileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal(autoConfContent))
+		Expect(cluster.Status.RecoveredAlterSystemSettings.Reapplied).To(BeTrue())
+	})
+
+	It("does nothing when there are no parameters", func(ctx SpecContext) {
+		writeAutoConf("# Do not edit this file manually!\n")
+
+		Expect(initInfo.reconcileRecoveredAlterSystemSettings(ctx, cli, cluster)).To(Succeed())
+		Expect(cluster.Status.RecoveredAlterSystemSettings).To(BeNil())
+	})
+})
@@ -18,9 +18,12 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"os"
 	"path"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/thoas/go-funk"
 	"k8s.io/utils/strings/slices"
 
@@ -205,3 +208,48 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(enforcedParamsInPGData["max_connections"]).To(Equal(200))
 	})
 })
+
+var _ = Describe("post-recovery maintenance", func() {
+	const databasesQuery = "SELECT datname FROM pg_catalog.pg_database WHERE datallowconn ORDER BY datname"
+
+	var (
+		db          *sql.DB
+		dbMock      sqlmock.Sqlmock
+		connectedTo []string
+	)
+
+	getConnection := func(dbname string) (*sql.DB, error) {
+		connectedTo = append(connectedTo, dbname)
+		return db, nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		db, dbMock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		connectedTo = nil
+	})
+
+	AfterEach(func() {
+		Expect(dbMock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("runs the maintenance command on every database", func(ctx SpecContext) {
+		dbMock.ExpectQuery(databasesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("postgres"))
+		dbMock.ExpectExec("VACUUM (ANALYZE)").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("VACUUM (ANALYZE)").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(runPostRecoveryMaintenance(ctx, db, getConnection, "VACUUM (ANALYZE)")).To(Succeed())
+		Expect(connectedTo).To(Equal([]string{"app", "postgres"}))
+	})
+
+	It("stops at the first failure", func(ctx SpecContext) {
+		dbMock.ExpectQuery(databasesQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"datname"}).AddRow("app").AddRow("postgres"))
+		dbMock.ExpectExec("ANALYZE").WillReturnError(errors.New("canceling statement"))
+
+		Expect(runPostRecoveryMaintenance(ctx, db, getConnection, "ANALYZE")).
+			To(MatchError(ContainSubstring("while running ANALYZE on database app")))
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrPgRewindWALUnavailable is returned when pg_rewind failed because a WAL
+// file it needs to find the point of divergence from the new primary is not
+// available anymore, neither in pg_wal nor in the WAL archive. Retrying
+// pg_rewind won't help in this case.
+var ErrPgRewindWALUnavailable = errors.New("a WAL file required by pg_rewind is not available")
+
+// pgRewindMissingWALFile is the file where the restore_command run by
+// pg_rewind records the WAL files that are not available
+var pgRewindMissingWALFile = filepath.Join(postgres.ScratchDataDirectory, "pg-rewind-missing-wal")
+
+// getPgRewindEnv gets the environment of pg_rewind, telling the
+// restore_command where to record the WAL files it can't find
+func getPgRewindEnv(env []string, missingWALFile string) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+
+	return append(slices.Clone(env), postgres.PgRewindMissingWALFileEnv+"="+missingWALFile)
+}
+
+// getPgRewindMissingWALs gets the WAL files that the restore_command run by
+// pg_rewind couldn't find, as recorded in the passed file
+func getPgRewindMissingWALs(missingWALFile string) ([]string, error) {
+	content, err := os.ReadFile(missingWALFile) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(content)), nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_rewind missing WAL files", func() {
+	It("tells the restore_command where to record the missing WAL files", func() {
+		env := []string{"PGDATA=/var/lib/postgresql/data/pgdata"}
+		Expect(getPgRewindEnv(env, "/controller/missing")).To(Equal([]string{
+			"PGDATA=/var/lib/postgresql/data/pgdata",
+			postgres.PgRewindMissingWALFileEnv + "=/controller/missing",
+		}))
+		Expect(env).To(HaveLen(1))
+	})
+
+	It("inherits the environment of the instance manager by default", func() {
+		Expect(getPgRewindEnv(nil, "/controller/missing")).To(ContainElements(
+			os.Environ()[0],
+			postgres.PgRewindMissingWALFileEnv+"=/controller/missing",
+		))
+	})
+
+	It("reads the recorded WAL files", func() {
+		missingWALFile := filepath.Join(GinkgoT().TempDir(), "missing")
+
+		Expect(getPgRewindMissingWALs(missingWALFile)).To(BeEmpty())
+
+		Expect(os.WriteFile(missingWALFile,
+			[]byte("000000010000000000000003\n000000010000000000000004\n"), 0o600)).To(Succeed())
+		Expect(getPgRewindMissingWALs(missingWALFile)).To(Equal([]string{
+			"000000010000000000000003",
+			"000000010000000000000004",
+		}))
+	})
+})
@@ -0,0 +1,666 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	pgUpgradeName = "pg_upgrade"
+	pgConfigName  = "pg_config"
+
+	// oldBinDirFileName is the name of the file, inside the directory
+	// where the binaries of the previous major version are copied,
+	// containing the path of their bindir
+	oldBinDirFileName = "bindir"
+
+	// upgradedDataSuffix is appended to the data and WAL directories
+	// to get the ones where the upgraded cluster is created
+	upgradedDataSuffix = "-new"
+
+	// previousDataSuffix is appended to the data directory when it's
+	// replaced by the upgraded one
+	previousDataSuffix = "-old"
+
+	// pgControlFileOldSuffix is appended by pg_upgrade to the control
+	// file of the old cluster when using the link mode
+	pgControlFileOldSuffix = ".old"
+
+	// upgradeStateFileSuffix is appended to the data directory to get
+	// the file where the progress of the upgrade is persisted
+	upgradeStateFileSuffix = "-upgrade.json"
+)
+
+// majorUpgradePhase is the progress of the upgrade of a data directory
+type majorUpgradePhase string
+
+const (
+	// majorUpgradePhaseRunning means that pg_upgrade may have changed the
+	// original data directory, which needs to be rolled back if the
+	// upgrade is interrupted
+	majorUpgradePhaseRunning majorUpgradePhase = "running"
+
+	// majorUpgradePhaseUpgraded means that pg_upgrade has been completed,
+	// and the original data directory is being replaced by the upgraded one
+	majorUpgradePhaseUpgraded majorUpgradePhase = "upgraded"
+
+	// majorUpgradePhaseCompleted means that the original data directory
+	// has been replaced, and is kept until the upgraded instance is
+	// confirmed to be running
+	majorUpgradePhaseCompleted majorUpgradePhase = "completed"
+)
+
+// majorUpgradeState is persisted beside the data directory during its
+// upgrade, so that an interrupted upgrade can be resumed or rolled back
+type majorUpgradeState struct {
+	Phase    majorUpgradePhase `json:"phase"`
+	OldMajor int               `json:"oldMajor"`
+	NewMajor int               `json:"newMajor"`
+	PgWal    string            `json:"pgWal,omitempty"`
+}
+
+// readMajorUpgradeState reads the progress of the upgrade of the
+// passed data directory, returning nil when there's no upgrade
+func readMajorUpgradeState(pgData string) (*majorUpgradeState, error) {
+	fileName := pgData + upgradeStateFileSuffix
+	exists, err := fileutils.FileExists(fileName)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	content, err := fileutils.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var state majorUpgradeState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("while parsing the state of the major upgrade: %w", err)
+	}
+	return &state, nil
+}
+
+// writeMajorUpgradeState persists the progress of the
+// upgrade of the passed data directory
+func writeMajorUpgradeState(pgData string, state majorUpgradeState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteFileAtomic(pgData+upgradeStateFileSuffix, content, 0o600)
+	return err
+}
+
+// removeMajorUpgradeState removes the progress of the
+// upgrade of the passed data directory
+func removeMajorUpgradeState(pgData string) error {
+	return fileutils.RemoveFile(pgData + upgradeStateFileSuffix)
+}
+
+// CopyPostgresBinaries copies the PostgreSQL binaries of this image,
+// together with their libraries and shared files, into the destination
+// directory, keeping their relative location. This allows pg_upgrade to
+// run them from an image containing a different major version
+func CopyPostgresBinaries(ctx context.Context, destination string) error {
+	contextLogger := log.FromContext(ctx)
+
+	out, err := exec.Command(pgConfigName, "--bindir", "--pkglibdir", "--sharedir").Output() // #nosec G204
+	if err != nil {
+		return fmt.Errorf("while detecting the PostgreSQL installation directories: %w", err)
+	}
+
+	directories := strings.Fields(string(out))
+	if len(directories) != 3 {
+		return fmt.Errorf("unexpected output from %s: %q", pgConfigName, string(out))
+	}
+
+	for _, directory := range directories {
+		contextLogger.Info("Copying the PostgreSQL installation directory",
+			"directory", directory,
+			"destination", destination)
+		if err := fileutils.CopyDirectory(directory, filepath.Join(destination, directory)); err != nil {
+			return fmt.Errorf("while copying %s: %w", directory, err)
+		}
+	}
+
+	_, err = fileutils.WriteStringToFile(
+		filepath.Join(destination, oldBinDirFileName),
+		filepath.Join(destination, directories[0]))
+	return err
+}
+
+// UpgradeMajorVersion upgrades the data directory to the PostgreSQL major
+// version of this image via pg_upgrade, using the binaries of the previous
+// major version copied in oldBinariesDirectory. The upgrade is aborted,
+// leaving the original data directory usable, if the pre-upgrade checks or
+// pg_upgrade itself fail. The progress of the upgrade is persisted, so that
+// an interrupted upgrade is resumed, or rolled back, when retried
+func (info InitInfo) UpgradeMajorVersion(ctx context.Context, oldBinariesDirectory string) error {
+	contextLogger := log.FromContext(ctx)
+	newInfo := info.getUpgradedDataInfo()
+
+	state, err := readMajorUpgradeState(info.PgData)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		switch state.Phase {
+		case majorUpgradePhaseUpgraded:
+			// pg_upgrade has already been completed, and the upgraded
+			// data directory has never been started
+			contextLogger.Info("Resuming the replacement of the data directory with the upgraded one",
+				"from", state.OldMajor,
+				"to", state.NewMajor)
+			if err := info.completeMajorUpgrade(newInfo, *state); err != nil {
+				return fmt.Errorf("while replacing the data directory with the upgraded one: %w", err)
+			}
+			return nil
+
+		case majorUpgradePhaseRunning:
+			contextLogger.Info("Rolling back an interrupted upgrade before retrying it",
+				"from", state.OldMajor,
+				"to", state.NewMajor)
+			if err := info.rollbackInterruptedMajorUpgrade(*state); err != nil {
+				return fmt.Errorf("while rolling back the interrupted upgrade: %w", err)
+			}
+		}
+	}
+
+	content, err := fileutils.ReadFile(filepath.Join(oldBinariesDirectory, oldBinDirFileName))
+	if err != nil {
+		return fmt.Errorf("while locating the binaries of the previous major version: %w", err)
+	}
+	oldBinDir := strings.TrimSpace(string(content))
+
+	oldMajor, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("while reading the major version of the data directory: %w", err)
+	}
+
+	newMajor, err := getPostgresMajorVersion()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case newMajor == oldMajor:
+		// This happens when the upgrade has been already completed
+		contextLogger.Info("The data directory doesn't need to be upgraded",
+			"majorVersion", oldMajor)
+		return nil
+	case newMajor < oldMajor:
+		return fmt.Errorf("can't downgrade the data directory from major %d to %d", oldMajor, newMajor)
+	}
+
+	// The data of a previous upgrade are not needed anymore,
+	// as the data directory is being upgraded again
+	if _, err := RemovePreviousMajorVersionData(info.PgData); err != nil {
+		return err
+	}
+
+	controlData, err := getPgControlData(oldBinDir, info.PgData)
+	if err != nil {
+		return err
+	}
+
+	pgDataState := utils.PgDataState(controlData[utils.PgControlDataDatabaseClusterStateKey])
+	if !pgDataState.IsShutdown(ctx) {
+		return fmt.Errorf("the data directory has not been cleanly shut down, its state is %q", pgDataState)
+	}
+
+	newInfo.InitDBOptions = append(newInfo.InitDBOptions, buildUpgradeInitDBOptions(controlData, newMajor)...)
+
+	// Any leftover of a previous attempt can be safely discarded, as the
+	// original data directory is only replaced after a successful upgrade
+	if err := newInfo.removeDataDirectories(); err != nil {
+		return err
+	}
+
+	if err := fileutils.EnsureDirectoryExists(postgres.UpgradeTemporaryDirectory); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Creating the data directory of the new major version",
+		"from", oldMajor,
+		"to", newMajor)
+	if err := newInfo.CreateDataDirectory(); err != nil {
+		return errors.Join(err, newInfo.removeDataDirectories())
+	}
+
+	contextLogger.Info("Checking the compatibility of the data directory with the new major version")
+	if err := info.runPgUpgrade(oldBinDir, newInfo.PgData, "--check"); err != nil {
+		return errors.Join(
+			fmt.Errorf("pre-upgrade checks failed, the upgrade has been aborted: %w", err),
+			newInfo.removeDataDirectories())
+	}
+
+	// From now on, pg_upgrade changes the original data directory
+	// which needs to be rolled back if the upgrade is interrupted
+	upgradeState := majorUpgradeState{
+		Phase:    majorUpgradePhaseRunning,
+		OldMajor: oldMajor,
+		NewMajor: newMajor,
+		PgWal:    info.PgWal,
+	}
+	if err := writeMajorUpgradeState(info.PgData, upgradeState); err != nil {
+		return errors.Join(err, newInfo.removeDataDirectories())
+	}
+
+	contextLogger.Info("Upgrading the data directory")
+	if err := info.runPgUpgrade(oldBinDir, newInfo.PgData); err != nil {
+		return errors.Join(
+			fmt.Errorf("pg_upgrade failed, the upgrade has been rolled back: %w", err),
+			info.rollbackInterruptedMajorUpgrade(upgradeState))
+	}
+
+	upgradeState.Phase = majorUpgradePhaseUpgraded
+	if err := writeMajorUpgradeState(info.PgData, upgradeState); err != nil {
+		return err
+	}
+
+	if err := info.completeMajorUpgrade(newInfo, upgradeState); err != nil {
+		return fmt.Errorf("while replacing the data directory with the upgraded one: %w", err)
+	}
+
+	contextLogger.Info("The data directory has been upgraded",
+		"from", oldMajor,
+		"to", newMajor)
+	return nil
+}
+
+// getUpgradedDataInfo gets the InitInfo of the data directory
+// where the upgraded cluster is created
+func (info InitInfo) getUpgradedDataInfo() InitInfo {
+	newInfo := info
+	newInfo.PgData = info.PgData + upgradedDataSuffix
+	if info.PgWal != "" {
+		newInfo.PgWal = info.PgWal + upgradedDataSuffix
+	}
+	return newInfo
+}
+
+// getPreviousDataInfo gets the InitInfo of the data directory
+// of the previous major version, once replaced by the upgraded one
+func (info InitInfo) getPreviousDataInfo() InitInfo {
+	previousInfo := info
+	previousInfo.PgData = info.PgData + previousDataSuffix
+	if info.PgWal != "" {
+		previousInfo.PgWal = info.PgWal + previousDataSuffix
+	}
+	return previousInfo
+}
+
+// removeDataDirectories removes the data and WAL directories
+func (info InitInfo) removeDataDirectories() error {
+	if err := os.RemoveAll(info.PgData); err != nil {
+		return err
+	}
+	if info.PgWal != "" {
+		return os.RemoveAll(info.PgWal)
+	}
+	return nil
+}
+
+// runPgUpgrade runs pg_upgrade, in link mode, from the data directory
+// of this instance to the new one
+func (info InitInfo) runPgUpgrade(oldBinDir, newPgData string, options ...string) error {
+	pgUpgradeCmd := exec.Command(pgUpgradeName, // #nosec G204
+		buildPgUpgradeOptions(oldBinDir, info.PgData, newPgData, options...)...)
+	pgUpgradeCmd.Dir = postgres.UpgradeTemporaryDirectory
+	return execlog.RunStreaming(pgUpgradeCmd, pgUpgradeName)
+}
+
+// buildPgUpgradeOptions builds the command line options of pg_upgrade
+func buildPgUpgradeOptions(oldBinDir, oldPgData, newPgData string, options ...string) []string {
+	return append([]string{
+		"--old-bindir", oldBinDir,
+		"--old-datadir", oldPgData,
+		"--new-datadir", newPgData,
+		"--username", "postgres",
+		"--socketdir", postgres.UpgradeTemporaryDirectory,
+		"--link",
+		// The configuration of the old cluster refers to the server
+		// certificates and to the WAL archiver of the instance manager,
+		// which are not available while upgrading
+		"--old-options", "-c ssl=off -c archive_mode=off",
+	}, options...)
+}
+
+// buildUpgradeInitDBOptions gets the options of initdb needed to create
+// a data directory compatible, for pg_upgrade, with the original one
+func buildUpgradeInitDBOptions(controlData map[string]string, newMajor int) []string {
+	var options []string
+
+	switch checksums := controlData[utils.PgControlDataKeyDataPageChecksumVersion]; {
+	case checksums != "" && checksums != "0":
+		options = append(options, "--data-checksums")
+	case newMajor >= 18:
+		// Data checksums are enabled by default since PostgreSQL 18
+		options = append(options, "--no-data-checksums")
+	}
+
+	if walSegmentSize, err := strconv.Atoi(controlData[utils.PgControlDataKeyBytesPerWALSegment]); err == nil &&
+		walSegmentSize > 0 {
+		options = append(options, fmt.Sprintf("--wal-segsize=%d", walSegmentSize/(1024*1024)))
+	}
+
+	return options
+}
+
+// RollbackInterruptedMajorUpgrade makes the original data directory usable
+// again when its upgrade has been interrupted before the upgraded one was
+// ever started, which happens when the upgrade is aborted by restoring the
+// image of the previous major version. Nothing is done otherwise
+func RollbackInterruptedMajorUpgrade(ctx context.Context, pgData string) error {
+	state, err := readMajorUpgradeState(pgData)
+	if err != nil {
+		return err
+	}
+	if state == nil || state.Phase == majorUpgradePhaseCompleted {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Rolling back the interrupted upgrade of the data directory",
+		"from", state.OldMajor,
+		"to", state.NewMajor)
+	info := InitInfo{PgData: pgData, PgWal: state.PgWal}
+	return info.rollbackInterruptedMajorUpgrade(*state)
+}
+
+// rollbackInterruptedMajorUpgrade puts the original data and WAL
+// directories back in place, if they have been already replaced by the
+// upgraded ones, and makes the original data directory usable again
+func (info InitInfo) rollbackInterruptedMajorUpgrade(state majorUpgradeState) error {
+	newInfo := info.getUpgradedDataInfo()
+	previousInfo := info.getPreviousDataInfo()
+
+	if state.Phase == majorUpgradePhaseUpgraded {
+		if err := restoreReplacedDirectory(previousInfo.PgData, info.PgData); err != nil {
+			return err
+		}
+		if info.PgWal != "" {
+			if err := restoreReplacedDirectory(previousInfo.PgWal, info.PgWal); err != nil {
+				return err
+			}
+		}
+	}
+
+	return info.rollbackMajorUpgrade(newInfo)
+}
+
+// restoreReplacedDirectory moves the previous directory back to its
+// original location, if it has been replaced by the upgraded one. The
+// upgraded directory is discarded, as in link mode its data files are
+// also linked in the previous one
+func restoreReplacedDirectory(previousDirectory, directory string) error {
+	previousExists, err := fileutils.FileExists(previousDirectory)
+	if err != nil || !previousExists {
+		return err
+	}
+
+	if err := os.RemoveAll(directory); err != nil {
+		return err
+	}
+	return os.Rename(previousDirectory, directory)
+}
+
+// rollbackMajorUpgrade makes the original data directory usable again
+// after a failed pg_upgrade, and removes the upgraded one
+func (info InitInfo) rollbackMajorUpgrade(newInfo InitInfo) error {
+	// In link mode, pg_upgrade renames the control file of the old
+	// cluster to prevent it from being started together with the new
+	// one. As the new cluster has never been started, the old one
+	// can be used again by restoring it
+	controlFile := filepath.Join(info.PgData, "global", "pg_control")
+	oldControlFile := controlFile + pgControlFileOldSuffix
+	oldControlFileExists, err := fileutils.FileExists(oldControlFile)
+	if err != nil {
+		return err
+	}
+	if oldControlFileExists {
+		if err := os.Rename(oldControlFile, controlFile); err != nil {
+			return fmt.Errorf("while restoring the control file of the data directory: %w", err)
+		}
+	}
+
+	if err := newInfo.removeDataDirectories(); err != nil {
+		return err
+	}
+
+	return removeMajorUpgradeState(info.PgData)
+}
+
+// completeMajorUpgrade replaces the original data directory with the
+// upgraded one, carrying over the configuration which is not generated
+// by the instance manager. The original data and WAL directories are
+// kept, until the upgraded instance is confirmed to be running, beside
+// the upgraded ones. Each step can be safely repeated, so that an
+// interrupted replacement can be resumed
+func (info InitInfo) completeMajorUpgrade(newInfo InitInfo, state majorUpgradeState) error {
+	previousInfo := info.getPreviousDataInfo()
+
+	newPgDataExists, err := fileutils.FileExists(newInfo.PgData)
+	if err != nil {
+		return err
+	}
+	if newPgDataExists {
+		pgDataExists, err := fileutils.FileExists(info.PgData)
+		if err != nil {
+			return err
+		}
+		if pgDataExists {
+			if err := copyUserConfigurationFiles(info.PgData, newInfo.PgData); err != nil {
+				return err
+			}
+			if err := replaceDirectory(info.PgData, previousInfo.PgData); err != nil {
+				return err
+			}
+		}
+		if err := os.Rename(newInfo.PgData, info.PgData); err != nil {
+			return err
+		}
+	}
+
+	if info.PgWal != "" {
+		newPgWalExists, err := fileutils.FileExists(newInfo.PgWal)
+		if err != nil {
+			return err
+		}
+		if newPgWalExists {
+			pgWalExists, err := fileutils.FileExists(info.PgWal)
+			if err != nil {
+				return err
+			}
+			if pgWalExists {
+				if err := replaceDirectory(info.PgWal, previousInfo.PgWal); err != nil {
+					return err
+				}
+			}
+			if err := os.Rename(newInfo.PgWal, info.PgWal); err != nil {
+				return err
+			}
+		}
+
+		// The upgraded data directory still points to
+		// the WAL directory where it was created
+		if err := ensureWALDirectoryLink(info.PgData, info.PgWal); err != nil {
+			return err
+		}
+	}
+
+	state.Phase = majorUpgradePhaseCompleted
+	return writeMajorUpgradeState(info.PgData, state)
+}
+
+// copyUserConfigurationFiles copies the configuration files which are
+// not generated by the instance manager between the passed data directories
+func copyUserConfigurationFiles(sourcePgData, destinationPgData string) error {
+	for _, fileName := range []string{"postgresql.auto.conf", constants.PostgresqlOverrideConfigurationFile} {
+		source := filepath.Join(sourcePgData, fileName)
+		exists, err := fileutils.FileExists(source)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := fileutils.CopyFile(source, filepath.Join(destinationPgData, fileName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceDirectory moves the passed directory to the destination,
+// removing what's already there
+func replaceDirectory(directory, destination string) error {
+	if err := os.RemoveAll(destination); err != nil {
+		return err
+	}
+	return os.Rename(directory, destination)
+}
+
+// ensureWALDirectoryLink makes the pg_wal directory of the
+// passed data directory a link to the passed WAL directory
+func ensureWALDirectoryLink(pgData, pgWal string) error {
+	walLink := filepath.Join(pgData, "pg_wal")
+	if target, err := os.Readlink(walLink); err == nil && target == pgWal {
+		return nil
+	}
+
+	if err := os.Remove(walLink); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(pgWal, walLink)
+}
+
+// RemovePreviousMajorVersionData removes the data and WAL directories of
+// the previous major version, together with its tablespaces, which are
+// kept after an upgrade until the upgraded instance is confirmed to be
+// running. It returns true when they have been removed
+func RemovePreviousMajorVersionData(pgData string) (bool, error) {
+	state, err := readMajorUpgradeState(pgData)
+	if err != nil || state == nil || state.Phase != majorUpgradePhaseCompleted {
+		return false, err
+	}
+
+	previousInfo := InitInfo{PgData: pgData, PgWal: state.PgWal}.getPreviousDataInfo()
+	if err := previousInfo.removeDataDirectories(); err != nil {
+		return false, err
+	}
+	if err := removeTablespacesVersionDirectories(pgData, state.OldMajor); err != nil {
+		return false, err
+	}
+
+	return true, removeMajorUpgradeState(pgData)
+}
+
+// removeTablespacesVersionDirectories removes, from the locations of the
+// tablespaces of the data directory, the directories belonging to the
+// passed major version
+func removeTablespacesVersionDirectories(pgData string, major int) error {
+	tablespacesDirectory := filepath.Join(pgData, "pg_tblspc")
+	links, err := os.ReadDir(tablespacesDirectory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	prefix := fmt.Sprintf("PG_%d_", major)
+	for _, link := range links {
+		location, err := filepath.EvalSymlinks(filepath.Join(tablespacesDirectory, link.Name()))
+		if err != nil {
+			return err
+		}
+
+		entries, err := os.ReadDir(location)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+				if err := os.RemoveAll(filepath.Join(location, entry.Name())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPgControlData gets the parsed output of the pg_controldata binary
+// available in binDir on the passed data directory
+func getPgControlData(binDir, pgData string) (map[string]string, error) {
+	pgControlDataCmd := exec.Command(filepath.Join(binDir, pgControlDataName)) // #nosec G204
+	pgControlDataCmd.Env = append(os.Environ(), "PGDATA="+pgData, "LANG=C", "LC_MESSAGES=C")
+	out, err := pgControlDataCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("while executing pg_controldata: %w", err)
+	}
+
+	return utils.ParsePgControldataOutput(string(out)), nil
+}
+
+// getPostgresMajorVersion gets the major version of the PostgreSQL
+// binaries available in this image
+func getPostgresMajorVersion() (int, error) {
+	out, err := exec.Command(postgresName, "--version").Output() // #nosec G204
+	if err != nil {
+		return 0, fmt.Errorf("while detecting the version of PostgreSQL: %w", err)
+	}
+
+	return parsePostgresMajorVersion(string(out))
+}
+
+// parsePostgresMajorVersion parses the output of "postgres --version",
+// i.e. "postgres (PostgreSQL) 17.2 (Debian 17.2-1.pgdg120+1)",
+// returning the major version
+func parsePostgresMajorVersion(output string) (int, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return 0, fmt.Errorf("unexpected PostgreSQL version: %q", output)
+	}
+
+	version, err := postgres.GetPostgresVersionFromTag(fields[2])
+	if err != nil {
+		return 0, err
+	}
+
+	return version / 10000, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_upgrade options", func() {
+	It("runs pg_upgrade in link mode, disabling the instance manager integrations", func() {
+		options := buildPgUpgradeOptions("/old/bin", "/pgdata", "/pgdata-new", "--check")
+		Expect(options).To(ContainElements(
+			"--link",
+			"--check",
+			"-c ssl=off -c archive_mode=off",
+		))
+		Expect(options).To(ContainElements("--old-bindir", "/old/bin"))
+		Expect(options).To(ContainElements("--old-datadir", "/pgdata"))
+		Expect(options).To(ContainElements("--new-datadir", "/pgdata-new"))
+	})
+
+	It("creates the new data directory with the checksums and WAL size of the old one", func() {
+		controlData := map[string]string{
+			utils.PgControlDataKeyDataPageChecksumVersion: "1",
+			utils.PgControlDataKeyBytesPerWALSegment:      "33554432",
+		}
+		Expect(buildUpgradeInitDBOptions(controlData, 17)).To(Equal([]string{
+			"--data-checksums",
+			"--wal-segsize=32",
+		}))
+	})
+
+	It("disables the data checksums when upgrading to PostgreSQL 18 or newer", func() {
+		controlData := map[string]string{
+			utils.PgControlDataKeyDataPageChecksumVersion: "0",
+		}
+		Expect(buildUpgradeInitDBOptions(controlData, 17)).To(BeEmpty())
+		Expect(buildUpgradeInitDBOptions(controlData, 18)).To(Equal([]string{"--no-data-checksums"}))
+	})
+})
+
+var _ = Describe("parsePostgresMajorVersion", func() {
+	It("parses the output of postgres --version", func() {
+		Expect(parsePostgresMajorVersion("postgres (PostgreSQL) 17.2 (Debian 17.2-1.pgdg120+1)\n")).
+			To(Equal(17))
+		Expect(parsePostgresMajorVersion("postgres (PostgreSQL) 16.4")).To(Equal(16))
+	})
+
+	It("complains about an unexpected output", func() {
+		_, err := parsePostgresMajorVersion("postgres")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("major upgrade of the data directory", func() {
+	var info InitInfo
+	var newInfo InitInfo
+	var upgradedState majorUpgradeState
+
+	BeforeEach(func() {
+		tempDir := GinkgoT().TempDir()
+		info = InitInfo{
+			PgData: filepath.Join(tempDir, "pgdata"),
+			PgWal:  filepath.Join(tempDir, "wal", "pg_wal"),
+		}
+		newInfo = info.getUpgradedDataInfo()
+		upgradedState = majorUpgradeState{
+			Phase:    majorUpgradePhaseUpgraded,
+			OldMajor: 16,
+			NewMajor: 17,
+			PgWal:    info.PgWal,
+		}
+
+		for _, pgInfo := range []InitInfo{info, newInfo} {
+			Expect(os.MkdirAll(filepath.Join(pgInfo.PgData, "global"), 0o700)).To(Succeed())
+			Expect(os.MkdirAll(pgInfo.PgWal, 0o700)).To(Succeed())
+			Expect(os.Symlink(pgInfo.PgWal, filepath.Join(pgInfo.PgData, "pg_wal"))).To(Succeed())
+		}
+		Expect(os.WriteFile(filepath.Join(info.PgData, "PG_VERSION"), []byte("16\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(newInfo.PgData, "PG_VERSION"), []byte("17\n"), 0o600)).To(Succeed())
+	})
+
+	It("creates the upgraded cluster beside the original one", func() {
+		Expect(newInfo.PgData).To(Equal(info.PgData + "-new"))
+		Expect(newInfo.PgWal).To(Equal(info.PgWal + "-new"))
+
+		info.PgWal = ""
+		Expect(info.getUpgradedDataInfo().PgWal).To(BeEmpty())
+	})
+
+	It("restores the original data directory when rolling back", func() {
+		controlFile := filepath.Join(info.PgData, "global", "pg_control")
+		Expect(os.WriteFile(controlFile+".old", []byte("control"), 0o600)).To(Succeed())
+
+		Expect(info.rollbackMajorUpgrade(newInfo)).To(Succeed())
+
+		Expect(os.ReadFile(controlFile)).To(BeEquivalentTo("control"))
+		Expect(newInfo.PgData).ToNot(BeADirectory())
+		Expect(newInfo.PgWal).ToNot(BeADirectory())
+		Expect(info.PgData).To(BeADirectory())
+	})
+
+	It("replaces the original data directory with the upgraded one, keeping the previous one", func() {
+		Expect(os.WriteFile(filepath.Join(info.PgData, "postgresql.auto.conf"),
+			[]byte("work_mem = '8MB'\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(info.PgWal, "000000010000000000000001"),
+			[]byte("wal"), 0o600)).To(Succeed())
+
+		tablespaceLocation := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(tablespaceLocation, "PG_16_202307071"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(tablespaceLocation, "PG_17_202406281"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(newInfo.PgData, "pg_tblspc"), 0o700)).To(Succeed())
+		Expect(os.Symlink(tablespaceLocation, filepath.Join(newInfo.PgData, "pg_tblspc", "16385"))).To(Succeed())
+
+		Expect(info.completeMajorUpgrade(newInfo, upgradedState)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("17\n"))
+		Expect(os.ReadFile(filepath.Join(info.PgData, "postgresql.auto.conf"))).
+			To(BeEquivalentTo("work_mem = '8MB'\n"))
+		Expect(os.Readlink(filepath.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+		Expect(filepath.Join(info.PgWal, "000000010000000000000001")).ToNot(BeAnExistingFile())
+		Expect(newInfo.PgData).ToNot(BeADirectory())
+		Expect(newInfo.PgWal).ToNot(BeADirectory())
+
+		state, err := readMajorUpgradeState(info.PgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state.Phase).To(Equal(majorUpgradePhaseCompleted))
+
+		// The previous major version is kept until the upgraded instance runs
+		Expect(os.ReadFile(filepath.Join(info.PgData+"-old", "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(filepath.Join(info.PgWal+"-old", "000000010000000000000001")).To(BeAnExistingFile())
+		Expect(filepath.Join(tablespaceLocation, "PG_16_202307071")).To(BeADirectory())
+
+		removed, err := RemovePreviousMajorVersionData(info.PgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(BeTrue())
+		Expect(info.PgData + "-old").ToNot(BeADirectory())
+		Expect(info.PgWal + "-old").ToNot(BeADirectory())
+		Expect(filepath.Join(tablespaceLocation, "PG_16_202307071")).ToNot(BeADirectory())
+		Expect(filepath.Join(tablespaceLocation, "PG_17_202406281")).To(BeADirectory())
+		Expect(info.PgData + "-upgrade.json").ToNot(BeAnExistingFile())
+
+		removed, err = RemovePreviousMajorVersionData(info.PgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(BeFalse())
+	})
+
+	It("resumes an interrupted replacement of the data directory", func() {
+		Expect(os.Rename(info.PgData, info.PgData+"-old")).To(Succeed())
+
+		Expect(info.completeMajorUpgrade(newInfo, upgradedState)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("17\n"))
+		Expect(os.ReadFile(filepath.Join(info.PgData+"-old", "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(os.Readlink(filepath.Join(info.PgData, "pg_wal"))).To(Equal(info.PgWal))
+		Expect(newInfo.PgData).ToNot(BeADirectory())
+		Expect(newInfo.PgWal).ToNot(BeADirectory())
+	})
+
+	It("rolls back an upgrade interrupted while replacing the data directory", func() {
+		controlFile := filepath.Join(info.PgData, "global", "pg_control")
+		Expect(os.WriteFile(controlFile+".old", []byte("control"), 0o600)).To(Succeed())
+		Expect(writeMajorUpgradeState(info.PgData, upgradedState)).To(Succeed())
+		Expect(os.Rename(info.PgData, info.PgData+"-old")).To(Succeed())
+		Expect(os.Rename(newInfo.PgData, info.PgData)).To(Succeed())
+
+		Expect(RollbackInterruptedMajorUpgrade(context.Background(), info.PgData)).To(Succeed())
+
+		Expect(os.ReadFile(filepath.Join(info.PgData, "PG_VERSION"))).To(BeEquivalentTo("16\n"))
+		Expect(os.ReadFile(controlFile)).To(BeEquivalentTo("control"))
+		Expect(info.PgData + "-old").ToNot(BeADirectory())
+		Expect(newInfo.PgWal).ToNot(BeADirectory())
+		Expect(info.PgWal).To(BeADirectory())
+		Expect(info.PgData + "-upgrade.json").ToNot(BeAnExistingFile())
+	})
+
+	It("doesn't roll back a completed upgrade", func() {
+		completedState := upgradedState
+		completedState.Phase = majorUpgradePhaseCompleted
+		Expect(writeMajorUpgradeState(info.PgData, completedState)).To(Succeed())
+
+		Expect(RollbackInterruptedMajorUpgrade(context.Background(), info.PgData)).To(Succeed())
+
+		Expect(newInfo.PgData).To(BeADirectory())
+		Expect(info.PgData + "-upgrade.json").To(BeAnExistingFile())
+	})
+})
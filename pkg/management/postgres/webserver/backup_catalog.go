@@ -0,0 +1,112 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+)
+
+// backupCatalogTTL is how long the backup catalog read from the
+// object store is reused, to avoid querying it at every request
+const backupCatalogTTL = 5 * time.Minute
+
+// backupCatalogFetcher reads the backup catalog of a server
+type backupCatalogFetcher func(
+	ctx context.Context,
+	objectStore *apiv1.BarmanObjectStoreConfiguration,
+	serverName string,
+) (*catalog.Catalog, error)
+
+// backupCatalogCache keeps the most recent summary of the backup catalog
+// of the cluster, read from the object store
+type backupCatalogCache struct {
+	mu sync.Mutex
+
+	fetch backupCatalogFetcher
+
+	// The object store the summary has been read from
+	destinationPath string
+	serverName      string
+
+	summary *catalog.Summary
+}
+
+func newBackupCatalogCache() *backupCatalogCache {
+	return &backupCatalogCache{
+		fetch: fetchBackupCatalog,
+	}
+}
+
+// fetchBackupCatalog reads the backup catalog from the object store,
+// using the credentials of the WAL archive
+func fetchBackupCatalog(
+	ctx context.Context,
+	objectStore *apiv1.BarmanObjectStoreConfiguration,
+	serverName string,
+) (*catalog.Catalog, error) {
+	env, err := cache.LoadEnv(cache.WALArchiveKey)
+	if err != nil {
+		return nil, fmt.Errorf("while getting the credentials of the object store: %w", err)
+	}
+
+	return barman.GetBackupList(ctx, objectStore, serverName, env)
+}
+
+// get gets the summary of the backup catalog of the cluster, reading it
+// from the object store only when the cached one has expired, when the
+// object store changed, or when a refresh is requested
+func (c *backupCatalogCache) get(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	refresh bool,
+	now time.Time,
+) (*catalog.Summary, error) {
+	objectStore := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.Name
+	if objectStore.ServerName != "" {
+		serverName = objectStore.ServerName
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !refresh &&
+		c.summary != nil &&
+		c.destinationPath == objectStore.DestinationPath &&
+		c.serverName == serverName &&
+		now.Before(c.summary.RetrievedAt.Add(backupCatalogTTL)) {
+		return c.summary, nil
+	}
+
+	backupCatalog, err := c.fetch(ctx, objectStore, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.destinationPath = objectStore.DestinationPath
+	c.serverName = serverName
+	c.summary = backupCatalog.Summary(serverName, now)
+	return c.summary, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("backup catalog cache", func() {
+	var (
+		catalogCache *backupCatalogCache
+		cluster      *apiv1.Cluster
+		fetches      int
+		fetchErr     error
+		now          time.Time
+	)
+
+	BeforeEach(func() {
+		fetches = 0
+		fetchErr = nil
+		now = time.Now()
+		catalogCache = &backupCatalogCache{
+			fetch: func(
+				_ context.Context,
+				_ *apiv1.BarmanObjectStoreConfiguration,
+				_ string,
+			) (*catalog.Catalog, error) {
+				fetches++
+				if fetchErr != nil {
+					return nil, fetchErr
+				}
+				return catalog.NewCatalog([]catalog.BarmanBackup{{ID: "backup-1"}}), nil
+			},
+		}
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://backups/",
+					},
+				},
+			},
+		}
+	})
+
+	It("reads the catalog from the object store", func(ctx SpecContext) {
+		summary, err := catalogCache.get(ctx, cluster, false, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary.ServerName).To(Equal("cluster-example"))
+		Expect(summary.Backups).To(HaveLen(1))
+		Expect(fetches).To(Equal(1))
+	})
+
+	It("reuses the catalog until it expires", func(ctx SpecContext) {
+		_, err := catalogCache.get(ctx, cluster, false, now)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = catalogCache.get(ctx, cluster, false, now.Add(time.Minute))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetches).To(Equal(1))
+
+		_, err = catalogCache.get(ctx, cluster, false, now.Add(backupCatalogTTL))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetches).To(Equal(2))
+	})
+
+	It("reads the catalog again when requested", func(ctx SpecContext) {
+		_, err := catalogCache.get(ctx, cluster, false, now)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = catalogCache.get(ctx, cluster, true, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetches).To(Equal(2))
+	})
+
+	It("reads the catalog again when the object store changes", func(ctx SpecContext) {
+		_, err := catalogCache.get(ctx, cluster, false, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		cluster.Spec.Backup.BarmanObjectStore.ServerName = "new-name"
+		summary, err := catalogCache.get(ctx, cluster, false, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary.ServerName).To(Equal("new-name"))
+		Expect(fetches).To(Equal(2))
+	})
+
+	It("doesn't cache the failures", func(ctx SpecContext) {
+		fetchErr = errors.New("access denied")
+		_, err := catalogCache.get(ctx, cluster, false, now)
+		Expect(err).To(MatchError(fetchErr))
+
+		fetchErr = nil
+		_, err = catalogCache.get(ctx, cluster, false, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetches).To(Equal(2))
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,6 +40,7 @@ type localWebserverEndpoints struct {
 	typedClient   client.Client
 	instance      *postgres.Instance
 	eventRecorder record.EventRecorder
+	backupCatalog *backupCatalogCache
 }
 
 // NewLocalWebServer returns a webserver that allows connection only from localhost
@@ -56,11 +58,13 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 		typedClient:   typedClient,
 		instance:      instance,
 		eventRecorder: eventRecorder,
+		backupCatalog: newBackupCatalogCache(),
 	}
 
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPgBackupCatalog, endpoints.serveBackupCatalog)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -81,8 +85,8 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	log.Debug("Cached object request received")
 
 	var js []byte
-	switch requestedObject {
-	case cache.ClusterKey:
+	switch {
+	case requestedObject == cache.ClusterKey:
 		response, err := cache.LoadClusterUnsafe()
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
@@ -99,7 +103,7 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-	case cache.WALRestoreKey, cache.WALArchiveKey:
+	case requestedObject == cache.WALRestoreKey, cache.IsWALArchiveKey(requestedObject):
 		response, err := cache.LoadEnv(requestedObject)
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
@@ -126,6 +130,74 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	_, _ = w.Write(js)
 }
 
+// This function serves the summary of the catalog of the base backups
+// stored in the object store. Pass "refresh=true" to ignore the cached one
+func (ws *localWebserverEndpoints) serveBackupCatalog(w http.ResponseWriter, r *http.Request) {
+	var cluster apiv1.Cluster
+
+	ctx := r.Context()
+
+	if err := ws.typedClient.Get(ctx, client.ObjectKey{
+		Namespace: ws.instance.Namespace,
+		Name:      ws.instance.ClusterName,
+	}, &cluster); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while getting cluster: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		http.Error(w, "Barman backup not configured in the cluster", http.StatusConflict)
+		return
+	}
+
+	refresh := r.URL.Query().Get("refresh") == "true"
+	cachedSummary, err := ws.backupCatalog.get(ctx, &cluster, refresh, time.Now())
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while reading the backup catalog: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	// The cached summary is shared between the requests
+	summary := *cachedSummary
+	if isPrimary, err := ws.instance.IsPrimary(); err == nil && isPrimary {
+		summary.LastArchivedWAL = ws.getLastArchivedWAL()
+	}
+
+	js, err := json.Marshal(summary)
+	if err != nil {
+		log.Error(err, "while marshalling the backup catalog")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(js)
+}
+
+// getLastArchivedWAL gets the name of the last archived WAL file,
+// or an empty string if it is not available
+func (ws *localWebserverEndpoints) getLastArchivedWAL() string {
+	db, err := ws.instance.GetSuperUserDB()
+	if err != nil {
+		return ""
+	}
+
+	var lastArchivedWAL string
+	row := db.QueryRow("SELECT COALESCE(last_archived_wal, '') FROM pg_catalog.pg_stat_archiver")
+	if err := row.Scan(&lastArchivedWAL); err != nil {
+		log.Debug("Cannot read the last archived WAL", "error", err)
+		return ""
+	}
+
+	return lastArchivedWAL
+}
+
 // This function schedule a backup
 func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.Request) {
 	var cluster apiv1.Cluster
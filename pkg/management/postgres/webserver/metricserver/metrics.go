@@ -24,6 +24,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
@@ -50,7 +51,12 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 		return nil, fmt.Errorf("while registering Go exporters: %w", err)
 	}
 	serveMux := http.NewServeMux()
-	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		// An invalid metric, like the ones generated by a faulty
+		// custom query, is skipped without failing the whole scrape
+		ErrorLog:      metricsErrorLogger{},
+		ErrorHandling: promhttp.ContinueOnError,
+	}))
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", url.PostgresMetricsPort),
@@ -67,6 +73,14 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 	return metricServer, nil
 }
 
+// metricsErrorLogger logs the errors found while gathering the metrics
+type metricsErrorLogger struct{}
+
+// Println implements the promhttp.Logger interface
+func (metricsErrorLogger) Println(v ...interface{}) {
+	log.Warning("Error while gathering the metrics", "error", fmt.Sprint(v...))
+}
+
 // GetExporter get the exporter used for metrics. If the web statusServer still
 // has not started, the exporter is nil
 func (ms *MetricsServer) GetExporter() *Exporter {
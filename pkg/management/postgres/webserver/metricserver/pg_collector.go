@@ -21,10 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
@@ -46,6 +48,10 @@ type Exporter struct {
 	instance *postgres.Instance
 	Metrics  *metrics
 	queries  *m.QueriesCollector
+
+	// the time of the last sample of the table statistics,
+	// which are collected at most once per interval
+	tableStatisticsSampleTime time.Time
 }
 
 // metrics here are related to the exporter itself, which is instrumented to
@@ -58,7 +64,9 @@ type metrics struct {
 	CollectionDuration           *prometheus.GaugeVec
 	SwitchoverRequired           prometheus.Gauge
 	SyncReplicas                 *prometheus.GaugeVec
+	SyncReplicationRelaxed       prometheus.Gauge
 	ReplicaCluster               prometheus.Gauge
+	PrimaryInstance              prometheus.Gauge
 	PgWALArchiveStatus           *prometheus.GaugeVec
 	PgWALDirectory               *prometheus.GaugeVec
 	PgVersion                    *prometheus.GaugeVec
@@ -68,6 +76,19 @@ type metrics struct {
 	FencingOn                    prometheus.Gauge
 	PgStatWalMetrics             PgStatWalMetrics
 	NodesUsed                    prometheus.Gauge
+	WALArchiveGap                prometheus.Gauge
+	TargetRPOExceeded            prometheus.Gauge
+	FirstRequiredWAL             *prometheus.GaugeVec
+	ReplicationLagBytes          *prometheus.GaugeVec
+	ReplicationLagSeconds        *prometheus.GaugeVec
+	TableSize                    *prometheus.GaugeVec
+	TableBloat                   *prometheus.GaugeVec
+	DatabaseBloat                *prometheus.GaugeVec
+	DiskTotalBytes               *prometheus.GaugeVec
+	DiskUsedBytes                *prometheus.GaugeVec
+	DiskAvailableBytes           *prometheus.GaugeVec
+	DiskGrowthRate               *prometheus.GaugeVec
+	DiskTimeToFull               *prometheus.GaugeVec
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -136,12 +157,25 @@ func newMetrics() *metrics {
 			Name:      "sync_replicas",
 			Help:      "Number of requested synchronous replicas (synchronous_standby_names)",
 		}, []string{"value"}),
+		SyncReplicationRelaxed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "sync_replication_relaxed",
+			Help: "1 if the synchronous replication has been relaxed below minSyncReplicas " +
+				"because of the standbys not being ready, 0 otherwise",
+		}),
 		ReplicaCluster: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
 			Name:      "replica_mode",
 			Help:      "1 if the cluster is in replica mode, 0 otherwise",
 		}),
+		PrimaryInstance: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "primary",
+			Help:      "1 if the instance is the primary of the cluster, 0 if it is a replica",
+		}),
 		PgWALArchiveStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
@@ -197,6 +231,97 @@ func newMetrics() *metrics {
 				"implying the absence of High Availability (HA). Ideally this value " +
 				"should match the number of instances in the cluster.",
 		}),
+		WALArchiveGap: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "wal_archive_gap",
+			Help: "1 if the last check of the WAL archive found a missing WAL file, 0 otherwise. " +
+				"Only reported by the primary instance",
+		}),
+		TargetRPOExceeded: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "target_rpo_exceeded",
+			Help: "1 if the archive lag exceeds the target RPO set in the cluster, 0 otherwise. " +
+				"Only reported by the primary instance",
+		}),
+		FirstRequiredWAL: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "first_required_wal",
+			Help: "The time, as a unix timestamp, when the first WAL file needed to recover " +
+				"from the oldest base backup in the object store was being written. " +
+				"Only reported by the primary instance",
+		}, []string{"cluster", "wal"}),
+		ReplicationLagBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "replication_lag_bytes",
+			Help: "Amount of WAL, in bytes, that each standby of the cluster still has to " +
+				"write, flush or replay (lag) compared to the current WAL position of the primary. " +
+				"Only available on the primary",
+		}, []string{"standby", "lag"}),
+		ReplicationLagSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "replication_lag_seconds",
+			Help: "Time elapsed, in seconds, between flushing recent WAL locally and receiving " +
+				"notification that each standby of the cluster has written, flushed or replayed (lag) it. " +
+				"Only available on the primary",
+		}, []string{"standby", "lag"}),
+		TableSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "table_size_bytes",
+			Help: "Total size in bytes of the largest tables of each database, including " +
+				"their indexes and TOAST data. Only reported when the table statistics are enabled",
+		}, []string{"datname", "schemaname", "relname"}),
+		TableBloat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "table_bloat_bytes",
+			Help: "Estimated bloat in bytes of the largest tables of each database. " +
+				"Only reported when the bloat estimation is enabled",
+		}, []string{"datname", "schemaname", "relname"}),
+		DatabaseBloat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "database_bloat_bytes",
+			Help: "Estimated bloat in bytes of all the tables of each database. " +
+				"Only reported when the bloat estimation is enabled",
+		}, []string{"datname"}),
+		DiskTotalBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disk_total_bytes",
+			Help:      "Size in bytes of each volume of the instance",
+		}, []string{"volume"}),
+		DiskUsedBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disk_used_bytes",
+			Help:      "Space in bytes used on each volume of the instance",
+		}, []string{"volume"}),
+		DiskAvailableBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disk_available_bytes",
+			Help:      "Space in bytes still available on each volume of the instance",
+		}, []string{"volume"}),
+		DiskGrowthRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disk_growth_rate_bytes",
+			Help: "Bytes per second the used space of each volume of the instance grew " +
+				"over the projection window, negative when it is shrinking",
+		}, []string{"volume"}),
+		DiskTimeToFull: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disk_time_to_full_seconds",
+			Help: "Seconds before each volume of the instance is full, when its used space " +
+				"keeps growing at the current rate. Only reported for the growing volumes",
+		}, []string{"volume"}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -266,7 +391,9 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- e.Metrics.SwitchoverRequired.Desc()
 	e.Metrics.CollectionDuration.Describe(ch)
 	e.Metrics.SyncReplicas.Describe(ch)
+	ch <- e.Metrics.SyncReplicationRelaxed.Desc()
 	ch <- e.Metrics.ReplicaCluster.Desc()
+	ch <- e.Metrics.PrimaryInstance.Desc()
 	e.Metrics.PgWALArchiveStatus.Describe(ch)
 	e.Metrics.PgWALDirectory.Describe(ch)
 	e.Metrics.PgVersion.Describe(ch)
@@ -275,6 +402,19 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.LastFailedBackupTimestamp.Describe(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Describe(ch)
 	e.Metrics.NodesUsed.Describe(ch)
+	e.Metrics.WALArchiveGap.Describe(ch)
+	e.Metrics.TargetRPOExceeded.Describe(ch)
+	e.Metrics.FirstRequiredWAL.Describe(ch)
+	e.Metrics.ReplicationLagBytes.Describe(ch)
+	e.Metrics.ReplicationLagSeconds.Describe(ch)
+	e.Metrics.TableSize.Describe(ch)
+	e.Metrics.TableBloat.Describe(ch)
+	e.Metrics.DatabaseBloat.Describe(ch)
+	e.Metrics.DiskTotalBytes.Describe(ch)
+	e.Metrics.DiskUsedBytes.Describe(ch)
+	e.Metrics.DiskAvailableBytes.Describe(ch)
+	e.Metrics.DiskGrowthRate.Describe(ch)
+	e.Metrics.DiskTimeToFull.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -304,7 +444,9 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	ch <- e.Metrics.SwitchoverRequired
 	e.Metrics.CollectionDuration.Collect(ch)
 	e.Metrics.SyncReplicas.Collect(ch)
+	ch <- e.Metrics.SyncReplicationRelaxed
 	ch <- e.Metrics.ReplicaCluster
+	ch <- e.Metrics.PrimaryInstance
 	e.Metrics.PgWALArchiveStatus.Collect(ch)
 	e.Metrics.PgWALDirectory.Collect(ch)
 	e.Metrics.PgVersion.Collect(ch)
@@ -313,6 +455,19 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.LastFailedBackupTimestamp.Collect(ch)
 	e.Metrics.LastAvailableBackupTimestamp.Collect(ch)
 	e.Metrics.NodesUsed.Collect(ch)
+	e.Metrics.WALArchiveGap.Collect(ch)
+	e.Metrics.TargetRPOExceeded.Collect(ch)
+	e.Metrics.FirstRequiredWAL.Collect(ch)
+	e.Metrics.ReplicationLagBytes.Collect(ch)
+	e.Metrics.ReplicationLagSeconds.Collect(ch)
+	e.Metrics.TableSize.Collect(ch)
+	e.Metrics.TableBloat.Collect(ch)
+	e.Metrics.DatabaseBloat.Collect(ch)
+	e.Metrics.DiskTotalBytes.Collect(ch)
+	e.Metrics.DiskUsedBytes.Collect(ch)
+	e.Metrics.DiskAvailableBytes.Collect(ch)
+	e.Metrics.DiskGrowthRate.Collect(ch)
+	e.Metrics.DiskTimeToFull.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -329,12 +484,25 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	e.Metrics.CollectionsTotal.Inc()
 	collectionStart := time.Now()
+
+	// The role of the instance is reported even when PostgreSQL is down
+	// or fenced, as it only depends on the content of PGDATA
+	isPrimary := e.collectPrimaryInstance()
+
+	// The volumes are sampled by the instance manager, even
+	// when PostgreSQL is down
+	e.collectDiskUsage()
+
 	if e.instance.IsFenced() {
 		e.Metrics.FencingOn.Set(1)
 		log.Info("metrics collection skipped due to fencing")
 		return
 	}
-	e.Metrics.FencingOn.Set(0)
+	if e.instance.IsReadOnlyFenced() {
+		e.Metrics.FencingOn.Set(1)
+	} else {
+		e.Metrics.FencingOn.Set(0)
+	}
 
 	if e.instance.MightBeUnavailable() {
 		log.Info("metrics collection skipped due to instance still being down")
@@ -374,13 +542,11 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.CollectionDuration.WithLabelValues(label).Set(time.Since(collectionStart).Seconds())
 	}
 
-	isPrimary, err := e.instance.IsPrimary()
-	if err != nil {
-		log.Error(err, "unable to get if primary")
-	}
-
 	e.collectNodesUsed()
 
+	// getting the size of the largest tables, when requested
+	e.collectTableStatistics(isPrimary)
+
 	// metrics collected only on primary server
 	if isPrimary {
 		// getting required synchronous standby number from postgres itself
@@ -393,6 +559,23 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.collectFromPrimaryLastAvailableBackupTimestamp()
 
 		e.collectFromPrimaryLastFailedBackupTimestamp()
+
+		// getting the outcome of the last check of the WAL archive
+		e.collectFromPrimaryWALArchiveGap()
+
+		// getting whether the archive lag exceeds the target RPO
+		e.collectFromPrimaryTargetRPOExceeded()
+
+		// getting the first WAL file required to recover the cluster
+		e.collectFromPrimaryFirstRequiredWAL()
+
+		// getting the replication lag of each standby
+		e.collectFromPrimaryReplicationLag(db)
+	} else {
+		// the replication lag is reported only by the primary
+		e.Metrics.ReplicationLagBytes.Reset()
+		e.Metrics.ReplicationLagSeconds.Reset()
+		e.Metrics.FirstRequiredWAL.Reset()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -425,6 +608,43 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	}
 }
 
+// collectPrimaryInstance sets the metric reporting the role of this
+// instance, returning true if it is the primary
+func (e *Exporter) collectPrimaryInstance() bool {
+	isPrimary, err := e.instance.IsPrimary()
+	if err != nil {
+		log.Error(err, "unable to get if primary")
+	}
+
+	if isPrimary {
+		e.Metrics.PrimaryInstance.Set(1)
+	} else {
+		e.Metrics.PrimaryInstance.Set(0)
+	}
+
+	return isPrimary
+}
+
+// collectDiskUsage sets the metrics reporting the usage of the volumes
+// of the instance, as sampled by the instance manager
+func (e *Exporter) collectDiskUsage() {
+	e.Metrics.DiskTotalBytes.Reset()
+	e.Metrics.DiskUsedBytes.Reset()
+	e.Metrics.DiskAvailableBytes.Reset()
+	e.Metrics.DiskGrowthRate.Reset()
+	e.Metrics.DiskTimeToFull.Reset()
+
+	for _, usage := range e.instance.GetDiskUsage() {
+		e.Metrics.DiskTotalBytes.WithLabelValues(usage.Volume).Set(float64(usage.TotalBytes))
+		e.Metrics.DiskUsedBytes.WithLabelValues(usage.Volume).Set(float64(usage.UsedBytes))
+		e.Metrics.DiskAvailableBytes.WithLabelValues(usage.Volume).Set(float64(usage.AvailableBytes))
+		e.Metrics.DiskGrowthRate.WithLabelValues(usage.Volume).Set(usage.GrowthRate)
+		if timeToFull, ok := usage.GetTimeToFull(); ok {
+			e.Metrics.DiskTimeToFull.WithLabelValues(usage.Volume).Set(timeToFull.Seconds())
+		}
+	}
+}
+
 func (e *Exporter) setTimestampMetric(
 	gauge prometheus.Gauge,
 	errorLabel string,
@@ -489,6 +709,86 @@ func (e *Exporter) collectNodesUsed() {
 	e.Metrics.NodesUsed.Set(float64(cluster.Status.Topology.NodesUsed))
 }
 
+func (e *Exporter) collectFromPrimaryWALArchiveGap() {
+	cluster, err := cache.LoadClusterUnsafe()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.WALArchiveGap").Inc()
+		e.Metrics.WALArchiveGap.Set(0)
+		return
+	}
+
+	if cluster.Status.WalArchiveCheck != nil && cluster.Status.WalArchiveCheck.MissingWAL != "" {
+		e.Metrics.WALArchiveGap.Set(1)
+		return
+	}
+
+	e.Metrics.WALArchiveGap.Set(0)
+}
+
+func (e *Exporter) collectFromPrimaryTargetRPOExceeded() {
+	cluster, err := cache.LoadClusterUnsafe()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.TargetRPOExceeded").Inc()
+		e.Metrics.TargetRPOExceeded.Set(0)
+		return
+	}
+
+	if meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionTargetRPO)) {
+		e.Metrics.TargetRPOExceeded.Set(1)
+		return
+	}
+
+	e.Metrics.TargetRPOExceeded.Set(0)
+}
+
+func (e *Exporter) collectFromPrimaryFirstRequiredWAL() {
+	// the WAL file is part of the labels, so the previous
+	// one must not be reported anymore
+	e.Metrics.FirstRequiredWAL.Reset()
+
+	cluster, err := cache.LoadClusterUnsafe()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.FirstRequiredWAL").Inc()
+		return
+	}
+
+	if cluster.Status.FirstRequiredWAL == "" {
+		return
+	}
+
+	var timestamp float64
+	if cluster.Status.FirstRequiredWALTime != "" {
+		parsedTS, err := time.Parse(time.RFC3339, cluster.Status.FirstRequiredWALTime)
+		if err != nil {
+			log.Error(err, "while collecting the first required WAL file")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.FirstRequiredWAL").Inc()
+			return
+		}
+		timestamp = float64(parsedTS.Unix())
+	}
+
+	e.Metrics.FirstRequiredWAL.WithLabelValues(cluster.Name, cluster.Status.FirstRequiredWAL).Set(timestamp)
+}
+
 func (e *Exporter) collectFromPrimaryLastFailedBackupTimestamp() {
 	const errorLabel = "Collect.LastFailedBackupTimestamp"
 	e.setTimestampMetric(e.Metrics.LastFailedBackupTimestamp, errorLabel, func(cluster *apiv1.Cluster) string {
@@ -523,6 +823,20 @@ func (e *Exporter) collectFromPrimarySynchronousStandbysNumber(db *sql.DB) {
 	e.Metrics.SyncReplicas.WithLabelValues("observed").Set(float64(nStandbys))
 }
 
+func (e *Exporter) collectFromPrimaryReplicationLag(db *sql.DB) {
+	// standbys that are not connected anymore shouldn't be reported
+	e.Metrics.ReplicationLagBytes.Reset()
+	e.Metrics.ReplicationLagSeconds.Reset()
+
+	if err := collectReplicationLag(e, db); err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.ReplicationLag").Inc()
+		e.Metrics.ReplicationLagBytes.Reset()
+		e.Metrics.ReplicationLagSeconds.Reset()
+	}
+}
+
 func collectPGVersion(e *Exporter) error {
 	semanticVersion, err := e.instance.GetPgVersion()
 	if err != nil {
@@ -540,6 +854,62 @@ func collectPGVersion(e *Exporter) error {
 	return nil
 }
 
+// replicationLagQuery gets the lag of each standby connected to the primary.
+// The lag columns are NULL when the standby is idle and fully caught up.
+const replicationLagQuery = `SELECT application_name,
+	COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), write_lsn), 0),
+	COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), flush_lsn), 0),
+	COALESCE(pg_catalog.pg_wal_lsn_diff(pg_catalog.pg_current_wal_lsn(), replay_lsn), 0),
+	COALESCE(EXTRACT(EPOCH FROM write_lag), 0),
+	COALESCE(EXTRACT(EPOCH FROM flush_lag), 0),
+	COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)
+FROM pg_catalog.pg_stat_replication`
+
+// collectReplicationLag sets the replication lag of the standbys of the
+// cluster, ignoring any other WAL receiver connected to the primary
+func collectReplicationLag(e *Exporter, db *sql.DB) error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(replicationLagQuery)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			standby                                   string
+			writeBytes, flushBytes, replayBytes       float64
+			writeSeconds, flushSeconds, replaySeconds float64
+		)
+		if err := rows.Scan(
+			&standby,
+			&writeBytes, &flushBytes, &replayBytes,
+			&writeSeconds, &flushSeconds, &replaySeconds,
+		); err != nil {
+			return err
+		}
+
+		if !slices.Contains(cluster.Status.InstanceNames, standby) {
+			continue
+		}
+
+		e.Metrics.ReplicationLagBytes.WithLabelValues(standby, "write").Set(writeBytes)
+		e.Metrics.ReplicationLagBytes.WithLabelValues(standby, "flush").Set(flushBytes)
+		e.Metrics.ReplicationLagBytes.WithLabelValues(standby, "replay").Set(replayBytes)
+		e.Metrics.ReplicationLagSeconds.WithLabelValues(standby, "write").Set(writeSeconds)
+		e.Metrics.ReplicationLagSeconds.WithLabelValues(standby, "flush").Set(flushSeconds)
+		e.Metrics.ReplicationLagSeconds.WithLabelValues(standby, "replay").Set(replaySeconds)
+	}
+
+	return rows.Err()
+}
+
 func getSynchronousStandbysNumber(db *sql.DB) (int, error) {
 	var syncReplicasFromConfig string
 	err := db.QueryRow(fmt.Sprintf("SHOW %s", postgresconf.SynchronousStandbyNames)).
@@ -568,6 +938,7 @@ type PgCollector interface {
 
 // SetCustomQueries sets the custom queries from the passed content
 func (e *Exporter) SetCustomQueries(queries *m.QueriesCollector) {
+	queries.InheritCache(e.queries)
 	e.queries = queries
 }
 
@@ -18,6 +18,8 @@ package metricserver
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -235,6 +237,303 @@ var _ = Describe("ensure timestamp metric it's set properly", func() {
 			Expect(pgCollectionErrorMetric).To(BeNil())
 		})
 	})
+
+	Context("collectFromPrimaryWALArchiveGap", func() {
+		const walArchiveGapName = "cnpg_collector_wal_archive_gap"
+
+		getWALArchiveGap := func() float64 {
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(exporter.Metrics.WALArchiveGap)
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			walArchiveGapMetric := getMetric(metrics, walArchiveGapName)
+			Expect(walArchiveGapMetric).ToNot(BeNil())
+			return walArchiveGapMetric.GetMetric()[0].GetGauge().GetValue()
+		}
+
+		It("reports no gap when the archive has never been checked", func() {
+			cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+
+			exporter.collectFromPrimaryWALArchiveGap()
+			Expect(getWALArchiveGap()).To(BeEquivalentTo(0))
+		})
+
+		It("reports the gap found by the last check", func() {
+			cache.Store(cache.ClusterKey, &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					WalArchiveCheck: &apiv1.WalArchiveCheckStatus{
+						LastCheckedWAL: "000000010000000000000002",
+						MissingWAL:     "000000010000000000000003",
+					},
+				},
+			})
+
+			exporter.collectFromPrimaryWALArchiveGap()
+			Expect(getWALArchiveGap()).To(BeEquivalentTo(1))
+		})
+	})
+
+	Context("collectFromPrimaryTargetRPOExceeded", func() {
+		const targetRPOExceededName = "cnpg_collector_target_rpo_exceeded"
+
+		getTargetRPOExceeded := func() float64 {
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(exporter.Metrics.TargetRPOExceeded)
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+
+			targetRPOExceededMetric := getMetric(metrics, targetRPOExceededName)
+			Expect(targetRPOExceededMetric).ToNot(BeNil())
+			return targetRPOExceededMetric.GetMetric()[0].GetGauge().GetValue()
+		}
+
+		It("reports nothing when the target RPO is met", func() {
+			cache.Store(cache.ClusterKey, &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					Conditions: []metav1.Condition{*apiv1.BuildTargetRPOMetCondition(5 * time.Minute)},
+				},
+			})
+
+			exporter.collectFromPrimaryTargetRPOExceeded()
+			Expect(getTargetRPOExceeded()).To(BeEquivalentTo(0))
+		})
+
+		It("reports the target RPO being exceeded", func() {
+			cache.Store(cache.ClusterKey, &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					Conditions: []metav1.Condition{*apiv1.BuildTargetRPOExceededCondition(5 * time.Minute)},
+				},
+			})
+
+			exporter.collectFromPrimaryTargetRPOExceeded()
+			Expect(getTargetRPOExceeded()).To(BeEquivalentTo(1))
+		})
+	})
+
+	Context("collectFromPrimaryFirstRequiredWAL", func() {
+		const firstRequiredWALName = "cnpg_collector_first_required_wal"
+
+		var registry *prometheus.Registry
+
+		BeforeEach(func() {
+			registry = prometheus.NewRegistry()
+			registry.MustRegister(exporter.Metrics.FirstRequiredWAL)
+		})
+
+		It("reports nothing when there are no base backups", func() {
+			cache.Store(cache.ClusterKey, &apiv1.Cluster{})
+
+			exporter.collectFromPrimaryFirstRequiredWAL()
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getMetric(metrics, firstRequiredWALName)).To(BeNil())
+		})
+
+		It("reports the first required WAL file, labelled by cluster", func() {
+			cache.Store(cache.ClusterKey, &apiv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-example",
+				},
+				Status: apiv1.ClusterStatus{
+					FirstRequiredWAL:     "000000010000000000000005",
+					FirstRequiredWALTime: "2023-02-16T22:44:56Z",
+				},
+			})
+
+			exporter.collectFromPrimaryFirstRequiredWAL()
+			metrics, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+			metric := getMetric(metrics, firstRequiredWALName)
+			Expect(metric).ToNot(BeNil())
+			Expect(metric.GetMetric()).To(HaveLen(1))
+
+			labels := make(map[string]string)
+			for _, label := range metric.GetMetric()[0].GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			Expect(labels).To(Equal(map[string]string{
+				"cluster": "cluster-example",
+				"wal":     "000000010000000000000005",
+			}))
+			Expect(metric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(1676587496))
+		})
+	})
+})
+
+var _ = Describe("replication lag metrics", func() {
+	const (
+		lagBytesName          = "cnpg_collector_replication_lag_bytes"
+		lagSecondsName        = "cnpg_collector_replication_lag_seconds"
+		pgCollectionErrorName = "cnpg_collector_collection_errors_total"
+	)
+
+	var (
+		exporter *Exporter
+		registry *prometheus.Registry
+	)
+
+	BeforeEach(func() {
+		cache.Delete(cache.ClusterKey)
+		exporter = NewExporter(postgres.NewInstance())
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.PgCollectionErrors)
+		registry.MustRegister(exporter.Metrics.ReplicationLagBytes)
+		registry.MustRegister(exporter.Metrics.ReplicationLagSeconds)
+	})
+
+	It("reports the lag of each standby of the cluster", func() {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Status: apiv1.ClusterStatus{
+				InstanceNames: []string{"cluster-example-1", "cluster-example-2"},
+			},
+		})
+
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		rows := sqlmock.NewRows([]string{
+			"application_name", "write_bytes", "flush_bytes", "replay_bytes",
+			"write_seconds", "flush_seconds", "replay_seconds",
+		}).
+			AddRow("cluster-example-2", 16, 32, 64, 0.5, 1.5, 2.5).
+			AddRow("pg_receivewal", 1, 1, 1, 1, 1, 1)
+		mock.ExpectQuery(replicationLagQuery).WillReturnRows(rows)
+
+		exporter.collectFromPrimaryReplicationLag(db)
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, pgCollectionErrorName)).To(BeNil())
+
+		lagBytes := getMetric(metrics, lagBytesName).GetMetric()
+		Expect(lagBytes).To(HaveLen(3))
+		lagSeconds := getMetric(metrics, lagSecondsName).GetMetric()
+		Expect(lagSeconds).To(HaveLen(3))
+
+		// labels are sorted by name, so the lag type comes first
+		lagBytesValues := make(map[string]float64)
+		for _, metric := range lagBytes {
+			Expect(metric.GetLabel()[1].GetValue()).To(Equal("cluster-example-2"))
+			lagBytesValues[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+		Expect(lagBytesValues).To(Equal(map[string]float64{"write": 16, "flush": 32, "replay": 64}))
+
+		lagSecondsValues := make(map[string]float64)
+		for _, metric := range lagSeconds {
+			Expect(metric.GetLabel()[1].GetValue()).To(Equal("cluster-example-2"))
+			lagSecondsValues[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+		Expect(lagSecondsValues).To(Equal(map[string]float64{"write": 0.5, "flush": 1.5, "replay": 2.5}))
+	})
+
+	It("reports an error when there's no cluster in the cache", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+
+		exporter.collectFromPrimaryReplicationLag(db)
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, lagBytesName)).To(BeNil())
+		Expect(getMetric(metrics, lagSecondsName)).To(BeNil())
+
+		pgCollectionErrorMetric := getMetric(metrics, pgCollectionErrorName)
+		Expect(pgCollectionErrorMetric).ToNot(BeNil())
+		Expect(pgCollectionErrorMetric.GetMetric()[0].GetLabel()[0].GetValue()).To(Equal("Collect.ReplicationLag"))
+	})
+})
+
+var _ = Describe("primary instance metric", func() {
+	const primaryName = "cnpg_collector_primary"
+
+	var (
+		exporter *Exporter
+		registry *prometheus.Registry
+		pgData   string
+	)
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+		instance := postgres.NewInstance()
+		instance.PgData = pgData
+		exporter = NewExporter(instance)
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.PrimaryInstance)
+	})
+
+	getValue := func() float64 {
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		primaryMetric := getMetric(metrics, primaryName)
+		Expect(primaryMetric).ToNot(BeNil())
+		return primaryMetric.GetMetric()[0].GetGauge().GetValue()
+	}
+
+	It("reports 1 on the primary instance", func() {
+		Expect(exporter.collectPrimaryInstance()).To(BeTrue())
+		Expect(getValue()).To(BeEquivalentTo(1))
+	})
+
+	It("reports 0 on a replica and follows the role transitions", func() {
+		standbySignal := filepath.Join(pgData, "standby.signal")
+		Expect(os.WriteFile(standbySignal, nil, 0o600)).To(Succeed())
+		Expect(exporter.collectPrimaryInstance()).To(BeFalse())
+		Expect(getValue()).To(BeEquivalentTo(0))
+
+		// the replica has been promoted
+		Expect(os.Remove(standbySignal)).To(Succeed())
+		Expect(exporter.collectPrimaryInstance()).To(BeTrue())
+		Expect(getValue()).To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("disk usage metrics", func() {
+	var (
+		exporter *Exporter
+		registry *prometheus.Registry
+		instance *postgres.Instance
+	)
+
+	BeforeEach(func() {
+		instance = postgres.NewInstance()
+		exporter = NewExporter(instance)
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.DiskUsedBytes, exporter.Metrics.DiskTimeToFull)
+	})
+
+	It("reports the usage of each volume, and the time to fill the growing ones", func() {
+		instance.SetDiskUsage([]postgresconf.VolumeUsage{
+			{Volume: "data", TotalBytes: 1000, UsedBytes: 400, AvailableBytes: 600, GrowthRate: 10},
+			{Volume: "wal", TotalBytes: 1000, UsedBytes: 100, AvailableBytes: 900},
+		})
+		exporter.collectDiskUsage()
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		usedMetric := getMetric(metrics, "cnpg_collector_disk_used_bytes")
+		Expect(usedMetric).ToNot(BeNil())
+		Expect(usedMetric.GetMetric()).To(HaveLen(2))
+
+		timeToFullMetric := getMetric(metrics, "cnpg_collector_disk_time_to_full_seconds")
+		Expect(timeToFullMetric).ToNot(BeNil())
+		Expect(timeToFullMetric.GetMetric()).To(HaveLen(1))
+		Expect(timeToFullMetric.GetMetric()[0].GetLabel()[0].GetValue()).To(Equal("data"))
+		Expect(timeToFullMetric.GetMetric()[0].GetGauge().GetValue()).To(BeEquivalentTo(60))
+	})
+
+	It("reports nothing before the first sample", func() {
+		exporter.collectDiskUsage()
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metrics).To(BeEmpty())
+	})
 })
 
 type nameGetter interface {
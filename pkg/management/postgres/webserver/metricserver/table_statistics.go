@@ -0,0 +1,219 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// tableStatisticsDatabasesQuery gets the databases whose tables are
+// reported, excluding the templates
+const tableStatisticsDatabasesQuery = `SELECT datname
+FROM pg_catalog.pg_database
+WHERE datallowconn AND NOT datistemplate`
+
+// tableSizeQuery gets the total size, including the indexes and the
+// TOAST data, of the largest tables of the current database
+const tableSizeQuery = `SELECT n.nspname, c.relname, pg_catalog.pg_total_relation_size(c.oid) AS size_bytes
+FROM pg_catalog.pg_class c
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'm')
+	AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+	AND n.nspname !~ '^pg_toast'
+ORDER BY size_bytes DESC
+LIMIT $1`
+
+// tableBloatQuery gets the total size of the largest tables of the current
+// database, together with their bloat and the one of every table of the
+// database. The bloat is estimated comparing the pages of the table with
+// the ones needed to store its rows, whose width is the sum of the average
+// width of the columns in pg_stats plus the tuple header and line pointer
+const tableBloatQuery = `WITH tables AS (
+	SELECT n.nspname, c.relname, pg_catalog.pg_total_relation_size(c.oid) AS size_bytes,
+		GREATEST(c.relpages - CEIL(GREATEST(c.reltuples, 0) * (28 + COALESCE(s.width, 0)) /
+			(pg_catalog.current_setting('block_size')::numeric - 24)), 0) *
+			pg_catalog.current_setting('block_size')::numeric AS bloat_bytes
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN (
+		SELECT schemaname, tablename, SUM(avg_width) AS width
+		FROM pg_catalog.pg_stats
+		GROUP BY schemaname, tablename
+	) s ON s.schemaname = n.nspname AND s.tablename = c.relname
+	WHERE c.relkind IN ('r', 'm')
+		AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+		AND n.nspname !~ '^pg_toast'
+)
+SELECT nspname, relname, size_bytes, bloat_bytes, SUM(bloat_bytes) OVER ()
+FROM tables
+ORDER BY size_bytes DESC
+LIMIT $1`
+
+// collectTableStatistics samples the size and the estimated bloat of the
+// tables, when requested and at most once per interval. The samples are
+// taken by the replicas, unless the cluster has a single instance
+func (e *Exporter) collectTableStatistics(isPrimary bool) {
+	cluster, err := cache.LoadClusterUnsafe()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	if err != nil {
+		log.Error(err, "unable to collect metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.TableStatistics").Inc()
+		e.resetTableStatistics()
+		return
+	}
+
+	monitoring := cluster.Spec.Monitoring
+	if !monitoring.AreTableStatisticsEnabled() || (isPrimary && cluster.GetInstances() > 1) {
+		e.resetTableStatistics()
+		return
+	}
+
+	if !e.tableStatisticsSampleTime.IsZero() &&
+		time.Since(e.tableStatisticsSampleTime) < monitoring.GetTableStatisticsInterval() {
+		return
+	}
+
+	collectionStart := time.Now()
+	e.resetTableStatistics()
+	if err := sampleTableStatistics(
+		e,
+		monitoring.GetTableStatisticsMaxTables(),
+		monitoring.TableStatistics.EstimateBloat,
+	); err != nil {
+		log.Error(err, "while collecting the table statistics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.TableStatistics").Inc()
+	}
+	// a failed sample is retried only after the interval, to avoid
+	// repeating an expensive collection at every scrape
+	e.tableStatisticsSampleTime = collectionStart
+	e.Metrics.CollectionDuration.WithLabelValues("Collect.TableStatistics").Set(
+		time.Since(collectionStart).Seconds())
+}
+
+// resetTableStatistics removes the table statistics, which will be
+// sampled again as soon as they are requested
+func (e *Exporter) resetTableStatistics() {
+	e.tableStatisticsSampleTime = time.Time{}
+	e.Metrics.TableSize.Reset()
+	e.Metrics.TableBloat.Reset()
+	e.Metrics.DatabaseBloat.Reset()
+}
+
+// sampleTableStatistics sets the table statistics of every database
+func sampleTableStatistics(e *Exporter, maxTables int, estimateBloat bool) error {
+	superUserDB, err := e.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	databases, err := getTableStatisticsDatabases(superUserDB)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, datname := range databases {
+		db, err := e.instance.ConnectionPool().Connection(datname)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := sampleDatabaseTableStatistics(e, db, datname, maxTables, estimateBloat); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func getTableStatisticsDatabases(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(tableStatisticsDatabasesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []string
+	for rows.Next() {
+		var datname string
+		if err := rows.Scan(&datname); err != nil {
+			return nil, err
+		}
+		databases = append(databases, datname)
+	}
+
+	return databases, rows.Err()
+}
+
+// sampleDatabaseTableStatistics sets the statistics of the largest
+// tables of the database the passed connection points to
+func sampleDatabaseTableStatistics(
+	e *Exporter,
+	db *sql.DB,
+	datname string,
+	maxTables int,
+	estimateBloat bool,
+) error {
+	query := tableSizeQuery
+	if estimateBloat {
+		query = tableBloatQuery
+	}
+
+	rows, err := db.Query(query, maxTables)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var (
+			schemaName, tableName                     string
+			sizeBytes, bloatBytes, databaseBloatBytes float64
+		)
+		if !estimateBloat {
+			if err := rows.Scan(&schemaName, &tableName, &sizeBytes); err != nil {
+				return err
+			}
+			e.Metrics.TableSize.WithLabelValues(datname, schemaName, tableName).Set(sizeBytes)
+			continue
+		}
+
+		if err := rows.Scan(&schemaName, &tableName, &sizeBytes, &bloatBytes, &databaseBloatBytes); err != nil {
+			return err
+		}
+		e.Metrics.TableSize.WithLabelValues(datname, schemaName, tableName).Set(sizeBytes)
+		e.Metrics.TableBloat.WithLabelValues(datname, schemaName, tableName).Set(bloatBytes)
+		e.Metrics.DatabaseBloat.WithLabelValues(datname).Set(databaseBloatBytes)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricserver
+
+import (
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("table statistics metrics", func() {
+	const (
+		tableSizeName     = "cnpg_collector_table_size_bytes"
+		tableBloatName    = "cnpg_collector_table_bloat_bytes"
+		databaseBloatName = "cnpg_collector_database_bloat_bytes"
+	)
+
+	var (
+		exporter *Exporter
+		registry *prometheus.Registry
+	)
+
+	BeforeEach(func() {
+		cache.Delete(cache.ClusterKey)
+		exporter = NewExporter(postgres.NewInstance())
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(exporter.Metrics.TableSize)
+		registry.MustRegister(exporter.Metrics.TableBloat)
+		registry.MustRegister(exporter.Metrics.DatabaseBloat)
+	})
+
+	storeCluster := func(instances int, tableStatistics *apiv1.TableStatisticsConfiguration) {
+		cache.Store(cache.ClusterKey, &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Instances: instances,
+				Monitoring: &apiv1.MonitoringConfiguration{
+					TableStatistics: tableStatistics,
+				},
+			},
+		})
+	}
+
+	It("reports the size of the largest tables", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		rows := sqlmock.NewRows([]string{"nspname", "relname", "size_bytes"}).
+			AddRow("public", "orders", 8192000).
+			AddRow("public", "customers", 16384)
+		mock.ExpectQuery(tableSizeQuery).WithArgs(10).WillReturnRows(rows)
+
+		Expect(sampleDatabaseTableStatistics(exporter, db, "app", 10, false)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, tableSizeName).GetMetric()).To(HaveLen(2))
+		Expect(getMetric(metrics, tableBloatName)).To(BeNil())
+		Expect(getMetric(metrics, databaseBloatName)).To(BeNil())
+	})
+
+	It("reports the estimated bloat when requested", func() {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		Expect(err).ToNot(HaveOccurred())
+		rows := sqlmock.NewRows([]string{"nspname", "relname", "size_bytes", "bloat_bytes", "sum"}).
+			AddRow("public", "orders", 8192000, 81920, 98304)
+		mock.ExpectQuery(tableBloatQuery).WithArgs(5).WillReturnRows(rows)
+
+		Expect(sampleDatabaseTableStatistics(exporter, db, "app", 5, true)).To(Succeed())
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		// labels are sorted by name
+		tableBloat := getMetric(metrics, tableBloatName).GetMetric()
+		Expect(tableBloat).To(HaveLen(1))
+		Expect(tableBloat[0].GetLabel()[0].GetValue()).To(Equal("app"))
+		Expect(tableBloat[0].GetLabel()[1].GetValue()).To(Equal("orders"))
+		Expect(tableBloat[0].GetLabel()[2].GetValue()).To(Equal("public"))
+		Expect(tableBloat[0].GetGauge().GetValue()).To(BeEquivalentTo(81920))
+
+		databaseBloat := getMetric(metrics, databaseBloatName).GetMetric()
+		Expect(databaseBloat).To(HaveLen(1))
+		Expect(databaseBloat[0].GetGauge().GetValue()).To(BeEquivalentTo(98304))
+	})
+
+	It("doesn't report anything when not enabled", func() {
+		storeCluster(1, nil)
+		exporter.Metrics.TableSize.WithLabelValues("app", "public", "orders").Set(1)
+
+		exporter.collectTableStatistics(true)
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, tableSizeName)).To(BeNil())
+	})
+
+	It("spares the primary when the cluster has replicas", func() {
+		storeCluster(3, &apiv1.TableStatisticsConfiguration{Enabled: true})
+		exporter.tableStatisticsSampleTime = time.Now()
+		exporter.Metrics.TableSize.WithLabelValues("app", "public", "orders").Set(1)
+
+		exporter.collectTableStatistics(true)
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, tableSizeName)).To(BeNil())
+		Expect(exporter.tableStatisticsSampleTime).To(BeZero())
+	})
+
+	It("keeps the last sample until the interval has elapsed", func() {
+		storeCluster(3, &apiv1.TableStatisticsConfiguration{Enabled: true, Interval: 600})
+		sampleTime := time.Now().Add(-5 * time.Minute)
+		exporter.tableStatisticsSampleTime = sampleTime
+		exporter.Metrics.TableSize.WithLabelValues("app", "public", "orders").Set(1)
+
+		exporter.collectTableStatistics(false)
+
+		metrics, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getMetric(metrics, tableSizeName).GetMetric()).To(HaveLen(1))
+		Expect(exporter.tableStatisticsSampleTime).To(Equal(sampleTime))
+	})
+})
@@ -27,10 +27,12 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
@@ -134,16 +136,58 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, _ *ht
 
 // This is the readiness probe
 func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, _ *http.Request) {
+	// The instances fenced in read-only mode are removed from the
+	// services, even if PostgreSQL is running
+	if ws.instance.IsReadOnlyFenced() {
+		log.Debug("Readiness probe failing, the instance is fenced in read-only mode")
+		http.Error(w, "instance fenced in read-only mode", http.StatusInternalServerError)
+		return
+	}
+
 	if err := ws.instance.IsServerReady(); err != nil {
 		log.Debug("Readiness probe failing", "err", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if err := ws.isReplicationLagAcceptable(); err != nil {
+		log.Debug("Readiness probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	log.Trace("Readiness probe succeeding")
 	_, _ = fmt.Fprint(w, "OK")
 }
 
+// isReplicationLagAcceptable checks the replication lag of the replicas,
+// when requested in the cluster. The current primary, which may be the
+// designated primary of a replica cluster, is never checked, while the
+// delayed replicas, which lag behind by design, are only required to
+// be streaming from their source.
+func (ws *remoteWebserverEndpoints) isReplicationLagAcceptable() error {
+	cluster, err := cache.LoadClusterUnsafe()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		// we don't know the cluster configuration yet
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	configuration := cluster.Spec.ReplicaReadiness
+	if configuration == nil || cluster.Status.CurrentPrimary == ws.instance.PodName {
+		return nil
+	}
+
+	maximumLag := time.Duration(configuration.MaximumLag) * time.Second
+	if cluster.Spec.PostgresConfiguration.DelayedReplicas.IsDelayedReplica(ws.instance.PodName) {
+		maximumLag = postgres.UnlimitedReplicationLag
+	}
+
+	return ws.instance.IsReplicationLagAcceptable(maximumLag)
+}
+
 // This probe is for the instance status, including replication
 func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, _ *http.Request) {
 	// Extract the status of the current instance
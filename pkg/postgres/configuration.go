@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
@@ -72,6 +73,15 @@ const (
 	// hbaTemplateString is the template used to generate the pg_hba.conf
 	// configuration file
 	hbaTemplateString = `
+{{- if .PrependedUserRules }}
+#
+# PREPENDED USER-DEFINED RULES
+#
+
+{{ range $rule := .PrependedUserRules }}
+{{ $rule -}}
+{{ end }}
+{{ end }}
 #
 # FIXED RULES
 #
@@ -83,7 +93,15 @@ local all all peer map=local
 hostssl postgres streaming_replica all cert
 hostssl replication streaming_replica all cert
 hostssl all cnpg_pooler_pgbouncer all cert
+{{ if .CertificateRules }}
+#
+# CLIENT CERTIFICATE AUTHENTICATION
+#
 
+{{ range $rule := .CertificateRules }}
+{{ $rule -}}
+{{ end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -114,7 +132,15 @@ host all all all {{.DefaultAuthenticationMethod}}
 
 # Grant local access ('local' user map)
 local {{.Username}} postgres
+{{ if .CertificateMappings }}
+#
+# CLIENT CERTIFICATE AUTHENTICATION
+#
 
+{{ range $rule := .CertificateMappings }}
+{{ $rule -}}
+{{ end }}
+{{ end }}
 #
 # USER-DEFINED RULES
 #
@@ -137,12 +163,21 @@ local {{.Username}} postgres
 	// ScratchDataDirectory is the directory to be used for scratch data
 	ScratchDataDirectory = "/controller"
 
+	// PgRewindMissingWALFileEnv is the environment variable set for
+	// pg_rewind, and inherited by the restore_command it runs, with the
+	// file where the restore_command records the WAL files it can't find
+	PgRewindMissingWALFileEnv = "CNPG_PG_REWIND_MISSING_WAL_FILE"
+
 	// CertificatesDir location to store the certificates
 	CertificatesDir = ScratchDataDirectory + "/certificates/"
 
 	// ProjectedVolumeDirectory is the base directory to store ProjectedVolumeSource
 	ProjectedVolumeDirectory = "/projected"
 
+	// WALRestoreCacheDirectory is where the WAL restore cache shared by
+	// the instances is mounted
+	WALRestoreCacheDirectory = "/var/lib/postgresql/wal-restore-cache"
+
 	// ServerCertificateLocation is the location where the server certificate
 	// is stored
 	ServerCertificateLocation = CertificatesDir + "server.crt"
@@ -196,6 +231,14 @@ local {{.Username}} postgres
 	// needed in the recovery process
 	RecoveryTemporaryDirectory = ScratchDataDirectory + "/recovery"
 
+	// UpgradeOldBinariesDirectory is where the binaries of the previous
+	// PostgreSQL major version are copied during a major upgrade
+	UpgradeOldBinariesDirectory = ScratchDataDirectory + "/old-binaries"
+
+	// UpgradeTemporaryDirectory is the working directory of pg_upgrade,
+	// where its sockets and logs are stored
+	UpgradeTemporaryDirectory = ScratchDataDirectory + "/upgrade"
+
 	// SocketDirectory provides a path to store the Unix socket to be
 	// used by the PostgreSQL server
 	SocketDirectory = ScratchDataDirectory + "/run"
@@ -309,6 +352,40 @@ type ConfigurationInfo struct {
 
 	// IsWalArchivingDisabled is true when user requested to disable WAL archiving
 	IsWalArchivingDisabled bool
+
+	// IsUnsafeDurabilityAllowed is true when the user acknowledged the risk
+	// of data loss deriving from changing the parameters listed in
+	// UnsafeDurabilityParameters
+	IsUnsafeDurabilityAllowed bool
+
+	// LogDestination is the format of the log written by PostgreSQL,
+	// replacing the default one if not empty
+	LogDestination string
+
+	// RecoveryMinApplyDelay is the value of recovery_min_apply_delay
+	// for a delayed replica, and is empty for the other instances
+	RecoveryMinApplyDelay string
+
+	// DeclaredExtensions is the list of the managed extensions the
+	// user declared in the cluster specification
+	DeclaredExtensions []string
+
+	// ManagedExtensions is the list of the extensions managed by the
+	// operator, including the ones declared with a recipe in the cluster
+	// specification. When empty, the ones supported by the operator are used
+	ManagedExtensions []ManagedExtension
+}
+
+// overridesUnsafeDurabilityParameter checks whether the user is allowed
+// to override the value of the passed parameter, which is one of the
+// UnsafeDurabilityParameters, and has done it
+func (info ConfigurationInfo) overridesUnsafeDurabilityParameter(key string) bool {
+	if !info.IsUnsafeDurabilityAllowed || !slices.Contains(UnsafeDurabilityParameters, key) {
+		return false
+	}
+
+	_, isSetByUser := info.UserSettings[key]
+	return isSetByUser
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -324,6 +401,10 @@ type ManagedExtension struct {
 
 	// SkipCreateExtension is true when the extension is made only from a shared preload library
 	SkipCreateExtension bool
+
+	// DefaultParameters contains the configuration applied when the
+	// extension is declared, unless the user sets a different value
+	DefaultParameters map[string]string
 }
 
 // IsUsed checks whether a configuration namespace in the namespaces list
@@ -339,24 +420,57 @@ func (e ManagedExtension) IsUsed(userConfigs map[string]string) bool {
 	return false
 }
 
+// IsEnabled checks whether the extension has been declared by the user
+// or is used in the user provided configuration
+func (e ManagedExtension) IsEnabled(declaredExtensions []string, userConfigs map[string]string) bool {
+	return slices.Contains(declaredExtensions, e.Name) || e.IsUsed(userConfigs)
+}
+
+// GetManagedExtension gets the managed extension with the passed name,
+// returning false if the operator doesn't support it
+func GetManagedExtension(name string) (ManagedExtension, bool) {
+	for _, extension := range ManagedExtensions {
+		if extension.Name == name {
+			return extension, true
+		}
+	}
+	return ManagedExtension{}, false
+}
+
 var (
+	// UnsafeDurabilityParameters contains the parameters that, when disabled,
+	// make PostgreSQL faster at the cost of risking an unrecoverable data
+	// corruption in case of a crash
+	UnsafeDurabilityParameters = []string{"fsync", "full_page_writes"}
+
 	// ManagedExtensions contains the list of extensions the operator supports to manage
 	ManagedExtensions = []ManagedExtension{
 		{
 			Name:                   "pgaudit",
 			Namespaces:             []string{"pgaudit"},
 			SharedPreloadLibraries: []string{"pgaudit"},
+			DefaultParameters: map[string]string{
+				"pgaudit.log":         "ddl, role",
+				"pgaudit.log_catalog": "off",
+			},
 		},
 		{
 			Name:                   "pg_stat_statements",
 			Namespaces:             []string{"pg_stat_statements"},
 			SharedPreloadLibraries: []string{"pg_stat_statements"},
+			DefaultParameters: map[string]string{
+				"pg_stat_statements.max":   "10000",
+				"pg_stat_statements.track": "top",
+			},
 		},
 		{
 			Name:                   "auto_explain",
 			SkipCreateExtension:    true,
 			Namespaces:             []string{"auto_explain"},
 			SharedPreloadLibraries: []string{"auto_explain"},
+			DefaultParameters: map[string]string{
+				"auto_explain.log_min_duration": "10s",
+			},
 		},
 		{
 			Name:                   "pg_failover_slots",
@@ -499,17 +613,23 @@ var (
 )
 
 // CreateHBARules will create the content of pg_hba.conf file given
-// the rules set by the cluster spec
-func CreateHBARules(hba []string,
+// the rules set by the cluster spec. The prepended rules are placed
+// before the fixed ones, while the rules authenticating the clients
+// with their certificates are placed after them
+func CreateHBARules(hba, prependedHBA, certificateRules []string,
 	defaultAuthenticationMethod, ldapConfigString string,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
 	templateData := struct {
+		PrependedUserRules          []string
+		CertificateRules            []string
 		UserRules                   []string
 		LDAPConfiguration           string
 		DefaultAuthenticationMethod string
 	}{
+		PrependedUserRules:          prependedHBA,
+		CertificateRules:            certificateRules,
 		UserRules:                   hba,
 		LDAPConfiguration:           ldapConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
@@ -523,16 +643,19 @@ func CreateHBARules(hba []string,
 }
 
 // CreateIdentRules will create the content of pg_ident.conf file given
-// the rules set by the cluster spec
-func CreateIdentRules(ident []string, username string) (string, error) {
+// the rules set by the cluster spec and the mappings of the client
+// certificates
+func CreateIdentRules(ident, certificateMappings []string, username string) (string, error) {
 	var identContent bytes.Buffer
 
 	templateData := struct {
-		Mappings []string
-		Username string
+		CertificateMappings []string
+		Mappings            []string
+		Username            string
 	}{
-		Mappings: ident,
-		Username: username,
+		CertificateMappings: certificateMappings,
+		Mappings:            ident,
+		Username:            username,
 	}
 
 	if err := identTemplate.Execute(&identContent, templateData); err != nil {
@@ -610,7 +733,7 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 	// ignoring those which are fixed if ignoreFixedSettingsFromUser is true
 	for key, value := range info.UserSettings {
 		_, isFixed := FixedConfigurationParameters[key]
-		if isFixed && ignoreFixedSettingsFromUser {
+		if isFixed && ignoreFixedSettingsFromUser && !info.overridesUnsafeDurabilityParameter(key) {
 			continue
 		}
 		configuration.OverwriteConfig(key, value)
@@ -619,6 +742,9 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 	// Apply all mandatory settings, on top of defaults and user settings
 	if info.IncludingMandatory {
 		for key, value := range info.Settings.MandatorySettings {
+			if info.overridesUnsafeDurabilityParameter(key) {
+				continue
+			}
 			configuration.OverwriteConfig(key, value)
 		}
 	}
@@ -651,9 +777,38 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig("temp_tablespaces", strings.Join(info.TemporaryTablespaces, ","))
 	}
 
+	// Apply the requested log format
+	if info.LogDestination != "" {
+		configuration.OverwriteConfig("log_destination", info.LogDestination)
+	}
+
+	// Delay the application of the changes, for the delayed replicas
+	if info.RecoveryMinApplyDelay != "" {
+		configuration.OverwriteConfig("recovery_min_apply_delay", info.RecoveryMinApplyDelay)
+	}
+
 	return configuration
 }
 
+// GetDisabledUnsafeDurabilityParameters returns the list of the
+// UnsafeDurabilityParameters which have been disabled in the passed
+// configuration parameters
+func GetDisabledUnsafeDurabilityParameters(parameters map[string]string) []string {
+	var result []string
+	for _, key := range UnsafeDurabilityParameters {
+		value, ok := parameters[key]
+		if !ok {
+			continue
+		}
+
+		if enabled, err := ParsePostgresConfigBoolean(value); err == nil && !enabled {
+			result = append(result, key)
+		}
+	}
+
+	return result
+}
+
 // setDefaultConfigurations sets all default configurations into the configuration map
 // from the provided info
 func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfiguration) {
@@ -676,8 +831,13 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 
 // setManagedSharedPreloadLibraries sets all additional preloaded libraries
 func setManagedSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConfiguration) {
-	for _, extension := range ManagedExtensions {
-		if extension.IsUsed(info.UserSettings) {
+	managedExtensions := info.ManagedExtensions
+	if len(managedExtensions) == 0 {
+		managedExtensions = ManagedExtensions
+	}
+
+	for _, extension := range managedExtensions {
+		if extension.IsEnabled(info.DeclaredExtensions, info.UserSettings) {
 			for _, library := range extension.SharedPreloadLibraries {
 				configuration.AddSharedPreloadLibrary(library)
 			}
@@ -17,6 +17,7 @@ limitations under the License.
 package postgres
 
 import (
+	"slices"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -225,6 +226,61 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			Expect(config.GetConfig("recovery_target_name")).To(Equal(""))
 		})
 	})
+
+	It("allows disabling the durability settings only when requested", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 100000,
+			UserSettings: map[string]string{
+				"fsync":            "off",
+				"full_page_writes": "off",
+			},
+			IncludingMandatory: true,
+		}
+		By("enforcing full_page_writes by default", func() {
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("full_page_writes")).To(Equal("on"))
+		})
+
+		By("using the user settings when the risk has been acknowledged", func() {
+			info.IsUnsafeDurabilityAllowed = true
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("fsync")).To(Equal("off"))
+			Expect(config.GetConfig("full_page_writes")).To(Equal("off"))
+		})
+
+		By("keeping the mandatory value when the user didn't change it", func() {
+			delete(info.UserSettings, "full_page_writes")
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("full_page_writes")).To(Equal("on"))
+		})
+	})
+
+	It("detects the disabled durability settings", func() {
+		Expect(GetDisabledUnsafeDurabilityParameters(map[string]string{
+			"fsync":            "on",
+			"full_page_writes": "false",
+			"shared_buffers":   "off",
+		})).To(Equal([]string{"full_page_writes"}))
+		Expect(GetDisabledUnsafeDurabilityParameters(map[string]string{
+			"fsync": "0",
+		})).To(Equal([]string{"fsync"}))
+		Expect(GetDisabledUnsafeDurabilityParameters(nil)).To(BeEmpty())
+	})
+
+	It("applies the requested log format", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       150000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("log_destination")).To(Equal("csvlog"))
+
+		info.LogDestination = "jsonlog"
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("log_destination")).To(Equal("jsonlog"))
+	})
 })
 
 var _ = Describe("pg_hba.conf generation", func() {
@@ -235,17 +291,39 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, nil, nil, "md5", "")).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, nil, nil, "this-one", "")).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
+	It("places the prepended rules before the fixed ones", func() {
+		rules, err := CreateHBARules(specRules, []string{"zero"}, nil, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(MatchRegexp(`(?s)\nzero\n.*FIXED RULES.*\none\n`))
+	})
+
+	It("doesn't add the prepended rules section when there are no such rules", func() {
+		Expect(CreateHBARules(specRules, nil, nil, "md5", "")).ToNot(
+			ContainSubstring("PREPENDED"))
+	})
+
+	It("places the client certificate rules between the fixed and the user-defined ones", func() {
+		rules, err := CreateHBARules(specRules, nil, []string{"certificate"}, "md5", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(MatchRegexp(`(?s)FIXED RULES.*CLIENT CERTIFICATE AUTHENTICATION.*\ncertificate\n.*\none\n`))
+	})
+
+	It("doesn't add the client certificate section when there are no such rules", func() {
+		Expect(CreateHBARules(specRules, nil, nil, "md5", "")).ToNot(
+			ContainSubstring("CLIENT CERTIFICATE"))
+	})
+
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, nil, nil, "defaultAuthenticationMethod", "ldapConfigString")).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
 })
@@ -256,15 +334,21 @@ var _ = Describe("pg_ident.conf generation", func() {
 	}
 
 	It("contains the default map when no mappings are added", func() {
-		Expect(CreateIdentRules(make([]string, 0), "someone")).To(
+		Expect(CreateIdentRules(make([]string, 0), nil, "someone")).To(
 			ContainSubstring("\nlocal someone postgres\n"))
 	})
 
 	It("contains the default map and additional mappings when added", func() {
-		rules, _ := CreateIdentRules(specRules, "someone")
+		rules, _ := CreateIdentRules(specRules, nil, "someone")
 		Expect(rules).To(ContainSubstring("\nlocal someone postgres\n"))
 		Expect(rules).To(ContainSubstring("\ntest someone else\n"))
 	})
+
+	It("contains the mappings of the client certificates", func() {
+		rules, err := CreateIdentRules(specRules, []string{"certificate mapping"}, "someone")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rules).To(MatchRegexp(`(?s)CLIENT CERTIFICATE AUTHENTICATION.*\ncertificate mapping\n.*\ntest someone else\n`))
+	})
 })
 
 var _ = Describe("pgaudit", func() {
@@ -364,3 +448,41 @@ var _ = Describe("pg_failover_slots", func() {
 		Expect(libraries).To(ContainElements("pg_failover_slots"))
 	})
 })
+
+var _ = Describe("declared managed extensions", func() {
+	It("is enabled when declared, even without any parameter", func() {
+		pgStatStatements, found := GetManagedExtension("pg_stat_statements")
+		Expect(found).To(BeTrue())
+		Expect(pgStatStatements.IsEnabled([]string{"pg_stat_statements"}, nil)).To(BeTrue())
+		Expect(pgStatStatements.IsEnabled([]string{"pgaudit"}, nil)).To(BeFalse())
+		Expect(pgStatStatements.IsEnabled(nil, map[string]string{"pg_stat_statements.max": "100"})).To(BeTrue())
+	})
+
+	It("adds the libraries of the declared extensions to shared_preload_library", func() {
+		info := ConfigurationInfo{
+			Settings:                        CnpgConfigurationSettings,
+			MajorVersion:                    130000,
+			DeclaredExtensions:              []string{"pg_failover_slots"},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_failover_slots"))
+	})
+
+	It("uses the managed extensions passed in the configuration info", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 130000,
+			ManagedExtensions: append(slices.Clone(ManagedExtensions), ManagedExtension{
+				Name:                   "pg_cron",
+				SharedPreloadLibraries: []string{"pg_cron"},
+			}),
+			DeclaredExtensions:              []string{"pg_cron"},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_cron"))
+	})
+})
@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"cmp"
+	"slices"
+)
+
+// FailoverCandidate is a replica that could be promoted on failover,
+// together with the data used to rank it
+type FailoverCandidate struct {
+	// The status of the replica
+	Status PostgresqlStatus `json:"status"`
+
+	// The failover priority of the replica
+	Priority int32 `json:"priority"`
+
+	// The amount of WAL, in bytes, by which the replica is behind the
+	// most advanced one, or -1 if it is not known
+	LagBytes int64 `json:"lagBytes"`
+
+	// True if the replica is within the LSN tolerance from the most
+	// advanced one, and can be preferred for its priority
+	WithinLSNTolerance bool `json:"withinLSNTolerance"`
+
+	// True if the replica can be promoted automatically, that is if it
+	// is reporting its status and it is neither delayed nor fenced
+	Eligible bool `json:"eligible"`
+}
+
+// RankFailoverCandidates ranks the replicas in the order in which they
+// would be promoted on failover. The list must be sorted. The most
+// advanced replica comes first, unless a replica with a higher failover
+// priority has received WAL within the LSN tolerance from it, and the
+// replicas which can't be promoted automatically come last
+func (list PostgresqlStatusList) RankFailoverCandidates(
+	getPriority func(instanceName string) int32,
+	lsnTolerance int64,
+) []FailoverCandidate {
+	candidates := make([]FailoverCandidate, 0, len(list.Items))
+	var mostAdvancedLSN int64
+	hasMostAdvancedLSN := false
+	withinLSNTolerance := true
+	for _, item := range list.Items {
+		if item.IsPrimary || item.Pod == nil {
+			continue
+		}
+
+		candidate := FailoverCandidate{
+			Status:   item,
+			Priority: getPriority(item.Pod.Name),
+			LagBytes: -1,
+			Eligible: item.IsElectable() && item.HasHTTPStatus(),
+		}
+
+		receivedLSN, err := item.ReceivedLsn.Parse()
+		if candidate.Eligible && err == nil {
+			if !hasMostAdvancedLSN {
+				mostAdvancedLSN = receivedLSN
+				hasMostAdvancedLSN = true
+			}
+			candidate.LagBytes = mostAdvancedLSN - receivedLSN
+		}
+
+		// The replicas are sorted by received LSN, and the delayed or
+		// fenced ones and the ones not reporting their status come last
+		if candidate.LagBytes < 0 || candidate.LagBytes > lsnTolerance {
+			withinLSNTolerance = false
+		}
+		candidate.WithinLSNTolerance = withinLSNTolerance
+
+		candidates = append(candidates, candidate)
+	}
+
+	preferable := 0
+	for preferable < len(candidates) && candidates[preferable].WithinLSNTolerance {
+		preferable++
+	}
+	slices.SortStableFunc(candidates[:preferable], func(a, b FailoverCandidate) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+
+	return candidates
+}
+
+// SelectFailoverCandidate gets the instance to be promoted from the
+// sorted list, which is the first one ranked by RankFailoverCandidates.
+// The primary is returned if it is still working
+func (list PostgresqlStatusList) SelectFailoverCandidate(
+	getPriority func(instanceName string) int32,
+	lsnTolerance int64,
+) PostgresqlStatus {
+	if list.Items[0].IsPrimary {
+		return list.Items[0]
+	}
+
+	candidates := list.RankFailoverCandidates(getPriority, lsnTolerance)
+	if len(candidates) == 0 {
+		return list.Items[0]
+	}
+
+	return candidates[0].Status
+}
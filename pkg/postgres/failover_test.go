@@ -0,0 +1,119 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("failover candidates ranking", func() {
+	var (
+		list       PostgresqlStatusList
+		priorities map[string]int32
+	)
+
+	getPriority := func(instanceName string) int32 {
+		return priorities[instanceName]
+	}
+
+	instance := func(name string, receivedLSN LSN) PostgresqlStatus {
+		return PostgresqlStatus{
+			Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			ReceivedLsn: receivedLSN,
+		}
+	}
+
+	names := func(candidates []FailoverCandidate) []string {
+		result := make([]string, len(candidates))
+		for idx, candidate := range candidates {
+			result[idx] = candidate.Status.Pod.Name
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		priorities = map[string]int32{
+			"cluster-example-3": 10,
+			"cluster-example-4": 20,
+		}
+		primary := instance("cluster-example-1", "")
+		primary.IsPrimary = true
+		failed := instance("cluster-example-5", "")
+		failed.Error = fmt.Errorf("connection refused")
+		list = PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				primary,
+				instance("cluster-example-2", "0/5000000"),
+				instance("cluster-example-3", "0/4800000"),
+				instance("cluster-example-4", "0/3000000"),
+				failed,
+			},
+		}
+	})
+
+	It("ranks the replicas by priority within the LSN tolerance", func() {
+		candidates := list.RankFailoverCandidates(getPriority, 16*1024*1024)
+		Expect(names(candidates)).To(Equal([]string{
+			"cluster-example-3", "cluster-example-2", "cluster-example-4", "cluster-example-5",
+		}))
+
+		Expect(candidates[0].LagBytes).To(BeEquivalentTo(0x800000))
+		Expect(candidates[0].WithinLSNTolerance).To(BeTrue())
+		Expect(candidates[1].LagBytes).To(BeZero())
+		Expect(candidates[2].LagBytes).To(BeEquivalentTo(0x2000000))
+		Expect(candidates[2].WithinLSNTolerance).To(BeFalse())
+		Expect(candidates[3].Eligible).To(BeFalse())
+		Expect(candidates[3].LagBytes).To(BeEquivalentTo(-1))
+	})
+
+	It("keeps the replication order without priorities", func() {
+		priorities = nil
+		candidates := list.RankFailoverCandidates(getPriority, 64*1024*1024)
+		Expect(names(candidates)).To(Equal([]string{
+			"cluster-example-2", "cluster-example-3", "cluster-example-4", "cluster-example-5",
+		}))
+	})
+
+	It("never prefers a delayed replica", func() {
+		list.Items[2].IsDelayedReplica = true
+		candidates := list.RankFailoverCandidates(getPriority, 64*1024*1024)
+		Expect(names(candidates)[0]).To(Equal("cluster-example-2"))
+		Expect(candidates[1].Eligible).To(BeFalse())
+	})
+
+	It("never prefers a fenced replica", func() {
+		list.Items[2].FencingMode = utils.FencingModeReadOnly
+		candidates := list.RankFailoverCandidates(getPriority, 64*1024*1024)
+		Expect(names(candidates)[0]).To(Equal("cluster-example-2"))
+		Expect(candidates[1].Eligible).To(BeFalse())
+	})
+
+	It("selects the primary while it is working", func() {
+		Expect(list.SelectFailoverCandidate(getPriority, 64*1024*1024).Pod.Name).To(Equal("cluster-example-1"))
+
+		list.Items = list.Items[1:]
+		Expect(list.SelectFailoverCandidate(getPriority, 64*1024*1024).Pod.Name).To(Equal("cluster-example-4"))
+	})
+})
@@ -0,0 +1,257 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// hbaAuthenticationMethods is the list of the authentication methods supported
+// by PostgreSQL.
+// See: https://www.postgresql.org/docs/current/auth-methods.html
+var hbaAuthenticationMethods = []string{
+	"trust", "reject", "scram-sha-256", "md5", "password", "oauth", "gss",
+	"sspi", "ident", "peer", "ldap", "radius", "cert", "pam", "bsd",
+}
+
+// hbaFixedRule is a connection which is authenticated
+// by the fixed rules of the operator
+type hbaFixedRule struct {
+	// The connection types matching the connection
+	connectionTypes []string
+
+	// The databases of the connection
+	databases []string
+
+	// The user of the connection
+	user string
+}
+
+// hbaFixedRules are the connections authenticated by the fixed rules
+// of the pg_hba.conf file, which the operator relies on
+var hbaFixedRules = []hbaFixedRule{
+	{
+		connectionTypes: []string{"local"},
+		databases:       []string{"postgres", "template1"},
+		user:            "postgres",
+	},
+	{
+		connectionTypes: []string{"host", "hostssl"},
+		databases:       []string{"postgres", "replication"},
+		user:            "streaming_replica",
+	},
+	{
+		connectionTypes: []string{"host", "hostssl"},
+		databases:       []string{"postgres"},
+		user:            "cnpg_pooler_pgbouncer",
+	},
+}
+
+// hbaHostConnectionTypes is the list of the pg_hba.conf connection types
+// that require an address field
+var hbaHostConnectionTypes = []string{
+	"host", "hostssl", "hostnossl", "hostgssenc", "hostnogssenc",
+}
+
+// hbaIncludeDirectives is the list of the directives including
+// other files in pg_hba.conf (PostgreSQL 16+)
+var hbaIncludeDirectives = []string{
+	"include", "include_if_exists", "include_dir",
+}
+
+// ValidateHBARule checks the syntax of a line of the pg_hba.conf file.
+// The check is not meant to be exhaustive: it detects the errors that would
+// prevent PostgreSQL from loading the file, such as unknown connection types,
+// missing fields, invalid addresses and unknown authentication methods.
+// Empty lines and comments are accepted.
+// See: https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+func ValidateHBARule(rule string) error {
+	fields, err := splitHBARule(rule)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	connectionType := fields[0]
+	var methodIndex int
+	switch {
+	case slices.Contains(hbaIncludeDirectives, connectionType):
+		// include file
+		if len(fields) != 2 {
+			return fmt.Errorf("%q requires exactly one file name", connectionType)
+		}
+		return nil
+
+	case connectionType == "local":
+		// local database user auth-method [auth-options]
+		methodIndex = 3
+
+	case slices.Contains(hbaHostConnectionTypes, connectionType):
+		// host database user address auth-method [auth-options]
+		// host database user IP-address IP-mask auth-method [auth-options]
+		methodIndex = 4
+		if len(fields) > 3 {
+			if err := validateHBAAddress(fields[3]); err != nil {
+				return err
+			}
+			if net.ParseIP(fields[3]) != nil {
+				if len(fields) < 5 || net.ParseIP(fields[4]) == nil {
+					return fmt.Errorf("missing IP mask after IP address %q", fields[3])
+				}
+				methodIndex = 5
+			}
+		}
+
+	default:
+		return fmt.Errorf("invalid connection type %q", connectionType)
+	}
+
+	if len(fields) <= methodIndex {
+		return fmt.Errorf("missing fields in %q rule: expected at least %d, found %d",
+			connectionType, methodIndex+1, len(fields))
+	}
+
+	if method := fields[methodIndex]; !slices.Contains(hbaAuthenticationMethods, method) {
+		return fmt.Errorf("invalid authentication method %q", method)
+	}
+
+	return nil
+}
+
+// HBARuleShadowsFixedRules checks if a rule placed before the fixed rules of
+// the pg_hba.conf file matches a connection the operator authenticates
+// through them, like the streaming replication ones. Group names and
+// included files can't be resolved, and are considered to be matching.
+func HBARuleShadowsFixedRules(rule string) bool {
+	fields, err := splitHBARule(rule)
+	if err != nil || len(fields) < 3 {
+		return false
+	}
+
+	for _, fixedRule := range hbaFixedRules {
+		if !slices.Contains(fixedRule.connectionTypes, fields[0]) {
+			continue
+		}
+		if !hbaFieldMatches(fields[2], fixedRule.user, false) {
+			continue
+		}
+		for _, database := range fixedRule.databases {
+			if hbaFieldMatches(fields[1], database, database == "replication") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hbaFieldMatches checks if the database or user field of a pg_hba.conf
+// rule matches the passed value. The "all" keyword doesn't match the
+// replication connections, which are only matched by "replication"
+func hbaFieldMatches(field string, value string, isReplication bool) bool {
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case item == "all":
+			if !isReplication {
+				return true
+			}
+
+		case strings.HasPrefix(item, "+"), strings.HasPrefix(item, "@"):
+			return true
+
+		case strings.HasPrefix(item, "/"):
+			if matched, err := regexp.MatchString(item[1:], value); err != nil || matched {
+				return true
+			}
+
+		case strings.Trim(item, `"`) == value:
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateHBAAddress checks the address field of a pg_hba.conf rule when it
+// is expressed in CIDR notation. Host names and keywords are always accepted.
+func validateHBAAddress(address string) error {
+	if !strings.Contains(address, "/") {
+		return nil
+	}
+
+	if _, _, err := net.ParseCIDR(address); err != nil {
+		return fmt.Errorf("invalid CIDR address %q", address)
+	}
+
+	return nil
+}
+
+// splitHBARule splits a pg_hba.conf line in its fields, honoring the
+// double-quoted values and stripping the comments
+func splitHBARule(rule string) ([]string, error) {
+	var (
+		fields  []string
+		current strings.Builder
+		inField bool
+		quoted  bool
+	)
+
+	for _, char := range rule {
+		switch {
+		case char == '"':
+			quoted = !quoted
+			inField = true
+			current.WriteRune(char)
+
+		case quoted:
+			current.WriteRune(char)
+
+		case char == '#':
+			if inField {
+				fields = append(fields, current.String())
+			}
+			return fields, nil
+
+		case char == ' ' || char == '\t':
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+
+		default:
+			inField = true
+			current.WriteRune(char)
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+
+	return fields, nil
+}
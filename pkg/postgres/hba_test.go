@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_hba.conf rules validation", func() {
+	DescribeTable("accepts valid rules",
+		func(rule string) {
+			Expect(ValidateHBARule(rule)).To(Succeed())
+		},
+		Entry("empty line", ""),
+		Entry("comment", "# just a comment"),
+		Entry("local rule", "local all all peer map=local"),
+		Entry("CIDR address", "hostssl app app 10.244.0.0/16 scram-sha-256"),
+		Entry("IPv6 CIDR address", "host all all ::1/128 md5"),
+		Entry("IP address and mask", "host all all 10.0.0.0 255.0.0.0 reject"),
+		Entry("host name", "host all all .example.com scram-sha-256"),
+		Entry("keyword address", "hostnossl all all samenet reject"),
+		Entry("quoted names", `host "my db" "my user" all md5 # trailing comment`),
+		Entry("include directive", "include_if_exists /etc/hba/extra.conf"),
+		Entry("authentication options", `host all all all ldap ldapserver=ldap.example.com ldapprefix="cn="`),
+	)
+
+	DescribeTable("rejects invalid rules",
+		func(rule string, message string) {
+			Expect(ValidateHBARule(rule)).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("unknown connection type", "hots all all all md5", "invalid connection type"),
+		Entry("missing fields", "host all all md5", "missing fields"),
+		Entry("invalid CIDR address", "host all all 10.0.0.0/33 md5", "invalid CIDR address"),
+		Entry("missing IP mask", "host all all 10.0.0.1 md5", "missing IP mask"),
+		Entry("unknown authentication method", "host all all all scram", "invalid authentication method"),
+		Entry("unterminated quote", `host "app all all md5`, "unterminated quoted string"),
+	)
+
+	It("accepts every authentication method supported by PostgreSQL", func() {
+		Expect(ValidateHBARule("host all all all oauth issuer=https://example.com scope=openid")).To(Succeed())
+	})
+
+	DescribeTable("detects the rules shadowing the fixed ones",
+		func(rule string, shadows bool) {
+			Expect(HBARuleShadowsFixedRules(rule)).To(Equal(shadows))
+		},
+		Entry("local rule for every user", "local all all md5", true),
+		Entry("local rule for an application user", "local app app md5", false),
+		Entry("replication rule for every user", "hostssl replication all all reject", true),
+		Entry("every database doesn't include replication", "hostssl all app,other all scram-sha-256", false),
+		Entry("rule for every user", "host all all 10.0.0.0/8 scram-sha-256", true),
+		Entry("rule for the pooler user", "hostssl all cnpg_pooler_pgbouncer all md5", true),
+		Entry("rule for a group", "host app +admins all md5", false),
+		Entry("rule for a group on every database", "host all +admins all md5", true),
+		Entry("regular expression on the user", `host all /^stream all md5`, true),
+		Entry("connection without SSL", "hostnossl all all all reject", false),
+		Entry("comment", "# a comment", false),
+	)
+})
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parameters catalog", func() {
+	It("has a catalog for every supported major version", func() {
+		for _, version := range []int{110022, 120019, 130015, 140012, 150007, 160003, 170000} {
+			_, hasCatalog := IsParameterInCatalog(version, "shared_buffers")
+			Expect(hasCatalog).To(BeTrue(), "missing catalog for version %d", version)
+		}
+	})
+
+	It("can't check the parameters of the unknown major versions", func() {
+		_, hasCatalog := IsParameterInCatalog(990000, "shared_buffers")
+		Expect(hasCatalog).To(BeFalse())
+	})
+
+	DescribeTable("checks the parameters depending on the major version",
+		func(version int, name string, expected bool) {
+			found, _ := IsParameterInCatalog(version, name)
+			Expect(found).To(Equal(expected))
+		},
+		Entry("common parameter", 160000, "work_mem", true),
+		Entry("typo", 160000, "shared_bufers", false),
+		Entry("parameter names are case-insensitive", 160000, "TimeZone", true),
+		Entry("custom parameters are always accepted", 110000, "pg_stat_statements.max", true),
+		Entry("added in a later version", 120000, "wal_keep_size", false),
+		Entry("available since the version adding it", 130000, "wal_keep_size", true),
+		Entry("still available in the following versions", 170000, "wal_keep_size", true),
+		Entry("available before being removed", 120000, "wal_keep_segments", true),
+		Entry("removed in a later version", 130000, "wal_keep_segments", false),
+		Entry("renamed parameter, old name", 160000, "force_parallel_mode", false),
+		Entry("renamed parameter, new name", 160000, "debug_parallel_query", true),
+		Entry("recovery parameters before PostgreSQL 12", 110000, "restore_command", false),
+		Entry("recovery parameters since PostgreSQL 12", 120000, "restore_command", true),
+	)
+})
@@ -19,6 +19,8 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -28,24 +30,42 @@ import (
 
 // PostgresqlStatus defines a status for every instance in the cluster
 type PostgresqlStatus struct {
-	CurrentLsn                LSN         `json:"currentLsn,omitempty"`
-	ReceivedLsn               LSN         `json:"receivedLsn,omitempty"`
-	ReplayLsn                 LSN         `json:"replayLsn,omitempty"`
-	SystemID                  string      `json:"systemID"`
-	IsPrimary                 bool        `json:"isPrimary"`
-	ReplayPaused              bool        `json:"replayPaused"`
-	PendingRestart            bool        `json:"pendingRestart"`
-	PendingRestartForDecrease bool        `json:"pendingRestartForDecrease"`
-	IsWalReceiverActive       bool        `json:"isWalReceiverActive"`
-	IsPgRewindRunning         bool        `json:"isPgRewindRunning"`
-	MightBeUnavailable        bool        `json:"mightBeUnavailable"`
-	IsArchivingWAL            bool        `json:"isArchivingWAL,omitempty"`
-	Node                      string      `json:"node"`
-	Pod                       *corev1.Pod `json:"pod"`
-	TotalInstanceSize         string      `json:"totalInstanceSize"`
+	CurrentLsn                LSN    `json:"currentLsn,omitempty"`
+	ReceivedLsn               LSN    `json:"receivedLsn,omitempty"`
+	ReplayLsn                 LSN    `json:"replayLsn,omitempty"`
+	SystemID                  string `json:"systemID"`
+	IsPrimary                 bool   `json:"isPrimary"`
+	ReplayPaused              bool   `json:"replayPaused"`
+	PendingRestart            bool   `json:"pendingRestart"`
+	PendingRestartForDecrease bool   `json:"pendingRestartForDecrease"`
+	// The parameters whose changes are waiting for a restart
+	PendingRestartParameters []string    `json:"pendingRestartParameters,omitempty"`
+	IsWalReceiverActive      bool        `json:"isWalReceiverActive"`
+	IsPgRewindRunning        bool        `json:"isPgRewindRunning"`
+	MightBeUnavailable       bool        `json:"mightBeUnavailable"`
+	IsArchivingWAL           bool        `json:"isArchivingWAL,omitempty"`
+	Node                     string      `json:"node"`
+	Pod                      *corev1.Pod `json:"pod"`
+	TotalInstanceSize        string      `json:"totalInstanceSize"`
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
+	// IsDelayedReplica is true when the replica applies the changes
+	// with a delay, and must not be promoted automatically
+	IsDelayedReplica bool `json:"isDelayedReplica,omitempty"`
+
+	// FencingMode is how the instance is fenced, empty
+	// when the instance is not fenced
+	FencingMode utils.FencingMode `json:"fencingMode,omitempty"`
+
+	// The number of client connections running a query, excluding
+	// the ones of the instance manager
+	ActiveConnections int `json:"activeConnections,omitempty"`
+
+	// The values in effect of the parameters controlled by the slow
+	// query logging settings of the cluster, as found in pg_settings
+	SlowQueryLoggingSettings map[string]string `json:"slowQueryLoggingSettings,omitempty"`
+
 	// Archiver status
 
 	LastArchivedWAL     string `json:"lastArchivedWAL,omitempty"`
@@ -53,10 +73,26 @@ type PostgresqlStatus struct {
 	LastFailedWAL       string `json:"lastFailedWAL,omitempty"`
 	LastFailedWALTime   string `json:"lastFailedWALTime,omitempty"`
 
+	// Restorer status
+
+	LastRestoredWAL     string `json:"lastRestoredWAL,omitempty"`
+	LastRestoredWALTime string `json:"lastRestoredWALTime,omitempty"`
+	// contains the most recent failures of the restore_command, the oldest
+	// one first
+	WALRestoreFailures []WALRestoreFailure `json:"walRestoreFailures,omitempty"`
+
 	// WAL Status
 
 	CurrentWAL string `json:"currentWAL,omitempty"`
 
+	// Is the number of WAL files that were archived in parallel and that
+	// PostgreSQL still has to request to archive
+	ArchiveSpoolWALFiles int `json:"archiveSpoolWalFiles,omitempty"`
+
+	// Is the number of WAL files that were prefetched in parallel and that
+	// PostgreSQL still has to request to restore
+	RestoreSpoolWALFiles int `json:"restoreSpoolWalFiles,omitempty"`
+
 	// Is the number of '.ready' wal files contained in the wal archive folder
 	ReadyWALFiles int `json:"readyWalFiles,omitempty"`
 
@@ -64,6 +100,15 @@ type PostgresqlStatus struct {
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The age of the oldest unfrozen transaction or multixact ID among
+	// the databases, and the name of the database it belongs to. Only
+	// reported by the primary instance
+	OldestXIDAge      int64  `json:"oldestXIDAge,omitempty"`
+	OldestXIDDatabase string `json:"oldestXIDDatabase,omitempty"`
+
+	// The usage of the volumes of the instance, and their growth rate
+	DiskUsage []VolumeUsage `json:"diskUsage,omitempty"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error error `json:"-"`
@@ -107,6 +152,42 @@ type PgStatReplication struct {
 	SyncPriority    string `json:"syncPriority,omitempty"`
 }
 
+// WALRestoreFailure is a failed execution of the restore_command
+type WALRestoreFailure struct {
+	WALName string `json:"walName"`
+	Time    string `json:"time"`
+	Error   string `json:"error"`
+}
+
+// VolumeUsage is the usage of a volume of an instance, as
+// sampled by the instance manager
+type VolumeUsage struct {
+	// The name of the volume: `data`, `wal` or `tbs-<tablespace>`
+	Volume         string `json:"volume"`
+	TotalBytes     uint64 `json:"totalBytes"`
+	UsedBytes      uint64 `json:"usedBytes"`
+	AvailableBytes uint64 `json:"availableBytes"`
+
+	// The number of bytes the used space grew every second over the
+	// projection window, zero until there are enough samples
+	GrowthRate float64 `json:"growthRate,omitempty"`
+}
+
+// GetTimeToFull gets the time needed to fill the volume at the current
+// growth rate. It returns false if the volume is not growing
+func (usage VolumeUsage) GetTimeToFull() (time.Duration, bool) {
+	if usage.GrowthRate <= 0 {
+		return 0, false
+	}
+
+	timeToFull := float64(usage.AvailableBytes) / usage.GrowthRate * float64(time.Second)
+	if timeToFull >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64), true
+	}
+
+	return time.Duration(timeToFull), true
+}
+
 // PgStatBasebackup contains the information for progress of basebackup as reported by the primary instance
 type PgStatBasebackup struct {
 	Usename              string `json:"usename"`
@@ -147,6 +228,27 @@ func (status PostgresqlStatus) HasHTTPStatus() bool {
 	return status.Error == nil
 }
 
+// GetArchiveLag gets the time since the last WAL file has been archived,
+// which is zero when no WAL file is waiting to be archived. It returns
+// false when the lag cannot be computed, because the instance isn't
+// reporting its status or has never archived a WAL file.
+func (status PostgresqlStatus) GetArchiveLag(now time.Time) (time.Duration, bool) {
+	if !status.HasHTTPStatus() {
+		return 0, false
+	}
+
+	if status.ReadyWALFiles == 0 {
+		return 0, true
+	}
+
+	lastArchivedWALTime, err := time.Parse(time.RFC3339Nano, status.LastArchivedWALTime)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(now.Sub(lastArchivedWALTime), 0), true
+}
+
 // PgStatReplicationList is a list of PgStatReplication reported by the primary instance
 type PgStatReplicationList []PgStatReplication
 
@@ -163,6 +265,11 @@ type PgReplicationSlot struct {
 	WalStatus   string `json:"walStatus,omitempty"`
 	SafeWalSize *int   `json:"safeWalSize,omitempty"`
 	Active      bool   `json:"active,omitempty"`
+
+	// These fields are only meaningful for the logical replication slots
+	ConfirmedFlushLsn string `json:"confirmedFlushLsn,omitempty"`
+	LagBytes          int64  `json:"lagBytes,omitempty"`
+	Failover          bool   `json:"failover,omitempty"`
 }
 
 // PgReplicationSlotList is a list of PgReplicationSlot reported by the primary instance
@@ -287,6 +394,15 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return false
 	}
 
+	// Delayed replicas and fenced instances go after the
+	// other ones, since they must not be elected as the new primary
+	switch {
+	case !list.Items[i].IsElectable() && list.Items[j].IsElectable():
+		return false
+	case list.Items[i].IsElectable() && !list.Items[j].IsElectable():
+		return true
+	}
+
 	// Compare received LSN (bigger LSN orders first)
 	if list.Items[i].ReceivedLsn != list.Items[j].ReceivedLsn {
 		return !list.Items[i].ReceivedLsn.Less(list.Items[j].ReceivedLsn)
@@ -300,6 +416,12 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 	return list.Items[i].Pod.Name < list.Items[j].Pod.Name
 }
 
+// IsElectable checks whether the instance can be promoted automatically,
+// which is not the case for the delayed replicas and the fenced instances
+func (status PostgresqlStatus) IsElectable() bool {
+	return !status.IsDelayedReplica && status.FencingMode == ""
+}
+
 // AreWalReceiversDown checks if every WAL receiver of the cluster is down
 // ignoring the status of the primary, that does not matter during
 // a switchover or a failover
@@ -21,10 +21,13 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -234,3 +237,101 @@ var _ = Describe("PostgreSQL status real", func() {
 		})
 	})
 })
+
+var _ = Describe("PostgreSQL status with delayed replicas", func() {
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:              &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}},
+				ReceivedLsn:      "1/23",
+				IsDelayedReplica: true,
+			},
+			{
+				Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}},
+				ReceivedLsn: "1/21",
+			},
+			{
+				Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}},
+				IsPrimary: true,
+			},
+		},
+	}
+
+	Describe("when sorted", func() {
+		sort.Sort(&list)
+
+		It("puts the delayed replicas after the other ones", func() {
+			Expect(list.GetNames()).To(Equal([]string{"server-1", "server-2", "server-3"}))
+		})
+	})
+})
+
+var _ = Describe("PostgreSQL status with fenced instances", func() {
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}},
+				ReceivedLsn: "1/23",
+				FencingMode: utils.FencingModeReadOnly,
+			},
+			{
+				Pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}},
+				ReceivedLsn: "1/21",
+			},
+		},
+	}
+
+	Describe("when sorted", func() {
+		sort.Sort(&list)
+
+		It("puts the fenced instances after the other ones", func() {
+			Expect(list.GetNames()).To(Equal([]string{"server-2", "server-3"}))
+			Expect(list.Items[1].IsElectable()).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("archive lag", func() {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	It("is zero when no WAL file is waiting to be archived", func() {
+		lag, ok := PostgresqlStatus{LastArchivedWALTime: "-infinity"}.GetArchiveLag(now)
+		Expect(ok).To(BeTrue())
+		Expect(lag).To(BeZero())
+	})
+
+	It("is the time since the last WAL file has been archived", func() {
+		status := PostgresqlStatus{
+			ReadyWALFiles:       3,
+			LastArchivedWALTime: now.Add(-5 * time.Minute).Format(time.RFC3339Nano),
+		}
+		lag, ok := status.GetArchiveLag(now)
+		Expect(ok).To(BeTrue())
+		Expect(lag).To(Equal(5 * time.Minute))
+	})
+
+	It("cannot be computed when no WAL file has ever been archived", func() {
+		_, ok := PostgresqlStatus{ReadyWALFiles: 3, LastArchivedWALTime: "-infinity"}.GetArchiveLag(now)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("cannot be computed when the instance is not reporting its status", func() {
+		_, ok := PostgresqlStatus{Error: fmt.Errorf("connection refused")}.GetArchiveLag(now)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("time to fill a volume", func() {
+	It("is the available space divided by the growth rate", func() {
+		timeToFull, ok := VolumeUsage{AvailableBytes: 3600 * 1024, GrowthRate: 1024}.GetTimeToFull()
+		Expect(ok).To(BeTrue())
+		Expect(timeToFull).To(Equal(time.Hour))
+	})
+
+	It("cannot be computed when the volume is not growing", func() {
+		_, ok := VolumeUsage{AvailableBytes: 1024}.GetTimeToFull()
+		Expect(ok).To(BeFalse())
+		_, ok = VolumeUsage{AvailableBytes: 1024, GrowthRate: -10}.GetTimeToFull()
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -242,6 +242,7 @@ func (se *Reconciler) finalizeSnapshotBackupStep(
 	}
 
 	backup.Status.BackupSnapshotStatus.SetSnapshotElements(snapshots)
+	backup.Status.Size = snapshots.getRestoreSize()
 	if err := backupStatusFromSnapshots(snapshots, &backup.Status); err != nil {
 		contextLogger.Error(err, "while enriching the backup status")
 	}
@@ -106,6 +106,19 @@ func (s slice) getControldata() (string, error) {
 	return "", fmt.Errorf("could not retrieve pg_controldata from any snapshot")
 }
 
+// getRestoreSize gets the sum of the restore sizes of the VolumeSnapshots,
+// which is zero if they are not known yet
+func (s slice) getRestoreSize() int64 {
+	var size int64
+	for _, volumeSnapshot := range s {
+		if volumeSnapshot.Status == nil || volumeSnapshot.Status.RestoreSize == nil {
+			continue
+		}
+		size += volumeSnapshot.Status.RestoreSize.Value()
+	}
+	return size
+}
+
 // getBackupVolumeSnapshots extracts the list of volume snapshots related
 // to a backup name
 func getBackupVolumeSnapshots(
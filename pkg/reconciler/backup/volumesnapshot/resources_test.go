@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -193,3 +194,15 @@ var _ = Describe("parseVolumeSnapshotInfo", func() {
 		})
 	})
 })
+
+var _ = Describe("getRestoreSize", func() {
+	It("sums the restore sizes which are known", func() {
+		snapshots := slice{
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{RestoreSize: ptr.To(resource.MustParse("10Gi"))}},
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{RestoreSize: ptr.To(resource.MustParse("2Gi"))}},
+			{Status: &storagesnapshotv1.VolumeSnapshotStatus{}},
+			{},
+		}
+		Expect(snapshots.getRestoreSize()).To(BeEquivalentTo(12 * 1024 * 1024 * 1024))
+	})
+})
@@ -22,20 +22,28 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 )
 
+// walArchiveTimeout is the maximum time the hibernation waits for the
+// primary instance to archive the WAL files that are ready, before
+// shutting it down anyway
+const walArchiveTimeout = 5 * time.Minute
+
 // Reconcile reconciles the cluster hibernation status.
 func Reconcile(
 	ctx context.Context,
 	c client.Client,
 	cluster *apiv1.Cluster,
 	instances []corev1.Pod,
+	instancesStatus postgres.PostgresqlStatusList,
 ) (*ctrl.Result, error) {
 	hibernationCondition := meta.FindStatusCondition(cluster.Status.Conditions, HibernationConditionType)
 	if hibernationCondition == nil {
@@ -45,7 +53,7 @@ func Reconcile(
 
 	switch hibernationCondition.Reason {
 	case HibernationConditionReasonDeletingPods:
-		return reconcileDeletePods(ctx, c, instances)
+		return reconcileDeletePods(ctx, c, hibernationCondition, instances, instancesStatus)
 
 	case HibernationConditionReasonWaitingPodsDeletion:
 		return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
@@ -58,7 +66,9 @@ func Reconcile(
 func reconcileDeletePods(
 	ctx context.Context,
 	c client.Client,
+	hibernationCondition *metav1.Condition,
 	instances []corev1.Pod,
+	instancesStatus postgres.PostgresqlStatusList,
 ) (*ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
@@ -77,6 +87,21 @@ func reconcileDeletePods(
 		// The primary Pod has already been deleted, we can
 		// delete the replicas
 		podToBeDeleted = &instances[0]
+	} else if pendingWALFiles := getPendingWALFiles(instancesStatus, podToBeDeleted.Name); pendingWALFiles > 0 {
+		// The WAL files which are ready would be archived only after
+		// the rehydration, leaving a hole in the WAL archive for the
+		// whole hibernation
+		if time.Since(hibernationCondition.LastTransitionTime.Time) < walArchiveTimeout {
+			contextLogger.Info("Waiting for the primary to archive the WAL files before hibernating",
+				"podName", podToBeDeleted.Name,
+				"pendingWALFiles", pendingWALFiles)
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		contextLogger.Warning("Timeout while waiting for the primary to archive the WAL files, "+
+			"hibernating anyway",
+			"podName", podToBeDeleted.Name,
+			"pendingWALFiles", pendingWALFiles)
 	}
 
 	// The Pod list is sorted and the primary instance
@@ -85,3 +110,15 @@ func reconcileDeletePods(
 	deletionResult := c.Delete(ctx, podToBeDeleted)
 	return &ctrl.Result{RequeueAfter: 5 * time.Second}, deletionResult
 }
+
+// getPendingWALFiles gets the number of WAL files which are waiting
+// to be archived by the passed instance
+func getPendingWALFiles(instancesStatus postgres.PostgresqlStatusList, podName string) int {
+	for _, status := range instancesStatus.Items {
+		if status.Pod != nil && status.Pod.Name == podName && status.Error == nil {
+			return status.ReadyWALFiles
+		}
+	}
+
+	return 0
+}
@@ -18,6 +18,7 @@ package hibernation
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -27,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
@@ -69,7 +71,7 @@ var _ = Describe("Reconcile resources", func() {
 		mock := &clientMock{}
 		cluster := apiv1.Cluster{}
 		// A Reconcile with a nil return will allow cluster reconciliation to proceed
-		Expect(Reconcile(ctx, mock, &cluster, nil)).To(BeNil())
+		Expect(Reconcile(ctx, mock, &cluster, nil, postgres.PostgresqlStatusList{})).To(BeNil())
 		Expect(mock.deletedPods).To(BeEmpty())
 	})
 
@@ -92,7 +94,7 @@ var _ = Describe("Reconcile resources", func() {
 			},
 		}
 		// A Reconcile with a non-nil return will stop the cluster reconciliation
-		Expect(Reconcile(ctx, mock, &cluster, nil)).ToNot(BeNil())
+		Expect(Reconcile(ctx, mock, &cluster, nil, postgres.PostgresqlStatusList{})).ToNot(BeNil())
 		Expect(mock.deletedPods).To(BeEmpty())
 	})
 
@@ -123,7 +125,7 @@ var _ = Describe("Reconcile resources", func() {
 				},
 			},
 		}
-		result, err := Reconcile(ctx, mock, &cluster, pods)
+		result, err := Reconcile(ctx, mock, &cluster, pods, postgres.PostgresqlStatusList{})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(result).ToNot(BeNil())
 		Expect(result.RequeueAfter).ToNot(BeZero())
@@ -151,7 +153,7 @@ var _ = Describe("Reconcile resources", func() {
 		}
 
 		pods := fakePodListWithPrimary()
-		Expect(Reconcile(ctx, mock, &cluster, pods)).ToNot(BeNil())
+		Expect(Reconcile(ctx, mock, &cluster, pods, postgres.PostgresqlStatusList{})).ToNot(BeNil())
 		Expect(mock.deletedPods).To(ConsistOf("cluster-example-2"))
 	})
 
@@ -176,9 +178,46 @@ var _ = Describe("Reconcile resources", func() {
 		}
 
 		pods := fakePodListWithoutPrimary()
-		Expect(Reconcile(ctx, mock, &cluster, pods)).ToNot(BeNil())
+		Expect(Reconcile(ctx, mock, &cluster, pods, postgres.PostgresqlStatusList{})).ToNot(BeNil())
 		Expect(mock.deletedPods).To(ConsistOf("cluster-example-1"))
 	})
+
+	It("waits for the primary to archive the ready WAL files", func(ctx SpecContext) {
+		mock := &clientMock{}
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Status: apiv1.ClusterStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               HibernationConditionType,
+						Status:             metav1.ConditionFalse,
+						Reason:             HibernationConditionReasonDeletingPods,
+						LastTransitionTime: metav1.Now(),
+					},
+				},
+			},
+		}
+
+		pods := fakePodListWithPrimary()
+		instancesStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: &pods[1], IsPrimary: true, ReadyWALFiles: 3},
+			},
+		}
+		result, err := Reconcile(ctx, mock, &cluster, pods, instancesStatus)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		Expect(mock.deletedPods).To(BeEmpty())
+
+		By("hibernating anyway after the timeout", func() {
+			cluster.Status.Conditions[0].LastTransitionTime = metav1.NewTime(
+				time.Now().Add(-walArchiveTimeout - time.Minute))
+			Expect(Reconcile(ctx, mock, &cluster, pods, instancesStatus)).ToNot(BeNil())
+			Expect(mock.deletedPods).To(ConsistOf("cluster-example-2"))
+		})
+	})
 })
 
 func fakePod(name string, role string) corev1.Pod {
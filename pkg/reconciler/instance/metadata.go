@@ -157,7 +157,7 @@ func updateClusterLabels(
 	return true
 }
 
-// Make sure that primary and replicas are correctly labelled as such
+// Make sure that primary, replicas and delayed replicas are correctly labelled as such
 //
 // Returns true if the instance needed updating
 func updateRoleLabels(
@@ -186,6 +186,16 @@ func updateRoleLabels(
 	newPodRole, newHasRole := instance.ObjectMeta.Labels[utils.ClusterInstanceRoleLabelName]
 
 	switch {
+	case isDrainingConnections(cluster, instance.Name):
+		// The instance must not receive new connections while the
+		// running transactions are completing
+		if hasRole || newHasRole {
+			contextLogger.Info("Removing role label to drain the connections", "pod", instance.Name)
+			delete(instance.Labels, utils.ClusterRoleLabelName)
+			delete(instance.Labels, utils.ClusterInstanceRoleLabelName)
+			return true
+		}
+
 	case instance.Name == cluster.Status.CurrentPrimary:
 		if !hasRole || podRole != specs.ClusterRoleLabelPrimary || !newHasRole ||
 			newPodRole != specs.ClusterRoleLabelPrimary {
@@ -194,6 +204,14 @@ func updateRoleLabels(
 			return true
 		}
 
+	case cluster.Spec.PostgresConfiguration.DelayedReplicas.IsDelayedReplica(instance.Name):
+		if !hasRole || podRole != specs.ClusterRoleLabelDelayedReplica || !newHasRole ||
+			newPodRole != specs.ClusterRoleLabelDelayedReplica {
+			contextLogger.Info("Setting delayed replica label", "pod", instance.Name)
+			utils.SetInstanceRole(instance.ObjectMeta, specs.ClusterRoleLabelDelayedReplica)
+			return true
+		}
+
 	default:
 		if !hasRole || podRole != specs.ClusterRoleLabelReplica || !newHasRole ||
 			newPodRole != specs.ClusterRoleLabelReplica {
@@ -206,6 +224,17 @@ func updateRoleLabels(
 	return false
 }
 
+// isDrainingConnections checks whether the connections to the passed instance
+// are being drained, either because it is the former primary during a
+// switchover or because it is the replica being removed by the autoscaling
+func isDrainingConnections(cluster *apiv1.Cluster, instanceName string) bool {
+	if instanceName == cluster.Status.CurrentPrimary {
+		return cluster.IsDrainingPrimaryConnections()
+	}
+
+	return instanceName == cluster.Status.ReplicaAutoscaling.GetDrainingInstance()
+}
+
 // updateOperatorLabels ensures that the instances are labelled as instances,
 // and have the correct instance name
 //
@@ -106,6 +106,115 @@ var _ = Describe("object metadata test", func() {
 			Expect(newReplicaPod.Labels[utils.ClusterInstanceRoleLabelName]).To(Equal(specs.ClusterRoleLabelReplica))
 		})
 
+		It("Should remove the role labels from the primary while draining its connections", func() {
+			cluster := &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					SwitchoverDrainTimeout: 30,
+				},
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary: "oldPrimaryPod",
+					TargetPrimary:  "newPrimaryPod",
+				},
+			}
+
+			oldPrimaryPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "oldPrimaryPod",
+					Labels: map[string]string{
+						utils.ClusterRoleLabelName:         specs.ClusterRoleLabelPrimary,
+						utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelPrimary,
+					},
+				},
+			}
+
+			updated := updateRoleLabels(context.Background(), cluster, oldPrimaryPod)
+			Expect(updated).To(BeTrue())
+			Expect(oldPrimaryPod.Labels).ToNot(HaveKey(utils.ClusterRoleLabelName))
+			Expect(oldPrimaryPod.Labels).ToNot(HaveKey(utils.ClusterInstanceRoleLabelName))
+
+			updated = updateRoleLabels(context.Background(), cluster, oldPrimaryPod)
+			Expect(updated).To(BeFalse())
+
+			// without draining, the primary keeps its labels during a switchover
+			cluster.Spec.SwitchoverDrainTimeout = 0
+			updated = updateRoleLabels(context.Background(), cluster, oldPrimaryPod)
+			Expect(updated).To(BeTrue())
+			Expect(oldPrimaryPod.Labels[utils.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelPrimary))
+		})
+
+		It("Should remove the role labels from the replica drained by the autoscaling", func() {
+			cluster := &apiv1.Cluster{
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary: "primaryPod",
+					TargetPrimary:  "primaryPod",
+					ReplicaAutoscaling: &apiv1.ReplicaAutoscalingStatus{
+						DrainingInstance: "replicaPod",
+					},
+				},
+			}
+
+			replicaPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "replicaPod",
+					Labels: map[string]string{
+						utils.ClusterRoleLabelName:         specs.ClusterRoleLabelReplica,
+						utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelReplica,
+					},
+				},
+			}
+
+			updated := updateRoleLabels(context.Background(), cluster, replicaPod)
+			Expect(updated).To(BeTrue())
+			Expect(replicaPod.Labels).ToNot(HaveKey(utils.ClusterRoleLabelName))
+			Expect(replicaPod.Labels).ToNot(HaveKey(utils.ClusterInstanceRoleLabelName))
+
+			// when the drain is canceled, the replica gets its labels back
+			cluster.Status.ReplicaAutoscaling = nil
+			updated = updateRoleLabels(context.Background(), cluster, replicaPod)
+			Expect(updated).To(BeTrue())
+			Expect(replicaPod.Labels[utils.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelReplica))
+		})
+
+		It("Should label the delayed replicas as such", func() {
+			cluster := &apiv1.Cluster{
+				Spec: apiv1.ClusterSpec{
+					PostgresConfiguration: apiv1.PostgresConfiguration{
+						DelayedReplicas: &apiv1.DelayedReplicasConfiguration{
+							Instances:     []string{"delayedPod"},
+							MinApplyDelay: "1h",
+						},
+					},
+				},
+				Status: apiv1.ClusterStatus{
+					CurrentPrimary: "primaryPod",
+				},
+			}
+
+			delayedPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "delayedPod",
+					Labels: map[string]string{
+						utils.ClusterRoleLabelName:         specs.ClusterRoleLabelReplica,
+						utils.ClusterInstanceRoleLabelName: specs.ClusterRoleLabelReplica,
+					},
+				},
+			}
+
+			updated := updateRoleLabels(context.Background(), cluster, delayedPod)
+			Expect(updated).To(BeTrue())
+			Expect(delayedPod.Labels[utils.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelDelayedReplica))
+			Expect(delayedPod.Labels[utils.ClusterInstanceRoleLabelName]).To(Equal(specs.ClusterRoleLabelDelayedReplica))
+
+			updated = updateRoleLabels(context.Background(), cluster, delayedPod)
+			Expect(updated).To(BeFalse())
+
+			// a delayed replica promoted manually is labelled as the primary
+			cluster.Status.CurrentPrimary = "delayedPod"
+			updated = updateRoleLabels(context.Background(), cluster, delayedPod)
+			Expect(updated).To(BeTrue())
+			Expect(delayedPod.Labels[utils.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelPrimary))
+		})
+
 		It("Should not perform role reconciliation when there is no current primary", func() {
 			cluster := &apiv1.Cluster{}
 
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -54,7 +56,11 @@ func createIfNotExists(
 		)
 	}
 
-	if err = c.Create(ctx, pvc); err != nil && !apierrs.IsAlreadyExists(err) {
+	err = c.Create(ctx, pvc)
+	if apierrs.IsAlreadyExists(err) {
+		return ensureOwnedByCluster(ctx, c, cluster, pvc.Name)
+	}
+	if err != nil {
 		return fmt.Errorf("unable to create a PVC: %s for this node (nodeSerial: %d): %w",
 			pvc.Name,
 			configuration.NodeSerial,
@@ -64,3 +70,26 @@ func createIfNotExists(
 
 	return nil
 }
+
+// ensureOwnedByCluster checks that the existing PVC with the passed name
+// belongs to the cluster. A PVC not owned by the cluster, like one retained
+// after the deletion of a previous cluster with the same name, contains
+// data which is unrelated to this cluster and must not be adopted
+func ensureOwnedByCluster(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	pvcName string,
+) error {
+	var pvc corev1.PersistentVolumeClaim
+	if err := c.Get(ctx, client.ObjectKey{Name: pvcName, Namespace: cluster.Namespace}, &pvc); err != nil {
+		return fmt.Errorf("while getting the existing PVC %s: %w", pvcName, err)
+	}
+
+	if !metav1.IsControlledBy(&pvc, cluster) {
+		return fmt.Errorf("PVC %s already exists and is not owned by the cluster, "+
+			"delete it or choose a different name for the cluster", pvcName)
+	}
+
+	return nil
+}
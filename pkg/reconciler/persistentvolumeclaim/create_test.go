@@ -81,20 +81,37 @@ var _ = Describe("testing create function", func() {
 	})
 
 	Context("when PVC already exists", func() {
-		It("should not return an error", func() {
+		var existingPVC *corev1.PersistentVolumeClaim
+
+		BeforeEach(func() {
+			existingPVC = &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pvcName,
+					Namespace: "default",
+				},
+			}
+		})
+
+		It("should not return an error if it is owned by the cluster", func() {
+			cluster.SetInheritedDataAndOwnership(&existingPVC.ObjectMeta)
 			cli = fake.NewClientBuilder().
 				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
-				WithObjects(&corev1.PersistentVolumeClaim{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      pvcName,
-						Namespace: "default",
-					},
-				}).
+				WithObjects(existingPVC).
 				Build()
 
 			err := createIfNotExists(ctx, cli, cluster, cc)
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("should return an error if it is not owned by the cluster", func() {
+			cli = fake.NewClientBuilder().
+				WithScheme(schemeBuilder.BuildWithAllKnownScheme()).
+				WithObjects(existingPVC).
+				Build()
+
+			err := createIfNotExists(ctx, cli, cluster, cc)
+			Expect(err).To(MatchError(ContainSubstring("is not owned by the cluster")))
+		})
 	})
 
 	It("should return ErrNextLoop on invalid size", func() {
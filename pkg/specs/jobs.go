@@ -18,12 +18,15 @@ package specs
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/kballard/go-shellquote"
 	storagesnapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -35,6 +38,14 @@ const (
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
+
+	// postInitSQLRefsFolder points to the folder of
+	// postInitSQL files in the primary job with initdb.
+	postInitSQLRefsFolder = "/etc/post-init-sql"
+
+	// postInitTemplateSQLRefsFolder points to the folder of
+	// postInitTemplateSQL files in the primary job with initdb.
+	postInitTemplateSQLRefsFolder = "/etc/post-init-template-sql"
 )
 
 // CreatePrimaryJobViaInitdb creates a new primary instance in a Pod
@@ -70,12 +81,27 @@ func CreatePrimaryJobViaInitdb(cluster apiv1.Cluster, nodeSerial int) *batchv1.J
 			shellquote.Join(cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQL...))
 	}
 
+	if cluster.Spec.Bootstrap.InitDB.Timezone != "" {
+		initCommand = append(initCommand,
+			"--timezone", cluster.Spec.Bootstrap.InitDB.Timezone)
+	}
+
 	if cluster.ShouldInitDBCreateApplicationDatabase() {
 		initCommand = append(initCommand,
 			"--app-db-name", cluster.Spec.Bootstrap.InitDB.Database,
 			"--app-user", cluster.Spec.Bootstrap.InitDB.Owner)
 	}
 
+	if cluster.ShouldInitDBRunPostInitSQLRefs() {
+		initCommand = append(initCommand,
+			"--post-init-sql-refs-folder", postInitSQLRefsFolder)
+	}
+
+	if cluster.ShouldInitDBRunPostInitTemplateSQLRefs() {
+		initCommand = append(initCommand,
+			"--post-init-template-sql-refs-folder", postInitTemplateSQLRefsFolder)
+	}
+
 	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
 
 	if cluster.Spec.Bootstrap.InitDB.Import != nil {
@@ -119,6 +145,9 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if encoding := config.Encoding; encoding != "" {
 		options = append(options, fmt.Sprintf("--encoding=%s", encoding))
 	}
+	if locale := config.Locale; locale != "" {
+		options = append(options, fmt.Sprintf("--locale=%s", locale))
+	}
 	if localeCollate := config.LocaleCollate; localeCollate != "" {
 		options = append(options, fmt.Sprintf("--lc-collate=%s", localeCollate))
 	}
@@ -251,6 +280,158 @@ func RestoreReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job
 	return job
 }
 
+// CreateBackupVerificationJob creates a job restoring the latest base backup
+// of the cluster into a throwaway data directory, to verify it can be
+// recovered. No PVC is used: every volume of the instance is replaced by
+// an ephemeral one, whose size is limited to avoid filling the ephemeral
+// storage of the node
+func CreateBackupVerificationJob(cluster apiv1.Cluster) *batchv1.Job {
+	initCommand := []string{
+		"/controller/manager",
+		"instance",
+		"verifybackup",
+	}
+
+	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+
+	volumes := createPostgresVolumes(&cluster, cluster.GetBackupVerificationJobName())
+	for idx := range volumes {
+		if volumes[idx].PersistentVolumeClaim != nil {
+			volumes[idx].VolumeSource = corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: getBackupVerificationSizeLimit(&cluster, volumes[idx].Name),
+				},
+			}
+		}
+	}
+
+	job := createJob(
+		cluster,
+		cluster.GetBackupVerificationJobName(),
+		jobRoleBackupVerification,
+		initCommand,
+		volumes,
+	)
+	job.Labels[utils.JobRoleLabelName] = string(jobRoleBackupVerification)
+
+	// A failed verification is a result by itself, there's no reason to retry it
+	job.Spec.BackoffLimit = ptr.To(int32(0))
+
+	if cluster.Spec.Backup.IsBarmanEndpointCASet() {
+		AddBarmanEndpointCAToPodSpec(
+			&job.Spec.Template.Spec,
+			cluster.Spec.Backup.BarmanObjectStore.EndpointCA,
+			cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials,
+		)
+	}
+
+	return job
+}
+
+// getBackupVerificationSizeLimit gets the size limit of the ephemeral volume
+// replacing the passed volume of the instance in the backup verification job.
+// When no limit is configured, the size requested for the volume is used
+func getBackupVerificationSizeLimit(cluster *apiv1.Cluster, volumeName string) *resource.Quantity {
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.Verification != nil &&
+		cluster.Spec.Backup.Verification.ScratchSizeLimit != nil {
+		return cluster.Spec.Backup.Verification.ScratchSizeLimit
+	}
+
+	switch {
+	case volumeName == "pgdata":
+		return cluster.Spec.StorageConfiguration.GetSizeOrNil()
+
+	case volumeName == "pg-wal" && cluster.Spec.WalStorage != nil:
+		return cluster.Spec.WalStorage.GetSizeOrNil()
+	}
+
+	for idx := range cluster.Spec.Tablespaces {
+		if VolumeMountNameForTablespace(cluster.Spec.Tablespaces[idx].Name) == volumeName {
+			return cluster.Spec.Tablespaces[idx].Storage.GetSizeOrNil()
+		}
+	}
+
+	return nil
+}
+
+// IsBackupVerificationJob checks if the passed job is verifying
+// the backups of a cluster
+func IsBackupVerificationJob(job batchv1.Job) bool {
+	return job.Labels[utils.JobRoleLabelName] == string(jobRoleBackupVerification)
+}
+
+// CreateMajorUpgradeJob creates a job upgrading the data directory of the
+// passed instance, via pg_upgrade, to the PostgreSQL major version of the
+// image of the cluster, which is newMajorVersion. The binaries of the
+// previous major version are copied from oldImage by an init container
+func CreateMajorUpgradeJob(
+	cluster apiv1.Cluster,
+	nodeSerial int,
+	oldImage string,
+	newMajorVersion int,
+) *batchv1.Job {
+	initCommand := []string{
+		"/controller/manager",
+		"instance",
+		"upgrade",
+		"execute",
+	}
+
+	// The upgraded data directory needs to have the
+	// same encoding and locale of the original one
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+		initCommand = append(initCommand, buildInitDBFlags(cluster)...)
+	}
+
+	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+
+	instanceName := GetInstanceName(cluster.Name, nodeSerial)
+	job := createJob(
+		cluster,
+		jobRoleMajorUpgrade.getJobName(instanceName),
+		jobRoleMajorUpgrade,
+		initCommand,
+		createPostgresVolumes(&cluster, instanceName),
+	)
+	job.Labels[utils.InstanceNameLabelName] = instanceName
+	job.Labels[utils.JobRoleLabelName] = string(jobRoleMajorUpgrade)
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[utils.MajorVersionAnnotationName] = strconv.Itoa(newMajorVersion)
+	applyInstancePlacement(&job.Spec.Template.Spec, cluster, cluster.GetInstancePlacement(instanceName))
+	job.Spec.Template.Labels[utils.InstanceNameLabelName] = instanceName
+
+	prepareContainer := corev1.Container{
+		Name:            MajorUpgradePrepareContainerName,
+		Image:           oldImage,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/controller/manager",
+			"instance",
+			"upgrade",
+			"prepare",
+		},
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		Resources:       cluster.Spec.Resources,
+		SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+	}
+	addManagerLoggingOptions(cluster, &prepareContainer)
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, prepareContainer)
+
+	// A failed upgrade is rolled back, and retrying it
+	// would just lead to the same result
+	job.Spec.BackoffLimit = ptr.To(int32(0))
+
+	return job
+}
+
+// IsMajorUpgradeJob checks if the passed job is upgrading
+// the data directory to a new PostgreSQL major version
+func IsMajorUpgradeJob(job batchv1.Job) bool {
+	return job.Labels[utils.JobRoleLabelName] == string(jobRoleMajorUpgrade)
+}
+
 func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 	var flags []string
 
@@ -271,6 +452,9 @@ const (
 	jobRoleFullRecovery     jobRole = "full-recovery"
 	jobRoleJoin             jobRole = "join"
 	jobRoleSnapshotRecovery jobRole = "snapshot-recovery"
+
+	jobRoleBackupVerification jobRole = "backup-verification"
+	jobRoleMajorUpgrade       jobRole = "major-upgrade"
 )
 
 var jobRoleList = []jobRole{jobRoleImport, jobRoleInitDB, jobRolePGBaseBackup, jobRoleFullRecovery, jobRoleJoin}
@@ -295,6 +479,47 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 	instanceName := GetInstanceName(cluster.Name, nodeSerial)
 	jobName := role.getJobName(instanceName)
 
+	job := createJob(cluster, jobName, role, initCommand, createPostgresVolumes(&cluster, instanceName))
+	job.Labels[utils.InstanceNameLabelName] = instanceName
+	applyInstancePlacement(&job.Spec.Template.Spec, cluster, cluster.GetInstancePlacement(instanceName))
+	job.Spec.Template.Labels[utils.InstanceNameLabelName] = instanceName
+
+	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
+		addSQLRefsVolumesToJob(job, postInitApplicationSQLRefsFolder,
+			cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs)
+	}
+
+	if cluster.ShouldInitDBRunPostInitSQLRefs() {
+		addSQLRefsVolumesToJob(job, postInitSQLRefsFolder,
+			cluster.Spec.Bootstrap.InitDB.PostInitSQLRefs)
+	}
+
+	if cluster.ShouldInitDBRunPostInitTemplateSQLRefs() {
+		addSQLRefsVolumesToJob(job, postInitTemplateSQLRefsFolder,
+			cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQLRefs)
+	}
+
+	return job
+}
+
+// addSQLRefsVolumesToJob projects the referenced SQL files
+// inside the passed folder of the job container
+func addSQLRefsVolumesToJob(job *batchv1.Job, folder string, refs *apiv1.SQLRefs) {
+	volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(folder, refs)
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
+	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+		job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
+}
+
+// createJob create a job named jobName, that executes the provided command
+// using the passed volumes
+func createJob(
+	cluster apiv1.Cluster,
+	jobName string,
+	role jobRole,
+	initCommand []string,
+	volumes []corev1.Volume,
+) *batchv1.Job {
 	envConfig := CreatePodEnvConfig(cluster, jobName)
 
 	job := &batchv1.Job{
@@ -302,17 +527,15 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 			Name:      jobName,
 			Namespace: cluster.Namespace,
 			Labels: map[string]string{
-				utils.InstanceNameLabelName: instanceName,
-				utils.ClusterLabelName:      cluster.Name,
+				utils.ClusterLabelName: cluster.Name,
 			},
 		},
 		Spec: batchv1.JobSpec{
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
-						utils.InstanceNameLabelName: instanceName,
-						utils.ClusterLabelName:      cluster.Name,
-						utils.JobRoleLabelName:      string(role),
+						utils.ClusterLabelName: cluster.Name,
+						utils.JobRoleLabelName: string(role),
 					},
 				},
 				Spec: corev1.PodSpec{
@@ -334,7 +557,7 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 							SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
 						},
 					},
-					Volumes: createPostgresVolumes(&cluster, instanceName),
+					Volumes: volumes,
 					SecurityContext: CreatePodSecurityContext(
 						cluster.GetSeccompProfile(),
 						cluster.GetPostgresUID(),
@@ -360,15 +583,6 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role jobRole, initC
 		utils.AnnotateAppArmor(&job.ObjectMeta, &job.Spec.Template.Spec, cluster.Annotations)
 	}
 
-	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
-			cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs,
-		)
-		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
-		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
-			job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
-	}
-
 	if cluster.Spec.PriorityClassName != "" {
 		job.Spec.Template.Spec.PriorityClassName = cluster.Spec.PriorityClassName
 	}
@@ -19,7 +19,9 @@ package specs
 import (
 	v1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -144,7 +146,7 @@ var _ = Describe("Job created via InitDB", func() {
 						PostInitSQL:            []string{"testPostInitSql"},
 						PostInitTemplateSQL:    []string{"testPostInitTemplateSql"},
 						PostInitApplicationSQL: []string{"testPostInitApplicationSql"},
-						PostInitApplicationSQLRefs: &apiv1.PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &apiv1.SQLRefs{
 							SecretRefs: []apiv1.SecretKeySelector{
 								{
 									Key: "secretKey1",
@@ -164,4 +166,163 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitApplicationSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
+
+	It("contain the references to the post-init SQL files of every database", func() {
+		refs := &apiv1.SQLRefs{
+			ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+				{
+					Key: "configMapKey1",
+					LocalObjectReference: apiv1.LocalObjectReference{
+						Name: "configMapName1",
+					},
+				},
+			},
+		}
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						PostInitSQLRefs:            refs,
+						PostInitTemplateSQLRefs:    refs,
+						PostInitApplicationSQLRefs: refs,
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Command).Should(ContainElements(
+			postInitSQLRefsFolder, postInitTemplateSQLRefsFolder, postInitApplicationSQLRefsFolder))
+		Expect(container.VolumeMounts).Should(ContainElements(
+			HaveField("MountPath", postInitSQLRefsFolder+"/0.sql"),
+			HaveField("MountPath", postInitTemplateSQLRefsFolder+"/0.sql"),
+			HaveField("MountPath", postInitApplicationSQLRefsFolder+"/0.sql"),
+		))
+		Expect(job.Spec.Template.Spec.Volumes).Should(ContainElements(
+			HaveField("Name", "0-post-init-sql"),
+			HaveField("Name", "0-post-init-template-sql"),
+			HaveField("Name", "0-post-init-application-sql"),
+		))
+	})
+})
+
+var _ = Describe("Job created via InitDB with locales and time zone", func() {
+	It("passes the locale to initdb and the time zone to the instance manager", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Locale:      "en_US.UTF-8",
+						LocaleCType: "C",
+						Timezone:    "Europe/Rome",
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		command := job.Spec.Template.Spec.Containers[0].Command
+		Expect(command).To(ContainElement("--locale=en_US.UTF-8 --lc-ctype=C"))
+		Expect(command).To(ContainElements("--timezone", "Europe/Rome"))
+	})
+})
+
+var _ = Describe("Backup verification job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://bucket/path",
+				},
+				Verification: &apiv1.BackupVerificationConfiguration{
+					Enabled: true,
+				},
+			},
+		},
+	}
+
+	It("runs the verifybackup command", func() {
+		job := CreateBackupVerificationJob(cluster)
+		Expect(job.Name).To(Equal("cluster-example-backup-verification"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElement("verifybackup"))
+		Expect(IsBackupVerificationJob(*job)).To(BeTrue())
+	})
+
+	It("doesn't use any PVC", func() {
+		job := CreateBackupVerificationJob(cluster)
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			Expect(volume.PersistentVolumeClaim).To(BeNil())
+		}
+		Expect(job.Spec.Template.Spec.Volumes[0].Name).To(Equal("pgdata"))
+		Expect(job.Spec.Template.Spec.Volumes[0].EmptyDir).ToNot(BeNil())
+	})
+
+	It("limits the size of the ephemeral volumes", func() {
+		limitedCluster := cluster.DeepCopy()
+		limitedCluster.Spec.StorageConfiguration.Size = "1Gi"
+		job := CreateBackupVerificationJob(*limitedCluster)
+		Expect(job.Spec.Template.Spec.Volumes[0].EmptyDir.SizeLimit.String()).To(Equal("1Gi"))
+
+		limitedCluster.Spec.Backup.Verification.ScratchSizeLimit = ptr.To(resource.MustParse("500Mi"))
+		job = CreateBackupVerificationJob(*limitedCluster)
+		Expect(job.Spec.Template.Spec.Volumes[0].EmptyDir.SizeLimit.String()).To(Equal("500Mi"))
+	})
+
+	It("is not bound to any instance", func() {
+		job := CreateBackupVerificationJob(cluster)
+		Expect(job.Labels).ToNot(HaveKey("cnpg.io/instanceName"))
+		Expect(job.Spec.Template.Labels).ToNot(HaveKey("cnpg.io/instanceName"))
+	})
+
+	It("isn't confused with the jobs creating instances", func() {
+		Expect(IsBackupVerificationJob(*CreatePrimaryJobViaPgBaseBackup(cluster, 1))).To(BeFalse())
+	})
+})
+
+var _ = Describe("Major upgrade job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:17.0",
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Encoding: "UTF8",
+				},
+			},
+		},
+	}
+
+	It("runs pg_upgrade on the data directory of the instance", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "postgres:16.4", 17)
+		Expect(job.Name).To(Equal("cluster-example-1-major-upgrade"))
+		Expect(job.Labels).To(HaveKeyWithValue("cnpg.io/instanceName", "cluster-example-1"))
+		Expect(job.Annotations).To(HaveKeyWithValue("cnpg.io/majorVersion", "17"))
+		Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("postgres:17.0"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements("upgrade", "execute"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElement("--encoding=UTF8"))
+		Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("cluster-example-1"))
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+		Expect(IsMajorUpgradeJob(*job)).To(BeTrue())
+	})
+
+	It("copies the binaries from the image of the previous major version", func() {
+		job := CreateMajorUpgradeJob(cluster, 1, "postgres:16.4", 17)
+		initContainers := job.Spec.Template.Spec.InitContainers
+		Expect(initContainers).To(HaveLen(2))
+		Expect(initContainers[0].Name).To(Equal(BootstrapControllerContainerName))
+		Expect(initContainers[1].Name).To(Equal(MajorUpgradePrepareContainerName))
+		Expect(initContainers[1].Image).To(Equal("postgres:16.4"))
+		Expect(initContainers[1].Command).To(ContainElements("upgrade", "prepare"))
+	})
+
+	It("isn't confused with the jobs creating instances", func() {
+		Expect(IsMajorUpgradeJob(*CreatePrimaryJobViaPgBaseBackup(cluster, 1))).To(BeFalse())
+		Expect(IsMajorUpgradeJob(*CreateBackupVerificationJob(cluster))).To(BeFalse())
+	})
 })
@@ -72,6 +72,20 @@ func GetBootstrapControllerImageName(pod corev1.Pod) (string, error) {
 	return GetInitContainerImageName(pod, BootstrapControllerContainerName)
 }
 
+// GetPostgresContainerID gets the ID of the PostgreSQL container of a Pod,
+// or an empty string if its status is not available yet. The kubelet
+// sorts the container statuses by name, so this is not necessarily the
+// first one when sidecars are defined
+func GetPostgresContainerID(pod corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == PostgresContainerName {
+			return status.ContainerID
+		}
+	}
+
+	return ""
+}
+
 // GetContainerImageName get the name of the image used in a container
 func GetContainerImageName(pod corev1.Pod, containerName string) (string, error) {
 	for _, container := range pod.Spec.Containers {
@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -43,3 +44,21 @@ var _ = Describe("Extract the used image name", func() {
 		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current.OperatorImageName))
 	})
 })
+
+var _ = Describe("PostgreSQL container ID", func() {
+	It("finds the status of the PostgreSQL container among the sidecars", func() {
+		pod := corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "auditor", ContainerID: "containerd://auditor"},
+					{Name: PostgresContainerName, ContainerID: "containerd://postgres"},
+				},
+			},
+		}
+		Expect(GetPostgresContainerID(pod)).To(Equal("containerd://postgres"))
+	})
+
+	It("returns an empty string when the status is not available", func() {
+		Expect(GetPostgresContainerID(corev1.Pod{})).To(BeEmpty())
+	})
+})
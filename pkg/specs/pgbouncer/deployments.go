@@ -118,7 +118,7 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 		}, false).
 		Build()
 
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pooler.Name,
 			Namespace: pooler.Namespace,
@@ -147,7 +147,11 @@ func Deployment(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*appsv1.Deploymen
 			},
 			Strategy: getDeploymentStrategy(pooler.Spec.DeploymentStrategy),
 		},
-	}, nil
+	}
+	pooler.SetInheritedData(&deployment.ObjectMeta)
+	pooler.SetInheritedData(&deployment.Spec.Template.ObjectMeta)
+
+	return deployment, nil
 }
 
 func getDeploymentStrategy(strategy *appsv1.DeploymentStrategy) appsv1.DeploymentStrategy {
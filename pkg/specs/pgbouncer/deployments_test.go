@@ -105,6 +105,25 @@ var _ = Describe("Deployment", func() {
 		Expect(podTemplate.Spec.Containers[0].Image).To(Equal(DefaultPgbouncerImage))
 	})
 
+	It("propagates the inherited metadata without overriding the operator labels", func() {
+		pooler.Spec.InheritedMetadata = &apiv1.EmbeddedObjectMetadata{
+			Labels: map[string]string{
+				"team":                   "platform",
+				utils.PgbouncerNameLabel: "another-pooler",
+			},
+			Annotations: map[string]string{"cost-center": "1234"},
+		}
+
+		deployment, err := Deployment(pooler, cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		for _, meta := range []metav1.ObjectMeta{deployment.ObjectMeta, deployment.Spec.Template.ObjectMeta} {
+			Expect(meta.Labels).To(HaveKeyWithValue("team", "platform"))
+			Expect(meta.Labels).To(HaveKeyWithValue(utils.PgbouncerNameLabel, pooler.Name))
+			Expect(meta.Annotations).To(HaveKeyWithValue("cost-center", "1234"))
+		}
+	})
+
 	It("sets the correct number of replicas", func() {
 		pooler.Spec.Instances = ptr.To(int32(3))
 		deployment, err := Deployment(pooler, cluster)
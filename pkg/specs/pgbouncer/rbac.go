@@ -27,9 +27,12 @@ import (
 
 // ServiceAccount creates a service account for a given pooler
 func ServiceAccount(pooler *apiv1.Pooler) *corev1.ServiceAccount {
-	return &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
 		Name: pooler.Name, Namespace: pooler.Namespace,
 	}}
+	pooler.SetInheritedData(&serviceAccount.ObjectMeta)
+
+	return serviceAccount
 }
 
 // Role creates a role for a given pooler
@@ -49,7 +52,7 @@ func Role(pooler *apiv1.Pooler) *v1.Role {
 		}
 	}
 
-	return &v1.Role{ObjectMeta: metav1.ObjectMeta{
+	role := &v1.Role{ObjectMeta: metav1.ObjectMeta{
 		Name: pooler.Name, Namespace: pooler.Namespace,
 	}, Rules: []v1.PolicyRule{
 		{
@@ -98,9 +101,15 @@ func Role(pooler *apiv1.Pooler) *v1.Role {
 			ResourceNames: secretNames,
 		},
 	}}
+	pooler.SetInheritedData(&role.ObjectMeta)
+
+	return role
 }
 
 // RoleBinding creates a role binding for a given pooler
 func RoleBinding(pooler *apiv1.Pooler) v1.RoleBinding {
-	return specs.CreateRoleBinding(pooler.ObjectMeta)
+	roleBinding := specs.CreateRoleBinding(pooler.ObjectMeta)
+	pooler.SetInheritedData(&roleBinding.ObjectMeta)
+
+	return roleBinding
 }
@@ -71,4 +71,21 @@ var _ = Describe("Pooler ServiceAccount, Role, and RoleBinding", func() {
 			Expect(roleBinding.Namespace).To(Equal(pooler.Namespace))
 		})
 	})
+
+	It("propagates the inherited metadata", func() {
+		pooler.Spec.InheritedMetadata = &apiv1.EmbeddedObjectMetadata{
+			Labels:      map[string]string{"team": "platform"},
+			Annotations: map[string]string{"cost-center": "1234"},
+		}
+
+		roleBinding := RoleBinding(pooler)
+		for _, meta := range []metav1.ObjectMeta{
+			ServiceAccount(pooler).ObjectMeta,
+			Role(pooler).ObjectMeta,
+			roleBinding.ObjectMeta,
+		} {
+			Expect(meta.Labels).To(HaveKeyWithValue("team", "platform"))
+			Expect(meta.Annotations).To(HaveKeyWithValue("cost-center", "1234"))
+		}
+	})
 })
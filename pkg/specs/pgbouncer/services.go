@@ -51,7 +51,7 @@ func Service(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*corev1.Service, err
 		SetPGBouncerSelector(pooler.Name).
 		Build()
 
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        pooler.Name,
 			Namespace:   pooler.Namespace,
@@ -59,5 +59,8 @@ func Service(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (*corev1.Service, err
 			Annotations: serviceTemplate.ObjectMeta.Annotations,
 		},
 		Spec: serviceTemplate.Spec,
-	}, nil
+	}
+	pooler.SetInheritedData(&service.ObjectMeta)
+
+	return service, nil
 }
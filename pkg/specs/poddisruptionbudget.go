@@ -26,30 +26,53 @@ import (
 )
 
 // BuildReplicasPodDisruptionBudget creates a pod disruption budget telling
-// K8s to avoid removing more than one replica at a time
+// K8s to avoid removing more than one replica at a time, unless a different
+// policy has been configured in the cluster.
+// Returns nil when the cluster doesn't need a PDB for its replicas.
 func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisruptionBudget {
-	// We should ensure that in a cluster of n instances,
-	// with n-1 replicas, at least n-2 are always available
-	if cluster == nil || cluster.Spec.Instances < 3 {
+	if cluster == nil || cluster.GetInstances() < 2 {
 		return nil
 	}
-	minAvailableReplicas := int32(cluster.Spec.Instances - 2)
-	allReplicasButOne := intstr.FromInt32(minAvailableReplicas)
+
+	var spec policyv1.PodDisruptionBudgetSpec
+	if policy := cluster.GetReplicasPodDisruptionBudgetPolicy(); policy != nil {
+		spec.MinAvailable = policy.MinAvailable
+		spec.MaxUnavailable = policy.MaxUnavailable
+	} else {
+		// We should ensure that in a cluster of n instances,
+		// with n-1 replicas, at least n-2 are always available.
+		if cluster.GetInstances() < 3 {
+			return nil
+		}
+		allReplicasButOne := intstr.FromInt32(int32(cluster.GetInstances() - 2))
+		spec.MinAvailable = &allReplicasButOne
+	}
+	spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			utils.ClusterLabelName:     cluster.Name,
+			utils.ClusterRoleLabelName: ClusterRoleLabelReplica,
+		},
+	}
+	if delayedReplicas := cluster.Spec.PostgresConfiguration.DelayedReplicas; delayedReplicas != nil &&
+		len(delayedReplicas.Instances) > 0 {
+		// The delayed replicas have a different role label,
+		// but they are covered by this PDB as well
+		delete(spec.Selector.MatchLabels, utils.ClusterRoleLabelName)
+		spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{
+			{
+				Key:      utils.ClusterRoleLabelName,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{ClusterRoleLabelReplica, ClusterRoleLabelDelayedReplica},
+			},
+		}
+	}
 
 	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cluster.Name,
 			Namespace: cluster.Namespace,
 		},
-		Spec: policyv1.PodDisruptionBudgetSpec{
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					utils.ClusterLabelName:     cluster.Name,
-					utils.ClusterRoleLabelName: ClusterRoleLabelReplica,
-				},
-			},
-			MinAvailable: &allReplicasButOne,
-		},
+		Spec: spec,
 	}
 
 	cluster.SetInheritedDataAndOwnership(&pdb.ObjectMeta)
@@ -58,11 +81,13 @@ func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisru
 }
 
 // BuildPrimaryPodDisruptionBudget creates a pod disruption budget, telling
-// K8s to avoid removing more than one primary instance at a time
+// K8s to avoid removing the primary instance, unless a different policy
+// has been configured in the cluster
 func BuildPrimaryPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisruptionBudget {
 	if cluster == nil {
 		return nil
 	}
+
 	one := intstr.FromInt32(1)
 
 	pdb := &policyv1.PodDisruptionBudget{
@@ -81,6 +106,11 @@ func BuildPrimaryPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisrup
 		},
 	}
 
+	if policy := cluster.GetPrimaryPodDisruptionBudgetPolicy(); policy != nil {
+		pdb.Spec.MinAvailable = policy.MinAvailable
+		pdb.Spec.MaxUnavailable = policy.MaxUnavailable
+	}
+
 	cluster.SetInheritedDataAndOwnership(&pdb.ObjectMeta)
 
 	return pdb
@@ -18,8 +18,10 @@ package specs
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -53,4 +55,66 @@ var _ = Describe("POD Disruption Budget specifications", func() {
 		result := BuildPrimaryPodDisruptionBudget(cluster)
 		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(minAvailablePrimary)))
 	})
+
+	It("is not generated for the replicas of clusters with less than three instances", func() {
+		smallCluster := cluster.DeepCopy()
+		smallCluster.Spec.Instances = 2
+		Expect(BuildReplicasPodDisruptionBudget(smallCluster)).To(BeNil())
+	})
+
+	It("covers the delayed replicas too", func() {
+		delayedCluster := cluster.DeepCopy()
+		delayedCluster.Spec.Instances = 4
+		delayedCluster.Spec.PostgresConfiguration.DelayedReplicas = &apiv1.DelayedReplicasConfiguration{
+			Instances:     []string{"thistest-4"},
+			MinApplyDelay: "1h",
+		}
+		result := BuildReplicasPodDisruptionBudget(delayedCluster)
+		Expect(result.Spec.MinAvailable.IntVal).To(Equal(int32(2)))
+		Expect(result.Spec.Selector.MatchLabels).To(Equal(map[string]string{
+			utils.ClusterLabelName: "thistest",
+		}))
+		Expect(result.Spec.Selector.MatchExpressions).To(ConsistOf(metav1.LabelSelectorRequirement{
+			Key:      utils.ClusterRoleLabelName,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{ClusterRoleLabelReplica, ClusterRoleLabelDelayedReplica},
+		}))
+
+		delayedCluster.Spec.Instances = 3
+		Expect(BuildReplicasPodDisruptionBudget(delayedCluster).Spec.MinAvailable.IntVal).To(Equal(int32(1)))
+	})
+
+	Context("with custom policies", func() {
+		var customCluster *apiv1.Cluster
+
+		BeforeEach(func() {
+			maxUnavailable := intstr.FromString("50%")
+			minAvailable := intstr.FromInt32(0)
+			customCluster = cluster.DeepCopy()
+			customCluster.Spec.PodDisruptionBudget = &apiv1.PodDisruptionBudgetConfiguration{
+				Primary:  &apiv1.PodDisruptionBudgetPolicy{MinAvailable: &minAvailable},
+				Replicas: &apiv1.PodDisruptionBudgetPolicy{MaxUnavailable: &maxUnavailable},
+			}
+		})
+
+		It("uses the policy of the primary instance", func() {
+			result := BuildPrimaryPodDisruptionBudget(customCluster)
+			Expect(result.Spec.MinAvailable.IntVal).To(BeZero())
+			Expect(result.Spec.MaxUnavailable).To(BeNil())
+		})
+
+		It("uses the policy of the replicas", func() {
+			result := BuildReplicasPodDisruptionBudget(customCluster)
+			Expect(result.Spec.MinAvailable).To(BeNil())
+			Expect(result.Spec.MaxUnavailable.StrVal).To(Equal("50%"))
+		})
+
+		It("uses the policy of the replicas also in clusters with two instances", func() {
+			customCluster.Spec.Instances = 2
+			Expect(BuildReplicasPodDisruptionBudget(customCluster)).ToNot(BeNil())
+
+			customCluster.Spec.Instances = 1
+			Expect(BuildReplicasPodDisruptionBudget(customCluster)).To(BeNil())
+		})
+	})
 })
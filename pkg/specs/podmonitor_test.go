@@ -70,6 +70,14 @@ var _ = Describe("PodMonitor test", func() {
 		Expect(monitor.Spec.PodMetricsEndpoints).To(ContainElement(expectedEndpoint))
 	})
 
+	It("doesn't select the pods of the poolers", func() {
+		mgr := NewClusterPodMonitorManager(cluster.DeepCopy())
+		monitor := mgr.BuildPodMonitor()
+		Expect(monitor.Spec.Selector.MatchLabels).To(
+			HaveKeyWithValue(utils.PodRoleLabelName, string(utils.PodRoleInstance)))
+		Expect(monitor.Labels).ToNot(HaveKey(utils.PodRoleLabelName))
+	})
+
 	It("should create a monitoringv1.PodMonitor object with MetricRelabelConfigs rules", func() {
 		relabeledCluster := cluster.DeepCopy()
 		relabeledCluster.Spec.Monitoring.PodMonitorMetricRelabelConfigs = metricRelabelings
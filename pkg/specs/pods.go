@@ -64,6 +64,11 @@ const (
 	// ClusterRoleLabelReplica is written in labels to represent replica servers
 	ClusterRoleLabelReplica = "replica"
 
+	// ClusterRoleLabelDelayedReplica is written in labels to represent
+	// replica servers applying the changes with a delay, which are
+	// excluded from the read-only service
+	ClusterRoleLabelDelayedReplica = "delayed-replica"
+
 	// PostgresContainerName is the name of the container executing PostgreSQL
 	// inside one Pod
 	PostgresContainerName = "postgres"
@@ -72,6 +77,11 @@ const (
 	// controller inside the Pod file system
 	BootstrapControllerContainerName = "bootstrap-controller"
 
+	// MajorUpgradePrepareContainerName is the name of the container copying
+	// the binaries of the previous PostgreSQL major version inside the Pod
+	// file system, to be used by pg_upgrade
+	MajorUpgradePrepareContainerName = "prepare-major-upgrade"
+
 	// PgDataPath is the path to PGDATA variable
 	PgDataPath = "/var/lib/postgresql/data/pgdata"
 
@@ -161,7 +171,7 @@ func CreateClusterPodSpec(
 	gracePeriod int64,
 	enableHTTPS bool,
 ) corev1.PodSpec {
-	return corev1.PodSpec{
+	podSpec := corev1.PodSpec{
 		Hostname: podName,
 		InitContainers: []corev1.Container{
 			createBootstrapContainer(cluster),
@@ -180,6 +190,54 @@ func CreateClusterPodSpec(
 		TerminationGracePeriodSeconds: &gracePeriod,
 		TopologySpreadConstraints:     cluster.Spec.TopologySpreadConstraints,
 	}
+
+	// the PostgreSQL container is always the first one
+	podSpec.Containers = append(podSpec.Containers, createSidecarContainers(cluster)...)
+
+	applyInstancePlacement(&podSpec, cluster, cluster.GetInstancePlacement(podName))
+	return podSpec
+}
+
+// applyInstancePlacement replaces the placement rules of the pod with the
+// ones defined for the role of the instance
+func applyInstancePlacement(podSpec *corev1.PodSpec, cluster apiv1.Cluster, placement *apiv1.InstancePlacement) {
+	if placement == nil {
+		return
+	}
+
+	if placement.NodeSelector != nil {
+		podSpec.NodeSelector = placement.NodeSelector
+	}
+	if placement.Tolerations != nil {
+		podSpec.Tolerations = placement.Tolerations
+	}
+	if placement.TopologySpreadConstraints != nil {
+		podSpec.TopologySpreadConstraints = placement.TopologySpreadConstraints
+	}
+	if placement.NodeAffinity != nil {
+		affinityConfiguration := cluster.Spec.Affinity
+		affinityConfiguration.NodeAffinity = placement.NodeAffinity
+		podSpec.Affinity = CreateAffinitySection(cluster.Name, affinityConfiguration)
+	}
+}
+
+// createSidecarContainers creates the sidecar containers defined by the
+// user, applying the security context of the PostgreSQL container to
+// the ones not defining it
+func createSidecarContainers(cluster apiv1.Cluster) []corev1.Container {
+	if len(cluster.Spec.Sidecars) == 0 {
+		return nil
+	}
+
+	containers := make([]corev1.Container, len(cluster.Spec.Sidecars))
+	for i := range cluster.Spec.Sidecars {
+		cluster.Spec.Sidecars[i].DeepCopyInto(&containers[i])
+		if containers[i].SecurityContext == nil {
+			containers[i].SecurityContext = CreateContainerSecurityContext(cluster.GetSeccompProfile())
+		}
+	}
+
+	return containers
 }
 
 // createPostgresContainers create the PostgreSQL containers that are
@@ -263,6 +321,8 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig, enable
 	// if user customizes the liveness probe timeout, we need to adjust the failure threshold
 	addLivenessProbeFailureThreshold(cluster, &containers[0])
 
+	configureStartupProbe(cluster, containers[0].StartupProbe)
+
 	return containers
 }
 
@@ -274,13 +334,40 @@ func addLivenessProbeFailureThreshold(cluster apiv1.Cluster, container *corev1.C
 	}
 }
 
+// configureStartupProbe applies the startup probe configuration of the
+// user. When only the period is set, the failure threshold is derived
+// from `startDelay` as usual
+func configureStartupProbe(cluster apiv1.Cluster, probe *corev1.Probe) {
+	configuration := cluster.Spec.Probes.GetStartup()
+	if configuration == nil {
+		return
+	}
+
+	if configuration.PeriodSeconds > 0 {
+		probe.PeriodSeconds = configuration.PeriodSeconds
+		probe.FailureThreshold = getProbeFailureThreshold(cluster.GetMaxStartDelay(), configuration.PeriodSeconds)
+	}
+	if configuration.FailureThreshold > 0 {
+		probe.FailureThreshold = configuration.FailureThreshold
+	}
+	if configuration.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = configuration.TimeoutSeconds
+	}
+}
+
 // getStartupProbeFailureThreshold get the startup probe failure threshold
 // FAILURE_THRESHOLD = ceil(startDelay / periodSeconds) and minimum value is 1
 func getStartupProbeFailureThreshold(startupDelay int32) int32 {
-	if startupDelay <= StartupProbePeriod {
+	return getProbeFailureThreshold(startupDelay, StartupProbePeriod)
+}
+
+// getProbeFailureThreshold get the failure threshold of a probe performed
+// every period seconds, to allow for the passed delay. The minimum value is 1
+func getProbeFailureThreshold(delay int32, period int32) int32 {
+	if delay <= period {
 		return 1
 	}
-	return int32(math.Ceil(float64(startupDelay) / float64(StartupProbePeriod)))
+	return int32(math.Ceil(float64(delay) / float64(period)))
 }
 
 // getLivenessProbeFailureThreshold get the liveness probe failure threshold
@@ -433,6 +520,10 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		pod.Annotations[utils.PodSpecAnnotationName] = string(podSpecMarshaled)
 	}
 
+	if len(cluster.Spec.Sidecars) > 0 {
+		pod.Annotations[utils.DefaultContainerAnnotationName] = PostgresContainerName
+	}
+
 	if cluster.Spec.PriorityClassName != "" {
 		pod.Spec.PriorityClassName = cluster.Spec.PriorityClassName
 	}
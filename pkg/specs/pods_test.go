@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -702,6 +703,29 @@ var _ = Describe("PodSpec drift detection", func() {
 		Expect(specsMatch).To(BeFalse())
 	})
 
+	It("detects difference in the startup probe", func() {
+		podSpec1 := corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:         "postgres",
+					StartupProbe: &corev1.Probe{PeriodSeconds: 10},
+				},
+			},
+		}
+		podSpec2 := corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:         "postgres",
+					StartupProbe: &corev1.Probe{PeriodSeconds: 30},
+				},
+			},
+		}
+
+		specsMatch, diff := ComparePodSpecs(podSpec1, podSpec2)
+		Expect(diff).To(ContainSubstring("startup-probe"))
+		Expect(specsMatch).To(BeFalse())
+	})
+
 	It("detects difference in generic field", func() {
 		podSpec1 := corev1.PodSpec{
 			ServiceAccountName: "foo",
@@ -930,6 +954,45 @@ var _ = Describe("Compute startup probe failure threshold", func() {
 	})
 })
 
+var _ = Describe("Startup probe configuration", func() {
+	getStartupProbe := func(cluster v1.Cluster) *corev1.Probe {
+		return createPostgresContainers(cluster, EnvConfig{}, false)[0].StartupProbe
+	}
+
+	It("derives the failure threshold from startDelay by default", func() {
+		probe := getStartupProbe(v1.Cluster{Spec: v1.ClusterSpec{MaxStartDelay: 600}})
+		Expect(probe.PeriodSeconds).To(BeEquivalentTo(StartupProbePeriod))
+		Expect(probe.FailureThreshold).To(BeEquivalentTo(60))
+	})
+
+	It("derives the failure threshold from startDelay and the configured period", func() {
+		probe := getStartupProbe(v1.Cluster{Spec: v1.ClusterSpec{
+			MaxStartDelay: 600,
+			Probes: &v1.ProbesConfiguration{
+				Startup: &v1.ProbeConfiguration{PeriodSeconds: 30},
+			},
+		}})
+		Expect(probe.PeriodSeconds).To(BeEquivalentTo(30))
+		Expect(probe.FailureThreshold).To(BeEquivalentTo(20))
+	})
+
+	It("uses the configured failure threshold and timeout", func() {
+		probe := getStartupProbe(v1.Cluster{Spec: v1.ClusterSpec{
+			MaxStartDelay: 600,
+			Probes: &v1.ProbesConfiguration{
+				Startup: &v1.ProbeConfiguration{
+					PeriodSeconds:    30,
+					FailureThreshold: 1000,
+					TimeoutSeconds:   15,
+				},
+			},
+		}})
+		Expect(probe.PeriodSeconds).To(BeEquivalentTo(30))
+		Expect(probe.FailureThreshold).To(BeEquivalentTo(1000))
+		Expect(probe.TimeoutSeconds).To(BeEquivalentTo(15))
+	})
+})
+
 var _ = Describe("Compute liveness probe failure threshold", func() {
 	It("should take the minimum value 1", func() {
 		Expect(getLivenessProbeFailureThreshold(5)).To(BeNumerically("==", 1))
@@ -939,3 +1002,125 @@ var _ = Describe("Compute liveness probe failure threshold", func() {
 		Expect(getLivenessProbeFailureThreshold(31)).To(BeNumerically("==", 4))
 	})
 })
+
+var _ = Describe("Role placement", func() {
+	var cluster v1.Cluster
+
+	BeforeEach(func() {
+		cluster = v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: v1.ClusterSpec{
+				Affinity: v1.AffinityConfiguration{
+					NodeSelector: map[string]string{"workload": "postgres"},
+				},
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"},
+				},
+				RolePlacement: &v1.RolePlacementConfiguration{
+					Primary: &v1.InstancePlacement{
+						NodeSelector: map[string]string{"pool": "primary"},
+						NodeAffinity: &corev1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{},
+						},
+					},
+					Replica: &v1.InstancePlacement{
+						TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+							{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname"},
+						},
+					},
+				},
+			},
+			Status: v1.ClusterStatus{
+				TargetPrimary: "cluster-example-1",
+			},
+		}
+	})
+
+	It("applies the rules of the primary to its pod", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{"pool": "primary"}))
+		Expect(pod.Spec.Affinity.NodeAffinity).To(Equal(cluster.Spec.RolePlacement.Primary.NodeAffinity))
+		Expect(pod.Spec.TopologySpreadConstraints).To(Equal(cluster.Spec.TopologySpreadConstraints))
+	})
+
+	It("applies the rules of the replicas to their pods", func() {
+		pod := PodWithExistingStorage(cluster, 2)
+		Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{"workload": "postgres"}))
+		Expect(pod.Spec.Affinity).To(BeNil())
+		Expect(pod.Spec.TopologySpreadConstraints).To(
+			Equal(cluster.Spec.RolePlacement.Replica.TopologySpreadConstraints))
+	})
+
+	It("applies the rules of the primary while bootstrapping the cluster", func() {
+		cluster.Status.TargetPrimary = ""
+		cluster.Spec.Bootstrap = &v1.BootstrapConfiguration{InitDB: &v1.BootstrapInitDB{}}
+		job := CreatePrimaryJobViaInitdb(cluster, 1)
+		Expect(job.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"pool": "primary"}))
+	})
+
+	It("applies the rules of the replicas to the jobs joining the cluster", func() {
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.TopologySpreadConstraints).To(
+			Equal(cluster.Spec.RolePlacement.Replica.TopologySpreadConstraints))
+	})
+})
+
+var _ = Describe("Sidecar containers", func() {
+	var cluster v1.Cluster
+
+	BeforeEach(func() {
+		cluster = v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: v1.ClusterSpec{
+				Sidecars: []corev1.Container{
+					{
+						Name:  "log-shipper",
+						Image: "example.com/log-shipper:1.0",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "pgdata", MountPath: "/pgdata", ReadOnly: true},
+						},
+					},
+					{
+						Name:            "auditor",
+						Image:           "example.com/auditor:1.0",
+						SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(false)},
+					},
+				},
+			},
+		}
+	})
+
+	It("doesn't add any container when no sidecar is defined", func() {
+		cluster.Spec.Sidecars = nil
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Annotations).ToNot(HaveKey(utils.DefaultContainerAnnotationName))
+	})
+
+	It("adds the sidecars after the PostgreSQL container", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(3))
+		Expect(pod.Spec.Containers[0].Name).To(Equal(PostgresContainerName))
+		Expect(pod.Spec.Containers[1].Name).To(Equal("log-shipper"))
+		Expect(pod.Spec.Containers[1].VolumeMounts).To(Equal(cluster.Spec.Sidecars[0].VolumeMounts))
+		Expect(pod.Spec.Containers[2].Name).To(Equal("auditor"))
+		Expect(pod.Annotations).To(HaveKeyWithValue(utils.DefaultContainerAnnotationName, PostgresContainerName))
+	})
+
+	It("applies the security context of PostgreSQL to the sidecars without one", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers[1].SecurityContext).To(Equal(pod.Spec.Containers[0].SecurityContext))
+		Expect(pod.Spec.Containers[2].SecurityContext).To(Equal(cluster.Spec.Sidecars[1].SecurityContext))
+		Expect(cluster.Spec.Sidecars[0].SecurityContext).To(BeNil())
+	})
+
+	It("detects the drift when a sidecar is changed", func() {
+		currentPodSpec := PodWithExistingStorage(cluster, 1).Spec
+		cluster.Spec.Sidecars[0].Image = "example.com/log-shipper:1.1"
+		targetPodSpec := PodWithExistingStorage(cluster, 1).Spec
+
+		match, diff := ComparePodSpecs(currentPodSpec, targetPodSpec)
+		Expect(match).To(BeFalse())
+		Expect(diff).To(Equal("containers: container log-shipper differs in image"))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+	"regexp"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// defaultAlert is one of the alerts the operator creates for every
+// cluster having the PrometheusRule enabled
+type defaultAlert struct {
+	name        apiv1.PrometheusAlertName
+	expr        func(instances string, volumes string) string
+	duration    monitoringv1.Duration
+	severity    string
+	summary     string
+	description string
+}
+
+// defaultAlerts is the list of the default alerts of a cluster. The expression
+// receives the PromQL selectors of the instances and of their volumes
+var defaultAlerts = []defaultAlert{
+	{
+		name: apiv1.PrometheusAlertReplicationLag,
+		expr: func(instances string, _ string) string {
+			return fmt.Sprintf("cnpg_pg_replication_lag{%s} > 300", instances)
+		},
+		duration:    "5m",
+		severity:    "warning",
+		summary:     "A replica is lagging behind the primary",
+		description: "Replica {{ $labels.pod }} is lagging behind the primary by more than 5 minutes",
+	},
+	{
+		name: apiv1.PrometheusAlertArchiveFailing,
+		expr: func(instances string, _ string) string {
+			return fmt.Sprintf(
+				"cnpg_pg_stat_archiver_last_failed_time{%s} > cnpg_pg_stat_archiver_last_archived_time{%s}",
+				instances, instances)
+		},
+		duration:    "5m",
+		severity:    "critical",
+		summary:     "The WAL files can't be archived",
+		description: "Instance {{ $labels.pod }} is failing to archive the WAL files",
+	},
+	{
+		name: apiv1.PrometheusAlertDiskPressure,
+		expr: func(_ string, volumes string) string {
+			return fmt.Sprintf(
+				"kubelet_volume_stats_available_bytes{%s} / kubelet_volume_stats_capacity_bytes{%s} < 0.1",
+				volumes, volumes)
+		},
+		duration:    "5m",
+		severity:    "warning",
+		summary:     "A volume of an instance is running out of space",
+		description: "Volume {{ $labels.persistentvolumeclaim }} has less than 10% of free space",
+	},
+}
+
+// BuildPrometheusRule builds the PrometheusRule containing the default alerts
+// of the cluster, with the customizations of the user
+func BuildPrometheusRule(cluster *apiv1.Cluster) *monitoringv1.PrometheusRule {
+	meta := metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+	cluster.SetInheritedDataAndOwnership(&meta)
+
+	// Only match the instances, and their volumes, of this cluster:
+	// the pods of the poolers have a different name
+	clusterName := regexp.QuoteMeta(cluster.Name)
+	instances := fmt.Sprintf(`namespace=%q,pod=~"%s-[0-9]+"`, cluster.Namespace, clusterName)
+	volumes := fmt.Sprintf(`namespace=%q,persistentvolumeclaim=~"%s-[0-9]+(-wal|-tbs-.+)?"`,
+		cluster.Namespace, clusterName)
+
+	var configuration *apiv1.PrometheusRuleConfiguration
+	if cluster.Spec.Monitoring != nil {
+		configuration = cluster.Spec.Monitoring.PrometheusRule
+	}
+
+	rules := make([]monitoringv1.Rule, 0, len(defaultAlerts))
+	for _, alert := range defaultAlerts {
+		duration := alert.duration
+		rule := monitoringv1.Rule{
+			Alert: string(alert.name),
+			Expr:  intstr.FromString(alert.expr(instances, volumes)),
+			For:   &duration,
+			Labels: map[string]string{
+				"severity": alert.severity,
+			},
+			Annotations: map[string]string{
+				"summary":     alert.summary,
+				"description": alert.description,
+			},
+		}
+
+		if customization := configuration.GetAlert(alert.name); customization != nil {
+			if customization.Disabled {
+				continue
+			}
+			applyAlertCustomization(&rule, customization)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: meta,
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name:  fmt.Sprintf("cnpg-%s.rules", cluster.Name),
+					Rules: rules,
+				},
+			},
+		},
+	}
+}
+
+// applyAlertCustomization overrides the default configuration of an alert
+func applyAlertCustomization(rule *monitoringv1.Rule, customization *apiv1.PrometheusAlertConfiguration) {
+	if customization.Expr != "" {
+		rule.Expr = intstr.FromString(customization.Expr)
+	}
+	if customization.For != nil {
+		duration := *customization.For
+		rule.For = &duration
+	}
+	for key, value := range customization.Labels {
+		rule.Labels[key] = value
+	}
+	for key, value := range customization.Annotations {
+		rule.Annotations[key] = value
+	}
+	if customization.Severity != "" {
+		rule.Labels["severity"] = customization.Severity
+	}
+}
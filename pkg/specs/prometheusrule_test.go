@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrometheusRule", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "cluster.example",
+			},
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					PrometheusRule: &apiv1.PrometheusRuleConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+		}
+	})
+
+	findRule := func(rule *monitoringv1.PrometheusRule, name apiv1.PrometheusAlertName) *monitoringv1.Rule {
+		for i := range rule.Spec.Groups[0].Rules {
+			if rule.Spec.Groups[0].Rules[i].Alert == string(name) {
+				return &rule.Spec.Groups[0].Rules[i]
+			}
+		}
+		return nil
+	}
+
+	It("contains the default alerts scoped to the cluster", func() {
+		rule := BuildPrometheusRule(cluster)
+		Expect(rule.Name).To(Equal(cluster.Name))
+		Expect(rule.Namespace).To(Equal(cluster.Namespace))
+		Expect(rule.Labels[utils.ClusterLabelName]).To(Equal(cluster.Name))
+		Expect(rule.Spec.Groups).To(HaveLen(1))
+		Expect(rule.Spec.Groups[0].Rules).To(HaveLen(3))
+
+		lag := findRule(rule, apiv1.PrometheusAlertReplicationLag)
+		Expect(lag).ToNot(BeNil())
+		Expect(lag.Expr.String()).To(Equal(
+			`cnpg_pg_replication_lag{namespace="test-namespace",pod=~"cluster\.example-[0-9]+"} > 300`))
+		Expect(lag.Labels).To(HaveKeyWithValue("severity", "warning"))
+		Expect(*lag.For).To(BeEquivalentTo("5m"))
+
+		diskPressure := findRule(rule, apiv1.PrometheusAlertDiskPressure)
+		Expect(diskPressure).ToNot(BeNil())
+		Expect(diskPressure.Expr.String()).To(ContainSubstring(
+			`persistentvolumeclaim=~"cluster\.example-[0-9]+(-wal|-tbs-.+)?"`))
+
+		Expect(findRule(rule, apiv1.PrometheusAlertArchiveFailing)).ToNot(BeNil())
+	})
+
+	It("applies the customizations of the alerts", func() {
+		duration := monitoringv1.Duration("10m")
+		cluster.Spec.Monitoring.PrometheusRule.Alerts = []apiv1.PrometheusAlertConfiguration{
+			{
+				Name:        apiv1.PrometheusAlertReplicationLag,
+				Expr:        "cnpg_pg_replication_lag > 60",
+				For:         &duration,
+				Severity:    "critical",
+				Labels:      map[string]string{"team": "dba"},
+				Annotations: map[string]string{"runbook_url": "https://example.com/lag"},
+			},
+			{
+				Name:     apiv1.PrometheusAlertArchiveFailing,
+				Disabled: true,
+			},
+		}
+
+		rule := BuildPrometheusRule(cluster)
+		Expect(rule.Spec.Groups[0].Rules).To(HaveLen(2))
+		Expect(findRule(rule, apiv1.PrometheusAlertArchiveFailing)).To(BeNil())
+
+		lag := findRule(rule, apiv1.PrometheusAlertReplicationLag)
+		Expect(lag.Expr.String()).To(Equal("cnpg_pg_replication_lag > 60"))
+		Expect(*lag.For).To(Equal(duration))
+		Expect(lag.Labels).To(Equal(map[string]string{"severity": "critical", "team": "dba"}))
+		Expect(lag.Annotations).To(HaveKeyWithValue("runbook_url", "https://example.com/lag"))
+		Expect(lag.Annotations).To(HaveKey("summary"))
+	})
+})
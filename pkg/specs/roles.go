@@ -26,8 +26,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
-// CreateRole create a role with the permissions needed by the instance manager
-func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
+// CreateRole create a role with the permissions needed by the instance manager.
+// The passed databases are the ones of the cluster, whose foreign servers
+// may use credentials stored in secrets
+func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup, databases []apiv1.Database) rbacv1.Role {
 	rules := []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -53,7 +55,7 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"get",
 				"watch",
 			},
-			ResourceNames: getInvolvedSecretNames(cluster, backupOrigin),
+			ResourceNames: getInvolvedSecretNames(cluster, backupOrigin, databases),
 		},
 		{
 			APIGroups: []string{
@@ -114,6 +116,60 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"update",
 			},
 		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"databases",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"databases/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"publications",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+			},
+		},
+		{
+			APIGroups: []string{
+				"postgresql.cnpg.io",
+			},
+			Resources: []string{
+				"publications/status",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+				"update",
+			},
+		},
 		{
 			APIGroups: []string{
 				"",
@@ -126,6 +182,20 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"patch",
 			},
 		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"endpoints",
+			},
+			Verbs: []string{
+				"get",
+			},
+			ResourceNames: []string{
+				cluster.GetServiceReadWriteName(),
+			},
+		},
 	}
 
 	return rbacv1.Role{
@@ -137,7 +207,11 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	}
 }
 
-func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
+func getInvolvedSecretNames(
+	cluster apiv1.Cluster,
+	backupOrigin *apiv1.Backup,
+	databases []apiv1.Database,
+) []string {
 	involvedSecretNames := []string{
 		cluster.GetReplicationSecretName(),
 		cluster.GetClientCASecretName(),
@@ -157,6 +231,11 @@ func getInvolvedSecretNames(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) [
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(cluster)...)
+	for idx := range databases {
+		involvedSecretNames = append(involvedSecretNames, databases[idx].GetCredentialsSecretNames()...)
+	}
+	involvedSecretNames = append(involvedSecretNames,
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication.GetAdditionalClientCASecretNames()...)
 
 	return cleanupResourceList(involvedSecretNames)
 }
@@ -189,7 +268,14 @@ func cleanupResourceList(resourceList []string) []string {
 func externalClusterSecrets(cluster apiv1.Cluster) []string {
 	var result []string
 
-	for _, server := range cluster.Spec.ExternalClusters {
+	servers := cluster.Spec.ExternalClusters
+	if cluster.ShouldImportFromBackup() {
+		// The import job connects to the temporary cluster
+		// where the base backup has been recovered
+		servers = append(slices.Clone(servers), cluster.GetImportSourceExternalCluster())
+	}
+
+	for _, server := range servers {
 		if server.SSLCert != nil {
 			result = append(result,
 				server.SSLCert.Name)
@@ -206,7 +292,13 @@ func externalClusterSecrets(cluster apiv1.Cluster) []string {
 			result = append(result,
 				server.Password.Name)
 		}
-		if barmanObjStore := server.BarmanObjectStore; barmanObjStore != nil {
+		for _, barmanObjStore := range []*apiv1.BarmanObjectStoreConfiguration{
+			server.BarmanObjectStore,
+			server.ArchiveObjectStore,
+		} {
+			if barmanObjStore == nil {
+				continue
+			}
 			result = append(
 				result,
 				s3CredentialsSecrets(barmanObjStore.BarmanCredentials.AWS)...)
@@ -239,6 +331,18 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 		result = append(
 			result,
 			googleCredentialsSecrets(cluster.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google)...)
+
+		for _, destination := range cluster.Spec.Backup.AdditionalWalDestinations {
+			result = append(
+				result,
+				s3CredentialsSecrets(destination.BarmanCredentials.AWS)...)
+			result = append(
+				result,
+				azureCredentialsSecrets(destination.BarmanCredentials.Azure)...)
+			result = append(
+				result,
+				googleCredentialsSecrets(destination.BarmanCredentials.Google)...)
+		}
 	}
 
 	// Secrets needed by Barman, if set
@@ -162,14 +162,14 @@ var _ = Describe("Roles", func() {
 	}
 
 	It("are created with the cluster name for pure k8s", func() {
-		serviceAccount := CreateRole(cluster, nil)
+		serviceAccount := CreateRole(cluster, nil, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules).To(HaveLen(7))
+		Expect(serviceAccount.Rules).To(HaveLen(12))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
-		serviceAccount := CreateRole(cluster, &backupOrigin)
+		serviceAccount := CreateRole(cluster, &backupOrigin, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
 		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf("thisTest", "testConfigMapKeySelector"))
@@ -277,8 +277,39 @@ var _ = Describe("Secrets", func() {
 		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-region", "test-session", "test-endpoint-ca-name"))
 	})
 
+	It("includes the secrets of the additional WAL destinations", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						Google: &apiv1.GoogleCredentials{
+							ApplicationCredentials: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "test-gcs"},
+							},
+						},
+					},
+				},
+				AdditionalWalDestinations: []apiv1.WalArchiveDestination{
+					{
+						Name: "offsite",
+						BarmanObjectStoreConfiguration: apiv1.BarmanObjectStoreConfiguration{
+							BarmanCredentials: apiv1.BarmanCredentials{
+								Azure: &apiv1.AzureCredentials{
+									ConnectionString: &apiv1.SecretKeySelector{
+										LocalObjectReference: apiv1.LocalObjectReference{Name: "test-azure"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(backupSecrets(cluster, nil)).To(ConsistOf("test-gcs", "test-azure"))
+	})
+
 	It("should contain default secrets only", func() {
-		Expect(getInvolvedSecretNames(cluster, nil)).To(Equal([]string{
+		Expect(getInvolvedSecretNames(cluster, nil, nil)).To(Equal([]string{
 			"thisTest-app",
 			"thisTest-ca",
 			"thisTest-replication",
@@ -288,7 +319,7 @@ var _ = Describe("Secrets", func() {
 	})
 
 	It("should created an ordered string list with the backup secrets", func() {
-		Expect(getInvolvedSecretNames(cluster, &backup)).To(Equal([]string{
+		Expect(getInvolvedSecretNames(cluster, &backup, nil)).To(Equal([]string{
 			"aws-status-secret-test",
 			"azure-storage-key-secret-test",
 			"google-application-secret-test",
@@ -299,6 +330,85 @@ var _ = Describe("Secrets", func() {
 			"thisTest-superuser",
 		}))
 	})
+
+	It("includes the superuser secret of the cluster used to import from a base backup", func() {
+		importCluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "thisTest"},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Import: &apiv1.Import{
+							Type:      apiv1.MicroserviceSnapshotType,
+							Databases: []string{"app"},
+							Source: apiv1.ImportSource{
+								ExternalCluster: "origin",
+								FromBackup:      &apiv1.ImportFromBackup{},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(externalClusterSecrets(importCluster)).To(Equal([]string{"thisTest-import-source-superuser"}))
+	})
+
+	It("includes the secrets of the archive tier of the external clusters", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			ExternalClusters: []apiv1.ExternalCluster{
+				{
+					Name: "origin",
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						BarmanCredentials: apiv1.BarmanCredentials{
+							Google: &apiv1.GoogleCredentials{
+								ApplicationCredentials: &apiv1.SecretKeySelector{
+									LocalObjectReference: apiv1.LocalObjectReference{Name: "test-gcs"},
+								},
+							},
+						},
+					},
+					ArchiveObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						BarmanCredentials: apiv1.BarmanCredentials{
+							Azure: &apiv1.AzureCredentials{
+								ConnectionString: &apiv1.SecretKeySelector{
+									LocalObjectReference: apiv1.LocalObjectReference{Name: "test-azure"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(externalClusterSecrets(cluster)).To(ConsistOf("test-gcs", "test-azure"))
+	})
+
+	It("includes the additional client CA secrets", func() {
+		cluster.Spec.PostgresConfiguration.CertificateAuthentication =
+			&apiv1.CertificateAuthenticationConfiguration{
+				Mappings:                  []apiv1.CertificateMapping{{CommonName: "app", Role: "app"}},
+				AdditionalClientCASecrets: []apiv1.LocalObjectReference{{Name: "previous-client-ca"}},
+			}
+		Expect(getInvolvedSecretNames(cluster, nil, nil)).To(ContainElement("previous-client-ca"))
+	})
+
+	It("includes the credentials secrets of the user mappings of the databases", func() {
+		databases := []apiv1.Database{
+			{
+				Spec: apiv1.DatabaseSpec{
+					Servers: []apiv1.ServerSpec{
+						{
+							Name:               "remote",
+							ForeignDataWrapper: "postgres_fdw",
+							UserMappings: []apiv1.UserMappingSpec{
+								{User: "app", CredentialsSecret: &apiv1.LocalObjectReference{Name: "remote-app"}},
+								{User: "PUBLIC"},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(getInvolvedSecretNames(cluster, nil, databases)).To(ContainElement("remote-app"))
+	})
 })
 
 var _ = Describe("Managed Roles", func() {
@@ -343,7 +453,7 @@ var _ = Describe("Managed Roles", func() {
 	It("gets the list of secrets needed by the managed roles", func() {
 		Expect(managedRolesSecrets(cluster)).
 			To(ConsistOf("my_secret1", "my_secret3"))
-		serviceAccount := CreateRole(cluster, nil)
+		serviceAccount := CreateRole(cluster, nil, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
 		var secretsPolicy v1.PolicyRule
@@ -170,12 +170,30 @@ func createPostgresVolumes(cluster *apiv1.Cluster, podName string) []corev1.Volu
 	if cluster.ShouldCreateProjectedVolume() {
 		result = append(result, createProjectedVolume(cluster))
 	}
+
+	if cluster.ShouldMountWalRestoreCache() {
+		result = append(result,
+			corev1.Volume{
+				Name: "wal-restore-cache",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: cluster.Spec.WalRestoreCache.ClaimName,
+					},
+				},
+			})
+	}
 	return result
 }
 
-func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
-	refs *apiv1.PostInitApplicationSQLRefs,
+// createVolumesAndVolumeMountsForSQLRefs creates the volumes and the volume
+// mounts needed to project the referenced SQL files inside the passed folder.
+// Each file is named after its execution order, and the volume names are
+// prefixed with the base name of the folder to keep them unique
+func createVolumesAndVolumeMountsForSQLRefs(
+	folder string,
+	refs *apiv1.SQLRefs,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumeSuffix := path.Base(folder)
 	length := len(refs.ConfigMapRefs) + len(refs.SecretRefs)
 	digitsCount := len(fmt.Sprintf("%d", length))
 	volumes := make([]corev1.Volume, 0, length)
@@ -183,7 +201,7 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 
 	for i := range refs.SecretRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
 					SecretName: refs.SecretRefs[i].Name,
@@ -198,8 +216,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i),
 			ReadOnly:  true,
 		})
@@ -207,7 +225,7 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 
 	for i := range refs.ConfigMapRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
@@ -224,8 +242,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i+len(refs.SecretRefs)),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i+len(refs.SecretRefs)),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i+len(refs.SecretRefs)),
 			ReadOnly:  true,
 		})
@@ -303,6 +321,15 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 			)
 		}
 	}
+
+	if cluster.ShouldMountWalRestoreCache() {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      "wal-restore-cache",
+				MountPath: postgres.WALRestoreCacheDirectory,
+			},
+		)
+	}
 	return volumeMounts
 }
 
@@ -27,16 +27,16 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs", func() {
+var _ = Describe("test createVolumesAndVolumeMountsForSQLRefs", func() {
 	It("input is empty", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		input := &apiv1.SQLRefs{}
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumes).To(BeEmpty())
 		Expect(volumeMounts).To(BeEmpty())
 	})
 
 	It("we have reference to secrets only", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{
+		input := &apiv1.SQLRefs{
 			SecretRefs: []apiv1.SecretKeySelector{
 				{
 					LocalObjectReference: apiv1.LocalObjectReference{
@@ -52,7 +52,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 				},
 			},
 		}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumeMounts).To(Equal([]corev1.VolumeMount{
 			{
 				Name:      "0-post-init-application-sql",
@@ -101,7 +101,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 	})
 
 	It("we have reference to configmaps only", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{
+		input := &apiv1.SQLRefs{
 			ConfigMapRefs: []apiv1.ConfigMapKeySelector{
 				{
 					LocalObjectReference: apiv1.LocalObjectReference{
@@ -117,7 +117,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 				},
 			},
 		}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumeMounts).To(Equal([]corev1.VolumeMount{
 			{
 				Name:      "0-post-init-application-sql",
@@ -170,7 +170,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 	})
 
 	It("we have reference to both configmaps and secrets", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{
+		input := &apiv1.SQLRefs{
 			SecretRefs: []apiv1.SecretKeySelector{
 				{
 					LocalObjectReference: apiv1.LocalObjectReference{
@@ -200,7 +200,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 				},
 			},
 		}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumeMounts).To(Equal([]corev1.VolumeMount{
 			{
 				Name:      "0-post-init-application-sql",
@@ -518,3 +518,47 @@ var _ = Describe("createEphemeralVolume", func() {
 		Expect(*ephemeralVolume.VolumeSource.EmptyDir.SizeLimit).To(Equal(quantity))
 	})
 })
+
+var _ = Describe("shared WAL restore cache volume", func() {
+	It("is not mounted by default", func() {
+		cluster := apiv1.Cluster{}
+		for _, volume := range createPostgresVolumes(&cluster, "cluster-example-1") {
+			Expect(volume.Name).ToNot(Equal("wal-restore-cache"))
+		}
+		for _, volumeMount := range createPostgresVolumeMounts(cluster) {
+			Expect(volumeMount.Name).ToNot(Equal("wal-restore-cache"))
+		}
+	})
+
+	It("is not mounted until the operator verified the claim", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				WalRestoreCache: &apiv1.WalRestoreCacheConfiguration{ClaimName: "shared-wal-cache"},
+			},
+		}
+		for _, volume := range createPostgresVolumes(&cluster, "cluster-example-1") {
+			Expect(volume.Name).ToNot(Equal("wal-restore-cache"))
+		}
+	})
+
+	It("mounts the configured claim in every instance", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				WalRestoreCache: &apiv1.WalRestoreCacheConfiguration{ClaimName: "shared-wal-cache"},
+			},
+			Status: apiv1.ClusterStatus{
+				WalRestoreCacheClaim: "shared-wal-cache",
+			},
+		}
+
+		volumes := createPostgresVolumes(&cluster, "cluster-example-1")
+		Expect(volumes[len(volumes)-1].Name).To(Equal("wal-restore-cache"))
+		Expect(volumes[len(volumes)-1].PersistentVolumeClaim.ClaimName).To(Equal("shared-wal-cache"))
+
+		volumeMounts := createPostgresVolumeMounts(cluster)
+		Expect(volumeMounts[len(volumeMounts)-1]).To(Equal(corev1.VolumeMount{
+			Name:      "wal-restore-cache",
+			MountPath: "/var/lib/postgresql/wal-restore-cache",
+		}))
+	})
+})
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/cnpgerrors"
 )
@@ -42,6 +44,27 @@ func ParsePolicy(policy string) (string, error) {
 	return fmt.Sprintf("RECOVERY WINDOW OF %v %v", matches[1], unitName[matches[2]]), nil
 }
 
+// ParsePolicyDuration returns the length of the recovery window
+// expressed by a policy, counting a month as 30 days
+func ParsePolicyDuration(policy string) (time.Duration, error) {
+	unitDays := map[string]int{
+		"d": 1,
+		"w": 7,
+		"m": 30,
+	}
+	matches := regexPolicy.FindStringSubmatch(policy)
+	if len(matches) < 3 {
+		return 0, fmt.Errorf("not a valid policy")
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, fmt.Errorf("not a valid policy: %w", err)
+	}
+
+	return time.Duration(value*unitDays[matches[2]]) * 24 * time.Hour, nil
+}
+
 // MapToBarmanTagsFormat will transform a map[string]string into the
 // Barman tags format needed
 func MapToBarmanTagsFormat(option string, mapTags map[string]string) ([]string, error) {
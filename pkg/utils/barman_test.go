@@ -17,6 +17,8 @@ limitations under the License.
 package utils
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -41,6 +43,22 @@ var _ = Describe("parsing policy", func() {
 	})
 })
 
+var _ = Describe("parsing policy duration", func() {
+	It("must properly parse a correct policy", func() {
+		Expect(ParsePolicyDuration("7d")).To(Equal(7 * 24 * time.Hour))
+		Expect(ParsePolicyDuration("2w")).To(Equal(14 * 24 * time.Hour))
+		Expect(ParsePolicyDuration("3m")).To(Equal(90 * 24 * time.Hour))
+	})
+
+	It("must complain with a wrong policy", func() {
+		_, err := ParsePolicyDuration("30")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParsePolicyDuration("00d")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("converting map to barman tags format", func() {
 	It("returns an empty slice, if map is missing", func() {
 		Expect(MapToBarmanTagsFormat("test", nil)).To(BeEmpty())
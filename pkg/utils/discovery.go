@@ -186,6 +186,11 @@ func PodMonitorExist(client discovery.DiscoveryInterface) (bool, error) {
 	return exist, nil
 }
 
+// PrometheusRuleExist tries to find the PrometheusRule resource in the current cluster
+func PrometheusRuleExist(client discovery.DiscoveryInterface) (bool, error) {
+	return resourceExist(client, "monitoring.coreos.com/v1", "prometheusrules")
+}
+
 // HaveSeccompSupport returns true if Seccomp is supported. If it is, we should
 // set the SeccompProfile in the pods
 func HaveSeccompSupport() bool {
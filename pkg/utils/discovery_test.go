@@ -106,6 +106,10 @@ var _ = Describe("Detect resources properly when", func() {
 		exists, err := PodMonitorExist(client.Discovery())
 		Expect(err).ToNot(HaveOccurred())
 		Expect(exists).To(BeTrue())
+
+		exists, err = PrometheusRuleExist(client.Discovery())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
 	})
 
 	It("should not detect SecurityContextConstraints", func() {
@@ -48,6 +48,47 @@ const (
 	FenceAllInstances = "*"
 )
 
+// FencingMode is how the fenced instances are isolated from the cluster
+type FencingMode string
+
+const (
+	// FencingModeStop shuts PostgreSQL down on the fenced instances
+	FencingModeStop FencingMode = "stop"
+
+	// FencingModeReadOnly keeps PostgreSQL running on the fenced instances, in read-only
+	// mode, and excludes them from the services and from the failover
+	FencingModeReadOnly FencingMode = "readOnly"
+)
+
+// GetFencingMode gets the fencing mode from the annotations, defaulting to FencingModeStop
+func GetFencingMode(annotations map[string]string) FencingMode {
+	if FencingMode(annotations[FencingModeAnnotationName]) == FencingModeReadOnly {
+		return FencingModeReadOnly
+	}
+
+	return FencingModeStop
+}
+
+// setFencingMode sets the fencing mode inside the annotations, returning true if it has changed
+func setFencingMode(object metav1.Object, mode FencingMode) bool {
+	if GetFencingMode(object.GetAnnotations()) == mode {
+		return false
+	}
+
+	annotations := object.GetAnnotations()
+	if mode == FencingModeStop {
+		delete(annotations, FencingModeAnnotationName)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[FencingModeAnnotationName] = string(mode)
+	}
+	object.SetAnnotations(annotations)
+
+	return true
+}
+
 // GetFencedInstances gets the set of fenced servers from the annotations
 func GetFencedInstances(annotations map[string]string) (*stringset.Data, error) {
 	fencedInstances, ok := annotations[FencedInstanceAnnotation]
@@ -70,7 +111,9 @@ func setFencedInstances(object metav1.Object, data *stringset.Data) error {
 		object.SetAnnotations(annotations)
 	}()
 	if data.Len() == 0 {
+		// the fencing mode is meaningless without fenced instances
 		delete(annotations, FencedInstanceAnnotation)
+		delete(annotations, FencingModeAnnotationName)
 		return nil
 	}
 
@@ -143,6 +186,7 @@ type FencingMetadataExecutor struct {
 	fenceFunc     func(string, metav1.Object) (appliedChange bool, err error)
 	cli           client.Client
 	instanceNames []string
+	mode          FencingMode
 }
 
 // NewFencingMetadataExecutor creates a fluent client for FencingMetadataExecutor
@@ -164,6 +208,12 @@ func (fb *FencingMetadataExecutor) RemoveFencing() *FencingMetadataExecutor {
 	return fb
 }
 
+// WithMode sets the fencing mode of the cluster together with the fenced instances
+func (fb *FencingMetadataExecutor) WithMode(mode FencingMode) *FencingMetadataExecutor {
+	fb.mode = mode
+	return fb
+}
+
 // ForAllInstances applies the logic to all cluster instances
 func (fb *FencingMetadataExecutor) ForAllInstances() *FencingMetadataExecutor {
 	fb.instanceNames = []string{FenceAllInstances}
@@ -207,6 +257,9 @@ func (fb *FencingMetadataExecutor) Execute(ctx context.Context, key types.Namesp
 		}
 		appliedChange = appliedChange || changed
 	}
+	if fb.mode != "" && setFencingMode(fencedObject, fb.mode) {
+		appliedChange = true
+	}
 	if !appliedChange {
 		return nil
 	}
@@ -118,4 +118,31 @@ var _ = Describe("Fencing annotation handling", func() {
 				To(HaveKeyWithValue(FencedInstanceAnnotation, jsonMarshal("cluster-example-1")))
 		})
 	})
+	When("The instances are fenced in read-only mode", func() {
+		It("should default to stopping the fenced instances", func() {
+			Expect(GetFencingMode(nil)).To(Equal(FencingModeStop))
+			Expect(GetFencingMode(map[string]string{FencingModeAnnotationName: "unknown"})).
+				To(Equal(FencingModeStop))
+		})
+		It("should set and remove the fencing mode", func() {
+			clusterMeta := metav1.ObjectMeta{}
+			Expect(setFencingMode(&clusterMeta, FencingModeReadOnly)).To(BeTrue())
+			Expect(GetFencingMode(clusterMeta.Annotations)).To(Equal(FencingModeReadOnly))
+			Expect(setFencingMode(&clusterMeta, FencingModeReadOnly)).To(BeFalse())
+			Expect(setFencingMode(&clusterMeta, FencingModeStop)).To(BeTrue())
+			Expect(clusterMeta.Annotations).NotTo(HaveKey(FencingModeAnnotationName))
+		})
+		It("should remove the fencing mode when the last instance is unfenced", func() {
+			clusterMeta := metav1.ObjectMeta{
+				Annotations: map[string]string{
+					FencedInstanceAnnotation:  jsonMarshal("cluster-example-1"),
+					FencingModeAnnotationName: string(FencingModeReadOnly),
+				},
+			}
+			modified, err := removeFencedInstance("cluster-example-1", &clusterMeta)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeTrue())
+			Expect(clusterMeta.Annotations).NotTo(HaveKey(FencingModeAnnotationName))
+		})
+	})
 })
@@ -34,6 +34,10 @@ const (
 	// ClusterLabelName is the name of the label cluster which the backup CR belongs to
 	ClusterLabelName = MetadataNamespace + "/cluster"
 
+	// RetainedFromClusterLabelName is the name of the label replacing the cluster one on
+	// the PVCs retained after the deletion of the cluster, containing the cluster name
+	RetainedFromClusterLabelName = MetadataNamespace + "/retainedFromCluster"
+
 	// JobRoleLabelName is the name of the label containing the purpose of the executed job
 	// the value could be import, initdb, join
 	JobRoleLabelName = MetadataNamespace + "/jobRole"
@@ -92,6 +96,10 @@ const (
 
 	// IsManagedLabelName is the name of the label used to indicate a '.spec.managed' resource
 	IsManagedLabelName = MetadataNamespace + "/isManaged"
+
+	// UserMappingSecretLabelName is the name of the label which needs to be set to "true"
+	// on a secret to allow its credentials to be used in the user mappings of a Database
+	UserMappingSecretLabelName = MetadataNamespace + "/userMapping"
 )
 
 const (
@@ -137,6 +145,11 @@ const (
 	// SkipWalArchiving is the name of the annotation which turns off WAL archiving
 	SkipWalArchiving = MetadataNamespace + "/skipWalArchiving"
 
+	// UnsafeDurabilityAnnotationName is the name of the annotation which
+	// acknowledges the risk of data loss, allowing the user to disable
+	// fsync and full_page_writes
+	UnsafeDurabilityAnnotationName = MetadataNamespace + "/allowUnsafeDurabilitySettings"
+
 	// skipEmptyWalArchiveCheck is the name of the annotation which turns off the checks that ensure that the WAL
 	// archive is empty before writing data
 	skipEmptyWalArchiveCheck = MetadataNamespace + "/skipEmptyWalArchiveCheck"
@@ -161,6 +174,31 @@ const (
 	// PostgreSQL cluster
 	HibernationAnnotationName = MetadataNamespace + "/hibernation"
 
+	// CertificatesRotationAnnotationName is the name of the annotation which is used to
+	// request the immediate renewal of the certificates managed by the operator
+	CertificatesRotationAnnotationName = MetadataNamespace + "/rotateCertificates"
+
+	// RestartApprovalAnnotationName is the name of the annotation which is used to
+	// approve the restarts required by the changes of the PostgreSQL parameters,
+	// when they are deferred
+	RestartApprovalAnnotationName = MetadataNamespace + "/approveRestart"
+
+	// SwitchoverToAnnotationName is the name of the annotation which is used to declaratively
+	// request a switchover of a PostgreSQL cluster to the named instance
+	SwitchoverToAnnotationName = MetadataNamespace + "/switchoverTo"
+
+	// ReplaceInstanceAnnotationName is the name of the annotation which is used to request
+	// the replacement of the named instance of a PostgreSQL cluster with a new one
+	ReplaceInstanceAnnotationName = MetadataNamespace + "/replaceInstance"
+
+	// DeletionConfirmationAnnotationName is the name of the annotation which is used to confirm
+	// the deletion of a PostgreSQL cluster requiring it, when set to the name of the cluster
+	DeletionConfirmationAnnotationName = MetadataNamespace + "/deletionConfirmation"
+
+	// MajorVersionAnnotationName is the name of the annotation added to the job upgrading
+	// the data directory to tell the PostgreSQL major version it is upgrading to
+	MajorVersionAnnotationName = MetadataNamespace + "/majorVersion"
+
 	// PoolerSpecHashAnnotationName is the name of the annotation added to the deployment to tell
 	// the hash of the Pooler Specification
 	PoolerSpecHashAnnotationName = MetadataNamespace + "/poolerSpecHash"
@@ -174,6 +212,21 @@ const (
 	// If the list contain the "*" element, every node is fenced.
 	FencedInstanceAnnotation = MetadataNamespace + "/fencedInstances"
 
+	// FencingModeAnnotationName is the annotation to be used to choose how the instances listed in
+	// FencedInstanceAnnotation are fenced: "stop" (default) shuts PostgreSQL down, while "readOnly"
+	// keeps it running in read-only mode, excluded from the services and from the failover
+	FencingModeAnnotationName = MetadataNamespace + "/fencingMode"
+
+	// QuarantineRestartWindowAnnotationName is the name of the annotation where the
+	// operator keeps, for each instance, the start of the current window in which
+	// the restarts are counted, together with the restart count at that time
+	QuarantineRestartWindowAnnotationName = MetadataNamespace + "/quarantineRestartWindow"
+
+	// ValidateRecoveryStoreAnnotationName is the annotation to be set to "enabled" on a
+	// Cluster being created to have the validating webhook check that the object store
+	// used by the recovery is reachable
+	ValidateRecoveryStoreAnnotationName = MetadataNamespace + "/validateRecoveryStore"
+
 	// CNPGHashAnnotationName is the name of the annotation containing the hash of the resource used by operator
 	// expect the pooler that uses PoolerSpecHashAnnotationName
 	CNPGHashAnnotationName = MetadataNamespace + "/hash"
@@ -196,6 +249,10 @@ const (
 	// BackupTablespaceMapFileAnnotationName is the name of the annotation where the `tablespace_map` file is kept
 	BackupTablespaceMapFileAnnotationName = MetadataNamespace + "/backupTablespaceMapFile"
 
+	// PasswordRotationTimeAnnotationName is the name of the annotation where
+	// the time of the last rotation of the password of a secret is kept
+	PasswordRotationTimeAnnotationName = MetadataNamespace + "/passwordRotationTime"
+
 	// SnapshotStartTimeAnnotationName is the name of the annotation where a snapshot's start time is kept
 	SnapshotStartTimeAnnotationName = MetadataNamespace + "/snapshotStartTime"
 
@@ -206,6 +263,10 @@ const (
 	// latest required restart time
 	ClusterRestartAnnotationName = "kubectl.kubernetes.io/restartedAt"
 
+	// DefaultContainerAnnotationName is the name of the annotation selecting
+	// the container used by kubectl when none is specified
+	DefaultContainerAnnotationName = "kubectl.kubernetes.io/default-container"
+
 	// UpdateStrategyAnnotation is the name of the annotation used to indicate how to update the given resource
 	UpdateStrategyAnnotation = MetadataNamespace + "/updateStrategy"
 )
@@ -297,11 +358,13 @@ func InheritAnnotations(
 	}
 
 	for key, value := range fixedAnnotations {
-		object.Annotations[key] = value
+		if !IsReservedMetadataKey(key) {
+			object.Annotations[key] = value
+		}
 	}
 
 	for key, value := range annotations {
-		if controller.IsAnnotationInherited(key) {
+		if controller.IsAnnotationInherited(key) && !IsReservedMetadataKey(key) {
 			object.Annotations[key] = value
 		}
 	}
@@ -321,16 +384,25 @@ func InheritLabels(
 	}
 
 	for key, value := range fixedLabels {
-		object.Labels[key] = value
+		if !IsReservedMetadataKey(key) {
+			object.Labels[key] = value
+		}
 	}
 
 	for key, value := range labels {
-		if controller.IsLabelInherited(key) {
+		if controller.IsLabelInherited(key) && !IsReservedMetadataKey(key) {
 			object.Labels[key] = value
 		}
 	}
 }
 
+// IsReservedMetadataKey checks if a label or an annotation is managed by
+// the operator. These keys, belonging to the operator namespace, are never
+// inherited, so that they can't be overridden by the user
+func IsReservedMetadataKey(key string) bool {
+	return key == ClusterRoleLabelName || strings.HasPrefix(key, MetadataNamespace+"/")
+}
+
 func getAnnotationAppArmor(spec *corev1.PodSpec, annotations map[string]string) map[string]string {
 	containsContainerWithName := func(name string, containers ...corev1.Container) bool {
 		for _, container := range containers {
@@ -404,6 +476,13 @@ func IsWalArchivingDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[SkipWalArchiving] == string(annotationStatusEnabled)
 }
 
+// IsUnsafeDurabilityAllowed returns a boolean indicating if the user
+// acknowledged the risk of data loss deriving from disabling the
+// PostgreSQL durability settings
+func IsUnsafeDurabilityAllowed(object *metav1.ObjectMeta) bool {
+	return object.Annotations[UnsafeDurabilityAnnotationName] == string(annotationStatusEnabled)
+}
+
 func mergeMap(receiver, giver map[string]string) map[string]string {
 	for key, value := range giver {
 		receiver[key] = value
@@ -107,6 +107,30 @@ var _ = Describe("Label management", func() {
 			fixedMap, config)
 		Expect(pod.Labels).To(Equal(map[string]string{"alpha": "1", "beta": "2", "delta": "4", "epsilon": "5"}))
 	})
+
+	It("must not override the labels reserved to the operator", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					ClusterInstanceRoleLabelName: "primary",
+					ClusterRoleLabelName:         "primary",
+				},
+			},
+		}
+		reservedConfig := &fakeInhericanceController{labels: []string{"alpha", ClusterInstanceRoleLabelName}}
+		InheritLabels(&pod.ObjectMeta,
+			map[string]string{"alpha": "1", ClusterInstanceRoleLabelName: "replica"},
+			map[string]string{"delta": "4", ClusterRoleLabelName: "replica"},
+			reservedConfig)
+		Expect(pod.Labels).To(Equal(map[string]string{
+			"alpha":                      "1",
+			"delta":                      "4",
+			ClusterInstanceRoleLabelName: "primary",
+			ClusterRoleLabelName:         "primary",
+		}))
+		Expect(IsLabelSubset(pod.Labels,
+			map[string]string{ClusterInstanceRoleLabelName: "replica"}, nil, reservedConfig)).To(BeTrue())
+	})
 })
 
 var _ = Describe("Label cluster name management", func() {
@@ -77,11 +77,13 @@ func IsLabelSubset(
 	mapToEvaluate := map[string]string{}
 
 	for key, value := range fixedInheritedLabels {
-		mapToEvaluate[key] = value
+		if !IsReservedMetadataKey(key) {
+			mapToEvaluate[key] = value
+		}
 	}
 
 	for key, value := range clusterLabels {
-		if controller.IsLabelInherited(key) {
+		if controller.IsLabelInherited(key) && !IsReservedMetadataKey(key) {
 			mapToEvaluate[key] = value
 		}
 	}
@@ -101,11 +103,13 @@ func IsAnnotationSubset(
 	mapToEvaluate := map[string]string{}
 
 	for key, value := range fixedInheritedAnnotations {
-		mapToEvaluate[key] = value
+		if !IsReservedMetadataKey(key) {
+			mapToEvaluate[key] = value
+		}
 	}
 
 	for key, value := range clusterAnnotations {
-		if controller.IsAnnotationInherited(key) {
+		if controller.IsAnnotationInherited(key) && !IsReservedMetadataKey(key) {
 			mapToEvaluate[key] = value
 		}
 	}
@@ -21,6 +21,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -53,6 +54,14 @@ const (
 	// PgControlDataDatabaseClusterStateKey is the status
 	// of the latest primary that run on this data directory.
 	PgControlDataDatabaseClusterStateKey pgControlDataKey = "Database cluster state"
+
+	// PgControlDataKeyBytesPerWALSegment is the size of
+	// the WAL segments pg_controldata entry
+	PgControlDataKeyBytesPerWALSegment pgControlDataKey = "Bytes per WAL segment"
+
+	// PgControlDataKeyDataPageChecksumVersion is the version of the
+	// data page checksums pg_controldata entry, zero when disabled
+	PgControlDataKeyDataPageChecksumVersion pgControlDataKey = "Data page checksum version"
 )
 
 // PgDataState represents the "Database cluster state" field of pg_controldata
@@ -94,6 +103,24 @@ func ParsePgControldataOutput(data string) map[string]string {
 // TODO(leonardoce): I believe that the code about the promotion token
 // belongs to a different package
 
+// GetWALSegmentSizeFromPgControldata gets the size of the WAL segments
+// from the parsed pg_controldata output
+func GetWALSegmentSizeFromPgControldata(pgControlData map[string]string) (int64, error) {
+	walSegmentSizeString, ok := pgControlData[PgControlDataKeyBytesPerWALSegment]
+	if !ok {
+		return 0, fmt.Errorf("no '%s' section into pg_controldata output", PgControlDataKeyBytesPerWALSegment)
+	}
+
+	walSegmentSize, err := strconv.ParseInt(walSegmentSizeString, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"wrong '%s' pg_controldata value (not an integer): '%s' %w",
+			PgControlDataKeyBytesPerWALSegment, walSegmentSizeString, err)
+	}
+
+	return walSegmentSize, nil
+}
+
 // PgControldataTokenContent contains the data needed to properly create a promotion token
 type PgControldataTokenContent struct {
 	// Latest checkpoint's TimeLineID
@@ -97,6 +97,26 @@ var _ = Describe("pg_controldata output parser", func() {
 	})
 })
 
+var _ = Describe("WAL segment size from pg_controldata", func() {
+	It("gets the WAL segment size", func() {
+		Expect(GetWALSegmentSizeFromPgControldata(map[string]string{
+			PgControlDataKeyBytesPerWALSegment: "67108864",
+		})).To(BeEquivalentTo(64 * 1024 * 1024))
+	})
+
+	It("fails when the WAL segment size is missing", func() {
+		_, err := GetWALSegmentSizeFromPgControldata(map[string]string{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails when the WAL segment size is not an integer", func() {
+		_, err := GetWALSegmentSizeFromPgControldata(map[string]string{
+			PgControlDataKeyBytesPerWALSegment: "16MB",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("promotion token creation", func() {
 	It("creates a promotion token from a parsed pg_controldata", func() {
 		parsedControlData := ParsePgControldataOutput(fakeControlData)